@@ -2,4 +2,10 @@ package config
 
 const (
 	PremiumNewsletterSubscriptionTrialLengthDays int64 = 30
+
+	// Users keep premium for this long after a failed renewal
+	// while Stripe retries the payment
+	DunningGracePeriodDays int64 = 14
+	// The last reminder is sent this many days before the downgrade
+	DunningFinalNoticeDaysBeforeDowngrade int64 = 3
 )
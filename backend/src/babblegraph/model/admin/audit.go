@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AuditLogEntryID string
+
+type AuditLogResult string
+
+const (
+	AuditLogResultPending AuditLogResult = "pending"
+	AuditLogResultSuccess AuditLogResult = "success"
+	AuditLogResultError   AuditLogResult = "error"
+	// The handler ran without an error, but the response said
+	// the request didn't go through, like an invalid TOTP code
+	AuditLogResultFailure AuditLogResult = "failure"
+)
+
+func (a AuditLogResult) Str() string {
+	return string(a)
+}
+
+type dbAuditLogEntry struct {
+	ID             AuditLogEntryID `db:"_id"`
+	CreatedAt      time.Time       `db:"created_at"`
+	LastModifiedAt time.Time       `db:"last_modified_at"`
+	AdminUserID    ID              `db:"admin_user_id"`
+	Route          string          `db:"route"`
+	Permission     *Permission     `db:"permission"`
+	RequestBody    *string         `db:"request_body"`
+	Result         AuditLogResult  `db:"result"`
+	ErrorMessage   *string         `db:"error_message"`
+	CompletedAt    *time.Time      `db:"completed_at"`
+}
+
+func (d dbAuditLogEntry) ToNonDB() AuditLogEntry {
+	return AuditLogEntry{
+		ID:           d.ID,
+		CreatedAt:    d.CreatedAt,
+		AdminUserID:  d.AdminUserID,
+		Route:        d.Route,
+		Permission:   d.Permission,
+		RequestBody:  d.RequestBody,
+		Result:       d.Result,
+		ErrorMessage: d.ErrorMessage,
+		CompletedAt:  d.CompletedAt,
+	}
+}
+
+type AuditLogEntry struct {
+	ID          AuditLogEntryID
+	CreatedAt   time.Time
+	AdminUserID ID
+	Route       string
+	// This is nil for routes that only require authentication
+	Permission *Permission
+	// This is JSON with secrets redacted, or nil
+	// if the request did not have a JSON body
+	RequestBody  *string
+	Result       AuditLogResult
+	ErrorMessage *string
+	CompletedAt  *time.Time
+}
+
+const (
+	redactedAuditValue = "[redacted]"
+
+	maxAuditStringValueLength = 1024
+	maxAuditRequestBodyLength = 64 * 1024
+)
+
+// Any field whose name contains one of these is redacted, wherever it is in the body
+var sensitiveAuditFieldNameSubstrings = []string{
+	"password",
+	"secret",
+	"token",
+	"api_key",
+	"apikey",
+	"authorization",
+	"private_key",
+	"two_factor",
+	"recovery_code",
+}
+
+func isSensitiveAuditFieldName(fieldName string) bool {
+	normalized := strings.ToLower(fieldName)
+	for _, s := range sensitiveAuditFieldNameSubstrings {
+		if strings.Contains(normalized, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAuditRequestBody returns nil for empty or non-JSON bodies,
+// like file uploads, since there is nothing useful to store for those
+func redactAuditRequestBody(body []byte) (*string, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, nil
+	}
+	redacted, err := json.Marshal(redactAuditValue(decoded))
+	if err != nil {
+		return nil, err
+	}
+	if len(redacted) > maxAuditRequestBodyLength {
+		redacted = []byte(fmt.Sprintf(`{"_truncated": true, "_length": %d}`, len(redacted)))
+	}
+	out := string(redacted)
+	return &out, nil
+}
+
+func redactAuditValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for key, value := range t {
+			if isSensitiveAuditFieldName(key) {
+				out[key] = redactedAuditValue
+				continue
+			}
+			out[key] = redactAuditValue(value)
+		}
+		return out
+	case []interface{}:
+		var out []interface{}
+		for _, value := range t {
+			out = append(out, redactAuditValue(value))
+		}
+		return out
+	case string:
+		// Things like blog posts are too big to be worth keeping in full
+		if len(t) > maxAuditStringValueLength {
+			return fmt.Sprintf("%s...[truncated %d characters]", t[:maxAuditStringValueLength], len(t)-maxAuditStringValueLength)
+		}
+		return t
+	default:
+		return t
+	}
+}
@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createAuditLogEntryQuery = `INSERT INTO
+        admin_audit_log (
+            admin_user_id,
+            route,
+            permission,
+            request_body,
+            result
+        ) VALUES ($1, $2, $3, $4, $5)
+        RETURNING _id`
+	completeAuditLogEntryQuery = `UPDATE admin_audit_log
+        SET result = $2, error_message = $3, completed_at = timezone('utc', now()), last_modified_at = timezone('utc', now())
+        WHERE _id = $1`
+	// Every filter is optional, which is what the IS NULL checks are for
+	getAuditLogEntriesQuery = `SELECT * FROM admin_audit_log
+        WHERE ($1::uuid IS NULL OR admin_user_id = $1)
+        AND ($2::text IS NULL OR route = $2)
+        AND ($3::timestamptz IS NULL OR created_at >= $3)
+        AND ($4::timestamptz IS NULL OR created_at < $4)
+        ORDER BY created_at DESC
+        LIMIT $5`
+
+	defaultAuditLogEntriesLimit = 100
+	maxAuditLogEntriesLimit     = 500
+)
+
+type CreateAuditLogEntryInput struct {
+	AdminUserID ID
+	Route       string
+	Permission  *Permission
+	RequestBody []byte
+}
+
+// CreateAuditLogEntry records a pending entry, which
+// should be completed once the request has been handled
+func CreateAuditLogEntry(tx *sqlx.Tx, input CreateAuditLogEntryInput) (*AuditLogEntryID, error) {
+	requestBody, err := redactAuditRequestBody(input.RequestBody)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(createAuditLogEntryQuery, input.AdminUserID, input.Route, input.Permission, requestBody, AuditLogResultPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var id AuditLogEntryID
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+	}
+	return &id, nil
+}
+
+func CompleteAuditLogEntry(tx *sqlx.Tx, id AuditLogEntryID, result AuditLogResult, errorMessage *string) error {
+	if _, err := tx.Exec(completeAuditLogEntryQuery, id, result, errorMessage); err != nil {
+		return err
+	}
+	return nil
+}
+
+type GetAuditLogEntriesInput struct {
+	AdminUserID *ID
+	Route       *string
+	StartTime   *time.Time
+	EndTime     *time.Time
+	Limit       *int
+}
+
+func GetAuditLogEntries(tx *sqlx.Tx, input GetAuditLogEntriesInput) ([]AuditLogEntry, error) {
+	limit := defaultAuditLogEntriesLimit
+	switch {
+	case input.Limit == nil,
+		*input.Limit <= 0:
+		// no-op
+	case *input.Limit > maxAuditLogEntriesLimit:
+		limit = maxAuditLogEntriesLimit
+	default:
+		limit = *input.Limit
+	}
+	var matches []dbAuditLogEntry
+	if err := tx.Select(&matches, getAuditLogEntriesQuery, input.AdminUserID, input.Route, input.StartTime, input.EndTime, limit); err != nil {
+		return nil, err
+	}
+	var out []AuditLogEntry
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
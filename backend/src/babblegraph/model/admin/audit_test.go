@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"babblegraph/util/ptr"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedactAuditRequestBody(t *testing.T) {
+	type testCase struct {
+		body     string
+		expected *string
+	}
+	longString := strings.Repeat("a", maxAuditStringValueLength+10)
+	for idx, tc := range []testCase{
+		{body: "", expected: nil},
+		{body: "--boundary\r\nContent-Disposition: form-data", expected: nil},
+		{
+			body:     `{"email_address": "test@babblegraph.com", "password": "hunter2"}`,
+			expected: ptr.String(`{"email_address": "test@babblegraph.com", "password": "[redacted]"}`),
+		},
+		{
+			body:     `{"updates": [{"permission": "manage-billing", "access_token": "abc", "stripe_api_key": "sk_live"}]}`,
+			expected: ptr.String(`{"updates": [{"permission": "manage-billing", "access_token": "[redacted]", "stripe_api_key": "[redacted]"}]}`),
+		},
+		{
+			body:     `{"Client_Secret": {"nested": "value"}, "source_id": "123"}`,
+			expected: ptr.String(`{"Client_Secret": "[redacted]", "source_id": "123"}`),
+		},
+		{
+			body:     `{"content": "` + longString + `"}`,
+			expected: ptr.String(`{"content": "` + longString[:maxAuditStringValueLength] + `...[truncated 10 characters]"}`),
+		},
+	} {
+		result, err := redactAuditRequestBody([]byte(tc.body))
+		switch {
+		case err != nil:
+			t.Errorf("Error on test case %d: %s", idx, err.Error())
+		case tc.expected == nil && result != nil:
+			t.Errorf("Error on test case %d: expected nil, but got %s", idx, *result)
+		case tc.expected != nil && result == nil:
+			t.Errorf("Error on test case %d: expected %s, but got nil", idx, *tc.expected)
+		case tc.expected != nil:
+			var expected, actual interface{}
+			if err := json.Unmarshal([]byte(*tc.expected), &expected); err != nil {
+				t.Fatalf("Error on test case %d: invalid expectation: %s", idx, err.Error())
+			}
+			if err := json.Unmarshal([]byte(*result), &actual); err != nil {
+				t.Errorf("Error on test case %d: result is not JSON: %s", idx, err.Error())
+				continue
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("Error on test case %d: expected %s, but got %s", idx, *tc.expected, *result)
+			}
+		}
+	}
+}
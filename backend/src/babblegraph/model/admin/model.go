@@ -4,24 +4,47 @@ import "time"
 
 type ID string
 
+func (i ID) Ptr() *ID {
+	return &i
+}
+
 type dbAdmin struct {
-	CreatedAt      time.Time `db:"created_at"`
-	LastModifiedAt time.Time `db:"last_modified_at"`
-	ID             ID        `db:"_id"`
-	EmailAddress   string    `db:"email_address"`
-	IsActive       bool      `db:"is_active"`
+	CreatedAt       time.Time       `db:"created_at"`
+	LastModifiedAt  time.Time       `db:"last_modified_at"`
+	ID              ID              `db:"_id"`
+	EmailAddress    string          `db:"email_address"`
+	IsActive        bool            `db:"is_active"`
+	TwoFactorMethod TwoFactorMethod `db:"two_factor_method"`
 }
 
 func (d dbAdmin) ToNonDB() Admin {
 	return Admin{
-		ID:           d.ID,
-		EmailAddress: d.EmailAddress,
+		ID:              d.ID,
+		EmailAddress:    d.EmailAddress,
+		TwoFactorMethod: d.TwoFactorMethod,
 	}
 }
 
 type Admin struct {
-	ID           ID
-	EmailAddress string
+	ID              ID
+	EmailAddress    string
+	TwoFactorMethod TwoFactorMethod
+}
+
+type TwoFactorMethod string
+
+const (
+	TwoFactorMethodEmail    TwoFactorMethod = "email"
+	TwoFactorMethodTOTP     TwoFactorMethod = "totp"
+	TwoFactorMethodWebAuthn TwoFactorMethod = "webauthn"
+)
+
+func (t TwoFactorMethod) Str() string {
+	return string(t)
+}
+
+func (t TwoFactorMethod) Ptr() *TwoFactorMethod {
+	return &t
 }
 
 type passwordID string
@@ -54,6 +77,10 @@ type UserPermissionMapping struct {
 
 type Permission string
 
+func (p Permission) Ptr() *Permission {
+	return &p
+}
+
 const (
 	PermissionManagePermissions     Permission = "manage-permissions"
 	PermissionEditContentTopics     Permission = "edit-content-topics"
@@ -61,6 +88,8 @@ const (
 	PermissionManageBilling         Permission = "manage-billing"
 	PermissionBillingAddCouponCodes Permission = "billing-add-coupon-codes"
 	PermissionPodcastSearch         Permission = "podcast-search"
+	PermissionPreviewNewsletters    Permission = "preview-newsletters"
+	PermissionViewAuditLog          Permission = "view-audit-log"
 
 	// TODO: delete these
 	PermissionViewUserMetrics               Permission = "view-user-metrics"
@@ -105,3 +134,51 @@ type dbAccessToken struct {
 	ExpiresAt   time.Time `db:"expires_at"`
 	AdminUserID ID        `db:"admin_user_id"`
 }
+
+type dbTOTPSecret struct {
+	CreatedAt        time.Time `db:"created_at"`
+	LastModifiedAt   time.Time `db:"last_modified_at"`
+	ID               string    `db:"_id"`
+	AdminUserID      ID        `db:"admin_user_id"`
+	Secret           string    `db:"secret"`
+	IsConfirmed      bool      `db:"is_confirmed"`
+	LastUsedTimeStep *int64    `db:"last_used_time_step"`
+}
+
+type dbRecoveryCode struct {
+	CreatedAt      time.Time  `db:"created_at"`
+	LastModifiedAt time.Time  `db:"last_modified_at"`
+	ID             string     `db:"_id"`
+	AdminUserID    ID         `db:"admin_user_id"`
+	CodeHash       string     `db:"code_hash"`
+	UsedAt         *time.Time `db:"used_at"`
+}
+
+type dbWebAuthnCredential struct {
+	CreatedAt      time.Time  `db:"created_at"`
+	LastModifiedAt time.Time  `db:"last_modified_at"`
+	ID             string     `db:"_id"`
+	AdminUserID    ID         `db:"admin_user_id"`
+	Name           string     `db:"name"`
+	CredentialID   string     `db:"credential_id"`
+	PublicKey      []byte     `db:"public_key"`
+	SignCount      int64      `db:"sign_count"`
+	LastUsedAt     *time.Time `db:"last_used_at"`
+}
+
+func (d dbWebAuthnCredential) ToNonDB() WebAuthnCredential {
+	return WebAuthnCredential{
+		CredentialID: d.CredentialID,
+		Name:         d.Name,
+		CreatedAt:    d.CreatedAt,
+		LastUsedAt:   d.LastUsedAt,
+	}
+}
+
+type WebAuthnCredential struct {
+	// This is base64url encoded, which is how browsers hand it over
+	CredentialID string
+	Name         string
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
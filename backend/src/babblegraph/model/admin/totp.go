@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters follow RFC 6238 defaults, since
+// those are the only ones that every authenticator app supports
+const (
+	totpIssuer           = "Babblegraph Admin"
+	totpSecretByteLength = 20
+	totpDigits           = 6
+	totpTimeStep         = 30 * time.Second
+	// Allows for a code to be accepted one step on
+	// either side of the current one to account for clock drift
+	totpAllowedSkewSteps = 1
+	// TOTP secrets are stored encrypted, since anyone
+	// with the secret can generate valid codes
+	totpSecretEncryptionPurpose = "admin-totp-secret"
+
+	numberOfRecoveryCodes     = 10
+	recoveryCodeByteLength    = 5
+	recoveryCodeDisplayLength = 8
+)
+
+var totpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateTOTPSecret() (*string, error) {
+	secretBytes := make([]byte, totpSecretByteLength)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, err
+	}
+	secret := totpSecretEncoding.EncodeToString(secretBytes)
+	return &secret, nil
+}
+
+func getTOTPTimeStep(t time.Time) int64 {
+	return t.Unix() / int64(totpTimeStep/time.Second)
+}
+
+// This is HOTP (RFC 4226) with the time step as the counter
+func computeTOTPCode(secret []byte, timeStep int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(timeStep))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, truncated%modulus)
+}
+
+// findTOTPTimeStepForCode returns the time step that the code was generated for,
+// or nil if it does not match any time step in the allowed window.
+// Time steps at or before lastUsedTimeStep are never matched so that a code cannot be reused.
+func findTOTPTimeStepForCode(secret, code string, now time.Time, lastUsedTimeStep *int64) (*int64, error) {
+	secretBytes, err := totpSecretEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, err
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return nil, nil
+	}
+	currentTimeStep := getTOTPTimeStep(now)
+	for timeStep := currentTimeStep - totpAllowedSkewSteps; timeStep <= currentTimeStep+totpAllowedSkewSteps; timeStep++ {
+		if lastUsedTimeStep != nil && timeStep <= *lastUsedTimeStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(computeTOTPCode(secretBytes, timeStep)), []byte(code)) == 1 {
+			matchedTimeStep := timeStep
+			return &matchedTimeStep, nil
+		}
+	}
+	return nil, nil
+}
+
+// This is the URI that gets turned into a QR code for authenticator apps
+func makeTOTPProvisioningURI(secret, emailAddress string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", totpIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", totpDigits))
+	params.Set("period", fmt.Sprintf("%d", int64(totpTimeStep/time.Second)))
+	label := url.PathEscape(fmt.Sprintf("%s:%s", totpIssuer, emailAddress))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// Recovery codes have enough entropy that they can be
+// looked up by an unsalted hash, unlike passwords
+func generateRecoveryCodes() ([]string, error) {
+	var out []string
+	for i := 0; i < numberOfRecoveryCodes; i++ {
+		codeBytes := make([]byte, recoveryCodeByteLength)
+		if _, err := rand.Read(codeBytes); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(totpSecretEncoding.EncodeToString(codeBytes))
+		out = append(out, fmt.Sprintf("%s-%s", code[:recoveryCodeDisplayLength/2], code[recoveryCodeDisplayLength/2:]))
+	}
+	return out, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
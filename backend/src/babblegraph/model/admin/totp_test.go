@@ -0,0 +1,82 @@
+package admin
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// These are the SHA1 test vectors from RFC 6238, truncated to six digits
+func TestComputeTOTPCode(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	type testCase struct {
+		unixTime int64
+		expected string
+	}
+	for idx, tc := range []testCase{
+		{unixTime: 59, expected: "287082"},
+		{unixTime: 1111111109, expected: "081804"},
+		{unixTime: 1111111111, expected: "050471"},
+		{unixTime: 1234567890, expected: "005924"},
+		{unixTime: 2000000000, expected: "279037"},
+		{unixTime: 20000000000, expected: "353130"},
+	} {
+		if result := computeTOTPCode(secret, getTOTPTimeStep(time.Unix(tc.unixTime, 0))); result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestFindTOTPTimeStepForCode(t *testing.T) {
+	secret := totpSecretEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+	currentTimeStep := getTOTPTimeStep(now)
+	previousTimeStep := currentTimeStep - 1
+	type testCase struct {
+		code             string
+		lastUsedTimeStep *int64
+		expected         *int64
+	}
+	for idx, tc := range []testCase{
+		{code: "050471", expected: &currentTimeStep},
+		// The code from the previous time step is still accepted
+		{code: "081804", expected: &previousTimeStep},
+		{code: "081804", lastUsedTimeStep: &previousTimeStep, expected: nil},
+		{code: "050471", lastUsedTimeStep: &currentTimeStep, expected: nil},
+		{code: "000000", expected: nil},
+		{code: "05047", expected: nil},
+	} {
+		result, err := findTOTPTimeStepForCode(secret, tc.code, now, tc.lastUsedTimeStep)
+		switch {
+		case err != nil:
+			t.Errorf("Error on test case %d: %s", idx, err.Error())
+		case tc.expected == nil && result != nil:
+			t.Errorf("Error on test case %d: expected no match, but got %d", idx, *result)
+		case tc.expected != nil && result == nil:
+			t.Errorf("Error on test case %d: expected %d, but got no match", idx, *tc.expected)
+		case tc.expected != nil && *result != *tc.expected:
+			t.Errorf("Error on test case %d: expected %d, but got %d", idx, *tc.expected, *result)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("Error generating recovery codes: %s", err.Error())
+	}
+	if len(codes) != numberOfRecoveryCodes {
+		t.Fatalf("Expected %d codes, but got %d", numberOfRecoveryCodes, len(codes))
+	}
+	hashes := make(map[string]bool)
+	for _, code := range codes {
+		hashes[hashRecoveryCode(code)] = true
+	}
+	if len(hashes) != numberOfRecoveryCodes {
+		t.Errorf("Expected all recovery codes to be distinct")
+	}
+	// Codes are forgiving of how they are typed back in
+	if hashRecoveryCode(codes[0]) != hashRecoveryCode(" "+strings.ToUpper(strings.Replace(codes[0], "-", "", -1))+" ") {
+		t.Errorf("Expected recovery code hash to ignore case, dashes, and whitespace")
+	}
+}
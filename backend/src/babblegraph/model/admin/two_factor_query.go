@@ -0,0 +1,258 @@
+package admin
+
+import (
+	"babblegraph/util/encrypt"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	updateTwoFactorMethodQuery = "UPDATE admin_user SET two_factor_method = $2, last_modified_at = timezone('utc', now()) WHERE _id = $1"
+
+	getTOTPSecretQuery    = "SELECT * FROM admin_totp_secret WHERE admin_user_id = $1"
+	upsertTOTPSecretQuery = `INSERT INTO
+        admin_totp_secret (
+            admin_user_id,
+            secret
+        ) VALUES ($1, $2)
+        ON CONFLICT (admin_user_id) DO UPDATE
+        SET secret = $2, is_confirmed = FALSE, last_used_time_step = NULL, last_modified_at = timezone('utc', now())
+    `
+	confirmTOTPSecretQuery = `UPDATE admin_totp_secret
+        SET is_confirmed = TRUE, last_used_time_step = $3, last_modified_at = timezone('utc', now())
+        WHERE admin_user_id = $1 AND secret = $2 AND is_confirmed = FALSE`
+	// Secrets stored before they were encrypted are
+	// only replaced if nothing else has changed them
+	encryptTOTPSecretQuery = "UPDATE admin_totp_secret SET secret = $3, last_modified_at = timezone('utc', now()) WHERE admin_user_id = $1 AND secret = $2"
+	// The time step comparison is what makes
+	// a code single use, even for concurrent requests
+	useTOTPTimeStepQuery = `UPDATE admin_totp_secret
+        SET last_used_time_step = $2, last_modified_at = timezone('utc', now())
+        WHERE admin_user_id = $1
+        AND is_confirmed = TRUE
+        AND (last_used_time_step IS NULL OR last_used_time_step < $2)`
+
+	deleteRecoveryCodesForAdminQuery = "DELETE FROM admin_recovery_code WHERE admin_user_id = $1"
+	createRecoveryCodeQuery          = "INSERT INTO admin_recovery_code (admin_user_id, code_hash) VALUES ($1, $2)"
+	useRecoveryCodeQuery             = `UPDATE admin_recovery_code
+        SET used_at = timezone('utc', now()), last_modified_at = timezone('utc', now())
+        WHERE admin_user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	countUnusedRecoveryCodesQuery = "SELECT COUNT(*) FROM admin_recovery_code WHERE admin_user_id = $1 AND used_at IS NULL"
+)
+
+type TwoFactorSettings struct {
+	Method                      TwoFactorMethod
+	IsTOTPEnrolled              bool
+	NumberOfUnusedRecoveryCodes int
+	WebAuthnCredentials         []WebAuthnCredential
+}
+
+func GetTwoFactorSettings(tx *sqlx.Tx, adminID ID) (*TwoFactorSettings, error) {
+	adminUser, err := GetAdminUser(tx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	totpSecret, err := lookupTOTPSecret(tx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	var numberOfUnusedRecoveryCodes int
+	if err := tx.Get(&numberOfUnusedRecoveryCodes, countUnusedRecoveryCodesQuery, adminID); err != nil {
+		return nil, err
+	}
+	webAuthnCredentials, err := GetWebAuthnCredentials(tx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorSettings{
+		Method:                      adminUser.TwoFactorMethod,
+		IsTOTPEnrolled:              totpSecret != nil && totpSecret.IsConfirmed,
+		NumberOfUnusedRecoveryCodes: numberOfUnusedRecoveryCodes,
+		WebAuthnCredentials:         webAuthnCredentials,
+	}, nil
+}
+
+// SetTwoFactorMethod only allows switching to a method that
+// has been set up, so that an admin can't lock themselves out.
+// Email is always allowed since it is the fallback.
+func SetTwoFactorMethod(tx *sqlx.Tx, adminID ID, method TwoFactorMethod) error {
+	settings, err := GetTwoFactorSettings(tx, adminID)
+	if err != nil {
+		return err
+	}
+	switch method {
+	case TwoFactorMethodEmail:
+		// no-op
+	case TwoFactorMethodTOTP:
+		if !settings.IsTOTPEnrolled {
+			return fmt.Errorf("Admin %s has not enrolled an authenticator app", adminID)
+		}
+	case TwoFactorMethodWebAuthn:
+		if len(settings.WebAuthnCredentials) == 0 {
+			return fmt.Errorf("Admin %s has not registered a security key", adminID)
+		}
+	default:
+		return fmt.Errorf("Unrecognized two factor method: %s", method.Str())
+	}
+	if _, err := tx.Exec(updateTwoFactorMethodQuery, adminID, method); err != nil {
+		return err
+	}
+	return nil
+}
+
+type TOTPEnrollment struct {
+	// The secret is included for admins who
+	// can't scan the provisioning URI as a QR code
+	Secret          string
+	ProvisioningURI string
+}
+
+// BeginTOTPEnrollment replaces any existing secret, so it is not allowed while
+// TOTP is the admin's two factor method. Otherwise, the admin would be locked out
+// until they confirm the new secret.
+func BeginTOTPEnrollment(tx *sqlx.Tx, adminID ID) (*TOTPEnrollment, error) {
+	adminUser, err := GetAdminUser(tx, adminID)
+	switch {
+	case err != nil:
+		return nil, err
+	case adminUser.TwoFactorMethod == TwoFactorMethodTOTP:
+		return nil, fmt.Errorf("Admin %s must switch two factor methods before re-enrolling an authenticator app", adminID)
+	}
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encryptedSecret, err := encrypt.EncryptSecret(totpSecretEncryptionPurpose, *secret)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(upsertTOTPSecretQuery, adminID, *encryptedSecret); err != nil {
+		return nil, err
+	}
+	return &TOTPEnrollment{
+		Secret:          *secret,
+		ProvisioningURI: makeTOTPProvisioningURI(*secret, adminUser.EmailAddress),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment returns a new set of recovery codes if the code is valid
+// and nil if it is not. The recovery codes are only available at this point since they are stored hashed.
+func ConfirmTOTPEnrollment(tx *sqlx.Tx, adminID ID, code string) (_recoveryCodes []string, _err error) {
+	totpSecret, err := lookupTOTPSecret(tx, adminID)
+	switch {
+	case err != nil:
+		return nil, err
+	case totpSecret == nil:
+		return nil, fmt.Errorf("Admin %s has not started enrolling an authenticator app", adminID)
+	case totpSecret.IsConfirmed:
+		return nil, fmt.Errorf("Admin %s has already confirmed their authenticator app", adminID)
+	}
+	secret, err := encrypt.DecryptSecret(totpSecretEncryptionPurpose, totpSecret.Secret)
+	if err != nil {
+		return nil, err
+	}
+	timeStep, err := findTOTPTimeStepForCode(*secret, code, time.Now(), nil)
+	switch {
+	case err != nil:
+		return nil, err
+	case timeStep == nil:
+		return nil, nil
+	}
+	res, err := tx.Exec(confirmTOTPSecretQuery, adminID, totpSecret.Secret, *timeStep)
+	if err != nil {
+		return nil, err
+	}
+	if numRows, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if numRows == 0 {
+		return nil, nil
+	}
+	return RegenerateRecoveryCodes(tx, adminID)
+}
+
+func ValidateTOTPCode(tx *sqlx.Tx, adminID ID, code string) (_isValid bool, _err error) {
+	totpSecret, err := lookupTOTPSecret(tx, adminID)
+	switch {
+	case err != nil:
+		return false, err
+	case totpSecret == nil,
+		!totpSecret.IsConfirmed:
+		return false, nil
+	}
+	secret, err := encrypt.DecryptSecret(totpSecretEncryptionPurpose, totpSecret.Secret)
+	if err != nil {
+		return false, err
+	}
+	timeStep, err := findTOTPTimeStepForCode(*secret, code, time.Now(), totpSecret.LastUsedTimeStep)
+	switch {
+	case err != nil:
+		return false, err
+	case timeStep == nil:
+		return false, nil
+	}
+	res, err := tx.Exec(useTOTPTimeStepQuery, adminID, *timeStep)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
+
+// RegenerateRecoveryCodes invalidates all existing recovery codes
+func RegenerateRecoveryCodes(tx *sqlx.Tx, adminID ID) ([]string, error) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(deleteRecoveryCodesForAdminQuery, adminID); err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		if _, err := tx.Exec(createRecoveryCodeQuery, adminID, hashRecoveryCode(code)); err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+func ValidateRecoveryCode(tx *sqlx.Tx, adminID ID, code string) (_isValid bool, _err error) {
+	res, err := tx.Exec(useRecoveryCodeQuery, adminID, hashRecoveryCode(code))
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
+
+func lookupTOTPSecret(tx *sqlx.Tx, adminID ID) (*dbTOTPSecret, error) {
+	var matches []dbTOTPSecret
+	err := tx.Select(&matches, getTOTPSecretQuery, adminID)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one TOTP secret for admin %s, but got %d", adminID, len(matches))
+	case !encrypt.IsEncryptedSecret(matches[0].Secret):
+		encryptedSecret, err := encrypt.EncryptSecret(totpSecretEncryptionPurpose, matches[0].Secret)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(encryptTOTPSecretQuery, adminID, matches[0].Secret, *encryptedSecret); err != nil {
+			return nil, err
+		}
+		matches[0].Secret = *encryptedSecret
+		return &matches[0], nil
+	default:
+		return &matches[0], nil
+	}
+}
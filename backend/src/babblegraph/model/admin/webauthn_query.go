@@ -0,0 +1,306 @@
+package admin
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	createWebAuthnChallengeQuery = "INSERT INTO admin_webauthn_challenge (admin_user_id, challenge, ceremony, expires_at) VALUES ($1, $2, $3, $4)"
+	// Deleting the challenge as it is checked is what makes it single use
+	consumeWebAuthnChallengeQuery = `DELETE FROM admin_webauthn_challenge
+        WHERE admin_user_id = $1
+        AND challenge = $2
+        AND ceremony = $3
+        AND expires_at > timezone('utc', now())`
+	deleteExpiredWebAuthnChallengesQuery = "DELETE FROM admin_webauthn_challenge WHERE expires_at < timezone('utc', now())"
+
+	getWebAuthnCredentialsForAdminQuery = "SELECT * FROM admin_webauthn_credential WHERE admin_user_id = $1 ORDER BY created_at ASC"
+	lookupWebAuthnCredentialQuery       = "SELECT * FROM admin_webauthn_credential WHERE admin_user_id = $1 AND credential_id = $2"
+	createWebAuthnCredentialQuery       = `INSERT INTO
+        admin_webauthn_credential (
+            admin_user_id,
+            name,
+            credential_id,
+            public_key,
+            sign_count
+        ) VALUES ($1, $2, $3, $4, $5)`
+	updateWebAuthnCredentialUsageQuery = `UPDATE admin_webauthn_credential
+        SET sign_count = $3, last_used_at = timezone('utc', now()), last_modified_at = timezone('utc', now())
+        WHERE admin_user_id = $1 AND credential_id = $2`
+	deleteWebAuthnCredentialQuery = "DELETE FROM admin_webauthn_credential WHERE admin_user_id = $1 AND credential_id = $2"
+
+	maxWebAuthnCredentialNameLength = 64
+)
+
+// These are the values that the browser needs to call navigator.credentials.create()
+type WebAuthnRegistrationOptions struct {
+	Challenge        string
+	RelyingPartyID   string
+	RelyingPartyName string
+	// UserHandle is the base64url encoded admin ID
+	UserHandle           string
+	UserName             string
+	ExcludeCredentialIDs []string
+}
+
+func BeginWebAuthnRegistration(tx *sqlx.Tx, adminID ID) (*WebAuthnRegistrationOptions, error) {
+	adminUser, err := GetAdminUser(tx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := getWebAuthnRelyingParty()
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := GetWebAuthnCredentials(tx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := createWebAuthnChallenge(tx, adminID, webAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	excludeCredentialIDs := []string{}
+	for _, c := range credentials {
+		excludeCredentialIDs = append(excludeCredentialIDs, c.CredentialID)
+	}
+	return &WebAuthnRegistrationOptions{
+		Challenge:            *challenge,
+		RelyingPartyID:       rp.ID,
+		RelyingPartyName:     webAuthnRelyingPartyName,
+		UserHandle:           base64.RawURLEncoding.EncodeToString([]byte(adminUser.ID)),
+		UserName:             adminUser.EmailAddress,
+		ExcludeCredentialIDs: excludeCredentialIDs,
+	}, nil
+}
+
+type FinishWebAuthnRegistrationInput struct {
+	Name string
+	// base64url encoded, as returned by the browser
+	CredentialID      string
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	// DER encoded SubjectPublicKeyInfo, as returned by getPublicKey()
+	PublicKey []byte
+}
+
+func FinishWebAuthnRegistration(tx *sqlx.Tx, adminID ID, input FinishWebAuthnRegistrationInput) error {
+	rp, err := getWebAuthnRelyingParty()
+	if err != nil {
+		return err
+	}
+	verified, err := verifyWebAuthnRegistration(input, *rp)
+	if err != nil {
+		return err
+	}
+	didConsume, err := consumeWebAuthnChallenge(tx, adminID, verified.Challenge, webAuthnCeremonyRegistration)
+	switch {
+	case err != nil:
+		return err
+	case !didConsume:
+		return fmt.Errorf("No outstanding registration challenge for admin %s", adminID)
+	}
+	name := strings.TrimSpace(input.Name)
+	switch {
+	case len(name) == 0:
+		name = "Security Key"
+	case len(name) > maxWebAuthnCredentialNameLength:
+		name = name[:maxWebAuthnCredentialNameLength]
+	}
+	if _, err := tx.Exec(createWebAuthnCredentialQuery, adminID, name, input.CredentialID, input.PublicKey, verified.SignCount); err != nil {
+		return err
+	}
+	return nil
+}
+
+// These are the values that the browser needs to call navigator.credentials.get()
+type WebAuthnAuthenticationOptions struct {
+	Challenge          string
+	RelyingPartyID     string
+	AllowCredentialIDs []string
+}
+
+func BeginWebAuthnAuthentication(tx *sqlx.Tx, adminID ID) (*WebAuthnAuthenticationOptions, error) {
+	rp, err := getWebAuthnRelyingParty()
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := GetWebAuthnCredentials(tx, adminID)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(credentials) == 0:
+		return nil, fmt.Errorf("Admin %s has no security keys", adminID)
+	}
+	challenge, err := createWebAuthnChallenge(tx, adminID, webAuthnCeremonyAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	var allowCredentialIDs []string
+	for _, c := range credentials {
+		allowCredentialIDs = append(allowCredentialIDs, c.CredentialID)
+	}
+	return &WebAuthnAuthenticationOptions{
+		Challenge:          *challenge,
+		RelyingPartyID:     rp.ID,
+		AllowCredentialIDs: allowCredentialIDs,
+	}, nil
+}
+
+type WebAuthnAssertion struct {
+	// base64url encoded, as returned by the browser
+	CredentialID      string
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+}
+
+func ValidateWebAuthnAssertion(tx *sqlx.Tx, adminID ID, assertion WebAuthnAssertion) (_isValid bool, _err error) {
+	rp, err := getWebAuthnRelyingParty()
+	if err != nil {
+		return false, err
+	}
+	var matches []dbWebAuthnCredential
+	err = tx.Select(&matches, lookupWebAuthnCredentialQuery, adminID, assertion.CredentialID)
+	switch {
+	case err != nil:
+		return false, err
+	case len(matches) == 0:
+		return false, nil
+	case len(matches) > 1:
+		return false, fmt.Errorf("Expected at most one credential, but got %d", len(matches))
+	}
+	verified, err := verifyWebAuthnAssertion(matches[0], assertion, *rp)
+	if err != nil {
+		return false, nil
+	}
+	didConsume, err := consumeWebAuthnChallenge(tx, adminID, verified.Challenge, webAuthnCeremonyAuthentication)
+	switch {
+	case err != nil:
+		return false, err
+	case !didConsume:
+		return false, nil
+	}
+	if _, err := tx.Exec(updateWebAuthnCredentialUsageQuery, adminID, assertion.CredentialID, verified.SignCount); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func GetWebAuthnCredentials(tx *sqlx.Tx, adminID ID) ([]WebAuthnCredential, error) {
+	var matches []dbWebAuthnCredential
+	if err := tx.Select(&matches, getWebAuthnCredentialsForAdminQuery, adminID); err != nil {
+		return nil, err
+	}
+	var out []WebAuthnCredential
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+// RemoveWebAuthnCredential will not remove an admin's last
+// security key while it is their two factor method
+func RemoveWebAuthnCredential(tx *sqlx.Tx, adminID ID, credentialID string) (_didRemove bool, _err error) {
+	settings, err := GetTwoFactorSettings(tx, adminID)
+	switch {
+	case err != nil:
+		return false, err
+	case settings.Method == TwoFactorMethodWebAuthn && len(settings.WebAuthnCredentials) <= 1:
+		return false, fmt.Errorf("Admin %s must switch two factor methods before removing their last security key", adminID)
+	}
+	res, err := tx.Exec(deleteWebAuthnCredentialQuery, adminID, credentialID)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
+
+func RemoveExpiredWebAuthnChallenges(tx *sqlx.Tx) error {
+	if _, err := tx.Exec(deleteExpiredWebAuthnChallengesQuery); err != nil {
+		return err
+	}
+	return nil
+}
+
+func createWebAuthnChallenge(tx *sqlx.Tx, adminID ID, ceremony webAuthnCeremony) (*string, error) {
+	challenge, err := generateWebAuthnChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(createWebAuthnChallengeQuery, adminID, *challenge, ceremony, time.Now().Add(webAuthnChallengeLifetime)); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func consumeWebAuthnChallenge(tx *sqlx.Tx, adminID ID, challenge string, ceremony webAuthnCeremony) (_didConsume bool, _err error) {
+	res, err := tx.Exec(consumeWebAuthnChallengeQuery, adminID, challenge, ceremony)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
+
+type verifiedWebAuthnResponse struct {
+	Challenge string
+	SignCount uint32
+}
+
+func verifyWebAuthnRegistration(input FinishWebAuthnRegistrationInput, rp webAuthnRelyingParty) (*verifiedWebAuthnResponse, error) {
+	challenge, err := verifyWebAuthnClientData(input.ClientDataJSON, webAuthnCeremonyRegistration, rp)
+	if err != nil {
+		return nil, err
+	}
+	authenticatorData, err := parseWebAuthnAuthenticatorData(input.AuthenticatorData, rp)
+	switch {
+	case err != nil:
+		return nil, err
+	case authenticatorData.CredentialID == nil:
+		return nil, fmt.Errorf("Authenticator data has no attested credential")
+	case base64.RawURLEncoding.EncodeToString(authenticatorData.CredentialID) != input.CredentialID:
+		return nil, fmt.Errorf("Credential ID does not match authenticator data")
+	case !isValidPublicKeyType(input.PublicKey):
+		return nil, fmt.Errorf("Unsupported public key")
+	}
+	return &verifiedWebAuthnResponse{
+		Challenge: *challenge,
+		SignCount: authenticatorData.SignCount,
+	}, nil
+}
+
+func verifyWebAuthnAssertion(credential dbWebAuthnCredential, assertion WebAuthnAssertion, rp webAuthnRelyingParty) (*verifiedWebAuthnResponse, error) {
+	challenge, err := verifyWebAuthnClientData(assertion.ClientDataJSON, webAuthnCeremonyAuthentication, rp)
+	if err != nil {
+		return nil, err
+	}
+	authenticatorData, err := parseWebAuthnAuthenticatorData(assertion.AuthenticatorData, rp)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyWebAuthnSignature(credential.PublicKey, assertion.AuthenticatorData, assertion.ClientDataJSON, assertion.Signature); err != nil {
+		return nil, err
+	}
+	// Authenticators that don't keep a counter always send zero.
+	// Otherwise, a counter that doesn't increase means the key may have been cloned.
+	if (authenticatorData.SignCount != 0 || credential.SignCount != 0) && int64(authenticatorData.SignCount) <= credential.SignCount {
+		return nil, fmt.Errorf("Sign count did not increase")
+	}
+	return &verifiedWebAuthnResponse{
+		Challenge: *challenge,
+		SignCount: authenticatorData.SignCount,
+	}, nil
+}
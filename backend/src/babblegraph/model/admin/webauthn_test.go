@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+var testRelyingParty = webAuthnRelyingParty{
+	ID:     "babblegraph.test",
+	Origin: "http://babblegraph.test",
+}
+
+func makeTestAuthenticatorData(rpID string, flags byte, signCount uint32, credentialID []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	out := append([]byte{}, rpIDHash[:]...)
+	out = append(out, flags)
+	var signCountBytes [4]byte
+	binary.BigEndian.PutUint32(signCountBytes[:], signCount)
+	out = append(out, signCountBytes[:]...)
+	if credentialID != nil {
+		out = append(out, make([]byte, 16)...)
+		var credentialIDLength [2]byte
+		binary.BigEndian.PutUint16(credentialIDLength[:], uint16(len(credentialID)))
+		out = append(out, credentialIDLength[:]...)
+		out = append(out, credentialID...)
+	}
+	return out
+}
+
+func makeTestClientData(t *testing.T, clientDataType, challenge, origin string) []byte {
+	clientDataJSON, err := json.Marshal(webAuthnClientData{
+		Type:      clientDataType,
+		Challenge: challenge,
+		Origin:    origin,
+	})
+	if err != nil {
+		t.Fatalf("Error marshalling client data: %s", err.Error())
+	}
+	return clientDataJSON
+}
+
+func signTestAssertion(t *testing.T, key *ecdsa.PrivateKey, authenticatorData, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signedDataHash := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	r, s, err := ecdsa.Sign(rand.Reader, key, signedDataHash[:])
+	if err != nil {
+		t.Fatalf("Error signing: %s", err.Error())
+	}
+	signature, err := asn1.Marshal(struct {
+		R, S *big.Int
+	}{r, s})
+	if err != nil {
+		t.Fatalf("Error marshalling signature: %s", err.Error())
+	}
+	return signature
+}
+
+func TestVerifyWebAuthnRegistration(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err.Error())
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Error marshalling public key: %s", err.Error())
+	}
+	credentialID := []byte("test-credential")
+	validInput := FinishWebAuthnRegistrationInput{
+		CredentialID:      base64.RawURLEncoding.EncodeToString(credentialID),
+		ClientDataJSON:    makeTestClientData(t, "webauthn.create", "challenge", testRelyingParty.Origin),
+		AuthenticatorData: makeTestAuthenticatorData(testRelyingParty.ID, webAuthnFlagUserPresent|webAuthnFlagAttestedCredentialData, 0, credentialID),
+		PublicKey:         publicKey,
+	}
+	verified, err := verifyWebAuthnRegistration(validInput, testRelyingParty)
+	switch {
+	case err != nil:
+		t.Fatalf("Expected registration to verify, but got error: %s", err.Error())
+	case verified.Challenge != "challenge":
+		t.Errorf("Expected challenge to be challenge, but got %s", verified.Challenge)
+	}
+
+	wrongOrigin := validInput
+	wrongOrigin.ClientDataJSON = makeTestClientData(t, "webauthn.create", "challenge", "https://evil.test")
+	wrongCeremony := validInput
+	wrongCeremony.ClientDataJSON = makeTestClientData(t, "webauthn.get", "challenge", testRelyingParty.Origin)
+	wrongRelyingParty := validInput
+	wrongRelyingParty.AuthenticatorData = makeTestAuthenticatorData("evil.test", webAuthnFlagUserPresent|webAuthnFlagAttestedCredentialData, 0, credentialID)
+	wrongCredentialID := validInput
+	wrongCredentialID.CredentialID = base64.RawURLEncoding.EncodeToString([]byte("other-credential"))
+	userNotPresent := validInput
+	userNotPresent.AuthenticatorData = makeTestAuthenticatorData(testRelyingParty.ID, webAuthnFlagAttestedCredentialData, 0, credentialID)
+	invalidPublicKey := validInput
+	invalidPublicKey.PublicKey = []byte("not a key")
+	for idx, input := range []FinishWebAuthnRegistrationInput{
+		wrongOrigin,
+		wrongCeremony,
+		wrongRelyingParty,
+		wrongCredentialID,
+		userNotPresent,
+		invalidPublicKey,
+	} {
+		if _, err := verifyWebAuthnRegistration(input, testRelyingParty); err == nil {
+			t.Errorf("Error on test case %d: expected registration to fail verification", idx)
+		}
+	}
+}
+
+func TestVerifyWebAuthnAssertion(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err.Error())
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Error marshalling public key: %s", err.Error())
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err.Error())
+	}
+	credential := dbWebAuthnCredential{
+		PublicKey: publicKey,
+		SignCount: 4,
+	}
+	clientDataJSON := makeTestClientData(t, "webauthn.get", "challenge", testRelyingParty.Origin)
+	makeAssertion := func(signingKey *ecdsa.PrivateKey, signCount uint32) WebAuthnAssertion {
+		authenticatorData := makeTestAuthenticatorData(testRelyingParty.ID, webAuthnFlagUserPresent, signCount, nil)
+		return WebAuthnAssertion{
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authenticatorData,
+			Signature:         signTestAssertion(t, signingKey, authenticatorData, clientDataJSON),
+		}
+	}
+	verified, err := verifyWebAuthnAssertion(credential, makeAssertion(key, 5), testRelyingParty)
+	switch {
+	case err != nil:
+		t.Fatalf("Expected assertion to verify, but got error: %s", err.Error())
+	case verified.Challenge != "challenge":
+		t.Errorf("Expected challenge to be challenge, but got %s", verified.Challenge)
+	case verified.SignCount != 5:
+		t.Errorf("Expected sign count to be 5, but got %d", verified.SignCount)
+	}
+
+	tamperedClientData := makeAssertion(key, 5)
+	tamperedClientData.ClientDataJSON = makeTestClientData(t, "webauthn.get", "other-challenge", testRelyingParty.Origin)
+	for idx, assertion := range []WebAuthnAssertion{
+		makeAssertion(otherKey, 5),
+		// A sign count that does not increase suggests a cloned key
+		makeAssertion(key, 4),
+		tamperedClientData,
+	} {
+		if _, err := verifyWebAuthnAssertion(credential, assertion, testRelyingParty); err == nil {
+			t.Errorf("Error on test case %d: expected assertion to fail verification", idx)
+		}
+	}
+
+	// Authenticators without counters always send zero
+	if _, err := verifyWebAuthnAssertion(dbWebAuthnCredential{PublicKey: publicKey}, makeAssertion(key, 0), testRelyingParty); err != nil {
+		t.Errorf("Expected assertion without a sign count to verify, but got error: %s", err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package advertising
 
 import (
+	"babblegraph/model/email"
 	"babblegraph/util/ptr"
 	"fmt"
 
@@ -42,6 +43,17 @@ func GetURLForUserAdvertisementID(tx *sqlx.Tx, id UserAdvertisementID, useAdditi
 }
 
 func RegisterUserAdvertisementClick(tx *sqlx.Tx, id UserAdvertisementID) error {
-	_, err := tx.Exec(registerUserAdvertisementClickQuery, id)
-	return err
+	if _, err := tx.Exec(registerUserAdvertisementClickQuery, id); err != nil {
+		return err
+	}
+	var matches []dbUserAdvertisement
+	if err := tx.Select(&matches, getUserAdvertisementQuery, id); err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if err := email.RecordEngagementEvent(tx, m.EmailRecordID, email.EngagementEventTypeClicked, email.EngagementSectionAdvertisement.Ptr()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -3,14 +3,11 @@ package billing
 import (
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
-	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/coupon"
-	"github.com/stripe/stripe-go/v72/promotioncode"
 )
 
 const (
@@ -68,19 +65,18 @@ type CreatePromotionCodeInput struct {
 }
 
 func CreatePromotionCode(c ctx.LogContext, tx *sqlx.Tx, input CreatePromotionCodeInput) (*PromotionCode, error) {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
 	var stripeCoupon *stripe.Coupon
 	var stripePromotionCode *stripe.PromotionCode
 	var err error
 	defer func() {
 		if err != nil {
 			if stripeCoupon != nil {
-				if _, err := coupon.Del(stripeCoupon.ID, nil); err != nil {
+				if err := stripeClient.DeleteCoupon(stripeCoupon.ID); err != nil {
 					c.Errorf("Error rolling back coupon %s", stripeCoupon.ID)
 				}
 			}
 			if stripePromotionCode != nil {
-				if _, err := promotioncode.Update(stripePromotionCode.ID, &stripe.PromotionCodeParams{
+				if _, err := stripeClient.UpdatePromotionCode(stripePromotionCode.ID, &stripe.PromotionCodeParams{
 					Active: ptr.Bool(false),
 				}); err != nil {
 					c.Errorf("Error rolling back promotion code %s", stripePromotionCode.ID)
@@ -100,11 +96,11 @@ func CreatePromotionCode(c ctx.LogContext, tx *sqlx.Tx, input CreatePromotionCod
 		return nil, fmt.Errorf("Must specify either amount off or percent off")
 	}
 	couponParams.MaxRedemptions = input.MaxRedemptions
-	stripeCoupon, err = coupon.New(couponParams)
+	stripeCoupon, err = stripeClient.NewCoupon(couponParams)
 	if err != nil {
 		return nil, err
 	}
-	stripePromotionCode, err = promotioncode.New(&stripe.PromotionCodeParams{
+	stripePromotionCode, err = stripeClient.NewPromotionCode(&stripe.PromotionCodeParams{
 		Coupon: stripe.String(stripeCoupon.ID),
 		Code:   ptr.String(input.Code),
 		Active: ptr.Bool(true),
@@ -3,13 +3,11 @@ package billing
 import (
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
-	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/customer"
 )
 
 const (
@@ -40,7 +38,6 @@ func GetOrCreateBillingInformationForUser(c ctx.LogContext, tx *sqlx.Tx, userID
 		}
 		return out, nil
 	default:
-		stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
 		user, err := users.GetUser(tx, userID)
 		switch {
 		case err != nil:
@@ -51,21 +48,21 @@ func GetOrCreateBillingInformationForUser(c ctx.LogContext, tx *sqlx.Tx, userID
 		customerParams := &stripe.CustomerParams{
 			Email: stripe.String(user.EmailAddress),
 		}
-		stripeCustomer, err := customer.New(customerParams)
+		stripeCustomer, err := stripeClient.NewCustomer(customerParams)
 		if err != nil {
 			return nil, err
 		}
 		externalMappingID, err := insertExternalIDMapping(tx, stripeCustomer.ID)
 		if err != nil {
 			c.Warnf("Attempting to rollback customer with Stripe ID %s and Babblegraph User ID %s", stripeCustomer.ID, userID)
-			if _, sErr := customer.Del(stripeCustomer.ID, &stripe.CustomerParams{}); sErr != nil {
+			if sErr := stripeClient.DeleteCustomer(stripeCustomer.ID); sErr != nil {
 				c.Errorf("Error rolling back customer ID %s in Stripe for user ID %s because of error %s", stripeCustomer.ID, userID, sErr.Error())
 			}
 			return nil, err
 		}
 		if err := insertBillingInformationForUserID(tx, userID, *externalMappingID); err != nil {
 			c.Warnf("Attempting to rollback customer with Stripe ID %s and Babblegraph User ID %s", stripeCustomer.ID, userID)
-			if _, sErr := customer.Del(stripeCustomer.ID, &stripe.CustomerParams{}); sErr != nil {
+			if sErr := stripeClient.DeleteCustomer(stripeCustomer.ID); sErr != nil {
 				c.Errorf("Error rolling back customer ID %s in Stripe for user ID %s because of error %s", stripeCustomer.ID, userID, sErr.Error())
 			}
 			return nil, err
@@ -77,6 +74,50 @@ func GetOrCreateBillingInformationForUser(c ctx.LogContext, tx *sqlx.Tx, userID
 	}
 }
 
+// AnonymizeStripeCustomerForUser removes the user's payment methods and contact
+// details from Stripe. The customer itself is kept since invoices reference it.
+// This can be called more than once for the same user.
+func AnonymizeStripeCustomerForUser(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID) error {
+	billingInformation, err := lookupBillingInformationForUserID(tx, userID)
+	switch {
+	case err != nil:
+		return err
+	case billingInformation == nil:
+		return nil
+	}
+	externalID, err := getExternalIDMapping(tx, billingInformation.ExternalIDMappingID)
+	if err != nil {
+		return err
+	}
+	switch externalID.IDType {
+	case externalIDTypeStripe:
+		stripePaymentMethods, err := stripeClient.ListPaymentMethods(&stripe.PaymentMethodListParams{
+			Customer: ptr.String(externalID.ExternalID),
+			Type:     ptr.String("card"),
+		})
+		if err != nil {
+			return err
+		}
+		for _, paymentMethod := range stripePaymentMethods {
+			if err := stripeClient.DetachPaymentMethod(paymentMethod.ID); err != nil {
+				return err
+			}
+		}
+		c.Infof("Anonymizing Stripe customer %s for user %s", externalID.ExternalID, userID)
+		if _, err := stripeClient.UpdateCustomer(externalID.ExternalID, &stripe.CustomerParams{
+			Email:       ptr.String(""),
+			Name:        ptr.String(""),
+			Phone:       ptr.String(""),
+			Description: ptr.String(""),
+		}); err != nil {
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("Unrecognized external ID type %s", externalID.IDType)
+	}
+}
+
 // TODO: maybe external ID type should be exported
 func LookupBillingInformationByExternalID(tx *sqlx.Tx, externalID string) (*BillingInformation, error) {
 	externalIDMapping, err := lookupExternalIDMappingByExternalID(tx, externalIDTypeStripe, externalID)
@@ -0,0 +1,228 @@
+package billing
+
+import (
+	"babblegraph/config"
+	"babblegraph/util/ctx"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stripe/stripe-go/v72"
+)
+
+const (
+	insertDunningCycleQuery                    = "INSERT INTO billing_dunning_cycles (premium_newsletter_subscription_id, invoice_external_id_mapping_id, amount_due_cents, currency, payment_attempt_count, next_payment_attempt_at, grace_period_ends_at) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+	lookupOpenDunningCycleForSubscriptionQuery = "SELECT * FROM billing_dunning_cycles WHERE premium_newsletter_subscription_id = $1 AND resolved_at IS NULL FOR UPDATE"
+	updateDunningCycleForFailedPaymentQuery    = "UPDATE billing_dunning_cycles SET invoice_external_id_mapping_id = $2, amount_due_cents = $3, currency = $4, payment_attempt_count = $5, next_payment_attempt_at = $6, grace_period_ends_at = $7, last_modified_at = timezone('utc', now()) WHERE _id = $1"
+	getOpenDunningCyclesQuery                  = "SELECT * FROM billing_dunning_cycles WHERE resolved_at IS NULL ORDER BY created_at ASC"
+	markDunningReminderSentQuery               = "UPDATE billing_dunning_cycles SET last_reminder_type = $2, last_modified_at = timezone('utc', now()) WHERE _id = $1"
+	resolveDunningCycleQuery                   = "UPDATE billing_dunning_cycles SET resolved_at = timezone('utc', now()), resolution = $2, last_modified_at = timezone('utc', now()) WHERE _id = $1 AND resolved_at IS NULL"
+	getDunningCyclesStartedBetweenQuery        = "SELECT * FROM billing_dunning_cycles WHERE created_at >= $1 AND created_at < $2"
+)
+
+// recordFailedInvoicePayment starts a dunning cycle for the subscription,
+// or updates the open one with Stripe's latest retry information.
+func recordFailedInvoicePayment(c ctx.LogContext, tx *sqlx.Tx, premiumNewsletterSubscription PremiumNewsletterSubscription, stripeInvoice stripe.Invoice) error {
+	switch {
+	case premiumNewsletterSubscription.ID == nil:
+		return fmt.Errorf("Cannot record failed payment for a subscription without an ID")
+	case premiumNewsletterSubscription.PaymentState == PaymentStateCreatedUnpaid:
+		// The user never had premium from this subscription, so there's nothing to keep going
+		c.Infof("Subscription %s has not been paid yet, not starting dunning for invoice %s", *premiumNewsletterSubscription.ID, stripeInvoice.ID)
+		return nil
+	case premiumNewsletterSubscription.PaymentState == PaymentStateTerminated:
+		c.Infof("Subscription %s is already terminated, not starting dunning for invoice %s", *premiumNewsletterSubscription.ID, stripeInvoice.ID)
+		return nil
+	case stripeInvoice.AmountDue <= 0:
+		return nil
+	}
+	// A trial that ends without a card fails its first invoice, but there was never a
+	// payment to recover, so those users are left for the usual trial expiration
+	userID, err := premiumNewsletterSubscription.GetUserID()
+	if err != nil {
+		return err
+	}
+	paymentMethods, err := GetPaymentMethodsForUser(tx, *userID)
+	switch {
+	case err != nil:
+		return err
+	case len(paymentMethods) == 0:
+		c.Infof("Subscription %s has no payment method, not starting dunning for invoice %s", *premiumNewsletterSubscription.ID, stripeInvoice.ID)
+		return nil
+	}
+	invoiceExternalIDMapping, err := lookupExternalIDMappingByExternalID(tx, externalIDTypeStripe, stripeInvoice.ID)
+	if err != nil {
+		return err
+	}
+	var invoiceExternalIDMappingID externalIDMappingID
+	if invoiceExternalIDMapping != nil {
+		invoiceExternalIDMappingID = invoiceExternalIDMapping.ID
+	} else {
+		id, err := insertExternalIDMapping(tx, stripeInvoice.ID)
+		if err != nil {
+			return err
+		}
+		invoiceExternalIDMappingID = *id
+	}
+	var nextPaymentAttemptAt *time.Time
+	if stripeInvoice.NextPaymentAttempt > 0 {
+		t := time.Unix(stripeInvoice.NextPaymentAttempt, 0)
+		nextPaymentAttemptAt = &t
+	}
+	openDunningCycle, err := lookupDBOpenDunningCycleForPremiumNewsletterSubscription(tx, *premiumNewsletterSubscription.ID)
+	switch {
+	case err != nil:
+		return err
+	case openDunningCycle == nil:
+		c.Infof("Starting dunning for subscription %s after failed payment on invoice %s", *premiumNewsletterSubscription.ID, stripeInvoice.ID)
+		gracePeriodEndsAt := getGracePeriodEndForDunningCycle(time.Now(), nextPaymentAttemptAt)
+		if _, err := tx.Exec(insertDunningCycleQuery, *premiumNewsletterSubscription.ID, invoiceExternalIDMappingID, stripeInvoice.AmountDue, string(stripeInvoice.Currency), stripeInvoice.AttemptCount, nextPaymentAttemptAt, gracePeriodEndsAt); err != nil {
+			return err
+		}
+	default:
+		gracePeriodEndsAt := getGracePeriodEndForDunningCycle(openDunningCycle.CreatedAt, nextPaymentAttemptAt)
+		if _, err := tx.Exec(updateDunningCycleForFailedPaymentQuery, openDunningCycle.ID, invoiceExternalIDMappingID, stripeInvoice.AmountDue, string(stripeInvoice.Currency), stripeInvoice.AttemptCount, nextPaymentAttemptAt, gracePeriodEndsAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The grace period is extended past the configured length if Stripe
+// is going to retry the payment after it would have ended, so that the
+// last retry still has a chance to recover the subscription.
+func getGracePeriodEndForDunningCycle(startedAt time.Time, nextPaymentAttemptAt *time.Time) time.Time {
+	gracePeriodEndsAt := startedAt.Add(time.Duration(config.DunningGracePeriodDays) * 24 * time.Hour)
+	if nextPaymentAttemptAt != nil {
+		afterNextPaymentAttempt := nextPaymentAttemptAt.Add(24 * time.Hour)
+		if afterNextPaymentAttempt.After(gracePeriodEndsAt) {
+			return afterNextPaymentAttempt
+		}
+	}
+	return gracePeriodEndsAt
+}
+
+func LookupOpenDunningCycleForPremiumNewsletterSubscription(tx *sqlx.Tx, id PremiumNewsletterSubscriptionID) (*DunningCycle, error) {
+	dunningCycle, err := lookupDBOpenDunningCycleForPremiumNewsletterSubscription(tx, id)
+	switch {
+	case err != nil:
+		return nil, err
+	case dunningCycle == nil:
+		return nil, nil
+	default:
+		out := dunningCycle.ToNonDB()
+		return &out, nil
+	}
+}
+
+func lookupDBOpenDunningCycleForPremiumNewsletterSubscription(tx *sqlx.Tx, id PremiumNewsletterSubscriptionID) (*dbDunningCycle, error) {
+	var matches []dbDunningCycle
+	err := tx.Select(&matches, lookupOpenDunningCycleForSubscriptionQuery, id)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one open dunning cycle for subscription %s, but got %d", id, len(matches))
+	default:
+		return &matches[0], nil
+	}
+}
+
+func GetOpenDunningCycles(tx *sqlx.Tx) ([]DunningCycle, error) {
+	var matches []dbDunningCycle
+	if err := tx.Select(&matches, getOpenDunningCyclesQuery); err != nil {
+		return nil, err
+	}
+	var out []DunningCycle
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+func MarkDunningReminderSent(tx *sqlx.Tx, id DunningCycleID, reminderType DunningReminderType) error {
+	if _, err := tx.Exec(markDunningReminderSentQuery, id, reminderType); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ResolveDunningCycle(tx *sqlx.Tx, id DunningCycleID, resolution DunningResolution) error {
+	if _, err := tx.Exec(resolveDunningCycleQuery, id, resolution); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDunningResolutionForPaymentState returns nil if the
+// subscription is still waiting on a payment
+func GetDunningResolutionForPaymentState(paymentState PaymentState) *DunningResolution {
+	switch paymentState {
+	case PaymentStateActive:
+		return DunningResolutionRecovered.Ptr()
+	case PaymentStateTerminated:
+		return DunningResolutionCanceled.Ptr()
+	default:
+		return nil
+	}
+}
+
+// GetDueDunningReminderType returns nil if the user has already
+// been sent the reminder for where the dunning cycle is now
+func GetDueDunningReminderType(dunningCycle DunningCycle, now time.Time) *DunningReminderType {
+	finalNoticeAt := dunningCycle.GracePeriodEndsAt.Add(-time.Duration(config.DunningFinalNoticeDaysBeforeDowngrade) * 24 * time.Hour)
+	var reminderType DunningReminderType
+	switch {
+	case dunningCycle.NextPaymentAttemptAt == nil,
+		!now.Before(finalNoticeAt):
+		// Stripe has given up retrying, or the downgrade is close
+		reminderType = DunningReminderTypeFinalNotice
+	case dunningCycle.PaymentAttemptCount > 1:
+		reminderType = DunningReminderTypeStillFailing
+	default:
+		reminderType = DunningReminderTypePaymentFailed
+	}
+	if dunningCycle.LastReminderType != nil && reminderType.getEscalationLevel() <= dunningCycle.LastReminderType.getEscalationLevel() {
+		return nil
+	}
+	return reminderType.Ptr()
+}
+
+// GetDunningMetrics is for dunning cycles that started in the
+// time range, whether or not they have been resolved yet
+func GetDunningMetrics(tx *sqlx.Tx, startTime, endTime time.Time) (*DunningMetrics, error) {
+	var matches []dbDunningCycle
+	if err := tx.Select(&matches, getDunningCyclesStartedBetweenQuery, startTime, endTime); err != nil {
+		return nil, err
+	}
+	metrics := getDunningMetricsForCycles(matches)
+	return &metrics, nil
+}
+
+func getDunningMetricsForCycles(dunningCycles []dbDunningCycle) DunningMetrics {
+	metrics := DunningMetrics{
+		RecoveredAmountCents: make(map[string]int64),
+		LostAmountCents:      make(map[string]int64),
+	}
+	for _, d := range dunningCycles {
+		metrics.StartedCount++
+		currency := strings.ToLower(d.Currency)
+		switch {
+		case d.Resolution == nil:
+			metrics.OpenCount++
+		case *d.Resolution == DunningResolutionRecovered:
+			metrics.RecoveredCount++
+			metrics.RecoveredAmountCents[currency] += d.AmountDueCents
+		case *d.Resolution == DunningResolutionDowngraded:
+			metrics.DowngradedCount++
+			metrics.LostAmountCents[currency] += d.AmountDueCents
+		case *d.Resolution == DunningResolutionCanceled:
+			metrics.CanceledCount++
+			metrics.LostAmountCents[currency] += d.AmountDueCents
+		}
+	}
+	return metrics
+}
@@ -0,0 +1,140 @@
+package billing
+
+import (
+	"babblegraph/config"
+	"testing"
+	"time"
+)
+
+func TestGetGracePeriodEndForDunningCycle(t *testing.T) {
+	startedAt := time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC)
+	defaultGracePeriodEnd := startedAt.Add(time.Duration(config.DunningGracePeriodDays) * 24 * time.Hour)
+	earlyRetry := startedAt.Add(3 * 24 * time.Hour)
+	lateRetry := defaultGracePeriodEnd.Add(2 * 24 * time.Hour)
+	type testCase struct {
+		nextPaymentAttemptAt *time.Time
+		expected             time.Time
+	}
+	for idx, tc := range []testCase{
+		{nextPaymentAttemptAt: nil, expected: defaultGracePeriodEnd},
+		{nextPaymentAttemptAt: &earlyRetry, expected: defaultGracePeriodEnd},
+		{nextPaymentAttemptAt: &lateRetry, expected: lateRetry.Add(24 * time.Hour)},
+	} {
+		if result := getGracePeriodEndForDunningCycle(startedAt, tc.nextPaymentAttemptAt); !result.Equal(tc.expected) {
+			t.Errorf("Error on test case %d: expected %v, but got %v", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestGetDueDunningReminderType(t *testing.T) {
+	now := time.Date(2021, time.October, 5, 12, 0, 0, 0, time.UTC)
+	nextPaymentAttemptAt := now.Add(3 * 24 * time.Hour)
+	type testCase struct {
+		paymentAttemptCount  int64
+		nextPaymentAttemptAt *time.Time
+		gracePeriodEndsAt    time.Time
+		lastReminderType     *DunningReminderType
+		expected             *DunningReminderType
+	}
+	for idx, tc := range []testCase{
+		{
+			paymentAttemptCount:  1,
+			nextPaymentAttemptAt: &nextPaymentAttemptAt,
+			gracePeriodEndsAt:    now.Add(10 * 24 * time.Hour),
+			expected:             DunningReminderTypePaymentFailed.Ptr(),
+		}, {
+			paymentAttemptCount:  1,
+			nextPaymentAttemptAt: &nextPaymentAttemptAt,
+			gracePeriodEndsAt:    now.Add(10 * 24 * time.Hour),
+			lastReminderType:     DunningReminderTypePaymentFailed.Ptr(),
+			expected:             nil,
+		}, {
+			paymentAttemptCount:  2,
+			nextPaymentAttemptAt: &nextPaymentAttemptAt,
+			gracePeriodEndsAt:    now.Add(10 * 24 * time.Hour),
+			lastReminderType:     DunningReminderTypePaymentFailed.Ptr(),
+			expected:             DunningReminderTypeStillFailing.Ptr(),
+		}, {
+			// The user skips straight to the final notice if the downgrade is close
+			paymentAttemptCount:  1,
+			nextPaymentAttemptAt: &nextPaymentAttemptAt,
+			gracePeriodEndsAt:    now.Add(24 * time.Hour),
+			expected:             DunningReminderTypeFinalNotice.Ptr(),
+		}, {
+			// Stripe has stopped retrying
+			paymentAttemptCount: 4,
+			gracePeriodEndsAt:   now.Add(10 * 24 * time.Hour),
+			lastReminderType:    DunningReminderTypeStillFailing.Ptr(),
+			expected:            DunningReminderTypeFinalNotice.Ptr(),
+		}, {
+			// Reminders never go back to a milder one
+			paymentAttemptCount:  2,
+			nextPaymentAttemptAt: &nextPaymentAttemptAt,
+			gracePeriodEndsAt:    now.Add(10 * 24 * time.Hour),
+			lastReminderType:     DunningReminderTypeFinalNotice.Ptr(),
+			expected:             nil,
+		},
+	} {
+		result := GetDueDunningReminderType(DunningCycle{
+			PaymentAttemptCount:  tc.paymentAttemptCount,
+			NextPaymentAttemptAt: tc.nextPaymentAttemptAt,
+			GracePeriodEndsAt:    tc.gracePeriodEndsAt,
+			LastReminderType:     tc.lastReminderType,
+		}, now)
+		switch {
+		case tc.expected == nil && result != nil:
+			t.Errorf("Error on test case %d: expected no reminder, but got %s", idx, *result)
+		case tc.expected != nil && result == nil:
+			t.Errorf("Error on test case %d: expected %s, but got no reminder", idx, *tc.expected)
+		case tc.expected != nil && *tc.expected != *result:
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, *tc.expected, *result)
+		}
+	}
+}
+
+func TestGetDunningResolutionForPaymentState(t *testing.T) {
+	type testCase struct {
+		paymentState PaymentState
+		expected     *DunningResolution
+	}
+	for idx, tc := range []testCase{
+		{paymentState: PaymentStateActive, expected: DunningResolutionRecovered.Ptr()},
+		{paymentState: PaymentStateTerminated, expected: DunningResolutionCanceled.Ptr()},
+		{paymentState: PaymentStatePaymentPending, expected: nil},
+		{paymentState: PaymentStateErrored, expected: nil},
+	} {
+		result := GetDunningResolutionForPaymentState(tc.paymentState)
+		switch {
+		case tc.expected == nil && result != nil:
+			t.Errorf("Error on test case %d: expected no resolution, but got %s", idx, *result)
+		case tc.expected != nil && (result == nil || *tc.expected != *result):
+			t.Errorf("Error on test case %d: expected %s, but got %v", idx, *tc.expected, result)
+		}
+	}
+}
+
+func TestGetDunningMetricsForCycles(t *testing.T) {
+	metrics := getDunningMetricsForCycles([]dbDunningCycle{
+		{AmountDueCents: 2900, Currency: "usd", Resolution: DunningResolutionRecovered.Ptr()},
+		{AmountDueCents: 2900, Currency: "USD", Resolution: DunningResolutionRecovered.Ptr()},
+		{AmountDueCents: 399, Currency: "eur", Resolution: DunningResolutionRecovered.Ptr()},
+		{AmountDueCents: 2900, Currency: "usd", Resolution: DunningResolutionDowngraded.Ptr()},
+		{AmountDueCents: 399, Currency: "usd", Resolution: DunningResolutionCanceled.Ptr()},
+		{AmountDueCents: 2900, Currency: "usd"},
+	})
+	switch {
+	case metrics.StartedCount != 6:
+		t.Errorf("Expected 6 started, but got %d", metrics.StartedCount)
+	case metrics.RecoveredCount != 3,
+		metrics.DowngradedCount != 1,
+		metrics.CanceledCount != 1,
+		metrics.OpenCount != 1:
+		t.Errorf("Got wrong counts: %+v", metrics)
+	case metrics.RecoveredAmountCents["usd"] != 5800,
+		metrics.RecoveredAmountCents["eur"] != 399:
+		t.Errorf("Got wrong recovered amounts: %+v", metrics.RecoveredAmountCents)
+	case metrics.LostAmountCents["usd"] != 3299,
+		len(metrics.LostAmountCents) != 1:
+		t.Errorf("Got wrong lost amounts: %+v", metrics.LostAmountCents)
+	}
+}
@@ -19,7 +19,8 @@ func getStripeProductIDForEnvironment() (*string, error) {
 	case env.EnvironmentStage,
 		env.EnvironmentLocal,
 		env.EnvironmentLocalNoEmail,
-		env.EnvironmentLocalTestEmail:
+		env.EnvironmentLocalTestEmail,
+		env.EnvironmentTest:
 		return ptr.String(PremiumNewsletterSubscriptionStripeProductIDTest), nil
 	default:
 		return nil, fmt.Errorf("unsupported environment: %s", currentEnv)
@@ -0,0 +1,559 @@
+package billing
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+const (
+	fakeStripeWebhookSecret = "whsec_fake"
+
+	// Stripe's smart retries are spread over a few days
+	fakeStripePaymentRetryInterval = 3 * 24 * time.Hour
+)
+
+// fakeStripeClient keeps customers, subscriptions and everything else billing
+// uses in memory. Every change to a subscription queues the webhook events that
+// Stripe would send, signed with fakeStripeWebhookSecret.
+type fakeStripeClient struct {
+	mu     sync.Mutex
+	nextID int
+
+	customers      map[string]*stripe.Customer
+	paymentMethods map[string]*stripe.PaymentMethod
+	subscriptions  map[string]*stripe.Subscription
+	prices         map[string]*stripe.Price
+	coupons        map[string]*stripe.Coupon
+	promotionCodes map[string]*stripe.PromotionCode
+
+	webhookEvents []fakeStripeWebhookEvent
+}
+
+type fakeStripeWebhookEvent struct {
+	Type      string
+	Payload   []byte
+	Signature string
+}
+
+func newFakeStripeClient() *fakeStripeClient {
+	return &fakeStripeClient{
+		customers:      make(map[string]*stripe.Customer),
+		paymentMethods: make(map[string]*stripe.PaymentMethod),
+		subscriptions:  make(map[string]*stripe.Subscription),
+		prices:         make(map[string]*stripe.Price),
+		coupons:        make(map[string]*stripe.Coupon),
+		promotionCodes: make(map[string]*stripe.PromotionCode),
+	}
+}
+
+// useFakeStripeClient swaps out the live Stripe client until the test ends
+func useFakeStripeClient(t *testing.T) *fakeStripeClient {
+	fake := newFakeStripeClient()
+	previous := stripeClient
+	stripeClient = fake
+	t.Cleanup(func() {
+		stripeClient = previous
+	})
+	return fake
+}
+
+func (f *fakeStripeClient) makeID(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s_fake%d", prefix, f.nextID)
+}
+
+// Simulation helpers
+
+func (f *fakeStripeClient) addPrice(id string, unitAmount int64, interval stripe.PriceRecurringInterval) *stripe.Price {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := &stripe.Price{
+		ID:         id,
+		Active:     true,
+		Currency:   stripe.CurrencyUSD,
+		UnitAmount: unitAmount,
+		Recurring: &stripe.PriceRecurring{
+			Interval:      interval,
+			IntervalCount: 1,
+		},
+	}
+	f.prices[id] = p
+	return p
+}
+
+// addPaymentMethod is what happens when a user completes a setup intent
+func (f *fakeStripeClient) addPaymentMethod(customerID string) (*stripe.PaymentMethod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.customers[customerID]; !ok {
+		return nil, fmt.Errorf("No such customer: %s", customerID)
+	}
+	paymentMethod := &stripe.PaymentMethod{
+		ID:       f.makeID("pm"),
+		Type:     stripe.PaymentMethodTypeCard,
+		Customer: &stripe.Customer{ID: customerID},
+		Card: &stripe.PaymentMethodCard{
+			Brand:    stripe.PaymentMethodCardBrandVisa,
+			Last4:    "4242",
+			ExpMonth: 12,
+			ExpYear:  uint64(time.Now().Year() + 2),
+		},
+	}
+	f.paymentMethods[paymentMethod.ID] = paymentMethod
+	if err := f.queueWebhookEvent("setup_intent.succeeded", &stripe.SetupIntent{
+		ID:            f.makeID("seti"),
+		Customer:      &stripe.Customer{ID: customerID},
+		PaymentMethod: &stripe.PaymentMethod{ID: paymentMethod.ID},
+		Status:        stripe.SetupIntentStatusSucceeded,
+	}); err != nil {
+		return nil, err
+	}
+	return paymentMethod, nil
+}
+
+// endCurrentPeriod moves the subscription to its next billing period,
+// which ends a trial. Subscriptions that are set to cancel at the end
+// of the period are canceled instead of being charged.
+func (f *fakeStripeClient) endCurrentPeriod(subscriptionID string, shouldPaymentSucceed bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	subscription, ok := f.subscriptions[subscriptionID]
+	if !ok {
+		return fmt.Errorf("No such subscription: %s", subscriptionID)
+	}
+	if subscription.Status == stripe.SubscriptionStatusCanceled {
+		return fmt.Errorf("Subscription %s is canceled", subscriptionID)
+	}
+	if subscription.CancelAtPeriodEnd {
+		return f.cancelSubscription(subscription)
+	}
+	periodStart := time.Unix(subscription.CurrentPeriodEnd, 0)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	if subscription.Plan.Interval == stripe.PlanIntervalYear {
+		periodEnd = periodStart.AddDate(1, 0, 0)
+	}
+	subscription.CurrentPeriodStart = periodStart.Unix()
+	subscription.CurrentPeriodEnd = periodEnd.Unix()
+	invoice := f.makeInvoice(subscription)
+	invoice.Attempted = true
+	invoice.AttemptCount = 1
+	subscription.LatestInvoice = invoice
+	// A payment can only succeed if the customer has a card
+	invoiceEventType := "invoice.payment_failed"
+	invoice.NextPaymentAttempt = time.Now().Add(fakeStripePaymentRetryInterval).Unix()
+	if shouldPaymentSucceed && len(f.getPaymentMethodsForCustomer(subscription.Customer.ID)) > 0 {
+		invoice.Status = stripe.InvoiceStatusPaid
+		invoice.AmountPaid = invoice.AmountDue
+		invoice.NextPaymentAttempt = 0
+		subscription.Status = stripe.SubscriptionStatusActive
+		invoiceEventType = "invoice.paid"
+	} else {
+		subscription.Status = stripe.SubscriptionStatusPastDue
+	}
+	if err := f.queueWebhookEvent(invoiceEventType, invoice); err != nil {
+		return err
+	}
+	return f.queueWebhookEvent("customer.subscription.updated", subscription)
+}
+
+// takeWebhookEvents returns every event queued since it was last called
+func (f *fakeStripeClient) takeWebhookEvents() []fakeStripeWebhookEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := f.webhookEvents
+	f.webhookEvents = nil
+	return out
+}
+
+func (f *fakeStripeClient) queueWebhookEvent(eventType string, object interface{}) error {
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(stripe.Event{
+		ID:      f.makeID("evt"),
+		Object:  "event",
+		Type:    eventType,
+		Created: time.Now().Unix(),
+		Data: &stripe.EventData{
+			Raw: objectJSON,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	signedAt := time.Now()
+	f.webhookEvents = append(f.webhookEvents, fakeStripeWebhookEvent{
+		Type:      eventType,
+		Payload:   payload,
+		Signature: fmt.Sprintf("t=%d,v1=%x", signedAt.Unix(), webhook.ComputeSignature(signedAt, payload, fakeStripeWebhookSecret)),
+	})
+	return nil
+}
+
+func (f *fakeStripeClient) makeInvoice(subscription *stripe.Subscription) *stripe.Invoice {
+	amountDue := subscription.Plan.Amount
+	if subscription.Discount != nil && subscription.Discount.Coupon != nil {
+		amountDue -= subscription.Discount.Coupon.AmountOff
+	}
+	return &stripe.Invoice{
+		ID:        f.makeID("in"),
+		Status:    stripe.InvoiceStatusOpen,
+		AmountDue: amountDue,
+		Currency:  subscription.Plan.Currency,
+		Customer:  &stripe.Customer{ID: subscription.Customer.ID},
+		// This can't point to the subscription itself, since
+		// the subscription points back to this invoice
+		Subscription: &stripe.Subscription{ID: subscription.ID},
+		PaymentIntent: &stripe.PaymentIntent{
+			ID:           f.makeID("pi"),
+			ClientSecret: f.makeID("pi_secret"),
+		},
+	}
+}
+
+func (f *fakeStripeClient) cancelSubscription(subscription *stripe.Subscription) error {
+	subscription.Status = stripe.SubscriptionStatusCanceled
+	subscription.CanceledAt = time.Now().Unix()
+	subscription.EndedAt = time.Now().Unix()
+	return f.queueWebhookEvent("customer.subscription.deleted", subscription)
+}
+
+func (f *fakeStripeClient) getPaymentMethodsForCustomer(customerID string) []*stripe.PaymentMethod {
+	var out []*stripe.PaymentMethod
+	for _, paymentMethod := range f.paymentMethods {
+		if paymentMethod.Customer != nil && paymentMethod.Customer.ID == customerID {
+			out = append(out, paymentMethod)
+		}
+	}
+	return out
+}
+
+// stripeAPI implementation
+
+func (f *fakeStripeClient) NewCustomer(params *stripe.CustomerParams) (*stripe.Customer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := &stripe.Customer{
+		ID:              f.makeID("cus"),
+		InvoiceSettings: &stripe.CustomerInvoiceSettings{},
+	}
+	if params.Email != nil {
+		c.Email = *params.Email
+	}
+	f.customers[c.ID] = c
+	return c, nil
+}
+
+func (f *fakeStripeClient) GetCustomer(id string) (*stripe.Customer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.customers[id]
+	if !ok {
+		return nil, fmt.Errorf("No such customer: %s", id)
+	}
+	return c, nil
+}
+
+func (f *fakeStripeClient) UpdateCustomer(id string, params *stripe.CustomerParams) (*stripe.Customer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.customers[id]
+	if !ok {
+		return nil, fmt.Errorf("No such customer: %s", id)
+	}
+	if params.Email != nil {
+		c.Email = *params.Email
+	}
+	if params.InvoiceSettings != nil && params.InvoiceSettings.DefaultPaymentMethod != nil {
+		paymentMethod, ok := f.paymentMethods[*params.InvoiceSettings.DefaultPaymentMethod]
+		if !ok {
+			return nil, fmt.Errorf("No such payment method: %s", *params.InvoiceSettings.DefaultPaymentMethod)
+		}
+		c.InvoiceSettings.DefaultPaymentMethod = paymentMethod
+	}
+	return c, nil
+}
+
+func (f *fakeStripeClient) DeleteCustomer(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.customers, id)
+	return nil
+}
+
+func (f *fakeStripeClient) ListPaymentMethods(params *stripe.PaymentMethodListParams) ([]*stripe.PaymentMethod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if params.Customer == nil {
+		return nil, fmt.Errorf("Customer is required")
+	}
+	return f.getPaymentMethodsForCustomer(*params.Customer), nil
+}
+
+func (f *fakeStripeClient) DetachPaymentMethod(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paymentMethod, ok := f.paymentMethods[id]
+	if !ok {
+		return fmt.Errorf("No such payment method: %s", id)
+	}
+	paymentMethod.Customer = nil
+	return nil
+}
+
+func (f *fakeStripeClient) NewSetupIntent(params *stripe.SetupIntentParams) (*stripe.SetupIntent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.makeID("seti")
+	return &stripe.SetupIntent{
+		ID:           id,
+		ClientSecret: fmt.Sprintf("%s_secret", id),
+		Customer:     &stripe.Customer{ID: *params.Customer},
+		Status:       stripe.SetupIntentStatusRequiresPaymentMethod,
+	}, nil
+}
+
+func (f *fakeStripeClient) NewSubscription(params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case params.Customer == nil:
+		return nil, fmt.Errorf("Customer is required")
+	case len(params.Items) != 1 || params.Items[0].Price == nil:
+		return nil, fmt.Errorf("Expected exactly one price")
+	}
+	if _, ok := f.customers[*params.Customer]; !ok {
+		return nil, fmt.Errorf("No such customer: %s", *params.Customer)
+	}
+	subscription := &stripe.Subscription{
+		ID:       f.makeID("sub"),
+		Created:  time.Now().Unix(),
+		Customer: &stripe.Customer{ID: *params.Customer},
+	}
+	if err := f.setSubscriptionPrice(subscription, *params.Items[0].Price); err != nil {
+		return nil, err
+	}
+	if params.Coupon != nil {
+		c, ok := f.coupons[*params.Coupon]
+		if !ok {
+			return nil, fmt.Errorf("No such coupon: %s", *params.Coupon)
+		}
+		subscription.Discount = &stripe.Discount{
+			Coupon: c,
+		}
+	}
+	now := time.Now()
+	subscription.CurrentPeriodStart = now.Unix()
+	switch {
+	case params.TrialEnd != nil:
+		subscription.Status = stripe.SubscriptionStatusTrialing
+		subscription.TrialEnd = *params.TrialEnd
+	case params.TrialPeriodDays != nil:
+		subscription.Status = stripe.SubscriptionStatusTrialing
+		subscription.TrialEnd = now.AddDate(0, 0, int(*params.TrialPeriodDays)).Unix()
+	default:
+		// Subscriptions are created with default_incomplete,
+		// so they wait for the first invoice to be paid
+		subscription.Status = stripe.SubscriptionStatusIncomplete
+		subscription.LatestInvoice = f.makeInvoice(subscription)
+	}
+	if subscription.Status == stripe.SubscriptionStatusTrialing {
+		subscription.CurrentPeriodEnd = subscription.TrialEnd
+	} else {
+		subscription.CurrentPeriodEnd = now.AddDate(0, 1, 0).Unix()
+		if subscription.Plan.Interval == stripe.PlanIntervalYear {
+			subscription.CurrentPeriodEnd = now.AddDate(1, 0, 0).Unix()
+		}
+	}
+	f.subscriptions[subscription.ID] = subscription
+	if err := f.queueWebhookEvent("customer.subscription.created", subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (f *fakeStripeClient) setSubscriptionPrice(subscription *stripe.Subscription, priceID string) error {
+	p, ok := f.prices[priceID]
+	if !ok {
+		return fmt.Errorf("No such price: %s", priceID)
+	}
+	subscription.Plan = &stripe.Plan{
+		ID:            p.ID,
+		Active:        p.Active,
+		Amount:        p.UnitAmount,
+		Currency:      p.Currency,
+		Interval:      stripe.PlanInterval(p.Recurring.Interval),
+		IntervalCount: p.Recurring.IntervalCount,
+	}
+	itemID := f.makeID("si")
+	if subscription.Items != nil && len(subscription.Items.Data) == 1 {
+		itemID = subscription.Items.Data[0].ID
+	}
+	subscription.Items = &stripe.SubscriptionItemList{
+		Data: []*stripe.SubscriptionItem{
+			{
+				ID:    itemID,
+				Price: p,
+				Plan:  subscription.Plan,
+			},
+		},
+	}
+	return nil
+}
+
+func (f *fakeStripeClient) GetSubscription(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	subscription, ok := f.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("No such subscription: %s", id)
+	}
+	return subscription, nil
+}
+
+func (f *fakeStripeClient) UpdateSubscription(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	subscription, ok := f.subscriptions[id]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("No such subscription: %s", id)
+	case subscription.Status == stripe.SubscriptionStatusCanceled:
+		return nil, fmt.Errorf("Subscription %s is canceled", id)
+	}
+	if params.CancelAtPeriodEnd != nil {
+		subscription.CancelAtPeriodEnd = *params.CancelAtPeriodEnd
+	}
+	for _, item := range params.Items {
+		if item.Price == nil {
+			continue
+		}
+		if item.ID == nil || *item.ID != subscription.Items.Data[0].ID {
+			return nil, fmt.Errorf("No such subscription item for subscription %s", id)
+		}
+		// Prorations are charged on the next invoice, so
+		// they don't show up until the period ends
+		if err := f.setSubscriptionPrice(subscription, *item.Price); err != nil {
+			return nil, err
+		}
+	}
+	if err := f.queueWebhookEvent("customer.subscription.updated", subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (f *fakeStripeClient) CancelSubscription(id string) (*stripe.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	subscription, ok := f.subscriptions[id]
+	if !ok {
+		return nil, fmt.Errorf("No such subscription: %s", id)
+	}
+	if err := f.cancelSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (f *fakeStripeClient) GetPrice(id string) (*stripe.Price, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.prices[id]
+	if !ok {
+		return nil, fmt.Errorf("No such price: %s", id)
+	}
+	return p, nil
+}
+
+func (f *fakeStripeClient) NewCoupon(params *stripe.CouponParams) (*stripe.Coupon, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := &stripe.Coupon{
+		ID:    f.makeID("coupon"),
+		Valid: true,
+	}
+	if params.AmountOff != nil {
+		c.AmountOff = *params.AmountOff
+	}
+	if params.PercentOff != nil {
+		c.PercentOff = *params.PercentOff
+	}
+	if params.MaxRedemptions != nil {
+		c.MaxRedemptions = *params.MaxRedemptions
+	}
+	f.coupons[c.ID] = c
+	return c, nil
+}
+
+func (f *fakeStripeClient) DeleteCoupon(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.coupons, id)
+	return nil
+}
+
+func (f *fakeStripeClient) NewPromotionCode(params *stripe.PromotionCodeParams) (*stripe.PromotionCode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.coupons[*params.Coupon]
+	if !ok {
+		return nil, fmt.Errorf("No such coupon: %s", *params.Coupon)
+	}
+	promotionCode := &stripe.PromotionCode{
+		ID:     f.makeID("promo"),
+		Code:   *params.Code,
+		Coupon: c,
+		Active: params.Active == nil || *params.Active,
+	}
+	f.promotionCodes[promotionCode.ID] = promotionCode
+	return promotionCode, nil
+}
+
+func (f *fakeStripeClient) GetPromotionCode(id string) (*stripe.PromotionCode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	promotionCode, ok := f.promotionCodes[id]
+	if !ok {
+		return nil, fmt.Errorf("No such promotion code: %s", id)
+	}
+	return promotionCode, nil
+}
+
+func (f *fakeStripeClient) UpdatePromotionCode(id string, params *stripe.PromotionCodeParams) (*stripe.PromotionCode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	promotionCode, ok := f.promotionCodes[id]
+	if !ok {
+		return nil, fmt.Errorf("No such promotion code: %s", id)
+	}
+	if params.Active != nil {
+		promotionCode.Active = *params.Active
+	}
+	return promotionCode, nil
+}
+
+func (f *fakeStripeClient) ListPromotionCodes(params *stripe.PromotionCodeListParams) ([]*stripe.PromotionCode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*stripe.PromotionCode
+	for _, promotionCode := range f.promotionCodes {
+		out = append(out, promotionCode)
+	}
+	return out, nil
+}
+
+func (f *fakeStripeClient) ConstructWebhookEvent(payload []byte, signature string) (*stripe.Event, error) {
+	event, err := webhook.ConstructEvent(payload, signature, fakeStripeWebhookSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
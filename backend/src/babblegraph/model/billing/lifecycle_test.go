@@ -0,0 +1,266 @@
+package billing
+
+import (
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/users"
+	"babblegraph/util/ctx"
+	"babblegraph/util/env"
+	"babblegraph/util/postgres"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stripe/stripe-go/v72"
+)
+
+// beginIntegrationTestTx returns a transaction against the database in the
+// PG_* environment variables, which must already have every migration
+// applied. scripts/test-backend starts one. Everything written in the
+// transaction is rolled back.
+func beginIntegrationTestTx(t *testing.T) *sqlx.Tx {
+	if os.Getenv("PG_HOST") == "" {
+		// CI always has a test database, so these tests
+		// should never be skipped there without anyone noticing
+		if os.Getenv("CI") != "" {
+			t.Fatalf("PG_HOST is not set, but the billing integration tests must run in CI")
+		}
+		t.Skip("PG_HOST is not set, skipping billing integration test")
+	}
+	if os.Getenv("ENV") == "" {
+		os.Setenv("ENV", env.EnvironmentTest.Str())
+		t.Cleanup(func() {
+			os.Unsetenv("ENV")
+		})
+	}
+	config := postgres.MustPostgresConfigForEnvironment()
+	db, err := sqlx.Connect("postgres", config.MakeConnectionString())
+	if err != nil {
+		t.Fatalf("Error connecting to database: %s", err.Error())
+	}
+	tx := db.MustBegin()
+	t.Cleanup(func() {
+		tx.Rollback()
+		db.Close()
+	})
+	return tx
+}
+
+func TestPremiumNewsletterSubscriptionLifecycle(t *testing.T) {
+	tx := beginIntegrationTestTx(t)
+	fake := useFakeStripeClient(t)
+	c := ctx.GetDefaultLogContext()
+
+	user := insertVerifiedUserForTest(t, tx, "billing-lifecycle")
+	stripePrice := fake.addPrice("price_lifecycle_annual", 2900, stripe.PriceRecurringIntervalYear)
+	if _, err := SetStripePriceForPlanType(c, tx, PlanTypeAnnual, stripePrice.ID); err != nil {
+		t.Fatalf("Error setting price for plan: %s", err.Error())
+	}
+	billingInformation, err := GetOrCreateBillingInformationForUser(c, tx, user.ID)
+	if err != nil {
+		t.Fatalf("Error creating billing information: %s", err.Error())
+	}
+
+	// Trial
+	subscriptionID := NewPremiumNewsletterSubscriptionID()
+	if err := InsertPremiumNewsletterSyncRequest(tx, subscriptionID, PremiumNewsletterSubscriptionUpdateTypeTransitionToActive); err != nil {
+		t.Fatalf("Error inserting sync request: %s", err.Error())
+	}
+	if _, err := CreatePremiumNewsletterSubscriptionForUserWithID(c, tx, user.ID, subscriptionID, PlanTypeAnnual); err != nil {
+		t.Fatalf("Error creating subscription: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription := syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	expectSubscriptionState(t, tx, "trial", user.ID, premiumNewsletterSubscription, PaymentStateTrialNoPaymentMethod, true)
+	if premiumNewsletterSubscription.Plan == nil || premiumNewsletterSubscription.Plan.Type != PlanTypeAnnual {
+		t.Errorf("Expected subscription to be on the annual plan, but got %+v", premiumNewsletterSubscription.Plan)
+	}
+
+	// Payment method added
+	if _, err := fake.addPaymentMethod(*billingInformation.StripeCustomerID); err != nil {
+		t.Fatalf("Error adding payment method: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription = syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	expectSubscriptionState(t, tx, "payment method added", user.ID, premiumNewsletterSubscription, PaymentStateTrialPaymentMethodAdded, true)
+	paymentMethods, err := GetPaymentMethodsForUser(tx, user.ID)
+	switch {
+	case err != nil:
+		t.Fatalf("Error getting payment methods: %s", err.Error())
+	case len(paymentMethods) != 1 || !paymentMethods[0].IsDefault:
+		t.Errorf("Expected the sync to make the only payment method the default, but got %+v", paymentMethods)
+	}
+
+	// Active
+	if err := fake.endCurrentPeriod(*fakeStripeSubscriptionIDForTest(t, tx, subscriptionID), true); err != nil {
+		t.Fatalf("Error ending trial: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription = syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	expectSubscriptionState(t, tx, "active", user.ID, premiumNewsletterSubscription, PaymentStateActive, true)
+	if premiumNewsletterSubscription.PriceCents == nil || *premiumNewsletterSubscription.PriceCents != 2900 {
+		t.Errorf("Expected subscription to cost 2900 cents, but got %v", premiumNewsletterSubscription.PriceCents)
+	}
+
+	// Payment failure
+	if err := fake.endCurrentPeriod(*fakeStripeSubscriptionIDForTest(t, tx, subscriptionID), false); err != nil {
+		t.Fatalf("Error renewing subscription: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription = syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	expectSubscriptionState(t, tx, "payment failure", user.ID, premiumNewsletterSubscription, PaymentStatePaymentPending, true)
+	dunningCycle, err := LookupOpenDunningCycleForPremiumNewsletterSubscription(tx, subscriptionID)
+	switch {
+	case err != nil:
+		t.Fatalf("Error looking up dunning cycle: %s", err.Error())
+	case dunningCycle == nil:
+		t.Fatalf("Expected a dunning cycle to start after the payment failure")
+	case dunningCycle.AmountDueCents != 2900 || dunningCycle.PaymentAttemptCount != 1 || dunningCycle.NextPaymentAttemptAt == nil:
+		t.Errorf("Expected dunning cycle for one failed attempt at 2900 cents with a retry scheduled, but got %+v", dunningCycle)
+	}
+
+	// Cancellation
+	if err := UpdateSubscriptionAutoRenewForUser(tx, user.ID, false); err != nil {
+		t.Fatalf("Error turning off auto renew: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription = syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	expectSubscriptionState(t, tx, "cancellation", user.ID, premiumNewsletterSubscription, PaymentStatePaymentPending, true)
+	if premiumNewsletterSubscription.IsAutoRenewEnabled {
+		t.Errorf("Expected auto renew to be disabled after cancellation")
+	}
+
+	// Termination
+	if err := fake.endCurrentPeriod(*fakeStripeSubscriptionIDForTest(t, tx, subscriptionID), true); err != nil {
+		t.Fatalf("Error ending subscription: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription = syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	expectSubscriptionState(t, tx, "termination", user.ID, premiumNewsletterSubscription, PaymentStateTerminated, false)
+	activeSubscription, err := LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
+	switch {
+	case err != nil:
+		t.Fatalf("Error looking up subscription: %s", err.Error())
+	case activeSubscription != nil:
+		t.Errorf("Expected no active subscription after termination, but got %+v", activeSubscription)
+	}
+}
+
+func TestTrialWithoutPaymentMethodDoesNotStartDunning(t *testing.T) {
+	tx := beginIntegrationTestTx(t)
+	fake := useFakeStripeClient(t)
+	c := ctx.GetDefaultLogContext()
+
+	user := insertVerifiedUserForTest(t, tx, "billing-trial-no-card")
+	stripePrice := fake.addPrice("price_trial_no_card_annual", 2900, stripe.PriceRecurringIntervalYear)
+	if _, err := SetStripePriceForPlanType(c, tx, PlanTypeAnnual, stripePrice.ID); err != nil {
+		t.Fatalf("Error setting price for plan: %s", err.Error())
+	}
+	subscriptionID := NewPremiumNewsletterSubscriptionID()
+	if err := InsertPremiumNewsletterSyncRequest(tx, subscriptionID, PremiumNewsletterSubscriptionUpdateTypeTransitionToActive); err != nil {
+		t.Fatalf("Error inserting sync request: %s", err.Error())
+	}
+	if _, err := CreatePremiumNewsletterSubscriptionForUserWithID(c, tx, user.ID, subscriptionID, PlanTypeAnnual); err != nil {
+		t.Fatalf("Error creating subscription: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+
+	// The trial ends, and the first invoice fails because there's no card
+	if err := fake.endCurrentPeriod(*fakeStripeSubscriptionIDForTest(t, tx, subscriptionID), true); err != nil {
+		t.Fatalf("Error ending trial: %s", err.Error())
+	}
+	deliverFakeStripeWebhookEvents(t, c, tx, fake)
+	premiumNewsletterSubscription := syncPremiumNewsletterSubscription(t, c, tx, subscriptionID)
+	if premiumNewsletterSubscription.PaymentState != PaymentStatePaymentPending {
+		t.Errorf("Expected payment state %d, but got %d", PaymentStatePaymentPending, premiumNewsletterSubscription.PaymentState)
+	}
+	dunningCycle, err := LookupOpenDunningCycleForPremiumNewsletterSubscription(tx, subscriptionID)
+	switch {
+	case err != nil:
+		t.Fatalf("Error looking up dunning cycle: %s", err.Error())
+	case dunningCycle != nil:
+		t.Errorf("Expected no dunning cycle for a trial that ended without a payment method, but got %+v", dunningCycle)
+	}
+}
+
+func insertVerifiedUserForTest(t *testing.T, tx *sqlx.Tx, emailPrefix string) *users.User {
+	emailAddress := fmt.Sprintf("%s-%d@babblegraph.com", emailPrefix, time.Now().UnixNano())
+	if err := users.InsertNewUnverifiedUser(tx, emailAddress); err != nil {
+		t.Fatalf("Error inserting user: %s", err.Error())
+	}
+	user, err := users.LookupUserByEmailAddress(tx, emailAddress)
+	if err != nil {
+		t.Fatalf("Error looking up user: %s", err.Error())
+	}
+	if err := users.SetUserStatusToVerified(tx, user.ID); err != nil {
+		t.Fatalf("Error verifying user: %s", err.Error())
+	}
+	return user
+}
+
+// deliverFakeStripeWebhookEvents sends every queued event
+// through the same handler as the Stripe webhook route
+func deliverFakeStripeWebhookEvents(t *testing.T, c ctx.LogContext, tx *sqlx.Tx, fake *fakeStripeClient) {
+	for _, e := range fake.takeWebhookEvents() {
+		if err := HandleStripeEvent(c, tx, e.Signature, e.Payload); err != nil {
+			t.Fatalf("Error handling %s event: %s", e.Type, err.Error())
+		}
+	}
+}
+
+// syncPremiumNewsletterSubscription does what the billing sync
+// job does for a remote update to a single subscription
+func syncPremiumNewsletterSubscription(t *testing.T, c ctx.LogContext, tx *sqlx.Tx, id PremiumNewsletterSubscriptionID) *PremiumNewsletterSubscription {
+	syncRequests, err := GetPremiumNewsletterSyncRequests(tx)
+	if err != nil {
+		t.Fatalf("Error getting sync requests: %s", err.Error())
+	}
+	if _, ok := syncRequests[id]; !ok {
+		t.Fatalf("Expected a sync request for subscription %s", id)
+	}
+	premiumNewsletterSubscription, err := GetPremiumNewsletterSubscriptionByID(c, tx, id)
+	if err != nil {
+		t.Fatalf("Error getting subscription: %s", err.Error())
+	}
+	userID, err := premiumNewsletterSubscription.GetUserID()
+	if err != nil {
+		t.Fatalf("Error getting user for subscription: %s", err.Error())
+	}
+	if err := SyncUserAccountWithPremiumNewsletterSubscription(tx, *userID, premiumNewsletterSubscription); err != nil {
+		t.Fatalf("Error syncing user account: %s", err.Error())
+	}
+	if err := MarkPremiumNewsletterSyncRequestDone(tx, id); err != nil {
+		t.Fatalf("Error marking sync request done: %s", err.Error())
+	}
+	return premiumNewsletterSubscription
+}
+
+func expectSubscriptionState(t *testing.T, tx *sqlx.Tx, step string, userID users.UserID, premiumNewsletterSubscription *PremiumNewsletterSubscription, expectedPaymentState PaymentState, expectPremium bool) {
+	if premiumNewsletterSubscription.PaymentState != expectedPaymentState {
+		t.Errorf("Error on step %s: expected payment state %d, but got %d", step, expectedPaymentState, premiumNewsletterSubscription.PaymentState)
+	}
+	subscriptionLevel, err := useraccounts.LookupSubscriptionLevelForUser(tx, userID)
+	switch {
+	case err != nil:
+		t.Fatalf("Error on step %s: %s", step, err.Error())
+	case expectPremium && (subscriptionLevel == nil || *subscriptionLevel != useraccounts.SubscriptionLevelPremium):
+		t.Errorf("Error on step %s: expected user to have premium, but got %v", step, subscriptionLevel)
+	case !expectPremium && subscriptionLevel != nil:
+		t.Errorf("Error on step %s: expected user to have no subscription, but got %s", step, *subscriptionLevel)
+	}
+}
+
+func fakeStripeSubscriptionIDForTest(t *testing.T, tx *sqlx.Tx, id PremiumNewsletterSubscriptionID) *string {
+	var matches []dbPremiumNewsletterSubscription
+	if err := tx.Select(&matches, lookupPremiumNewsletterSubscriptionByIDQuery, id); err != nil || len(matches) != 1 {
+		t.Fatalf("Error looking up subscription %s", id)
+	}
+	externalID, err := getExternalIDMapping(tx, matches[0].ExternalIDMappingID)
+	if err != nil {
+		t.Fatalf("Error looking up Stripe ID for subscription %s: %s", id, err.Error())
+	}
+	return &externalID.ExternalID
+}
@@ -3,6 +3,7 @@ package billing
 import (
 	"babblegraph/model/users"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -50,6 +51,9 @@ type PremiumNewsletterSubscription struct {
 
 	PriceCents       *int64 `json:"price_cents,omitempty"`
 	HasValidDiscount bool   `json:"has_valid_discount"`
+
+	// This is nil for subscriptions on a price that isn't in the plans catalog
+	Plan *Plan `json:"plan,omitempty"`
 }
 
 func (p *PremiumNewsletterSubscription) GetUserID() (*users.UserID, error) {
@@ -130,6 +134,10 @@ const (
 
 	PremiumNewsletterSubscriptionUpdateTypePaymentMethodAdded PremiumNewsletterSubscriptionUpdateType = "payment-method-added"
 
+	// This update type is for when a user switches plans, since the prorated
+	// invoice may not have been paid by the time the switch is made
+	PremiumNewsletterSubscriptionUpdateTypePlanChanged PremiumNewsletterSubscriptionUpdateType = "plan-changed"
+
 	// This update type is for when we receive an update pushed from
 	// the remote payment processor
 	PremiumNewsletterSubscriptionUpdateTypeRemoteUpdated PremiumNewsletterSubscriptionUpdateType = "remote-updated"
@@ -248,3 +256,204 @@ func GetPromotionTypeForString(s string) (*PromotionType, error) {
 		return nil, fmt.Errorf("Unrecognized promotion type %s", s)
 	}
 }
+
+type PlanID string
+
+type PlanType string
+
+const (
+	PlanTypeMonthly PlanType = "monthly"
+	PlanTypeAnnual  PlanType = "annual"
+	PlanTypeStudent PlanType = "student"
+
+	// Subscriptions that don't ask for a plan get this one
+	DefaultPlanType = PlanTypeAnnual
+)
+
+func (p PlanType) Str() string {
+	return string(p)
+}
+
+func (p PlanType) Ptr() *PlanType {
+	return &p
+}
+
+func GetPlanTypeForString(s string) (*PlanType, error) {
+	switch s {
+	case PlanTypeMonthly.Str():
+		return PlanTypeMonthly.Ptr(), nil
+	case PlanTypeAnnual.Str():
+		return PlanTypeAnnual.Ptr(), nil
+	case PlanTypeStudent.Str():
+		return PlanTypeStudent.Ptr(), nil
+	default:
+		return nil, fmt.Errorf("Unrecognized plan type %s", s)
+	}
+}
+
+type BillingInterval string
+
+const (
+	BillingIntervalMonth BillingInterval = "month"
+	BillingIntervalYear  BillingInterval = "year"
+)
+
+func (b BillingInterval) Str() string {
+	return string(b)
+}
+
+type dbPlan struct {
+	CreatedAt           time.Time           `db:"created_at"`
+	LastModifiedAt      time.Time           `db:"last_modified_at"`
+	ID                  PlanID              `db:"_id"`
+	PlanType            PlanType            `db:"plan_type"`
+	ExternalIDMappingID externalIDMappingID `db:"external_id_mapping_id"`
+	PriceCents          int64               `db:"price_cents"`
+	Currency            string              `db:"currency"`
+	BillingInterval     BillingInterval     `db:"billing_interval"`
+	IsActive            bool                `db:"is_active"`
+}
+
+func (d dbPlan) ToNonDB() Plan {
+	return Plan{
+		ID:              d.ID,
+		Type:            d.PlanType,
+		PriceCents:      d.PriceCents,
+		Currency:        d.Currency,
+		BillingInterval: d.BillingInterval,
+		IsActive:        d.IsActive,
+	}
+}
+
+type Plan struct {
+	ID              PlanID          `json:"id"`
+	Type            PlanType        `json:"plan_type"`
+	PriceCents      int64           `json:"price_cents"`
+	Currency        string          `json:"currency"`
+	BillingInterval BillingInterval `json:"billing_interval"`
+	IsActive        bool            `json:"is_active"`
+}
+
+func FormatPriceCents(priceCents int64, currency string) string {
+	amount := fmt.Sprintf("%d.%02d", priceCents/100, priceCents%100)
+	switch strings.ToLower(currency) {
+	case "usd":
+		return fmt.Sprintf("US$%s", amount)
+	case "eur":
+		return fmt.Sprintf("€%s", amount)
+	case "gbp":
+		return fmt.Sprintf("£%s", amount)
+	default:
+		return fmt.Sprintf("%s %s", amount, strings.ToUpper(currency))
+	}
+}
+
+type DunningCycleID string
+
+type dbDunningCycle struct {
+	CreatedAt                       time.Time                       `db:"created_at"`
+	LastModifiedAt                  time.Time                       `db:"last_modified_at"`
+	ID                              DunningCycleID                  `db:"_id"`
+	PremiumNewsletterSubscriptionID PremiumNewsletterSubscriptionID `db:"premium_newsletter_subscription_id"`
+	InvoiceExternalIDMappingID      externalIDMappingID             `db:"invoice_external_id_mapping_id"`
+	AmountDueCents                  int64                           `db:"amount_due_cents"`
+	Currency                        string                          `db:"currency"`
+	PaymentAttemptCount             int64                           `db:"payment_attempt_count"`
+	NextPaymentAttemptAt            *time.Time                      `db:"next_payment_attempt_at"`
+	GracePeriodEndsAt               time.Time                       `db:"grace_period_ends_at"`
+	LastReminderType                *DunningReminderType            `db:"last_reminder_type"`
+	ResolvedAt                      *time.Time                      `db:"resolved_at"`
+	Resolution                      *DunningResolution              `db:"resolution"`
+}
+
+func (d dbDunningCycle) ToNonDB() DunningCycle {
+	return DunningCycle{
+		ID:                              d.ID,
+		StartedAt:                       d.CreatedAt,
+		PremiumNewsletterSubscriptionID: d.PremiumNewsletterSubscriptionID,
+		AmountDueCents:                  d.AmountDueCents,
+		Currency:                        d.Currency,
+		PaymentAttemptCount:             d.PaymentAttemptCount,
+		NextPaymentAttemptAt:            d.NextPaymentAttemptAt,
+		GracePeriodEndsAt:               d.GracePeriodEndsAt,
+		LastReminderType:                d.LastReminderType,
+		ResolvedAt:                      d.ResolvedAt,
+		Resolution:                      d.Resolution,
+	}
+}
+
+// DunningCycle tracks a subscription from its first failed payment
+// until the payment is recovered or the user is downgraded
+type DunningCycle struct {
+	ID                              DunningCycleID                  `json:"id"`
+	StartedAt                       time.Time                       `json:"started_at"`
+	PremiumNewsletterSubscriptionID PremiumNewsletterSubscriptionID `json:"premium_newsletter_subscription_id"`
+	AmountDueCents                  int64                           `json:"amount_due_cents"`
+	Currency                        string                          `json:"currency"`
+	PaymentAttemptCount             int64                           `json:"payment_attempt_count"`
+	// This is nil once Stripe has stopped retrying the payment
+	NextPaymentAttemptAt *time.Time           `json:"next_payment_attempt_at,omitempty"`
+	GracePeriodEndsAt    time.Time            `json:"grace_period_ends_at"`
+	LastReminderType     *DunningReminderType `json:"last_reminder_type,omitempty"`
+	ResolvedAt           *time.Time           `json:"resolved_at,omitempty"`
+	Resolution           *DunningResolution   `json:"resolution,omitempty"`
+}
+
+type DunningReminderType string
+
+const (
+	DunningReminderTypePaymentFailed DunningReminderType = "payment-failed"
+	DunningReminderTypeStillFailing  DunningReminderType = "still-failing"
+	DunningReminderTypeFinalNotice   DunningReminderType = "final-notice"
+)
+
+func (d DunningReminderType) Ptr() *DunningReminderType {
+	return &d
+}
+
+func (d DunningReminderType) Str() string {
+	return string(d)
+}
+
+// Reminders only ever escalate, so a user never gets
+// a milder reminder after a more urgent one
+func (d DunningReminderType) getEscalationLevel() int {
+	switch d {
+	case DunningReminderTypePaymentFailed:
+		return 1
+	case DunningReminderTypeStillFailing:
+		return 2
+	case DunningReminderTypeFinalNotice:
+		return 3
+	default:
+		return 0
+	}
+}
+
+type DunningResolution string
+
+const (
+	DunningResolutionRecovered  DunningResolution = "recovered"
+	DunningResolutionDowngraded DunningResolution = "downgraded"
+	DunningResolutionCanceled   DunningResolution = "canceled"
+)
+
+func (d DunningResolution) Ptr() *DunningResolution {
+	return &d
+}
+
+func (d DunningResolution) Str() string {
+	return string(d)
+}
+
+type DunningMetrics struct {
+	StartedCount    int64 `json:"started_count"`
+	RecoveredCount  int64 `json:"recovered_count"`
+	DowngradedCount int64 `json:"downgraded_count"`
+	CanceledCount   int64 `json:"canceled_count"`
+	OpenCount       int64 `json:"open_count"`
+	// These are keyed by currency, since invoices
+	// aren't all in the same currency
+	RecoveredAmountCents map[string]int64 `json:"recovered_amount_cents"`
+	LostAmountCents      map[string]int64 `json:"lost_amount_cents"`
+}
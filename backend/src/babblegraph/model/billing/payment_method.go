@@ -2,14 +2,11 @@ package billing
 
 import (
 	"babblegraph/model/users"
-	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/customer"
-	"github.com/stripe/stripe-go/v72/paymentmethod"
 )
 
 func GetPaymentMethodsForUser(tx *sqlx.Tx, userID users.UserID) ([]PaymentMethod, error) {
@@ -76,7 +73,7 @@ func DeletePaymentMethodForUser(tx *sqlx.Tx, userID users.UserID, externalPaymen
 }
 
 func getStripeDefaultPaymentMethodID(stripeCustomerID string) (*string, error) {
-	customer, err := customer.Get(stripeCustomerID, &stripe.CustomerParams{})
+	customer, err := stripeClient.GetCustomer(stripeCustomerID)
 	switch {
 	case err != nil:
 		return nil, err
@@ -90,17 +87,19 @@ func getStripeDefaultPaymentMethodID(stripeCustomerID string) (*string, error) {
 }
 
 func getStripePaymentMethodsForUser(stripeCustomerID string) ([]PaymentMethod, error) {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-	stripePaymentMethods := paymentmethod.List(&stripe.PaymentMethodListParams{
+	stripePaymentMethods, err := stripeClient.ListPaymentMethods(&stripe.PaymentMethodListParams{
 		Customer: ptr.String(stripeCustomerID),
 		Type:     ptr.String("card"),
 	})
+	if err != nil {
+		return nil, err
+	}
 	defaultPaymentMethodID, err := getStripeDefaultPaymentMethodID(stripeCustomerID)
 	if err != nil {
 		return nil, err
 	}
 	var out []PaymentMethod
-	for _, paymentMethod := range stripePaymentMethods.PaymentMethodList().Data {
+	for _, paymentMethod := range stripePaymentMethods {
 		convertedPaymentMethod, err := convertStripePaymentMethod(defaultPaymentMethodID, paymentMethod)
 		if err != nil {
 			return nil, err
@@ -111,8 +110,7 @@ func getStripePaymentMethodsForUser(stripeCustomerID string) ([]PaymentMethod, e
 }
 
 func markPaymentMethodAsDefaultForUser(stripeCustomerID, paymentMethodID string) error {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-	if _, err := customer.Update(stripeCustomerID, &stripe.CustomerParams{
+	if _, err := stripeClient.UpdateCustomer(stripeCustomerID, &stripe.CustomerParams{
 		InvoiceSettings: &stripe.CustomerInvoiceSettingsParams{
 			DefaultPaymentMethod: ptr.String(paymentMethodID),
 		},
@@ -123,8 +121,7 @@ func markPaymentMethodAsDefaultForUser(stripeCustomerID, paymentMethodID string)
 }
 
 func deletePaymentMethod(stripeCustomerID, paymentMethodID string) error {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-	if _, err := paymentmethod.Detach(paymentMethodID, nil); err != nil {
+	if err := stripeClient.DetachPaymentMethod(paymentMethodID); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,327 @@
+package billing
+
+import (
+	"babblegraph/model/users"
+	"babblegraph/util/ctx"
+	"babblegraph/util/ptr"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stripe/stripe-go/v72"
+)
+
+const (
+	getAllPlansQuery               = "SELECT * FROM billing_plans ORDER BY created_at DESC"
+	getActivePlansQuery            = "SELECT * FROM billing_plans WHERE is_active = TRUE"
+	lookupActivePlanForTypeQuery   = "SELECT * FROM billing_plans WHERE plan_type = $1 AND is_active = TRUE"
+	lookupPlanByExternalIDQuery    = "SELECT * FROM billing_plans WHERE external_id_mapping_id = $1"
+	deactivatePlansForTypeQuery    = "UPDATE billing_plans SET is_active = FALSE, last_modified_at = timezone('utc', now()) WHERE plan_type = $1 AND is_active = TRUE"
+	activatePlanQuery              = "UPDATE billing_plans SET is_active = TRUE, last_modified_at = timezone('utc', now()) WHERE _id = $1"
+	insertPlanQuery                = "INSERT INTO billing_plans (plan_type, external_id_mapping_id, price_cents, currency, billing_interval) VALUES ($1, $2, $3, $4, $5)"
+	updatePlanFromStripePriceQuery = "UPDATE billing_plans SET price_cents = $2, currency = $3, billing_interval = $4, is_active = (is_active AND $5), last_modified_at = timezone('utc', now()) WHERE _id = $1"
+
+	studentPlanEmailTopLevelDomain = "edu"
+
+	legacyPlanCacheLifetime = 1 * time.Hour
+)
+
+// Stripe prices can't be edited, so the legacy plan is cached instead of
+// being fetched for every email. Only whether the price is archived can change,
+// and that is fine to be an hour out of date.
+var legacyPlanCache struct {
+	mu        sync.Mutex
+	plan      *Plan
+	fetchedAt time.Time
+}
+
+func GetActivePlans(tx *sqlx.Tx) ([]Plan, error) {
+	var matches []dbPlan
+	if err := tx.Select(&matches, getActivePlansQuery); err != nil {
+		return nil, err
+	}
+	var out []Plan
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+func GetAllPlans(tx *sqlx.Tx) ([]Plan, error) {
+	var matches []dbPlan
+	if err := tx.Select(&matches, getAllPlansQuery); err != nil {
+		return nil, err
+	}
+	var out []Plan
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+// GetAvailablePlansForUser filters out plans that the user can't subscribe to
+func GetAvailablePlansForUser(tx *sqlx.Tx, userID users.UserID) ([]Plan, error) {
+	plans, err := GetActivePlans(tx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := users.GetUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var out []Plan
+	for _, p := range plans {
+		if isEmailAddressEligibleForPlanType(user.EmailAddress, p.Type) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// SetStripePriceForPlanType makes the Stripe price the active price for the
+// plan type. Stripe prices can't be edited, so changing the price of a plan
+// means creating a new price in Stripe and then calling this.
+func SetStripePriceForPlanType(c ctx.LogContext, tx *sqlx.Tx, planType PlanType, stripePriceID string) (*Plan, error) {
+	stripePrice, err := stripeClient.GetPrice(stripePriceID)
+	if err != nil {
+		return nil, err
+	}
+	priceCents, currency, billingInterval, err := getPlanFieldsForStripePrice(stripePrice)
+	if err != nil {
+		return nil, err
+	}
+	if !stripePrice.Active {
+		return nil, fmt.Errorf("Stripe price %s is not active", stripePriceID)
+	}
+	if expectedBillingInterval := getBillingIntervalForPlanType(planType); *billingInterval != expectedBillingInterval {
+		return nil, fmt.Errorf("Plan type %s must be billed every %s, but Stripe price %s is billed every %s", planType, expectedBillingInterval, stripePriceID, *billingInterval)
+	}
+	if _, err := tx.Exec(deactivatePlansForTypeQuery, planType); err != nil {
+		return nil, err
+	}
+	existingPlan, err := lookupPlanByStripePriceID(tx, stripePriceID)
+	switch {
+	case err != nil:
+		return nil, err
+	case existingPlan != nil:
+		if existingPlan.Type != planType {
+			return nil, fmt.Errorf("Stripe price %s is already used for plan type %s", stripePriceID, existingPlan.Type)
+		}
+		c.Infof("Reactivating plan %s for Stripe price %s", existingPlan.ID, stripePriceID)
+		if _, err := tx.Exec(activatePlanQuery, existingPlan.ID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(updatePlanFromStripePriceQuery, existingPlan.ID, *priceCents, *currency, *billingInterval, true); err != nil {
+			return nil, err
+		}
+	default:
+		externalIDMappingID, err := insertExternalIDMapping(tx, stripePriceID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(insertPlanQuery, planType, *externalIDMappingID, *priceCents, *currency, *billingInterval); err != nil {
+			return nil, err
+		}
+	}
+	return lookupActivePlanForType(tx, planType)
+}
+
+// SyncPlansWithStripe copies prices from Stripe into the catalog, and
+// deactivates any plan whose price has been archived in Stripe
+func SyncPlansWithStripe(c ctx.LogContext, tx *sqlx.Tx) error {
+	var matches []dbPlan
+	if err := tx.Select(&matches, getAllPlansQuery); err != nil {
+		return err
+	}
+	for _, m := range matches {
+		externalID, err := getExternalIDMapping(tx, m.ExternalIDMappingID)
+		if err != nil {
+			return err
+		}
+		if externalID.IDType != externalIDTypeStripe {
+			return fmt.Errorf("Unrecognized external ID type %s for plan %s", externalID.IDType, m.ID)
+		}
+		stripePrice, err := stripeClient.GetPrice(externalID.ExternalID)
+		if err != nil {
+			return err
+		}
+		priceCents, currency, billingInterval, err := getPlanFieldsForStripePrice(stripePrice)
+		if err != nil {
+			return err
+		}
+		if m.IsActive && !stripePrice.Active {
+			c.Warnf("Stripe price %s for plan %s was archived, deactivating plan", externalID.ExternalID, m.ID)
+		}
+		if _, err := tx.Exec(updatePlanFromStripePriceQuery, m.ID, *priceCents, *currency, *billingInterval, stripePrice.Active); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getStripePriceIDForPlanType falls back to the price that was used
+// before there was a catalog for annual plans, so that checkout
+// keeps working in environments where no plans have been added
+func getStripePriceIDForPlanType(tx *sqlx.Tx, planType PlanType) (*string, error) {
+	var matches []dbPlan
+	err := tx.Select(&matches, lookupActivePlanForTypeQuery, planType)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one active plan for type %s, but got %d", planType, len(matches))
+	case len(matches) == 1:
+		externalID, err := getExternalIDMapping(tx, matches[0].ExternalIDMappingID)
+		if err != nil {
+			return nil, err
+		}
+		return ptr.String(externalID.ExternalID), nil
+	case planType == PlanTypeAnnual:
+		return getStripeProductIDForEnvironment()
+	default:
+		return nil, fmt.Errorf("No active plan for type %s", planType)
+	}
+}
+
+// GetPlanForPremiumNewsletterSubscription looks up the price that was used before
+// there was a catalog in Stripe for subscriptions that aren't on a plan. The returned
+// plan has no ID in that case, since it isn't in the catalog.
+func GetPlanForPremiumNewsletterSubscription(premiumNewsletterSubscription PremiumNewsletterSubscription) (*Plan, error) {
+	if premiumNewsletterSubscription.Plan != nil {
+		return premiumNewsletterSubscription.Plan, nil
+	}
+	legacyPlanCache.mu.Lock()
+	defer legacyPlanCache.mu.Unlock()
+	if legacyPlanCache.plan != nil && time.Since(legacyPlanCache.fetchedAt) < legacyPlanCacheLifetime {
+		plan := *legacyPlanCache.plan
+		return &plan, nil
+	}
+	stripePriceID, err := getStripeProductIDForEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	stripePrice, err := stripeClient.GetPrice(*stripePriceID)
+	if err != nil {
+		return nil, err
+	}
+	priceCents, currency, billingInterval, err := getPlanFieldsForStripePrice(stripePrice)
+	if err != nil {
+		return nil, err
+	}
+	legacyPlanCache.plan = &Plan{
+		Type:            PlanTypeAnnual,
+		PriceCents:      *priceCents,
+		Currency:        *currency,
+		BillingInterval: *billingInterval,
+		IsActive:        stripePrice.Active,
+	}
+	legacyPlanCache.fetchedAt = time.Now()
+	plan := *legacyPlanCache.plan
+	return &plan, nil
+}
+
+func lookupActivePlanForType(tx *sqlx.Tx, planType PlanType) (*Plan, error) {
+	var matches []dbPlan
+	err := tx.Select(&matches, lookupActivePlanForTypeQuery, planType)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one active plan for type %s, but got %d", planType, len(matches))
+	default:
+		plan := matches[0].ToNonDB()
+		return &plan, nil
+	}
+}
+
+func lookupPlanByStripePriceID(tx *sqlx.Tx, stripePriceID string) (*Plan, error) {
+	externalIDMapping, err := lookupExternalIDMappingByExternalID(tx, externalIDTypeStripe, stripePriceID)
+	switch {
+	case err != nil:
+		return nil, err
+	case externalIDMapping == nil:
+		return nil, nil
+	}
+	var matches []dbPlan
+	err = tx.Select(&matches, lookupPlanByExternalIDQuery, externalIDMapping.ID)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one plan for Stripe price %s, but got %d", stripePriceID, len(matches))
+	default:
+		plan := matches[0].ToNonDB()
+		return &plan, nil
+	}
+}
+
+func getPlanFieldsForStripePrice(stripePrice *stripe.Price) (_priceCents *int64, _currency *string, _billingInterval *BillingInterval, _err error) {
+	if stripePrice.Recurring == nil {
+		return nil, nil, nil, fmt.Errorf("Stripe price %s is not recurring", stripePrice.ID)
+	}
+	if stripePrice.Recurring.IntervalCount != 1 {
+		return nil, nil, nil, fmt.Errorf("Stripe price %s has unsupported interval count %d", stripePrice.ID, stripePrice.Recurring.IntervalCount)
+	}
+	var billingInterval BillingInterval
+	switch stripePrice.Recurring.Interval {
+	case stripe.PriceRecurringIntervalMonth:
+		billingInterval = BillingIntervalMonth
+	case stripe.PriceRecurringIntervalYear:
+		billingInterval = BillingIntervalYear
+	default:
+		return nil, nil, nil, fmt.Errorf("Stripe price %s has unsupported interval %s", stripePrice.ID, stripePrice.Recurring.Interval)
+	}
+	return ptr.Int64(stripePrice.UnitAmount), ptr.String(string(stripePrice.Currency)), &billingInterval, nil
+}
+
+func getBillingIntervalForPlanType(planType PlanType) BillingInterval {
+	switch planType {
+	case PlanTypeMonthly:
+		return BillingIntervalMonth
+	case PlanTypeAnnual,
+		PlanTypeStudent:
+		return BillingIntervalYear
+	default:
+		panic(fmt.Sprintf("unrecognized plan type %s", planType))
+	}
+}
+
+func isEmailAddressEligibleForPlanType(emailAddress string, planType PlanType) bool {
+	switch planType {
+	case PlanTypeMonthly,
+		PlanTypeAnnual:
+		return true
+	case PlanTypeStudent:
+		return isEmailAddressEligibleForStudentPlan(emailAddress)
+	default:
+		return false
+	}
+}
+
+// Student plans are only offered to email addresses on a .edu domain. This
+// only checks that the address belongs to a school, not that the user is
+// enrolled, since staff and alumni often keep their .edu addresses too.
+// Schools outside the US mostly don't use .edu, so their students can't get
+// the plan yet.
+func isEmailAddressEligibleForStudentPlan(emailAddress string) bool {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(emailAddress)), "@")
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return false
+	}
+	domainLabels := strings.Split(parts[1], ".")
+	if len(domainLabels) < 2 || domainLabels[len(domainLabels)-1] != studentPlanEmailTopLevelDomain {
+		return false
+	}
+	for _, label := range domainLabels {
+		if len(label) == 0 {
+			return false
+		}
+	}
+	return true
+}
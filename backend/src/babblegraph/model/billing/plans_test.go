@@ -0,0 +1,141 @@
+package billing
+
+import (
+	"babblegraph/util/env"
+	"os"
+	"testing"
+
+	"github.com/stripe/stripe-go/v72"
+)
+
+func TestFormatPriceCents(t *testing.T) {
+	type testCase struct {
+		priceCents int64
+		currency   string
+		expected   string
+	}
+	for idx, tc := range []testCase{
+		{priceCents: 2900, currency: "usd", expected: "US$29.00"},
+		{priceCents: 399, currency: "USD", expected: "US$3.99"},
+		{priceCents: 1505, currency: "eur", expected: "€15.05"},
+		{priceCents: 5, currency: "gbp", expected: "£0.05"},
+		{priceCents: 2900, currency: "mxn", expected: "29.00 MXN"},
+	} {
+		if result := FormatPriceCents(tc.priceCents, tc.currency); result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestGetPlanTypeForString(t *testing.T) {
+	for _, planType := range []PlanType{PlanTypeMonthly, PlanTypeAnnual, PlanTypeStudent} {
+		result, err := GetPlanTypeForString(planType.Str())
+		switch {
+		case err != nil:
+			t.Errorf("Got error for plan type %s: %s", planType, err.Error())
+		case *result != planType:
+			t.Errorf("Expected %s, but got %s", planType, *result)
+		}
+	}
+	if _, err := GetPlanTypeForString("weekly"); err == nil {
+		t.Errorf("Expected error for unrecognized plan type")
+	}
+}
+
+func TestIsEmailAddressEligibleForPlanType(t *testing.T) {
+	type testCase struct {
+		emailAddress string
+		planType     PlanType
+		expected     bool
+	}
+	for idx, tc := range []testCase{
+		{emailAddress: "someone@babblegraph.com", planType: PlanTypeMonthly, expected: true},
+		{emailAddress: "someone@babblegraph.com", planType: PlanTypeAnnual, expected: true},
+		{emailAddress: "someone@babblegraph.com", planType: PlanTypeStudent, expected: false},
+		{emailAddress: "someone@university.edu", planType: PlanTypeStudent, expected: true},
+		{emailAddress: "SomeOne@University.EDU", planType: PlanTypeStudent, expected: true},
+		{emailAddress: "someone@edu.babblegraph.com", planType: PlanTypeStudent, expected: false},
+		{emailAddress: "someone@cs.university.edu", planType: PlanTypeStudent, expected: true},
+		{emailAddress: "someone@.edu", planType: PlanTypeStudent, expected: false},
+		{emailAddress: "someone@edu", planType: PlanTypeStudent, expected: false},
+		{emailAddress: "@university.edu", planType: PlanTypeStudent, expected: false},
+		{emailAddress: "someone@notanedu", planType: PlanTypeStudent, expected: false},
+		{emailAddress: "someone@university.edu@babblegraph.com", planType: PlanTypeStudent, expected: false},
+	} {
+		if result := isEmailAddressEligibleForPlanType(tc.emailAddress, tc.planType); result != tc.expected {
+			t.Errorf("Error on test case %d: expected %t, but got %t", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestGetPlanFieldsForStripePrice(t *testing.T) {
+	priceCents, currency, billingInterval, err := getPlanFieldsForStripePrice(&stripe.Price{
+		ID:         "price_123",
+		UnitAmount: 499,
+		Currency:   stripe.CurrencyUSD,
+		Recurring: &stripe.PriceRecurring{
+			Interval:      stripe.PriceRecurringIntervalMonth,
+			IntervalCount: 1,
+		},
+	})
+	switch {
+	case err != nil:
+		t.Fatalf("Got error %s", err.Error())
+	case *priceCents != 499:
+		t.Errorf("Expected price of 499, but got %d", *priceCents)
+	case *currency != "usd":
+		t.Errorf("Expected currency usd, but got %s", *currency)
+	case *billingInterval != BillingIntervalMonth:
+		t.Errorf("Expected billing interval %s, but got %s", BillingIntervalMonth, *billingInterval)
+	}
+	for idx, invalidPrice := range []stripe.Price{
+		{ID: "price_one_time"},
+		{ID: "price_quarterly", Recurring: &stripe.PriceRecurring{Interval: stripe.PriceRecurringIntervalMonth, IntervalCount: 3}},
+		{ID: "price_weekly", Recurring: &stripe.PriceRecurring{Interval: stripe.PriceRecurringIntervalWeek, IntervalCount: 1}},
+	} {
+		if _, _, _, err := getPlanFieldsForStripePrice(&invalidPrice); err == nil {
+			t.Errorf("Expected error on test case %d", idx)
+		}
+	}
+}
+
+func TestGetPlanForPremiumNewsletterSubscription(t *testing.T) {
+	if os.Getenv("ENV") == "" {
+		os.Setenv("ENV", env.EnvironmentTest.Str())
+		t.Cleanup(func() {
+			os.Unsetenv("ENV")
+		})
+	}
+	fake := useFakeStripeClient(t)
+	legacyPlanCache.plan = nil
+	t.Cleanup(func() {
+		legacyPlanCache.plan = nil
+	})
+	legacyStripePriceID, err := getStripeProductIDForEnvironment()
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	fake.addPrice(*legacyStripePriceID, 2900, stripe.PriceRecurringIntervalYear)
+	plan, err := GetPlanForPremiumNewsletterSubscription(PremiumNewsletterSubscription{})
+	switch {
+	case err != nil:
+		t.Fatalf("Got error %s", err.Error())
+	case plan.PriceCents != 2900:
+		t.Errorf("Expected price of 2900, but got %d", plan.PriceCents)
+	case plan.BillingInterval != BillingIntervalYear:
+		t.Errorf("Expected billing interval %s, but got %s", BillingIntervalYear, plan.BillingInterval)
+	}
+	// The legacy plan is cached, so a second lookup doesn't go to Stripe
+	delete(fake.prices, *legacyStripePriceID)
+	if plan, err := GetPlanForPremiumNewsletterSubscription(PremiumNewsletterSubscription{}); err != nil || plan.PriceCents != 2900 {
+		t.Errorf("Expected cached legacy plan, but got plan %+v and error %v", plan, err)
+	}
+	catalogPlan := Plan{Type: PlanTypeMonthly, PriceCents: 499, Currency: "usd", BillingInterval: BillingIntervalMonth}
+	plan, err = GetPlanForPremiumNewsletterSubscription(PremiumNewsletterSubscription{Plan: &catalogPlan})
+	switch {
+	case err != nil:
+		t.Fatalf("Got error %s", err.Error())
+	case *plan != catalogPlan:
+		t.Errorf("Expected plan %+v, but got %+v", catalogPlan, *plan)
+	}
+}
@@ -13,7 +13,6 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/sub"
 )
 
 const (
@@ -32,7 +31,6 @@ const (
 )
 
 func LookupPremiumNewsletterSubscriptionForUser(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID) (*PremiumNewsletterSubscription, error) {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
 	billingInformation, err := lookupBillingInformationForUserID(tx, userID)
 	switch {
 	case err != nil:
@@ -43,8 +41,7 @@ func LookupPremiumNewsletterSubscriptionForUser(c ctx.LogContext, tx *sqlx.Tx, u
 	return lookupActivePremiumNewsletterSubscriptionForUser(c, tx, *billingInformation)
 }
 
-func CreatePremiumNewsletterSubscriptionForUserWithID(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, subscriptionID PremiumNewsletterSubscriptionID) (*PremiumNewsletterSubscription, error) {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
+func CreatePremiumNewsletterSubscriptionForUserWithID(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, subscriptionID PremiumNewsletterSubscriptionID, planType PlanType) (*PremiumNewsletterSubscription, error) {
 	billingInformation, err := lookupBillingInformationForUserID(tx, userID)
 	switch {
 	case err != nil:
@@ -52,10 +49,13 @@ func CreatePremiumNewsletterSubscriptionForUserWithID(c ctx.LogContext, tx *sqlx
 	case billingInformation == nil:
 		return nil, fmt.Errorf("Expected there to be a billing information for user %s, but none exists", userID)
 	}
+	if err := verifyUserIsEligibleForPlanType(tx, userID, planType); err != nil {
+		return nil, err
+	}
 	if _, err := tx.Exec(insertPremiumNewsletterSubscriptionDebounceRecordQuery, billingInformation.ID); err != nil {
 		return nil, err
 	}
-	stripeProductID, err := getStripeProductIDForEnvironment()
+	stripeProductID, err := getStripePriceIDForPlanType(tx, planType)
 	if err != nil {
 		return nil, err
 	}
@@ -119,13 +119,13 @@ func CreatePremiumNewsletterSubscriptionForUserWithID(c ctx.LogContext, tx *sqlx
 		// To test locally, we set the subscription to expire in 5 minutes
 		subscriptionParams.TrialEnd = ptr.Int64(time.Now().Add(5 * time.Minute).Unix())
 	}
-	stripeSubscription, err := sub.New(subscriptionParams)
+	stripeSubscription, err := stripeClient.NewSubscription(subscriptionParams)
 	if err != nil {
 		return nil, err
 	}
 	if err := insertActivePremiumNewsletterSubscriptionForUser(tx, subscriptionID, billingInformation.ID, stripeSubscription); err != nil {
 		c.Warnf("Attempting to rollback stripe subscription with Stripe ID %s and Babblegraph User ID %s", stripeSubscription.ID, userID)
-		if _, sErr := sub.Cancel(stripeSubscription.ID, &stripe.SubscriptionCancelParams{}); sErr != nil {
+		if _, sErr := stripeClient.CancelSubscription(stripeSubscription.ID); sErr != nil {
 			c.Errorf("Error rolling back subscription ID %s in Stripe for user ID %s because of error %s", stripeSubscription.ID, userID, sErr.Error())
 		}
 		return nil, err
@@ -168,8 +168,7 @@ func UpdateSubscriptionAutoRenewForUser(tx *sqlx.Tx, userID users.UserID, isAuto
 		}
 		switch externalID.IDType {
 		case externalIDTypeStripe:
-			stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-			if _, err := sub.Update(externalID.ExternalID, &stripe.SubscriptionParams{
+			if _, err := stripeClient.UpdateSubscription(externalID.ExternalID, &stripe.SubscriptionParams{
 				CancelAtPeriodEnd: ptr.Bool(!isAutoRenewEnabled),
 			}); err != nil {
 				return err
@@ -207,8 +206,7 @@ func CancelPremiumNewsletterSubscriptionForUser(c ctx.LogContext, tx *sqlx.Tx, u
 		}
 		switch externalID.IDType {
 		case externalIDTypeStripe:
-			stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-			if _, err := sub.Cancel(externalID.ExternalID, nil); err != nil {
+			if _, err := stripeClient.CancelSubscription(externalID.ExternalID); err != nil {
 				return err
 			}
 			return nil
@@ -220,6 +218,100 @@ func CancelPremiumNewsletterSubscriptionForUser(c ctx.LogContext, tx *sqlx.Tx, u
 	}
 }
 
+type PremiumNewsletterSubscriptionPlanSwitch struct {
+	premiumNewsletterSubscriptionID PremiumNewsletterSubscriptionID
+	planType                        PlanType
+	stripeSubscriptionID            string
+	stripePriceID                   string
+}
+
+// PreparePremiumNewsletterSubscriptionPlanSwitch checks that the user can move their active
+// subscription to the plan type, and queues a sync so that the user's account is updated once
+// Stripe has the new plan. Stripe isn't updated until ApplyPremiumNewsletterSubscriptionPlanSwitch
+// is called after the transaction commits, so a rollback can't leave Stripe on a plan we don't know about.
+func PreparePremiumNewsletterSubscriptionPlanSwitch(tx *sqlx.Tx, userID users.UserID, planType PlanType) (*PremiumNewsletterSubscriptionPlanSwitch, error) {
+	if err := verifyUserIsEligibleForPlanType(tx, userID, planType); err != nil {
+		return nil, err
+	}
+	billingInformation, err := lookupBillingInformationForUserID(tx, userID)
+	switch {
+	case err != nil:
+		return nil, err
+	case billingInformation == nil:
+		return nil, fmt.Errorf("Expected there to be a billing information for user %s, but none exists", userID)
+	}
+	dbPremiumNewsletterSubscription, err := lookupDBActivePremiumNewsletterSubscriptionForUser(tx, *billingInformation)
+	switch {
+	case err != nil:
+		return nil, err
+	case dbPremiumNewsletterSubscription == nil:
+		return nil, fmt.Errorf("User %s has no active subscription to switch", userID)
+	}
+	stripePriceID, err := getStripePriceIDForPlanType(tx, planType)
+	if err != nil {
+		return nil, err
+	}
+	externalID, err := getExternalIDMapping(tx, dbPremiumNewsletterSubscription.ExternalIDMappingID)
+	if err != nil {
+		return nil, err
+	}
+	if externalID.IDType != externalIDTypeStripe {
+		return nil, fmt.Errorf("Invalid ID Type %s", externalID.IDType)
+	}
+	// If Stripe can't be updated after this commits, the sync
+	// just copies over the subscription as it is in Stripe
+	if err := InsertPremiumNewsletterSyncRequest(tx, dbPremiumNewsletterSubscription.ID, PremiumNewsletterSubscriptionUpdateTypePlanChanged); err != nil {
+		return nil, err
+	}
+	return &PremiumNewsletterSubscriptionPlanSwitch{
+		premiumNewsletterSubscriptionID: dbPremiumNewsletterSubscription.ID,
+		planType:                        planType,
+		stripeSubscriptionID:            externalID.ExternalID,
+		stripePriceID:                   *stripePriceID,
+	}, nil
+}
+
+// ApplyPremiumNewsletterSubscriptionPlanSwitch moves the subscription in Stripe to the new price.
+// The difference is prorated by Stripe. It's safe to retry, since a subscription that is already
+// on the new price is left alone.
+func ApplyPremiumNewsletterSubscriptionPlanSwitch(c ctx.LogContext, planSwitch PremiumNewsletterSubscriptionPlanSwitch) error {
+	stripeSubscription, err := stripeClient.GetSubscription(planSwitch.stripeSubscriptionID, nil)
+	if err != nil {
+		return err
+	}
+	if stripeSubscription.Items == nil || len(stripeSubscription.Items.Data) != 1 {
+		return fmt.Errorf("Expected Stripe subscription %s to have exactly one item", stripeSubscription.ID)
+	}
+	subscriptionItem := stripeSubscription.Items.Data[0]
+	if subscriptionItem.Price != nil && subscriptionItem.Price.ID == planSwitch.stripePriceID {
+		c.Infof("Subscription %s is already on plan type %s", planSwitch.premiumNewsletterSubscriptionID, planSwitch.planType)
+		return nil
+	}
+	if _, err := stripeClient.UpdateSubscription(stripeSubscription.ID, &stripe.SubscriptionParams{
+		Items: []*stripe.SubscriptionItemsParams{
+			{
+				ID:    ptr.String(subscriptionItem.ID),
+				Price: ptr.String(planSwitch.stripePriceID),
+			},
+		},
+		ProrationBehavior: ptr.String(string(stripe.SubscriptionProrationBehaviorCreateProrations)),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func verifyUserIsEligibleForPlanType(tx *sqlx.Tx, userID users.UserID, planType PlanType) error {
+	user, err := users.GetUser(tx, userID)
+	if err != nil {
+		return err
+	}
+	if !isEmailAddressEligibleForPlanType(user.EmailAddress, planType) {
+		return fmt.Errorf("User %s is not eligible for plan type %s", userID, planType)
+	}
+	return nil
+}
+
 func lookupActivePremiumNewsletterSubscriptionForUser(c ctx.LogContext, tx *sqlx.Tx, billingInformation dbBillingInformation) (*PremiumNewsletterSubscription, error) {
 	// There are three possible scenarios for this function:
 	// The database returns no active subscriptions - in which case we assume that there are no active subscriptions in the provider
@@ -240,7 +332,6 @@ func lookupActivePremiumNewsletterSubscriptionForUser(c ctx.LogContext, tx *sqlx
 }
 
 func getStripeSubscriptionAndConvertSubscriptionForDBPremiumNewsletterSubscription(c ctx.LogContext, tx *sqlx.Tx, dbPremiumNewsletterSubscription dbPremiumNewsletterSubscription, shouldReturnIfTerminated bool) (*PremiumNewsletterSubscription, error) {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
 	var premiumNewsletterSubscription *PremiumNewsletterSubscription
 	externalID, err := getExternalIDMapping(tx, dbPremiumNewsletterSubscription.ExternalIDMappingID)
 	if err != nil {
@@ -253,7 +344,7 @@ func getStripeSubscriptionAndConvertSubscriptionForDBPremiumNewsletterSubscripti
 		subscriptionParams.AddExpand("default_payment_method")
 		subscriptionParams.AddExpand("plan")
 		subscriptionParams.AddExpand("discount")
-		stripeSubscription, err := sub.Get(externalID.ExternalID, subscriptionParams)
+		stripeSubscription, err := stripeClient.GetSubscription(externalID.ExternalID, subscriptionParams)
 		if err != nil {
 			return nil, err
 		}
@@ -3,13 +3,11 @@ package billing
 import (
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
-	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/promotioncode"
 )
 
 const (
@@ -59,20 +57,14 @@ func GetAllURLPromotionCodes(c ctx.LogContext, tx *sqlx.Tx) ([]PromotionCode, er
 		dbPromotionCodesByID[externalIDWithType] = promotionCode
 	}
 	// Get Stripe Codes
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
 	promotionCodeListParams := &stripe.PromotionCodeListParams{}
 	promotionCodeListParams.AddExpand("data.coupon")
-	stripePromotionCodesIterator := promotioncode.List(promotionCodeListParams)
-	if stripePromotionCodesIterator.Err() != nil {
-		return nil, stripePromotionCodesIterator.Err()
+	stripePromotionCodes, err := stripeClient.ListPromotionCodes(promotionCodeListParams)
+	if err != nil {
+		return nil, err
 	}
 	var out []PromotionCode
-	for stripePromotionCodesIterator.Next() {
-		stripePromotionCode, ok := stripePromotionCodesIterator.Current().(*stripe.PromotionCode)
-		if !ok {
-			c.Debugf("Could not convert to promotion code")
-			continue
-		}
+	for _, stripePromotionCode := range stripePromotionCodes {
 		externalIDWithType := fmt.Sprintf("%s_%s", externalIDTypeStripe, stripePromotionCode.ID)
 		dbPromotionCode, ok := dbPromotionCodesByID[externalIDWithType]
 		if !ok {
@@ -147,9 +139,7 @@ func getPromotionCodeFromDBVersion(tx *sqlx.Tx, dbVersion dbPromotionCode) (*Pro
 	case err != nil:
 		return nil, err
 	case externalIDMapping.IDType == externalIDTypeStripe:
-		stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-		searchParams := &stripe.PromotionCodeParams{}
-		stripePromotionCode, err := promotioncode.Get(externalIDMapping.ExternalID, searchParams)
+		stripePromotionCode, err := stripeClient.GetPromotionCode(externalIDMapping.ExternalID)
 		if err != nil {
 			return nil, err
 		}
@@ -3,7 +3,6 @@ package billing
 import (
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
-	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"encoding/json"
 	"fmt"
@@ -11,8 +10,6 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stripe/stripe-go/v72"
-	"github.com/stripe/stripe-go/v72/setupintent"
-	"github.com/stripe/stripe-go/v72/webhook"
 )
 
 // This file is for any stripe specific methods
@@ -22,7 +19,6 @@ const (
 )
 
 func GetSetupIntentClientSecretForUser(tx *sqlx.Tx, userID users.UserID) (*string, error) {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
 	billingInformation, err := lookupBillingInformationForUserID(tx, userID)
 	switch {
 	case err != nil:
@@ -44,7 +40,7 @@ func GetSetupIntentClientSecretForUser(tx *sqlx.Tx, userID users.UserID) (*strin
 			},
 			Usage: ptr.String("off_session"),
 		}
-		si, err := setupintent.New(params)
+		si, err := stripeClient.NewSetupIntent(params)
 		if err != nil {
 			return nil, err
 		}
@@ -59,7 +55,13 @@ func convertStripeSubscriptionToPremiumNewsletterSubscription(tx *sqlx.Tx, strip
 	}
 	var priceCents *int64
 	var hasValidDiscount bool
+	var plan *Plan
 	if stripeSubscription.Plan != nil {
+		var err error
+		plan, err = lookupPlanByStripePriceID(tx, stripeSubscription.Plan.ID)
+		if err != nil {
+			return nil, err
+		}
 		priceCents = ptr.Int64(stripeSubscription.Plan.Amount)
 		hasValidDiscount = stripeSubscription.Discount != nil && stripeSubscription.Discount.Coupon != nil && stripeSubscription.Discount.Coupon.Valid
 		if hasValidDiscount {
@@ -72,6 +74,7 @@ func convertStripeSubscriptionToPremiumNewsletterSubscription(tx *sqlx.Tx, strip
 		IsAutoRenewEnabled:    !stripeSubscription.CancelAtPeriodEnd,
 		PriceCents:            priceCents,
 		HasValidDiscount:      hasValidDiscount,
+		Plan:                  plan,
 	}
 	var billingInformation *dbBillingInformation
 	if dbNewsletterSubscription != nil {
@@ -83,45 +86,54 @@ func convertStripeSubscriptionToPremiumNewsletterSubscription(tx *sqlx.Tx, strip
 		premiumNewsletterSubscription.userID = billingInformation.UserID
 		premiumNewsletterSubscription.ID = &dbNewsletterSubscription.ID
 	}
+	var hasPaymentMethod bool
+	if stripeSubscription.Status == stripe.SubscriptionStatusTrialing && billingInformation != nil && billingInformation.UserID != nil {
+		paymentMethods, err := GetPaymentMethodsForUser(tx, *billingInformation.UserID)
+		if err != nil {
+			return nil, err
+		}
+		hasPaymentMethod = len(paymentMethods) > 0
+	}
+	paymentState, err := getPaymentStateForStripeSubscription(stripeSubscription, hasPaymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	premiumNewsletterSubscription.PaymentState = *paymentState
+	return &premiumNewsletterSubscription, nil
+}
+
+func getPaymentStateForStripeSubscription(stripeSubscription *stripe.Subscription, hasPaymentMethod bool) (*PaymentState, error) {
+	var paymentState PaymentState
 	switch stripeSubscription.Status {
 	case stripe.SubscriptionStatusTrialing:
-		premiumNewsletterSubscription.PaymentState = PaymentStateTrialNoPaymentMethod
-		if billingInformation != nil && billingInformation.UserID != nil {
-			paymentMethods, err := GetPaymentMethodsForUser(tx, *billingInformation.UserID)
-			if err != nil {
-				return nil, err
-			}
-			if len(paymentMethods) > 0 {
-				premiumNewsletterSubscription.PaymentState = PaymentStateTrialPaymentMethodAdded
-			}
+		paymentState = PaymentStateTrialNoPaymentMethod
+		if hasPaymentMethod {
+			paymentState = PaymentStateTrialPaymentMethodAdded
 		}
 	case stripe.SubscriptionStatusIncomplete:
-		premiumNewsletterSubscription.PaymentState = PaymentStateCreatedUnpaid
+		paymentState = PaymentStateCreatedUnpaid
 	case stripe.SubscriptionStatusActive:
-		status := PaymentStateActive
+		paymentState = PaymentStateActive
 		if stripeSubscription.LatestInvoice != nil && stripeSubscription.LatestInvoice.Status != stripe.InvoiceStatusPaid {
-			status = PaymentStatePaymentPending
+			paymentState = PaymentStatePaymentPending
 		}
-		premiumNewsletterSubscription.PaymentState = status
 	case stripe.SubscriptionStatusPastDue:
-		premiumNewsletterSubscription.PaymentState = PaymentStatePaymentPending
+		paymentState = PaymentStatePaymentPending
 	case stripe.SubscriptionStatusUnpaid:
-		premiumNewsletterSubscription.PaymentState = PaymentStateErrored
+		paymentState = PaymentStateErrored
 	case stripe.SubscriptionStatusIncompleteExpired,
 		stripe.SubscriptionStatusCanceled:
-		premiumNewsletterSubscription.PaymentState = PaymentStateTerminated
+		paymentState = PaymentStateTerminated
 	case stripe.SubscriptionStatusAll:
 		return nil, fmt.Errorf("Unsupported payment status: all")
 	default:
 		return nil, fmt.Errorf("Unsupported payment status: %s", stripeSubscription.Status)
 	}
-	return &premiumNewsletterSubscription, nil
+	return &paymentState, nil
 }
 
 func HandleStripeEvent(c ctx.LogContext, tx *sqlx.Tx, stripeSignature string, eventBytes []byte) error {
-	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
-	webhookSecret := env.MustEnvironmentVariable("STRIPE_WEBHOOK_SECRET")
-	event, err := webhook.ConstructEvent(eventBytes, stripeSignature, webhookSecret)
+	event, err := stripeClient.ConstructWebhookEvent(eventBytes, stripeSignature)
 	if err != nil {
 		return err
 	}
@@ -166,6 +178,11 @@ func HandleStripeEvent(c ctx.LogContext, tx *sqlx.Tx, stripeSignature string, ev
 			case premiumNewsletterSubscription == nil:
 				c.Warnf("No subscription found for stripe ID %s", stripeInvoice.Subscription.ID)
 			case premiumNewsletterSubscription != nil:
+				if event.Type == "invoice.payment_failed" {
+					if err := recordFailedInvoicePayment(c, tx, *premiumNewsletterSubscription, stripeInvoice); err != nil {
+						return err
+					}
+				}
 				if err := InsertPremiumNewsletterSyncRequest(tx, *premiumNewsletterSubscription.ID, PremiumNewsletterSubscriptionUpdateTypeRemoteUpdated); err != nil {
 					return err
 				}
@@ -0,0 +1,171 @@
+package billing
+
+import (
+	"babblegraph/util/env"
+
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/coupon"
+	"github.com/stripe/stripe-go/v72/customer"
+	"github.com/stripe/stripe-go/v72/paymentmethod"
+	"github.com/stripe/stripe-go/v72/price"
+	"github.com/stripe/stripe-go/v72/promotioncode"
+	"github.com/stripe/stripe-go/v72/setupintent"
+	"github.com/stripe/stripe-go/v72/sub"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+// stripeAPI is every call that billing makes to Stripe.
+// Tests replace stripeClient with an in-memory fake.
+type stripeAPI interface {
+	NewCustomer(params *stripe.CustomerParams) (*stripe.Customer, error)
+	GetCustomer(id string) (*stripe.Customer, error)
+	UpdateCustomer(id string, params *stripe.CustomerParams) (*stripe.Customer, error)
+	DeleteCustomer(id string) error
+
+	ListPaymentMethods(params *stripe.PaymentMethodListParams) ([]*stripe.PaymentMethod, error)
+	DetachPaymentMethod(id string) error
+	NewSetupIntent(params *stripe.SetupIntentParams) (*stripe.SetupIntent, error)
+
+	NewSubscription(params *stripe.SubscriptionParams) (*stripe.Subscription, error)
+	GetSubscription(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error)
+	UpdateSubscription(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error)
+	CancelSubscription(id string) (*stripe.Subscription, error)
+
+	GetPrice(id string) (*stripe.Price, error)
+
+	NewCoupon(params *stripe.CouponParams) (*stripe.Coupon, error)
+	DeleteCoupon(id string) error
+	NewPromotionCode(params *stripe.PromotionCodeParams) (*stripe.PromotionCode, error)
+	GetPromotionCode(id string) (*stripe.PromotionCode, error)
+	UpdatePromotionCode(id string, params *stripe.PromotionCodeParams) (*stripe.PromotionCode, error)
+	ListPromotionCodes(params *stripe.PromotionCodeListParams) ([]*stripe.PromotionCode, error)
+
+	// ConstructWebhookEvent verifies the Stripe-Signature header
+	// of a webhook request and parses the event
+	ConstructWebhookEvent(payload []byte, signature string) (*stripe.Event, error)
+}
+
+var stripeClient stripeAPI = liveStripeClient{}
+
+type liveStripeClient struct{}
+
+func (liveStripeClient) setKey() {
+	stripe.Key = env.MustEnvironmentVariable("STRIPE_KEY")
+}
+
+func (l liveStripeClient) NewCustomer(params *stripe.CustomerParams) (*stripe.Customer, error) {
+	l.setKey()
+	return customer.New(params)
+}
+
+func (l liveStripeClient) GetCustomer(id string) (*stripe.Customer, error) {
+	l.setKey()
+	return customer.Get(id, &stripe.CustomerParams{})
+}
+
+func (l liveStripeClient) UpdateCustomer(id string, params *stripe.CustomerParams) (*stripe.Customer, error) {
+	l.setKey()
+	return customer.Update(id, params)
+}
+
+func (l liveStripeClient) DeleteCustomer(id string) error {
+	l.setKey()
+	_, err := customer.Del(id, &stripe.CustomerParams{})
+	return err
+}
+
+func (l liveStripeClient) ListPaymentMethods(params *stripe.PaymentMethodListParams) ([]*stripe.PaymentMethod, error) {
+	l.setKey()
+	iter := paymentmethod.List(params)
+	var out []*stripe.PaymentMethod
+	for iter.Next() {
+		out = append(out, iter.PaymentMethod())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (l liveStripeClient) DetachPaymentMethod(id string) error {
+	l.setKey()
+	_, err := paymentmethod.Detach(id, nil)
+	return err
+}
+
+func (l liveStripeClient) NewSetupIntent(params *stripe.SetupIntentParams) (*stripe.SetupIntent, error) {
+	l.setKey()
+	return setupintent.New(params)
+}
+
+func (l liveStripeClient) NewSubscription(params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	l.setKey()
+	return sub.New(params)
+}
+
+func (l liveStripeClient) GetSubscription(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	l.setKey()
+	return sub.Get(id, params)
+}
+
+func (l liveStripeClient) UpdateSubscription(id string, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	l.setKey()
+	return sub.Update(id, params)
+}
+
+func (l liveStripeClient) CancelSubscription(id string) (*stripe.Subscription, error) {
+	l.setKey()
+	return sub.Cancel(id, &stripe.SubscriptionCancelParams{})
+}
+
+func (l liveStripeClient) GetPrice(id string) (*stripe.Price, error) {
+	l.setKey()
+	return price.Get(id, nil)
+}
+
+func (l liveStripeClient) NewCoupon(params *stripe.CouponParams) (*stripe.Coupon, error) {
+	l.setKey()
+	return coupon.New(params)
+}
+
+func (l liveStripeClient) DeleteCoupon(id string) error {
+	l.setKey()
+	_, err := coupon.Del(id, nil)
+	return err
+}
+
+func (l liveStripeClient) NewPromotionCode(params *stripe.PromotionCodeParams) (*stripe.PromotionCode, error) {
+	l.setKey()
+	return promotioncode.New(params)
+}
+
+func (l liveStripeClient) GetPromotionCode(id string) (*stripe.PromotionCode, error) {
+	l.setKey()
+	return promotioncode.Get(id, &stripe.PromotionCodeParams{})
+}
+
+func (l liveStripeClient) UpdatePromotionCode(id string, params *stripe.PromotionCodeParams) (*stripe.PromotionCode, error) {
+	l.setKey()
+	return promotioncode.Update(id, params)
+}
+
+func (l liveStripeClient) ListPromotionCodes(params *stripe.PromotionCodeListParams) ([]*stripe.PromotionCode, error) {
+	l.setKey()
+	iter := promotioncode.List(params)
+	var out []*stripe.PromotionCode
+	for iter.Next() {
+		out = append(out, iter.PromotionCode())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (liveStripeClient) ConstructWebhookEvent(payload []byte, signature string) (*stripe.Event, error) {
+	event, err := webhook.ConstructEvent(payload, signature, env.MustEnvironmentVariable("STRIPE_WEBHOOK_SECRET"))
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
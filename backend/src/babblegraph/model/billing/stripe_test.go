@@ -0,0 +1,137 @@
+package billing
+
+import (
+	"babblegraph/util/ptr"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v72"
+)
+
+func TestFakeStripeWebhookEventsAreSigned(t *testing.T) {
+	fake := newFakeStripeClient()
+	fake.addPrice("price_annual", 2900, stripe.PriceRecurringIntervalYear)
+	customer, err := fake.NewCustomer(&stripe.CustomerParams{})
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	if _, err := fake.NewSubscription(&stripe.SubscriptionParams{
+		Customer: ptr.String(customer.ID),
+		Items: []*stripe.SubscriptionItemsParams{
+			{Price: ptr.String("price_annual")},
+		},
+		TrialPeriodDays: ptr.Int64(14),
+	}); err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	events := fake.takeWebhookEvents()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, but got %d", len(events))
+	}
+	event, err := fake.ConstructWebhookEvent(events[0].Payload, events[0].Signature)
+	switch {
+	case err != nil:
+		t.Fatalf("Got error %s", err.Error())
+	case event.Type != "customer.subscription.created":
+		t.Errorf("Expected customer.subscription.created, but got %s", event.Type)
+	}
+	tamperedPayload := append([]byte{}, events[0].Payload...)
+	tamperedPayload[len(tamperedPayload)-2] = ' '
+	if _, err := fake.ConstructWebhookEvent(tamperedPayload, events[0].Signature); err == nil {
+		t.Errorf("Expected error for tampered payload")
+	}
+	if len(fake.takeWebhookEvents()) != 0 {
+		t.Errorf("Expected events to be cleared once taken")
+	}
+}
+
+func TestPaymentStatesThroughSubscriptionLifecycle(t *testing.T) {
+	fake := newFakeStripeClient()
+	fake.addPrice("price_annual", 2900, stripe.PriceRecurringIntervalYear)
+	customer, err := fake.NewCustomer(&stripe.CustomerParams{})
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	subscription, err := fake.NewSubscription(&stripe.SubscriptionParams{
+		Customer: ptr.String(customer.ID),
+		Items: []*stripe.SubscriptionItemsParams{
+			{Price: ptr.String("price_annual")},
+		},
+		TrialEnd: ptr.Int64(time.Now().Add(5 * time.Minute).Unix()),
+	})
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	checkPaymentState := func(step string, stripeSubscription *stripe.Subscription, hasPaymentMethod bool, expected PaymentState) {
+		paymentState, err := getPaymentStateForStripeSubscription(stripeSubscription, hasPaymentMethod)
+		switch {
+		case err != nil:
+			t.Fatalf("Error on step %s: %s", step, err.Error())
+		case *paymentState != expected:
+			t.Errorf("Error on step %s: expected payment state %d, but got %d", step, expected, *paymentState)
+		}
+	}
+	// The payment state is taken from the subscription in the webhook
+	// events, since that's what Babblegraph would be sent
+	expectPaymentState := func(step string, hasPaymentMethod bool, expectedEventTypes []string, expected PaymentState) {
+		events := fake.takeWebhookEvents()
+		var eventTypes []string
+		for _, e := range events {
+			eventTypes = append(eventTypes, e.Type)
+		}
+		if strings.Join(eventTypes, ",") != strings.Join(expectedEventTypes, ",") {
+			t.Fatalf("Error on step %s: expected events %v, but got %v", step, expectedEventTypes, eventTypes)
+		}
+		var stripeSubscription *stripe.Subscription
+		for _, e := range events {
+			event, err := fake.ConstructWebhookEvent(e.Payload, e.Signature)
+			if err != nil {
+				t.Fatalf("Error on step %s: %s", step, err.Error())
+			}
+			if !strings.HasPrefix(event.Type, "customer.subscription.") {
+				continue
+			}
+			stripeSubscription = &stripe.Subscription{}
+			if err := json.Unmarshal(event.Data.Raw, stripeSubscription); err != nil {
+				t.Fatalf("Error on step %s: %s", step, err.Error())
+			}
+		}
+		if stripeSubscription == nil || stripeSubscription.ID != subscription.ID {
+			t.Fatalf("Error on step %s: expected subscription %s in events", step, subscription.ID)
+		}
+		checkPaymentState(step, stripeSubscription, hasPaymentMethod, expected)
+	}
+	expectPaymentState("trial", false, []string{"customer.subscription.created"}, PaymentStateTrialNoPaymentMethod)
+
+	if _, err := fake.addPaymentMethod(customer.ID); err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	if events := fake.takeWebhookEvents(); len(events) != 1 || events[0].Type != "setup_intent.succeeded" {
+		t.Fatalf("Expected a setup_intent.succeeded event after adding a payment method")
+	}
+	checkPaymentState("payment method added", subscription, true, PaymentStateTrialPaymentMethodAdded)
+
+	if err := fake.endCurrentPeriod(subscription.ID, true); err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	expectPaymentState("active", true, []string{"invoice.paid", "customer.subscription.updated"}, PaymentStateActive)
+
+	if err := fake.endCurrentPeriod(subscription.ID, false); err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	expectPaymentState("payment failure", true, []string{"invoice.payment_failed", "customer.subscription.updated"}, PaymentStatePaymentPending)
+
+	if _, err := fake.UpdateSubscription(subscription.ID, &stripe.SubscriptionParams{
+		CancelAtPeriodEnd: ptr.Bool(true),
+	}); err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	expectPaymentState("cancellation", true, []string{"customer.subscription.updated"}, PaymentStatePaymentPending)
+
+	if err := fake.endCurrentPeriod(subscription.ID, true); err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	expectPaymentState("termination", true, []string{"customer.subscription.deleted"}, PaymentStateTerminated)
+}
@@ -56,7 +56,9 @@ func SyncUserAccountWithPremiumNewsletterSubscription(tx *sqlx.Tx, userID users.
 	if premiumNewsletterSubscription == nil {
 		return useraccounts.ExpireSubscriptionForUser(tx, userID)
 	}
-	subscriptionLevel, err := useraccounts.LookupSubscriptionLevelForUser(tx, userID)
+	// Gifts and group licenses aren't stored on the account, so they
+	// need to be left out when deciding whether to add or update the subscription
+	subscriptionLevel, err := useraccounts.LookupAccountSubscriptionLevelForUser(tx, userID)
 	if err != nil {
 		return err
 	}
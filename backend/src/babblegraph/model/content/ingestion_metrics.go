@@ -0,0 +1,291 @@
+package content
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	upsertIngestionMetricsQuery = `INSERT INTO
+        content_source_ingestion_metrics (
+            source_id,
+            metric_date,
+            links_fetched,
+            parse_failures,
+            paywalled,
+            indexed,
+            readability_score_total,
+            last_success_at
+        ) VALUES (
+            $1, timezone('utc', now())::date, $2, $3, $4, $5, $6, $7
+        ) ON CONFLICT (source_id, metric_date) DO UPDATE
+        SET
+            links_fetched = content_source_ingestion_metrics.links_fetched + $2,
+            parse_failures = content_source_ingestion_metrics.parse_failures + $3,
+            paywalled = content_source_ingestion_metrics.paywalled + $4,
+            indexed = content_source_ingestion_metrics.indexed + $5,
+            readability_score_total = content_source_ingestion_metrics.readability_score_total + $6,
+            last_success_at = COALESCE($7, content_source_ingestion_metrics.last_success_at),
+            last_modified_at = timezone('utc', now())
+    `
+	getIngestionMetricsSinceQuery = `
+        SELECT
+            source_id,
+            SUM(links_fetched) links_fetched,
+            SUM(parse_failures) parse_failures,
+            SUM(paywalled) paywalled,
+            SUM(indexed) indexed,
+            SUM(readability_score_total) readability_score_total,
+            MAX(last_success_at) last_success_at
+        FROM
+            content_source_ingestion_metrics
+        WHERE
+            metric_date >= $1
+        GROUP BY source_id
+    `
+	// Metrics from on or before the day a source was last quarantined are
+	// left out, so that a reactivated source isn't quarantined again for the
+	// failures that got it quarantined. Nothing is recorded while a source
+	// is inactive, so this also covers the time until it was reactivated.
+	getIngestionMetricsForQuarantineQuery = `
+        SELECT
+            m.source_id,
+            SUM(m.links_fetched) links_fetched,
+            SUM(m.parse_failures) parse_failures,
+            SUM(m.paywalled) paywalled,
+            SUM(m.indexed) indexed,
+            SUM(m.readability_score_total) readability_score_total,
+            MAX(m.last_success_at) last_success_at,
+            GREATEST($1::date, MAX(q.quarantined_at)::date + 1) window_start
+        FROM
+            content_source_ingestion_metrics m
+        LEFT JOIN (
+            SELECT source_id, MAX(created_at) quarantined_at FROM content_source_quarantine GROUP BY source_id
+        ) q ON q.source_id = m.source_id
+        WHERE
+            m.metric_date >= $1 AND
+            (q.quarantined_at IS NULL OR m.metric_date > q.quarantined_at::date)
+        GROUP BY m.source_id
+    `
+	getLastSuccessForSourcesQuery = "SELECT source_id, MAX(last_success_at) last_success_at FROM content_source_ingestion_metrics GROUP BY source_id"
+
+	insertSourceQuarantineQuery        = "INSERT INTO content_source_quarantine (source_id, links_fetched, parse_failures, window_start_date) VALUES ($1, $2, $3, $4)"
+	deactivateSourceQuery              = "UPDATE content_source SET is_active = FALSE, last_modified_at = timezone('utc', now()) WHERE _id = $1"
+	getAllSourceQuarantinesQuery       = "SELECT * FROM content_source_quarantine ORDER BY created_at DESC"
+	getSourceQuarantinesForSourceQuery = "SELECT * FROM content_source_quarantine WHERE source_id = $1 ORDER BY created_at DESC"
+
+	// A source is only considered for quarantine once it has
+	// attempted enough links in the window to make the failure rate meaningful.
+	minimumLinksFetchedForQuarantine = 25
+	quarantineParseFailureRate       = 0.8
+)
+
+type IngestionOutcome string
+
+const (
+	IngestionOutcomeParseFailure IngestionOutcome = "parse-failure"
+	IngestionOutcomeNotIndexed   IngestionOutcome = "not-indexed"
+	IngestionOutcomeIndexed      IngestionOutcome = "indexed"
+)
+
+type RecordIngestionOutcomeInput struct {
+	SourceID         SourceID
+	Outcome          IngestionOutcome
+	IsPaywalled      bool
+	ReadabilityScore *int64
+}
+
+// RecordIngestionOutcome records the result of processing a single
+// link for a source. Every call counts as one fetched link.
+func RecordIngestionOutcome(tx *sqlx.Tx, input RecordIngestionOutcomeInput) error {
+	var parseFailures, paywalled, indexed, readabilityScoreTotal int64
+	var lastSuccessAt *time.Time
+	switch input.Outcome {
+	case IngestionOutcomeParseFailure:
+		parseFailures = 1
+	case IngestionOutcomeIndexed:
+		indexed = 1
+		now := time.Now()
+		lastSuccessAt = &now
+		if input.ReadabilityScore != nil {
+			readabilityScoreTotal = *input.ReadabilityScore
+		}
+	}
+	if input.IsPaywalled {
+		paywalled = 1
+	}
+	if _, err := tx.Exec(upsertIngestionMetricsQuery, input.SourceID, 1, parseFailures, paywalled, indexed, readabilityScoreTotal, lastSuccessAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+type dbSourceIngestionMetrics struct {
+	SourceID              SourceID   `db:"source_id"`
+	LinksFetched          int64      `db:"links_fetched"`
+	ParseFailures         int64      `db:"parse_failures"`
+	Paywalled             int64      `db:"paywalled"`
+	Indexed               int64      `db:"indexed"`
+	ReadabilityScoreTotal int64      `db:"readability_score_total"`
+	LastSuccessAt         *time.Time `db:"last_success_at"`
+}
+
+func (d dbSourceIngestionMetrics) ToNonDB(windowStart time.Time) SourceIngestionMetrics {
+	var averageReadability *float64
+	if d.Indexed > 0 {
+		avg := float64(d.ReadabilityScoreTotal) / float64(d.Indexed)
+		averageReadability = &avg
+	}
+	return SourceIngestionMetrics{
+		SourceID:                d.SourceID,
+		WindowStart:             windowStart,
+		LinksFetched:            d.LinksFetched,
+		ParseFailures:           d.ParseFailures,
+		Paywalled:               d.Paywalled,
+		Indexed:                 d.Indexed,
+		AverageReadabilityScore: averageReadability,
+		LastSuccessAt:           d.LastSuccessAt,
+	}
+}
+
+type SourceIngestionMetrics struct {
+	SourceID                SourceID   `json:"source_id"`
+	WindowStart             time.Time  `json:"window_start"`
+	LinksFetched            int64      `json:"links_fetched"`
+	ParseFailures           int64      `json:"parse_failures"`
+	Paywalled               int64      `json:"paywalled"`
+	Indexed                 int64      `json:"indexed"`
+	AverageReadabilityScore *float64   `json:"average_readability_score,omitempty"`
+	LastSuccessAt           *time.Time `json:"last_success_at,omitempty"`
+}
+
+func (s SourceIngestionMetrics) ParseFailureRate() float64 {
+	if s.LinksFetched == 0 {
+		return 0
+	}
+	return float64(s.ParseFailures) / float64(s.LinksFetched)
+}
+
+// ShouldQuarantine returns true if the source has fetched enough links in the
+// window and nearly all of them failed to parse, which usually means that the
+// source changed its markup.
+func (s SourceIngestionMetrics) ShouldQuarantine() bool {
+	return s.LinksFetched >= minimumLinksFetchedForQuarantine && s.ParseFailureRate() >= quarantineParseFailureRate
+}
+
+// GetIngestionMetricsForWindow returns metrics aggregated over every day since
+// windowStart. The last success time is not limited to the window, so that
+// sources that have not succeeded in a long time are still visible.
+func GetIngestionMetricsForWindow(tx *sqlx.Tx, windowStart time.Time) ([]SourceIngestionMetrics, error) {
+	var matches []dbSourceIngestionMetrics
+	if err := tx.Select(&matches, getIngestionMetricsSinceQuery, windowStart); err != nil {
+		return nil, err
+	}
+	var lastSuccesses []struct {
+		SourceID      SourceID   `db:"source_id"`
+		LastSuccessAt *time.Time `db:"last_success_at"`
+	}
+	if err := tx.Select(&lastSuccesses, getLastSuccessForSourcesQuery); err != nil {
+		return nil, err
+	}
+	lastSuccessBySourceID := make(map[SourceID]*time.Time)
+	for _, l := range lastSuccesses {
+		lastSuccessBySourceID[l.SourceID] = l.LastSuccessAt
+	}
+	var out []SourceIngestionMetrics
+	for _, m := range matches {
+		metrics := m.ToNonDB(windowStart)
+		metrics.LastSuccessAt = lastSuccessBySourceID[m.SourceID]
+		out = append(out, metrics)
+	}
+	return out, nil
+}
+
+type dbSourceIngestionMetricsForQuarantine struct {
+	dbSourceIngestionMetrics
+	WindowStart time.Time `db:"window_start"`
+}
+
+// GetIngestionMetricsForQuarantine is the same as GetIngestionMetricsForWindow,
+// except that each source's window starts after the last time it was quarantined
+// if that was more recent than windowStart.
+func GetIngestionMetricsForQuarantine(tx *sqlx.Tx, windowStart time.Time) ([]SourceIngestionMetrics, error) {
+	var matches []dbSourceIngestionMetricsForQuarantine
+	if err := tx.Select(&matches, getIngestionMetricsForQuarantineQuery, windowStart); err != nil {
+		return nil, err
+	}
+	var out []SourceIngestionMetrics
+	for _, m := range matches {
+		out = append(out, m.ToNonDB(m.WindowStart))
+	}
+	return out, nil
+}
+
+type SourceQuarantineID string
+
+type dbSourceQuarantine struct {
+	ID              SourceQuarantineID `db:"_id"`
+	CreatedAt       time.Time          `db:"created_at"`
+	LastModifiedAt  time.Time          `db:"last_modified_at"`
+	SourceID        SourceID           `db:"source_id"`
+	LinksFetched    int64              `db:"links_fetched"`
+	ParseFailures   int64              `db:"parse_failures"`
+	WindowStartDate time.Time          `db:"window_start_date"`
+}
+
+func (d dbSourceQuarantine) ToNonDB() SourceQuarantine {
+	return SourceQuarantine{
+		ID:              d.ID,
+		SourceID:        d.SourceID,
+		QuarantinedAt:   d.CreatedAt,
+		LinksFetched:    d.LinksFetched,
+		ParseFailures:   d.ParseFailures,
+		WindowStartDate: d.WindowStartDate,
+	}
+}
+
+type SourceQuarantine struct {
+	ID              SourceQuarantineID `json:"id"`
+	SourceID        SourceID           `json:"source_id"`
+	QuarantinedAt   time.Time          `json:"quarantined_at"`
+	LinksFetched    int64              `json:"links_fetched"`
+	ParseFailures   int64              `json:"parse_failures"`
+	WindowStartDate time.Time          `json:"window_start_date"`
+}
+
+// QuarantineSource deactivates the source and keeps a record of the metrics
+// that caused it. Reactivating the source is done through UpdateSource.
+func QuarantineSource(tx *sqlx.Tx, metrics SourceIngestionMetrics) error {
+	if _, err := tx.Exec(deactivateSourceQuery, metrics.SourceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(insertSourceQuarantineQuery, metrics.SourceID, metrics.LinksFetched, metrics.ParseFailures, metrics.WindowStart); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetAllSourceQuarantines(tx *sqlx.Tx) ([]SourceQuarantine, error) {
+	var matches []dbSourceQuarantine
+	if err := tx.Select(&matches, getAllSourceQuarantinesQuery); err != nil {
+		return nil, err
+	}
+	var out []SourceQuarantine
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+func GetSourceQuarantinesForSource(tx *sqlx.Tx, sourceID SourceID) ([]SourceQuarantine, error) {
+	var matches []dbSourceQuarantine
+	if err := tx.Select(&matches, getSourceQuarantinesForSourceQuery, sourceID); err != nil {
+		return nil, err
+	}
+	var out []SourceQuarantine
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package content
+
+import "testing"
+
+func TestSourceIngestionMetricsShouldQuarantine(t *testing.T) {
+	type testCase struct {
+		LinksFetched   int64
+		ParseFailures  int64
+		ExpectedResult bool
+	}
+	tcs := []testCase{
+		{
+			LinksFetched:   0,
+			ParseFailures:  0,
+			ExpectedResult: false,
+		}, {
+			// Too few links to say anything about the source
+			LinksFetched:   10,
+			ParseFailures:  10,
+			ExpectedResult: false,
+		}, {
+			LinksFetched:   100,
+			ParseFailures:  79,
+			ExpectedResult: false,
+		}, {
+			LinksFetched:   100,
+			ParseFailures:  80,
+			ExpectedResult: true,
+		}, {
+			LinksFetched:   minimumLinksFetchedForQuarantine,
+			ParseFailures:  minimumLinksFetchedForQuarantine,
+			ExpectedResult: true,
+		},
+	}
+	for idx, tc := range tcs {
+		result := SourceIngestionMetrics{
+			LinksFetched:  tc.LinksFetched,
+			ParseFailures: tc.ParseFailures,
+		}.ShouldQuarantine()
+		if result != tc.ExpectedResult {
+			t.Errorf("Error on test %d: expected %t, but got %t", idx+1, tc.ExpectedResult, result)
+		}
+	}
+}
@@ -52,12 +52,13 @@ type IndexDocumentInput struct {
 	HasPaywall                         bool
 	LemmatizedDescription              *string
 	LemmatizedDescriptionIndexMappings []int
+	TopicClassifications               []TopicClassification
 }
 
 func AssignIDAndIndexDocument(c ctx.LogContext, input IndexDocumentInput) (*DocumentID, error) {
 	documentID := makeDocumentIndexForURL(input.URL)
 	ogMetadata := opengraph.GetBasicMetadata(input.Metadata)
-	if len(input.TopicIDs) != len(input.Topics) {
+	if len(input.TopicClassifications) == 0 && len(input.TopicIDs) != len(input.Topics) {
 		c.Warnf("Document %s has %d topic IDs, but %d topics", documentID, len(input.TopicIDs), len(input.Topics))
 	}
 	if input.SourceID == nil {
@@ -80,6 +81,7 @@ func AssignIDAndIndexDocument(c ctx.LogContext, input IndexDocumentInput) (*Docu
 		LemmatizedDescriptionIndexMappings: input.LemmatizedDescriptionIndexMappings,
 		SeedJobIngestTimestamp:             input.SeedJobIngestTimestamp,
 		HasPaywall:                         ptr.Bool(input.HasPaywall),
+		TopicClassifications:               input.TopicClassifications,
 		Metadata: Metadata{
 			Title:              ogMetadata.Title,
 			Image:              ogMetadata.ImageURL,
@@ -57,5 +57,9 @@ func CreateDocumentMappings() error {
 		makeDefaultTextWithKeywordField("source_id"),
 		makeDefaultTextWithKeywordField("topic_ids"),
 		makeDefaultTextWithKeywordField("topic_mapping_ids"),
+		esmapping.MakeObjectMapping("topic_classifications", []esmapping.Mapping{
+			makeDefaultTextWithKeywordField("topic_id"),
+			esmapping.MakeFloatMapping("confidence", esmapping.MappingOptions{}),
+		}),
 	})
 }
@@ -31,7 +31,10 @@ const (
 	// Version 8 adds source ID and topic mapping
 	Version8 Version = 8
 
-	CurrentDocumentVersion Version = Version8
+	// Version 9 adds classified topics
+	Version9 Version = 9
+
+	CurrentDocumentVersion Version = Version9
 )
 
 func (v Version) Ptr() *Version {
@@ -60,6 +63,11 @@ type Metadata struct {
 	PublicationTimeUTC *time.Time `json:"publication_time_utc,omitempty"`
 }
 
+type TopicClassification struct {
+	TopicID    content.TopicID `json:"topic_id"`
+	Confidence float64         `json:"confidence"`
+}
+
 type DocumentID string
 
 func (d DocumentID) Str() string {
@@ -83,6 +91,9 @@ type Document struct {
 	LemmatizedDescription              *string                  `json:"lemmatized_description,omitempty"`
 	HasPaywall                         *bool                    `json:"has_paywall"`
 	LemmatizedDescriptionIndexMappings []int                    `json:"lemmatized_description_index_mappings,omitempty"`
+	// Topics assigned by the topic classifier for documents that
+	// do not have a topic mapping. These are also included in TopicIDs.
+	TopicClassifications []TopicClassification `json:"topic_classifications,omitempty"`
 
 	// These will all be deprecated
 	Domain                   string                       `json:"domain"`
@@ -38,7 +38,7 @@ const maximumNumberOfTopicsPerDocument int64 = 4
 var validVersionsForLanguageCode = map[wordsmith.LanguageCode][]Version{
 	wordsmith.LanguageCodeSpanish: {
 		Version7,
-		Version8,
+		Version9,
 	},
 }
 
@@ -0,0 +1,44 @@
+package documents
+
+import (
+	"babblegraph/util/elastic/esquery"
+	"babblegraph/util/math/decimal"
+	"babblegraph/wordsmith"
+	"encoding/json"
+)
+
+// Elasticsearch does not return more than 10,000 results
+// for a single search without scrolling
+const maximumTopicClassifierTrainingDocuments int64 = 10000
+
+// GetDocumentsForTopicClassifierTraining returns the most recently ingested documents
+// that have topics from a topic mapping and a lemmatized description. Documents whose
+// topics came from the topic classifier are excluded so that the classifier is never
+// trained on its own output.
+func GetDocumentsForTopicClassifierTraining(languageCode wordsmith.LanguageCode) ([]Document, error) {
+	queryBuilder := esquery.NewBoolQueryBuilder()
+	queryBuilder.AddMust(esquery.Match("language_code", languageCode.Str()))
+	queryBuilder.AddFilter(esquery.Exists("topic_mapping_ids"))
+	queryBuilder.AddFilter(esquery.Exists("lemmatized_description"))
+	// Documents from a source that has a topic mapping can still be classified
+	// if the mapping has no topics, so having a mapping is not enough on its own
+	queryBuilder.AddMustNot(esquery.Exists("topic_classifications"))
+	versionRangeQueryBuilder := esquery.NewRangeQueryBuilderForFieldName("version")
+	versionRangeQueryBuilder.GreaterThanOrEqualToInt64(int64(Version8))
+	queryBuilder.AddMust(versionRangeQueryBuilder.BuildRangeQuery())
+	timestampSortBuilder := esquery.NewDescendingSortBuilder("seed_job_ingest_timestamp")
+	timestampSortBuilder.WithMissingValuesLast()
+	timestampSortBuilder.AsUnmappedTypeLong()
+	var docs []Document
+	if err := esquery.ExecuteSearchWithSize(documentIndex{}, queryBuilder.BuildBoolQuery(), esquery.NewOrderedSort(timestampSortBuilder.AsSort()), maximumTopicClassifierTrainingDocuments, func(source []byte, score decimal.Number) error {
+		var doc Document
+		if err := json.Unmarshal(source, &doc); err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
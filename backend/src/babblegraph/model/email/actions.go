@@ -1,7 +1,8 @@
 package email
 
 import (
-	"babblegraph/util/ses"
+	"babblegraph/util/emailsender"
+	"babblegraph/util/ptr"
 	"fmt"
 	"log"
 
@@ -14,17 +15,31 @@ type SendEmailWithHTMLBodyInput struct {
 	Subject         string
 	EmailSenderName *string
 	Body            string
+	TextBody        string
+
+	// These are only set for emails that users can unsubscribe from
+	ListName       *string
+	UnsubscribeURL *string
 }
 
-func SendEmailWithHTMLBody(tx *sqlx.Tx, cl *ses.Client, input SendEmailWithHTMLBodyInput) error {
+func SendEmailWithHTMLBody(tx *sqlx.Tx, cl emailsender.EmailSender, input SendEmailWithHTMLBodyInput) error {
 	if err := SetEmailRecordSentAtTime(tx, input.ID); err != nil {
 		return err
 	}
-	sesMessageID, err := cl.SendEmail(ses.SendEmailInput{
+	// This is recorded before sending so that a failure
+	// here doesn't roll back the transaction after SES has sent the email
+	if err := RecordEngagementEvent(tx, input.ID, EngagementEventTypeSent, nil); err != nil {
+		return err
+	}
+	sesMessageID, err := cl.SendEmail(emailsender.SendEmailInput{
 		Recipient:       input.EmailAddress,
 		HTMLBody:        input.Body,
+		TextBody:        input.TextBody,
 		Subject:         input.Subject,
 		EmailSenderName: input.EmailSenderName,
+		MessageID:       ptr.String(string(input.ID)),
+		ListName:        input.ListName,
+		UnsubscribeURL:  input.UnsubscribeURL,
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,133 @@
+package email
+
+import (
+	"babblegraph/model/users"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Engagement events are written for every email that goes out,
+// so that opens, clicks, unsubscribes and SES notifications can be
+// aggregated in one place instead of across each feature's tables.
+
+type EngagementEventType string
+
+const (
+	EngagementEventTypeSent         EngagementEventType = "sent"
+	EngagementEventTypeDelivered    EngagementEventType = "delivered"
+	EngagementEventTypeOpened       EngagementEventType = "opened"
+	EngagementEventTypeClicked      EngagementEventType = "clicked"
+	EngagementEventTypeUnsubscribed EngagementEventType = "unsubscribed"
+	EngagementEventTypeBounced      EngagementEventType = "bounced"
+
+	EngagementEventTypeDeliveryDelayed EngagementEventType = "delivery-delayed"
+	EngagementEventTypeRejected        EngagementEventType = "rejected"
+)
+
+// EngagementSection is the part of a newsletter that an event came from
+type EngagementSection string
+
+const (
+	EngagementSectionDocument      EngagementSection = "document"
+	EngagementSectionPodcast       EngagementSection = "podcast"
+	EngagementSectionAdvertisement EngagementSection = "advertisement"
+)
+
+func (e EngagementSection) Ptr() *EngagementSection {
+	return &e
+}
+
+const (
+	insertEngagementEventQuery = `INSERT INTO
+        email_engagement_events (email_record_id, user_id, event_type, section)
+    SELECT _id, user_id, $2, $3 FROM email_records WHERE _id = $1`
+	insertEngagementEventForSESMessageIDQuery = `INSERT INTO
+        email_engagement_events (email_record_id, user_id, event_type, section)
+    SELECT _id, user_id, $2, NULL FROM email_records WHERE ses_message_id = $1`
+	insertUnsubscribeEngagementEventQuery = `INSERT INTO
+        email_engagement_events (email_record_id, user_id, event_type, section)
+    SELECT _id, user_id, $3, NULL FROM email_records
+        WHERE user_id = $1 AND type = $2 AND sent_at IS NOT NULL
+        ORDER BY sent_at DESC
+        LIMIT 1`
+
+	getNewsletterEngagementByDayQuery = `SELECT
+        date_trunc('day', r.sent_at) AS day,
+        e.event_type,
+        COUNT(DISTINCT e.email_record_id) AS number_of_emails
+    FROM email_engagement_events e
+    JOIN email_records r ON e.email_record_id = r._id
+    WHERE r.type = $1 AND r.sent_at >= $2 AND r.sent_at < $3
+    GROUP BY 1, 2`
+	getNewsletterEngagementBySectionQuery = `SELECT
+        e.section,
+        e.event_type,
+        COUNT(*) AS number_of_events,
+        COUNT(DISTINCT e.email_record_id) AS number_of_emails
+    FROM email_engagement_events e
+    JOIN email_records r ON e.email_record_id = r._id
+    WHERE r.type = $1 AND r.sent_at >= $2 AND r.sent_at < $3 AND e.section IS NOT NULL
+    GROUP BY 1, 2`
+)
+
+func RecordEngagementEvent(tx *sqlx.Tx, id ID, eventType EngagementEventType, section *EngagementSection) error {
+	if _, err := tx.Exec(insertEngagementEventQuery, id, eventType, section); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RecordEngagementEventForSESMessageID returns false
+// if no email was sent with the SES message ID
+func RecordEngagementEventForSESMessageID(tx *sqlx.Tx, sesMessageID string, eventType EngagementEventType) (bool, error) {
+	res, err := tx.Exec(insertEngagementEventForSESMessageIDQuery, sesMessageID, eventType)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
+
+// Unsubscribe links aren't tied to a specific email,
+// so unsubscribes are attributed to the last newsletter the user was sent
+func RecordUnsubscribeEngagementEvent(tx *sqlx.Tx, userID users.UserID) error {
+	if _, err := tx.Exec(insertUnsubscribeEngagementEventQuery, userID, EmailTypeDaily, EngagementEventTypeUnsubscribed); err != nil {
+		return err
+	}
+	return nil
+}
+
+type NewsletterEngagementForDay struct {
+	Day            time.Time           `db:"day"`
+	EventType      EngagementEventType `db:"event_type" json:"event_type"`
+	NumberOfEmails int64               `db:"number_of_emails" json:"number_of_emails"`
+}
+
+// GetNewsletterEngagementByDay returns the number of newsletters sent
+// on each day in [from, to) that had at least one event of each type
+func GetNewsletterEngagementByDay(tx *sqlx.Tx, from, to time.Time) ([]NewsletterEngagementForDay, error) {
+	var matches []NewsletterEngagementForDay
+	if err := tx.Select(&matches, getNewsletterEngagementByDayQuery, EmailTypeDaily, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+type NewsletterEngagementForSection struct {
+	Section        EngagementSection   `db:"section" json:"section"`
+	EventType      EngagementEventType `db:"event_type" json:"event_type"`
+	NumberOfEvents int64               `db:"number_of_events" json:"number_of_events"`
+	NumberOfEmails int64               `db:"number_of_emails" json:"number_of_emails"`
+}
+
+func GetNewsletterEngagementBySection(tx *sqlx.Tx, from, to time.Time) ([]NewsletterEngagementForSection, error) {
+	var matches []NewsletterEngagementForSection
+	if err := tx.Select(&matches, getNewsletterEngagementBySectionQuery, EmailTypeDaily, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
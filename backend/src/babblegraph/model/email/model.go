@@ -13,6 +13,7 @@ type dbEmail struct {
 	UserID        users.UserID `db:"user_id"`
 	SentAt        time.Time    `db:"sent_at"`
 	FirstOpenedAt *time.Time   `db:"first_opened_at"`
+	DeliveredAt   *time.Time   `db:"delivered_at"`
 	Type          EmailType    `db:"type"`
 }
 
@@ -28,11 +29,21 @@ const (
 	EmailTypeTrialEndingSoon               EmailType = "trial-ending-soon"
 	EmailTypePremiumSubscriptionCanceled   EmailType = "premium-subscription-canceled"
 	EmailTypePaymentFailureNotification    EmailType = "payment-failure-notification"
+	EmailTypeDunningPaymentFailed          EmailType = "dunning-payment-failed"
+	EmailTypeDunningStillFailing           EmailType = "dunning-still-failing"
+	EmailTypeDunningFinalNotice            EmailType = "dunning-final-notice"
 
 	EmailTypeAdminTwoFactorAuthenticationCode EmailType = "admin-two-factor-authentication-code"
 
 	EmailTypeGoodbye EmailType = "goodbye"
 
+	EmailTypeReengagement       EmailType = "reengagement"
+	EmailTypeReengagementSunset EmailType = "reengagement-sunset"
+
+	EmailTypeUserDataRequest EmailType = "user-data-request"
+
+	EmailTypeGroupLicenseSeat EmailType = "group-license-seat"
+
 	// Deprecated types
 	EmailTypeUserFeedbackDEPRECATED                EmailType = "user-feedback"
 	EmailTypePrivacyPolicyUpdateJune2021DEPRECATED           = "privacy-policy-update-june-2021"
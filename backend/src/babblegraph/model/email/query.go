@@ -2,6 +2,7 @@ package email
 
 import (
 	"babblegraph/model/users"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -66,3 +67,19 @@ func SetEmailRecordSentAtTime(tx *sqlx.Tx, id ID) error {
 	}
 	return nil
 }
+
+const setEmailRecordDeliveredForSESMessageIDQuery = "UPDATE email_records SET delivered_at = $1 WHERE ses_message_id = $2 AND delivered_at IS NULL"
+
+// SetEmailRecordDeliveredForSESMessageID returns false if there is no
+// undelivered email with the SES message ID
+func SetEmailRecordDeliveredForSESMessageID(tx *sqlx.Tx, sesMessageID string, deliveredAt time.Time) (bool, error) {
+	res, err := tx.Exec(setEmailRecordDeliveredForSESMessageIDQuery, deliveredAt.UTC(), sesMessageID)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
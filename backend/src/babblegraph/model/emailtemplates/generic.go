@@ -3,8 +3,10 @@ package emailtemplates
 import "babblegraph/model/email"
 
 const (
-	genericEmailTemplateFilename        string = "generic_email_with_optional_action_link_template.html"
-	genericNonUserEmailTemplateFilename string = "generic_non_user_template.html"
+	genericEmailTemplateFilename            string = "generic_email_with_optional_action_link_template.html"
+	genericEmailTextTemplateFilename        string = "generic_email_with_optional_action_link_template.txt"
+	genericNonUserEmailTemplateFilename     string = "generic_non_user_template.html"
+	genericNonUserEmailTextTemplateFilename string = "generic_non_user_template.txt"
 )
 
 type GenericTemplate struct {
@@ -25,7 +27,7 @@ type GenericEmailAction struct {
 	ButtonText string
 }
 
-type MakeGenericUserEmailHTMLInput struct {
+type MakeGenericUserEmailInput struct {
 	EmailRecordID      email.ID
 	UserAccessor       UserAccessor
 	EmailTitle         string
@@ -36,12 +38,12 @@ type MakeGenericUserEmailHTMLInput struct {
 	ExcludeFooter      bool
 }
 
-func MakeGenericUserEmailHTML(input MakeGenericUserEmailHTMLInput) (*string, error) {
+func MakeGenericUserEmail(input MakeGenericUserEmailInput) (*RenderedEmail, error) {
 	baseEmailTemplate, err := createBaseEmailTemplate(input.EmailRecordID, input.UserAccessor)
 	if err != nil {
 		return nil, err
 	}
-	return openAndExecuteTemplate(genericEmailTemplateFilename, genericEmailWithOptionalActionTemplate{
+	return renderEmail(genericEmailTemplateFilename, genericEmailTextTemplateFilename, genericEmailWithOptionalActionTemplate{
 		BaseEmailTemplate: *baseEmailTemplate,
 		GenericTemplate: GenericTemplate{
 			EmailTitle:       input.EmailTitle,
@@ -53,7 +55,7 @@ func MakeGenericUserEmailHTML(input MakeGenericUserEmailHTMLInput) (*string, err
 	})
 }
 
-type MakeGenericEmailHTMLInput struct {
+type MakeGenericEmailInput struct {
 	EmailTitle         string
 	PreheaderText      string
 	BeforeParagraphs   []string
@@ -61,8 +63,8 @@ type MakeGenericEmailHTMLInput struct {
 	AfterParagraphs    []string
 }
 
-func MakeGenericEmailHTML(input MakeGenericEmailHTMLInput) (*string, error) {
-	return openAndExecuteTemplate(genericNonUserEmailTemplateFilename, GenericTemplate{
+func MakeGenericEmail(input MakeGenericEmailInput) (*RenderedEmail, error) {
+	return renderEmail(genericNonUserEmailTemplateFilename, genericNonUserEmailTextTemplateFilename, GenericTemplate{
 		EmailTitle:       input.EmailTitle,
 		PreheaderText:    input.PreheaderText,
 		BeforeParagraphs: input.BeforeParagraphs,
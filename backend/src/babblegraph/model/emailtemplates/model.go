@@ -1,8 +1,22 @@
 package emailtemplates
 
+// Every email is rendered from an HTML template and a plain text
+// template with the same inputs, since some mail clients only show
+// the text/plain part of the message
+type RenderedEmail struct {
+	HTMLBody string
+	TextBody string
+}
+
 // All email templates should use this
 type BaseEmailTemplate struct {
 	SubscriptionManagementLink string
 	HeroImageURL               string
 	HomePageURL                string
+	Footer                     EmailFooterCopy
+}
+
+type EmailFooterCopy struct {
+	ManageSubscriptionText     string
+	ManageSubscriptionLinkText string
 }
@@ -2,15 +2,19 @@ package emailtemplates
 
 import (
 	"babblegraph/model/email"
+	"babblegraph/model/localization"
 	"babblegraph/model/newsletter"
-	"babblegraph/util/ptr"
 	"html/template"
 	"strings"
 )
 
 const (
-	newsletterTemplateFilename         = "newsletter_template.html"
-	newsletterTemplateVersion2Filename = "version2_newsletter_template.html"
+	newsletterTemplateFilename             = "newsletter_template.html"
+	newsletterTextTemplateFilename         = "newsletter_template.txt"
+	newsletterTemplateVersion2Filename     = "version2_newsletter_template.html"
+	newsletterTextTemplateVersion2Filename = "version2_newsletter_template.txt"
+	weeklyDigestTemplateFilename           = "weekly_digest_template.html"
+	weeklyDigestTextTemplateFilename       = "weekly_digest_template.txt"
 )
 
 type newsletterTemplate struct {
@@ -18,13 +22,13 @@ type newsletterTemplate struct {
 	Body newsletter.NewsletterBody
 }
 
-type MakeNewsletterHTMLInput struct {
+type MakeNewsletterInput struct {
 	EmailRecordID email.ID
 	UserAccessor  UserAccessor
 	Body          newsletter.NewsletterBody
 }
 
-func MakeNewsletterHTML(input MakeNewsletterHTMLInput) (*string, error) {
+func MakeNewsletter(input MakeNewsletterInput) (*RenderedEmail, error) {
 	baseEmailTemplate, err := createBaseEmailTemplate(input.EmailRecordID, input.UserAccessor)
 	if err != nil {
 		return nil, err
@@ -37,34 +41,70 @@ func MakeNewsletterHTML(input MakeNewsletterHTMLInput) (*string, error) {
 	if err != nil {
 		return nil, err
 	}
-	var b strings.Builder
-	if err := t.Execute(&b, newsletterTemplate{
+	body := newsletterTemplate{
 		BaseEmailTemplate: *baseEmailTemplate,
 		Body:              input.Body,
-	}); err != nil {
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, body); err != nil {
 		return nil, err
 	}
-	return ptr.String(b.String()), nil
+	textBody, err := openAndExecuteTemplate(newsletterTextTemplateFilename, body)
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedEmail{
+		HTMLBody: b.String(),
+		TextBody: *textBody,
+	}, nil
 }
 
 type newsletterVersion2Template struct {
 	BaseEmailTemplate
-	Body newsletter.NewsletterVersion2Body
+	Body          newsletter.NewsletterVersion2Body
+	ViewInBrowser *GenericEmailAction
 }
 
-type MakeNewsletterVersion2HTMLInput struct {
+type weeklyDigestTemplate struct {
+	BaseEmailTemplate
+	Body          newsletter.WeeklyDigestBody
+	ViewInBrowser *GenericEmailAction
+}
+
+type MakeNewsletterVersion2Input struct {
 	EmailRecordID email.ID
 	UserAccessor  UserAccessor
 	Body          newsletter.NewsletterVersion2Body
+	// If this is set, the weekly digest is rendered instead of Body
+	WeeklyDigestBody *newsletter.WeeklyDigestBody
+
+	// This is only set for newsletters that are sent,
+	// since previews are not stored anywhere
+	ViewInBrowserLink *string
 }
 
-func MakeNewsletterVersion2HTML(input MakeNewsletterVersion2HTMLInput) (*string, error) {
+func MakeNewsletterVersion2(input MakeNewsletterVersion2Input) (*RenderedEmail, error) {
 	baseEmailTemplate, err := createBaseEmailTemplate(input.EmailRecordID, input.UserAccessor)
 	if err != nil {
 		return nil, err
 	}
-	return openAndExecuteTemplate(newsletterTemplateVersion2Filename, newsletterVersion2Template{
+	var viewInBrowser *GenericEmailAction
+	if input.ViewInBrowserLink != nil {
+		viewInBrowser = &GenericEmailAction{
+			Link:       *input.ViewInBrowserLink,
+			ButtonText: localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyEmailViewInBrowser, nil),
+		}
+	}
+	if input.WeeklyDigestBody != nil {
+		return renderEmail(weeklyDigestTemplateFilename, weeklyDigestTextTemplateFilename, weeklyDigestTemplate{
+			BaseEmailTemplate: *baseEmailTemplate,
+			Body:              *input.WeeklyDigestBody,
+			ViewInBrowser:     viewInBrowser,
+		})
+	}
+	return renderEmail(newsletterTemplateVersion2Filename, newsletterTextTemplateVersion2Filename, newsletterVersion2Template{
 		BaseEmailTemplate: *baseEmailTemplate,
 		Body:              input.Body,
+		ViewInBrowser:     viewInBrowser,
 	})
 }
@@ -19,7 +19,7 @@ func TestCreateNewsletterTemplate(t *testing.T) {
 		SetTopicsLink:     ptr.String("babblegraph.com/topics"),
 		ReinforcementLink: "babblegraph.com/reinforce",
 	}
-	html, err := MakeNewsletterHTML(MakeNewsletterHTMLInput{
+	rendered, err := MakeNewsletter(MakeNewsletterInput{
 		EmailRecordID: email.NewEmailRecordID(),
 		UserAccessor:  userAccessor,
 		Body:          testNewsletter,
@@ -27,13 +27,13 @@ func TestCreateNewsletterTemplate(t *testing.T) {
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
-	if len(*html) == 0 {
+	if len(rendered.HTMLBody) == 0 {
 		t.Fatalf("Got empty body")
 	}
-	if !strings.Contains(*html, "babblegraph.com/topics") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/topics") {
 		t.Errorf("Expected topics link")
 	}
-	if !strings.Contains(*html, "babblegraph.com/reinforce") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/reinforce") {
 		t.Errorf("Expected reinforcement link")
 	}
 }
@@ -77,7 +77,7 @@ func TestCreateNewsletterWithLinksTemplate(t *testing.T) {
 			},
 		},
 	}
-	html, err := MakeNewsletterHTML(MakeNewsletterHTMLInput{
+	rendered, err := MakeNewsletter(MakeNewsletterInput{
 		EmailRecordID: email.NewEmailRecordID(),
 		UserAccessor:  userAccessor,
 		Body:          testNewsletter,
@@ -85,27 +85,41 @@ func TestCreateNewsletterWithLinksTemplate(t *testing.T) {
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
-	if len(*html) == 0 {
+	if len(rendered.HTMLBody) == 0 {
 		t.Fatalf("Got empty body")
 	}
-	if !strings.Contains(*html, "babblegraph.com/topics") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/topics") {
 		t.Errorf("Expected topics link")
 	}
-	if !strings.Contains(*html, "babblegraph.com/reinforce") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/reinforce") {
 		t.Errorf("Expected reinforcement link")
 	}
-	if !strings.Contains(*html, "Test Link") {
+	if !strings.Contains(rendered.HTMLBody, "Test Link") {
 		t.Errorf("Expected title")
 	}
-	if !strings.Contains(*html, "Test Description") {
+	if !strings.Contains(rendered.HTMLBody, "Test Description") {
 		t.Errorf("Expected description")
 	}
-	if !strings.Contains(*html, "Test Episode") {
+	if !strings.Contains(rendered.HTMLBody, "Test Episode") {
 		t.Errorf("Expected episode title")
 	}
-	if !strings.Contains(*html, "This episode is a test") {
+	if !strings.Contains(rendered.HTMLBody, "This episode is a test") {
 		t.Errorf("Expected episode description")
 	}
+	for _, expected := range []string{
+		"babblegraph.com/topics",
+		"Test Link",
+		"Test Description",
+		"Test Episode",
+		"This episode is a test",
+	} {
+		if !strings.Contains(rendered.TextBody, expected) {
+			t.Errorf("Expected plain text newsletter to contain %s", expected)
+		}
+	}
+	if strings.Contains(rendered.TextBody, "<") {
+		t.Errorf("Expected plain text newsletter not to contain HTML, but got %s", rendered.TextBody)
+	}
 }
 
 func TestCreateNewsletterNoPodcastLinksTemplate(t *testing.T) {
@@ -138,7 +152,7 @@ func TestCreateNewsletterNoPodcastLinksTemplate(t *testing.T) {
 			},
 		},
 	}
-	html, err := MakeNewsletterHTML(MakeNewsletterHTMLInput{
+	rendered, err := MakeNewsletter(MakeNewsletterInput{
 		EmailRecordID: email.NewEmailRecordID(),
 		UserAccessor:  userAccessor,
 		Body:          testNewsletter,
@@ -146,19 +160,19 @@ func TestCreateNewsletterNoPodcastLinksTemplate(t *testing.T) {
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
-	if len(*html) == 0 {
+	if len(rendered.HTMLBody) == 0 {
 		t.Fatalf("Got empty body")
 	}
-	if !strings.Contains(*html, "babblegraph.com/topics") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/topics") {
 		t.Errorf("Expected topics link")
 	}
-	if !strings.Contains(*html, "babblegraph.com/reinforce") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/reinforce") {
 		t.Errorf("Expected reinforcement link")
 	}
-	if !strings.Contains(*html, "Test Link") {
+	if !strings.Contains(rendered.HTMLBody, "Test Link") {
 		t.Errorf("Expected title")
 	}
-	if !strings.Contains(*html, "Test Description") {
+	if !strings.Contains(rendered.HTMLBody, "Test Description") {
 		t.Errorf("Expected description")
 	}
 }
@@ -201,7 +215,7 @@ func TestCreateNewsletterWithAdsTemplate(t *testing.T) {
 			AdvertisementPolicyLink: "babblegraph.com/advertising-policy-link",
 		},
 	}
-	html, err := MakeNewsletterHTML(MakeNewsletterHTMLInput{
+	rendered, err := MakeNewsletter(MakeNewsletterInput{
 		EmailRecordID: email.NewEmailRecordID(),
 		UserAccessor:  userAccessor,
 		Body:          testNewsletter,
@@ -209,19 +223,120 @@ func TestCreateNewsletterWithAdsTemplate(t *testing.T) {
 	if err != nil {
 		t.Fatalf(err.Error())
 	}
-	if len(*html) == 0 {
+	if len(rendered.HTMLBody) == 0 {
 		t.Fatalf("Got empty body")
 	}
-	if !strings.Contains(*html, "babblegraph.com/topics") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/topics") {
 		t.Errorf("Expected topics link")
 	}
-	if !strings.Contains(*html, "babblegraph.com/reinforce") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/reinforce") {
 		t.Errorf("Expected reinforcement link")
 	}
-	if !strings.Contains(*html, "babblegraph.com/this-is-a-test-advertisement") {
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/this-is-a-test-advertisement") {
 		t.Errorf("Expected advertising link")
 	}
-	if !strings.Contains(*html, "This is an ad") {
+	if !strings.Contains(rendered.HTMLBody, "This is an ad") {
 		t.Errorf("Expected description")
 	}
 }
+
+func TestCreateWeeklyDigestTemplate(t *testing.T) {
+	userAccessor := &testUserAccessor{
+		userHasAccount: false,
+		userID:         users.UserID("12345"),
+	}
+	weeklyDigest := newsletter.WeeklyDigestBody{
+		Heading: "Your week in the news",
+		TopicSections: []newsletter.Section{
+			{
+				Title: "Test Topic",
+				FocusContent: &newsletter.SectionFocusContent{
+					Title:       "Test Focus Link",
+					ImageURL:    "babblegraph.com/focus.jpg",
+					Description: "Test Focus Description",
+					URL:         "babblegraph.com/focus",
+				},
+				OtherLinks: []newsletter.SectionLink{
+					{
+						Title: "Test Other Link",
+						URL:   "babblegraph.com/other",
+					},
+				},
+				OtherLinksTitle: ptr.String("More from this week"),
+			},
+		},
+		VocabularySection: &newsletter.Section{
+			Title: "Your words in the news this week",
+			OtherLinks: []newsletter.SectionLink{
+				{
+					Title:    "hablar",
+					URL:      "babblegraph.com/hablar",
+					BodyText: ptr.String("Appeared in 3 articles this week"),
+				},
+			},
+		},
+		AccountSection: newsletter.Section{
+			Title: "Links to manage your subscription",
+			OtherLinks: []newsletter.SectionLink{
+				{
+					Title: "Change your preferences",
+					URL:   "babblegraph.com/preferences",
+				},
+			},
+		},
+	}
+	rendered, err := MakeNewsletterVersion2(MakeNewsletterVersion2Input{
+		EmailRecordID:    email.NewEmailRecordID(),
+		UserAccessor:     userAccessor,
+		WeeklyDigestBody: &weeklyDigest,
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	for _, expected := range []string{
+		"Your week in the news",
+		"Test Topic",
+		"Test Focus Link",
+		"Test Focus Description",
+		"babblegraph.com/other",
+		"hablar",
+		"Appeared in 3 articles this week",
+		"babblegraph.com/preferences",
+	} {
+		if !strings.Contains(rendered.HTMLBody, expected) {
+			t.Errorf("Expected weekly digest to contain %s", expected)
+		}
+		if !strings.Contains(rendered.TextBody, expected) {
+			t.Errorf("Expected plain text weekly digest to contain %s", expected)
+		}
+	}
+}
+
+func TestCreateGenericUserEmailTemplate(t *testing.T) {
+	userAccessor := &testUserAccessor{
+		userHasAccount: false,
+		userID:         users.UserID("12345"),
+	}
+	rendered, err := MakeGenericUserEmail(MakeGenericUserEmailInput{
+		EmailRecordID:    email.NewEmailRecordID(),
+		UserAccessor:     userAccessor,
+		EmailTitle:       "Test Title",
+		PreheaderText:    "Test Preheader",
+		BeforeParagraphs: []string{"First paragraph"},
+		GenericEmailAction: &GenericEmailAction{
+			Link:       "babblegraph.com/action",
+			ButtonText: "Do the thing",
+		},
+		AfterParagraphs: []string{"Last paragraph"},
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	expectedText := "First paragraph\n\nDo the thing: babblegraph.com/action\n\nLast paragraph\n\n"
+	if !strings.HasPrefix(rendered.TextBody, expectedText) {
+		t.Errorf("Expected plain text email to start with %q, but got %q", expectedText, rendered.TextBody)
+	}
+	if !strings.Contains(rendered.HTMLBody, "babblegraph.com/action") {
+		t.Errorf("Expected action link")
+	}
+}
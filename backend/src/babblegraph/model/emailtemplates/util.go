@@ -2,6 +2,7 @@ package emailtemplates
 
 import (
 	"babblegraph/model/email"
+	"babblegraph/model/localization"
 	"babblegraph/model/routes"
 	"babblegraph/util/env"
 	"babblegraph/util/ptr"
@@ -39,6 +40,25 @@ func createBaseEmailTemplate(emailRecordID email.ID, userAccessor UserAccessor)
 		SubscriptionManagementLink: *subscriptionManagementLink,
 		HeroImageURL:               *heroImageURL,
 		HomePageURL:                routes.MustGetHomePageURL(),
+		Footer: EmailFooterCopy{
+			ManageSubscriptionText:     localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyEmailFooterManageSubscriptionText, nil),
+			ManageSubscriptionLinkText: localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyEmailFooterManageSubscriptionLink, nil),
+		},
+	}, nil
+}
+
+func renderEmail(htmlTemplateFileName, textTemplateFileName string, body interface{}) (*RenderedEmail, error) {
+	htmlBody, err := openAndExecuteTemplate(htmlTemplateFileName, body)
+	if err != nil {
+		return nil, err
+	}
+	textBody, err := openAndExecuteTemplate(textTemplateFileName, body)
+	if err != nil {
+		return nil, err
+	}
+	return &RenderedEmail{
+		HTMLBody: *htmlBody,
+		TextBody: *textBody,
 	}, nil
 }
 
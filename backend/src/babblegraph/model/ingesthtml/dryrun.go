@@ -0,0 +1,108 @@
+package ingesthtml
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/model/contenttopics"
+	"babblegraph/model/links2"
+	"babblegraph/model/textprocessing"
+	"babblegraph/model/urltopicmapping"
+	"babblegraph/util/ctx"
+	"babblegraph/util/database"
+	"babblegraph/util/opengraph"
+	"babblegraph/util/ptr"
+	"babblegraph/util/urlparser"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type DryRunWebsiteHTML1LinkInput struct {
+	URL          string
+	Source       content.Source
+	SourceFilter *content.SourceFilter
+}
+
+type DryRunWebsiteHTML1LinkOutput struct {
+	URL                   string                       `json:"url"`
+	IsSeedURL             bool                         `json:"is_seed_url"`
+	BodyText              string                       `json:"body_text"`
+	Metadata              map[string]string            `json:"metadata"`
+	Language              *string                      `json:"language,omitempty"`
+	PageType              *string                      `json:"page_type,omitempty"`
+	IsPaywalled           bool                         `json:"is_paywalled"`
+	Links                 []string                     `json:"links"`
+	LinksForKnownSources  []string                     `json:"links_for_known_sources"`
+	ReadabilityScore      *int64                       `json:"readability_score,omitempty"`
+	LemmatizedDescription *string                      `json:"lemmatized_description,omitempty"`
+	TopicsForURL          []contenttopics.ContentTopic `json:"topics_for_url"`
+	TopicIDs              []content.TopicID            `json:"topic_ids"`
+	TextProcessingError   *string                      `json:"text_processing_error,omitempty"`
+}
+
+// DryRunWebsiteHTML1Link runs a link through the same steps as content ingestion
+// using the given source and source filter, but does not insert links, index the document
+// or mark anything as fetched. This is used to check a source configuration before saving it.
+func DryRunWebsiteHTML1Link(c ctx.LogContext, input DryRunWebsiteHTML1LinkInput) (*DryRunWebsiteHTML1LinkOutput, error) {
+	if input.Source.IngestStrategy != content.IngestStrategyWebsiteHTML1 {
+		return nil, fmt.Errorf("Dry runs are only supported for ingest strategy %s", content.IngestStrategyWebsiteHTML1)
+	}
+	p := urlparser.ParseURL(input.URL)
+	if p == nil {
+		return nil, fmt.Errorf("Invalid URL")
+	}
+	parsedHTMLPage, err := ProcessURL(ProcessURLInput{
+		URL:          input.URL,
+		Source:       input.Source,
+		SourceFilter: input.SourceFilter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing html: %s", err.Error())
+	}
+	out := &DryRunWebsiteHTML1LinkOutput{
+		URL:         p.URL,
+		BodyText:    parsedHTMLPage.BodyText,
+		Metadata:    parsedHTMLPage.Metadata,
+		Language:    parsedHTMLPage.Language,
+		PageType:    parsedHTMLPage.PageType,
+		IsPaywalled: parsedHTMLPage.IsPaywalled,
+		Links:       parsedHTMLPage.Links,
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		out.IsSeedURL, err = content.IsParsedURLASeedURL(tx, *p)
+		if err != nil {
+			return err
+		}
+		linksForKnownSources, err := links2.FilterLinksForKnownSources(tx, parsedHTMLPage.Links)
+		if err != nil {
+			return err
+		}
+		for _, l := range linksForKnownSources {
+			out.LinksForKnownSources = append(out.LinksForKnownSources, l.URL.URL)
+		}
+		out.TopicsForURL, _, out.TopicIDs, err = urltopicmapping.LookupTopicsForURL(c, tx, input.URL)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	var description *string
+	if d, ok := parsedHTMLPage.Metadata[opengraph.DescriptionTag.Str()]; ok {
+		description = ptr.String(d)
+	}
+	textMetadata, err := textprocessing.ProcessText(textprocessing.ProcessTextInput{
+		BodyText:     parsedHTMLPage.BodyText,
+		Description:  description,
+		LanguageCode: input.Source.LanguageCode,
+	})
+	if err != nil {
+		// Ingestion skips the document in this case, so it is
+		// reported back instead of failing the whole dry run
+		out.TextProcessingError = ptr.String(err.Error())
+		return out, nil
+	}
+	out.ReadabilityScore = ptr.Int64(textMetadata.ReadabilityScore.ToInt64Rounded())
+	if textMetadata.LemmatizedDescription != nil {
+		out.LemmatizedDescription = ptr.String(textMetadata.LemmatizedDescription.LemmatizedText)
+	}
+	return out, nil
+}
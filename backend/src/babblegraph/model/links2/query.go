@@ -21,6 +21,30 @@ type URLWithSourceMapping struct {
 	SourceID content.SourceID
 }
 
+// FilterLinksForKnownSources drops any URLs that aren't for the domain of a source
+func FilterLinksForKnownSources(tx *sqlx.Tx, urls []string) ([]URLWithSourceMapping, error) {
+	var filteredURLs []URLWithSourceMapping
+	for _, u := range urls {
+		parsedURL := urlparser.ParseURL(u)
+		if parsedURL == nil {
+			continue
+		}
+		sourceID, _, err := content.LookupSourceIDForDomain(tx, parsedURL.Domain)
+		switch {
+		case err != nil:
+			return nil, err
+		case sourceID == nil:
+			continue
+		default:
+			filteredURLs = append(filteredURLs, URLWithSourceMapping{
+				URL:      *parsedURL,
+				SourceID: *sourceID,
+			})
+		}
+	}
+	return filteredURLs, nil
+}
+
 func InsertLinksWithSourceID(tx *sqlx.Tx, urls []URLWithSourceMapping) error {
 	queryBuilder, err := database.NewBulkInsertQueryBuilder("links2", "url_identifier", "domain", "url", "source_id")
 	if err != nil {
@@ -0,0 +1,148 @@
+package localization
+
+const (
+	// Shared by all emails sent to users
+
+	MessageKeyEmailGreeting                     MessageKey = "email.greeting"
+	MessageKeyEmailQuestionsSignOff             MessageKey = "email.questions_sign_off"
+	MessageKeyEmailThanksForTryingSignOff       MessageKey = "email.thanks_for_trying_sign_off"
+	MessageKeyEmailFooterManageSubscriptionText MessageKey = "email.footer.manage_subscription_text"
+	MessageKeyEmailFooterManageSubscriptionLink MessageKey = "email.footer.manage_subscription_link"
+	MessageKeyEmailAddPaymentMethodButton       MessageKey = "email.add_payment_method_button"
+	MessageKeyEmailViewInBrowser                MessageKey = "email.view_in_browser"
+
+	// Billing
+
+	MessageKeyBillingPricePerMonth MessageKey = "billing.price_per_month"
+	MessageKeyBillingPricePerYear  MessageKey = "billing.price_per_year"
+
+	// Newsletter
+
+	MessageKeyNewsletterSpotlightSectionTitle           MessageKey = "newsletter.spotlight_section.title"
+	MessageKeyNewsletterAdvertisementSectionTitle       MessageKey = "newsletter.advertisement_section.title"
+	MessageKeyNewsletterAdvertisementPremiumLinkTitle   MessageKey = "newsletter.advertisement_section.premium_link_title"
+	MessageKeyNewsletterAdvertisementPremiumLinkBody    MessageKey = "newsletter.advertisement_section.premium_link_body"
+	MessageKeyNewsletterAdvertisingDisclaimerText       MessageKey = "newsletter.advertising_disclaimer.text"
+	MessageKeyNewsletterAdvertisingDisclaimerLinkText   MessageKey = "newsletter.advertising_disclaimer.link_text"
+	MessageKeyNewsletterAddPaymentMethodPreText         MessageKey = "newsletter.add_payment_method.pre_text"
+	MessageKeyNewsletterAddPaymentMethodLinkText        MessageKey = "newsletter.add_payment_method.link_text"
+	MessageKeyNewsletterDocumentSectionDefaultTitle     MessageKey = "newsletter.document_section.default_title"
+	MessageKeyNewsletterDocumentSectionOtherLinksTitle  MessageKey = "newsletter.document_section.other_links_title"
+	MessageKeyNewsletterDocumentLinkDomain              MessageKey = "newsletter.document_section.link_domain"
+	MessageKeyNewsletterPodcastSectionTitle             MessageKey = "newsletter.podcast_section.title"
+	MessageKeyNewsletterPodcastSectionOtherLinksTitle   MessageKey = "newsletter.podcast_section.other_links_title"
+	MessageKeyNewsletterAccountSectionTitle             MessageKey = "newsletter.account_section.title"
+	MessageKeyNewsletterAccountSectionReinforcementLink MessageKey = "newsletter.account_section.reinforcement_link"
+	MessageKeyNewsletterAccountSectionSetTopicsLink     MessageKey = "newsletter.account_section.set_topics_link"
+	MessageKeyNewsletterAccountSectionPreferencesLink   MessageKey = "newsletter.account_section.preferences_link"
+
+	// Weekly digest
+
+	MessageKeyWeeklyDigestHeading                MessageKey = "weekly_digest.heading"
+	MessageKeyWeeklyDigestVocabularySectionTitle MessageKey = "weekly_digest.vocabulary_section.title"
+	MessageKeyWeeklyDigestVocabularyAppearances  MessageKey = "weekly_digest.vocabulary_section.appearances"
+	MessageKeyWeeklyDigestTopicSectionOtherLinks MessageKey = "weekly_digest.topic_section.other_links_title"
+
+	// Trial ending soon notification
+
+	MessageKeyTrialEndingSoonSubject                 MessageKey = "trial_ending_soon.subject"
+	MessageKeyTrialEndingSoonTitle                   MessageKey = "trial_ending_soon.title"
+	MessageKeyTrialEndingSoonPreheader               MessageKey = "trial_ending_soon.preheader"
+	MessageKeyTrialEndingSoonHopeYouEnjoyed          MessageKey = "trial_ending_soon.hope_you_enjoyed"
+	MessageKeyTrialEndingSoonAskForFeedback          MessageKey = "trial_ending_soon.ask_for_feedback"
+	MessageKeyTrialEndingSoonTrialAlmostOver         MessageKey = "trial_ending_soon.trial_almost_over"
+	MessageKeyTrialEndingSoonNoPaymentMethod         MessageKey = "trial_ending_soon.no_payment_method"
+	MessageKeyTrialEndingSoonAutoRenewDisabled       MessageKey = "trial_ending_soon.auto_renew_disabled"
+	MessageKeyTrialEndingSoonAutoRenewDisabledButton MessageKey = "trial_ending_soon.auto_renew_disabled_button"
+	MessageKeyTrialEndingSoonAutoRenewEnabled        MessageKey = "trial_ending_soon.auto_renew_enabled"
+	MessageKeyTrialEndingSoonThanks                  MessageKey = "trial_ending_soon.thanks"
+
+	// Subscription canceled notification
+
+	MessageKeySubscriptionCanceledSubject        MessageKey = "subscription_canceled.subject"
+	MessageKeySubscriptionCanceledTitle          MessageKey = "subscription_canceled.title"
+	MessageKeySubscriptionCanceledPreheader      MessageKey = "subscription_canceled.preheader"
+	MessageKeySubscriptionCanceledThanks         MessageKey = "subscription_canceled.thanks"
+	MessageKeySubscriptionCanceledHasEnded       MessageKey = "subscription_canceled.has_ended"
+	MessageKeySubscriptionCanceledCanRestart     MessageKey = "subscription_canceled.can_restart"
+	MessageKeySubscriptionCanceledRestartButton  MessageKey = "subscription_canceled.restart_button"
+	MessageKeySubscriptionCanceledNewCharges     MessageKey = "subscription_canceled.new_charges"
+	MessageKeySubscriptionCanceledAskForFeedback MessageKey = "subscription_canceled.ask_for_feedback"
+
+	// Need payment method notification
+
+	MessageKeyNeedPaymentMethodSubject          MessageKey = "need_payment_method.subject"
+	MessageKeyNeedPaymentMethodTitle            MessageKey = "need_payment_method.title"
+	MessageKeyNeedPaymentMethodPreheader        MessageKey = "need_payment_method.preheader"
+	MessageKeyNeedPaymentMethodEndingSoon       MessageKey = "need_payment_method.ending_soon"
+	MessageKeyNeedPaymentMethodAddPaymentMethod MessageKey = "need_payment_method.add_payment_method"
+
+	// Payment error notification
+
+	MessageKeyPaymentErrorSubject          MessageKey = "payment_error.subject"
+	MessageKeyPaymentErrorTitle            MessageKey = "payment_error.title"
+	MessageKeyPaymentErrorPreheader        MessageKey = "payment_error.preheader"
+	MessageKeyPaymentErrorErrorProcessing  MessageKey = "payment_error.error_processing"
+	MessageKeyPaymentErrorAddPaymentMethod MessageKey = "payment_error.add_payment_method"
+	MessageKeyPaymentErrorButton           MessageKey = "payment_error.button"
+
+	// Dunning notifications, sent while a failed payment is being retried
+
+	MessageKeyDunningPaymentFailedSubject   MessageKey = "dunning.payment_failed.subject"
+	MessageKeyDunningPaymentFailedTitle     MessageKey = "dunning.payment_failed.title"
+	MessageKeyDunningPaymentFailedPreheader MessageKey = "dunning.payment_failed.preheader"
+	MessageKeyDunningPaymentFailedBody      MessageKey = "dunning.payment_failed.body"
+	MessageKeyDunningStillFailingSubject    MessageKey = "dunning.still_failing.subject"
+	MessageKeyDunningStillFailingTitle      MessageKey = "dunning.still_failing.title"
+	MessageKeyDunningStillFailingPreheader  MessageKey = "dunning.still_failing.preheader"
+	MessageKeyDunningStillFailingBody       MessageKey = "dunning.still_failing.body"
+	MessageKeyDunningFinalNoticeSubject     MessageKey = "dunning.final_notice.subject"
+	MessageKeyDunningFinalNoticeTitle       MessageKey = "dunning.final_notice.title"
+	MessageKeyDunningFinalNoticePreheader   MessageKey = "dunning.final_notice.preheader"
+	MessageKeyDunningFinalNoticeBody        MessageKey = "dunning.final_notice.body"
+	MessageKeyDunningKeepPremiumUntil       MessageKey = "dunning.keep_premium_until"
+	MessageKeyDunningUpdatePaymentMethod    MessageKey = "dunning.update_payment_method"
+	MessageKeyDunningButton                 MessageKey = "dunning.button"
+
+	// Reengagement notification
+
+	MessageKeyReengagementSubject          MessageKey = "reengagement.subject"
+	MessageKeyReengagementTitle            MessageKey = "reengagement.title"
+	MessageKeyReengagementPreheader        MessageKey = "reengagement.preheader"
+	MessageKeyReengagementHaventSeenYou    MessageKey = "reengagement.havent_seen_you"
+	MessageKeyReengagementAdjustNewsletter MessageKey = "reengagement.adjust_newsletter"
+	MessageKeyReengagementButton           MessageKey = "reengagement.button"
+	MessageKeyReengagementWillSendLess     MessageKey = "reengagement.will_send_less"
+
+	// Reengagement sunset notification
+
+	MessageKeyReengagementSunsetSubject   MessageKey = "reengagement_sunset.subject"
+	MessageKeyReengagementSunsetTitle     MessageKey = "reengagement_sunset.title"
+	MessageKeyReengagementSunsetPreheader MessageKey = "reengagement_sunset.preheader"
+	MessageKeyReengagementSunsetPaused    MessageKey = "reengagement_sunset.paused"
+	MessageKeyReengagementSunsetCanResume MessageKey = "reengagement_sunset.can_resume"
+	MessageKeyReengagementSunsetButton    MessageKey = "reengagement_sunset.button"
+
+	// Link for users without an account to export or erase their data
+
+	MessageKeyUserDataRequestSubject      MessageKey = "user_data_request.subject"
+	MessageKeyUserDataRequestTitle        MessageKey = "user_data_request.title"
+	MessageKeyUserDataRequestPreheader    MessageKey = "user_data_request.preheader"
+	MessageKeyUserDataRequestBody         MessageKey = "user_data_request.body"
+	MessageKeyUserDataRequestDidNotAsk    MessageKey = "user_data_request.did_not_ask"
+	MessageKeyUserDataRequestButton       MessageKey = "user_data_request.button"
+	MessageKeyUserDataRequestLinkLifetime MessageKey = "user_data_request.link_lifetime"
+
+	// Sent when someone is added to a group license
+
+	MessageKeyGroupLicenseSeatSubject       MessageKey = "group_license_seat.subject"
+	MessageKeyGroupLicenseSeatTitle         MessageKey = "group_license_seat.title"
+	MessageKeyGroupLicenseSeatPreheader     MessageKey = "group_license_seat.preheader"
+	MessageKeyGroupLicenseSeatBody          MessageKey = "group_license_seat.body"
+	MessageKeyGroupLicenseSeatClaimSignUp   MessageKey = "group_license_seat.claim_sign_up"
+	MessageKeyGroupLicenseSeatClaimAccount  MessageKey = "group_license_seat.claim_account"
+	MessageKeyGroupLicenseSeatClaimLogIn    MessageKey = "group_license_seat.claim_log_in"
+	MessageKeyGroupLicenseSeatButtonSignUp  MessageKey = "group_license_seat.button_sign_up"
+	MessageKeyGroupLicenseSeatButtonAccount MessageKey = "group_license_seat.button_account"
+	MessageKeyGroupLicenseSeatButtonLogIn   MessageKey = "group_license_seat.button_log_in"
+)
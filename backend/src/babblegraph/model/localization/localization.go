@@ -0,0 +1,123 @@
+package localization
+
+import (
+	"babblegraph/util/ctx"
+	"babblegraph/wordsmith"
+	"fmt"
+	"strings"
+)
+
+/*
+   User-facing copy lives in a catalog per locale (messages_<locale>.go)
+   instead of inline, so that every string can be looked up in any locale.
+
+   Messages can contain named placeholders like {lemma}, which are filled in
+   from Params. Messages that depend on a count have a One form as well as
+   the Other form, and the count is available as the {count} placeholder.
+*/
+
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleSpanish Locale = "es"
+
+	// All emails that are not written in the language the user
+	// is learning are in English until users can choose
+	// an interface language
+	DefaultInterfaceLocale = LocaleEnglish
+)
+
+func (l Locale) Str() string {
+	return string(l)
+}
+
+func (l Locale) Ptr() *Locale {
+	return &l
+}
+
+// GetLocaleForLanguageCode returns the locale for copy
+// that is written in the language that the user is learning
+func GetLocaleForLanguageCode(languageCode wordsmith.LanguageCode) (*Locale, error) {
+	switch languageCode {
+	case wordsmith.LanguageCodeSpanish:
+		return LocaleSpanish.Ptr(), nil
+	default:
+		return nil, fmt.Errorf("No locale for language code %s", languageCode)
+	}
+}
+
+type MessageKey string
+
+func (k MessageKey) Str() string {
+	return string(k)
+}
+
+type Params map[string]string
+
+type message struct {
+	One   string
+	Other string
+}
+
+func (m message) isPlural() bool {
+	return len(m.One) > 0
+}
+
+type catalog map[MessageKey]message
+
+var catalogsByLocale = map[Locale]catalog{
+	LocaleEnglish: englishMessages,
+	LocaleSpanish: spanishMessages,
+}
+
+func GetMessage(locale Locale, key MessageKey, params Params) string {
+	m, ok := lookupMessage(locale, key)
+	if !ok {
+		return key.Str()
+	}
+	return fillPlaceholders(m.Other, params)
+}
+
+func GetPluralMessage(locale Locale, key MessageKey, count int, params Params) string {
+	m, ok := lookupMessage(locale, key)
+	if !ok {
+		return key.Str()
+	}
+	withCount := Params{
+		"count": fmt.Sprintf("%d", count),
+	}
+	for k, v := range params {
+		withCount[k] = v
+	}
+	text := m.Other
+	// Every supported locale only distinguishes between one and everything else
+	if count == 1 && m.isPlural() {
+		text = m.One
+	}
+	return fillPlaceholders(text, withCount)
+}
+
+// A missing message falls back to the default interface locale
+// so that a user never gets an email with a raw message key
+func lookupMessage(locale Locale, key MessageKey) (*message, bool) {
+	if m, ok := catalogsByLocale[locale][key]; ok {
+		return &m, true
+	}
+	ctx.GetDefaultLogContext().Warnf("Missing message %s for locale %s", key, locale)
+	if m, ok := catalogsByLocale[DefaultInterfaceLocale][key]; ok {
+		return &m, true
+	}
+	return nil, false
+}
+
+func fillPlaceholders(text string, params Params) string {
+	if len(params) == 0 {
+		return text
+	}
+	var replacements []string
+	for k, v := range params {
+		replacements = append(replacements, fmt.Sprintf("{%s}", k), v)
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
@@ -0,0 +1,157 @@
+package localization
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+var placeholderRegex = regexp.MustCompile(`\{[a-zA-Z_]+\}`)
+
+func TestAllLocalesHaveAllMessages(t *testing.T) {
+	declaredKeys := getMessageKeysDeclaredInFile(t, "keys.go")
+	if len(declaredKeys) == 0 {
+		t.Fatalf("Expected message keys to be declared in keys.go")
+	}
+	for locale, c := range catalogsByLocale {
+		for key := range declaredKeys {
+			if _, ok := c[key]; !ok {
+				t.Errorf("Locale %s is missing message %s", locale, key)
+			}
+		}
+		for key := range c {
+			if !declaredKeys[key] {
+				t.Errorf("Locale %s has message %s, which is not declared in keys.go", locale, key)
+			}
+		}
+	}
+}
+
+// getMessageKeysDeclaredInFile parses the file instead of relying on
+// the catalogs, so that a key that was added to keys.go but not to any
+// catalog is still caught
+func getMessageKeysDeclaredInFile(t *testing.T, filename string) map[MessageKey]bool {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", filename, err.Error())
+	}
+	out := make(map[MessageKey]bool)
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			if typeIdent, ok := valueSpec.Type.(*ast.Ident); !ok || typeIdent.Name != "MessageKey" {
+				continue
+			}
+			for idx, value := range valueSpec.Values {
+				literal, ok := value.(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					t.Fatalf("Expected %s to be a string literal", valueSpec.Names[idx].Name)
+				}
+				key, err := strconv.Unquote(literal.Value)
+				if err != nil {
+					t.Fatalf("Error unquoting %s: %s", valueSpec.Names[idx].Name, err.Error())
+				}
+				out[MessageKey(key)] = true
+			}
+		}
+	}
+	return out
+}
+
+func TestMessagesAreConsistentAcrossLocales(t *testing.T) {
+	for key, defaultMessage := range catalogsByLocale[DefaultInterfaceLocale] {
+		expectedPlaceholders := getPlaceholders(defaultMessage)
+		for locale, c := range catalogsByLocale {
+			m, ok := c[key]
+			if !ok {
+				continue
+			}
+			if len(m.Other) == 0 {
+				t.Errorf("Message %s in locale %s is empty", key, locale)
+			}
+			if m.isPlural() != defaultMessage.isPlural() {
+				t.Errorf("Message %s is plural in locale %s: %t, but plural in locale %s: %t", key, locale, m.isPlural(), DefaultInterfaceLocale, defaultMessage.isPlural())
+			}
+			if placeholders := getPlaceholders(m); !reflect.DeepEqual(placeholders, expectedPlaceholders) {
+				t.Errorf("Message %s has placeholders %v in locale %s, but %v in locale %s", key, placeholders, locale, expectedPlaceholders, DefaultInterfaceLocale)
+			}
+		}
+	}
+}
+
+func getPlaceholders(m message) []string {
+	seen := make(map[string]bool)
+	for _, text := range []string{m.One, m.Other} {
+		for _, placeholder := range placeholderRegex.FindAllString(text, -1) {
+			if placeholder != "{count}" {
+				seen[placeholder] = true
+			}
+		}
+	}
+	var out []string
+	for placeholder := range seen {
+		out = append(out, placeholder)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestGetMessage(t *testing.T) {
+	type testCase struct {
+		locale   Locale
+		key      MessageKey
+		count    *int
+		params   Params
+		expected string
+	}
+	one, three := 1, 3
+	for idx, tc := range []testCase{
+		{
+			locale:   LocaleSpanish,
+			key:      MessageKeyNewsletterSpotlightSectionTitle,
+			params:   Params{"lemma": "perro"},
+			expected: "Tu vocabulario en las noticias: perro",
+		}, {
+			locale:   LocaleEnglish,
+			key:      MessageKeyNewsletterSpotlightSectionTitle,
+			params:   Params{"lemma": "perro"},
+			expected: "Your vocabulary in the news: perro",
+		}, {
+			locale:   LocaleSpanish,
+			key:      MessageKeyNewsletterDocumentSectionOtherLinksTitle,
+			count:    &one,
+			expected: "Otro enlace",
+		}, {
+			locale:   LocaleSpanish,
+			key:      MessageKeyNewsletterDocumentSectionOtherLinksTitle,
+			count:    &three,
+			expected: "Otros enlaces",
+		}, {
+			locale:   LocaleSpanish,
+			key:      MessageKey("does.not.exist"),
+			expected: "does.not.exist",
+		},
+	} {
+		var result string
+		if tc.count != nil {
+			result = GetPluralMessage(tc.locale, tc.key, *tc.count, tc.params)
+		} else {
+			result = GetMessage(tc.locale, tc.key, tc.params)
+		}
+		if result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package localization
+
+var englishMessages = catalog{
+	MessageKeyEmailGreeting:                     {Other: "Hello!"},
+	MessageKeyEmailQuestionsSignOff:             {Other: "If you have any questions or believe there is an error in this email, just respond to this email."},
+	MessageKeyEmailThanksForTryingSignOff:       {Other: "Thanks again so much for trying out Babblegraph!"},
+	MessageKeyEmailFooterManageSubscriptionText: {Other: "Click here to"},
+	MessageKeyEmailFooterManageSubscriptionLink: {Other: "manage your subscription"},
+	MessageKeyEmailAddPaymentMethodButton:       {Other: "Add a payment method to your account"},
+	MessageKeyEmailViewInBrowser:                {Other: "Having trouble reading this email? View it in your browser"},
+
+	MessageKeyBillingPricePerMonth: {Other: "{amount} per month"},
+	MessageKeyBillingPricePerYear:  {Other: "{amount} per year"},
+
+	MessageKeyNewsletterSpotlightSectionTitle:         {Other: "Your vocabulary in the news: {lemma}"},
+	MessageKeyNewsletterAdvertisementSectionTitle:     {Other: "Something we like"},
+	MessageKeyNewsletterAdvertisementPremiumLinkTitle: {Other: "If you don’t want to see any more ads, sign up for Babblegraph Premium"},
+	MessageKeyNewsletterAdvertisementPremiumLinkBody:  {Other: "With Babblegraph Premium, you won’t see ads like this one. You’ll also get access to exclusive tools, like receiving podcasts in your email."},
+	MessageKeyNewsletterAdvertisingDisclaimerText:     {Other: "* Partnering with great products and brands keeps Babblegraph running. We may earn a commission if you buy something through one of these links."},
+	MessageKeyNewsletterAdvertisingDisclaimerLinkText: {Other: "You can learn more about ads like these here"},
+	MessageKeyNewsletterAddPaymentMethodPreText:       {Other: "Enjoying Babblegraph? Make sure that you keep access after your free trial ends."},
+	MessageKeyNewsletterAddPaymentMethodLinkText:      {Other: "Click here to add a payment method."},
+	MessageKeyNewsletterDocumentSectionDefaultTitle:   {Other: "In the news"},
+	MessageKeyNewsletterDocumentSectionOtherLinksTitle: {
+		One:   "Another link",
+		Other: "Other links",
+	},
+	MessageKeyNewsletterDocumentLinkDomain:  {Other: "from {domain}"},
+	MessageKeyNewsletterPodcastSectionTitle: {Other: "Podcasts for you"},
+	MessageKeyNewsletterPodcastSectionOtherLinksTitle: {
+		One:   "Another episode",
+		Other: "Other episodes",
+	},
+	MessageKeyNewsletterAccountSectionTitle:             {Other: "Links to manage your subscription"},
+	MessageKeyNewsletterAccountSectionReinforcementLink: {Other: "Learned a new word? Click here to add it to your vocabulary list"},
+	MessageKeyNewsletterAccountSectionSetTopicsLink:     {Other: "You can pick interesting topics to personalize your next newsletter"},
+	MessageKeyNewsletterAccountSectionPreferencesLink:   {Other: "Getting too many emails? Do the emails have too many stories? You can change that here."},
+
+	MessageKeyWeeklyDigestHeading:                {Other: "Your week in the news"},
+	MessageKeyWeeklyDigestVocabularySectionTitle: {Other: "Your words in the news this week"},
+	MessageKeyWeeklyDigestVocabularyAppearances: {
+		One:   "Appeared in {count} article this week",
+		Other: "Appeared in {count} articles this week",
+	},
+	MessageKeyWeeklyDigestTopicSectionOtherLinks: {Other: "More from this week"},
+
+	MessageKeyTrialEndingSoonSubject:                 {Other: "Attention! Your Babblegraph trial is ending soon!"},
+	MessageKeyTrialEndingSoonTitle:                   {Other: "Your Babblegraph trial is ending soon"},
+	MessageKeyTrialEndingSoonPreheader:               {Other: "Your trial of Babblegraph is set to expire in the next few days"},
+	MessageKeyTrialEndingSoonHopeYouEnjoyed:          {Other: "We hope you’ve been enjoying Babblegraph. If not, your feedback is always appreciated!"},
+	MessageKeyTrialEndingSoonAskForFeedback:          {Other: "Just respond to this email with any ideas or comments you have about what Babblegraph could be doing better."},
+	MessageKeyTrialEndingSoonTrialAlmostOver:         {Other: "This email is to let you know that your trial is almost over."},
+	MessageKeyTrialEndingSoonNoPaymentMethod:         {Other: "It looks like you haven’t added a payment method yet. If you’d like to continue using Babblegraph, then you’ll need to add a payment method. You can do that at this link"},
+	MessageKeyTrialEndingSoonAutoRenewDisabled:       {Other: "You have a payment method added to your account, but your subscription is not set to automatically renew. If you would like to continue to use Babblegraph, you’ll need to turn auto-renew on."},
+	MessageKeyTrialEndingSoonAutoRenewDisabledButton: {Other: "Manage your subscription settings here"},
+	MessageKeyTrialEndingSoonAutoRenewEnabled:        {Other: "You have a payment method added to your account, so your subscription will automatically renew in a few days, and you’ll be charged {price} then."},
+	MessageKeyTrialEndingSoonThanks:                  {Other: "Thank you so much for trying out Babblegraph!"},
+
+	MessageKeySubscriptionCanceledSubject:        {Other: "Your Babblegraph subscription has ended"},
+	MessageKeySubscriptionCanceledTitle:          {Other: "Your Babblegraph subscription has ended"},
+	MessageKeySubscriptionCanceledPreheader:      {Other: "Your Babblegraph subscription has expired"},
+	MessageKeySubscriptionCanceledThanks:         {Other: "Thanks so much for trying Babblegraph!"},
+	MessageKeySubscriptionCanceledHasEnded:       {Other: "This email is to let you know that your subscription to Babblegraph has ended. You will no longer be charged for the subscription."},
+	MessageKeySubscriptionCanceledCanRestart:     {Other: "If you wanted to continue to use Babblegraph, you can restart your subscription at the link below."},
+	MessageKeySubscriptionCanceledRestartButton:  {Other: "Click here to restart your subscription"},
+	MessageKeySubscriptionCanceledNewCharges:     {Other: "If you are not restarting your subscription and you see any new charges on your credit card statement from Babblegraph, just respond to this email and we’ll get it sorted out."},
+	MessageKeySubscriptionCanceledAskForFeedback: {Other: "Lastly, before you go, we’d love to know what we could do better! You can respond directly to this email to give feedback."},
+
+	MessageKeyNeedPaymentMethodSubject:          {Other: "Attention! Add a payment method to keep using Babblegraph"},
+	MessageKeyNeedPaymentMethodTitle:            {Other: "Your trial is about to expire with no payment method"},
+	MessageKeyNeedPaymentMethodPreheader:        {Other: "Your trial is ending, but you don’t have a payment method added."},
+	MessageKeyNeedPaymentMethodEndingSoon:       {Other: "This email is to let you know that your subscription is ending soon, but you have no payment method added to your account."},
+	MessageKeyNeedPaymentMethodAddPaymentMethod: {Other: "If you would like to continue to use Babblegraph, you’ll need to add a payment method. You can do that at the link below."},
+
+	MessageKeyPaymentErrorSubject:          {Other: "Attention! There was an error processing your payment"},
+	MessageKeyPaymentErrorTitle:            {Other: "There was an error processing your payment"},
+	MessageKeyPaymentErrorPreheader:        {Other: "Your subscription is about to end and there was an error processing your payment."},
+	MessageKeyPaymentErrorErrorProcessing:  {Other: "This email is to let you know that there has been an error processing the payment method that we have on file."},
+	MessageKeyPaymentErrorAddPaymentMethod: {Other: "If you would like to continue to use Babblegraph, you’ll need to add a valid payment method. You can do that at the link below."},
+	MessageKeyPaymentErrorButton:           {Other: "Edit the payment method on your account"},
+
+	MessageKeyDunningPaymentFailedSubject:   {Other: "We couldn’t process your Babblegraph payment"},
+	MessageKeyDunningPaymentFailedTitle:     {Other: "Your payment didn’t go through"},
+	MessageKeyDunningPaymentFailedPreheader: {Other: "There was a problem renewing your Babblegraph subscription."},
+	MessageKeyDunningPaymentFailedBody:      {Other: "This email is to let you know that we tried to charge {amount} to renew your Babblegraph subscription, but the payment didn’t go through. We’ll try again automatically over the next few days."},
+	MessageKeyDunningStillFailingSubject:    {Other: "Attention! Your Babblegraph payment is still failing"},
+	MessageKeyDunningStillFailingTitle:      {Other: "Your payment is still failing"},
+	MessageKeyDunningStillFailingPreheader:  {Other: "We’ve tried to renew your Babblegraph subscription a few times now."},
+	MessageKeyDunningStillFailingBody:       {Other: "We’ve tried to charge {amount} to renew your Babblegraph subscription a few times now, but the payment still isn’t going through."},
+	MessageKeyDunningFinalNoticeSubject:     {Other: "Final notice: your Babblegraph subscription is about to end"},
+	MessageKeyDunningFinalNoticeTitle:       {Other: "Your subscription is about to end"},
+	MessageKeyDunningFinalNoticePreheader:   {Other: "We still haven’t been able to renew your Babblegraph subscription."},
+	MessageKeyDunningFinalNoticeBody:        {Other: "We still haven’t been able to charge {amount} to renew your Babblegraph subscription. If the payment doesn’t go through by {date}, your account will go back to the free version of Babblegraph."},
+	MessageKeyDunningKeepPremiumUntil:       {Other: "You’ll keep all of your premium features until {date} while we sort this out."},
+	MessageKeyDunningUpdatePaymentMethod:    {Other: "The easiest way to fix this is to update the payment method on your account, which you can do at the link below."},
+	MessageKeyDunningButton:                 {Other: "Update your payment method"},
+
+	MessageKeyReengagementSubject:          {Other: "We miss you at Babblegraph!"},
+	MessageKeyReengagementTitle:            {Other: "We miss you!"},
+	MessageKeyReengagementPreheader:        {Other: "It looks like you haven’t read your newsletter in a while."},
+	MessageKeyReengagementHaventSeenYou:    {Other: "It looks like you haven’t opened or clicked on any of your Babblegraph newsletters in the last few weeks."},
+	MessageKeyReengagementAdjustNewsletter: {Other: "If the articles have been too hard, too easy, or just not that interesting, you can change your topics, which days you get your newsletter, and how many articles are in each one."},
+	MessageKeyReengagementButton:           {Other: "Adjust your newsletter"},
+	MessageKeyReengagementWillSendLess:     {Other: "If we don’t hear from you, we’ll start sending your newsletter less often so that it doesn’t crowd your inbox."},
+
+	MessageKeyReengagementSunsetSubject:   {Other: "We’ve paused your Babblegraph newsletter"},
+	MessageKeyReengagementSunsetTitle:     {Other: "Your newsletter has been paused"},
+	MessageKeyReengagementSunsetPreheader: {Other: "We haven’t seen you in a while, so we’ve stopped sending your newsletter."},
+	MessageKeyReengagementSunsetPaused:    {Other: "Since you haven’t opened your newsletter in a few months, we’ve paused it so that it doesn’t keep filling up your inbox."},
+	MessageKeyReengagementSunsetCanResume: {Other: "If you’d like to start getting it again, just save your newsletter preferences at the link below."},
+	MessageKeyReengagementSunsetButton:    {Other: "Resume your newsletter"},
+
+	MessageKeyUserDataRequestSubject:      {Other: "Your Babblegraph data request"},
+	MessageKeyUserDataRequestTitle:        {Other: "Your data request"},
+	MessageKeyUserDataRequestPreheader:    {Other: "Download or erase the data Babblegraph has about you."},
+	MessageKeyUserDataRequestBody:         {Other: "There was recently a request to download or erase the data Babblegraph has about this email address. You can do either at the link below."},
+	MessageKeyUserDataRequestDidNotAsk:    {Other: "If you did not make this request, you do not need to do anything."},
+	MessageKeyUserDataRequestButton:       {Other: "Manage your data"},
+	MessageKeyUserDataRequestLinkLifetime: {Other: "This link is only valid for 24 hours."},
+
+	MessageKeyGroupLicenseSeatSubject:       {Other: "You’ve been given Babblegraph Premium"},
+	MessageKeyGroupLicenseSeatTitle:         {Other: "You have a Babblegraph Premium seat"},
+	MessageKeyGroupLicenseSeatPreheader:     {Other: "Someone has added you to their Babblegraph Premium group."},
+	MessageKeyGroupLicenseSeatBody:          {Other: "You’ve been given a seat on {name}, so you’ll have all of the premium features of Babblegraph until {date}."},
+	MessageKeyGroupLicenseSeatClaimSignUp:   {Other: "To claim your seat, sign up for Babblegraph with this email address, then create an account once you’ve verified it."},
+	MessageKeyGroupLicenseSeatClaimAccount:  {Other: "Your seat is already active. To use the premium features, create an account with the link below."},
+	MessageKeyGroupLicenseSeatClaimLogIn:    {Other: "Your seat is already active, so you just need to log in to start using the premium features."},
+	MessageKeyGroupLicenseSeatButtonSignUp:  {Other: "Sign up for Babblegraph"},
+	MessageKeyGroupLicenseSeatButtonAccount: {Other: "Create your account"},
+	MessageKeyGroupLicenseSeatButtonLogIn:   {Other: "Log in"},
+}
@@ -0,0 +1,131 @@
+package localization
+
+var spanishMessages = catalog{
+	MessageKeyEmailGreeting:                     {Other: "¡Hola!"},
+	MessageKeyEmailQuestionsSignOff:             {Other: "Si tienes alguna pregunta o crees que hay un error en este email, solo responde a este email."},
+	MessageKeyEmailThanksForTryingSignOff:       {Other: "¡Muchas gracias otra vez por probar Babblegraph!"},
+	MessageKeyEmailFooterManageSubscriptionText: {Other: "Haz clic aquí para"},
+	MessageKeyEmailFooterManageSubscriptionLink: {Other: "gestionar tu suscripción"},
+	MessageKeyEmailAddPaymentMethodButton:       {Other: "Agrega un método de pago a tu cuenta"},
+	MessageKeyEmailViewInBrowser:                {Other: "¿Tienes problemas para leer este email? Míralo en tu navegador"},
+
+	MessageKeyBillingPricePerMonth: {Other: "{amount} al mes"},
+	MessageKeyBillingPricePerYear:  {Other: "{amount} al año"},
+
+	MessageKeyNewsletterSpotlightSectionTitle:         {Other: "Tu vocabulario en las noticias: {lemma}"},
+	MessageKeyNewsletterAdvertisementSectionTitle:     {Other: "Algo que nos gusta"},
+	MessageKeyNewsletterAdvertisementPremiumLinkTitle: {Other: "Si no quieres ver más anuncios, inscribete a Babblegraph Premium"},
+	MessageKeyNewsletterAdvertisementPremiumLinkBody:  {Other: "Con Babblegraph Premium, no verás anuncios como esto. También, tendrás acceso a herramientas exclusivas: como recibir podcasts en el email."},
+	MessageKeyNewsletterAdvertisingDisclaimerText:     {Other: "* Asociarnos con excelentes productos y marcas permite que Babblegraph siga funcionando. Podemos ganar una comisión si compra algo a través de uno de estos enlaces."},
+	MessageKeyNewsletterAdvertisingDisclaimerLinkText: {Other: "Puedes obtener más información sobre anuncios como estos aquí"},
+	MessageKeyNewsletterAddPaymentMethodPreText:       {Other: "¿Te gusta usar Babblegraph? Asegurate de que sigas tener acceso después de acabar tu prueba gratis."},
+	MessageKeyNewsletterAddPaymentMethodLinkText:      {Other: "Haz clic aquí para agregar un método de pago."},
+	MessageKeyNewsletterDocumentSectionDefaultTitle:   {Other: "En las noticias"},
+	MessageKeyNewsletterDocumentSectionOtherLinksTitle: {
+		One:   "Otro enlace",
+		Other: "Otros enlaces",
+	},
+	MessageKeyNewsletterDocumentLinkDomain:  {Other: "por {domain}"},
+	MessageKeyNewsletterPodcastSectionTitle: {Other: "Podcasts para ti"},
+	MessageKeyNewsletterPodcastSectionOtherLinksTitle: {
+		One:   "Otro episodio",
+		Other: "Otros episodios",
+	},
+	MessageKeyNewsletterAccountSectionTitle:             {Other: "Enlaces para gestionar tu suscripción"},
+	MessageKeyNewsletterAccountSectionReinforcementLink: {Other: "¿Has aprendido una palabra nueva? Haz clic aquí para añadirla a tu lista de vocabulario"},
+	MessageKeyNewsletterAccountSectionSetTopicsLink:     {Other: "Puedes escoger temas interesantes para personalizar el próximo boletín"},
+	MessageKeyNewsletterAccountSectionPreferencesLink:   {Other: "¿Estás recibiendo demasiados emails? ¿Los emails tienen demasiadas historias? Puedes cambiar eso aquí."},
+
+	MessageKeyWeeklyDigestHeading:                {Other: "Tu semana en las noticias"},
+	MessageKeyWeeklyDigestVocabularySectionTitle: {Other: "Tus palabras en las noticias esta semana"},
+	MessageKeyWeeklyDigestVocabularyAppearances: {
+		One:   "Apareció en {count} artículo esta semana",
+		Other: "Apareció en {count} artículos esta semana",
+	},
+	MessageKeyWeeklyDigestTopicSectionOtherLinks: {Other: "Más de esta semana"},
+
+	MessageKeyTrialEndingSoonSubject:                 {Other: "¡Atención! Tu prueba de Babblegraph se termina pronto"},
+	MessageKeyTrialEndingSoonTitle:                   {Other: "Tu prueba de Babblegraph se termina pronto"},
+	MessageKeyTrialEndingSoonPreheader:               {Other: "Tu prueba de Babblegraph se vence en los próximos días"},
+	MessageKeyTrialEndingSoonHopeYouEnjoyed:          {Other: "Esperamos que te haya gustado Babblegraph. Si no, ¡siempre agradecemos tus comentarios!"},
+	MessageKeyTrialEndingSoonAskForFeedback:          {Other: "Solo responde a este email con cualquier idea o comentario que tengas sobre lo que Babblegraph podría hacer mejor."},
+	MessageKeyTrialEndingSoonTrialAlmostOver:         {Other: "Te escribimos para avisarte de que tu prueba está por terminar."},
+	MessageKeyTrialEndingSoonNoPaymentMethod:         {Other: "Parece que todavía no has agregado un método de pago. Si quieres seguir usando Babblegraph, tendrás que agregar un método de pago. Puedes hacerlo en este enlace"},
+	MessageKeyTrialEndingSoonAutoRenewDisabled:       {Other: "Tienes un método de pago en tu cuenta, pero tu suscripción no está configurada para renovarse automáticamente. Si quieres seguir usando Babblegraph, tendrás que activar la renovación automática."},
+	MessageKeyTrialEndingSoonAutoRenewDisabledButton: {Other: "Gestiona la configuración de tu suscripción aquí"},
+	MessageKeyTrialEndingSoonAutoRenewEnabled:        {Other: "Tienes un método de pago en tu cuenta, así que tu suscripción se renovará automáticamente en unos días y se te cobrará {price} entonces."},
+	MessageKeyTrialEndingSoonThanks:                  {Other: "¡Muchísimas gracias por probar Babblegraph!"},
+
+	MessageKeySubscriptionCanceledSubject:        {Other: "Tu suscripción a Babblegraph ha terminado"},
+	MessageKeySubscriptionCanceledTitle:          {Other: "Tu suscripción a Babblegraph ha terminado"},
+	MessageKeySubscriptionCanceledPreheader:      {Other: "Tu suscripción a Babblegraph se ha vencido"},
+	MessageKeySubscriptionCanceledThanks:         {Other: "¡Muchas gracias por probar Babblegraph!"},
+	MessageKeySubscriptionCanceledHasEnded:       {Other: "Te escribimos para avisarte de que tu suscripción a Babblegraph ha terminado. Ya no se te cobrará por la suscripción."},
+	MessageKeySubscriptionCanceledCanRestart:     {Other: "Si quieres seguir usando Babblegraph, puedes reiniciar tu suscripción en el enlace de abajo."},
+	MessageKeySubscriptionCanceledRestartButton:  {Other: "Haz clic aquí para reiniciar tu suscripción"},
+	MessageKeySubscriptionCanceledNewCharges:     {Other: "Si no vas a reiniciar tu suscripción y ves algún cargo nuevo de Babblegraph en el extracto de tu tarjeta de crédito, solo responde a este email y lo resolveremos."},
+	MessageKeySubscriptionCanceledAskForFeedback: {Other: "Por último, antes de que te vayas, ¡nos encantaría saber qué podríamos hacer mejor! Puedes responder directamente a este email para darnos tus comentarios."},
+
+	MessageKeyNeedPaymentMethodSubject:          {Other: "¡Atención! Agrega un método de pago para seguir usando Babblegraph"},
+	MessageKeyNeedPaymentMethodTitle:            {Other: "Tu prueba está por vencerse y no tienes un método de pago"},
+	MessageKeyNeedPaymentMethodPreheader:        {Other: "Tu prueba se termina, pero no has agregado un método de pago."},
+	MessageKeyNeedPaymentMethodEndingSoon:       {Other: "Te escribimos para avisarte de que tu suscripción termina pronto, pero no tienes un método de pago en tu cuenta."},
+	MessageKeyNeedPaymentMethodAddPaymentMethod: {Other: "Si quieres seguir usando Babblegraph, tendrás que agregar un método de pago. Puedes hacerlo en el enlace de abajo."},
+
+	MessageKeyPaymentErrorSubject:          {Other: "¡Atención! Hubo un error al procesar tu pago"},
+	MessageKeyPaymentErrorTitle:            {Other: "Hubo un error al procesar tu pago"},
+	MessageKeyPaymentErrorPreheader:        {Other: "Tu suscripción está por terminar y hubo un error al procesar tu pago."},
+	MessageKeyPaymentErrorErrorProcessing:  {Other: "Te escribimos para avisarte de que hubo un error al procesar el método de pago que tenemos registrado."},
+	MessageKeyPaymentErrorAddPaymentMethod: {Other: "Si quieres seguir usando Babblegraph, tendrás que agregar un método de pago válido. Puedes hacerlo en el enlace de abajo."},
+	MessageKeyPaymentErrorButton:           {Other: "Edita el método de pago de tu cuenta"},
+
+	MessageKeyDunningPaymentFailedSubject:   {Other: "No pudimos procesar tu pago de Babblegraph"},
+	MessageKeyDunningPaymentFailedTitle:     {Other: "Tu pago no se completó"},
+	MessageKeyDunningPaymentFailedPreheader: {Other: "Hubo un problema al renovar tu suscripción a Babblegraph."},
+	MessageKeyDunningPaymentFailedBody:      {Other: "Te escribimos para avisarte de que intentamos cobrar {amount} para renovar tu suscripción a Babblegraph, pero el pago no se completó. Lo volveremos a intentar automáticamente en los próximos días."},
+	MessageKeyDunningStillFailingSubject:    {Other: "¡Atención! Tu pago de Babblegraph sigue fallando"},
+	MessageKeyDunningStillFailingTitle:      {Other: "Tu pago sigue fallando"},
+	MessageKeyDunningStillFailingPreheader:  {Other: "Ya intentamos renovar tu suscripción a Babblegraph varias veces."},
+	MessageKeyDunningStillFailingBody:       {Other: "Ya intentamos cobrar {amount} varias veces para renovar tu suscripción a Babblegraph, pero el pago sigue sin completarse."},
+	MessageKeyDunningFinalNoticeSubject:     {Other: "Último aviso: tu suscripción a Babblegraph está por terminar"},
+	MessageKeyDunningFinalNoticeTitle:       {Other: "Tu suscripción está por terminar"},
+	MessageKeyDunningFinalNoticePreheader:   {Other: "Todavía no hemos podido renovar tu suscripción a Babblegraph."},
+	MessageKeyDunningFinalNoticeBody:        {Other: "Todavía no hemos podido cobrar {amount} para renovar tu suscripción a Babblegraph. Si el pago no se completa antes del {date}, tu cuenta volverá a la versión gratuita de Babblegraph."},
+	MessageKeyDunningKeepPremiumUntil:       {Other: "Seguirás teniendo todas las funciones premium hasta el {date} mientras lo resolvemos."},
+	MessageKeyDunningUpdatePaymentMethod:    {Other: "La forma más fácil de arreglarlo es actualizar el método de pago de tu cuenta, lo cual puedes hacer en el enlace de abajo."},
+	MessageKeyDunningButton:                 {Other: "Actualiza tu método de pago"},
+
+	MessageKeyReengagementSubject:          {Other: "¡Te extrañamos en Babblegraph!"},
+	MessageKeyReengagementTitle:            {Other: "¡Te extrañamos!"},
+	MessageKeyReengagementPreheader:        {Other: "Parece que hace tiempo que no lees tu boletín."},
+	MessageKeyReengagementHaventSeenYou:    {Other: "Parece que no has abierto ni hecho clic en ninguno de tus boletines de Babblegraph en las últimas semanas."},
+	MessageKeyReengagementAdjustNewsletter: {Other: "Si los artículos han sido demasiado difíciles, demasiado fáciles o simplemente no muy interesantes, puedes cambiar tus temas, los días en que recibes tu boletín y cuántos artículos incluye cada uno."},
+	MessageKeyReengagementButton:           {Other: "Ajusta tu boletín"},
+	MessageKeyReengagementWillSendLess:     {Other: "Si no sabemos de ti, empezaremos a enviarte el boletín con menos frecuencia para que no llene tu bandeja de entrada."},
+
+	MessageKeyReengagementSunsetSubject:   {Other: "Hemos pausado tu boletín de Babblegraph"},
+	MessageKeyReengagementSunsetTitle:     {Other: "Tu boletín ha sido pausado"},
+	MessageKeyReengagementSunsetPreheader: {Other: "Hace tiempo que no te vemos, así que hemos dejado de enviarte el boletín."},
+	MessageKeyReengagementSunsetPaused:    {Other: "Como no has abierto tu boletín en algunos meses, lo hemos pausado para que no siga llenando tu bandeja de entrada."},
+	MessageKeyReengagementSunsetCanResume: {Other: "Si quieres volver a recibirlo, solo tienes que guardar tus preferencias del boletín en el enlace de abajo."},
+	MessageKeyReengagementSunsetButton:    {Other: "Reanuda tu boletín"},
+
+	MessageKeyUserDataRequestSubject:      {Other: "Tu solicitud de datos de Babblegraph"},
+	MessageKeyUserDataRequestTitle:        {Other: "Tu solicitud de datos"},
+	MessageKeyUserDataRequestPreheader:    {Other: "Descarga o borra los datos que Babblegraph tiene sobre ti."},
+	MessageKeyUserDataRequestBody:         {Other: "Hace poco se pidió descargar o borrar los datos que Babblegraph tiene sobre esta dirección de correo electrónico. Puedes hacer cualquiera de las dos cosas en el enlace de abajo."},
+	MessageKeyUserDataRequestDidNotAsk:    {Other: "Si no hiciste esta solicitud, no tienes que hacer nada."},
+	MessageKeyUserDataRequestButton:       {Other: "Gestiona tus datos"},
+	MessageKeyUserDataRequestLinkLifetime: {Other: "Este enlace solo es válido durante 24 horas."},
+
+	MessageKeyGroupLicenseSeatSubject:       {Other: "Te han regalado Babblegraph Premium"},
+	MessageKeyGroupLicenseSeatTitle:         {Other: "Tienes una plaza de Babblegraph Premium"},
+	MessageKeyGroupLicenseSeatPreheader:     {Other: "Alguien te ha añadido a su grupo de Babblegraph Premium."},
+	MessageKeyGroupLicenseSeatBody:          {Other: "Te han dado una plaza en {name}, así que tendrás todas las funciones premium de Babblegraph hasta el {date}."},
+	MessageKeyGroupLicenseSeatClaimSignUp:   {Other: "Para reclamar tu plaza, suscríbete a Babblegraph con esta dirección de correo electrónico y crea una cuenta después de verificarla."},
+	MessageKeyGroupLicenseSeatClaimAccount:  {Other: "Tu plaza ya está activa. Para usar las funciones premium, crea una cuenta con el enlace de abajo."},
+	MessageKeyGroupLicenseSeatClaimLogIn:    {Other: "Tu plaza ya está activa, así que solo tienes que iniciar sesión para empezar a usar las funciones premium."},
+	MessageKeyGroupLicenseSeatButtonSignUp:  {Other: "Suscríbete a Babblegraph"},
+	MessageKeyGroupLicenseSeatButtonAccount: {Other: "Crea tu cuenta"},
+	MessageKeyGroupLicenseSeatButtonLogIn:   {Other: "Inicia sesión"},
+}
@@ -72,7 +72,7 @@ func getDocumentCategories(c ctx.LogContext, input getDocumentCategoriesInput) (
 	}
 	var podcastEpisodesByTopic map[content.TopicID][]podcasts.Episode
 	if input.userAccessor.getUserSubscriptionLevel() != nil {
-		podcastEpisodesByTopic, err = input.podcastAccessor.LookupPodcastEpisodesForTopics(topics)
+		podcastEpisodesByTopic, err = input.podcastAccessor.LookupPodcastEpisodesForTopics(topics, input.userAccessor.getReadingLevel(), lemmaIDPhrases)
 	}
 	return joinDocumentsIntoCategories(c, joinDocumentsIntoCategoriesInput{
 		emailRecordID:                 input.emailRecordID,
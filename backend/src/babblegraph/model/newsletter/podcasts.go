@@ -8,6 +8,7 @@ import (
 	"babblegraph/model/userpodcasts"
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
+	"babblegraph/util/ptr"
 	"babblegraph/wordsmith"
 
 	"github.com/jmoiron/sqlx"
@@ -24,7 +25,8 @@ const (
 )
 
 type podcastAccessor interface {
-	LookupPodcastEpisodesForTopics(topics []content.TopicID) (map[content.TopicID][]podcasts.Episode, error)
+	LookupPodcastEpisodesForTopics(topics []content.TopicID, readingLevel *userReadingLevel, lemmaIDPhrases [][]wordsmith.LemmaID) (map[content.TopicID][]podcasts.Episode, error)
+	LookupPodcastEpisodesForLemma(readingLevel *userReadingLevel, lemmaIDPhrases [][]wordsmith.LemmaID) ([]podcasts.Episode, error)
 	GetPodcastMetadataForSourceID(sourceID content.SourceID) (*podcasts.PodcastMetadata, error)
 	InsertUserPodcastAndGetID(emailRecordID email.ID, episode podcasts.Episode) (*userpodcasts.ID, error)
 }
@@ -67,7 +69,7 @@ func GetDefaultPodcastAccessor(c ctx.LogContext, tx *sqlx.Tx, languageCode words
 	}, nil
 }
 
-func (d *DefaultPodcastAccessor) LookupPodcastEpisodesForTopics(topicIDs []content.TopicID) (map[content.TopicID][]podcasts.Episode, error) {
+func (d *DefaultPodcastAccessor) LookupPodcastEpisodesForTopics(topicIDs []content.TopicID, readingLevel *userReadingLevel, lemmaIDPhrases [][]wordsmith.LemmaID) (map[content.TopicID][]podcasts.Episode, error) {
 	if !d.userNewsletterPreferences.PodcastPreferences.ArePodcastsEnabled {
 		return nil, nil
 	}
@@ -76,10 +78,13 @@ func (d *DefaultPodcastAccessor) LookupPodcastEpisodesForTopics(topicIDs []conte
 		scoredEpisodes, err := podcasts.QueryEpisodes(d.languageCode, podcasts.QueryEpisodesInput{
 			SeenPodcastIDs:          d.seenPodcastIDs,
 			ValidSourceIDs:          d.validSourceIDs,
-			TopicID:                 t,
+			TopicID:                 t.Ptr(),
 			IncludeExplicitPodcasts: d.userNewsletterPreferences.PodcastPreferences.IncludeExplicitPodcasts,
 			MinDurationNanoseconds:  d.userNewsletterPreferences.PodcastPreferences.MinimumDurationNanoseconds,
 			MaxDurationNanoseconds:  d.userNewsletterPreferences.PodcastPreferences.MaximumDurationNanoseconds,
+			MinimumReadingLevel:     ptr.Int64(readingLevel.LowerBound),
+			MaximumReadingLevel:     ptr.Int64(readingLevel.UpperBound),
+			LemmaIDPhrases:          lemmaIDPhrases,
 		})
 		if err != nil {
 			return nil, err
@@ -91,10 +96,42 @@ func (d *DefaultPodcastAccessor) LookupPodcastEpisodesForTopics(topicIDs []conte
 	return out, nil
 }
 
+func (d *DefaultPodcastAccessor) LookupPodcastEpisodesForLemma(readingLevel *userReadingLevel, lemmaIDPhrases [][]wordsmith.LemmaID) ([]podcasts.Episode, error) {
+	if !d.userNewsletterPreferences.PodcastPreferences.ArePodcastsEnabled {
+		return nil, nil
+	}
+	scoredEpisodes, err := podcasts.QueryEpisodes(d.languageCode, podcasts.QueryEpisodesInput{
+		SeenPodcastIDs:          d.seenPodcastIDs,
+		ValidSourceIDs:          d.validSourceIDs,
+		IncludeExplicitPodcasts: d.userNewsletterPreferences.PodcastPreferences.IncludeExplicitPodcasts,
+		MinDurationNanoseconds:  d.userNewsletterPreferences.PodcastPreferences.MinimumDurationNanoseconds,
+		MaxDurationNanoseconds:  d.userNewsletterPreferences.PodcastPreferences.MaximumDurationNanoseconds,
+		MinimumReadingLevel:     ptr.Int64(readingLevel.LowerBound),
+		MaximumReadingLevel:     ptr.Int64(readingLevel.UpperBound),
+		LemmaIDPhrases:          lemmaIDPhrases,
+		RequireLemmaIDPhrases:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out []podcasts.Episode
+	for _, ep := range scoredEpisodes {
+		out = append(out, ep.Episode)
+	}
+	return out, nil
+}
+
 func (d *DefaultPodcastAccessor) GetPodcastMetadataForSourceID(sourceID content.SourceID) (*podcasts.PodcastMetadata, error) {
 	return podcasts.GetPodcastMetadataForSourceID(d.tx, sourceID)
 }
 
 func (d *DefaultPodcastAccessor) InsertUserPodcastAndGetID(emailRecordID email.ID, episode podcasts.Episode) (*userpodcasts.ID, error) {
-	return userpodcasts.InsertUserPodcastAndReturnID(d.tx, episode.ID, d.userID, episode.SourceID, emailRecordID)
+	userPodcastID, err := userpodcasts.InsertUserPodcastAndReturnID(d.tx, episode.ID, d.userID, episode.SourceID, emailRecordID)
+	if err != nil {
+		return nil, err
+	}
+	// The spotlight is picked before the podcast section,
+	// so this keeps the same episode from being sent twice
+	d.seenPodcastIDs = append(d.seenPodcastIDs, episode.ID)
+	return userPodcastID, nil
 }
@@ -7,10 +7,12 @@ import (
 	"babblegraph/model/usernewsletterpreferences"
 	"babblegraph/model/userpodcasts"
 	"babblegraph/util/ctx"
+	"babblegraph/util/deref"
 	"babblegraph/util/ptr"
 	"babblegraph/util/random"
 	"babblegraph/wordsmith"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -22,33 +24,60 @@ type testPodcastAccessor struct {
 	podcastEpisodes []podcasts.Episode
 }
 
-func (t *testPodcastAccessor) LookupPodcastEpisodesForTopics(topics []content.TopicID) (map[content.TopicID][]podcasts.Episode, error) {
-	// This is a hack
-	c := ctx.GetDefaultLogContext()
-	podcastPreferences := t.userNewsletterPreferences.PodcastPreferences
+func (t *testPodcastAccessor) LookupPodcastEpisodesForTopics(topics []content.TopicID, readingLevel *userReadingLevel, lemmaIDPhrases [][]wordsmith.LemmaID) (map[content.TopicID][]podcasts.Episode, error) {
 	episodesByTopic := make(map[content.TopicID][]podcasts.Episode)
 	for _, ep := range t.podcastEpisodes {
-		switch {
-		case !podcastPreferences.IncludeExplicitPodcasts && ep.IsExplicit:
-			c.Debugf("Filtering out podcast because of explicit tag")
-		case podcastPreferences.MinimumDurationNanoseconds != nil && *ep.DurationNanoseconds < *podcastPreferences.MinimumDurationNanoseconds:
-			c.Debugf("Filtering out podcast because of it's too short")
-		case podcastPreferences.MaximumDurationNanoseconds != nil && *ep.DurationNanoseconds > *podcastPreferences.MaximumDurationNanoseconds:
-			c.Debugf("Filtering out podcast because of it's too long")
-		case !isSourceValid(ep.SourceID.Ptr(), t.validSourceIDs):
-			c.Debugf("Filtering out podcast because the source is not valid")
-		// no-op
-		default:
-			for _, t := range topics {
-				if containsTopic(t, ep.TopicIDs) {
-					episodesByTopic[t] = append(episodesByTopic[t], ep)
-				}
+		if !t.isEpisodeEligible(ep, readingLevel) {
+			continue
+		}
+		for _, t := range topics {
+			if containsTopic(t, ep.TopicIDs) {
+				episodesByTopic[t] = append(episodesByTopic[t], ep)
 			}
 		}
 	}
 	return episodesByTopic, nil
 }
 
+func (t *testPodcastAccessor) LookupPodcastEpisodesForLemma(readingLevel *userReadingLevel, lemmaIDPhrases [][]wordsmith.LemmaID) ([]podcasts.Episode, error) {
+	var out []podcasts.Episode
+	for _, ep := range t.podcastEpisodes {
+		if !t.isEpisodeEligible(ep, readingLevel) {
+			continue
+		}
+		lemmatizedText := fmt.Sprintf("%s %s", deref.String(ep.LemmatizedDescription, ""), deref.String(ep.LemmatizedTranscript, ""))
+		tokens := multipleSpaces.Split(strings.TrimSpace(lemmatizedText), -1)
+		for idx := range tokens {
+			if containsSpotlight(tokens, idx, lemmaIDPhrases) {
+				out = append(out, ep)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (t *testPodcastAccessor) isEpisodeEligible(ep podcasts.Episode, readingLevel *userReadingLevel) bool {
+	// This is a hack
+	c := ctx.GetDefaultLogContext()
+	podcastPreferences := t.userNewsletterPreferences.PodcastPreferences
+	switch {
+	case !podcastPreferences.IncludeExplicitPodcasts && ep.IsExplicit:
+		c.Debugf("Filtering out podcast because of explicit tag")
+	case podcastPreferences.MinimumDurationNanoseconds != nil && *ep.DurationNanoseconds < *podcastPreferences.MinimumDurationNanoseconds:
+		c.Debugf("Filtering out podcast because of it's too short")
+	case podcastPreferences.MaximumDurationNanoseconds != nil && *ep.DurationNanoseconds > *podcastPreferences.MaximumDurationNanoseconds:
+		c.Debugf("Filtering out podcast because of it's too long")
+	case ep.ReadabilityScore != nil && (*ep.ReadabilityScore < readingLevel.LowerBound || *ep.ReadabilityScore > readingLevel.UpperBound):
+		c.Debugf("Filtering out podcast because of reading level")
+	case !isSourceValid(ep.SourceID.Ptr(), t.validSourceIDs):
+		c.Debugf("Filtering out podcast because the source is not valid")
+	default:
+		return true
+	}
+	return false
+}
+
 func (t *testPodcastAccessor) GetPodcastMetadataForSourceID(sourceID content.SourceID) (*podcasts.PodcastMetadata, error) {
 	return &podcasts.PodcastMetadata{
 		ImageURL:  ptr.String("https://static.babblegraph.com"),
@@ -157,6 +157,74 @@ func lookupSpotlightForAllPotentialSpotlights(c ctx.LogContext, input lookupSpot
 	return nil, nil
 }
 
+type podcastSpotlight struct {
+	LemmaText string
+	Podcast   PodcastLink
+}
+
+type getPodcastSpotlightForNewsletterInput struct {
+	emailRecordID   email.ID
+	userAccessor    userPreferencesAccessor
+	podcastAccessor podcastAccessor
+	contentAccessor contentAccessor
+}
+
+// getPodcastSpotlightForNewsletter is only used when no article has any of
+// the user's vocabulary, since a word is easier to find in an article than an episode
+func getPodcastSpotlightForNewsletter(c ctx.LogContext, input getPodcastSpotlightForNewsletterInput) (*podcastSpotlight, error) {
+	if newsletterPreferences := input.userAccessor.getUserNewsletterPreferences(); newsletterPreferences == nil || !newsletterPreferences.ShouldIncludeLemmaReinforcementSpotlight {
+		return nil, nil
+	}
+	userEntriesByID := make(map[uservocabulary.UserVocabularyEntryID]uservocabulary.UserVocabularyEntry)
+	for _, entry := range input.userAccessor.getUserVocabularyEntries() {
+		userEntriesByID[entry.ID] = entry
+	}
+	for _, potentialSpotlight := range getOrderedListOfPotentialSpotlights(input.userAccessor) {
+		entry, ok := userEntriesByID[potentialSpotlight]
+		if !ok {
+			continue
+		}
+		lemmaIDPhrases, err := entry.AsLemmaIDPhrases()
+		switch {
+		case err != nil:
+			c.Infof("Error generating lemma ID phrases for entry %s: %s", entry.ID, err.Error())
+			continue
+		case len(lemmaIDPhrases) == 0:
+			continue
+		}
+		episodes, err := input.podcastAccessor.LookupPodcastEpisodesForLemma(input.userAccessor.getReadingLevel(), lemmaIDPhrases)
+		if err != nil {
+			return nil, err
+		}
+		for _, episode := range episodes {
+			// The spotlight is rendered as focus content, which needs an image
+			podcastMetadata, err := input.podcastAccessor.GetPodcastMetadataForSourceID(episode.SourceID)
+			switch {
+			case err != nil:
+				c.Errorf("Error getting podcast metadata for podcast %s: %s", episode.SourceID, err.Error())
+				continue
+			case podcastMetadata.ImageURL == nil:
+				continue
+			}
+			podcastLink, err := makeLinkFromPodcast(c, input.podcastAccessor, input.contentAccessor, episode, input.emailRecordID)
+			switch {
+			case err != nil:
+				return nil, err
+			case podcastLink == nil || podcastLink.PodcastImageURL == nil:
+				continue
+			}
+			if err := input.userAccessor.insertSpotlightReinforcementRecord(potentialSpotlight); err != nil {
+				return nil, err
+			}
+			return &podcastSpotlight{
+				LemmaText: entry.VocabularyDisplay,
+				Podcast:   *podcastLink,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
 func getOrderedListOfPotentialSpotlights(userAccessor userPreferencesAccessor) []uservocabulary.UserVocabularyEntryID {
 	userVocabularyReinforcementSpotlightRecords := userAccessor.getSpotlightRecordsOrderedBySentOn()
 	userVocabularySpotlightRecordSentOnTimeByID := make(map[uservocabulary.UserVocabularyEntryID]time.Time)
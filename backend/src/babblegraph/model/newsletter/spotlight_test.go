@@ -4,6 +4,7 @@ import (
 	"babblegraph/model/content"
 	"babblegraph/model/documents"
 	"babblegraph/model/email"
+	"babblegraph/model/podcasts"
 	"babblegraph/model/usernewsletterpreferences"
 	"babblegraph/model/users"
 	"babblegraph/model/uservocabulary"
@@ -362,3 +363,80 @@ func TestSpotlightRecordsForTrackedLemmaWithoutSpotlight(t *testing.T) {
 		}
 	}
 }
+
+func TestPodcastSpotlightForLemmaWithoutArticle(t *testing.T) {
+	c := ctx.GetDefaultLogContext()
+	expectedEntryID := uservocabulary.UserVocabularyEntryID("word3")
+	userAccessor := &testUserAccessor{
+		languageCode:        wordsmith.LanguageCodeSpanish,
+		doesUserHaveAccount: true,
+		readingLevel: &userReadingLevel{
+			LowerBound: 30,
+			UpperBound: 80,
+		},
+		userNewsletterPreferences: &usernewsletterpreferences.UserNewsletterPreferences{
+			ShouldIncludeLemmaReinforcementSpotlight: true,
+			LanguageCode:                             wordsmith.LanguageCodeSpanish,
+		},
+		spotlightRecords: []uservocabulary.UserVocabularySpotlightRecord{
+			{
+				LanguageCode:      wordsmith.LanguageCodeSpanish,
+				VocabularyEntryID: "word1",
+				LastSentOn:        time.Now(),
+			},
+		},
+		vocabularyEntries: []uservocabulary.UserVocabularyEntry{
+			{
+				ID:                "word1",
+				VocabularyID:      ptr.String("word1"),
+				VocabularyType:    uservocabulary.VocabularyTypeLemma,
+				VocabularyDisplay: "word1",
+			}, {
+				ID:                "word2",
+				VocabularyID:      ptr.String("word2"),
+				VocabularyType:    uservocabulary.VocabularyTypeLemma,
+				VocabularyDisplay: "word2",
+			}, {
+				ID:                expectedEntryID,
+				VocabularyID:      ptr.String(string(expectedEntryID)),
+				VocabularyType:    uservocabulary.VocabularyTypeLemma,
+				VocabularyDisplay: string(expectedEntryID),
+			},
+		},
+	}
+	var episodes []podcasts.Episode
+	for _, lemmas := range []string{"word1", "word4 word3 word5"} {
+		episode := getDefaultPodcast(content.TopicID("topicid-art"))
+		episode.LemmatizedTranscript = ptr.String(lemmas)
+		episodes = append(episodes, episode)
+	}
+	podcastAccessor := &testPodcastAccessor{
+		languageCode: wordsmith.LanguageCodeSpanish,
+		validSourceIDs: []content.SourceID{
+			content.SourceID("test-source"),
+		},
+		userNewsletterPreferences: usernewsletterpreferences.UserNewsletterPreferences{
+			PodcastPreferences: usernewsletterpreferences.PodcastPreferences{
+				IncludeExplicitPodcasts: true,
+				ArePodcastsEnabled:      true,
+			},
+		},
+		podcastEpisodes: episodes,
+	}
+	spotlight, err := getPodcastSpotlightForNewsletter(c, getPodcastSpotlightForNewsletterInput{
+		emailRecordID:   email.NewEmailRecordID(),
+		userAccessor:    userAccessor,
+		podcastAccessor: podcastAccessor,
+		contentAccessor: &testContentAccessor{},
+	})
+	switch {
+	case err != nil:
+		t.Fatalf("Got error %s", err.Error())
+	case spotlight == nil:
+		t.Errorf("Expected a podcast spotlight, but got none")
+	case spotlight.LemmaText != expectedEntryID.Str():
+		t.Errorf("Expected lemma to be %s, but got %s", expectedEntryID.Str(), spotlight.LemmaText)
+	case len(userAccessor.insertedSpotlightRecords) != 1 || userAccessor.insertedSpotlightRecords[0] != expectedEntryID:
+		t.Errorf("Expected a spotlight record for %s, but got %+v", expectedEntryID, userAccessor.insertedSpotlightRecords)
+	}
+}
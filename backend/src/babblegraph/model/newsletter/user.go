@@ -0,0 +1,47 @@
+package newsletter
+
+import (
+	"babblegraph/model/usernewsletterpreferences"
+	"babblegraph/model/users"
+	"babblegraph/util/ctx"
+	"babblegraph/wordsmith"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateNewsletterVersion2ForUser creates a newsletter, or a weekly digest for users
+// on that cadence, with the default accessors.
+// This inserts the email record and the user documents, podcasts, and advertisements
+// that reference it, so previews need to use a transaction that is rolled back.
+func CreateNewsletterVersion2ForUser(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, languageCode wordsmith.LanguageCode, dateOfSendUTCMidnight time.Time) (*NewsletterVersion2, error) {
+	userAccessor, err := GetDefaultUserPreferencesAccessor(c, tx, userID, languageCode, dateOfSendUTCMidnight)
+	if err != nil {
+		return nil, err
+	}
+	contentAccessor, err := GetDefaultContentAccessor(tx, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	podcastAccessor, err := GetDefaultPodcastAccessor(c, tx, languageCode, userID)
+	if err != nil {
+		return nil, err
+	}
+	advertisementAccessor, err := GetDefaultAdvertisementAccessor(tx, userID, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	input := CreateNewsletterVersion2Input{
+		WordsmithAccessor:     GetDefaultWordsmithAccessor(),
+		EmailAccessor:         GetDefaultEmailAccessor(tx),
+		UserAccessor:          userAccessor,
+		DocsAccessor:          GetDefaultDocumentsAccessor(),
+		ContentAccessor:       contentAccessor,
+		PodcastAccessor:       podcastAccessor,
+		AdvertisementAccessor: advertisementAccessor,
+	}
+	if userAccessor.getUserNewsletterSchedule().GetCadence() == usernewsletterpreferences.NewsletterCadenceWeeklyDigest {
+		return CreateWeeklyDigest(c, dateOfSendUTCMidnight, input)
+	}
+	return CreateNewsletterVersion2(c, dateOfSendUTCMidnight, input)
+}
@@ -5,6 +5,7 @@ import (
 	"babblegraph/model/content"
 	"babblegraph/model/documents"
 	"babblegraph/model/email"
+	"babblegraph/model/localization"
 	"babblegraph/model/podcasts"
 	"babblegraph/model/routes"
 	"babblegraph/model/useraccounts"
@@ -30,6 +31,9 @@ type NewsletterVersion2 struct {
 	EmailRecordID email.ID               `json:"email_record_id"`
 	LanguageCode  wordsmith.LanguageCode `json:"language_code"`
 	Body          NewsletterVersion2Body `json:"body"`
+	// This is only set for users on the weekly digest cadence,
+	// in which case it is rendered instead of Body
+	WeeklyDigestBody *WeeklyDigestBody `json:"weekly_digest_body,omitempty"`
 }
 
 type NewsletterVersion2Body struct {
@@ -86,6 +90,10 @@ type CreateNewsletterVersion2Input struct {
 }
 
 func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time, input CreateNewsletterVersion2Input) (*NewsletterVersion2, error) {
+	locale, err := localization.GetLocaleForLanguageCode(input.UserAccessor.getLanguageCode())
+	if err != nil {
+		return nil, err
+	}
 	emailRecordID := email.NewEmailRecordID()
 	if err := input.EmailAccessor.InsertEmailRecord(emailRecordID, input.UserAccessor.getUserID()); err != nil {
 		return nil, err
@@ -96,6 +104,7 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 	numberOfDocumentsInNewsletter := input.UserAccessor.getUserNewsletterSchedule().GetNumberOfDocuments()
 	documentSections, documentIDs, err := getDocumentSections(c, numberOfDocumentsInNewsletter, getDocumentSectionsInput{
 		emailRecordID:   emailRecordID,
+		locale:          *locale,
 		userAccessor:    input.UserAccessor,
 		docsAccessor:    input.DocsAccessor,
 		contentAccessor: input.ContentAccessor,
@@ -119,10 +128,13 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 	var out []Section
 	out = append(out, documentSections[0])
 	documentSections = append([]Section{}, documentSections[1:]...)
-	if spotlightRecord != nil {
+	userSubscriptionLevel := input.UserAccessor.getUserSubscriptionLevel()
+	switch {
+	case spotlightRecord != nil:
 		out = append(out, Section{
-			// TODO: make dynamic
-			Title: fmt.Sprintf("Tu vocabulario en las noticias: %s", spotlightRecord.LemmaText),
+			Title: localization.GetMessage(*locale, localization.MessageKeyNewsletterSpotlightSectionTitle, localization.Params{
+				"lemma": spotlightRecord.LemmaText,
+			}),
 			FocusContent: &SectionFocusContent{
 				Title:       *spotlightRecord.Document.Title,
 				ImageURL:    *spotlightRecord.Document.ImageURL,
@@ -130,9 +142,31 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 				URL:         spotlightRecord.Document.URL,
 			},
 		})
+	case canSubscriptionLevelReceivePodcasts(userSubscriptionLevel):
+		podcastSpotlight, err := getPodcastSpotlightForNewsletter(c, getPodcastSpotlightForNewsletterInput{
+			emailRecordID:   emailRecordID,
+			userAccessor:    input.UserAccessor,
+			podcastAccessor: input.PodcastAccessor,
+			contentAccessor: input.ContentAccessor,
+		})
+		switch {
+		case err != nil:
+			return nil, err
+		case podcastSpotlight != nil:
+			out = append(out, Section{
+				Title: localization.GetMessage(*locale, localization.MessageKeyNewsletterSpotlightSectionTitle, localization.Params{
+					"lemma": podcastSpotlight.LemmaText,
+				}),
+				FocusContent: &SectionFocusContent{
+					Title:       podcastSpotlight.Podcast.EpisodeTitle,
+					ImageURL:    *podcastSpotlight.Podcast.PodcastImageURL,
+					Description: podcastSpotlight.Podcast.EpisodeDescription,
+					URL:         podcastSpotlight.Podcast.ListenURL,
+				},
+			})
+		}
 	}
 	var premiumLink *PremiumAdvertisement
-	userSubscriptionLevel := input.UserAccessor.getUserSubscriptionLevel()
 	switch {
 	case userSubscriptionLevel == nil:
 		return nil, nil
@@ -149,14 +183,13 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 					URL:   advertisement.AdditionalAdvertisementLink.URL,
 				})
 			}
-			// TODO: make this all dynamic
 			otherLinks = append(otherLinks, SectionLink{
-				Title:    "Si no quieres ver más anuncios, inscribete a Babblegraph Premium",
-				BodyText: ptr.String("Con Babblegraph Premium, no verás anuncios como esto. También, tendrás acceso a herramientas exclusivas: como recibir podcasts en el email."),
+				Title:    localization.GetMessage(*locale, localization.MessageKeyNewsletterAdvertisementPremiumLinkTitle, nil),
+				BodyText: ptr.String(localization.GetMessage(*locale, localization.MessageKeyNewsletterAdvertisementPremiumLinkBody, nil)),
 				URL:      advertisement.PremiumLink,
 			})
 			out = append(out, Section{
-				Title: "Algo que nos gusta",
+				Title: localization.GetMessage(*locale, localization.MessageKeyNewsletterAdvertisementSectionTitle, nil),
 				FocusContent: &SectionFocusContent{
 					Title:       fmt.Sprintf("%s*", advertisement.Title),
 					ImageURL:    advertisement.ImageURL,
@@ -166,9 +199,9 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 				OtherLinks: otherLinks,
 			})
 			advertisingDisclaimer = &AdvertisingDisclaimer{
-				Text: "* Asociarnos con excelentes productos y marcas permite que Babblegraph siga funcionando. Podemos ganar una comisión si compra algo a través de uno de estos enlaces.",
+				Text: localization.GetMessage(*locale, localization.MessageKeyNewsletterAdvertisingDisclaimerText, nil),
 				AdvertisingPolicyLink: NewsletterLink{
-					Text: "Puedes obtener más información sobre anuncios como estos aquí",
+					Text: localization.GetMessage(*locale, localization.MessageKeyNewsletterAdvertisingDisclaimerLinkText, nil),
 					URL:  advertisement.AdvertisementPolicyLink,
 				},
 			}
@@ -178,6 +211,7 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 		*userSubscriptionLevel == useraccounts.SubscriptionLevelPremium:
 		podcastSection, err := getPodcastSectionForUser(c, getPodcastSectionForUserInput{
 			emailRecordID:   emailRecordID,
+			locale:          *locale,
 			userAccessor:    input.UserAccessor,
 			podcastAccessor: input.PodcastAccessor,
 			contentAccessor: input.ContentAccessor,
@@ -190,21 +224,9 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 		default:
 			out = append(out, *podcastSection)
 		}
-		needsPaymentMethod := input.UserAccessor.getSubscriptionPaymentState() != nil && *input.UserAccessor.getSubscriptionPaymentState() == billing.PaymentStateTrialNoPaymentMethod
-		isAccountOldEnoughForPaymentMethodReminder := input.UserAccessor.getUserCreatedDate().Add(addPaymentMethodBannerWaitingPeriod).Before(time.Now())
-		if *userSubscriptionLevel == useraccounts.SubscriptionLevelPremium && needsPaymentMethod && isAccountOldEnoughForPaymentMethodReminder {
-			checkoutLink, err := routes.MakePremiumSubscriptionCheckoutLink(input.UserAccessor.getUserID())
-			if err != nil {
-				return nil, err
-			}
-			// TODO(multiple-languages): make this dynamic
-			premiumLink = &PremiumAdvertisement{
-				PreText: "¿Te gusta usar Babblegraph? Asegurate de que sigas tener acceso después de acabar tu prueba gratis.",
-				Link: NewsletterLink{
-					URL:  *checkoutLink,
-					Text: "Haz clic aquí para agregar un método de pago.",
-				},
-			}
+		premiumLink, err = getAddPaymentMethodBanner(*locale, input.UserAccessor)
+		if err != nil {
+			return nil, err
 		}
 	default:
 		return nil, fmt.Errorf("Unrecognized subscription level: %s", *userSubscriptionLevel)
@@ -212,53 +234,86 @@ func CreateNewsletterVersion2(c ctx.LogContext, dateOfSendMidnightUTC time.Time,
 	for _, section := range documentSections {
 		out = append(out, section)
 	}
+	accountSection, err := getAccountSection(*locale, input.UserAccessor)
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, *accountSection)
+	return &NewsletterVersion2{
+		UserID:        input.UserAccessor.getUserID(),
+		EmailRecordID: emailRecordID,
+		LanguageCode:  input.UserAccessor.getLanguageCode(),
+		Body: NewsletterVersion2Body{
+			PremiumLink:           premiumLink,
+			Sections:              out,
+			AdvertisingDisclaimer: advertisingDisclaimer,
+		},
+	}, nil
+}
+
+// Account and billing
+
+func getAccountSection(locale localization.Locale, userAccessor userPreferencesAccessor) (*Section, error) {
 	var accountLinks []SectionLink
 	var reinforcementLink, setTopicsLink, preferencesLink *string
-	if input.UserAccessor.getDoesUserHaveAccount() {
+	var err error
+	if userAccessor.getDoesUserHaveAccount() {
 		reinforcementLink = ptr.String(routes.MakeLoginLinkWithReinforcementRedirect())
 		setTopicsLink = ptr.String(routes.MakeLoginLinkWithContentTopicsRedirect())
 		preferencesLink = ptr.String(routes.MakeLoginLinkWithNewsletterPreferencesRedirect())
 	} else {
-		reinforcementLink, err = routes.MakeWordReinforcementLink(input.UserAccessor.getUserID())
+		reinforcementLink, err = routes.MakeWordReinforcementLink(userAccessor.getUserID())
 		if err != nil {
 			return nil, err
 		}
-		setTopicsLink, err = routes.MakeSetTopicsLink(input.UserAccessor.getUserID())
+		setTopicsLink, err = routes.MakeSetTopicsLink(userAccessor.getUserID())
 		if err != nil {
 			return nil, err
 		}
-		preferencesLink, err = routes.MakeNewsletterPreferencesLink(input.UserAccessor.getUserID())
+		preferencesLink, err = routes.MakeNewsletterPreferencesLink(userAccessor.getUserID())
 		if err != nil {
 			return nil, err
 		}
 	}
-	// TODO: make dynamic
 	accountLinks = append(accountLinks, SectionLink{
-		Title: "¿Has aprendido una palabra nueva? Haz clic aquí para añadirla a tu lista de vocabulario",
+		Title: localization.GetMessage(locale, localization.MessageKeyNewsletterAccountSectionReinforcementLink, nil),
 		URL:   *reinforcementLink,
 	})
-	if len(input.UserAccessor.getUserTopics()) == 0 {
+	if len(userAccessor.getUserTopics()) == 0 {
 		accountLinks = append(accountLinks, SectionLink{
-			Title: "Puedes escoger temas interesantes para personalizar el próximo boletín",
+			Title: localization.GetMessage(locale, localization.MessageKeyNewsletterAccountSectionSetTopicsLink, nil),
 			URL:   *setTopicsLink,
 		})
 	}
 	accountLinks = append(accountLinks, SectionLink{
-		Title: "¿Estás recibiendo demasiados emails? ¿Los emails tienen demasiadas historias? Puedes cambiar eso aquí.",
+		Title: localization.GetMessage(locale, localization.MessageKeyNewsletterAccountSectionPreferencesLink, nil),
 		URL:   *preferencesLink,
 	})
-	out = append(out, Section{
-		Title:      "Enlaces para gestionar tu suscripción",
+	return &Section{
+		Title:      localization.GetMessage(locale, localization.MessageKeyNewsletterAccountSectionTitle, nil),
 		OtherLinks: accountLinks,
-	})
-	return &NewsletterVersion2{
-		UserID:        input.UserAccessor.getUserID(),
-		EmailRecordID: emailRecordID,
-		LanguageCode:  input.UserAccessor.getLanguageCode(),
-		Body: NewsletterVersion2Body{
-			PremiumLink:           premiumLink,
-			Sections:              out,
-			AdvertisingDisclaimer: advertisingDisclaimer,
+	}, nil
+}
+
+// getAddPaymentMethodBanner returns a nil banner unless the user is
+// on a premium trial without a payment method and has had their
+// account for long enough that a reminder isn't pushy
+func getAddPaymentMethodBanner(locale localization.Locale, userAccessor userPreferencesAccessor) (*PremiumAdvertisement, error) {
+	userSubscriptionLevel := userAccessor.getUserSubscriptionLevel()
+	needsPaymentMethod := userAccessor.getSubscriptionPaymentState() != nil && *userAccessor.getSubscriptionPaymentState() == billing.PaymentStateTrialNoPaymentMethod
+	isAccountOldEnoughForPaymentMethodReminder := userAccessor.getUserCreatedDate().Add(addPaymentMethodBannerWaitingPeriod).Before(time.Now())
+	if userSubscriptionLevel == nil || *userSubscriptionLevel != useraccounts.SubscriptionLevelPremium || !needsPaymentMethod || !isAccountOldEnoughForPaymentMethodReminder {
+		return nil, nil
+	}
+	checkoutLink, err := routes.MakePremiumSubscriptionCheckoutLink(userAccessor.getUserID())
+	if err != nil {
+		return nil, err
+	}
+	return &PremiumAdvertisement{
+		PreText: localization.GetMessage(locale, localization.MessageKeyNewsletterAddPaymentMethodPreText, nil),
+		Link: NewsletterLink{
+			URL:  *checkoutLink,
+			Text: localization.GetMessage(locale, localization.MessageKeyNewsletterAddPaymentMethodLinkText, nil),
 		},
 	}, nil
 }
@@ -276,6 +331,7 @@ const (
 
 type getDocumentSectionsInput struct {
 	emailRecordID   email.ID
+	locale          localization.Locale
 	userAccessor    userPreferencesAccessor
 	docsAccessor    documentAccessor
 	contentAccessor contentAccessor
@@ -388,7 +444,9 @@ func getDocumentSections(c ctx.LogContext, numberOfDocumentsInNewsletter int, in
 			case len(otherLinks) <= numberOfDocumentsInSection:
 				var description *string
 				if link.Domain != nil {
-					description = ptr.String(fmt.Sprintf("por %s", link.Domain.Name))
+					description = ptr.String(localization.GetMessage(input.locale, localization.MessageKeyNewsletterDocumentLinkDomain, localization.Params{
+						"domain": link.Domain.Name,
+					}))
 				}
 				otherLinks = append(otherLinks, SectionLink{
 					Title:    deref.String(link.Title, document.Document.URL),
@@ -401,8 +459,7 @@ func getDocumentSections(c ctx.LogContext, numberOfDocumentsInNewsletter int, in
 				break
 			}
 		}
-		// TODO: make this dynamic
-		sectionTitle := "En las noticias"
+		sectionTitle := localization.GetMessage(input.locale, localization.MessageKeyNewsletterDocumentSectionDefaultTitle, nil)
 		displayName, err := input.contentAccessor.GetDisplayNameByTopicID(topicIDs[i])
 		if err != nil {
 			c.Errorf("Error generating display name: %s", err.Error())
@@ -411,8 +468,7 @@ func getDocumentSections(c ctx.LogContext, numberOfDocumentsInNewsletter int, in
 		}
 		var otherLinksTitle *string
 		if len(otherLinks) > 0 {
-			// TODO: make this dynamic
-			otherLinksTitle = ptr.String("Otros enlaces")
+			otherLinksTitle = ptr.String(localization.GetPluralMessage(input.locale, localization.MessageKeyNewsletterDocumentSectionOtherLinksTitle, len(otherLinks), nil))
 		}
 		out = append(out, Section{
 			Title:           sectionTitle,
@@ -426,6 +482,7 @@ func getDocumentSections(c ctx.LogContext, numberOfDocumentsInNewsletter int, in
 
 type getPodcastSectionForUserInput struct {
 	emailRecordID   email.ID
+	locale          localization.Locale
 	userAccessor    userPreferencesAccessor
 	podcastAccessor podcastAccessor
 	contentAccessor contentAccessor
@@ -435,7 +492,7 @@ func getPodcastSectionForUser(c ctx.LogContext, input getPodcastSectionForUserIn
 	allUserTopics := input.userAccessor.getUserTopics()
 	rand.Seed(time.Now().UnixNano())
 	rand.Shuffle(len(allUserTopics), func(i, j int) { allUserTopics[i], allUserTopics[j] = allUserTopics[j], allUserTopics[i] })
-	podcastsByTopic, err := input.podcastAccessor.LookupPodcastEpisodesForTopics(allUserTopics)
+	podcastsByTopic, err := input.podcastAccessor.LookupPodcastEpisodesForTopics(allUserTopics, input.userAccessor.getReadingLevel(), getLemmaIDPhrases(c, input.userAccessor))
 	if err != nil {
 		return nil, err
 	}
@@ -484,15 +541,29 @@ func getPodcastSectionForUser(c ctx.LogContext, input getPodcastSectionForUserIn
 	if len(otherLinks) == 0 && focusContent == nil {
 		return nil, nil
 	}
-	// TODO: make this dynamic
 	return &Section{
-		Title:           "Podcasts para ti",
+		Title:           localization.GetMessage(input.locale, localization.MessageKeyNewsletterPodcastSectionTitle, nil),
 		FocusContent:    focusContent,
 		OtherLinks:      otherLinks,
-		OtherLinksTitle: ptr.String("Otros episodios"),
+		OtherLinksTitle: ptr.String(localization.GetPluralMessage(input.locale, localization.MessageKeyNewsletterPodcastSectionOtherLinksTitle, len(otherLinks), nil)),
 	}, nil
 }
 
+// Podcasts are only included for premium subscribers
+func canSubscriptionLevelReceivePodcasts(subscriptionLevel *useraccounts.SubscriptionLevel) bool {
+	if subscriptionLevel == nil {
+		return false
+	}
+	switch *subscriptionLevel {
+	case useraccounts.SubscriptionLevelBetaPremium,
+		useraccounts.SubscriptionLevelLegacyFriendsAndFamily,
+		useraccounts.SubscriptionLevelPremium:
+		return true
+	default:
+		return false
+	}
+}
+
 func isDocumentFocusContentEligible(doc documents.Document) bool {
 	documentMetadata := doc.Metadata
 	hasImage := documentMetadata.Image != nil && len(*documentMetadata.Image) > 0
@@ -0,0 +1,318 @@
+package newsletter
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/model/documents"
+	"babblegraph/model/email"
+	"babblegraph/model/localization"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/uservocabulary"
+	"babblegraph/util/ctx"
+	"babblegraph/util/deref"
+	"babblegraph/util/ptr"
+	"babblegraph/util/text"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MODEL
+
+// WeeklyDigestBody is sent instead of NewsletterVersion2Body
+// to users who have chosen the weekly digest cadence. It only
+// uses documents from the past week.
+type WeeklyDigestBody struct {
+	Heading           string                `json:"heading"`
+	PremiumLink       *PremiumAdvertisement `json:"premium_link,omitempty"`
+	TopicSections     []Section             `json:"topic_sections"`
+	VocabularySection *Section              `json:"vocabulary_section,omitempty"`
+	PodcastSection    *Section              `json:"podcast_section,omitempty"`
+	AccountSection    Section               `json:"account_section"`
+}
+
+// Query
+
+const (
+	maximumNumberOfTopicSectionsInWeeklyDigest    int = 4
+	maximumNumberOfDocumentsInWeeklyDigestSection int = 3
+
+	maximumNumberOfVocabularyEntriesInWeeklyDigest int = 5
+	// Each vocabulary entry is a separate search, so this
+	// bounds the work done for users with large vocabularies
+	maximumNumberOfVocabularyEntriesSearchedForWeeklyDigest int = 25
+)
+
+func CreateWeeklyDigest(c ctx.LogContext, dateOfSendMidnightUTC time.Time, input CreateNewsletterVersion2Input) (*NewsletterVersion2, error) {
+	locale, err := localization.GetLocaleForLanguageCode(input.UserAccessor.getLanguageCode())
+	if err != nil {
+		return nil, err
+	}
+	emailRecordID := email.NewEmailRecordID()
+	if err := input.EmailAccessor.InsertEmailRecord(emailRecordID, input.UserAccessor.getUserID()); err != nil {
+		return nil, err
+	}
+	if !input.UserAccessor.getUserNewsletterSchedule().IsSendRequested(dateOfSendMidnightUTC.Weekday()) {
+		return nil, nil
+	}
+	userSubscriptionLevel := input.UserAccessor.getUserSubscriptionLevel()
+	if userSubscriptionLevel == nil {
+		return nil, nil
+	}
+	topicSections, err := getWeeklyDigestTopicSections(c, getDocumentSectionsInput{
+		emailRecordID:   emailRecordID,
+		locale:          *locale,
+		userAccessor:    input.UserAccessor,
+		docsAccessor:    input.DocsAccessor,
+		contentAccessor: input.ContentAccessor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	vocabularySection, err := getWeeklyDigestVocabularySection(c, getDocumentSectionsInput{
+		emailRecordID:   emailRecordID,
+		locale:          *locale,
+		userAccessor:    input.UserAccessor,
+		docsAccessor:    input.DocsAccessor,
+		contentAccessor: input.ContentAccessor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var podcastSection *Section
+	switch *userSubscriptionLevel {
+	case useraccounts.SubscriptionLevelLegacy:
+		// The digest doesn't carry advertisements or podcasts
+		// for legacy subscribers
+	case useraccounts.SubscriptionLevelBetaPremium,
+		useraccounts.SubscriptionLevelLegacyFriendsAndFamily,
+		useraccounts.SubscriptionLevelPremium:
+		podcastSection, err = getPodcastSectionForUser(c, getPodcastSectionForUserInput{
+			emailRecordID:   emailRecordID,
+			locale:          *locale,
+			userAccessor:    input.UserAccessor,
+			podcastAccessor: input.PodcastAccessor,
+			contentAccessor: input.ContentAccessor,
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Unrecognized subscription level: %s", *userSubscriptionLevel)
+	}
+	premiumLink, err := getAddPaymentMethodBanner(*locale, input.UserAccessor)
+	if err != nil {
+		return nil, err
+	}
+	accountSection, err := getAccountSection(*locale, input.UserAccessor)
+	if err != nil {
+		return nil, err
+	}
+	return &NewsletterVersion2{
+		UserID:        input.UserAccessor.getUserID(),
+		EmailRecordID: emailRecordID,
+		LanguageCode:  input.UserAccessor.getLanguageCode(),
+		WeeklyDigestBody: &WeeklyDigestBody{
+			Heading:           localization.GetMessage(*locale, localization.MessageKeyWeeklyDigestHeading, nil),
+			PremiumLink:       premiumLink,
+			TopicSections:     topicSections,
+			VocabularySection: vocabularySection,
+			PodcastSection:    podcastSection,
+			AccountSection:    *accountSection,
+		},
+	}, nil
+}
+
+// getWeeklyDigestTopicSections returns a section with the best
+// recent documents for each topic, starting with the user's topics
+func getWeeklyDigestTopicSections(c ctx.LogContext, input getDocumentSectionsInput) ([]Section, error) {
+	topics := getSectionTopicsForUser(input.userAccessor, input.contentAccessor)
+	allowableSourceIDs := input.userAccessor.getAllowableSources()
+	documentIDsHashSet := make(map[documents.DocumentID]bool)
+	var out []Section
+	for _, t := range topics {
+		if len(out) >= maximumNumberOfTopicSectionsInWeeklyDigest {
+			break
+		}
+		documentsForTopic, err := input.docsAccessor.GetDocumentsForUser(c, getDocumentsForUserInput{
+			getDocumentsBaseInput: getDocumentsBaseInput{
+				LanguageCode:        input.userAccessor.getLanguageCode(),
+				ExcludedDocumentIDs: input.userAccessor.getSentDocumentIDs(),
+				ValidSourceIDs:      allowableSourceIDs,
+				MinimumReadingLevel: ptr.Int64(input.userAccessor.getReadingLevel().LowerBound),
+				MaximumReadingLevel: ptr.Int64(input.userAccessor.getReadingLevel().UpperBound),
+			},
+			Topic: t.Ptr(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		recentDocuments := append([]documents.DocumentWithScore{}, documentsForTopic.RecentDocuments...)
+		sort.SliceStable(recentDocuments, func(i, j int) bool {
+			return recentDocuments[i].Score.GreaterThan(recentDocuments[j].Score)
+		})
+		section, err := makeWeeklyDigestTopicSection(c, t, recentDocuments, documentIDsHashSet, input)
+		switch {
+		case err != nil:
+			return nil, err
+		case section == nil:
+			continue
+		}
+		out = append(out, *section)
+	}
+	return out, nil
+}
+
+func makeWeeklyDigestTopicSection(c ctx.LogContext, topicID content.TopicID, recentDocuments []documents.DocumentWithScore, documentIDsHashSet map[documents.DocumentID]bool, input getDocumentSectionsInput) (*Section, error) {
+	var focusContent *SectionFocusContent
+	var otherLinks []SectionLink
+	for _, document := range recentDocuments {
+		numberOfDocumentsInSection := len(otherLinks)
+		if focusContent != nil {
+			numberOfDocumentsInSection++
+		}
+		if numberOfDocumentsInSection >= maximumNumberOfDocumentsInWeeklyDigestSection {
+			break
+		}
+		if documentIDsHashSet[document.Document.ID] {
+			continue
+		}
+		link, err := makeLinkFromDocument(c, makeLinkFromDocumentInput{
+			emailRecordID:   input.emailRecordID,
+			userAccessor:    input.userAccessor,
+			contentAccessor: input.contentAccessor,
+			document:        document.Document,
+		})
+		switch {
+		case err != nil:
+			return nil, err
+		case link == nil:
+			continue
+		}
+		documentIDsHashSet[document.Document.ID] = true
+		if focusContent == nil && isDocumentFocusContentEligible(document.Document) {
+			focusContent = &SectionFocusContent{
+				Title:       *link.Title,
+				ImageURL:    *link.ImageURL,
+				Description: *link.Description,
+				URL:         link.URL,
+			}
+			continue
+		}
+		var description *string
+		if link.Domain != nil {
+			description = ptr.String(localization.GetMessage(input.locale, localization.MessageKeyNewsletterDocumentLinkDomain, localization.Params{
+				"domain": link.Domain.Name,
+			}))
+		}
+		otherLinks = append(otherLinks, SectionLink{
+			Title:    deref.String(link.Title, document.Document.URL),
+			BodyText: description,
+			URL:      link.URL,
+		})
+	}
+	if focusContent == nil && len(otherLinks) == 0 {
+		return nil, nil
+	}
+	sectionTitle := localization.GetMessage(input.locale, localization.MessageKeyNewsletterDocumentSectionDefaultTitle, nil)
+	displayName, err := input.contentAccessor.GetDisplayNameByTopicID(topicID)
+	if err != nil {
+		c.Errorf("Error generating display name: %s", err.Error())
+	} else {
+		sectionTitle = text.ToTitleCaseForLanguage(*displayName, input.userAccessor.getLanguageCode())
+	}
+	var otherLinksTitle *string
+	if len(otherLinks) > 0 {
+		otherLinksTitle = ptr.String(localization.GetMessage(input.locale, localization.MessageKeyWeeklyDigestTopicSectionOtherLinks, nil))
+	}
+	return &Section{
+		Title:           sectionTitle,
+		FocusContent:    focusContent,
+		OtherLinks:      otherLinks,
+		OtherLinksTitle: otherLinksTitle,
+	}, nil
+}
+
+type vocabularyEntryWithDocuments struct {
+	entry     uservocabulary.UserVocabularyEntry
+	documents []documents.DocumentWithScore
+}
+
+// getWeeklyDigestVocabularySection ranks the user's vocabulary
+// by how many of this week's documents contain each entry and links
+// each of the top entries to the best document that contains it
+func getWeeklyDigestVocabularySection(c ctx.LogContext, input getDocumentSectionsInput) (*Section, error) {
+	var entriesWithDocuments []vocabularyEntryWithDocuments
+	var numberOfEntriesSearched int
+	for _, entry := range input.userAccessor.getUserVocabularyEntries() {
+		if numberOfEntriesSearched >= maximumNumberOfVocabularyEntriesSearchedForWeeklyDigest {
+			break
+		}
+		lemmaIDPhrases, err := entry.AsLemmaIDPhrases()
+		switch {
+		case err != nil:
+			c.Infof("Error generating lemma ID phrases for entry %s: %s", entry.ID, err.Error())
+			continue
+		case len(lemmaIDPhrases) == 0:
+			continue
+		}
+		numberOfEntriesSearched++
+		documentsForEntry, err := input.docsAccessor.GetDocumentsForUserForLemma(c, getDocumentsForUserForLemmaInput{
+			getDocumentsBaseInput: getDocumentsBaseInput{
+				LanguageCode:        input.userAccessor.getLanguageCode(),
+				ValidSourceIDs:      input.userAccessor.getAllowableSources(),
+				MinimumReadingLevel: ptr.Int64(input.userAccessor.getReadingLevel().LowerBound),
+				MaximumReadingLevel: ptr.Int64(input.userAccessor.getReadingLevel().UpperBound),
+			},
+			LemmaIDPhrases: lemmaIDPhrases,
+		})
+		switch {
+		case err != nil:
+			return nil, err
+		case len(documentsForEntry) == 0:
+			continue
+		}
+		entriesWithDocuments = append(entriesWithDocuments, vocabularyEntryWithDocuments{
+			entry:     entry,
+			documents: documentsForEntry,
+		})
+	}
+	sort.SliceStable(entriesWithDocuments, func(i, j int) bool {
+		return len(entriesWithDocuments[i].documents) > len(entriesWithDocuments[j].documents)
+	})
+	var otherLinks []SectionLink
+	for _, e := range entriesWithDocuments {
+		if len(otherLinks) >= maximumNumberOfVocabularyEntriesInWeeklyDigest {
+			break
+		}
+		bestDocument := e.documents[0]
+		for _, d := range e.documents {
+			if d.Score.GreaterThan(bestDocument.Score) {
+				bestDocument = d
+			}
+		}
+		link, err := makeLinkFromDocument(c, makeLinkFromDocumentInput{
+			emailRecordID:   input.emailRecordID,
+			userAccessor:    input.userAccessor,
+			contentAccessor: input.contentAccessor,
+			document:        bestDocument.Document,
+		})
+		switch {
+		case err != nil:
+			return nil, err
+		case link == nil:
+			continue
+		}
+		otherLinks = append(otherLinks, SectionLink{
+			Title:    e.entry.VocabularyDisplay,
+			BodyText: ptr.String(localization.GetPluralMessage(input.locale, localization.MessageKeyWeeklyDigestVocabularyAppearances, len(e.documents), nil)),
+			URL:      link.URL,
+		})
+	}
+	if len(otherLinks) == 0 {
+		return nil, nil
+	}
+	return &Section{
+		Title:      localization.GetMessage(input.locale, localization.MessageKeyWeeklyDigestVocabularySectionTitle, nil),
+		OtherLinks: otherLinks,
+	}, nil
+}
@@ -0,0 +1,251 @@
+package newsletter
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/model/documents"
+	"babblegraph/model/email"
+	"babblegraph/model/localization"
+	"babblegraph/model/podcasts"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/usernewsletterpreferences"
+	"babblegraph/model/uservocabulary"
+	"babblegraph/util/ctx"
+	"babblegraph/util/ptr"
+	"babblegraph/wordsmith"
+	"testing"
+	"time"
+)
+
+func getWeeklyDigestTestUserAccessor(subscriptionLevel useraccounts.SubscriptionLevel) *testUserAccessor {
+	return &testUserAccessor{
+		languageCode:          wordsmith.LanguageCodeSpanish,
+		doesUserHaveAccount:   true,
+		userSubscriptionLevel: subscriptionLevel.Ptr(),
+		readingLevel: &userReadingLevel{
+			LowerBound: 30,
+			UpperBound: 80,
+		},
+		userTopics: []content.TopicID{
+			content.TopicID("test-art"),
+			content.TopicID("test-astronomy"),
+		},
+		allowableSourceIDs: []content.SourceID{
+			content.SourceID("test-source"),
+		},
+		userNewsletterSchedule: usernewsletterpreferences.TestNewsletterSchedule{
+			SendRequested: true,
+			Cadence:       usernewsletterpreferences.NewsletterCadenceWeeklyDigest,
+		},
+	}
+}
+
+func TestWeeklyDigestOnlyUsesThisWeeksDocuments(t *testing.T) {
+	c := ctx.GetDefaultLogContext()
+	userAccessor := getWeeklyDigestTestUserAccessor(useraccounts.SubscriptionLevelPremium)
+	contentAccessor := &testContentAccessor{}
+	emailRecordID := email.NewEmailRecordID()
+	recentTimestamp := ptr.Int64(time.Now().Add(-2 * 24 * time.Hour).Unix())
+	oldTimestamp := ptr.Int64(time.Now().Add(-14 * 24 * time.Hour).Unix())
+	var docs []documents.DocumentWithScore
+	for idx, input := range []getDefaultDocumentInput{
+		{Topics: []content.TopicID{"test-art"}, SeedJobIngestTimestamp: recentTimestamp},
+		{Topics: []content.TopicID{"test-art"}, SeedJobIngestTimestamp: recentTimestamp},
+		{Topics: []content.TopicID{"test-art"}, SeedJobIngestTimestamp: oldTimestamp},
+		{Topics: []content.TopicID{"test-astronomy"}, SeedJobIngestTimestamp: oldTimestamp},
+	} {
+		doc, _, err := getDefaultDocumentWithLink(c, idx, emailRecordID, contentAccessor, userAccessor, input)
+		if err != nil {
+			t.Fatalf("Error setting up test: %s", err.Error())
+		}
+		docs = append(docs, *doc)
+	}
+	digest, err := CreateWeeklyDigest(c, time.Now(), CreateNewsletterVersion2Input{
+		WordsmithAccessor:     &testWordsmithAccessor{},
+		EmailAccessor:         getTestEmailAccessor(),
+		UserAccessor:          userAccessor,
+		DocsAccessor:          &testDocsAccessor{documents: docs},
+		PodcastAccessor:       &testPodcastAccessor{},
+		ContentAccessor:       contentAccessor,
+		AdvertisementAccessor: &testAdvertisementAccessor{},
+	})
+	switch {
+	case err != nil:
+		t.Fatalf("Error creating weekly digest: %s", err.Error())
+	case digest == nil:
+		t.Fatalf("Expected weekly digest, but got none")
+	case digest.WeeklyDigestBody == nil:
+		t.Fatalf("Expected weekly digest body, but got none")
+	case len(digest.Body.Sections) != 0:
+		t.Errorf("Expected daily body to be empty, but got %d sections", len(digest.Body.Sections))
+	}
+	body := digest.WeeklyDigestBody
+	if len(body.TopicSections) != 1 {
+		t.Fatalf("Expected only the topic with recent documents to have a section, but got %d sections", len(body.TopicSections))
+	}
+	section := body.TopicSections[0]
+	if section.Title != "Art" {
+		t.Errorf("Expected section for Art, but got %s", section.Title)
+	}
+	if section.FocusContent == nil || section.FocusContent.Title != "Document 0" {
+		t.Errorf("Expected focus content to be Document 0, but got %+v", section.FocusContent)
+	}
+	if len(section.OtherLinks) != 1 || section.OtherLinks[0].Title != "Document 1" {
+		t.Errorf("Expected only Document 1 in other links, but got %+v", section.OtherLinks)
+	}
+	if body.VocabularySection != nil {
+		t.Errorf("Expected no vocabulary section for a user without vocabulary, but got %+v", body.VocabularySection)
+	}
+	if len(body.AccountSection.OtherLinks) == 0 {
+		t.Errorf("Expected account section to have links")
+	}
+}
+
+func TestWeeklyDigestVocabularySection(t *testing.T) {
+	c := ctx.GetDefaultLogContext()
+	userAccessor := getWeeklyDigestTestUserAccessor(useraccounts.SubscriptionLevelPremium)
+	userAccessor.vocabularyEntries = []uservocabulary.UserVocabularyEntry{
+		{
+			ID:                "word1",
+			VocabularyID:      ptr.String("word1"),
+			VocabularyType:    uservocabulary.VocabularyTypeLemma,
+			VocabularyDisplay: "word1",
+		}, {
+			ID:                "word2",
+			VocabularyID:      ptr.String("word2"),
+			VocabularyType:    uservocabulary.VocabularyTypeLemma,
+			VocabularyDisplay: "word2",
+		}, {
+			ID:                "word3",
+			VocabularyID:      ptr.String("word3"),
+			VocabularyType:    uservocabulary.VocabularyTypeLemma,
+			VocabularyDisplay: "word3",
+		},
+	}
+	contentAccessor := &testContentAccessor{}
+	emailRecordID := email.NewEmailRecordID()
+	recentTimestamp := ptr.Int64(time.Now().Add(-2 * 24 * time.Hour).Unix())
+	var docs []documents.DocumentWithScore
+	for idx, lemmas := range [][]wordsmith.LemmaID{
+		{"word1", "word2"},
+		{"word2"},
+		{"word2", "other"},
+	} {
+		doc, _, err := getDefaultDocumentWithLink(c, idx, emailRecordID, contentAccessor, userAccessor, getDefaultDocumentInput{
+			Topics:                 []content.TopicID{"test-art"},
+			Lemmas:                 lemmas,
+			SeedJobIngestTimestamp: recentTimestamp,
+		})
+		if err != nil {
+			t.Fatalf("Error setting up test: %s", err.Error())
+		}
+		docs = append(docs, *doc)
+	}
+	digest, err := CreateWeeklyDigest(c, time.Now(), CreateNewsletterVersion2Input{
+		WordsmithAccessor:     &testWordsmithAccessor{},
+		EmailAccessor:         getTestEmailAccessor(),
+		UserAccessor:          userAccessor,
+		DocsAccessor:          &testDocsAccessor{documents: docs},
+		PodcastAccessor:       &testPodcastAccessor{},
+		ContentAccessor:       contentAccessor,
+		AdvertisementAccessor: &testAdvertisementAccessor{},
+	})
+	switch {
+	case err != nil:
+		t.Fatalf("Error creating weekly digest: %s", err.Error())
+	case digest == nil || digest.WeeklyDigestBody == nil:
+		t.Fatalf("Expected weekly digest, but got none")
+	case digest.WeeklyDigestBody.VocabularySection == nil:
+		t.Fatalf("Expected vocabulary section, but got none")
+	}
+	links := digest.WeeklyDigestBody.VocabularySection.OtherLinks
+	expected := []struct {
+		word  string
+		count int
+	}{
+		{word: "word2", count: 3},
+		{word: "word1", count: 1},
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("Expected %d vocabulary links, but got %d", len(expected), len(links))
+	}
+	for idx, e := range expected {
+		if links[idx].Title != e.word {
+			t.Errorf("Expected vocabulary link %d to be %s, but got %s", idx, e.word, links[idx].Title)
+		}
+		expectedBodyText := localization.GetPluralMessage(localization.LocaleSpanish, localization.MessageKeyWeeklyDigestVocabularyAppearances, e.count, nil)
+		if links[idx].BodyText == nil || *links[idx].BodyText != expectedBodyText {
+			t.Errorf("Expected body text for %s to be %s, but got %v", e.word, expectedBodyText, links[idx].BodyText)
+		}
+	}
+}
+
+func TestWeeklyDigestPodcastsAreOnlyForPremium(t *testing.T) {
+	c := ctx.GetDefaultLogContext()
+	for _, tc := range []struct {
+		subscriptionLevel    useraccounts.SubscriptionLevel
+		expectPodcastSection bool
+	}{
+		{subscriptionLevel: useraccounts.SubscriptionLevelPremium, expectPodcastSection: true},
+		{subscriptionLevel: useraccounts.SubscriptionLevelLegacy, expectPodcastSection: false},
+	} {
+		userAccessor := getWeeklyDigestTestUserAccessor(tc.subscriptionLevel)
+		podcastAccessor := &testPodcastAccessor{
+			languageCode: wordsmith.LanguageCodeSpanish,
+			userNewsletterPreferences: usernewsletterpreferences.UserNewsletterPreferences{
+				PodcastPreferences: usernewsletterpreferences.PodcastPreferences{
+					ArePodcastsEnabled:      true,
+					IncludeExplicitPodcasts: true,
+				},
+			},
+			validSourceIDs: []content.SourceID{
+				content.SourceID("test-source"),
+			},
+			podcastEpisodes: []podcasts.Episode{
+				getDefaultPodcast(content.TopicID("test-art")),
+			},
+		}
+		digest, err := CreateWeeklyDigest(c, time.Now(), CreateNewsletterVersion2Input{
+			WordsmithAccessor:     &testWordsmithAccessor{},
+			EmailAccessor:         getTestEmailAccessor(),
+			UserAccessor:          userAccessor,
+			DocsAccessor:          &testDocsAccessor{},
+			PodcastAccessor:       podcastAccessor,
+			ContentAccessor:       &testContentAccessor{},
+			AdvertisementAccessor: &testAdvertisementAccessor{},
+		})
+		switch {
+		case err != nil:
+			t.Fatalf("Error creating weekly digest for %s: %s", tc.subscriptionLevel, err.Error())
+		case digest == nil || digest.WeeklyDigestBody == nil:
+			t.Fatalf("Expected weekly digest for %s, but got none", tc.subscriptionLevel)
+		case tc.expectPodcastSection && digest.WeeklyDigestBody.PodcastSection == nil:
+			t.Errorf("Expected podcast section for %s, but got none", tc.subscriptionLevel)
+		case !tc.expectPodcastSection && digest.WeeklyDigestBody.PodcastSection != nil:
+			t.Errorf("Expected no podcast section for %s, but got %+v", tc.subscriptionLevel, digest.WeeklyDigestBody.PodcastSection)
+		}
+	}
+}
+
+func TestWeeklyDigestNotRequested(t *testing.T) {
+	c := ctx.GetDefaultLogContext()
+	userAccessor := getWeeklyDigestTestUserAccessor(useraccounts.SubscriptionLevelPremium)
+	userAccessor.userNewsletterSchedule = usernewsletterpreferences.TestNewsletterSchedule{
+		SendRequested: false,
+		Cadence:       usernewsletterpreferences.NewsletterCadenceWeeklyDigest,
+	}
+	digest, err := CreateWeeklyDigest(c, time.Now(), CreateNewsletterVersion2Input{
+		WordsmithAccessor:     &testWordsmithAccessor{},
+		EmailAccessor:         getTestEmailAccessor(),
+		UserAccessor:          userAccessor,
+		DocsAccessor:          &testDocsAccessor{},
+		PodcastAccessor:       &testPodcastAccessor{},
+		ContentAccessor:       &testContentAccessor{},
+		AdvertisementAccessor: &testAdvertisementAccessor{},
+	})
+	if err != nil {
+		t.Fatalf("Error creating weekly digest: %s", err.Error())
+	}
+	if digest != nil {
+		t.Errorf("Expected no weekly digest, but got one")
+	}
+}
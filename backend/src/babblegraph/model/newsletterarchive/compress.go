@@ -0,0 +1,45 @@
+package newsletterarchive
+
+import (
+	"babblegraph/model/newsletter"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Newsletter bodies are mostly repeated JSON keys and URLs,
+// so they compress to a small fraction of their size
+
+func compressNewsletter(n newsletter.NewsletterVersion2) ([]byte, error) {
+	newsletterBytes, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(newsletterBytes); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressNewsletter(compressed []byte) (*newsletter.NewsletterVersion2, error) {
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	newsletterBytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var out newsletter.NewsletterVersion2
+	if err := json.Unmarshal(newsletterBytes, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,50 @@
+package newsletterarchive
+
+import (
+	"babblegraph/model/email"
+	"babblegraph/model/newsletter"
+	"babblegraph/model/users"
+	"babblegraph/util/ptr"
+	"babblegraph/wordsmith"
+	"reflect"
+	"testing"
+)
+
+func TestCompressNewsletterRoundTrip(t *testing.T) {
+	n := newsletter.NewsletterVersion2{
+		UserID:        users.UserID("test-user"),
+		EmailRecordID: email.ID("test-email-record"),
+		LanguageCode:  wordsmith.LanguageCodeSpanish,
+		Body: newsletter.NewsletterVersion2Body{
+			Sections: []newsletter.Section{
+				{
+					Title: "En las noticias",
+					FocusContent: &newsletter.SectionFocusContent{
+						Title:       "Un artículo",
+						ImageURL:    "https://www.babblegraph.com/image.jpg",
+						Description: "Una descripción",
+						URL:         "https://www.babblegraph.com/article/token",
+					},
+					OtherLinksTitle: ptr.String("Otro enlace"),
+					OtherLinks: []newsletter.SectionLink{
+						{
+							Title: "Otro artículo",
+							URL:   "https://www.babblegraph.com/article/other-token",
+						},
+					},
+				},
+			},
+		},
+	}
+	compressed, err := compressNewsletter(n)
+	if err != nil {
+		t.Fatalf("Error compressing newsletter: %s", err.Error())
+	}
+	decompressed, err := decompressNewsletter(compressed)
+	if err != nil {
+		t.Fatalf("Error decompressing newsletter: %s", err.Error())
+	}
+	if !reflect.DeepEqual(n, *decompressed) {
+		t.Errorf("Expected %+v, but got %+v", n, *decompressed)
+	}
+}
@@ -0,0 +1,47 @@
+package newsletterarchive
+
+import (
+	"babblegraph/model/email"
+	"babblegraph/model/newslettersendrequests"
+	"babblegraph/model/users"
+	"babblegraph/wordsmith"
+	"time"
+)
+
+type ID string
+
+func (i ID) Str() string {
+	return string(i)
+}
+
+type dbArchiveEntry struct {
+	ID                      ID                        `db:"_id"`
+	CreatedAt               time.Time                 `db:"created_at"`
+	LastModifiedAt          time.Time                 `db:"last_modified_at"`
+	NewsletterSendRequestID newslettersendrequests.ID `db:"newsletter_send_request_id"`
+	UserID                  users.UserID              `db:"user_id"`
+	EmailRecordID           email.ID                  `db:"email_record_id"`
+	LanguageCode            wordsmith.LanguageCode    `db:"language_code"`
+	DateOfSend              time.Time                 `db:"date_of_send"`
+	CompressedNewsletter    []byte                    `db:"compressed_newsletter"`
+}
+
+func (d dbArchiveEntry) ToNonDB() ArchiveEntry {
+	return ArchiveEntry{
+		ID:                      d.ID,
+		NewsletterSendRequestID: d.NewsletterSendRequestID,
+		EmailRecordID:           d.EmailRecordID,
+		LanguageCode:            d.LanguageCode,
+		DateOfSend:              d.DateOfSend,
+	}
+}
+
+// ArchiveEntry is the metadata for a sent newsletter,
+// the newsletter itself is only decompressed when it's looked up
+type ArchiveEntry struct {
+	ID                      ID                        `json:"id"`
+	NewsletterSendRequestID newslettersendrequests.ID `json:"newsletter_send_request_id"`
+	EmailRecordID           email.ID                  `json:"email_record_id"`
+	LanguageCode            wordsmith.LanguageCode    `json:"language_code"`
+	DateOfSend              time.Time                 `json:"date_of_send"`
+}
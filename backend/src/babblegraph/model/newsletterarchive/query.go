@@ -0,0 +1,88 @@
+package newsletterarchive
+
+import (
+	"babblegraph/model/newsletter"
+	"babblegraph/model/newslettersendrequests"
+	"babblegraph/model/users"
+	"babblegraph/wordsmith"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	insertArchiveEntryQuery = `INSERT INTO
+        newsletter_archive_entries
+    (
+        newsletter_send_request_id,
+        user_id,
+        email_record_id,
+        language_code,
+        date_of_send,
+        compressed_newsletter
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6
+    ) ON CONFLICT (newsletter_send_request_id) DO NOTHING`
+
+	getArchiveEntriesForUserQuery         = "SELECT * FROM newsletter_archive_entries WHERE user_id = $1 AND language_code = $2 AND date_of_send >= $3 AND date_of_send < $4 ORDER BY date_of_send DESC"
+	lookupArchiveEntryForSendRequestQuery = "SELECT * FROM newsletter_archive_entries WHERE newsletter_send_request_id = $1"
+)
+
+type InsertArchiveEntryInput struct {
+	SendRequest newslettersendrequests.NewsletterSendRequest
+	Newsletter  newsletter.NewsletterVersion2
+}
+
+// InsertArchiveEntry is safe to call more than once for a send request,
+// since the fulfillment worker can retry after a partial failure
+func InsertArchiveEntry(tx *sqlx.Tx, input InsertArchiveEntryInput) error {
+	compressed, err := compressNewsletter(input.Newsletter)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(insertArchiveEntryQuery,
+		input.SendRequest.ID,
+		input.SendRequest.UserID,
+		input.Newsletter.EmailRecordID,
+		input.SendRequest.LanguageCode,
+		input.SendRequest.DateOfSend.UTC(),
+		compressed,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetArchiveEntriesForUser returns the newsletters sent
+// to a user in [from, to), with the most recent first
+func GetArchiveEntriesForUser(tx *sqlx.Tx, userID users.UserID, languageCode wordsmith.LanguageCode, from, to time.Time) ([]ArchiveEntry, error) {
+	var matches []dbArchiveEntry
+	if err := tx.Select(&matches, getArchiveEntriesForUserQuery, userID, languageCode, from.UTC(), to.UTC()); err != nil {
+		return nil, err
+	}
+	var out []ArchiveEntry
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+func LookupArchivedNewsletterForSendRequest(tx *sqlx.Tx, sendRequestID newslettersendrequests.ID) (*ArchiveEntry, *newsletter.NewsletterVersion2, error) {
+	var matches []dbArchiveEntry
+	err := tx.Select(&matches, lookupArchiveEntryForSendRequestQuery, sendRequestID)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case len(matches) > 1:
+		return nil, nil, fmt.Errorf("Expected at most one archive entry for send request %s, but got %d", sendRequestID, len(matches))
+	case len(matches) == 0:
+		return nil, nil, nil
+	}
+	newsletterVersion2, err := decompressNewsletter(matches[0].CompressedNewsletter)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := matches[0].ToNonDB()
+	return &entry, newsletterVersion2, nil
+}
@@ -30,6 +30,7 @@ const (
         $1, $2, $3, $4, $5, $6, $7
     )`
 
+	lookupSendRequestByIDQuery             = "SELECT * FROM newsletter_send_requests WHERE _id = $1"
 	getOutstandingSendRequestsForUserQuery = "SELECT * FROM newsletter_send_requests WHERE user_id = '%s' AND language_code = '%s' AND payload_status NOT IN (?)"
 	updateSendRequestSendAtTimeQuery       = "UPDATE newsletter_send_requests SET hour_to_send_index_utc=$1, quarter_hour_to_send_index_utc=$2 WHERE _id = $3"
 
@@ -115,6 +116,21 @@ func GetOutstandingSendRequestsForUser(tx *sqlx.Tx, userID users.UserID, languag
 	return out, nil
 }
 
+func LookupSendRequestByID(tx *sqlx.Tx, id ID) (*NewsletterSendRequest, error) {
+	var matches []dbNewsletterSendRequest
+	err := tx.Select(&matches, lookupSendRequestByIDQuery, id)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one send request for ID %s, but got %d", id, len(matches))
+	case len(matches) == 0:
+		return nil, nil
+	default:
+		return matches[0].ToNonDB()
+	}
+}
+
 func GetNonDeletedSendRequestsOlderThan(tx *sqlx.Tx, t time.Time) ([]NewsletterSendRequest, error) {
 	var matches []dbNewsletterSendRequest
 	if err := tx.Select(&matches, getSendRequestsOlderThanWithStatusQuery, t, PayloadStatusDeleted); err != nil {
@@ -0,0 +1,282 @@
+package podcasts
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/wordsmith"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	lookupFeedStateQuery = "SELECT * FROM content_podcast_feed_state WHERE source_seed_id = $1"
+	upsertFeedStateQuery = `INSERT INTO
+        content_podcast_feed_state (
+            source_seed_id, etag, last_modified_header, content_hash, last_changed_at
+        ) VALUES (
+            $1, $2, $3, $4, timezone('utc', now())
+        ) ON CONFLICT (source_seed_id) DO UPDATE
+        SET
+            etag = $2,
+            last_modified_header = $3,
+            content_hash = $4,
+            last_changed_at = timezone('utc', now()),
+            last_modified_at = timezone('utc', now())
+    `
+
+	getActiveEpisodeRecordsForSourceSeedQuery = "SELECT * FROM content_podcast_episode_record WHERE source_seed_id = $1 AND is_active = TRUE"
+	upsertEpisodeRecordQuery                  = `INSERT INTO
+        content_podcast_episode_record (
+            source_id, source_seed_id, episode_id, language_code, indexed_guid, feed_guid, audio_url, fingerprint, is_active
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, TRUE
+        ) ON CONFLICT (episode_id) DO UPDATE
+        SET
+            source_seed_id = $2,
+            feed_guid = $6,
+            audio_url = $7,
+            fingerprint = $8,
+            is_active = TRUE,
+            last_modified_at = timezone('utc', now())
+    `
+	deactivateEpisodeRecordQuery = "UPDATE content_podcast_episode_record SET is_active = FALSE, last_modified_at = timezone('utc', now()) WHERE episode_id = $1"
+
+	getFeedChangesForSourceQuery = "SELECT * FROM content_podcast_feed_change WHERE source_id = $1 ORDER BY created_at DESC LIMIT $2"
+	// The conflict only applies to duplicates, which are recorded once per GUID
+	insertFeedChangeQuery = `INSERT INTO
+        content_podcast_feed_change (
+            source_id, source_seed_id, episode_id, feed_guid, change_type, details
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6
+        ) ON CONFLICT (source_seed_id, feed_guid) WHERE change_type = 'duplicate-in-feed' DO NOTHING
+    `
+)
+
+type dbFeedState struct {
+	CreatedAt          time.Time            `db:"created_at"`
+	LastModifiedAt     time.Time            `db:"last_modified_at"`
+	SourceSeedID       content.SourceSeedID `db:"source_seed_id"`
+	ETag               *string              `db:"etag"`
+	LastModifiedHeader *string              `db:"last_modified_header"`
+	ContentHash        string               `db:"content_hash"`
+	LastChangedAt      time.Time            `db:"last_changed_at"`
+}
+
+func (d dbFeedState) ToNonDB() FeedState {
+	return FeedState{
+		SourceSeedID:       d.SourceSeedID,
+		ETag:               d.ETag,
+		LastModifiedHeader: d.LastModifiedHeader,
+		ContentHash:        d.ContentHash,
+		LastChangedAt:      d.LastChangedAt,
+	}
+}
+
+// FeedState is what was seen the last time a feed was
+// successfully ingested, so that unchanged feeds can be skipped
+type FeedState struct {
+	SourceSeedID       content.SourceSeedID
+	ETag               *string
+	LastModifiedHeader *string
+	ContentHash        string
+	LastChangedAt      time.Time
+}
+
+func LookupFeedStateForSourceSeed(tx *sqlx.Tx, sourceSeedID content.SourceSeedID) (*FeedState, error) {
+	var matches []dbFeedState
+	err := tx.Select(&matches, lookupFeedStateQuery, sourceSeedID)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one feed state for source seed %s, but got %d", sourceSeedID, len(matches))
+	case len(matches) == 0:
+		return nil, nil
+	default:
+		out := matches[0].ToNonDB()
+		return &out, nil
+	}
+}
+
+type UpsertFeedStateInput struct {
+	SourceSeedID       content.SourceSeedID
+	ETag               *string
+	LastModifiedHeader *string
+	ContentHash        string
+}
+
+func UpsertFeedState(tx *sqlx.Tx, input UpsertFeedStateInput) error {
+	if _, err := tx.Exec(upsertFeedStateQuery, input.SourceSeedID, input.ETag, input.LastModifiedHeader, input.ContentHash); err != nil {
+		return err
+	}
+	return nil
+}
+
+type EpisodeRecordID string
+
+type dbEpisodeRecord struct {
+	ID             EpisodeRecordID        `db:"_id"`
+	CreatedAt      time.Time              `db:"created_at"`
+	LastModifiedAt time.Time              `db:"last_modified_at"`
+	SourceID       content.SourceID       `db:"source_id"`
+	SourceSeedID   content.SourceSeedID   `db:"source_seed_id"`
+	EpisodeID      EpisodeID              `db:"episode_id"`
+	LanguageCode   wordsmith.LanguageCode `db:"language_code"`
+	IndexedGUID    string                 `db:"indexed_guid"`
+	FeedGUID       string                 `db:"feed_guid"`
+	AudioURL       string                 `db:"audio_url"`
+	Fingerprint    string                 `db:"fingerprint"`
+	IsActive       bool                   `db:"is_active"`
+}
+
+func (d dbEpisodeRecord) ToNonDB() EpisodeRecord {
+	return EpisodeRecord{
+		EpisodeID:    d.EpisodeID,
+		SourceID:     d.SourceID,
+		SourceSeedID: d.SourceSeedID,
+		LanguageCode: d.LanguageCode,
+		IndexedGUID:  d.IndexedGUID,
+		FeedGUID:     d.FeedGUID,
+		AudioURL:     d.AudioURL,
+		Fingerprint:  d.Fingerprint,
+	}
+}
+
+// EpisodeRecord keeps track of every episode indexed for a feed.
+// The indexed GUID is the one the episode ID was made from, which
+// stays the same even if the feed later rewrites the GUID.
+type EpisodeRecord struct {
+	EpisodeID    EpisodeID
+	SourceID     content.SourceID
+	SourceSeedID content.SourceSeedID
+	LanguageCode wordsmith.LanguageCode
+	IndexedGUID  string
+	FeedGUID     string
+	AudioURL     string
+	Fingerprint  string
+}
+
+func GetActiveEpisodeRecordsForSourceSeed(tx *sqlx.Tx, sourceSeedID content.SourceSeedID) ([]EpisodeRecord, error) {
+	var matches []dbEpisodeRecord
+	if err := tx.Select(&matches, getActiveEpisodeRecordsForSourceSeedQuery, sourceSeedID); err != nil {
+		return nil, err
+	}
+	var out []EpisodeRecord
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+func UpsertEpisodeRecord(tx *sqlx.Tx, record EpisodeRecord) error {
+	if _, err := tx.Exec(upsertEpisodeRecordQuery, record.SourceID, record.SourceSeedID, record.EpisodeID, record.LanguageCode, record.IndexedGUID, record.FeedGUID, record.AudioURL, record.Fingerprint); err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeactivateEpisodeRecord(tx *sqlx.Tx, episodeID EpisodeID) error {
+	if _, err := tx.Exec(deactivateEpisodeRecordQuery, episodeID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MakeEpisodeFingerprint identifies an episode by its title and publication
+// day, which is used to recognize an episode whose GUID and enclosure both changed.
+func MakeEpisodeFingerprint(title string, publicationDate time.Time) string {
+	normalizedTitle := strings.ToLower(strings.Join(strings.Fields(title), " "))
+	if len(normalizedTitle) == 0 {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s", normalizedTitle, publicationDate.UTC().Format("2006-01-02"))))
+	return hex.EncodeToString(hash[:])
+}
+
+type FeedChangeType string
+
+const (
+	FeedChangeTypeAdded          FeedChangeType = "added"
+	FeedChangeTypeGUIDRewritten  FeedChangeType = "guid-rewritten"
+	FeedChangeTypeEnclosureMoved FeedChangeType = "enclosure-moved"
+	FeedChangeTypeDuplicate      FeedChangeType = "duplicate-in-feed"
+	FeedChangeTypeRemoved        FeedChangeType = "removed"
+)
+
+func (f FeedChangeType) Str() string {
+	return string(f)
+}
+
+func (f FeedChangeType) Ptr() *FeedChangeType {
+	return &f
+}
+
+type FeedChangeID string
+
+type dbFeedChange struct {
+	ID             FeedChangeID         `db:"_id"`
+	CreatedAt      time.Time            `db:"created_at"`
+	LastModifiedAt time.Time            `db:"last_modified_at"`
+	SourceID       content.SourceID     `db:"source_id"`
+	SourceSeedID   content.SourceSeedID `db:"source_seed_id"`
+	EpisodeID      *EpisodeID           `db:"episode_id"`
+	FeedGUID       *string              `db:"feed_guid"`
+	ChangeType     FeedChangeType       `db:"change_type"`
+	Details        string               `db:"details"`
+}
+
+func (d dbFeedChange) ToNonDB() FeedChange {
+	return FeedChange{
+		ID:           d.ID,
+		CreatedAt:    d.CreatedAt,
+		SourceID:     d.SourceID,
+		SourceSeedID: d.SourceSeedID,
+		EpisodeID:    d.EpisodeID,
+		FeedGUID:     d.FeedGUID,
+		ChangeType:   d.ChangeType,
+		Details:      d.Details,
+	}
+}
+
+type FeedChange struct {
+	ID           FeedChangeID         `json:"id"`
+	CreatedAt    time.Time            `json:"created_at"`
+	SourceID     content.SourceID     `json:"source_id"`
+	SourceSeedID content.SourceSeedID `json:"source_seed_id"`
+	EpisodeID    *EpisodeID           `json:"episode_id,omitempty"`
+	FeedGUID     *string              `json:"feed_guid,omitempty"`
+	ChangeType   FeedChangeType       `json:"change_type"`
+	Details      string               `json:"details"`
+}
+
+type InsertFeedChangeInput struct {
+	SourceID     content.SourceID
+	SourceSeedID content.SourceSeedID
+	EpisodeID    *EpisodeID
+	FeedGUID     string
+	ChangeType   FeedChangeType
+	Details      string
+}
+
+func InsertFeedChange(tx *sqlx.Tx, input InsertFeedChangeInput) error {
+	if _, err := tx.Exec(insertFeedChangeQuery, input.SourceID, input.SourceSeedID, input.EpisodeID, input.FeedGUID, input.ChangeType, input.Details); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetFeedChangesForSource(tx *sqlx.Tx, sourceID content.SourceID, limit int64) ([]FeedChange, error) {
+	var matches []dbFeedChange
+	if err := tx.Select(&matches, getFeedChangesForSourceQuery, sourceID, limit); err != nil {
+		return nil, err
+	}
+	var out []FeedChange
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
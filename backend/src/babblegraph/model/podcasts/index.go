@@ -52,6 +52,11 @@ type IndexPodcastEpisodeInput struct {
 
 	Version Version
 
+	ReadabilityScore                   *int64
+	LemmatizedDescription              *string
+	LemmatizedDescriptionIndexMappings []int
+	LemmatizedTranscript               *string
+
 	LanguageCode wordsmith.LanguageCode
 	TopicIDs     []content.TopicID
 	SourceID     content.SourceID
@@ -74,6 +79,12 @@ func AssignIDAndIndexPodcastEpisode(c ctx.LogContext, input IndexPodcastEpisodeI
 		LanguageCode:        input.LanguageCode,
 		TopicIDs:            input.TopicIDs,
 		SourceID:            input.SourceID,
+
+		ReadabilityScore:                   input.ReadabilityScore,
+		LemmatizedDescription:              input.LemmatizedDescription,
+		LemmatizedDescriptionIndexMappings: input.LemmatizedDescriptionIndexMappings,
+		LemmatizedTranscript:               input.LemmatizedTranscript,
+		HasTranscript:                      input.LemmatizedTranscript != nil,
 	}); err != nil {
 		return nil, err
 	}
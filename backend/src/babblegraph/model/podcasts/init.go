@@ -62,6 +62,16 @@ func CreatePodcastMappings(c ctx.LogContext) {
 				Analyzer: ptr.String(analyzerWithWhitespaceTokenizerName),
 			}),
 			esmapping.MakeTextMapping("language_code", esmapping.MappingOptions{}),
+			esmapping.MakeLongMapping("readability_score", esmapping.MappingOptions{}),
+			esmapping.MakeTextMapping("lemmatized_description", esmapping.MappingOptions{
+				Analyzer: ptr.String(analyzerWithWhitespaceTokenizerName),
+			}),
+			esmapping.MakeLongMapping("lemmatized_description_index_mappings", esmapping.MappingOptions{}),
+			esmapping.MakeTextMapping("lemmatized_transcript", esmapping.MappingOptions{
+				Analyzer: ptr.String(analyzerWithWhitespaceTokenizerName),
+			}),
+			esmapping.MakeBooleanMapping("has_transcript", esmapping.MappingOptions{}),
+			esmapping.MakeBooleanMapping("is_removed", esmapping.MappingOptions{}),
 		}); err != nil {
 			c.Errorf("Error updating mappings for podcast index %s: %s", code, err.Error())
 		}
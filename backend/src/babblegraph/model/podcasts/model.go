@@ -33,8 +33,10 @@ type Version int
 
 const (
 	Version1 Version = 1
+	// Adds lemmatized show notes, transcripts and readability scores
+	Version2 Version = 2
 
-	CurrentVersion = Version1
+	CurrentVersion = Version2
 )
 
 func (v Version) Int64() int64 {
@@ -47,6 +49,10 @@ func (e EpisodeID) Str() string {
 	return string(e)
 }
 
+func (e EpisodeID) Ptr() *EpisodeID {
+	return &e
+}
+
 type Episode struct {
 	ID                  EpisodeID      `json:"id"`
 	Title               string         `json:"title"`
@@ -64,6 +70,18 @@ type Episode struct {
 
 	Version Version `json:"version"`
 
+	// The readability score is calculated from the transcript
+	// if there is one, and the show notes otherwise
+	ReadabilityScore                   *int64  `json:"readability_score,omitempty"`
+	LemmatizedDescription              *string `json:"lemmatized_description,omitempty"`
+	LemmatizedDescriptionIndexMappings []int   `json:"lemmatized_description_index_mappings,omitempty"`
+	LemmatizedTranscript               *string `json:"lemmatized_transcript,omitempty"`
+	HasTranscript                      bool    `json:"has_transcript"`
+
+	// Episodes that disappear from their feed are kept in the
+	// index so that links in newsletters that were already sent keep working
+	IsRemoved bool `json:"is_removed"`
+
 	LanguageCode wordsmith.LanguageCode `json:"language_code"`
 	TopicIDs     []content.TopicID      `json:"topic_ids"`
 	SourceID     content.SourceID       `json:"source_id"`
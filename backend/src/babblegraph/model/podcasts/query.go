@@ -7,6 +7,7 @@ import (
 	"babblegraph/wordsmith"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -70,10 +71,20 @@ func GetEpisodeByID(languageCode wordsmith.LanguageCode, id EpisodeID) (*Episode
 type QueryEpisodesInput struct {
 	SeenPodcastIDs          []EpisodeID
 	ValidSourceIDs          []content.SourceID
-	TopicID                 content.TopicID
+	TopicID                 *content.TopicID
 	IncludeExplicitPodcasts bool
 	MaxDurationNanoseconds  *time.Duration
 	MinDurationNanoseconds  *time.Duration
+
+	// Episodes indexed before Version2 don't have a readability
+	// score or lemmas, so they pass the reading level filter and
+	// are only ranked lower than episodes with matching lemmas
+	MinimumReadingLevel *int64
+	MaximumReadingLevel *int64
+	LemmaIDPhrases      [][]wordsmith.LemmaID
+	// Spotlights search every topic for an episode that uses one
+	// of the phrases, instead of only ranking those episodes higher
+	RequireLemmaIDPhrases bool
 }
 
 type ScoredEpisode struct {
@@ -89,11 +100,14 @@ func QueryEpisodes(languageCode wordsmith.LanguageCode, input QueryEpisodesInput
 		validSourceIDs = append(validSourceIDs, s.Str())
 	}
 	queryBuilder.AddMust(esquery.Terms("source_id.keyword", validSourceIDs))
-	queryBuilder.AddMust(esquery.MatchPhrase("topic_ids", input.TopicID))
+	if input.TopicID != nil {
+		queryBuilder.AddMust(esquery.MatchPhrase("topic_ids", *input.TopicID))
+	}
 	versionRangeQueryBuilder := esquery.NewRangeQueryBuilderForFieldName("version")
 	versionRangeQueryBuilder.GreaterThanOrEqualToInt64(Version1.Int64())
-	versionRangeQueryBuilder.LessThanOrEqualToInt64(Version1.Int64())
+	versionRangeQueryBuilder.LessThanOrEqualToInt64(CurrentVersion.Int64())
 	queryBuilder.AddMust(versionRangeQueryBuilder.BuildRangeQuery())
+	queryBuilder.AddMustNot(esquery.Match("is_removed", true))
 	if !input.IncludeExplicitPodcasts {
 		queryBuilder.AddMust(esquery.Match("is_explicit", false))
 	}
@@ -107,6 +121,37 @@ func QueryEpisodes(languageCode wordsmith.LanguageCode, input QueryEpisodesInput
 		}
 		queryBuilder.AddMust(durationQueryBuilder.BuildRangeQuery())
 	}
+	if input.MinimumReadingLevel != nil || input.MaximumReadingLevel != nil {
+		readingLevelRangeQueryBuilder := esquery.NewRangeQueryBuilderForFieldName("readability_score")
+		if input.MinimumReadingLevel != nil {
+			readingLevelRangeQueryBuilder.GreaterThanOrEqualToInt64(*input.MinimumReadingLevel)
+		}
+		if input.MaximumReadingLevel != nil {
+			readingLevelRangeQueryBuilder.LessThanOrEqualToInt64(*input.MaximumReadingLevel)
+		}
+		withoutReadingLevelQueryBuilder := esquery.NewBoolQueryBuilder()
+		withoutReadingLevelQueryBuilder.AddMustNot(esquery.Exists("readability_score"))
+		readingLevelQueryBuilder := esquery.NewBoolQueryBuilder()
+		readingLevelQueryBuilder.AddShould(readingLevelRangeQueryBuilder.BuildRangeQuery())
+		readingLevelQueryBuilder.AddShould(withoutReadingLevelQueryBuilder.BuildBoolQuery())
+		queryBuilder.AddMust(readingLevelQueryBuilder.BuildBoolQuery())
+	}
+	lemmaQueryBuilder := queryBuilder
+	if input.RequireLemmaIDPhrases {
+		lemmaQueryBuilder = esquery.NewBoolQueryBuilder()
+	}
+	for _, phrase := range input.LemmaIDPhrases {
+		var phraseAsStrings []string
+		for _, lemmaID := range phrase {
+			phraseAsStrings = append(phraseAsStrings, lemmaID.Str())
+		}
+		// A lemma can be in either the show notes or the transcript
+		lemmaQueryBuilder.AddShould(esquery.MatchPhrase("lemmatized_description", strings.Join(phraseAsStrings, " ")))
+		lemmaQueryBuilder.AddShould(esquery.MatchPhrase("lemmatized_transcript", strings.Join(phraseAsStrings, " ")))
+	}
+	if input.RequireLemmaIDPhrases && len(input.LemmaIDPhrases) > 0 {
+		queryBuilder.AddMust(lemmaQueryBuilder.BuildBoolQuery())
+	}
 	if len(input.SeenPodcastIDs) != 0 {
 		var seenPodcastIDs []string
 		for _, s := range input.SeenPodcastIDs {
@@ -130,3 +175,14 @@ func QueryEpisodes(languageCode wordsmith.LanguageCode, input QueryEpisodesInput
 	}
 	return out, nil
 }
+
+type markEpisodeAsRemovedInput struct {
+	IsRemoved bool `json:"is_removed"`
+}
+
+func MarkEpisodeAsRemoved(languageCode wordsmith.LanguageCode, id EpisodeID) error {
+	podcastIndex := getPodcastIndexForLanguageCode(languageCode)
+	return esquery.ExecuteUpdate(podcastIndex, id.Str(), markEpisodeAsRemovedInput{
+		IsRemoved: true,
+	})
+}
@@ -0,0 +1,120 @@
+package podcasts
+
+import "fmt"
+
+// FeedEpisode is the part of an episode in a feed
+// that is used to match it against indexed episodes
+type FeedEpisode struct {
+	GUID        string
+	AudioURL    string
+	Fingerprint string
+}
+
+type EpisodeReconciliationAction struct {
+	FeedEpisode FeedEpisode
+	// IndexedGUID is the GUID that the episode should be indexed
+	// under. It's the GUID of the matched record if there is one.
+	IndexedGUID    string
+	ExistingRecord *EpisodeRecord
+	ChangeType     *FeedChangeType
+	Details        string
+}
+
+type EpisodeReconciliation struct {
+	ToIndex    []EpisodeReconciliationAction
+	Duplicates []EpisodeReconciliationAction
+	Removed    []EpisodeRecord
+}
+
+// ReconcileEpisodes matches the episodes currently in a feed against
+// the records of previously indexed episodes. An episode is the same as
+// a record if its GUID matches, or failing that, if its audio URL or fingerprint
+// matches, which handles feeds that rewrite GUIDs. Records that don't match
+// any episode in the feed are removed, unless the feed is empty, which is more likely
+// to be a broken feed than a podcast that deleted every episode.
+func ReconcileEpisodes(records []EpisodeRecord, feedEpisodes []FeedEpisode) EpisodeReconciliation {
+	recordsByGUID := make(map[string]EpisodeRecord)
+	recordsByAudioURL := make(map[string]EpisodeRecord)
+	recordsByFingerprint := make(map[string]EpisodeRecord)
+	for _, r := range records {
+		recordsByGUID[r.IndexedGUID] = r
+		recordsByGUID[r.FeedGUID] = r
+		if len(r.AudioURL) > 0 {
+			recordsByAudioURL[r.AudioURL] = r
+		}
+		if len(r.Fingerprint) > 0 {
+			recordsByFingerprint[r.Fingerprint] = r
+		}
+	}
+	matchedEpisodeIDs := make(map[EpisodeID]bool)
+	seenGUIDs := make(map[string]bool)
+	seenAudioURLs := make(map[string]bool)
+	var out EpisodeReconciliation
+	for _, e := range feedEpisodes {
+		switch {
+		case seenGUIDs[e.GUID]:
+			out.Duplicates = append(out.Duplicates, makeDuplicateAction(e, fmt.Sprintf("GUID %s appears more than once in the feed", e.GUID)))
+			continue
+		case len(e.AudioURL) > 0 && seenAudioURLs[e.AudioURL]:
+			out.Duplicates = append(out.Duplicates, makeDuplicateAction(e, fmt.Sprintf("Audio URL %s appears more than once in the feed", e.AudioURL)))
+			continue
+		}
+		seenGUIDs[e.GUID] = true
+		seenAudioURLs[e.AudioURL] = true
+		action := EpisodeReconciliationAction{
+			FeedEpisode: e,
+			IndexedGUID: e.GUID,
+		}
+		if r, ok := recordsByGUID[e.GUID]; ok && !matchedEpisodeIDs[r.EpisodeID] {
+			action.ExistingRecord = &r
+			action.IndexedGUID = r.IndexedGUID
+			if r.AudioURL != e.AudioURL {
+				action.ChangeType = FeedChangeTypeEnclosureMoved.Ptr()
+				action.Details = fmt.Sprintf("Audio URL changed from %s to %s", r.AudioURL, e.AudioURL)
+			}
+		} else if r, ok := lookupRecordForRewrittenGUID(e, recordsByAudioURL, recordsByFingerprint, matchedEpisodeIDs); ok {
+			action.ExistingRecord = &r
+			action.IndexedGUID = r.IndexedGUID
+			action.ChangeType = FeedChangeTypeGUIDRewritten.Ptr()
+			action.Details = fmt.Sprintf("GUID changed from %s to %s", r.FeedGUID, e.GUID)
+			if r.AudioURL != e.AudioURL {
+				action.Details = fmt.Sprintf("%s and audio URL changed from %s to %s", action.Details, r.AudioURL, e.AudioURL)
+			}
+		} else {
+			action.ChangeType = FeedChangeTypeAdded.Ptr()
+			action.Details = fmt.Sprintf("New episode with GUID %s", e.GUID)
+		}
+		if action.ExistingRecord != nil {
+			matchedEpisodeIDs[action.ExistingRecord.EpisodeID] = true
+		}
+		out.ToIndex = append(out.ToIndex, action)
+	}
+	if len(feedEpisodes) == 0 {
+		return out
+	}
+	for _, r := range records {
+		if !matchedEpisodeIDs[r.EpisodeID] {
+			out.Removed = append(out.Removed, r)
+		}
+	}
+	return out
+}
+
+func lookupRecordForRewrittenGUID(e FeedEpisode, recordsByAudioURL, recordsByFingerprint map[string]EpisodeRecord, matchedEpisodeIDs map[EpisodeID]bool) (EpisodeRecord, bool) {
+	if r, ok := recordsByAudioURL[e.AudioURL]; ok && len(e.AudioURL) > 0 && !matchedEpisodeIDs[r.EpisodeID] {
+		return r, true
+	}
+	if r, ok := recordsByFingerprint[e.Fingerprint]; ok && len(e.Fingerprint) > 0 && !matchedEpisodeIDs[r.EpisodeID] {
+		return r, true
+	}
+	return EpisodeRecord{}, false
+}
+
+func makeDuplicateAction(e FeedEpisode, details string) EpisodeReconciliationAction {
+	return EpisodeReconciliationAction{
+		FeedEpisode: e,
+		IndexedGUID: e.GUID,
+		ChangeType:  FeedChangeTypeDuplicate.Ptr(),
+		Details:     details,
+	}
+}
@@ -0,0 +1,68 @@
+package podcasts
+
+import (
+	"testing"
+)
+
+func TestReconcileEpisodes(t *testing.T) {
+	records := []EpisodeRecord{
+		{EpisodeID: "ep-1", IndexedGUID: "guid-1", FeedGUID: "guid-1", AudioURL: "https://cdn.example.com/1.mp3", Fingerprint: "fp-1"},
+		{EpisodeID: "ep-2", IndexedGUID: "guid-2", FeedGUID: "guid-2", AudioURL: "https://cdn.example.com/2.mp3", Fingerprint: "fp-2"},
+		{EpisodeID: "ep-3", IndexedGUID: "guid-3", FeedGUID: "guid-3", AudioURL: "https://cdn.example.com/3.mp3", Fingerprint: "fp-3"},
+		{EpisodeID: "ep-4", IndexedGUID: "guid-4", FeedGUID: "guid-4", AudioURL: "https://cdn.example.com/4.mp3", Fingerprint: "fp-4"},
+	}
+	reconciliation := ReconcileEpisodes(records, []FeedEpisode{
+		// Unchanged
+		{GUID: "guid-1", AudioURL: "https://cdn.example.com/1.mp3", Fingerprint: "fp-1"},
+		// Enclosure moved to a new host
+		{GUID: "guid-2", AudioURL: "https://newcdn.example.com/2.mp3", Fingerprint: "fp-2"},
+		// GUID rewritten, matched on audio URL
+		{GUID: "new-guid-3", AudioURL: "https://cdn.example.com/3.mp3", Fingerprint: "fp-3"},
+		// Duplicate item in the feed
+		{GUID: "guid-1", AudioURL: "https://cdn.example.com/1.mp3", Fingerprint: "fp-1"},
+		// New episode
+		{GUID: "guid-5", AudioURL: "https://cdn.example.com/5.mp3", Fingerprint: "fp-5"},
+	})
+	type expectedAction struct {
+		indexedGUID string
+		changeType  *FeedChangeType
+	}
+	expected := []expectedAction{
+		{indexedGUID: "guid-1"},
+		{indexedGUID: "guid-2", changeType: FeedChangeTypeEnclosureMoved.Ptr()},
+		{indexedGUID: "guid-3", changeType: FeedChangeTypeGUIDRewritten.Ptr()},
+		{indexedGUID: "guid-5", changeType: FeedChangeTypeAdded.Ptr()},
+	}
+	if len(reconciliation.ToIndex) != len(expected) {
+		t.Fatalf("Expected %d episodes to index, but got %d", len(expected), len(reconciliation.ToIndex))
+	}
+	for idx, e := range expected {
+		action := reconciliation.ToIndex[idx]
+		if action.IndexedGUID != e.indexedGUID {
+			t.Errorf("Error on action %d: expected indexed GUID %s, but got %s", idx, e.indexedGUID, action.IndexedGUID)
+		}
+		switch {
+		case e.changeType == nil && action.ChangeType != nil:
+			t.Errorf("Error on action %d: expected no change, but got %s", idx, *action.ChangeType)
+		case e.changeType != nil && action.ChangeType == nil:
+			t.Errorf("Error on action %d: expected change %s, but got none", idx, *e.changeType)
+		case e.changeType != nil && *e.changeType != *action.ChangeType:
+			t.Errorf("Error on action %d: expected change %s, but got %s", idx, *e.changeType, *action.ChangeType)
+		}
+	}
+	if len(reconciliation.Duplicates) != 1 {
+		t.Errorf("Expected 1 duplicate, but got %d", len(reconciliation.Duplicates))
+	}
+	if len(reconciliation.Removed) != 1 || reconciliation.Removed[0].EpisodeID != "ep-4" {
+		t.Errorf("Expected only ep-4 to be removed, but got %+v", reconciliation.Removed)
+	}
+}
+
+func TestReconcileEpisodesEmptyFeed(t *testing.T) {
+	reconciliation := ReconcileEpisodes([]EpisodeRecord{
+		{EpisodeID: "ep-1", IndexedGUID: "guid-1", FeedGUID: "guid-1", AudioURL: "https://cdn.example.com/1.mp3", Fingerprint: "fp-1"},
+	}, nil)
+	if len(reconciliation.Removed) != 0 {
+		t.Errorf("Expected no episodes to be removed for an empty feed, but got %d", len(reconciliation.Removed))
+	}
+}
@@ -0,0 +1,59 @@
+package podcasts
+
+import (
+	"babblegraph/model/content"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	lookupCachedTranscriptQuery = "SELECT * FROM content_podcast_transcript WHERE source_seed_id = $1 AND feed_guid = $2 AND transcript_url = $3"
+	insertCachedTranscriptQuery = `INSERT INTO
+        content_podcast_transcript (
+            source_seed_id, feed_guid, transcript_url, transcript_text
+        ) VALUES (
+            $1, $2, $3, $4
+        ) ON CONFLICT (source_seed_id, feed_guid, transcript_url) DO NOTHING
+    `
+)
+
+type dbCachedTranscript struct {
+	ID             string               `db:"_id"`
+	CreatedAt      time.Time            `db:"created_at"`
+	SourceSeedID   content.SourceSeedID `db:"source_seed_id"`
+	FeedGUID       string               `db:"feed_guid"`
+	TranscriptURL  string               `db:"transcript_url"`
+	TranscriptText string               `db:"transcript_text"`
+}
+
+type CachedTranscriptKey struct {
+	SourceSeedID  content.SourceSeedID
+	FeedGUID      string
+	TranscriptURL string
+}
+
+// LookupCachedTranscript returns nil if the transcript
+// for this episode has not been fetched before
+func LookupCachedTranscript(tx *sqlx.Tx, key CachedTranscriptKey) (*string, error) {
+	var matches []dbCachedTranscript
+	err := tx.Select(&matches, lookupCachedTranscriptQuery, key.SourceSeedID, key.FeedGUID, key.TranscriptURL)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one cached transcript for GUID %s on source seed %s, but got %d", key.FeedGUID, key.SourceSeedID, len(matches))
+	case len(matches) == 0:
+		return nil, nil
+	default:
+		return &matches[0].TranscriptText, nil
+	}
+}
+
+func InsertCachedTranscript(tx *sqlx.Tx, key CachedTranscriptKey, transcriptText string) error {
+	if _, err := tx.Exec(insertCachedTranscriptQuery, key.SourceSeedID, key.FeedGUID, key.TranscriptURL, transcriptText); err != nil {
+		return err
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"babblegraph/model/users"
 	"babblegraph/util/encrypt"
 	"fmt"
+	"time"
 )
 
 type RouteEncryptionKey string
@@ -16,15 +17,22 @@ const (
 	UserVerificationKey                      RouteEncryptionKey = "user-verification"
 	WordReinforcementKey                     RouteEncryptionKey = "word-reinforcement"
 	ArticleReaderKey                         RouteEncryptionKey = "article-reader"
+	NewsletterViewKey                        RouteEncryptionKey = "newsletter-view"
 
 	PremiumSubscriptionCheckoutKey RouteEncryptionKey = "premium-subscription-checkout"
 	CreateUserKey                  RouteEncryptionKey = "create-user"
 	ForgotPasswordKey              RouteEncryptionKey = "forgot-password"
 
+	// Lets users without an account export or erase their data
+	UserDataRequestKey RouteEncryptionKey = "user-data-request"
+
 	ArticleLinkKeyForUserDocumentID   RouteEncryptionKey = "article-link-user-document"
 	PaywallReportKeyForUserDocumentID RouteEncryptionKey = "paywall-report-user-document"
 
 	AdminRegistrationKey RouteEncryptionKey = "admin-registration"
+	// Issued once an admin's password is verified, so that the
+	// second factor can't be checked without the first
+	AdminLoginKey RouteEncryptionKey = "admin-login"
 
 	/*
 			   A mistake to learn from:
@@ -39,10 +47,47 @@ const (
 	PaywallReportKeyDEPRECATED RouteEncryptionKey = "paywall-report"
 )
 
+const archivedNewsletterLinkTokenLifetime = 5 * 365 * 24 * time.Hour
+
 func (r RouteEncryptionKey) Str() string {
 	return string(r)
 }
 
+// Links that end up in emails need to outlive the email
+// by a wide margin, but links for one-off actions like
+// verifying an account or resetting a password should not.
+func (r RouteEncryptionKey) getTokenLifetime() time.Duration {
+	switch r {
+	case UnsubscribeRouteEncryptionKey:
+		return 365 * 24 * time.Hour
+	case UserVerificationKey,
+		PremiumSubscriptionCheckoutKey,
+		AdminRegistrationKey:
+		return 7 * 24 * time.Hour
+	case CreateUserKey:
+		return 30 * 24 * time.Hour
+	case ForgotPasswordKey,
+		UserDataRequestKey:
+		return 24 * time.Hour
+	case AdminLoginKey:
+		return 10 * time.Minute
+	case ArticleLinkKeyForUserDocumentID,
+		PaywallReportKeyForUserDocumentID:
+		// These are kept in the newsletter archive, where
+		// an old issue can be opened long after it was sent
+		return archivedNewsletterLinkTokenLifetime
+	default:
+		return encrypt.DefaultTokenLifetime
+	}
+}
+
+func makeRouteToken(key RouteEncryptionKey, value interface{}) (*string, error) {
+	return encrypt.GetTokenWithLifetime(encrypt.TokenPair{
+		Key:   key.Str(),
+		Value: value,
+	}, key.getTokenLifetime())
+}
+
 func EncryptUserIDWithKey(userID users.UserID, key RouteEncryptionKey) (*string, error) {
 	switch key {
 	case SubscriptionManagementRouteEncryptionKey,
@@ -55,46 +100,32 @@ func EncryptUserIDWithKey(userID users.UserID, key RouteEncryptionKey) (*string,
 		ForgotPasswordKey,
 		ArticleLinkKeyForUserDocumentID,
 		PaywallReportKeyForUserDocumentID:
-		return encrypt.GetToken(encrypt.TokenPair{
-			Key:   key.Str(),
-			Value: userID,
-		})
+		return makeRouteToken(key, userID)
 	default:
 		return nil, fmt.Errorf("Invalid key type: %s", key.Str())
 	}
 }
 
 func MakeWordReinforcementToken(userID users.UserID) (*string, error) {
-	return encrypt.GetToken(encrypt.TokenPair{
-		Key:   WordReinforcementKey.Str(),
-		Value: userID,
-	})
+	return makeRouteToken(WordReinforcementKey, userID)
 }
 
 func MakeSubscriptionManagementToken(userID users.UserID) (*string, error) {
-	return encrypt.GetToken(encrypt.TokenPair{
-		Key:   SubscriptionManagementRouteEncryptionKey.Str(),
-		Value: userID,
-	})
+	return makeRouteToken(SubscriptionManagementRouteEncryptionKey, userID)
 }
 
 func MakeCreateUserToken(userID users.UserID) (*string, error) {
-	return encrypt.GetToken(encrypt.TokenPair{
-		Key:   CreateUserKey.Str(),
-		Value: userID,
-	})
+	return makeRouteToken(CreateUserKey, userID)
 }
 
 func MakePremiumSubscriptionCheckoutToken(userID users.UserID) (*string, error) {
-	return encrypt.GetToken(encrypt.TokenPair{
-		Key:   PremiumSubscriptionCheckoutKey.Str(),
-		Value: userID,
-	})
+	return makeRouteToken(PremiumSubscriptionCheckoutKey, userID)
 }
 
 func MakeAdminRegistrationToken(adminID admin.ID) (*string, error) {
-	return encrypt.GetToken(encrypt.TokenPair{
-		Key:   AdminRegistrationKey.Str(),
-		Value: adminID,
-	})
+	return makeRouteToken(AdminRegistrationKey, adminID)
+}
+
+func MakeAdminLoginToken(adminID admin.ID) (*string, error) {
+	return makeRouteToken(AdminLoginKey, adminID)
 }
@@ -17,6 +17,9 @@ const (
 	LoginRedirectKeyNewsletterPreferences  LoginRedirectKey = "npf"
 	LoginRedirectKeyCheckoutPage           LoginRedirectKey = "premco"
 	LoginRedirectKeyPaymentSettings        LoginRedirectKey = "pymtst"
+	LoginRedirectKeyGiftCode               LoginRedirectKey = "gftcd"
+	LoginRedirectKeyGroupLicenses          LoginRedirectKey = "grplc"
+	LoginRedirectKeyNewsletterArchive      LoginRedirectKey = "nwsarc"
 
 	LoginRedirectKeyDefault = LoginRedirectKeySubscriptionManagement
 )
@@ -39,6 +42,12 @@ func GetLoginRedirectKeyOrDefault(loginKey string) LoginRedirectKey {
 		return LoginRedirectKeyCheckoutPage
 	case LoginRedirectKeyPaymentSettings.Str():
 		return LoginRedirectKeyPaymentSettings
+	case LoginRedirectKeyGiftCode.Str():
+		return LoginRedirectKeyGiftCode
+	case LoginRedirectKeyGroupLicenses.Str():
+		return LoginRedirectKeyGroupLicenses
+	case LoginRedirectKeyNewsletterArchive.Str():
+		return LoginRedirectKeyNewsletterArchive
 	default:
 		return LoginRedirectKeyDefault
 	}
@@ -58,6 +67,12 @@ func GetLoginRedirectRouteForKeyAndUser(loginRedirectKey LoginRedirectKey, userI
 		return MakePremiumSubscriptionCheckoutLink(userID)
 	case LoginRedirectKeyPaymentSettings:
 		return MakePaymentSettingsRouteForUserID(userID)
+	case LoginRedirectKeyGiftCode:
+		return MakeGiftCodeRouteForUserID(userID)
+	case LoginRedirectKeyGroupLicenses:
+		return MakeGroupLicensesRouteForUserID(userID)
+	case LoginRedirectKeyNewsletterArchive:
+		return MakeNewsletterArchiveRouteForUserID(userID)
 	default:
 		return nil, fmt.Errorf("unimplemented")
 	}
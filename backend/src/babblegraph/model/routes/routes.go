@@ -5,10 +5,10 @@ import (
 	"babblegraph/model/useraccounts"
 	"babblegraph/model/userdocuments"
 	"babblegraph/model/users"
-	"babblegraph/util/encrypt"
 	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"fmt"
+	"net/url"
 )
 
 func MustGetHomePageURL() string {
@@ -48,16 +48,33 @@ func MakeNewsletterPreferencesLink(userID users.UserID) (*string, error) {
 }
 
 func MakeUnsubscribeRouteForUserID(userID users.UserID) (*string, error) {
-	token, err := encrypt.GetToken(encrypt.TokenPair{
-		Key:   UnsubscribeRouteEncryptionKey.Str(),
-		Value: userID,
-	})
+	token, err := makeRouteToken(UnsubscribeRouteEncryptionKey, userID)
 	if err != nil {
 		return nil, err
 	}
 	return ptr.String(env.GetAbsoluteURLForEnvironment(fmt.Sprintf("unsubscribe/%s", *token))), nil
 }
 
+// MakeOneClickUnsubscribeURLForUserID returns the URL used in the List-Unsubscribe
+// header. Mail clients POST to it directly, so it points at the API instead of a page.
+func MakeOneClickUnsubscribeURLForUserID(userID users.UserID) (*string, error) {
+	token, err := makeRouteToken(UnsubscribeRouteEncryptionKey, userID)
+	if err != nil {
+		return nil, err
+	}
+	return ptr.String(env.GetAbsoluteURLForEnvironment(fmt.Sprintf("api/user/one_click_unsubscribe_1?token=%s", url.QueryEscape(*token)))), nil
+}
+
+// MakeNewsletterViewInBrowserLink takes the ID of the send request
+// since that is what the newsletter payload is stored under
+func MakeNewsletterViewInBrowserLink(sendRequestID string) (*string, error) {
+	token, err := makeRouteToken(NewsletterViewKey, sendRequestID)
+	if err != nil {
+		return nil, err
+	}
+	return ptr.String(env.GetAbsoluteURLForEnvironment(fmt.Sprintf("view-newsletter/%s", *token))), nil
+}
+
 func MakePaymentSettingsRouteForUserID(userID users.UserID) (*string, error) {
 	managementLink, err := MakeSubscriptionManagementRouteForUserID(userID)
 	if err != nil {
@@ -66,6 +83,30 @@ func MakePaymentSettingsRouteForUserID(userID users.UserID) (*string, error) {
 	return ptr.String(fmt.Sprintf("%s/payment-settings", *managementLink)), nil
 }
 
+func MakeGiftCodeRouteForUserID(userID users.UserID) (*string, error) {
+	managementLink, err := MakeSubscriptionManagementRouteForUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return ptr.String(fmt.Sprintf("%s/gift-code", *managementLink)), nil
+}
+
+func MakeGroupLicensesRouteForUserID(userID users.UserID) (*string, error) {
+	managementLink, err := MakeSubscriptionManagementRouteForUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return ptr.String(fmt.Sprintf("%s/group-licenses", *managementLink)), nil
+}
+
+func MakeNewsletterArchiveRouteForUserID(userID users.UserID) (*string, error) {
+	managementLink, err := MakeSubscriptionManagementRouteForUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return ptr.String(fmt.Sprintf("%s/archive", *managementLink)), nil
+}
+
 func MakePremiumInformationLink(userID users.UserID) (*string, error) {
 	managementLink, err := MakeSubscriptionManagementRouteForUserID(userID)
 	if err != nil {
@@ -75,10 +116,7 @@ func MakePremiumInformationLink(userID users.UserID) (*string, error) {
 }
 
 func MakeLogoURLForEmailRecordID(emailRecordID email.ID) (*string, error) {
-	token, err := encrypt.GetToken(encrypt.TokenPair{
-		Key:   EmailOpenedKey.Str(),
-		Value: emailRecordID,
-	})
+	token, err := makeRouteToken(EmailOpenedKey, emailRecordID)
 	if err != nil {
 		return nil, err
 	}
@@ -86,10 +124,7 @@ func MakeLogoURLForEmailRecordID(emailRecordID email.ID) (*string, error) {
 }
 
 func MakeUserVerificationLink(userID users.UserID) (*string, error) {
-	token, err := encrypt.GetToken(encrypt.TokenPair{
-		Key:   UserVerificationKey.Str(),
-		Value: userID,
-	})
+	token, err := makeRouteToken(UserVerificationKey, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -121,10 +156,7 @@ func MakePremiumSubscriptionCheckoutLink(userID users.UserID) (*string, error) {
 }
 
 func MakeArticleLink(userDocumentID userdocuments.UserDocumentID) (*string, error) {
-	token, err := encrypt.GetToken(encrypt.TokenPair{
-		Key:   ArticleLinkKeyForUserDocumentID.Str(),
-		Value: userDocumentID,
-	})
+	token, err := makeRouteToken(ArticleLinkKeyForUserDocumentID, userDocumentID)
 	if err != nil {
 		return nil, err
 	}
@@ -132,21 +164,23 @@ func MakeArticleLink(userDocumentID userdocuments.UserDocumentID) (*string, erro
 }
 
 func MakePaywallReportLink(userDocumentID userdocuments.UserDocumentID) (*string, error) {
-	token, err := encrypt.GetToken(encrypt.TokenPair{
-		Key:   PaywallReportKeyForUserDocumentID.Str(),
-		Value: userDocumentID,
-	})
+	token, err := makeRouteToken(PaywallReportKeyForUserDocumentID, userDocumentID)
 	if err != nil {
 		return nil, err
 	}
 	return ptr.String(env.GetAbsoluteURLForEnvironment(fmt.Sprintf("paywall-report/%s", *token))), nil
 }
 
+func MakeUserDataRequestLink(userID users.UserID) (*string, error) {
+	token, err := makeRouteToken(UserDataRequestKey, userID)
+	if err != nil {
+		return nil, err
+	}
+	return ptr.String(env.GetAbsoluteURLForEnvironment(fmt.Sprintf("user-data/%s", *token))), nil
+}
+
 func MakeForgotPasswordLink(forgotPasswordAttemptID useraccounts.ForgotPasswordAttemptID) (*string, error) {
-	token, err := encrypt.GetToken(encrypt.TokenPair{
-		Key:   ForgotPasswordKey.Str(),
-		Value: forgotPasswordAttemptID,
-	})
+	token, err := makeRouteToken(ForgotPasswordKey, forgotPasswordAttemptID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,54 @@
+package sesnotifications
+
+import (
+	"babblegraph/util/ses"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// A single transient bounce is usually a full mailbox or a
+// greylisting server, so those addresses are only blocklisted
+// once they have bounced a few times in a short period
+const (
+	transientBounceBlocklistThreshold = 3
+	transientBounceLookbackWindow     = 30 * 24 * time.Hour
+)
+
+const (
+	insertTransientBounceQuery           = "INSERT INTO ses_transient_bounces (email_address, ses_message_id, bounce_sub_type) VALUES ($1, $2, $3)"
+	countTransientBouncesForAddressQuery = "SELECT COUNT(*) FROM ses_transient_bounces WHERE email_address = $1 AND created_at >= $2"
+)
+
+type RecordBounceForRecipientInput struct {
+	EmailAddress string
+	SESMessageID string
+	Bounce       ses.Bounce
+}
+
+// RecordBounceForRecipient returns true if the recipient
+// should be added to the bounce blocklist
+func RecordBounceForRecipient(tx *sqlx.Tx, input RecordBounceForRecipientInput) (_shouldBlocklist bool, _err error) {
+	if input.Bounce.Type == ses.BounceTypePermanent {
+		return true, nil
+	}
+	if _, err := tx.Exec(insertTransientBounceQuery, input.EmailAddress, input.SESMessageID, input.Bounce.Subtype); err != nil {
+		return false, err
+	}
+	var numberOfTransientBounces int64
+	if err := tx.Get(&numberOfTransientBounces, countTransientBouncesForAddressQuery, input.EmailAddress, time.Now().Add(-transientBounceLookbackWindow).UTC()); err != nil {
+		return false, err
+	}
+	return shouldBlocklistForBounce(input.Bounce.Type, numberOfTransientBounces), nil
+}
+
+func shouldBlocklistForBounce(bounceType ses.BounceType, numberOfRecentTransientBounces int64) bool {
+	switch bounceType {
+	case ses.BounceTypePermanent:
+		return true
+	default:
+		// Undetermined bounces, and any type SES adds later,
+		// are treated like transient ones
+		return numberOfRecentTransientBounces >= transientBounceBlocklistThreshold
+	}
+}
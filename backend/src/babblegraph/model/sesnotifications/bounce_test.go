@@ -0,0 +1,27 @@
+package sesnotifications
+
+import (
+	"babblegraph/util/ses"
+	"testing"
+)
+
+func TestShouldBlocklistForBounce(t *testing.T) {
+	testCases := []struct {
+		bounceType                     ses.BounceType
+		numberOfRecentTransientBounces int64
+		expected                       bool
+	}{
+		{bounceType: ses.BounceTypePermanent, numberOfRecentTransientBounces: 0, expected: true},
+		{bounceType: ses.BounceTypeTransient, numberOfRecentTransientBounces: 1, expected: false},
+		{bounceType: ses.BounceTypeTransient, numberOfRecentTransientBounces: transientBounceBlocklistThreshold - 1, expected: false},
+		{bounceType: ses.BounceTypeTransient, numberOfRecentTransientBounces: transientBounceBlocklistThreshold, expected: true},
+		{bounceType: ses.BounceTypeUndetermined, numberOfRecentTransientBounces: 1, expected: false},
+		{bounceType: ses.BounceTypeUndetermined, numberOfRecentTransientBounces: transientBounceBlocklistThreshold, expected: true},
+		{bounceType: ses.BounceType("Unknown"), numberOfRecentTransientBounces: 1, expected: false},
+	}
+	for _, tc := range testCases {
+		if result := shouldBlocklistForBounce(tc.bounceType, tc.numberOfRecentTransientBounces); result != tc.expected {
+			t.Errorf("Expected %t for %s bounce with %d recent transient bounces, but got %t", tc.expected, tc.bounceType, tc.numberOfRecentTransientBounces, result)
+		}
+	}
+}
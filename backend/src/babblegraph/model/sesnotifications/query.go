@@ -19,3 +19,23 @@ func InsertSESNotification(tx *sqlx.Tx, messageBody ses.Notification) error {
 	}
 	return nil
 }
+
+const markNotificationAsProcessedQuery = `INSERT INTO
+    ses_processed_notifications (ses_message_id, notification_type)
+    VALUES ($1, $2)
+    ON CONFLICT (ses_message_id, notification_type) DO NOTHING`
+
+// MarkNotificationAsProcessed returns false if a notification
+// of the same type has already been processed for the SES message,
+// in which case it should not be processed again
+func MarkNotificationAsProcessed(tx *sqlx.Tx, sesMessageID string, notificationType ses.NotificationType) (_isFirstTime bool, _err error) {
+	res, err := tx.Exec(markNotificationAsProcessedQuery, sesMessageID, notificationType)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
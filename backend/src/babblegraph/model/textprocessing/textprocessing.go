@@ -1,7 +1,7 @@
 package textprocessing
 
 import (
-	"babblegraph/services/worker/textprocessing/spanishprocessing"
+	"babblegraph/model/textprocessing/spanishprocessing"
 	"babblegraph/util/math/decimal"
 	"babblegraph/util/ptr"
 	"babblegraph/util/text"
@@ -39,22 +39,10 @@ func ProcessText(input ProcessTextInput) (*TextMetadata, error) {
 		}
 		var lemmatizedDescription *LemmatizedDescription
 		if normalizedDescription != nil {
-			lemmatizedTokens, err := spanishprocessing.LemmatizeText(*normalizedDescription)
+			lemmatizedDescription, err = lemmatizeSpanishText(*normalizedDescription)
 			if err != nil {
 				return nil, err
 			}
-			var indexMappings []int
-			var lemmatizedTextTokens []string
-			for idx, lemmaToken := range lemmatizedTokens {
-				if lemmaToken != nil {
-					indexMappings = append(indexMappings, idx)
-					lemmatizedTextTokens = append(lemmatizedTextTokens, lemmaToken.Str())
-				}
-			}
-			lemmatizedDescription = &LemmatizedDescription{
-				LemmatizedText: strings.Join(lemmatizedTextTokens, " "),
-				IndexMappings:  indexMappings,
-			}
 		}
 		return &TextMetadata{
 			ReadabilityScore:      *readabilityScore,
@@ -64,3 +52,34 @@ func ProcessText(input ProcessTextInput) (*TextMetadata, error) {
 		panic("unrecognized language")
 	}
 }
+
+// LemmatizeText lemmatizes text without calculating a readability
+// score, for longer text like podcast transcripts where only the lemmas are needed.
+func LemmatizeText(languageCode wordsmith.LanguageCode, bodyText string) (*LemmatizedDescription, error) {
+	normalizedText := text.Normalize(bodyText)
+	switch languageCode {
+	case wordsmith.LanguageCodeSpanish:
+		return lemmatizeSpanishText(normalizedText)
+	default:
+		panic("unrecognized language")
+	}
+}
+
+func lemmatizeSpanishText(normalizedText string) (*LemmatizedDescription, error) {
+	lemmatizedTokens, err := spanishprocessing.LemmatizeText(normalizedText)
+	if err != nil {
+		return nil, err
+	}
+	var indexMappings []int
+	var lemmatizedTextTokens []string
+	for idx, lemmaToken := range lemmatizedTokens {
+		if lemmaToken != nil {
+			indexMappings = append(indexMappings, idx)
+			lemmatizedTextTokens = append(lemmatizedTextTokens, lemmaToken.Str())
+		}
+	}
+	return &LemmatizedDescription{
+		LemmatizedText: strings.Join(lemmatizedTextTokens, " "),
+		IndexMappings:  indexMappings,
+	}, nil
+}
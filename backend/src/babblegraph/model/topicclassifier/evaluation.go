@@ -0,0 +1,108 @@
+package topicclassifier
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/wordsmith"
+	"hash/fnv"
+	"sort"
+)
+
+// Examples are split on a hash of their ID so that the
+// same document always lands in the same split between runs
+const heldOutPercentage = 20
+
+type TopicEvaluation struct {
+	TopicID        content.TopicID `json:"topic_id"`
+	Support        int64           `json:"support"`
+	TruePositives  int64           `json:"true_positives"`
+	FalsePositives int64           `json:"false_positives"`
+	FalseNegatives int64           `json:"false_negatives"`
+	Precision      *float64        `json:"precision,omitempty"`
+	Recall         *float64        `json:"recall,omitempty"`
+}
+
+type Evaluation struct {
+	NumberOfTrainingExamples int64             `json:"number_of_training_examples"`
+	NumberOfHeldOutExamples  int64             `json:"number_of_held_out_examples"`
+	NumberOfUnclassified     int64             `json:"number_of_unclassified"`
+	Topics                   []TopicEvaluation `json:"topics"`
+}
+
+func isHeldOut(example TrainingExample) bool {
+	h := fnv.New32a()
+	h.Write([]byte(example.ID))
+	return h.Sum32()%100 < heldOutPercentage
+}
+
+// TrainAndEvaluate trains a classifier on the training split of the examples
+// and reports precision and recall of the assigned topics on the held out split.
+// The returned classifier is the one that was evaluated.
+func TrainAndEvaluate(languageCode wordsmith.LanguageCode, examples []TrainingExample) (*Classifier, *Evaluation) {
+	var training, heldOut []TrainingExample
+	for _, example := range examples {
+		if isHeldOut(example) {
+			heldOut = append(heldOut, example)
+		} else {
+			training = append(training, example)
+		}
+	}
+	classifier := Train(languageCode, training)
+	evaluation := Evaluate(classifier, heldOut)
+	evaluation.NumberOfTrainingExamples = int64(len(training))
+	return classifier, evaluation
+}
+
+func Evaluate(classifier *Classifier, examples []TrainingExample) *Evaluation {
+	evaluationsByTopicID := make(map[content.TopicID]*TopicEvaluation)
+	getTopicEvaluation := func(topicID content.TopicID) *TopicEvaluation {
+		if e, ok := evaluationsByTopicID[topicID]; ok {
+			return e
+		}
+		e := &TopicEvaluation{TopicID: topicID}
+		evaluationsByTopicID[topicID] = e
+		return e
+	}
+	evaluation := &Evaluation{
+		NumberOfHeldOutExamples: int64(len(examples)),
+	}
+	for _, example := range examples {
+		actual := make(map[content.TopicID]bool)
+		for _, topicID := range example.TopicIDs {
+			actual[topicID] = true
+			getTopicEvaluation(topicID).Support++
+		}
+		assigned := classifier.AssignTopics(example.Tokens)
+		if len(assigned) == 0 {
+			evaluation.NumberOfUnclassified++
+		}
+		predicted := make(map[content.TopicID]bool)
+		for _, classification := range assigned {
+			predicted[classification.TopicID] = true
+			if actual[classification.TopicID] {
+				getTopicEvaluation(classification.TopicID).TruePositives++
+			} else {
+				getTopicEvaluation(classification.TopicID).FalsePositives++
+			}
+		}
+		for topicID := range actual {
+			if !predicted[topicID] {
+				getTopicEvaluation(topicID).FalseNegatives++
+			}
+		}
+	}
+	for _, e := range evaluationsByTopicID {
+		if predictedCount := e.TruePositives + e.FalsePositives; predictedCount > 0 {
+			precision := float64(e.TruePositives) / float64(predictedCount)
+			e.Precision = &precision
+		}
+		if actualCount := e.TruePositives + e.FalseNegatives; actualCount > 0 {
+			recall := float64(e.TruePositives) / float64(actualCount)
+			e.Recall = &recall
+		}
+		evaluation.Topics = append(evaluation.Topics, *e)
+	}
+	sort.Slice(evaluation.Topics, func(i, j int) bool {
+		return evaluation.Topics[i].TopicID < evaluation.Topics[j].TopicID
+	})
+	return evaluation
+}
@@ -0,0 +1,153 @@
+package topicclassifier
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/wordsmith"
+	"math"
+	"sort"
+)
+
+const (
+	// Classified topics are only assigned if the classifier
+	// is reasonably confident, since a wrong topic is worse for
+	// a newsletter than no topic at all.
+	minimumConfidenceForAssignment = 0.35
+	maximumAssignedTopics          = 2
+
+	// Documents with very short descriptions don't carry
+	// enough signal to classify
+	minimumKnownTokensForClassification = 3
+)
+
+type topicCounts struct {
+	NumberOfDocuments int64            `json:"number_of_documents"`
+	NumberOfTokens    int64            `json:"number_of_tokens"`
+	TokenCounts       map[string]int64 `json:"token_counts"`
+}
+
+// Classifier is a multinomial naive Bayes classifier over
+// the lemma IDs of a document's lemmatized description. Documents
+// with several topics count once towards each of their topics.
+type Classifier struct {
+	LanguageCode      wordsmith.LanguageCode          `json:"language_code"`
+	NumberOfDocuments int64                           `json:"number_of_documents"`
+	VocabularySize    int64                           `json:"vocabulary_size"`
+	Topics            map[content.TopicID]topicCounts `json:"topics"`
+}
+
+type TrainingExample struct {
+	ID       string
+	Tokens   []string
+	TopicIDs []content.TopicID
+}
+
+type Classification struct {
+	TopicID    content.TopicID `json:"topic_id"`
+	Confidence float64         `json:"confidence"`
+}
+
+func Train(languageCode wordsmith.LanguageCode, examples []TrainingExample) *Classifier {
+	classifier := &Classifier{
+		LanguageCode: languageCode,
+		Topics:       make(map[content.TopicID]topicCounts),
+	}
+	vocabulary := make(map[string]bool)
+	for _, example := range examples {
+		if len(example.TopicIDs) == 0 || len(example.Tokens) == 0 {
+			continue
+		}
+		classifier.NumberOfDocuments++
+		for _, topicID := range example.TopicIDs {
+			counts, ok := classifier.Topics[topicID]
+			if !ok {
+				counts = topicCounts{
+					TokenCounts: make(map[string]int64),
+				}
+			}
+			counts.NumberOfDocuments++
+			for _, token := range example.Tokens {
+				counts.TokenCounts[token]++
+				counts.NumberOfTokens++
+				vocabulary[token] = true
+			}
+			classifier.Topics[topicID] = counts
+		}
+	}
+	classifier.VocabularySize = int64(len(vocabulary))
+	return classifier
+}
+
+// Classify returns the posterior probability of every topic
+// for the given tokens, sorted by descending confidence. Tokens
+// that were never seen in training are ignored.
+func (c *Classifier) Classify(tokens []string) []Classification {
+	if c.NumberOfDocuments == 0 || len(c.Topics) == 0 {
+		return nil
+	}
+	knownTokens := c.getKnownTokens(tokens)
+	if len(knownTokens) < minimumKnownTokensForClassification {
+		return nil
+	}
+	var topicIDs []content.TopicID
+	var logProbabilities []float64
+	maxLogProbability := math.Inf(-1)
+	for topicID, counts := range c.Topics {
+		logProbability := math.Log(float64(counts.NumberOfDocuments) / float64(c.NumberOfDocuments))
+		denominator := float64(counts.NumberOfTokens + c.VocabularySize)
+		for _, token := range knownTokens {
+			// Laplace smoothing so that unseen tokens for
+			// a topic don't zero out its probability
+			logProbability += math.Log(float64(counts.TokenCounts[token]+1) / denominator)
+		}
+		topicIDs = append(topicIDs, topicID)
+		logProbabilities = append(logProbabilities, logProbability)
+		if logProbability > maxLogProbability {
+			maxLogProbability = logProbability
+		}
+	}
+	// Normalize with log-sum-exp to avoid underflow on long descriptions
+	var total float64
+	for _, logProbability := range logProbabilities {
+		total += math.Exp(logProbability - maxLogProbability)
+	}
+	var out []Classification
+	for idx, topicID := range topicIDs {
+		out = append(out, Classification{
+			TopicID:    topicID,
+			Confidence: math.Exp(logProbabilities[idx]-maxLogProbability) / total,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Confidence == out[j].Confidence {
+			return out[i].TopicID < out[j].TopicID
+		}
+		return out[i].Confidence > out[j].Confidence
+	})
+	return out
+}
+
+// AssignTopics returns the classifications that are
+// confident enough to be used as the topics of a document
+func (c *Classifier) AssignTopics(tokens []string) []Classification {
+	var out []Classification
+	for _, classification := range c.Classify(tokens) {
+		if classification.Confidence < minimumConfidenceForAssignment || len(out) >= maximumAssignedTopics {
+			break
+		}
+		out = append(out, classification)
+	}
+	return out
+}
+
+func (c *Classifier) getKnownTokens(tokens []string) []string {
+	var out []string
+	for _, token := range tokens {
+		for _, counts := range c.Topics {
+			if _, ok := counts.TokenCounts[token]; ok {
+				out = append(out, token)
+				break
+			}
+		}
+	}
+	return out
+}
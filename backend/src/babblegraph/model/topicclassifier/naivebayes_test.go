@@ -0,0 +1,88 @@
+package topicclassifier
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/wordsmith"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var testTrainingExamples = []TrainingExample{
+	{ID: "1", Tokens: strings.Fields("gol partido liga equipo entrenador"), TopicIDs: []content.TopicID{"sports"}},
+	{ID: "2", Tokens: strings.Fields("partido equipo jugador gol estadio"), TopicIDs: []content.TopicID{"sports"}},
+	{ID: "3", Tokens: strings.Fields("liga jugador fichaje equipo temporada"), TopicIDs: []content.TopicID{"sports"}},
+	{ID: "4", Tokens: strings.Fields("gobierno elección partido presidente congreso"), TopicIDs: []content.TopicID{"politics"}},
+	{ID: "5", Tokens: strings.Fields("presidente ministro gobierno ley congreso"), TopicIDs: []content.TopicID{"politics"}},
+	{ID: "6", Tokens: strings.Fields("elección voto candidato gobierno campaña"), TopicIDs: []content.TopicID{"politics"}},
+}
+
+func TestClassify(t *testing.T) {
+	classifier := Train(wordsmith.LanguageCodeSpanish, testTrainingExamples)
+	if classifier.NumberOfDocuments != 6 {
+		t.Errorf("Expected 6 documents, but got %d", classifier.NumberOfDocuments)
+	}
+	type testCase struct {
+		tokens          []string
+		expectedTopicID *content.TopicID
+	}
+	for idx, tc := range []testCase{
+		{
+			tokens:          strings.Fields("el equipo ganó el partido con un gol"),
+			expectedTopicID: content.TopicID("sports").Ptr(),
+		}, {
+			tokens:          strings.Fields("el presidente y el congreso aprobaron la ley del gobierno"),
+			expectedTopicID: content.TopicID("politics").Ptr(),
+		}, {
+			// Not enough known tokens
+			tokens:          strings.Fields("el gato come pescado con gol"),
+			expectedTopicID: nil,
+		},
+	} {
+		assigned := classifier.AssignTopics(tc.tokens)
+		switch {
+		case tc.expectedTopicID == nil && len(assigned) != 0:
+			t.Errorf("Error on test case %d: expected no topics, but got %+v", idx, assigned)
+		case tc.expectedTopicID != nil && len(assigned) == 0:
+			t.Errorf("Error on test case %d: expected topic %s, but got none", idx, *tc.expectedTopicID)
+		case tc.expectedTopicID != nil && assigned[0].TopicID != *tc.expectedTopicID:
+			t.Errorf("Error on test case %d: expected topic %s, but got %s", idx, *tc.expectedTopicID, assigned[0].TopicID)
+		}
+		var total float64
+		for _, c := range classifier.Classify(tc.tokens) {
+			total += c.Confidence
+		}
+		if len(assigned) > 0 && (total < 0.999 || total > 1.001) {
+			t.Errorf("Error on test case %d: expected confidences to sum to 1, but got %f", idx, total)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	classifier := Train(wordsmith.LanguageCodeSpanish, testTrainingExamples)
+	evaluation := Evaluate(classifier, []TrainingExample{
+		{ID: "7", Tokens: strings.Fields("equipo gol partido estadio"), TopicIDs: []content.TopicID{"sports"}},
+		{ID: "8", Tokens: strings.Fields("gobierno ley ministro congreso"), TopicIDs: []content.TopicID{"politics"}},
+		{ID: "9", Tokens: strings.Fields("jugador equipo liga temporada"), TopicIDs: []content.TopicID{"politics"}},
+	})
+	if evaluation.NumberOfHeldOutExamples != 3 {
+		t.Errorf("Expected 3 held out examples, but got %d", evaluation.NumberOfHeldOutExamples)
+	}
+	expected := map[content.TopicID]string{
+		"politics": "support 2, precision 1.00, recall 0.50",
+		"sports":   "support 1, precision 0.50, recall 1.00",
+	}
+	if len(evaluation.Topics) != len(expected) {
+		t.Fatalf("Expected %d topics, but got %d", len(expected), len(evaluation.Topics))
+	}
+	for _, e := range evaluation.Topics {
+		if e.Precision == nil || e.Recall == nil {
+			t.Errorf("Expected precision and recall for topic %s", e.TopicID)
+			continue
+		}
+		result := fmt.Sprintf("support %d, precision %.2f, recall %.2f", e.Support, *e.Precision, *e.Recall)
+		if result != expected[e.TopicID] {
+			t.Errorf("Error on topic %s: expected %s, but got %s", e.TopicID, expected[e.TopicID], result)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package topicclassifier
+
+import (
+	"babblegraph/wordsmith"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	deactivateClassifiersForLanguageQuery = "UPDATE content_topic_classifier SET is_active = FALSE, last_modified_at = timezone('utc', now()) WHERE language_code = $1 AND is_active = TRUE"
+	insertClassifierQuery                 = "INSERT INTO content_topic_classifier (language_code, classifier, evaluation, is_active) VALUES ($1, $2, $3, TRUE) RETURNING _id"
+	lookupActiveClassifierQuery           = "SELECT * FROM content_topic_classifier WHERE language_code = $1 AND is_active = TRUE"
+)
+
+type ClassifierID string
+
+type dbClassifier struct {
+	ID             ClassifierID           `db:"_id"`
+	CreatedAt      time.Time              `db:"created_at"`
+	LastModifiedAt time.Time              `db:"last_modified_at"`
+	LanguageCode   wordsmith.LanguageCode `db:"language_code"`
+	Classifier     []byte                 `db:"classifier"`
+	Evaluation     []byte                 `db:"evaluation"`
+	IsActive       bool                   `db:"is_active"`
+}
+
+// InsertActiveClassifier saves the classifier and its evaluation
+// and replaces the active classifier for the language
+func InsertActiveClassifier(tx *sqlx.Tx, classifier Classifier, evaluation Evaluation) (*ClassifierID, error) {
+	classifierAsJSON, err := json.Marshal(classifier)
+	if err != nil {
+		return nil, err
+	}
+	evaluationAsJSON, err := json.Marshal(evaluation)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(deactivateClassifiersForLanguageQuery, classifier.LanguageCode); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(insertClassifierQuery, classifier.LanguageCode, string(classifierAsJSON), string(evaluationAsJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var id ClassifierID
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+	}
+	return &id, nil
+}
+
+// LookupActiveClassifierForLanguage returns nil if no
+// classifier has been trained for the language yet
+func LookupActiveClassifierForLanguage(tx *sqlx.Tx, languageCode wordsmith.LanguageCode) (*Classifier, error) {
+	var matches []dbClassifier
+	err := tx.Select(&matches, lookupActiveClassifierQuery, languageCode)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one active classifier for language %s, but got %d", languageCode, len(matches))
+	case len(matches) == 0:
+		return nil, nil
+	}
+	var classifier Classifier
+	if err := json.Unmarshal(matches[0].Classifier, &classifier); err != nil {
+		return nil, err
+	}
+	return &classifier, nil
+}
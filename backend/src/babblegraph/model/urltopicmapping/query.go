@@ -3,6 +3,7 @@ package urltopicmapping
 import (
 	"babblegraph/model/content"
 	"babblegraph/model/contenttopics"
+	"babblegraph/util/ctx"
 	"babblegraph/util/database"
 	"babblegraph/util/urlparser"
 	"fmt"
@@ -47,3 +48,34 @@ func GetTopicsAndMappingIDsForURL(tx *sqlx.Tx, url string) ([]contenttopics.Cont
 	}
 	return topics, topicMappingIDs, nil
 }
+
+// LookupTopicsForURL returns the topics for the URL from both the old content topic
+// mappings and the source seed topic mappings
+func LookupTopicsForURL(c ctx.LogContext, tx *sqlx.Tx, u string) ([]contenttopics.ContentTopic, []content.TopicMappingID, []content.TopicID, error) {
+	topicsForURL, topicMappingIDs, err := GetTopicsAndMappingIDsForURL(tx, u)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var sourceSeedTopicMappings []content.SourceSeedTopicMappingID
+	for _, topicMappingID := range topicMappingIDs {
+		sourceSeedTopicMapping, sourceTopicMapping, err := topicMappingID.GetOriginID()
+		switch {
+		case err != nil:
+			return nil, nil, nil, err
+		case sourceTopicMapping != nil:
+			c.Warnf("Found source topic mapping from ID %s, which is unsupported", topicMappingID)
+		case sourceSeedTopicMapping != nil:
+			sourceSeedTopicMappings = append(sourceSeedTopicMappings, *sourceSeedTopicMapping)
+		default:
+			return nil, nil, nil, fmt.Errorf("unreachable")
+		}
+	}
+	var topicIDs []content.TopicID
+	if len(sourceSeedTopicMappings) > 0 {
+		topicIDs, err = content.LookupTopicsForSourceSeedMappingIDs(tx, sourceSeedTopicMappings)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return topicsForURL, topicMappingIDs, topicIDs, nil
+}
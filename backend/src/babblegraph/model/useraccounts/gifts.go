@@ -0,0 +1,200 @@
+package useraccounts
+
+import (
+	"babblegraph/model/users"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	insertGiftCodeQuery            = "INSERT INTO user_account_gift_codes (code, duration_days, purchaser_email_address, note) VALUES ($1, $2, $3, $4) RETURNING _id"
+	lookupGiftCodeByIDQuery        = "SELECT * FROM user_account_gift_codes WHERE _id = $1"
+	lookupUnredeemedGiftCodeQuery  = "SELECT * FROM user_account_gift_codes WHERE code = $1 AND redeemed_by_user_id IS NULL FOR UPDATE"
+	getAllGiftCodesQuery           = "SELECT * FROM user_account_gift_codes ORDER BY created_at DESC"
+	getActiveGiftCodesForUserQuery = "SELECT * FROM user_account_gift_codes WHERE redeemed_by_user_id = $1 AND premium_expires_at > timezone('utc', now())"
+	redeemGiftCodeQuery            = "UPDATE user_account_gift_codes SET redeemed_by_user_id = $2, redeemed_at = timezone('utc', now()), premium_expires_at = $3 WHERE _id = $1"
+
+	// Leaves out characters that are easily confused when read aloud or
+	// copied from a card, like 0 and O
+	giftCodeCharSet        = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	giftCodeGroupLength    = 4
+	giftCodeNumberOfGroups = 3
+)
+
+type GiftCodeID string
+
+type dbGiftCode struct {
+	ID                    GiftCodeID    `db:"_id"`
+	CreatedAt             time.Time     `db:"created_at"`
+	Code                  string        `db:"code"`
+	DurationDays          int64         `db:"duration_days"`
+	PurchaserEmailAddress *string       `db:"purchaser_email_address"`
+	Note                  *string       `db:"note"`
+	RedeemedByUserID      *users.UserID `db:"redeemed_by_user_id"`
+	RedeemedAt            *time.Time    `db:"redeemed_at"`
+	PremiumExpiresAt      *time.Time    `db:"premium_expires_at"`
+}
+
+func (d dbGiftCode) ToNonDB() GiftCode {
+	return GiftCode{
+		ID:                    d.ID,
+		CreatedAt:             d.CreatedAt,
+		Code:                  d.Code,
+		DurationDays:          d.DurationDays,
+		PurchaserEmailAddress: d.PurchaserEmailAddress,
+		Note:                  d.Note,
+		RedeemedByUserID:      d.RedeemedByUserID,
+		RedeemedAt:            d.RedeemedAt,
+		PremiumExpiresAt:      d.PremiumExpiresAt,
+	}
+}
+
+type GiftCode struct {
+	ID                    GiftCodeID    `json:"id"`
+	CreatedAt             time.Time     `json:"created_at"`
+	Code                  string        `json:"code"`
+	DurationDays          int64         `json:"duration_days"`
+	PurchaserEmailAddress *string       `json:"purchaser_email_address,omitempty"`
+	Note                  *string       `json:"note,omitempty"`
+	RedeemedByUserID      *users.UserID `json:"redeemed_by_user_id,omitempty"`
+	RedeemedAt            *time.Time    `json:"redeemed_at,omitempty"`
+	PremiumExpiresAt      *time.Time    `json:"premium_expires_at,omitempty"`
+}
+
+type CreateGiftCodeInput struct {
+	DurationDays          int64
+	PurchaserEmailAddress *string
+	Note                  *string
+}
+
+func CreateGiftCode(tx *sqlx.Tx, input CreateGiftCodeInput) (*GiftCode, error) {
+	if input.DurationDays <= 0 {
+		return nil, fmt.Errorf("Gift code duration must be positive, got %d days", input.DurationDays)
+	}
+	code, err := makeGiftCode()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(insertGiftCodeQuery, *code, input.DurationDays, input.PurchaserEmailAddress, input.Note)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var id GiftCodeID
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	return getGiftCodeByID(tx, id)
+}
+
+func getGiftCodeByID(tx *sqlx.Tx, id GiftCodeID) (*GiftCode, error) {
+	var matches []dbGiftCode
+	if err := tx.Select(&matches, lookupGiftCodeByIDQuery, id); err != nil {
+		return nil, err
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("Expected exactly one gift code with ID %s, but got %d", id, len(matches))
+	}
+	giftCode := matches[0].ToNonDB()
+	return &giftCode, nil
+}
+
+func GetAllGiftCodes(tx *sqlx.Tx) ([]GiftCode, error) {
+	var matches []dbGiftCode
+	if err := tx.Select(&matches, getAllGiftCodesQuery); err != nil {
+		return nil, err
+	}
+	var out []GiftCode
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+// RedeemGiftCodeForUser returns nil if the code does not exist or
+// has already been redeemed. Gifts that are redeemed while another gift
+// is still active start when the other gift ends.
+func RedeemGiftCodeForUser(tx *sqlx.Tx, userID users.UserID, code string) (*GiftCode, error) {
+	var matches []dbGiftCode
+	if err := tx.Select(&matches, lookupUnredeemedGiftCodeQuery, normalizeGiftCode(code)); err != nil {
+		return nil, err
+	}
+	if len(matches) != 1 {
+		return nil, nil
+	}
+	activeGiftCodes, err := getActiveGiftCodesForUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	premiumExpiresAt := getGiftCodeExpirationTime(time.Now(), matches[0].DurationDays, activeGiftCodes)
+	if _, err := tx.Exec(redeemGiftCodeQuery, matches[0].ID, userID, premiumExpiresAt); err != nil {
+		return nil, err
+	}
+	return getGiftCodeByID(tx, matches[0].ID)
+}
+
+func getActiveGiftCodesForUser(tx *sqlx.Tx, userID users.UserID) ([]dbGiftCode, error) {
+	var matches []dbGiftCode
+	if err := tx.Select(&matches, getActiveGiftCodesForUserQuery, userID); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func getGiftCodeExpirationTime(now time.Time, durationDays int64, activeGiftCodes []dbGiftCode) time.Time {
+	startsAt := now
+	for _, giftCode := range activeGiftCodes {
+		if giftCode.PremiumExpiresAt != nil && giftCode.PremiumExpiresAt.After(startsAt) {
+			startsAt = *giftCode.PremiumExpiresAt
+		}
+	}
+	return startsAt.Add(time.Duration(durationDays) * 24 * time.Hour)
+}
+
+func makeGiftCode() (*string, error) {
+	var groups []string
+	for i := 0; i < giftCodeNumberOfGroups; i++ {
+		var group []byte
+		for j := 0; j < giftCodeGroupLength; j++ {
+			idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(giftCodeCharSet))))
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, giftCodeCharSet[idx.Int64()])
+		}
+		groups = append(groups, string(group))
+	}
+	code := strings.Join(groups, "-")
+	return &code, nil
+}
+
+// normalizeGiftCode accepts codes as they're likely to be typed,
+// in any case and with or without the dashes
+func normalizeGiftCode(code string) string {
+	var chars []rune
+	for _, c := range strings.ToUpper(code) {
+		if c == '-' || c == ' ' {
+			continue
+		}
+		chars = append(chars, c)
+	}
+	var groups []string
+	for i := 0; i < len(chars); i += giftCodeGroupLength {
+		end := i + giftCodeGroupLength
+		if end > len(chars) {
+			end = len(chars)
+		}
+		groups = append(groups, string(chars[i:end]))
+	}
+	return strings.Join(groups, "-")
+}
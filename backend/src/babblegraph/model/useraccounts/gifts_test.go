@@ -0,0 +1,75 @@
+package useraccounts
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMakeGiftCode(t *testing.T) {
+	code, err := makeGiftCode()
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	groups := strings.Split(*code, "-")
+	if len(groups) != giftCodeNumberOfGroups {
+		t.Fatalf("Expected %d groups, but got %s", giftCodeNumberOfGroups, *code)
+	}
+	for _, group := range groups {
+		if len(group) != giftCodeGroupLength {
+			t.Errorf("Expected groups of length %d, but got %s", giftCodeGroupLength, *code)
+		}
+		for _, c := range group {
+			if !strings.ContainsRune(giftCodeCharSet, c) {
+				t.Errorf("Unexpected character %c in %s", c, *code)
+			}
+		}
+	}
+	if normalizeGiftCode(*code) != *code {
+		t.Errorf("Expected generated code %s to already be normalized", *code)
+	}
+}
+
+func TestNormalizeGiftCode(t *testing.T) {
+	for idx, input := range []string{
+		"ABCD-EFGH-JKLM",
+		"abcd-efgh-jklm",
+		"ABCDEFGHJKLM",
+		" abcd efgh jklm ",
+		"Ab-cdE-fghJ-klm",
+	} {
+		if result := normalizeGiftCode(input); result != "ABCD-EFGH-JKLM" {
+			t.Errorf("Error on test case %d: expected ABCD-EFGH-JKLM, but got %s", idx, result)
+		}
+	}
+}
+
+func TestGetGiftCodeExpirationTime(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	inTenDays := now.Add(10 * 24 * time.Hour)
+	tenDaysAgo := now.Add(-10 * 24 * time.Hour)
+	type testCase struct {
+		activeGiftCodes []dbGiftCode
+		expected        time.Time
+	}
+	for idx, tc := range []testCase{
+		{
+			expected: now.Add(365 * 24 * time.Hour),
+		}, {
+			activeGiftCodes: []dbGiftCode{
+				{PremiumExpiresAt: &inTenDays},
+			},
+			expected: inTenDays.Add(365 * 24 * time.Hour),
+		}, {
+			activeGiftCodes: []dbGiftCode{
+				{PremiumExpiresAt: &tenDaysAgo},
+				{PremiumExpiresAt: nil},
+			},
+			expected: now.Add(365 * 24 * time.Hour),
+		},
+	} {
+		if result := getGiftCodeExpirationTime(now, 365, tc.activeGiftCodes); !result.Equal(tc.expected) {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package useraccounts
+
+import (
+	"babblegraph/model/users"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	insertGroupLicenseQuery           = "INSERT INTO user_account_group_licenses (owner_user_id, name, seat_count, expires_at) VALUES ($1, $2, $3, $4) RETURNING _id"
+	lookupGroupLicenseByIDQuery       = "SELECT * FROM user_account_group_licenses WHERE _id = $1"
+	getAllGroupLicensesQuery          = "SELECT * FROM user_account_group_licenses ORDER BY created_at DESC"
+	getGroupLicensesForOwnerQuery     = "SELECT * FROM user_account_group_licenses WHERE owner_user_id = $1 ORDER BY created_at DESC"
+	updateGroupLicenseQuery           = "UPDATE user_account_group_licenses SET seat_count = $2, expires_at = $3, last_modified_at = timezone('utc', now()) WHERE _id = $1"
+	lockGroupLicenseForOwnerQuery     = "SELECT * FROM user_account_group_licenses WHERE _id = $1 AND owner_user_id = $2 FOR UPDATE"
+	getActiveGroupLicenseMembersQuery = "SELECT * FROM user_account_group_license_members WHERE group_license_id = $1 AND removed_at IS NULL ORDER BY created_at ASC"
+	removeGroupLicenseMemberQuery     = "UPDATE user_account_group_license_members SET removed_at = timezone('utc', now()) WHERE group_license_id = $1 AND email_address = $2 AND removed_at IS NULL"
+
+	// A member who was removed and added back has already been told about their seat
+	insertGroupLicenseMemberQuery = `INSERT INTO user_account_group_license_members (group_license_id, email_address, notified_at)
+        VALUES ($1, $2, (SELECT MAX(notified_at) FROM user_account_group_license_members WHERE group_license_id = $1 AND email_address = $2))`
+
+	getGroupLicenseMembersToNotifyQuery = `SELECT m._id, m.email_address, l.name, l.expires_at FROM user_account_group_license_members m
+        JOIN user_account_group_licenses l ON l._id = m.group_license_id
+        WHERE m.notified_at IS NULL AND m.removed_at IS NULL AND l.expires_at > timezone('utc', now())
+        ORDER BY m.created_at ASC`
+	markGroupLicenseMemberNotifiedQuery = "UPDATE user_account_group_license_members SET notified_at = timezone('utc', now()) WHERE _id = $1"
+
+	getActiveGroupLicenseMembershipsForUserQuery = `SELECT m.* FROM user_account_group_license_members m
+        JOIN user_account_group_licenses l ON l._id = m.group_license_id
+        JOIN users u ON u.email_address = m.email_address
+        WHERE u._id = $1 AND m.removed_at IS NULL AND l.expires_at > timezone('utc', now())`
+)
+
+type GroupLicenseID string
+
+type dbGroupLicense struct {
+	ID             GroupLicenseID `db:"_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+	LastModifiedAt time.Time      `db:"last_modified_at"`
+	OwnerUserID    users.UserID   `db:"owner_user_id"`
+	Name           string         `db:"name"`
+	SeatCount      int64          `db:"seat_count"`
+	ExpiresAt      time.Time      `db:"expires_at"`
+}
+
+func (d dbGroupLicense) ToNonDB(members []dbGroupLicenseMember) GroupLicense {
+	var memberEmailAddresses []string
+	for _, m := range members {
+		memberEmailAddresses = append(memberEmailAddresses, m.EmailAddress)
+	}
+	return GroupLicense{
+		ID:                   d.ID,
+		OwnerUserID:          d.OwnerUserID,
+		Name:                 d.Name,
+		SeatCount:            d.SeatCount,
+		ExpiresAt:            d.ExpiresAt,
+		MemberEmailAddresses: memberEmailAddresses,
+	}
+}
+
+type GroupLicense struct {
+	ID          GroupLicenseID `json:"id"`
+	OwnerUserID users.UserID   `json:"owner_user_id"`
+	Name        string         `json:"name"`
+	SeatCount   int64          `json:"seat_count"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	// This only includes members that haven't been removed
+	MemberEmailAddresses []string `json:"member_email_addresses"`
+}
+
+type groupLicenseMemberID string
+
+type dbGroupLicenseMember struct {
+	ID             groupLicenseMemberID `db:"_id"`
+	CreatedAt      time.Time            `db:"created_at"`
+	GroupLicenseID GroupLicenseID       `db:"group_license_id"`
+	EmailAddress   string               `db:"email_address"`
+	RemovedAt      *time.Time           `db:"removed_at"`
+	NotifiedAt     *time.Time           `db:"notified_at"`
+}
+
+// GroupLicenseMemberToNotify is a member that was added to
+// an unexpired license and hasn't been told about their seat yet
+type GroupLicenseMemberToNotify struct {
+	ID               groupLicenseMemberID `db:"_id"`
+	EmailAddress     string               `db:"email_address"`
+	GroupLicenseName string               `db:"name"`
+	ExpiresAt        time.Time            `db:"expires_at"`
+}
+
+type GroupLicenseMemberError string
+
+const (
+	GroupLicenseMemberErrorLicenseExpired   GroupLicenseMemberError = "license-expired"
+	GroupLicenseMemberErrorNoSeatsAvailable GroupLicenseMemberError = "no-seats-available"
+	GroupLicenseMemberErrorAlreadyMember    GroupLicenseMemberError = "already-member"
+)
+
+func (g GroupLicenseMemberError) Ptr() *GroupLicenseMemberError {
+	return &g
+}
+
+type CreateGroupLicenseInput struct {
+	OwnerUserID users.UserID
+	Name        string
+	SeatCount   int64
+	ExpiresAt   time.Time
+}
+
+func CreateGroupLicense(tx *sqlx.Tx, input CreateGroupLicenseInput) (*GroupLicense, error) {
+	if input.SeatCount <= 0 {
+		return nil, fmt.Errorf("Group license must have at least one seat, got %d", input.SeatCount)
+	}
+	rows, err := tx.Query(insertGroupLicenseQuery, input.OwnerUserID, input.Name, input.SeatCount, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var id GroupLicenseID
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	return GetGroupLicenseByID(tx, id)
+}
+
+func GetGroupLicenseByID(tx *sqlx.Tx, id GroupLicenseID) (*GroupLicense, error) {
+	var matches []dbGroupLicense
+	if err := tx.Select(&matches, lookupGroupLicenseByIDQuery, id); err != nil {
+		return nil, err
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("Expected exactly one group license with ID %s, but got %d", id, len(matches))
+	}
+	return withGroupLicenseMembers(tx, matches[0])
+}
+
+func GetAllGroupLicenses(tx *sqlx.Tx) ([]GroupLicense, error) {
+	var matches []dbGroupLicense
+	if err := tx.Select(&matches, getAllGroupLicensesQuery); err != nil {
+		return nil, err
+	}
+	return withManyGroupLicenseMembers(tx, matches)
+}
+
+func GetGroupLicensesForOwner(tx *sqlx.Tx, ownerUserID users.UserID) ([]GroupLicense, error) {
+	var matches []dbGroupLicense
+	if err := tx.Select(&matches, getGroupLicensesForOwnerQuery, ownerUserID); err != nil {
+		return nil, err
+	}
+	return withManyGroupLicenseMembers(tx, matches)
+}
+
+// UpdateGroupLicense is for renewing a license or changing its size.
+// The seat count can't go below the number of current members.
+func UpdateGroupLicense(tx *sqlx.Tx, id GroupLicenseID, seatCount int64, expiresAt time.Time) (*GroupLicense, error) {
+	groupLicense, err := GetGroupLicenseByID(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if seatCount < int64(len(groupLicense.MemberEmailAddresses)) {
+		return nil, fmt.Errorf("Group license %s has %d members, which is more than %d seats", id, len(groupLicense.MemberEmailAddresses), seatCount)
+	}
+	if _, err := tx.Exec(updateGroupLicenseQuery, id, seatCount, expiresAt); err != nil {
+		return nil, err
+	}
+	return GetGroupLicenseByID(tx, id)
+}
+
+// AddMemberToGroupLicense returns nil for the license if the user
+// does not own it. The license row is locked so that two members added at
+// the same time can't both take the last seat.
+func AddMemberToGroupLicense(tx *sqlx.Tx, ownerUserID users.UserID, id GroupLicenseID, emailAddress string) (*GroupLicense, *GroupLicenseMemberError, error) {
+	var matches []dbGroupLicense
+	if err := tx.Select(&matches, lockGroupLicenseForOwnerQuery, id, ownerUserID); err != nil {
+		return nil, nil, err
+	}
+	if len(matches) != 1 {
+		return nil, nil, nil
+	}
+	groupLicense, err := withGroupLicenseMembers(tx, matches[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if memberErr := getGroupLicenseMemberError(*groupLicense, emailAddress, time.Now()); memberErr != nil {
+		return groupLicense, memberErr, nil
+	}
+	if _, err := tx.Exec(insertGroupLicenseMemberQuery, id, emailAddress); err != nil {
+		return nil, nil, err
+	}
+	groupLicense, err = GetGroupLicenseByID(tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return groupLicense, nil, nil
+}
+
+// RemoveMemberFromGroupLicense returns nil if the user does not own the license
+func RemoveMemberFromGroupLicense(tx *sqlx.Tx, ownerUserID users.UserID, id GroupLicenseID, emailAddress string) (*GroupLicense, error) {
+	var matches []dbGroupLicense
+	if err := tx.Select(&matches, lockGroupLicenseForOwnerQuery, id, ownerUserID); err != nil {
+		return nil, err
+	}
+	if len(matches) != 1 {
+		return nil, nil
+	}
+	if _, err := tx.Exec(removeGroupLicenseMemberQuery, id, emailAddress); err != nil {
+		return nil, err
+	}
+	return GetGroupLicenseByID(tx, id)
+}
+
+func GetGroupLicenseMembersToNotify(tx *sqlx.Tx) ([]GroupLicenseMemberToNotify, error) {
+	var matches []GroupLicenseMemberToNotify
+	if err := tx.Select(&matches, getGroupLicenseMembersToNotifyQuery); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// Call this before sending the email
+func MarkGroupLicenseMemberNotified(tx *sqlx.Tx, member GroupLicenseMemberToNotify) error {
+	if _, err := tx.Exec(markGroupLicenseMemberNotifiedQuery, member.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func hasActiveGroupLicenseMembership(tx *sqlx.Tx, userID users.UserID) (bool, error) {
+	var matches []dbGroupLicenseMember
+	if err := tx.Select(&matches, getActiveGroupLicenseMembershipsForUserQuery, userID); err != nil {
+		return false, err
+	}
+	return len(matches) > 0, nil
+}
+
+func getGroupLicenseMemberError(groupLicense GroupLicense, emailAddress string, now time.Time) *GroupLicenseMemberError {
+	if !now.Before(groupLicense.ExpiresAt) {
+		return GroupLicenseMemberErrorLicenseExpired.Ptr()
+	}
+	for _, memberEmailAddress := range groupLicense.MemberEmailAddresses {
+		if memberEmailAddress == emailAddress {
+			return GroupLicenseMemberErrorAlreadyMember.Ptr()
+		}
+	}
+	if int64(len(groupLicense.MemberEmailAddresses)) >= groupLicense.SeatCount {
+		return GroupLicenseMemberErrorNoSeatsAvailable.Ptr()
+	}
+	return nil
+}
+
+func withGroupLicenseMembers(tx *sqlx.Tx, groupLicense dbGroupLicense) (*GroupLicense, error) {
+	var members []dbGroupLicenseMember
+	if err := tx.Select(&members, getActiveGroupLicenseMembersQuery, groupLicense.ID); err != nil {
+		return nil, err
+	}
+	out := groupLicense.ToNonDB(members)
+	return &out, nil
+}
+
+func withManyGroupLicenseMembers(tx *sqlx.Tx, groupLicenses []dbGroupLicense) ([]GroupLicense, error) {
+	var out []GroupLicense
+	for _, l := range groupLicenses {
+		groupLicense, err := withGroupLicenseMembers(tx, l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *groupLicense)
+	}
+	return out, nil
+}
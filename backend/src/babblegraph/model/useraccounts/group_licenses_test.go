@@ -0,0 +1,29 @@
+package useraccounts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetGroupLicenseMemberError(t *testing.T) {
+	now := time.Now()
+	groupLicense := GroupLicense{
+		SeatCount:            2,
+		ExpiresAt:            now.Add(24 * time.Hour),
+		MemberEmailAddresses: []string{"student-1@babblegraph.com"},
+	}
+	if err := getGroupLicenseMemberError(groupLicense, "student-2@babblegraph.com", now); err != nil {
+		t.Errorf("Expected no error for an open seat, but got %s", *err)
+	}
+	if err := getGroupLicenseMemberError(groupLicense, "student-1@babblegraph.com", now); err == nil || *err != GroupLicenseMemberErrorAlreadyMember {
+		t.Errorf("Expected %s for an existing member, but got %v", GroupLicenseMemberErrorAlreadyMember, err)
+	}
+	groupLicense.MemberEmailAddresses = append(groupLicense.MemberEmailAddresses, "student-2@babblegraph.com")
+	if err := getGroupLicenseMemberError(groupLicense, "student-3@babblegraph.com", now); err == nil || *err != GroupLicenseMemberErrorNoSeatsAvailable {
+		t.Errorf("Expected %s for a full license, but got %v", GroupLicenseMemberErrorNoSeatsAvailable, err)
+	}
+	groupLicense.SeatCount = 30
+	if err := getGroupLicenseMemberError(groupLicense, "student-3@babblegraph.com", now.Add(48*time.Hour)); err == nil || *err != GroupLicenseMemberErrorLicenseExpired {
+		t.Errorf("Expected %s for an expired license, but got %v", GroupLicenseMemberErrorLicenseExpired, err)
+	}
+}
@@ -82,7 +82,31 @@ func DoesUserHaveSubscription(tx *sqlx.Tx, userID users.UserID) (_didHaveAccount
 	return true, matches[0].IsActive, nil
 }
 
+// LookupSubscriptionLevelForUser is the level that a user should be treated as having.
+// Users without a subscription on their account are premium while they
+// have an active gift or are a member of an unexpired group license.
 func LookupSubscriptionLevelForUser(tx *sqlx.Tx, userID users.UserID) (*SubscriptionLevel, error) {
+	subscriptionLevel, err := LookupAccountSubscriptionLevelForUser(tx, userID)
+	switch {
+	case err != nil:
+		return nil, err
+	case subscriptionLevel != nil:
+		return subscriptionLevel, nil
+	}
+	hasPremiumGrant, err := HasPremiumGrantForUser(tx, userID)
+	switch {
+	case err != nil:
+		return nil, err
+	case hasPremiumGrant:
+		return SubscriptionLevelPremium.Ptr(), nil
+	default:
+		return nil, nil
+	}
+}
+
+// LookupAccountSubscriptionLevelForUser only looks at the subscription on the
+// user's account, which billing keeps in sync with Stripe. It ignores gifts and group licenses.
+func LookupAccountSubscriptionLevelForUser(tx *sqlx.Tx, userID users.UserID) (*SubscriptionLevel, error) {
 	var matches []dbUserSubscription
 	if err := tx.Select(&matches, getSubscriptionLevelForUserQuery, userID); err != nil {
 		return nil, err
@@ -93,6 +117,19 @@ func LookupSubscriptionLevelForUser(tx *sqlx.Tx, userID users.UserID) (*Subscrip
 	return matches[0].SubscriptionLevel.Ptr(), nil
 }
 
+// HasPremiumGrantForUser is true if the user has premium without a Stripe
+// subscription, either from a gift code or a group license
+func HasPremiumGrantForUser(tx *sqlx.Tx, userID users.UserID) (bool, error) {
+	activeGiftCodes, err := getActiveGiftCodesForUser(tx, userID)
+	switch {
+	case err != nil:
+		return false, err
+	case len(activeGiftCodes) > 0:
+		return true, nil
+	}
+	return hasActiveGroupLicenseMembership(tx, userID)
+}
+
 type AddSubscriptionLevelForUserInput struct {
 	UserID            users.UserID
 	SubscriptionLevel SubscriptionLevel
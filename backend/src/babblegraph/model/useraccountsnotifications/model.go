@@ -40,11 +40,20 @@ const (
 	NotificationTypePaymentError                NotificationType = "payment_error"
 	NotificationTypePremiumSubscriptionCanceled NotificationType = "premium_subscription_canceled"
 
+	NotificationTypeDunningPaymentFailed NotificationType = "dunning_payment_failed"
+	NotificationTypeDunningStillFailing  NotificationType = "dunning_still_failing"
+	NotificationTypeDunningFinalNotice   NotificationType = "dunning_final_notice"
+
 	NotificationTypeNeedPaymentMethodWarning           NotificationType = "need_payment_method_warning"
 	NotificationTypeTrialEndingSoon                    NotificationType = "trial_ending_soon"
 	NotificationTypeNeedPaymentMethodWarningUrgent     NotificationType = "need_payment_method_warning_urgent"
 	NotificationTypeNeedPaymentMethodWarningVeryUrgent NotificationType = "need_payment_method_warning_very_urgent"
 
+	NotificationTypeReengagement       NotificationType = "reengagement"
+	NotificationTypeReengagementSunset NotificationType = "reengagement_sunset"
+
+	NotificationTypeUserDataRequest NotificationType = "user_data_request"
+
 	NotificationTypeAccountCreatedDEPRECATED            NotificationType = "account_created"
 	NotificationTypeInitialPremiumInformationDEPRECATED NotificationType = "initial_premium_information"
 )
@@ -67,4 +76,10 @@ var minimumElapsedTimeBetweenNotificationsByType = map[NotificationType]*time.Du
 	NotificationTypeTrialEndingSoon:                    nil,
 	NotificationTypePaymentError:                       ptr.Duration(14 * 24 * time.Hour), // 2 weeks
 	NotificationTypePremiumSubscriptionCanceled:        ptr.Duration(3 * 24 * time.Hour),  // 3 days
+	NotificationTypeDunningPaymentFailed:               ptr.Duration(7 * 24 * time.Hour),  // 1 week
+	NotificationTypeDunningStillFailing:                ptr.Duration(7 * 24 * time.Hour),  // 1 week
+	NotificationTypeDunningFinalNotice:                 ptr.Duration(7 * 24 * time.Hour),  // 1 week
+	NotificationTypeReengagement:                       ptr.Duration(60 * 24 * time.Hour), // 2 months
+	NotificationTypeReengagementSunset:                 ptr.Duration(60 * 24 * time.Hour), // 2 months
+	NotificationTypeUserDataRequest:                    ptr.Duration(time.Hour),
 }
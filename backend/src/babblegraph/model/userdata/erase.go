@@ -0,0 +1,108 @@
+package userdata
+
+import (
+	"babblegraph/model/billing"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/users"
+	"babblegraph/model/utm"
+	"babblegraph/util/ctx"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	eraseRowsQuery = "DELETE FROM %s WHERE %s"
+
+	insertErasureTombstoneQuery     = "INSERT INTO user_data_erasure_tombstones (user_id, email_address_hash, erased_row_counts) VALUES ($1, $2, $3)"
+	getErasureTombstoneForUserQuery = "SELECT * FROM user_data_erasure_tombstones WHERE user_id = $1"
+)
+
+type EraseUserDataInput struct {
+	UserID     users.UserID
+	TrackingID *utm.TrackingID
+}
+
+// EraseUserData deletes everything personal about a user, anonymizes the
+// users row and their Stripe customer, cancels any premium subscription
+// and records a tombstone.
+// Erasing a user that has already been erased returns the existing tombstone.
+func EraseUserData(c ctx.LogContext, tx *sqlx.Tx, input EraseUserDataInput) (*ErasureTombstone, error) {
+	user, err := users.GetUser(tx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == users.UserStatusErased {
+		return GetErasureTombstoneForUser(tx, input.UserID)
+	}
+	// The subscription is looked up before anything is removed, but
+	// only cancelled at the end so that a failure to erase anything
+	// else leaves the user with the subscription they're paying for
+	premiumNewsletterSubscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	erasedRowCounts := make(map[string]int64)
+	for _, table := range userDataTables {
+		if table.ErasureAction != erasureActionDelete {
+			continue
+		}
+		key := getKeyForTable(table, *user, input.TrackingID)
+		if key == nil {
+			continue
+		}
+		res, err := tx.Exec(fmt.Sprintf(eraseRowsQuery, table.TableName, table.WhereClause), *key)
+		if err != nil {
+			return nil, fmt.Errorf("Error erasing %s: %s", table.TableName, err.Error())
+		}
+		numRows, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if numRows > 0 {
+			erasedRowCounts[table.TableName] = numRows
+		}
+	}
+	if err := useraccounts.ExpireSubscriptionForUser(tx, input.UserID); err != nil {
+		return nil, err
+	}
+	if err := users.AnonymizeUser(tx, input.UserID); err != nil {
+		return nil, err
+	}
+	erasedRowCountsJSON, err := json.Marshal(erasedRowCounts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(insertErasureTombstoneQuery, input.UserID, HashEmailAddress(user.EmailAddress), string(erasedRowCountsJSON)); err != nil {
+		return nil, err
+	}
+	if premiumNewsletterSubscription != nil {
+		c.Infof("Cancelling premium newsletter subscription for erased user %s", input.UserID)
+		if err := billing.InsertPremiumNewsletterSyncRequest(tx, *premiumNewsletterSubscription.ID, billing.PremiumNewsletterSubscriptionUpdateTypeCanceled); err != nil {
+			return nil, err
+		}
+		if err := billing.CancelPremiumNewsletterSubscriptionForUser(c, tx, input.UserID); err != nil {
+			return nil, err
+		}
+	}
+	if err := billing.AnonymizeStripeCustomerForUser(c, tx, input.UserID); err != nil {
+		return nil, err
+	}
+	return GetErasureTombstoneForUser(tx, input.UserID)
+}
+
+func GetErasureTombstoneForUser(tx *sqlx.Tx, userID users.UserID) (*ErasureTombstone, error) {
+	var matches []dbErasureTombstone
+	if err := tx.Select(&matches, getErasureTombstoneForUserQuery, userID); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most 1 erasure tombstone for user %s, but got %d", userID, len(matches))
+	default:
+		return matches[0].ToNonDB()
+	}
+}
@@ -0,0 +1,133 @@
+package userdata
+
+import (
+	"archive/zip"
+	"babblegraph/model/users"
+	"babblegraph/model/utm"
+	"babblegraph/util/ptr"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	exportRowsQuery = "SELECT row_to_json(t)::text FROM %s t WHERE %s"
+
+	exportArchiveUserFileName = "user.json"
+)
+
+type ExportUserDataInput struct {
+	UserID users.UserID
+	// Page hits are only tied to the browser that made them,
+	// so these are included when the request has a tracking ID
+	TrackingID *utm.TrackingID
+}
+
+func ExportUserData(tx *sqlx.Tx, input ExportUserDataInput) (*Export, error) {
+	user, err := users.GetUser(tx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	export := &Export{
+		UserID:    input.UserID,
+		CreatedAt: time.Now(),
+		User:      *user,
+		Tables:    make(map[string][]ExportRow),
+	}
+	for _, table := range userDataTables {
+		key := getKeyForTable(table, *user, input.TrackingID)
+		if key == nil {
+			continue
+		}
+		rows, err := getExportRowsForTable(tx, table, *key)
+		if err != nil {
+			return nil, fmt.Errorf("Error exporting %s: %s", table.TableName, err.Error())
+		}
+		if len(rows) > 0 {
+			export.Tables[table.TableName] = rows
+		}
+	}
+	return export, nil
+}
+
+func getExportRowsForTable(tx *sqlx.Tx, table userDataTable, key string) ([]ExportRow, error) {
+	var matches []string
+	if err := tx.Select(&matches, fmt.Sprintf(exportRowsQuery, table.TableName, table.WhereClause), key); err != nil {
+		return nil, err
+	}
+	var out []ExportRow
+	for _, m := range matches {
+		row, err := toExportRow([]byte(m), table.ExcludedExportFields)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// MakeZIPArchive writes the user to one file and each table to its own file
+func MakeZIPArchive(export Export) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	if err := writeJSONFileToArchive(w, exportArchiveUserFileName, struct {
+		UserID    users.UserID `json:"user_id"`
+		CreatedAt time.Time    `json:"created_at"`
+		User      users.User   `json:"user"`
+	}{
+		UserID:    export.UserID,
+		CreatedAt: export.CreatedAt,
+		User:      export.User,
+	}); err != nil {
+		return nil, err
+	}
+	var tableNames []string
+	for tableName := range export.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	for _, tableName := range tableNames {
+		if err := writeJSONFileToArchive(w, fmt.Sprintf("tables/%s.json", tableName), export.Tables[tableName]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSONFileToArchive(w *zip.Writer, fileName string, v interface{}) error {
+	f, err := w.Create(fileName)
+	if err != nil {
+		return err
+	}
+	contents, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(contents); err != nil {
+		return err
+	}
+	return nil
+}
+
+func getKeyForTable(table userDataTable, user users.User, trackingID *utm.TrackingID) *string {
+	switch table.Key {
+	case tableKeyUserID:
+		return ptr.String(string(user.ID))
+	case tableKeyEmailAddress:
+		return ptr.String(user.EmailAddress)
+	case tableKeyTrackingID:
+		if trackingID == nil {
+			return nil
+		}
+		return ptr.String(trackingID.Str())
+	default:
+		panic(fmt.Sprintf("unrecognized table key %s", table.Key))
+	}
+}
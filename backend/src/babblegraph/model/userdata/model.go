@@ -0,0 +1,71 @@
+package userdata
+
+import (
+	"babblegraph/model/users"
+	"babblegraph/util/encrypt"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type ExportRow map[string]interface{}
+
+type Export struct {
+	UserID    users.UserID `json:"user_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	User      users.User   `json:"user"`
+	// Tables with no rows for the user are left out
+	Tables map[string][]ExportRow `json:"tables"`
+}
+
+type ErasureTombstoneID string
+
+type dbErasureTombstone struct {
+	ID               ErasureTombstoneID `db:"_id"`
+	CreatedAt        time.Time          `db:"created_at"`
+	UserID           users.UserID       `db:"user_id"`
+	EmailAddressHash string             `db:"email_address_hash"`
+	ErasedRowCounts  []byte             `db:"erased_row_counts"`
+}
+
+func (d dbErasureTombstone) ToNonDB() (*ErasureTombstone, error) {
+	var erasedRowCounts map[string]int64
+	if err := json.Unmarshal(d.ErasedRowCounts, &erasedRowCounts); err != nil {
+		return nil, err
+	}
+	return &ErasureTombstone{
+		ID:               d.ID,
+		CreatedAt:        d.CreatedAt,
+		UserID:           d.UserID,
+		EmailAddressHash: d.EmailAddressHash,
+		ErasedRowCounts:  erasedRowCounts,
+	}, nil
+}
+
+type ErasureTombstone struct {
+	ID               ErasureTombstoneID
+	CreatedAt        time.Time
+	UserID           users.UserID
+	EmailAddressHash string
+	ErasedRowCounts  map[string]int64
+}
+
+const erasureTombstoneHashPurpose = "erasure-tombstone-email-address"
+
+// HashEmailAddress is used so that an erasure can be looked up by
+// email address without the tombstone keeping the address itself.
+// It's keyed so that the address can't be found by hashing a list of them.
+func HashEmailAddress(emailAddress string) string {
+	return encrypt.GetKeyedHash(erasureTombstoneHashPurpose, strings.ToLower(strings.TrimSpace(emailAddress)))
+}
+
+func toExportRow(rowJSON []byte, excludedFields []string) (ExportRow, error) {
+	var row ExportRow
+	if err := json.Unmarshal(rowJSON, &row); err != nil {
+		return nil, err
+	}
+	for _, field := range excludedFields {
+		delete(row, field)
+	}
+	return row, nil
+}
@@ -0,0 +1,102 @@
+package userdata
+
+import (
+	"archive/zip"
+	"babblegraph/model/users"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToExportRow(t *testing.T) {
+	row, err := toExportRow([]byte(`{"_id": "abc", "user_id": "123", "password_hash": "hash", "salt": "salt"}`), []string{"password_hash", "salt"})
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	if len(row) != 2 {
+		t.Errorf("Expected 2 fields, but got %d", len(row))
+	}
+	for _, field := range []string{"password_hash", "salt"} {
+		if _, ok := row[field]; ok {
+			t.Errorf("Expected %s to be excluded", field)
+		}
+	}
+	if row["user_id"] != "123" {
+		t.Errorf("Expected user_id to be 123, but got %v", row["user_id"])
+	}
+}
+
+func TestHashEmailAddress(t *testing.T) {
+	if os.Getenv("AES_KEY") == "" {
+		os.Setenv("AES_KEY", "ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE")
+		t.Cleanup(func() {
+			os.Unsetenv("AES_KEY")
+		})
+	}
+	expected := HashEmailAddress("someone@babblegraph.com")
+	for idx, emailAddress := range []string{
+		"someone@babblegraph.com",
+		"SomeOne@Babblegraph.com",
+		"  someone@babblegraph.com ",
+	} {
+		if result := HashEmailAddress(emailAddress); result != expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, expected, result)
+		}
+	}
+	if HashEmailAddress("someone-else@babblegraph.com") == expected {
+		t.Errorf("Expected different email addresses to have different hashes")
+	}
+}
+
+func TestMakeZIPArchive(t *testing.T) {
+	archive, err := MakeZIPArchive(Export{
+		UserID:    users.UserID("123"),
+		CreatedAt: time.Now(),
+		User: users.User{
+			ID:           users.UserID("123"),
+			EmailAddress: "someone@babblegraph.com",
+			Status:       users.UserStatusVerified,
+		},
+		Tables: map[string][]ExportRow{
+			"user_vocabulary_entries": {{"vocabulary_display": "hola"}},
+			"email_records":           {{"_id": "email-1"}, {"_id": "email-2"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("Got error %s", err.Error())
+	}
+	var fileNames []string
+	for _, f := range r.File {
+		fileNames = append(fileNames, f.Name)
+	}
+	expected := "user.json,tables/email_records.json,tables/user_vocabulary_entries.json"
+	if result := strings.Join(fileNames, ","); result != expected {
+		t.Errorf("Expected files %s, but got %s", expected, result)
+	}
+}
+
+func TestUserDataTables(t *testing.T) {
+	seenTableNames := make(map[string]bool)
+	for _, table := range userDataTables {
+		if seenTableNames[table.TableName] {
+			t.Errorf("Table %s appears more than once", table.TableName)
+		}
+		seenTableNames[table.TableName] = true
+		if !strings.Contains(table.WhereClause, "$1") {
+			t.Errorf("Expected where clause for %s to use its key", table.TableName)
+		}
+		switch table.ErasureAction {
+		case erasureActionDelete,
+			erasureActionRetain:
+			// no-op
+		default:
+			t.Errorf("Unrecognized erasure action %s for %s", table.ErasureAction, table.TableName)
+		}
+	}
+}
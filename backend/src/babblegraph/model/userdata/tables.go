@@ -0,0 +1,277 @@
+package userdata
+
+type tableKey string
+
+const (
+	tableKeyUserID       tableKey = "user-id"
+	tableKeyEmailAddress tableKey = "email-address"
+	tableKeyTrackingID   tableKey = "tracking-id"
+)
+
+type erasureAction string
+
+const (
+	erasureActionDelete erasureAction = "delete"
+	// Retained rows are kept for accounting and deliverability, and
+	// stop being identifying once the users row is anonymized
+	erasureActionRetain erasureAction = "retain"
+)
+
+type userDataTable struct {
+	TableName string
+	Key       tableKey
+	// This is a condition on the table's rows, where $1 is the key
+	WhereClause   string
+	ErasureAction erasureAction
+	// Credentials are never included in an export
+	ExcludedExportFields []string
+}
+
+// userDataTables is every table with data tied to a user. The order matters,
+// since rows are erased in this order and children must be removed
+// before the rows they reference.
+var userDataTables = []userDataTable{
+	{
+		TableName:     "user_newsletter_schedule_day_topic_mapping",
+		Key:           tableKeyUserID,
+		WhereClause:   "day_id IN (SELECT _id FROM user_newsletter_schedule_day_metadata WHERE user_id = $1)",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_newsletter_schedule_day_metadata",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_newsletter_schedule",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_newsletter_best_send_time",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_content_topic_mappings",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_readability_level",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_lemma_mappings",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_lemma_reinforcement_spotlight_records",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_lemma_reinforcement_spotlight_preferences",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_vocabulary_spotlight_records",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_vocabulary_entries",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_podcast_preferences",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_podcast_source_preferences",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_podcasts",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_documents",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_link_clicks",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "paywall_reports",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "newsletter_archive_entries",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "email_engagement_events",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "unsubscribe_reasons",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_verification_attempts",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:            "user_account_passwords",
+		Key:                  tableKeyUserID,
+		WhereClause:          "user_id = $1",
+		ErasureAction:        erasureActionDelete,
+		ExcludedExportFields: []string{"password_hash", "salt"},
+	}, {
+		TableName:     "user_forgot_password_attempts",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_account_notification_request_debounce_fulfillment_records",
+		Key:           tableKeyUserID,
+		WhereClause:   "notification_request_id IN (SELECT _id FROM user_account_notification_requests WHERE user_id = $1)",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_account_notification_requests",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_reader_tutorial_receipt",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_reengagement_statuses",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "experiments_user_variations",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:            "user_sessions",
+		Key:                  tableKeyUserID,
+		WhereClause:          "user_id = $1",
+		ErasureAction:        erasureActionDelete,
+		ExcludedExportFields: []string{"token_hash"},
+	}, {
+		TableName:     "billing_newsletter_subscription_trials",
+		Key:           tableKeyEmailAddress,
+		WhereClause:   "email_address = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "user_account_group_license_members",
+		Key:           tableKeyEmailAddress,
+		WhereClause:   "email_address = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "ses_transient_bounces",
+		Key:           tableKeyEmailAddress,
+		WhereClause:   "email_address = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "utm_page_hits",
+		Key:           tableKeyTrackingID,
+		WhereClause:   "tracking_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "blog_post_view",
+		Key:           tableKeyTrackingID,
+		WhereClause:   "tracking_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "utm_events",
+		Key:           tableKeyTrackingID,
+		WhereClause:   "tracking_id = $1",
+		ErasureAction: erasureActionDelete,
+	}, {
+		TableName:     "email_records",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "newsletter_send_requests",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "advertising_user_advertisements",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "user_account_subscription_levels",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "user_account_gift_codes",
+		Key:           tableKeyUserID,
+		WhereClause:   "redeemed_by_user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "user_account_group_licenses",
+		Key:           tableKeyUserID,
+		WhereClause:   "owner_user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "billing_information",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "billing_premium_newsletter_subscription",
+		Key:           tableKeyUserID,
+		WhereClause:   "billing_information_id IN (SELECT _id FROM billing_information WHERE user_id = $1)",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "billing_dunning_cycles",
+		Key:           tableKeyUserID,
+		WhereClause:   "premium_newsletter_subscription_id IN (SELECT s._id FROM billing_premium_newsletter_subscription s JOIN billing_information b ON b._id = s.billing_information_id WHERE b.user_id = $1)",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "billing_user_promotion",
+		Key:           tableKeyUserID,
+		WhereClause:   "user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "bgstripe_customer",
+		Key:           tableKeyUserID,
+		WhereClause:   "babblegraph_user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "bgstripe_subscription",
+		Key:           tableKeyUserID,
+		WhereClause:   "babblegraph_user_id = $1",
+		ErasureAction: erasureActionRetain,
+	}, {
+		TableName:     "bgstripe_payment_method",
+		Key:           tableKeyUserID,
+		WhereClause:   "babblegraph_user_id = $1",
+		ErasureAction: erasureActionRetain,
+	},
+}
@@ -26,7 +26,7 @@ func RegisterUserLinkClick(tx *sqlx.Tx, userID users.UserID, u urlparser.ParsedU
 	if _, err := tx.Exec(registerUserLinkClickQuery, userID, u.Domain, sourceID, u.URLIdentifier, emailRecordID, currentAccessMonth); err != nil {
 		return err
 	}
-	return nil
+	return email.RecordEngagementEvent(tx, emailRecordID, email.EngagementEventTypeClicked, email.EngagementSectionDocument.Ptr())
 }
 
 func GetDomainCountsByCurrentAccessMonthForUser(tx *sqlx.Tx, userID users.UserID) ([]UserDomainCount, error) {
@@ -2,11 +2,13 @@ package usernewsletterpreferences
 
 import (
 	"babblegraph/model/content"
+	"babblegraph/model/userreengagement"
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
 	"babblegraph/util/deref"
 	"babblegraph/util/timeutils"
 	"babblegraph/wordsmith"
+	"fmt"
 	"sort"
 	"time"
 
@@ -21,6 +23,35 @@ const (
 	defaultUTCSendTimeHour = 11
 )
 
+type NewsletterCadence string
+
+const (
+	// Daily cadence sends a newsletter on each of the user's active days
+	NewsletterCadenceDaily NewsletterCadence = "daily"
+	// Weekly digest cadence sends a single email once a week
+	// summarizing the week's best articles, vocabulary and podcasts
+	NewsletterCadenceWeeklyDigest NewsletterCadence = "weekly-digest"
+)
+
+func (n NewsletterCadence) Str() string {
+	return string(n)
+}
+
+func (n NewsletterCadence) Ptr() *NewsletterCadence {
+	return &n
+}
+
+func GetNewsletterCadenceFromString(s string) (*NewsletterCadence, error) {
+	switch NewsletterCadence(s) {
+	case NewsletterCadenceDaily:
+		return NewsletterCadenceDaily.Ptr(), nil
+	case NewsletterCadenceWeeklyDigest:
+		return NewsletterCadenceWeeklyDigest.Ptr(), nil
+	default:
+		return nil, fmt.Errorf("Unrecognized newsletter cadence %s", s)
+	}
+}
+
 type UserNewsletterPreferences struct {
 	UserID                                   users.UserID
 	LanguageCode                             wordsmith.LanguageCode
@@ -82,6 +113,10 @@ type dbUserNewsletterSchedule struct {
 	HourIndex                int                    `db:"hour_of_day_index"`
 	QuarterHourIndex         int                    `db:"quarter_hour_index"`
 	NumberOfArticlesPerEmail int                    `db:"number_of_articles_per_email"`
+	IsBestTimeEnabled        bool                   `db:"is_best_time_enabled"`
+	NewsletterCadence        NewsletterCadence      `db:"newsletter_cadence"`
+	// This is the day of the week in the user's timezone
+	WeeklyDigestDayOfWeekIndex int `db:"weekly_digest_day_of_week_index"`
 }
 
 type dayID string
@@ -97,6 +132,7 @@ type dbUserNewsletterDayMetadata struct {
 type Schedule interface {
 	IsSendRequested(utcWeekday time.Weekday) bool
 	GetNumberOfDocuments() int
+	GetCadence() NewsletterCadence
 	ConvertUTCTimeToUserDate(c ctx.LogContext, utcTime time.Time) (*time.Time, error)
 }
 
@@ -104,19 +140,38 @@ type ScheduleWithMetadata struct {
 	userScheduleDays    []dbUserNewsletterDayMetadata
 	utcHourIndex        int
 	utcQuarterHourIndex int
+	// These differ from HourIndex and QuarterHourIndex
+	// if the user has a learned best send time
+	sendHourIndex        int
+	sendQuarterHourIndex int
+	reengagementStage    userreengagement.Stage
+	// This is WeeklyDigestDayOfWeekIndex shifted to
+	// the UTC weekday on which the digest goes out
+	weeklyDigestUTCWeekday time.Weekday
 
-	NumberOfArticlesPerEmail int
-	IANATimezone             string
-	HourIndex                int
-	QuarterHourIndex         int
-	IsActiveForDay           []bool
+	NumberOfArticlesPerEmail   int
+	IANATimezone               string
+	HourIndex                  int
+	QuarterHourIndex           int
+	IsActiveForDay             []bool
+	IsBestTimeEnabled          bool
+	Cadence                    NewsletterCadence
+	WeeklyDigestDayOfWeekIndex int
 }
 
 func getUserNewsletterSchedule(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, languageCode wordsmith.LanguageCode, utcMidnight *time.Time) (*ScheduleWithMetadata, error) {
 	isActiveForDay := []bool{true, true, true, true, true, true, true}
 	var userScheduleDays []dbUserNewsletterDayMetadata
 	var ianaTimezone string
-	var utcHourIndex, utcQuarterHourIndex, hourIndex, quarterHourIndex, numberOfArticlesPerEmail int
+	var isBestTimeEnabled bool
+	cadence := NewsletterCadenceDaily
+	var weeklyDigestDayOfWeekIndex int
+	var weeklyDigestUTCWeekday time.Weekday
+	var utcHourIndex, utcQuarterHourIndex, hourIndex, quarterHourIndex, sendHourIndex, sendQuarterHourIndex, numberOfArticlesPerEmail int
+	reengagementStage, err := userreengagement.LookupStageForUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
 	userSchedule, err := lookupUserNewsletterScheduleForUser(tx, userID, languageCode)
 	switch {
 	case err != nil:
@@ -124,21 +179,43 @@ func getUserNewsletterSchedule(c ctx.LogContext, tx *sqlx.Tx, userID users.UserI
 	case userSchedule == nil:
 		ianaTimezone = "UTC"
 		utcHourIndex, utcQuarterHourIndex, hourIndex, quarterHourIndex = defaultUTCSendTimeHour, 0, defaultUTCSendTimeHour, 0
+		sendHourIndex, sendQuarterHourIndex = defaultUTCSendTimeHour, 0
 		numberOfArticlesPerEmail = defaultNumberOfArticles
 	default:
 		todayUTCMidnight := timeutils.ConvertToMidnight(deref.Time(utcMidnight, time.Now().UTC()))
-		userSendTime, err := resolveUTCMidnightWithNewsletterSchedule(c, todayUTCMidnight, *userSchedule)
+		hourIndex = userSchedule.HourIndex
+		quarterHourIndex = userSchedule.QuarterHourIndex
+		isBestTimeEnabled = userSchedule.IsBestTimeEnabled
+		scheduleForSend := *userSchedule
+		if isBestTimeEnabled {
+			bestSendTime, err := lookupBestSendTime(tx, userID, languageCode)
+			switch {
+			case err != nil:
+				return nil, err
+			case bestSendTime == nil:
+				c.Debugf("User %s has no best send time yet, using schedule", userID)
+			default:
+				scheduleForSend.HourIndex = bestSendTime.HourIndex
+				scheduleForSend.QuarterHourIndex = bestSendTime.QuarterHourIndex
+			}
+		}
+		userSendTime, err := resolveUTCMidnightWithNewsletterSchedule(c, todayUTCMidnight, scheduleForSend)
 		if err != nil {
 			return nil, err
 		}
 		c.Debugf("Input %+v, output %+v", todayUTCMidnight, userSendTime)
 		numberOfArticlesPerEmail = userSchedule.NumberOfArticlesPerEmail
 		ianaTimezone = userSchedule.IANATimezone
-		hourIndex = userSendTime.Hour()
-		quarterHourIndex = userSendTime.Minute() / 15
+		sendHourIndex = userSendTime.Hour()
+		sendQuarterHourIndex = userSendTime.Minute() / 15
 		userSendTimeUTC := userSendTime.UTC()
 		utcHourIndex = userSendTimeUTC.Hour()
 		utcQuarterHourIndex = userSendTimeUTC.Minute() / 15
+		offset := int(todayUTCMidnight.Weekday() - userSendTime.Weekday())
+		c.Debugf("Offset %d", offset)
+		cadence = userSchedule.NewsletterCadence
+		weeklyDigestDayOfWeekIndex = userSchedule.WeeklyDigestDayOfWeekIndex
+		weeklyDigestUTCWeekday = getWeeklyDigestUTCWeekday(weeklyDigestDayOfWeekIndex, offset)
 		userScheduleDays, err = lookupNewsletterDayMetadataForUser(tx, userID, languageCode)
 		switch {
 		case err != nil:
@@ -149,8 +226,6 @@ func getUserNewsletterSchedule(c ctx.LogContext, tx *sqlx.Tx, userID users.UserI
 			for _, d := range userScheduleDays {
 				isActiveForDay[d.DayOfWeekIndex] = d.IsActive
 			}
-			offset := int(todayUTCMidnight.Weekday() - userSendTime.Weekday())
-			c.Debugf("Offset %d", offset)
 			sort.SliceStable(userScheduleDays, func(i, j int) bool {
 				return (userScheduleDays[i].DayOfWeekIndex+offset)%7 < (userScheduleDays[j].DayOfWeekIndex+offset)%7
 			})
@@ -158,18 +233,52 @@ func getUserNewsletterSchedule(c ctx.LogContext, tx *sqlx.Tx, userID users.UserI
 		}
 	}
 	return &ScheduleWithMetadata{
-		NumberOfArticlesPerEmail: numberOfArticlesPerEmail,
-		userScheduleDays:         userScheduleDays,
-		utcHourIndex:             utcHourIndex,
-		utcQuarterHourIndex:      utcQuarterHourIndex,
-		IANATimezone:             ianaTimezone,
-		HourIndex:                hourIndex,
-		QuarterHourIndex:         quarterHourIndex,
-		IsActiveForDay:           isActiveForDay,
+		NumberOfArticlesPerEmail:   numberOfArticlesPerEmail,
+		userScheduleDays:           userScheduleDays,
+		utcHourIndex:               utcHourIndex,
+		utcQuarterHourIndex:        utcQuarterHourIndex,
+		sendHourIndex:              sendHourIndex,
+		sendQuarterHourIndex:       sendQuarterHourIndex,
+		reengagementStage:          reengagementStage,
+		weeklyDigestUTCWeekday:     weeklyDigestUTCWeekday,
+		IANATimezone:               ianaTimezone,
+		HourIndex:                  hourIndex,
+		QuarterHourIndex:           quarterHourIndex,
+		IsActiveForDay:             isActiveForDay,
+		IsBestTimeEnabled:          isBestTimeEnabled,
+		Cadence:                    cadence,
+		WeeklyDigestDayOfWeekIndex: weeklyDigestDayOfWeekIndex,
 	}, nil
 }
 
+// The offset is the difference between the UTC weekday and the user's
+// weekday at send time, which is -1, 0 or 1 depending on the timezone
+func getWeeklyDigestUTCWeekday(userDayOfWeekIndex, offset int) time.Weekday {
+	return time.Weekday(((userDayOfWeekIndex+offset)%7 + 7) % 7)
+}
+
 func (s *ScheduleWithMetadata) IsSendRequested(utcWeekday time.Weekday) bool {
+	switch {
+	case s.reengagementStage == userreengagement.StageSunset:
+		return false
+	case s.Cadence == NewsletterCadenceWeeklyDigest:
+		// The digest is already sent once a week, so
+		// reduced frequency doesn't change anything for it
+		return s.weeklyDigestUTCWeekday == utcWeekday
+	case s.reengagementStage == userreengagement.StageReducedFrequency:
+		// Chronically inactive users only get the first newsletter of their week
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if s.isScheduledForDay(d) {
+				return d == utcWeekday
+			}
+		}
+		return false
+	default:
+		return s.isScheduledForDay(utcWeekday)
+	}
+}
+
+func (s *ScheduleWithMetadata) isScheduledForDay(utcWeekday time.Weekday) bool {
 	return len(s.userScheduleDays) == 0 || s.userScheduleDays[int(utcWeekday)].IsActive
 }
 
@@ -177,10 +286,14 @@ func (s *ScheduleWithMetadata) GetNumberOfDocuments() int {
 	return s.NumberOfArticlesPerEmail
 }
 
+func (s *ScheduleWithMetadata) GetCadence() NewsletterCadence {
+	return s.Cadence
+}
+
 func (s *ScheduleWithMetadata) ConvertUTCTimeToUserDate(c ctx.LogContext, utcTime time.Time) (*time.Time, error) {
 	return resolveUTCMidnightWithNewsletterSchedule(c, timeutils.ConvertToMidnight(utcTime), dbUserNewsletterSchedule{
 		IANATimezone:     s.IANATimezone,
-		HourIndex:        s.HourIndex,
-		QuarterHourIndex: s.QuarterHourIndex,
+		HourIndex:        s.sendHourIndex,
+		QuarterHourIndex: s.sendQuarterHourIndex,
 	})
 }
@@ -0,0 +1,45 @@
+package usernewsletterpreferences
+
+import (
+	"babblegraph/model/userreengagement"
+	"testing"
+	"time"
+)
+
+func TestGetWeeklyDigestUTCWeekday(t *testing.T) {
+	type testCase struct {
+		userDayOfWeekIndex int
+		offset             int
+		expected           time.Weekday
+	}
+	for idx, tc := range []testCase{
+		{userDayOfWeekIndex: 3, offset: 0, expected: time.Wednesday},
+		// A user in Asia whose Monday morning is still Sunday in UTC
+		{userDayOfWeekIndex: 1, offset: -1, expected: time.Sunday},
+		{userDayOfWeekIndex: 0, offset: -1, expected: time.Saturday},
+		// A user in the Americas whose Saturday evening is already Sunday in UTC
+		{userDayOfWeekIndex: 6, offset: 1, expected: time.Sunday},
+	} {
+		result := getWeeklyDigestUTCWeekday(tc.userDayOfWeekIndex, tc.offset)
+		if result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestIsSendRequestedForWeeklyDigest(t *testing.T) {
+	schedule := &ScheduleWithMetadata{
+		reengagementStage:      userreengagement.StageReducedFrequency,
+		weeklyDigestUTCWeekday: time.Wednesday,
+		Cadence:                NewsletterCadenceWeeklyDigest,
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if isSendRequested := schedule.IsSendRequested(d); isSendRequested != (d == time.Wednesday) {
+			t.Errorf("Expected send requested on %s to be %t, but got %t", d, d == time.Wednesday, isSendRequested)
+		}
+	}
+	schedule.reengagementStage = userreengagement.StageSunset
+	if schedule.IsSendRequested(time.Wednesday) {
+		t.Errorf("Expected no weekly digest for sunset user")
+	}
+}
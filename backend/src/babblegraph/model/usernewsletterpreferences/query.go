@@ -2,6 +2,7 @@ package usernewsletterpreferences
 
 import (
 	"babblegraph/model/content"
+	"babblegraph/model/userreengagement"
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
 	"babblegraph/wordsmith"
@@ -94,6 +95,9 @@ type UpdateUserNewsletterPreferencesInput struct {
 	QuarterHourIndex                    int
 	NumberOfArticlesPerEmail            int
 	IsActiveForDays                     []bool
+	IsBestTimeEnabled                   bool
+	Cadence                             NewsletterCadence
+	WeeklyDigestDayOfWeekIndex          int
 }
 
 type PodcastPreferencesInput struct {
@@ -131,14 +135,22 @@ func UpdateUserNewsletterPreferences(c ctx.LogContext, tx *sqlx.Tx, input Update
 			return err
 		}
 	}
+	// Saving preferences means the user still wants their newsletter,
+	// so any reduced frequency or sunset from inactivity is lifted
+	if err := userreengagement.ReactivateUser(tx, input.UserID); err != nil {
+		return err
+	}
 	c.Debugf("Inserting schedule")
 	return upsertUserNewsletterSchedule(tx, upsertUserNewsletterScheduleInput{
-		UserID:                   input.UserID,
-		LanguageCode:             input.LanguageCode,
-		IANATimezone:             input.IANATimezone,
-		HourIndex:                input.HourIndex,
-		QuarterHourIndex:         input.QuarterHourIndex,
-		NumberOfArticlesPerEmail: input.NumberOfArticlesPerEmail,
+		UserID:                     input.UserID,
+		LanguageCode:               input.LanguageCode,
+		IANATimezone:               input.IANATimezone,
+		HourIndex:                  input.HourIndex,
+		QuarterHourIndex:           input.QuarterHourIndex,
+		NumberOfArticlesPerEmail:   input.NumberOfArticlesPerEmail,
+		IsBestTimeEnabled:          input.IsBestTimeEnabled,
+		Cadence:                    input.Cadence,
+		WeeklyDigestDayOfWeekIndex: input.WeeklyDigestDayOfWeekIndex,
 	})
 }
 
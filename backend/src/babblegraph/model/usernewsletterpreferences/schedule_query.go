@@ -34,9 +34,12 @@ const (
             iana_timezone,
             hour_of_day_index,
             quarter_hour_index,
-            number_of_articles_per_email
+            number_of_articles_per_email,
+            is_best_time_enabled,
+            newsletter_cadence,
+            weekly_digest_day_of_week_index
         ) VALUES (
-            $1, $2, $3, $4, $5, $6
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
         ) ON CONFLICT (
             user_id, language_code
         ) DO UPDATE
@@ -44,7 +47,10 @@ const (
             iana_timezone=$3,
             hour_of_day_index=$4,
             quarter_hour_index=$5,
-            number_of_articles_per_email=$6`
+            number_of_articles_per_email=$6,
+            is_best_time_enabled=$7,
+            newsletter_cadence=$8,
+            weekly_digest_day_of_week_index=$9`
 )
 
 func lookupNewsletterDayMetadataForUser(tx *sqlx.Tx, userID users.UserID, languageCode wordsmith.LanguageCode) ([]dbUserNewsletterDayMetadata, error) {
@@ -97,6 +103,10 @@ type upsertUserNewsletterScheduleInput struct {
 	HourIndex                int
 	QuarterHourIndex         int
 	NumberOfArticlesPerEmail int
+	IsBestTimeEnabled        bool
+	Cadence                  NewsletterCadence
+	// This is the day of the week in the user's timezone
+	WeeklyDigestDayOfWeekIndex int
 }
 
 func upsertUserNewsletterSchedule(tx *sqlx.Tx, input upsertUserNewsletterScheduleInput) error {
@@ -107,8 +117,14 @@ func upsertUserNewsletterSchedule(tx *sqlx.Tx, input upsertUserNewsletterSchedul
 		return fmt.Errorf("Quarter hour index should be between 0 and 3, but got %d", input.QuarterHourIndex)
 	case input.NumberOfArticlesPerEmail < minimumNumberOfArticles || input.NumberOfArticlesPerEmail > maximumNumberOfArticles:
 		return fmt.Errorf("Number of articles per email should be between %d and %d but got %d", minimumNumberOfArticles, maximumNumberOfArticles, input.NumberOfArticlesPerEmail)
+	case input.WeeklyDigestDayOfWeekIndex < 0 || input.WeeklyDigestDayOfWeekIndex > 6:
+		return fmt.Errorf("Weekly digest day of week should be between 0 and 6, but got %d", input.WeeklyDigestDayOfWeekIndex)
 	}
-	if _, err := tx.Exec(upsertNewsletterScheduleForUserQuery, input.UserID, input.LanguageCode, input.IANATimezone.String(), input.HourIndex, input.QuarterHourIndex, input.NumberOfArticlesPerEmail); err != nil {
+	cadence := input.Cadence
+	if cadence == "" {
+		cadence = NewsletterCadenceDaily
+	}
+	if _, err := tx.Exec(upsertNewsletterScheduleForUserQuery, input.UserID, input.LanguageCode, input.IANATimezone.String(), input.HourIndex, input.QuarterHourIndex, input.NumberOfArticlesPerEmail, input.IsBestTimeEnabled, cadence, input.WeeklyDigestDayOfWeekIndex); err != nil {
 		return err
 	}
 	return nil
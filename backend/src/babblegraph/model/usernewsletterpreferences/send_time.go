@@ -0,0 +1,169 @@
+package usernewsletterpreferences
+
+import (
+	"babblegraph/model/email"
+	"babblegraph/model/users"
+	"babblegraph/wordsmith"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+/*
+   Users who turn on "best time" get their newsletter shortly before
+   the window of the day in which they usually open emails and click links.
+
+   Engagements are bucketed by quarter hour in the user's own timezone,
+   since habits follow the user's local clock across daylight savings.
+   Users without enough engagements keep the time on their schedule.
+*/
+
+const (
+	quarterHoursPerDay = 24 * 4
+
+	bestSendTimeLookbackPeriod = 60 * 24 * time.Hour
+	// The window is the hour of the day with the most engagements
+	bestSendTimeWindowQuarterHours = 4
+	// Sending right at the start of the window would mean
+	// the email may not be at the top of the inbox yet
+	bestSendTimeLeadQuarterHours      = 2
+	minimumEngagementsForBestSendTime = 5
+
+	getEngagementTimesForUserQuery = `SELECT first_opened_at engaged_at FROM email_records
+        WHERE user_id = $1 AND type = $2 AND first_opened_at IS NOT NULL AND first_opened_at >= $3
+    UNION ALL
+    SELECT first_accessed_at engaged_at FROM user_link_clicks
+        WHERE user_id = $1 AND first_accessed_at >= $3`
+
+	getUsersWithBestTimeEnabledQuery = "SELECT * FROM user_newsletter_schedule WHERE is_best_time_enabled = TRUE"
+	lookupBestSendTimeQuery          = "SELECT * FROM user_newsletter_best_send_time WHERE user_id = $1 AND language_code = $2"
+	upsertBestSendTimeQuery          = `INSERT INTO
+        user_newsletter_best_send_time (
+            user_id,
+            language_code,
+            hour_of_day_index,
+            quarter_hour_index,
+            number_of_engagements
+        ) VALUES (
+            $1, $2, $3, $4, $5
+        ) ON CONFLICT (
+            user_id, language_code
+        ) DO UPDATE
+        SET
+            hour_of_day_index=$3,
+            quarter_hour_index=$4,
+            number_of_engagements=$5,
+            last_modified_at=timezone('utc', now())`
+	deleteBestSendTimeQuery = "DELETE FROM user_newsletter_best_send_time WHERE user_id = $1 AND language_code = $2"
+)
+
+type bestSendTimeID string
+
+type dbBestSendTime struct {
+	ID                  bestSendTimeID         `db:"_id"`
+	CreatedAt           time.Time              `db:"created_at"`
+	LastModifiedAt      time.Time              `db:"last_modified_at"`
+	UserID              users.UserID           `db:"user_id"`
+	LanguageCode        wordsmith.LanguageCode `db:"language_code"`
+	HourIndex           int                    `db:"hour_of_day_index"`
+	QuarterHourIndex    int                    `db:"quarter_hour_index"`
+	NumberOfEngagements int                    `db:"number_of_engagements"`
+}
+
+type UserWithBestTimeEnabled struct {
+	UserID       users.UserID
+	LanguageCode wordsmith.LanguageCode
+}
+
+func GetUsersWithBestTimeEnabled(tx *sqlx.Tx) ([]UserWithBestTimeEnabled, error) {
+	var matches []dbUserNewsletterSchedule
+	if err := tx.Select(&matches, getUsersWithBestTimeEnabledQuery); err != nil {
+		return nil, err
+	}
+	var out []UserWithBestTimeEnabled
+	for _, m := range matches {
+		out = append(out, UserWithBestTimeEnabled{
+			UserID:       m.UserID,
+			LanguageCode: m.LanguageCode,
+		})
+	}
+	return out, nil
+}
+
+// UpdateBestSendTimeForUser recomputes the best send time from the user's
+// recent engagement. It returns false if there wasn't enough engagement,
+// in which case any previous estimate is removed.
+func UpdateBestSendTimeForUser(tx *sqlx.Tx, userID users.UserID, languageCode wordsmith.LanguageCode, now time.Time) (bool, error) {
+	userSchedule, err := lookupUserNewsletterScheduleForUser(tx, userID, languageCode)
+	switch {
+	case err != nil:
+		return false, err
+	case userSchedule == nil:
+		return false, fmt.Errorf("User %s has no schedule for language %s", userID, languageCode)
+	}
+	userTimezone, err := time.LoadLocation(userSchedule.IANATimezone)
+	if err != nil {
+		return false, err
+	}
+	var engagementTimes []time.Time
+	if err := tx.Select(&engagementTimes, getEngagementTimesForUserQuery, userID, email.EmailTypeDaily, now.Add(-1*bestSendTimeLookbackPeriod)); err != nil {
+		return false, err
+	}
+	quarterHourIndex, ok := getBestSendQuarterHourOfDay(engagementTimes, userTimezone)
+	if !ok {
+		if _, err := tx.Exec(deleteBestSendTimeQuery, userID, languageCode); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	if _, err := tx.Exec(upsertBestSendTimeQuery, userID, languageCode, quarterHourIndex/4, quarterHourIndex%4, len(engagementTimes)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func lookupBestSendTime(tx *sqlx.Tx, userID users.UserID, languageCode wordsmith.LanguageCode) (*dbBestSendTime, error) {
+	var matches []dbBestSendTime
+	if err := tx.Select(&matches, lookupBestSendTimeQuery, userID, languageCode); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected to find at most one best send time, but got %d", len(matches))
+	default:
+		return &matches[0], nil
+	}
+}
+
+// getBestSendQuarterHourOfDay returns the quarter hour of the day (0-95) in the
+// user's timezone to send at so that the newsletter arrives just before the window
+// with the most engagements. Ties go to the earliest window of the day.
+func getBestSendQuarterHourOfDay(engagementTimes []time.Time, userTimezone *time.Location) (int, bool) {
+	if len(engagementTimes) < minimumEngagementsForBestSendTime {
+		return 0, false
+	}
+	var countsByQuarterHour [quarterHoursPerDay]int
+	for _, t := range engagementTimes {
+		local := t.In(userTimezone)
+		countsByQuarterHour[local.Hour()*4+local.Minute()/15]++
+	}
+	bestWindowStart, bestWindowCount := 0, -1
+	for start := 0; start < quarterHoursPerDay; start++ {
+		// Windows start at an engagement so that the
+		// send time is anchored to when the user actually reads
+		if countsByQuarterHour[start] == 0 {
+			continue
+		}
+		var count int
+		for offset := 0; offset < bestSendTimeWindowQuarterHours; offset++ {
+			count += countsByQuarterHour[(start+offset)%quarterHoursPerDay]
+		}
+		if count > bestWindowCount {
+			bestWindowStart, bestWindowCount = start, count
+		}
+	}
+	return (bestWindowStart - bestSendTimeLeadQuarterHours + quarterHoursPerDay) % quarterHoursPerDay, true
+}
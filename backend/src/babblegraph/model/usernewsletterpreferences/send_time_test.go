@@ -0,0 +1,55 @@
+package usernewsletterpreferences
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBestSendQuarterHourOfDay(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Error loading timezone: %s", err.Error())
+	}
+	makeTimes := func(loc *time.Location, hourMinutes ...[2]int) []time.Time {
+		var out []time.Time
+		for idx, hm := range hourMinutes {
+			out = append(out, time.Date(2021, time.March, 1+idx, hm[0], hm[1], 0, 0, loc))
+		}
+		return out
+	}
+	type testCase struct {
+		engagementTimes          []time.Time
+		expectedQuarterHourIndex int
+		expectedOK               bool
+	}
+	for idx, tc := range []testCase{
+		{
+			// Not enough engagement
+			engagementTimes: makeTimes(newYork, [2]int{8, 0}, [2]int{8, 10}),
+			expectedOK:      false,
+		}, {
+			// Mostly reads around 8am, so send at 7:30am
+			engagementTimes:          makeTimes(newYork, [2]int{8, 0}, [2]int{8, 10}, [2]int{8, 20}, [2]int{8, 40}, [2]int{21, 0}),
+			expectedQuarterHourIndex: 7*4 + 2,
+			expectedOK:               true,
+		}, {
+			// Engagement is bucketed in the user's timezone
+			engagementTimes:          makeTimes(time.UTC, [2]int{13, 0}, [2]int{13, 5}, [2]int{13, 14}, [2]int{13, 1}, [2]int{13, 2}),
+			expectedQuarterHourIndex: 7*4 + 2,
+			expectedOK:               true,
+		}, {
+			// Windows wrap around midnight
+			engagementTimes:          makeTimes(newYork, [2]int{0, 0}, [2]int{0, 5}, [2]int{0, 10}, [2]int{0, 1}, [2]int{0, 2}),
+			expectedQuarterHourIndex: 23*4 + 2,
+			expectedOK:               true,
+		},
+	} {
+		quarterHourIndex, ok := getBestSendQuarterHourOfDay(tc.engagementTimes, newYork)
+		switch {
+		case ok != tc.expectedOK:
+			t.Errorf("Error on test case %d: expected ok to be %t, but got %t", idx, tc.expectedOK, ok)
+		case ok && quarterHourIndex != tc.expectedQuarterHourIndex:
+			t.Errorf("Error on test case %d: expected quarter hour %d, but got %d", idx, tc.expectedQuarterHourIndex, quarterHourIndex)
+		}
+	}
+}
@@ -10,6 +10,8 @@ type TestNewsletterSchedule struct {
 	SendRequested     bool
 	UserSendTime      time.Time
 	NumberOfDocuments int
+	// Leaving this empty uses the daily cadence
+	Cadence NewsletterCadence
 }
 
 func (t TestNewsletterSchedule) IsSendRequested(utcWeekday time.Weekday) bool {
@@ -20,6 +22,13 @@ func (t TestNewsletterSchedule) GetNumberOfDocuments() int {
 	return t.NumberOfDocuments
 }
 
+func (t TestNewsletterSchedule) GetCadence() NewsletterCadence {
+	if t.Cadence == "" {
+		return NewsletterCadenceDaily
+	}
+	return t.Cadence
+}
+
 func (t TestNewsletterSchedule) ConvertUTCTimeToUserDate(c ctx.LogContext, utcTime time.Time) (*time.Time, error) {
 	return ptr.Time(t.UserSendTime), nil
 }
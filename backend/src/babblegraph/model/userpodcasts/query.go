@@ -15,7 +15,7 @@ const (
 	getByUserIDQuery = "SELECT * FROM user_podcasts WHERE user_id = $1"
 
 	insertUserPodcastsQuery    = "INSERT INTO user_podcasts (user_id, episode_id, source_id, email_record_id) VALUES ($1, $2, $3, $4) RETURNING _id"
-	registerOpenedPodcastQuery = "UPDATE user_podcasts SET first_opened_at = timezone('utc', now()) WHERE _id = $1 And first_opened_at IS NULL"
+	registerOpenedPodcastQuery = "UPDATE user_podcasts SET first_opened_at = COALESCE(first_opened_at, timezone('utc', now())) WHERE _id = $1 RETURNING email_record_id"
 )
 
 func GetByID(tx *sqlx.Tx, id ID) (*UserPodcast, error) {
@@ -61,8 +61,14 @@ func InsertUserPodcastAndReturnID(tx *sqlx.Tx, episodeID podcasts.EpisodeID, use
 }
 
 func RegisterOpenedPodcast(tx *sqlx.Tx, id ID) error {
-	if _, err := tx.Exec(registerOpenedPodcastQuery, id); err != nil {
+	var emailRecordIDs []email.ID
+	if err := tx.Select(&emailRecordIDs, registerOpenedPodcastQuery, id); err != nil {
 		return err
 	}
+	for _, emailRecordID := range emailRecordIDs {
+		if err := email.RecordEngagementEvent(tx, emailRecordID, email.EngagementEventTypeClicked, email.EngagementSectionPodcast.Ptr()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,50 @@
+package userreengagement
+
+import (
+	"babblegraph/model/users"
+	"time"
+)
+
+type Stage string
+
+const (
+	StageActive           Stage = "active"
+	StageDormant          Stage = "dormant"
+	StageReducedFrequency Stage = "reduced-frequency"
+	StageSunset           Stage = "sunset"
+)
+
+func (s Stage) Ptr() *Stage {
+	return &s
+}
+
+// Users move through these stages in order, one at a time,
+// and go straight back to active as soon as they engage again
+var orderedStages = []Stage{
+	StageActive,
+	StageDormant,
+	StageReducedFrequency,
+	StageSunset,
+}
+
+type statusID string
+
+type dbReengagementStatus struct {
+	ID             statusID     `db:"_id"`
+	CreatedAt      time.Time    `db:"created_at"`
+	LastModifiedAt time.Time    `db:"last_modified_at"`
+	UserID         users.UserID `db:"user_id"`
+	Stage          Stage        `db:"stage"`
+	StageChangedAt time.Time    `db:"stage_changed_at"`
+	ReactivatedAt  *time.Time   `db:"reactivated_at"`
+}
+
+type EngagementSummary struct {
+	UserID         users.UserID `db:"user_id"`
+	CurrentStage   *Stage       `db:"stage"`
+	StageChangedAt *time.Time   `db:"stage_changed_at"`
+	// These only count daily newsletters sent after the user's last
+	// open or click, so both are empty for a user who is engaged
+	NumberOfUnengagedEmails   int64      `db:"number_of_unengaged_emails"`
+	FirstUnengagedEmailSentAt *time.Time `db:"first_unengaged_email_sent_at"`
+}
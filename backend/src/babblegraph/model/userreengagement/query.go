@@ -0,0 +1,87 @@
+package userreengagement
+
+import (
+	"babblegraph/model/email"
+	"babblegraph/model/users"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	getEngagementSummariesForVerifiedUsersQuery = `WITH last_engagements AS (
+        SELECT user_id, MAX(engaged_at) AS last_engagement_at FROM (
+            SELECT user_id, first_opened_at AS engaged_at FROM email_records WHERE first_opened_at IS NOT NULL
+            UNION ALL
+            SELECT user_id, first_accessed_at AS engaged_at FROM user_link_clicks
+        ) e GROUP BY user_id
+    )
+    SELECT
+        u._id AS user_id,
+        s.stage,
+        s.stage_changed_at,
+        COUNT(r._id) AS number_of_unengaged_emails,
+        MIN(r.sent_at) AS first_unengaged_email_sent_at
+    FROM users u
+    LEFT JOIN last_engagements l ON l.user_id = u._id
+    LEFT JOIN user_reengagement_statuses s ON s.user_id = u._id
+    LEFT JOIN email_records r ON r.user_id = u._id
+        AND r.type = $2
+        AND r.sent_at IS NOT NULL
+        AND r.sent_at > COALESCE(GREATEST(l.last_engagement_at, s.reactivated_at), '-infinity'::timestamptz)
+    WHERE u.status = $1
+    GROUP BY u._id, s.stage, s.stage_changed_at`
+
+	lookupReengagementStatusForUserQuery = "SELECT * FROM user_reengagement_statuses WHERE user_id = $1"
+	upsertStageForUserQuery              = `INSERT INTO
+        user_reengagement_statuses (user_id, stage, stage_changed_at)
+        VALUES ($1, $2, timezone('utc', now()))
+    ON CONFLICT (user_id) DO UPDATE
+    SET stage = $2, stage_changed_at = timezone('utc', now()), last_modified_at = timezone('utc', now())`
+	reactivateUserQuery = `INSERT INTO
+        user_reengagement_statuses (user_id, stage, stage_changed_at, reactivated_at)
+        VALUES ($1, $2, timezone('utc', now()), timezone('utc', now()))
+    ON CONFLICT (user_id) DO UPDATE
+    SET stage = $2, stage_changed_at = timezone('utc', now()), reactivated_at = timezone('utc', now()), last_modified_at = timezone('utc', now())`
+)
+
+func GetEngagementSummariesForVerifiedUsers(tx *sqlx.Tx) ([]EngagementSummary, error) {
+	var matches []EngagementSummary
+	if err := tx.Select(&matches, getEngagementSummariesForVerifiedUsersQuery, users.UserStatusVerified, email.EmailTypeDaily); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func LookupStageForUser(tx *sqlx.Tx, userID users.UserID) (Stage, error) {
+	var matches []dbReengagementStatus
+	err := tx.Select(&matches, lookupReengagementStatusForUserQuery, userID)
+	switch {
+	case err != nil:
+		return "", err
+	case len(matches) > 1:
+		return "", fmt.Errorf("Expected at most one reengagement status for user %s, but got %d", userID, len(matches))
+	case len(matches) == 0:
+		return StageActive, nil
+	default:
+		return matches[0].Stage, nil
+	}
+}
+
+func UpdateStageForUser(tx *sqlx.Tx, userID users.UserID, stage Stage) error {
+	if _, err := tx.Exec(upsertStageForUserQuery, userID, stage); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReactivateUser should be called whenever a user does something
+// that shows they still want their newsletter, like updating
+// their preferences, so that they aren't treated as dormant
+// because of newsletters they ignored beforehand.
+func ReactivateUser(tx *sqlx.Tx, userID users.UserID) error {
+	if _, err := tx.Exec(reactivateUserQuery, userID, StageActive); err != nil {
+		return err
+	}
+	return nil
+}
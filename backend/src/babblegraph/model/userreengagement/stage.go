@@ -0,0 +1,59 @@
+package userreengagement
+
+import "time"
+
+const (
+	dormantAfter                           = 21 * 24 * time.Hour
+	minimumUnengagedEmailsForDormant       = 5
+	reducedFrequencyAfter                  = 45 * 24 * time.Hour
+	minimumUnengagedEmailsForReduced       = 10
+	sunsetAfter                            = 90 * 24 * time.Hour
+	minimumUnengagedEmailsForSunset        = 15
+	minimumTimeInStageBeforeNextEscalation = 14 * 24 * time.Hour
+)
+
+// GetNextStage escalates at most one stage at a time, and only after
+// the user has had some time to respond to the previous one.
+func GetNextStage(summary EngagementSummary, now time.Time) Stage {
+	currentStage := StageActive
+	if summary.CurrentStage != nil {
+		currentStage = *summary.CurrentStage
+	}
+	targetStage := StageActive
+	if summary.FirstUnengagedEmailSentAt != nil {
+		targetStage = getStageForDormancy(summary.NumberOfUnengagedEmails, now.Sub(*summary.FirstUnengagedEmailSentAt))
+	}
+	switch {
+	case targetStage == StageActive:
+		return StageActive
+	case getStageIndex(targetStage) <= getStageIndex(currentStage):
+		return currentStage
+	case currentStage != StageActive && summary.StageChangedAt != nil && now.Sub(*summary.StageChangedAt) < minimumTimeInStageBeforeNextEscalation:
+		return currentStage
+	default:
+		return orderedStages[getStageIndex(currentStage)+1]
+	}
+}
+
+func getStageForDormancy(numberOfUnengagedEmails int64, dormantFor time.Duration) Stage {
+	switch {
+	case dormantFor >= sunsetAfter && numberOfUnengagedEmails >= minimumUnengagedEmailsForSunset:
+		return StageSunset
+	case dormantFor >= reducedFrequencyAfter && numberOfUnengagedEmails >= minimumUnengagedEmailsForReduced:
+		return StageReducedFrequency
+	case dormantFor >= dormantAfter && numberOfUnengagedEmails >= minimumUnengagedEmailsForDormant:
+		return StageDormant
+	default:
+		return StageActive
+	}
+}
+
+func getStageIndex(stage Stage) int {
+	for idx, s := range orderedStages {
+		if s == stage {
+			return idx
+		}
+	}
+	// Unknown stages are treated as active
+	return 0
+}
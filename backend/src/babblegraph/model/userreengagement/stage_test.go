@@ -0,0 +1,110 @@
+package userreengagement
+
+import (
+	"babblegraph/util/ptr"
+	"testing"
+	"time"
+)
+
+func TestGetNextStage(t *testing.T) {
+	now := time.Date(2021, time.November, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		return ptr.Time(now.Add(-time.Duration(days) * 24 * time.Hour))
+	}
+	testCases := []struct {
+		description   string
+		summary       EngagementSummary
+		expectedStage Stage
+	}{
+		{
+			description:   "engaged user with no status",
+			summary:       EngagementSummary{},
+			expectedStage: StageActive,
+		}, {
+			description: "not dormant for long enough",
+			summary: EngagementSummary{
+				NumberOfUnengagedEmails:   10,
+				FirstUnengagedEmailSentAt: daysAgo(14),
+			},
+			expectedStage: StageActive,
+		}, {
+			description: "not enough unengaged emails",
+			summary: EngagementSummary{
+				NumberOfUnengagedEmails:   3,
+				FirstUnengagedEmailSentAt: daysAgo(30),
+			},
+			expectedStage: StageActive,
+		}, {
+			description: "becomes dormant",
+			summary: EngagementSummary{
+				NumberOfUnengagedEmails:   21,
+				FirstUnengagedEmailSentAt: daysAgo(21),
+			},
+			expectedStage: StageDormant,
+		}, {
+			description: "only escalates one stage at a time",
+			summary: EngagementSummary{
+				NumberOfUnengagedEmails:   100,
+				FirstUnengagedEmailSentAt: daysAgo(100),
+			},
+			expectedStage: StageDormant,
+		}, {
+			description: "waits before escalating again",
+			summary: EngagementSummary{
+				CurrentStage:              StageDormant.Ptr(),
+				StageChangedAt:            daysAgo(7),
+				NumberOfUnengagedEmails:   50,
+				FirstUnengagedEmailSentAt: daysAgo(50),
+			},
+			expectedStage: StageDormant,
+		}, {
+			description: "reduces frequency",
+			summary: EngagementSummary{
+				CurrentStage:              StageDormant.Ptr(),
+				StageChangedAt:            daysAgo(20),
+				NumberOfUnengagedEmails:   45,
+				FirstUnengagedEmailSentAt: daysAgo(45),
+			},
+			expectedStage: StageReducedFrequency,
+		}, {
+			description: "stays at reduced frequency before sunset threshold",
+			summary: EngagementSummary{
+				CurrentStage:              StageReducedFrequency.Ptr(),
+				StageChangedAt:            daysAgo(30),
+				NumberOfUnengagedEmails:   14,
+				FirstUnengagedEmailSentAt: daysAgo(100),
+			},
+			expectedStage: StageReducedFrequency,
+		}, {
+			description: "sunsets",
+			summary: EngagementSummary{
+				CurrentStage:              StageReducedFrequency.Ptr(),
+				StageChangedAt:            daysAgo(45),
+				NumberOfUnengagedEmails:   52,
+				FirstUnengagedEmailSentAt: daysAgo(90),
+			},
+			expectedStage: StageSunset,
+		}, {
+			description: "sunset users stay sunset",
+			summary: EngagementSummary{
+				CurrentStage:              StageSunset.Ptr(),
+				StageChangedAt:            daysAgo(200),
+				NumberOfUnengagedEmails:   52,
+				FirstUnengagedEmailSentAt: daysAgo(300),
+			},
+			expectedStage: StageSunset,
+		}, {
+			description: "engaging again resets the stage",
+			summary: EngagementSummary{
+				CurrentStage:   StageSunset.Ptr(),
+				StageChangedAt: daysAgo(2),
+			},
+			expectedStage: StageActive,
+		},
+	}
+	for _, tc := range testCases {
+		if stage := GetNextStage(tc.summary, now); stage != tc.expectedStage {
+			t.Errorf("Error on test %s: expected stage %s, but got %s", tc.description, tc.expectedStage, stage)
+		}
+	}
+}
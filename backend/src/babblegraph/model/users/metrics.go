@@ -65,7 +65,8 @@ func getVerifiedNetChangeOverPeriod(tx *sqlx.Tx, period string) (*decimal.Number
 			netChange = netChange.Add(value)
 		case UserStatusUnsubscribed,
 			UserStatusBlocklistComplaint,
-			UserStatusBlocklistBounced:
+			UserStatusBlocklistBounced,
+			UserStatusErased:
 			netChange = netChange.Subtract(value)
 		case UserStatusUnverified:
 			// no-op
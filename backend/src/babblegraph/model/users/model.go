@@ -12,6 +12,9 @@ const (
 	UserStatusUnsubscribed       UserStatus = "unsubscribed"
 	UserStatusBlocklistBounced   UserStatus = "blocklist-bounced"
 	UserStatusBlocklistComplaint UserStatus = "blocklist-complaint"
+	// Erased users have had their data removed at their request,
+	// and their email address replaced with a placeholder
+	UserStatusErased UserStatus = "erased"
 )
 
 type dbUser struct {
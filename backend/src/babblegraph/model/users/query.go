@@ -19,6 +19,10 @@ const (
 	lookupUserQuery               = "SELECT * FROM users WHERE _id = $1"
 
 	insertUnverifiedUserQuery = "INSERT INTO users (email_address, status) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+
+	anonymizeUserQuery = "UPDATE users SET email_address = $1, status = $2, last_modified_at = timezone('utc', now()) WHERE _id = $3"
+
+	erasedUserEmailAddressDomain = "erased.babblegraph.com"
 )
 
 func GetAllActiveUsers(tx *sqlx.Tx) ([]User, error) {
@@ -65,7 +69,8 @@ func AddUserToBlocklistByEmailAddress(tx *sqlx.Tx, emailAddress string, newStatu
 		// no-op
 	case UserStatusVerified,
 		UserStatusUnverified,
-		UserStatusUnsubscribed:
+		UserStatusUnsubscribed,
+		UserStatusErased:
 		return false, fmt.Errorf("User status %s not a blocklist", newStatus)
 	default:
 		return false, fmt.Errorf("Unrecognized status %s", newStatus)
@@ -120,3 +125,16 @@ func UnsubscribeUserByID(tx *sqlx.Tx, id UserID) error {
 	_, err := tx.Exec(updateUserStatusByID, UserStatusUnsubscribed, id)
 	return err
 }
+
+// AnonymizeUser replaces the user's email address with a placeholder
+// that is unique to the user, so that the address can sign up again
+func AnonymizeUser(tx *sqlx.Tx, id UserID) error {
+	if _, err := tx.Exec(anonymizeUserQuery, getErasedUserEmailAddress(id), UserStatusErased, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func getErasedUserEmailAddress(id UserID) string {
+	return fmt.Sprintf("erased-%s@%s", id, erasedUserEmailAddressDomain)
+}
@@ -0,0 +1,111 @@
+package usersessions
+
+import (
+	"babblegraph/model/users"
+	"strings"
+	"time"
+)
+
+const (
+	// Sessions slide forward by this much every time they are used,
+	// so an active user stays logged in...
+	SessionIdleTimeout = 3 * 24 * time.Hour
+	// ...but never for longer than this without logging in again
+	maximumSessionLifetime = 30 * 24 * time.Hour
+
+	// This keeps every authenticated request from writing to the sessions table
+	lastSeenUpdateInterval = 5 * time.Minute
+)
+
+type ID string
+
+func (i ID) Ptr() *ID {
+	return &i
+}
+
+type Device string
+
+const (
+	DeviceDesktop Device = "desktop"
+	DeviceMobile  Device = "mobile"
+	DeviceTablet  Device = "tablet"
+	DeviceUnknown Device = "unknown"
+)
+
+func (d Device) Str() string {
+	return string(d)
+}
+
+type dbSession struct {
+	ID             ID           `db:"_id"`
+	CreatedAt      time.Time    `db:"created_at"`
+	LastModifiedAt time.Time    `db:"last_modified_at"`
+	UserID         users.UserID `db:"user_id"`
+	TokenHash      string       `db:"token_hash"`
+	Device         Device       `db:"device"`
+	IPAddress      *string      `db:"ip_address"`
+	UserAgent      *string      `db:"user_agent"`
+	LastSeenAt     time.Time    `db:"last_seen_at"`
+	ExpiresAt      time.Time    `db:"expires_at"`
+	RevokedAt      *time.Time   `db:"revoked_at"`
+}
+
+func (d dbSession) ToNonDB() Session {
+	return Session{
+		ID:         d.ID,
+		UserID:     d.UserID,
+		CreatedAt:  d.CreatedAt,
+		Device:     d.Device,
+		IPAddress:  d.IPAddress,
+		UserAgent:  d.UserAgent,
+		LastSeenAt: d.LastSeenAt,
+		ExpiresAt:  d.ExpiresAt,
+	}
+}
+
+func (d dbSession) isActive(now time.Time) bool {
+	return d.RevokedAt == nil && now.Before(d.ExpiresAt)
+}
+
+type Session struct {
+	ID         ID
+	UserID     users.UserID
+	CreatedAt  time.Time
+	Device     Device
+	IPAddress  *string
+	UserAgent  *string
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+type SessionMetadata struct {
+	IPAddress string
+	UserAgent string
+}
+
+func getSessionExpirationTime(createdAt, now time.Time) time.Time {
+	expiresAt := now.Add(SessionIdleTimeout)
+	if maximumExpiresAt := createdAt.Add(maximumSessionLifetime); expiresAt.After(maximumExpiresAt) {
+		return maximumExpiresAt
+	}
+	return expiresAt
+}
+
+// This is only meant to give users a hint
+// when they are looking at their list of sessions
+func getDeviceForUserAgent(userAgent string) Device {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case len(ua) == 0:
+		return DeviceUnknown
+	case strings.Contains(ua, "ipad"),
+		strings.Contains(ua, "tablet"):
+		return DeviceTablet
+	case strings.Contains(ua, "mobi"),
+		strings.Contains(ua, "iphone"),
+		strings.Contains(ua, "android"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
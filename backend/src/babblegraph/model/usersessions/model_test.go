@@ -0,0 +1,60 @@
+package usersessions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetSessionExpirationTime(t *testing.T) {
+	createdAt := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	type testCase struct {
+		now      time.Time
+		expected time.Time
+	}
+	for idx, tc := range []testCase{
+		{now: createdAt, expected: createdAt.Add(SessionIdleTimeout)},
+		{now: createdAt.Add(10 * 24 * time.Hour), expected: createdAt.Add(13 * 24 * time.Hour)},
+		// Sliding expiration stops at the maximum session lifetime
+		{now: createdAt.Add(29 * 24 * time.Hour), expected: createdAt.Add(maximumSessionLifetime)},
+	} {
+		result := getSessionExpirationTime(createdAt, tc.now)
+		if !result.Equal(tc.expected) {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestGetDeviceForUserAgent(t *testing.T) {
+	type testCase struct {
+		userAgent string
+		expected  Device
+	}
+	for idx, tc := range []testCase{
+		{userAgent: "", expected: DeviceUnknown},
+		{userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0.3 Safari/605.1.15", expected: DeviceDesktop},
+		{userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 14_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Mobile/15E148 Safari/604.1", expected: DeviceMobile},
+		{userAgent: "Mozilla/5.0 (Linux; Android 11; Pixel 5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/89.0.4389.72 Mobile Safari/537.36", expected: DeviceMobile},
+		{userAgent: "Mozilla/5.0 (iPad; CPU OS 14_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.0 Mobile/15E148 Safari/604.1", expected: DeviceTablet},
+	} {
+		if result := getDeviceForUserAgent(tc.userAgent); result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
+
+func TestSessionIsActive(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+	for idx, tc := range []struct {
+		session  dbSession
+		expected bool
+	}{
+		{session: dbSession{ExpiresAt: now.Add(time.Hour)}, expected: true},
+		{session: dbSession{ExpiresAt: now.Add(-time.Hour)}, expected: false},
+		{session: dbSession{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}, expected: false},
+	} {
+		if result := tc.session.isActive(now); result != tc.expected {
+			t.Errorf("Error on test case %d: expected %t, but got %t", idx, tc.expected, result)
+		}
+	}
+}
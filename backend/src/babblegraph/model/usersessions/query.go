@@ -0,0 +1,165 @@
+package usersessions
+
+import (
+	"babblegraph/model/users"
+	"babblegraph/util/ptr"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	sessionTokenByteLength = 32
+
+	createSessionQuery = `INSERT INTO
+        user_sessions (user_id, token_hash, device, ip_address, user_agent, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6)`
+	lookupSessionForTokenHashQuery = "SELECT * FROM user_sessions WHERE token_hash = $1"
+	refreshSessionQuery            = `UPDATE user_sessions SET
+        last_seen_at = timezone('utc', now()),
+        last_modified_at = timezone('utc', now()),
+        ip_address = $2,
+        expires_at = $3
+    WHERE _id = $1`
+	getActiveSessionsForUserQuery = `SELECT * FROM user_sessions
+        WHERE user_id = $1
+        AND revoked_at IS NULL
+        AND expires_at > timezone('utc', now())
+        ORDER BY last_seen_at DESC`
+
+	revokeSessionForUserQuery      = "UPDATE user_sessions SET revoked_at = timezone('utc', now()), last_modified_at = timezone('utc', now()) WHERE user_id = $1 AND _id = $2 AND revoked_at IS NULL"
+	revokeSessionForTokenHashQuery = "UPDATE user_sessions SET revoked_at = timezone('utc', now()), last_modified_at = timezone('utc', now()) WHERE token_hash = $1 AND revoked_at IS NULL"
+	revokeAllSessionsForUserQuery  = "UPDATE user_sessions SET revoked_at = timezone('utc', now()), last_modified_at = timezone('utc', now()) WHERE user_id = $1 AND revoked_at IS NULL"
+	deleteInactiveSessionsQuery    = "DELETE FROM user_sessions WHERE expires_at < $1 OR revoked_at < $1"
+)
+
+// CreateSession returns the token for the new session, which
+// is the only time it is available since only its hash is stored
+func CreateSession(tx *sqlx.Tx, userID users.UserID, metadata SessionMetadata) (_token *string, _expiresAt *time.Time, _err error) {
+	tokenBytes := make([]byte, sessionTokenByteLength)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+	now := time.Now()
+	expiresAt := getSessionExpirationTime(now, now)
+	if _, err := tx.Exec(createSessionQuery, userID, hashSessionToken(token), getDeviceForUserAgent(metadata.UserAgent), ptr.String(metadata.IPAddress), ptr.String(metadata.UserAgent), expiresAt); err != nil {
+		return nil, nil, err
+	}
+	return &token, &expiresAt, nil
+}
+
+type ActiveSession struct {
+	ID        ID
+	UserID    users.UserID
+	ExpiresAt time.Time
+	// If this is true, the session cookie should be
+	// reissued so that its expiration matches the session
+	WasExtended bool
+}
+
+// LookupAndRefreshSessionForToken returns nil if the token does not
+// belong to a session or if that session is expired or revoked
+func LookupAndRefreshSessionForToken(tx *sqlx.Tx, token string, metadata SessionMetadata) (*ActiveSession, error) {
+	var matches []dbSession
+	err := tx.Select(&matches, lookupSessionForTokenHashQuery, hashSessionToken(token))
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) == 0:
+		return nil, nil
+	case len(matches) > 1:
+		return nil, fmt.Errorf("Expected at most one session for token, but got %d", len(matches))
+	}
+	session := matches[0]
+	now := time.Now()
+	if !session.isActive(now) {
+		return nil, nil
+	}
+	out := &ActiveSession{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if now.Sub(session.LastSeenAt) < lastSeenUpdateInterval {
+		return out, nil
+	}
+	expiresAt := getSessionExpirationTime(session.CreatedAt, now)
+	if _, err := tx.Exec(refreshSessionQuery, session.ID, ptr.String(metadata.IPAddress), expiresAt); err != nil {
+		return nil, err
+	}
+	out.ExpiresAt = expiresAt
+	out.WasExtended = true
+	return out, nil
+}
+
+// LookupUserIDForActiveSessionToken is like LookupAndRefreshSessionForToken,
+// but it does not count as the session being used
+func LookupUserIDForActiveSessionToken(tx *sqlx.Tx, token string) (*users.UserID, error) {
+	var matches []dbSession
+	err := tx.Select(&matches, lookupSessionForTokenHashQuery, hashSessionToken(token))
+	switch {
+	case err != nil:
+		return nil, err
+	case len(matches) != 1,
+		!matches[0].isActive(time.Now()):
+		return nil, nil
+	default:
+		return &matches[0].UserID, nil
+	}
+}
+
+func GetActiveSessionsForUser(tx *sqlx.Tx, userID users.UserID) ([]Session, error) {
+	var matches []dbSession
+	if err := tx.Select(&matches, getActiveSessionsForUserQuery, userID); err != nil {
+		return nil, err
+	}
+	var out []Session
+	for _, m := range matches {
+		out = append(out, m.ToNonDB())
+	}
+	return out, nil
+}
+
+func RevokeSessionForUser(tx *sqlx.Tx, userID users.UserID, sessionID ID) (_didRevoke bool, _err error) {
+	res, err := tx.Exec(revokeSessionForUserQuery, userID, sessionID)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return numRows > 0, nil
+}
+
+func RevokeSessionForToken(tx *sqlx.Tx, token string) error {
+	if _, err := tx.Exec(revokeSessionForTokenHashQuery, hashSessionToken(token)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RevokeAllSessionsForUser(tx *sqlx.Tx, userID users.UserID) error {
+	if _, err := tx.Exec(revokeAllSessionsForUserQuery, userID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func RemoveSessionsInactiveSince(tx *sqlx.Tx, t time.Time) error {
+	if _, err := tx.Exec(deleteInactiveSessionsQuery, t); err != nil {
+		return err
+	}
+	return nil
+}
+
+func hashSessionToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -48,7 +48,7 @@ func GetVirtualFileURL(id string, t Type) (*string, error) {
 func GetObjectIDAndType(virtualFileKey string) (*string, *Type, error) {
 	var objectID *string
 	var t *Type
-	if err := encrypt.WithDecodedToken(virtualFileKey, func(token encrypt.TokenPair) error {
+	if err := encrypt.WithDecodedTokenForKeys(virtualFileKey, []string{TypePodcast.Str(), TypePodcastImage.Str()}, func(token encrypt.TokenPair) error {
 		var err error
 		t, err = typeFromString(token.Key)
 		if err != nil {
@@ -5,8 +5,8 @@ import (
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
 	"babblegraph/util/elastic"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/env"
-	"babblegraph/util/ses"
 	"babblegraph/wordsmith"
 	"flag"
 	"fmt"
@@ -25,6 +25,7 @@ func main() {
         create-elastic-indexes: create new indices in ElasticSearch
         migrate-legacy-users: migrates all old users onto a legacy subscription
         expiration-dry-run: does a dry run of user account expiration
+        train-topic-classifier: trains and evaluates the topic classifier for Spanish documents
         create-admin: create admin`)
 	userEmail := flag.String("user-email", "none", "Email address of user to create")
 	flag.Parse()
@@ -37,12 +38,7 @@ func main() {
 	}); err != nil {
 		log.Fatal(err.Error())
 	}
-	emailClient := ses.NewClient(ses.NewClientInput{
-		AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
-		AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
-		AWSRegion:          "us-east-1",
-		FromAddress:        env.MustEnvironmentVariable("EMAIL_ADDRESS"),
-	})
+	emailClient := emailsender.NewEmailSenderForEnvironment()
 	defer sentry.Flush(2 * time.Second)
 	switch *taskName {
 	case "goodbye":
@@ -75,6 +71,10 @@ func main() {
 		if err := tasks.SubscriptionExpirationDryRun(ctx.GetDefaultLogContext()); err != nil {
 			log.Fatal(err.Error())
 		}
+	case "train-topic-classifier":
+		if err := tasks.TrainTopicClassifier(ctx.GetDefaultLogContext(), wordsmith.LanguageCodeSpanish); err != nil {
+			log.Fatal(err.Error())
+		}
 	default:
 		log.Fatal(fmt.Sprintf("Invalid task specified %s", *taskName))
 	}
@@ -7,13 +7,13 @@ import (
 	"babblegraph/model/users"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/ptr"
-	"babblegraph/util/ses"
 
 	"github.com/jmoiron/sqlx"
 )
 
-func SendGoodbyeEmail(cl *ses.Client) error {
+func SendGoodbyeEmail(cl emailsender.EmailSender) error {
 	var u []users.User
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		var err error
@@ -22,7 +22,7 @@ func SendGoodbyeEmail(cl *ses.Client) error {
 	}); err != nil {
 		return err
 	}
-	defaultInput := emailtemplates.MakeGenericUserEmailHTMLInput{
+	defaultInput := emailtemplates.MakeGenericUserEmailInput{
 		EmailTitle:    "Babblegraph is shutting down at the end of the month",
 		PreheaderText: "This email is to let you know that we’re shutting down.",
 		BeforeParagraphs: []string{
@@ -57,7 +57,7 @@ func SendGoodbyeEmail(cl *ses.Client) error {
 			}
 			defaultInput.UserAccessor = emailAccessor
 			defaultInput.EmailRecordID = emailRecordID
-			body, err := emailtemplates.MakeGenericUserEmailHTML(defaultInput)
+			renderedEmail, err := emailtemplates.MakeGenericUserEmail(defaultInput)
 			if err != nil {
 				return err
 			}
@@ -65,7 +65,8 @@ func SendGoodbyeEmail(cl *ses.Client) error {
 				ID:              emailRecordID,
 				EmailAddress:    user.EmailAddress,
 				Subject:         "So long!",
-				Body:            *body,
+				Body:            renderedEmail.HTMLBody,
+				TextBody:        renderedEmail.TextBody,
 				EmailSenderName: ptr.String("Andrew from Babblegraph"),
 			})
 		}); err != nil {
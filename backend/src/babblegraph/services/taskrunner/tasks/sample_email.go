@@ -10,10 +10,10 @@ import (
 	"babblegraph/model/uservocabulary"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/env"
 	"babblegraph/util/ptr"
 	"babblegraph/util/random"
-	"babblegraph/util/ses"
 	"babblegraph/util/timeutils"
 	"babblegraph/wordsmith"
 	"fmt"
@@ -22,7 +22,7 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-func SendSampleNewsletter(cl *ses.Client, emailAddress string) error {
+func SendSampleNewsletter(cl emailsender.EmailSender, emailAddress string) error {
 	c := ctx.GetDefaultLogContext()
 	switch env.MustEnvironmentName() {
 	case env.EnvironmentLocal,
@@ -99,12 +99,12 @@ func createNewsletter(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, emailR
 	})
 }
 
-func createNewsletterHTMLAndSend(emailClient *ses.Client, tx *sqlx.Tx, emailAddress string, userID users.UserID, emailRecordID email.ID, newsletterBody newsletter.NewsletterVersion2Body) error {
+func createNewsletterHTMLAndSend(emailClient emailsender.EmailSender, tx *sqlx.Tx, emailAddress string, userID users.UserID, emailRecordID email.ID, newsletterBody newsletter.NewsletterVersion2Body) error {
 	userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, userID)
 	if err != nil {
 		return err
 	}
-	newsletterHTML, err := emailtemplates.MakeNewsletterVersion2HTML(emailtemplates.MakeNewsletterVersion2HTMLInput{
+	renderedNewsletter, err := emailtemplates.MakeNewsletterVersion2(emailtemplates.MakeNewsletterVersion2Input{
 		EmailRecordID: emailRecordID,
 		UserAccessor:  userAccessor,
 		Body:          newsletterBody,
@@ -117,6 +117,7 @@ func createNewsletterHTMLAndSend(emailClient *ses.Client, tx *sqlx.Tx, emailAddr
 		EmailAddress:    emailAddress,
 		Subject:         fmt.Sprintf("Sample Newsletter - %s", random.MustMakeRandomString(5)),
 		EmailSenderName: ptr.String("Babblegraph Sample Email"),
-		Body:            *newsletterHTML,
+		Body:            renderedNewsletter.HTMLBody,
+		TextBody:        renderedNewsletter.TextBody,
 	})
 }
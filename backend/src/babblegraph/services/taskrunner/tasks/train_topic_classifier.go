@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"babblegraph/model/content"
+	"babblegraph/model/documents"
+	"babblegraph/model/topicclassifier"
+	"babblegraph/util/ctx"
+	"babblegraph/util/database"
+	"babblegraph/wordsmith"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TrainTopicClassifier(c ctx.LogContext, languageCode wordsmith.LanguageCode) error {
+	docs, err := documents.GetDocumentsForTopicClassifierTraining(languageCode)
+	if err != nil {
+		return fmt.Errorf("Error getting training documents: %s", err.Error())
+	}
+	var examples []topicclassifier.TrainingExample
+	for _, doc := range docs {
+		if doc.LemmatizedDescription == nil || len(doc.TopicIDs) == 0 {
+			continue
+		}
+		examples = append(examples, topicclassifier.TrainingExample{
+			ID:       doc.ID.Str(),
+			Tokens:   strings.Fields(*doc.LemmatizedDescription),
+			TopicIDs: doc.TopicIDs,
+		})
+	}
+	c.Infof("Training topic classifier on %d documents", len(examples))
+	classifier, evaluation := topicclassifier.TrainAndEvaluate(languageCode, examples)
+	var topicDisplayNames []content.TopicDisplayName
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		topicDisplayNames, err = content.GetTopicDisplayNamesForLanguage(tx, languageCode)
+		if err != nil {
+			return err
+		}
+		_, err = topicclassifier.InsertActiveClassifier(tx, *classifier, *evaluation)
+		return err
+	}); err != nil {
+		return fmt.Errorf("Error saving topic classifier: %s", err.Error())
+	}
+	labelsByTopicID := make(map[content.TopicID]string)
+	for _, d := range topicDisplayNames {
+		labelsByTopicID[d.TopicID] = d.Label
+	}
+	c.Infof("Evaluated on %d held out documents, %d were not classified", evaluation.NumberOfHeldOutExamples, evaluation.NumberOfUnclassified)
+	for _, e := range evaluation.Topics {
+		precision, recall := "n/a", "n/a"
+		if e.Precision != nil {
+			precision = fmt.Sprintf("%.3f", *e.Precision)
+		}
+		if e.Recall != nil {
+			recall = fmt.Sprintf("%.3f", *e.Recall)
+		}
+		c.Infof("Topic %s (%s): support %d, precision %s, recall %s", labelsByTopicID[e.TopicID], e.TopicID, e.Support, precision, recall)
+	}
+	return nil
+}
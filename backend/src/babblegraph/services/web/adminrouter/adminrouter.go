@@ -7,6 +7,7 @@ import (
 	"babblegraph/services/web/adminrouter/api/billing"
 	"babblegraph/services/web/adminrouter/api/blog"
 	"babblegraph/services/web/adminrouter/api/content"
+	"babblegraph/services/web/adminrouter/api/newsletter"
 	"babblegraph/services/web/adminrouter/api/podcasts"
 	"babblegraph/services/web/adminrouter/api/usermetrics"
 	"babblegraph/services/web/router"
@@ -31,6 +32,7 @@ func RegisterAdminRouter(r *mux.Router) error {
 		billing.Routes,
 		podcasts.Routes,
 		advertising.Routes,
+		newsletter.Routes,
 	}); err != nil {
 		return err
 	}
@@ -17,13 +17,13 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "insert_vendor_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingVendors,
 				insertVendor,
 			),
 		}, {
 			Path: "update_vendor_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingVendors,
 				editVendor,
 			),
@@ -35,13 +35,13 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "insert_source_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingSources,
 				insertSource,
 			),
 		}, {
 			Path: "update_source_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingSources,
 				editSource,
 			),
@@ -59,13 +59,13 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "insert_campaign_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingCampaigns,
 				insertCampaign,
 			),
 		}, {
 			Path: "update_campaign_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingCampaigns,
 				updateCampaign,
 			),
@@ -77,7 +77,7 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "update_campaign_topic_mappings_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingCampaigns,
 				updateCampaignTopicMappings,
 			),
@@ -89,13 +89,13 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "insert_advertisement_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingAdvertisements,
 				insertAdvertisement,
 			),
 		}, {
 			Path: "update_advertisement_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditAdvertisingAdvertisements,
 				updateAdvertisement,
 			),
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type getAuditLogEntriesRequest struct {
+	AdminID   *admin.ID  `json:"admin_id,omitempty"`
+	Route     *string    `json:"route,omitempty"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Limit     *int       `json:"limit,omitempty"`
+}
+
+type getAuditLogEntriesResponse struct {
+	Entries []auditLogEntry `json:"entries"`
+}
+
+type auditLogEntry struct {
+	ID           admin.AuditLogEntryID `json:"id"`
+	CreatedAt    time.Time             `json:"created_at"`
+	AdminID      admin.ID              `json:"admin_id"`
+	EmailAddress *string               `json:"email_address,omitempty"`
+	Route        string                `json:"route"`
+	Permission   *admin.Permission     `json:"permission,omitempty"`
+	RequestBody  *json.RawMessage      `json:"request_body,omitempty"`
+	Result       admin.AuditLogResult  `json:"result"`
+	ErrorMessage *string               `json:"error_message,omitempty"`
+	CompletedAt  *time.Time            `json:"completed_at,omitempty"`
+}
+
+func getAuditLogEntries(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req getAuditLogEntriesRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var entries []admin.AuditLogEntry
+	emailAddressesByAdminID := make(map[admin.ID]string)
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		entries, err = admin.GetAuditLogEntries(tx, admin.GetAuditLogEntriesInput{
+			AdminUserID: req.AdminID,
+			Route:       req.Route,
+			StartTime:   req.StartTime,
+			EndTime:     req.EndTime,
+			Limit:       req.Limit,
+		})
+		if err != nil {
+			return err
+		}
+		adminUsers, err := admin.GetAllAdminUsers(tx)
+		if err != nil {
+			return err
+		}
+		for _, u := range adminUsers {
+			emailAddressesByAdminID[u.ID] = u.EmailAddress
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	out := []auditLogEntry{}
+	for _, e := range entries {
+		entry := auditLogEntry{
+			ID:           e.ID,
+			CreatedAt:    e.CreatedAt,
+			AdminID:      e.AdminUserID,
+			Route:        e.Route,
+			Permission:   e.Permission,
+			Result:       e.Result,
+			ErrorMessage: e.ErrorMessage,
+			CompletedAt:  e.CompletedAt,
+		}
+		if emailAddress, ok := emailAddressesByAdminID[e.AdminUserID]; ok {
+			entry.EmailAddress = &emailAddress
+		}
+		if e.RequestBody != nil {
+			requestBody := json.RawMessage(*e.RequestBody)
+			entry.RequestBody = &requestBody
+		}
+		out = append(out, entry)
+	}
+	return getAuditLogEntriesResponse{
+		Entries: out,
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"babblegraph/util/email"
 	"babblegraph/util/encrypt"
 	"babblegraph/util/env"
+	"babblegraph/util/ptr"
 	"fmt"
 	"net/http"
 	"time"
@@ -22,9 +23,44 @@ var Routes = router.RouteGroup{
 		{
 			Path:    "validate_login_credentials_1",
 			Handler: validateLoginCredentials,
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       10,
+					RefillInterval: time.Minute,
+				},
+			},
+		}, {
+			Path:    "request_email_two_factor_code_1",
+			Handler: requestEmailTwoFactorCode,
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       5,
+					RefillInterval: time.Minute,
+				},
+				{
+					Key:            rateLimitKeyLoginTokenAdminID,
+					Capacity:       5,
+					RefillInterval: 2 * time.Minute,
+				},
+			},
 		}, {
 			Path:    "validate_two_factor_code_1",
 			Handler: validateTwoFactorAuthenticationCode,
+			// Six digit codes can be guessed without a limit
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       10,
+					RefillInterval: time.Minute,
+				},
+				{
+					Key:            rateLimitKeyLoginTokenAdminID,
+					Capacity:       5,
+					RefillInterval: 2 * time.Minute,
+				},
+			},
 		}, {
 			Path:    "invalidate_login_credentials_1",
 			Handler: invalidateCredentials,
@@ -34,9 +70,33 @@ var Routes = router.RouteGroup{
 		}, {
 			Path:    "validate_two_factor_code_for_create_1",
 			Handler: validateTwoFactorAuthenticationCodeForCreate,
+		}, {
+			Path:    "get_two_factor_settings_1",
+			Handler: middleware.WithAuthentication(getTwoFactorSettings),
+		}, {
+			Path:    "set_two_factor_method_1",
+			Handler: middleware.WithAuditedAuthentication(setTwoFactorMethod),
+		}, {
+			Path:    "begin_totp_enrollment_1",
+			Handler: middleware.WithAuditedAuthentication(beginTOTPEnrollment),
+		}, {
+			Path:    "confirm_totp_enrollment_1",
+			Handler: middleware.WithAuditedAuthentication(confirmTOTPEnrollment),
+		}, {
+			Path:    "regenerate_recovery_codes_1",
+			Handler: middleware.WithAuditedAuthentication(regenerateRecoveryCodes),
+		}, {
+			Path:    "begin_webauthn_registration_1",
+			Handler: middleware.WithAuthentication(beginWebAuthnRegistration),
+		}, {
+			Path:    "finish_webauthn_registration_1",
+			Handler: middleware.WithAuditedAuthentication(finishWebAuthnRegistration),
+		}, {
+			Path:    "remove_security_key_1",
+			Handler: middleware.WithAuditedAuthentication(removeSecurityKey),
 		}, {
 			Path: "manage_user_permissions_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionManagePermissions,
 				manageUserPermissions,
 			),
@@ -46,6 +106,12 @@ var Routes = router.RouteGroup{
 				admin.PermissionManagePermissions,
 				getUsersWithPermissions,
 			),
+		}, {
+			Path: "get_audit_log_entries_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionViewAuditLog,
+				getAuditLogEntries,
+			),
 		},
 	},
 }
@@ -56,7 +122,10 @@ type validateLoginCredentialsRequest struct {
 }
 
 type validateLoginCredentialsResponse struct {
-	Success bool `json:"success"`
+	Success         bool                           `json:"success"`
+	LoginToken      *string                        `json:"login_token,omitempty"`
+	TwoFactorMethod *admin.TwoFactorMethod         `json:"two_factor_method,omitempty"`
+	WebAuthnOptions *webAuthnAuthenticationOptions `json:"webauthn_options,omitempty"`
 }
 
 func validateLoginCredentials(r *router.Request) (interface{}, error) {
@@ -64,7 +133,9 @@ func validateLoginCredentials(r *router.Request) (interface{}, error) {
 	if err := r.GetJSONBody(&req); err != nil {
 		return nil, err
 	}
-	var success bool
+	var loginToken *string
+	var twoFactorMethod *admin.TwoFactorMethod
+	var webAuthnOptions *admin.WebAuthnAuthenticationOptions
 	formattedEmailAddress := email.FormatEmailAddress(req.EmailAddress)
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		adminUser, err := admin.LookupAdminUserByEmailAddress(tx, formattedEmailAddress)
@@ -77,22 +148,84 @@ func validateLoginCredentials(r *router.Request) (interface{}, error) {
 		if err := admin.ValidateAdminUserPassword(tx, adminUser.ID, req.Password); err != nil {
 			return nil
 		}
-		if err := admin.CreateTwoFactorAuthenticationAttempt(tx, adminUser.ID); err != nil {
+		switch adminUser.TwoFactorMethod {
+		case admin.TwoFactorMethodEmail:
+			if err := admin.CreateTwoFactorAuthenticationAttempt(tx, adminUser.ID); err != nil {
+				return err
+			}
+		case admin.TwoFactorMethodTOTP:
+			// no-op
+		case admin.TwoFactorMethodWebAuthn:
+			webAuthnOptions, err = admin.BeginWebAuthnAuthentication(tx, adminUser.ID)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unrecognized two factor method: %s", adminUser.TwoFactorMethod)
+		}
+		loginToken, err = routes.MakeAdminLoginToken(adminUser.ID)
+		if err != nil {
 			return err
 		}
-		success = true
+		twoFactorMethod = adminUser.TwoFactorMethod.Ptr()
 		return nil
 	}); err != nil {
 		return nil, err
 	}
 	return validateLoginCredentialsResponse{
-		Success: success,
+		Success:         loginToken != nil,
+		LoginToken:      loginToken,
+		TwoFactorMethod: twoFactorMethod,
+		WebAuthnOptions: toWebAuthnAuthenticationOptions(webAuthnOptions),
 	}, nil
 }
 
+type requestEmailTwoFactorCodeRequest struct {
+	LoginToken string `json:"login_token"`
+}
+
+type requestEmailTwoFactorCodeResponse struct {
+	Success bool `json:"success"`
+}
+
+// Email codes are the fallback for admins who
+// can't get to their authenticator app or security key
+func requestEmailTwoFactorCode(r *router.Request) (interface{}, error) {
+	var req requestEmailTwoFactorCodeRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	adminUserID, err := getAdminIDFromLoginToken(req.LoginToken)
+	if err != nil {
+		r.Warnf("Error decoding login token: %s", err.Error())
+		return requestEmailTwoFactorCodeResponse{
+			Success: false,
+		}, nil
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return admin.CreateTwoFactorAuthenticationAttempt(tx, *adminUserID)
+	}); err != nil {
+		return nil, err
+	}
+	return requestEmailTwoFactorCodeResponse{
+		Success: true,
+	}, nil
+}
+
+type twoFactorCodeType string
+
+const (
+	twoFactorCodeTypeEmail        twoFactorCodeType = "email"
+	twoFactorCodeTypeTOTP         twoFactorCodeType = "totp"
+	twoFactorCodeTypeRecoveryCode twoFactorCodeType = "recovery-code"
+	twoFactorCodeTypeWebAuthn     twoFactorCodeType = "webauthn"
+)
+
 type validateTwoFactorAuthenticationCodeRequest struct {
-	EmailAddress                string `json:"email_address"`
-	TwoFactorAuthenticationCode string `json:"two_factor_authentication_code"`
+	LoginToken                  string             `json:"login_token"`
+	CodeType                    twoFactorCodeType  `json:"code_type"`
+	TwoFactorAuthenticationCode string             `json:"two_factor_authentication_code"`
+	WebAuthnAssertion           *webAuthnAssertion `json:"webauthn_assertion,omitempty"`
 }
 
 type validateTwoFactorAuthenticationCodeResponse struct {
@@ -104,38 +237,32 @@ func validateTwoFactorAuthenticationCode(r *router.Request) (interface{}, error)
 	if err := r.GetJSONBody(&req); err != nil {
 		return nil, err
 	}
-	formattedEmailAddress := email.FormatEmailAddress(req.EmailAddress)
+	adminUserID, err := getAdminIDFromLoginToken(req.LoginToken)
+	if err != nil {
+		r.Warnf("Error decoding login token: %s", err.Error())
+		return validateTwoFactorAuthenticationCodeResponse{
+			Success: false,
+		}, nil
+	}
 	var accessToken *string
 	var expirationTime *time.Time
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
-		adminUser, err := admin.LookupAdminUserByEmailAddress(tx, formattedEmailAddress)
+		isValid, err := validateTwoFactorCodeOfType(tx, *adminUserID, req)
 		switch {
 		case err != nil:
 			return err
-		case adminUser == nil:
+		case !isValid:
 			return nil
 		}
-		envName := env.MustEnvironmentName()
-		switch envName {
-		case env.EnvironmentProd,
-			env.EnvironmentStage:
-			if err := admin.ValidateTwoFactorAuthenticationAttempt(tx, adminUser.ID, req.TwoFactorAuthenticationCode); err != nil {
-				return err
-			}
-		case env.EnvironmentLocal,
-			env.EnvironmentLocalNoEmail,
-			env.EnvironmentLocalTestEmail:
-			// no-op
-		default:
-			return fmt.Errorf("Unrecognized environment: %s", envName)
-		}
-		accessToken, expirationTime, err = admin.CreateAccessToken(tx, adminUser.ID)
+		accessToken, expirationTime, err = admin.CreateAccessToken(tx, *adminUserID)
 		return err
 	}); err != nil {
 		return nil, err
 	}
 	if accessToken == nil {
-		return nil, fmt.Errorf("Null access token")
+		return validateTwoFactorAuthenticationCodeResponse{
+			Success: false,
+		}, nil
 	}
 	r.RespondWithCookie(&http.Cookie{
 		Name:     admin.AccessTokenCookieName,
@@ -149,6 +276,76 @@ func validateTwoFactorAuthenticationCode(r *router.Request) (interface{}, error)
 	}, nil
 }
 
+func validateTwoFactorCodeOfType(tx *sqlx.Tx, adminUserID admin.ID, req validateTwoFactorAuthenticationCodeRequest) (_isValid bool, _err error) {
+	switch req.CodeType {
+	case twoFactorCodeTypeEmail:
+		envName := env.MustEnvironmentName()
+		switch envName {
+		case env.EnvironmentProd,
+			env.EnvironmentStage:
+			if err := admin.ValidateTwoFactorAuthenticationAttempt(tx, adminUserID, req.TwoFactorAuthenticationCode); err != nil {
+				return false, nil
+			}
+			return true, nil
+		case env.EnvironmentLocal,
+			env.EnvironmentLocalNoEmail,
+			env.EnvironmentLocalTestEmail:
+			return true, nil
+		default:
+			return false, fmt.Errorf("Unrecognized environment: %s", envName)
+		}
+	case twoFactorCodeTypeTOTP:
+		return admin.ValidateTOTPCode(tx, adminUserID, req.TwoFactorAuthenticationCode)
+	case twoFactorCodeTypeRecoveryCode:
+		return admin.ValidateRecoveryCode(tx, adminUserID, req.TwoFactorAuthenticationCode)
+	case twoFactorCodeTypeWebAuthn:
+		if req.WebAuthnAssertion == nil {
+			return false, nil
+		}
+		assertion, err := req.WebAuthnAssertion.toModel()
+		if err != nil {
+			return false, nil
+		}
+		return admin.ValidateWebAuthnAssertion(tx, adminUserID, *assertion)
+	default:
+		return false, fmt.Errorf("Unrecognized two factor code type: %s", req.CodeType)
+	}
+}
+
+// Each login mints a new token, so limits on the token itself can be
+// reset by logging in again. Keying on the admin ID inside it can't be.
+var rateLimitKeyLoginTokenAdminID = router.RateLimitKey{
+	Name: "login-token-admin-id",
+	GetKey: func(r *router.Request) (*string, error) {
+		var req struct {
+			LoginToken string `json:"login_token"`
+		}
+		if err := r.GetJSONBody(&req); err != nil {
+			return nil, nil
+		}
+		adminUserID, err := getAdminIDFromLoginToken(req.LoginToken)
+		if err != nil {
+			return nil, nil
+		}
+		return ptr.String(string(*adminUserID)), nil
+	},
+}
+
+func getAdminIDFromLoginToken(token string) (*admin.ID, error) {
+	var adminUserID *admin.ID
+	if err := encrypt.WithDecodedToken(token, routes.AdminLoginKey.Str(), func(t encrypt.TokenPair) error {
+		adminIDStr, ok := t.Value.(string)
+		if !ok {
+			return fmt.Errorf("incorrect type")
+		}
+		adminUserID = admin.ID(adminIDStr).Ptr()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return adminUserID, nil
+}
+
 type invalidateCredentialsRequest struct{}
 
 type invalidateCredentialsResponse struct {
@@ -195,10 +392,7 @@ func createAdminUserPassword(r *router.Request) (interface{}, error) {
 	}
 	formattedEmailAddress := email.FormatEmailAddress(req.EmailAddress)
 	var adminUserID admin.ID
-	if err := encrypt.WithDecodedToken(req.Token, func(t encrypt.TokenPair) error {
-		if t.Key != routes.AdminRegistrationKey.Str() {
-			return fmt.Errorf("incorrect key")
-		}
+	if err := encrypt.WithDecodedToken(req.Token, routes.AdminRegistrationKey.Str(), func(t encrypt.TokenPair) error {
 		adminIDStr, ok := t.Value.(string)
 		if !ok {
 			return fmt.Errorf("incorrect type")
@@ -246,10 +440,7 @@ func validateTwoFactorAuthenticationCodeForCreate(r *router.Request) (interface{
 		return nil, err
 	}
 	var adminUserID admin.ID
-	if err := encrypt.WithDecodedToken(req.Token, func(t encrypt.TokenPair) error {
-		if t.Key != routes.AdminRegistrationKey.Str() {
-			return fmt.Errorf("incorrect key")
-		}
+	if err := encrypt.WithDecodedToken(req.Token, routes.AdminRegistrationKey.Str(), func(t encrypt.TokenPair) error {
 		adminIDStr, ok := t.Value.(string)
 		if !ok {
 			return fmt.Errorf("incorrect type")
@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"encoding/base64"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Binary WebAuthn values are passed back and forth as unpadded base64url strings
+
+type webAuthnAuthenticationOptions struct {
+	Challenge          string   `json:"challenge"`
+	RelyingPartyID     string   `json:"relying_party_id"`
+	AllowCredentialIDs []string `json:"allow_credential_ids"`
+}
+
+func toWebAuthnAuthenticationOptions(options *admin.WebAuthnAuthenticationOptions) *webAuthnAuthenticationOptions {
+	if options == nil {
+		return nil
+	}
+	return &webAuthnAuthenticationOptions{
+		Challenge:          options.Challenge,
+		RelyingPartyID:     options.RelyingPartyID,
+		AllowCredentialIDs: options.AllowCredentialIDs,
+	}
+}
+
+type webAuthnAssertion struct {
+	CredentialID      string `json:"credential_id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data"`
+	Signature         string `json:"signature"`
+}
+
+func (w webAuthnAssertion) toModel() (*admin.WebAuthnAssertion, error) {
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(w.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	authenticatorData, err := base64.RawURLEncoding.DecodeString(w.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(w.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &admin.WebAuthnAssertion{
+		CredentialID:      w.CredentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         signature,
+	}, nil
+}
+
+type getTwoFactorSettingsRequest struct{}
+
+type getTwoFactorSettingsResponse struct {
+	TwoFactorMethod             admin.TwoFactorMethod `json:"two_factor_method"`
+	IsTOTPEnrolled              bool                  `json:"is_totp_enrolled"`
+	NumberOfUnusedRecoveryCodes int                   `json:"number_of_unused_recovery_codes"`
+	SecurityKeys                []securityKey         `json:"security_keys"`
+}
+
+type securityKey struct {
+	CredentialID string     `json:"credential_id"`
+	Name         string     `json:"name"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+}
+
+func getTwoFactorSettings(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var settings *admin.TwoFactorSettings
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		settings, err = admin.GetTwoFactorSettings(tx, adminID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	securityKeys := []securityKey{}
+	for _, c := range settings.WebAuthnCredentials {
+		securityKeys = append(securityKeys, securityKey{
+			CredentialID: c.CredentialID,
+			Name:         c.Name,
+			CreatedAt:    c.CreatedAt,
+			LastUsedAt:   c.LastUsedAt,
+		})
+	}
+	return getTwoFactorSettingsResponse{
+		TwoFactorMethod:             settings.Method,
+		IsTOTPEnrolled:              settings.IsTOTPEnrolled,
+		NumberOfUnusedRecoveryCodes: settings.NumberOfUnusedRecoveryCodes,
+		SecurityKeys:                securityKeys,
+	}, nil
+}
+
+type setTwoFactorMethodRequest struct {
+	TwoFactorMethod admin.TwoFactorMethod `json:"two_factor_method"`
+}
+
+type setTwoFactorMethodResponse struct {
+	Success bool `json:"success"`
+}
+
+func setTwoFactorMethod(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req setTwoFactorMethodRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return admin.SetTwoFactorMethod(tx, adminID, req.TwoFactorMethod)
+	}); err != nil {
+		return nil, err
+	}
+	return setTwoFactorMethodResponse{
+		Success: true,
+	}, nil
+}
+
+type beginTOTPEnrollmentRequest struct{}
+
+type beginTOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+func beginTOTPEnrollment(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var enrollment *admin.TOTPEnrollment
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		enrollment, err = admin.BeginTOTPEnrollment(tx, adminID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return beginTOTPEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	}, nil
+}
+
+type confirmTOTPEnrollmentRequest struct {
+	Code string `json:"code"`
+}
+
+type confirmTOTPEnrollmentResponse struct {
+	Success       bool     `json:"success"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+func confirmTOTPEnrollment(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req confirmTOTPEnrollmentRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var recoveryCodes []string
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		recoveryCodes, err = admin.ConfirmTOTPEnrollment(tx, adminID, req.Code)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return confirmTOTPEnrollmentResponse{
+		Success:       recoveryCodes != nil,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+type regenerateRecoveryCodesRequest struct{}
+
+type regenerateRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func regenerateRecoveryCodes(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var recoveryCodes []string
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		recoveryCodes, err = admin.RegenerateRecoveryCodes(tx, adminID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return regenerateRecoveryCodesResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+type beginWebAuthnRegistrationRequest struct{}
+
+type beginWebAuthnRegistrationResponse struct {
+	Challenge            string   `json:"challenge"`
+	RelyingPartyID       string   `json:"relying_party_id"`
+	RelyingPartyName     string   `json:"relying_party_name"`
+	UserHandle           string   `json:"user_handle"`
+	UserName             string   `json:"user_name"`
+	ExcludeCredentialIDs []string `json:"exclude_credential_ids"`
+}
+
+func beginWebAuthnRegistration(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var options *admin.WebAuthnRegistrationOptions
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		options, err = admin.BeginWebAuthnRegistration(tx, adminID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return beginWebAuthnRegistrationResponse{
+		Challenge:            options.Challenge,
+		RelyingPartyID:       options.RelyingPartyID,
+		RelyingPartyName:     options.RelyingPartyName,
+		UserHandle:           options.UserHandle,
+		UserName:             options.UserName,
+		ExcludeCredentialIDs: options.ExcludeCredentialIDs,
+	}, nil
+}
+
+type finishWebAuthnRegistrationRequest struct {
+	Name              string `json:"name"`
+	CredentialID      string `json:"credential_id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data"`
+	PublicKey         string `json:"public_key"`
+}
+
+type finishWebAuthnRegistrationResponse struct {
+	Success bool `json:"success"`
+}
+
+func finishWebAuthnRegistration(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req finishWebAuthnRegistrationRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(req.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	authenticatorData, err := base64.RawURLEncoding.DecodeString(req.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := base64.RawURLEncoding.DecodeString(req.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return admin.FinishWebAuthnRegistration(tx, adminID, admin.FinishWebAuthnRegistrationInput{
+			Name:              req.Name,
+			CredentialID:      req.CredentialID,
+			ClientDataJSON:    clientDataJSON,
+			AuthenticatorData: authenticatorData,
+			PublicKey:         publicKey,
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return finishWebAuthnRegistrationResponse{
+		Success: true,
+	}, nil
+}
+
+type removeSecurityKeyRequest struct {
+	CredentialID string `json:"credential_id"`
+}
+
+type removeSecurityKeyResponse struct {
+	Success bool `json:"success"`
+}
+
+func removeSecurityKey(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req removeSecurityKeyRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var didRemove bool
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		didRemove, err = admin.RemoveWebAuthnCredential(tx, adminID, req.CredentialID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return removeSecurityKeyResponse{
+		Success: didRemove,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package billing
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/billing"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/ptr"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const dunningMetricsDateFormat = "2006-01-02"
+
+type getDunningMetricsRequest struct {
+	// Both are formatted as YYYY-MM-DD, and the end date is inclusive
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type getDunningMetricsResponse struct {
+	Error          *string                 `json:"error,omitempty"`
+	DunningMetrics *billing.DunningMetrics `json:"dunning_metrics,omitempty"`
+}
+
+func getDunningMetrics(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req getDunningMetricsRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	startDate, err := time.Parse(dunningMetricsDateFormat, req.StartDate)
+	if err != nil {
+		return getDunningMetricsResponse{
+			Error: ptr.String(err.Error()),
+		}, nil
+	}
+	endDate, err := time.Parse(dunningMetricsDateFormat, req.EndDate)
+	if err != nil {
+		return getDunningMetricsResponse{
+			Error: ptr.String(err.Error()),
+		}, nil
+	}
+	endDate = endDate.Add(24 * time.Hour)
+	var dunningMetrics *billing.DunningMetrics
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		dunningMetrics, err = billing.GetDunningMetrics(tx, startDate, endDate)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getDunningMetricsResponse{
+		DunningMetrics: dunningMetrics,
+	}, nil
+}
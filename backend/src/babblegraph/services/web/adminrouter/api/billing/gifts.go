@@ -0,0 +1,160 @@
+package billing
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/users"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/email"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type getGiftCodesRequest struct{}
+
+type getGiftCodesResponse struct {
+	GiftCodes []useraccounts.GiftCode `json:"gift_codes"`
+}
+
+func getGiftCodes(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var giftCodes []useraccounts.GiftCode
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		giftCodes, err = useraccounts.GetAllGiftCodes(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getGiftCodesResponse{
+		GiftCodes: giftCodes,
+	}, nil
+}
+
+type createGiftCodeRequest struct {
+	DurationDays          int64   `json:"duration_days"`
+	PurchaserEmailAddress *string `json:"purchaser_email_address,omitempty"`
+	Note                  *string `json:"note,omitempty"`
+}
+
+type createGiftCodeResponse struct {
+	GiftCode *useraccounts.GiftCode `json:"gift_code"`
+}
+
+func createGiftCode(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req createGiftCodeRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var purchaserEmailAddress *string
+	if req.PurchaserEmailAddress != nil {
+		formattedEmailAddress := email.FormatEmailAddress(*req.PurchaserEmailAddress)
+		purchaserEmailAddress = &formattedEmailAddress
+	}
+	var giftCode *useraccounts.GiftCode
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		giftCode, err = useraccounts.CreateGiftCode(tx, useraccounts.CreateGiftCodeInput{
+			DurationDays:          req.DurationDays,
+			PurchaserEmailAddress: purchaserEmailAddress,
+			Note:                  req.Note,
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return createGiftCodeResponse{
+		GiftCode: giftCode,
+	}, nil
+}
+
+type getGroupLicensesRequest struct{}
+
+type getGroupLicensesResponse struct {
+	GroupLicenses []useraccounts.GroupLicense `json:"group_licenses"`
+}
+
+func getGroupLicenses(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var groupLicenses []useraccounts.GroupLicense
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		groupLicenses, err = useraccounts.GetAllGroupLicenses(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getGroupLicensesResponse{
+		GroupLicenses: groupLicenses,
+	}, nil
+}
+
+type createGroupLicenseRequest struct {
+	OwnerEmailAddress string    `json:"owner_email_address"`
+	Name              string    `json:"name"`
+	SeatCount         int64     `json:"seat_count"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type createGroupLicenseResponse struct {
+	GroupLicense *useraccounts.GroupLicense `json:"group_license"`
+}
+
+func createGroupLicense(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req createGroupLicenseRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	formattedEmailAddress := email.FormatEmailAddress(req.OwnerEmailAddress)
+	var groupLicense *useraccounts.GroupLicense
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		owner, err := users.LookupUserByEmailAddress(tx, formattedEmailAddress)
+		switch {
+		case err != nil:
+			return err
+		case owner == nil:
+			return fmt.Errorf("No user found for group license owner")
+		}
+		groupLicense, err = useraccounts.CreateGroupLicense(tx, useraccounts.CreateGroupLicenseInput{
+			OwnerUserID: owner.ID,
+			Name:        req.Name,
+			SeatCount:   req.SeatCount,
+			ExpiresAt:   req.ExpiresAt,
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return createGroupLicenseResponse{
+		GroupLicense: groupLicense,
+	}, nil
+}
+
+type updateGroupLicenseRequest struct {
+	GroupLicenseID useraccounts.GroupLicenseID `json:"group_license_id"`
+	SeatCount      int64                       `json:"seat_count"`
+	ExpiresAt      time.Time                   `json:"expires_at"`
+}
+
+type updateGroupLicenseResponse struct {
+	GroupLicense *useraccounts.GroupLicense `json:"group_license"`
+}
+
+func updateGroupLicense(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req updateGroupLicenseRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var groupLicense *useraccounts.GroupLicense
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		groupLicense, err = useraccounts.UpdateGroupLicense(tx, req.GroupLicenseID, req.SeatCount, req.ExpiresAt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return updateGroupLicenseResponse{
+		GroupLicense: groupLicense,
+	}, nil
+}
@@ -24,16 +24,64 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "force_sync_for_user_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionManageBilling,
 				forceSyncForUser,
 			),
 		}, {
 			Path: "create_promotion_code_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionManageBilling,
 				createPromotionCode,
 			),
+		}, {
+			Path: "get_billing_plans_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionManageBilling,
+				getBillingPlans,
+			),
+		}, {
+			Path: "set_stripe_price_for_plan_type_1",
+			Handler: middleware.WithAuditedPermission(
+				admin.PermissionManageBilling,
+				setStripePriceForPlanType,
+			),
+		}, {
+			Path: "get_gift_codes_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionManageBilling,
+				getGiftCodes,
+			),
+		}, {
+			Path: "create_gift_code_1",
+			Handler: middleware.WithAuditedPermission(
+				admin.PermissionManageBilling,
+				createGiftCode,
+			),
+		}, {
+			Path: "get_group_licenses_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionManageBilling,
+				getGroupLicenses,
+			),
+		}, {
+			Path: "create_group_license_1",
+			Handler: middleware.WithAuditedPermission(
+				admin.PermissionManageBilling,
+				createGroupLicense,
+			),
+		}, {
+			Path: "update_group_license_1",
+			Handler: middleware.WithAuditedPermission(
+				admin.PermissionManageBilling,
+				updateGroupLicense,
+			),
+		}, {
+			Path: "get_dunning_metrics_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionManageBilling,
+				getDunningMetrics,
+			),
 		},
 	},
 }
@@ -141,3 +189,54 @@ func createPromotionCode(adminID admin.ID, r *router.Request) (interface{}, erro
 		PromotionCode: promotionCode,
 	}, nil
 }
+
+type getBillingPlansRequest struct{}
+
+type getBillingPlansResponse struct {
+	Plans []billing.Plan `json:"plans"`
+}
+
+func getBillingPlans(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var plans []billing.Plan
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		plans, err = billing.GetAllPlans(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getBillingPlansResponse{
+		Plans: plans,
+	}, nil
+}
+
+type setStripePriceForPlanTypeRequest struct {
+	PlanType      string `json:"plan_type"`
+	StripePriceID string `json:"stripe_price_id"`
+}
+
+type setStripePriceForPlanTypeResponse struct {
+	Plan *billing.Plan `json:"plan"`
+}
+
+func setStripePriceForPlanType(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req setStripePriceForPlanTypeRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	planType, err := billing.GetPlanTypeForString(req.PlanType)
+	if err != nil {
+		return nil, err
+	}
+	var plan *billing.Plan
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		plan, err = billing.SetStripePriceForPlanType(r, tx, *planType, req.StripePriceID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return setStripePriceForPlanTypeResponse{
+		Plan: plan,
+	}, nil
+}
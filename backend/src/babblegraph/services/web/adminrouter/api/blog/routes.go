@@ -32,25 +32,25 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "add_blog_post_metadata_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionWriteBlog,
 				addBlogPostMetadata,
 			),
 		}, {
 			Path: "update_blog_post_metadata_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionWriteBlog,
 				updateBlogPostMetadata,
 			),
 		}, {
 			Path: "update_blog_post_status_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionPublishBlog,
 				updateBlogPostStatus,
 			),
 		}, {
 			Path: "update_blog_content_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionWriteBlog,
 				updateBlogContent,
 			),
@@ -62,7 +62,7 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "upload_blog_image_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionWriteBlog,
 				uploadBlogImage,
 			),
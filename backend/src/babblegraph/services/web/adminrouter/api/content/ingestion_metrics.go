@@ -0,0 +1,82 @@
+package content
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/content"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/timeutils"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultIngestionMetricsWindowDays = 7
+	maxIngestionMetricsWindowDays     = 90
+)
+
+type getSourceIngestionMetricsRequest struct {
+	WindowDays *int `json:"window_days,omitempty"`
+}
+
+type getSourceIngestionMetricsResponse struct {
+	Metrics []content.SourceIngestionMetrics `json:"metrics"`
+}
+
+func getSourceIngestionMetrics(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req getSourceIngestionMetricsRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	windowDays := defaultIngestionMetricsWindowDays
+	if req.WindowDays != nil {
+		windowDays = *req.WindowDays
+	}
+	if windowDays <= 0 || windowDays > maxIngestionMetricsWindowDays {
+		return nil, fmt.Errorf("Window must be between 1 and %d days", maxIngestionMetricsWindowDays)
+	}
+	windowStart := timeutils.ConvertToMidnight(time.Now().UTC().Add(-1 * time.Duration(windowDays) * 24 * time.Hour))
+	var metrics []content.SourceIngestionMetrics
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		metrics, err = content.GetIngestionMetricsForWindow(tx, windowStart)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getSourceIngestionMetricsResponse{
+		Metrics: metrics,
+	}, nil
+}
+
+type getSourceQuarantinesRequest struct {
+	SourceID *content.SourceID `json:"source_id,omitempty"`
+}
+
+type getSourceQuarantinesResponse struct {
+	Quarantines []content.SourceQuarantine `json:"quarantines"`
+}
+
+func getSourceQuarantines(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req getSourceQuarantinesRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var quarantines []content.SourceQuarantine
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		if req.SourceID != nil {
+			quarantines, err = content.GetSourceQuarantinesForSource(tx, *req.SourceID)
+		} else {
+			quarantines, err = content.GetAllSourceQuarantines(tx)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getSourceQuarantinesResponse{
+		Quarantines: quarantines,
+	}, nil
+}
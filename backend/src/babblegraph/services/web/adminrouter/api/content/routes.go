@@ -17,7 +17,7 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "add_topic_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentTopics,
 				addContentTopic,
 			),
@@ -29,7 +29,7 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "update_is_topic_active_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentTopics,
 				updateIsContentTopicActive,
 			),
@@ -41,19 +41,19 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "add_topic_display_name_for_topic_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentTopics,
 				addTopicDisplayNameForTopic,
 			),
 		}, {
 			Path: "update_topic_display_name_label_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentTopics,
 				updateTopicDisplayNameLabel,
 			),
 		}, {
 			Path: "toggle_topic_display_name_is_active_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentTopics,
 				toggleTopicDisplayNameIsActive,
 			),
@@ -71,13 +71,13 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "add_source_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				addSource,
 			),
 		}, {
 			Path: "update_source_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				updateSource,
 			),
@@ -89,19 +89,19 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "add_source_seed_for_source_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				addSourceSeed,
 			),
 		}, {
 			Path: "update_source_seed_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				updateSourceSeed,
 			),
 		}, {
 			Path: "upsert_source_seed_mappings_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				upsertSourceSeedMappings,
 			),
@@ -119,10 +119,28 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "upsert_source_filter_for_source_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				upsertSourceFilterForSource,
 			),
+		}, {
+			Path: "get_source_ingestion_metrics_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionEditContentSources,
+				getSourceIngestionMetrics,
+			),
+		}, {
+			Path: "get_source_quarantines_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionEditContentSources,
+				getSourceQuarantines,
+			),
+		}, {
+			Path: "test_source_configuration_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionEditContentSources,
+				testSourceConfiguration,
+			),
 		},
 	},
 }
@@ -0,0 +1,131 @@
+package content
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/content"
+	"babblegraph/model/ingesthtml"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/geo"
+	"babblegraph/util/urlparser"
+	"babblegraph/wordsmith"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type testSourceConfigurationRequest struct {
+	SampleURL string `json:"sample_url"`
+
+	// If SourceID is set, the saved source is used and the fields below
+	// are ignored. The saved source filter is used unless one is given in
+	// the request, so that changes can be tested before they are saved.
+	SourceID       *content.SourceID `json:"source_id,omitempty"`
+	URL            string            `json:"url"`
+	LanguageCode   string            `json:"language_code"`
+	Country        string            `json:"country"`
+	IngestStrategy string            `json:"ingest_strategy"`
+	Type           string            `json:"type"`
+
+	SourceFilter *testSourceConfigurationSourceFilter `json:"source_filter,omitempty"`
+}
+
+type testSourceConfigurationSourceFilter struct {
+	IsActive            bool     `json:"is_active"`
+	UseLDJSONValidation *bool    `json:"use_ld_json_validation,omitempty"`
+	PaywallClasses      []string `json:"paywall_classes"`
+	PaywallIDs          []string `json:"paywall_ids"`
+}
+
+type testSourceConfigurationResponse struct {
+	Result ingesthtml.DryRunWebsiteHTML1LinkOutput `json:"result"`
+}
+
+func testSourceConfiguration(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req testSourceConfigurationRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var source *content.Source
+	var sourceFilter *content.SourceFilter
+	if req.SourceID != nil {
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			var err error
+			source, err = content.GetSource(tx, *req.SourceID)
+			if err != nil {
+				return err
+			}
+			sourceFilter, err = content.LookupSourceFilterForSource(tx, *req.SourceID)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		source, err = makeSourceForDryRun(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case req.SourceFilter == nil:
+		// no-op
+	case !req.SourceFilter.IsActive:
+		sourceFilter = nil
+	default:
+		sourceFilter = &content.SourceFilter{
+			RootID:              source.ID,
+			IsActive:            req.SourceFilter.IsActive,
+			UseLDJSONValidation: req.SourceFilter.UseLDJSONValidation,
+			PaywallClasses:      req.SourceFilter.PaywallClasses,
+			PaywallIDs:          req.SourceFilter.PaywallIDs,
+		}
+	}
+	result, err := ingesthtml.DryRunWebsiteHTML1Link(r, ingesthtml.DryRunWebsiteHTML1LinkInput{
+		URL:          req.SampleURL,
+		Source:       *source,
+		SourceFilter: sourceFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return testSourceConfigurationResponse{
+		Result: *result,
+	}, nil
+}
+
+func makeSourceForDryRun(req testSourceConfigurationRequest) (*content.Source, error) {
+	countryCode, err := geo.GetCountryCodeFromString(req.Country)
+	if err != nil {
+		return nil, err
+	}
+	ingestStrategy, err := content.GetIngestStrategyFromString(req.IngestStrategy)
+	if err != nil {
+		return nil, err
+	}
+	languageCode, err := wordsmith.GetLanguageCodeFromString(req.LanguageCode)
+	if err != nil {
+		return nil, err
+	}
+	sourceType, err := content.GetSourceTypeFromString(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	u := urlparser.ParseURL(req.URL)
+	if u == nil {
+		return nil, fmt.Errorf("Invalid URL")
+	}
+	url, err := urlparser.EnsureProtocol(u.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &content.Source{
+		Title:          u.Domain,
+		URL:            *url,
+		Type:           *sourceType,
+		Country:        *countryCode,
+		IngestStrategy: *ingestStrategy,
+		LanguageCode:   *languageCode,
+		IsActive:       true,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package newsletter
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/emailtemplates"
+	"babblegraph/model/newsletter"
+	"babblegraph/model/users"
+	"babblegraph/services/web/adminrouter/middleware"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/ptr"
+	"babblegraph/wordsmith"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var Routes = router.RouteGroup{
+	Prefix: "newsletter",
+	Routes: []router.Route{
+		{
+			Path: "preview_newsletter_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionPreviewNewsletters,
+				previewNewsletter,
+			),
+		},
+	},
+}
+
+const previewDateOfSendFormat = "2006-01-02"
+
+type previewNewsletterRequest struct {
+	UserID       users.UserID `json:"user_id"`
+	DateOfSend   string       `json:"date_of_send"`
+	LanguageCode *string      `json:"language_code,omitempty"`
+}
+
+type previewNewsletterResponse struct {
+	Error           *string                        `json:"error,omitempty"`
+	NoSendRequested bool                           `json:"no_send_requested"`
+	Newsletter      *newsletter.NewsletterVersion2 `json:"newsletter,omitempty"`
+	HTML            *string                        `json:"html,omitempty"`
+}
+
+func previewNewsletter(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req previewNewsletterRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	dateOfSend, err := time.Parse(previewDateOfSendFormat, req.DateOfSend)
+	if err != nil {
+		return previewNewsletterResponse{
+			Error: ptr.String(err.Error()),
+		}, nil
+	}
+	languageCode := wordsmith.LanguageCodeSpanish.Ptr()
+	if req.LanguageCode != nil {
+		languageCode, err = wordsmith.GetLanguageCodeFromString(*req.LanguageCode)
+		if err != nil {
+			return previewNewsletterResponse{
+				Error: ptr.String(err.Error()),
+			}, nil
+		}
+	}
+	r.Infof("Admin %s is previewing newsletter for user %s on %s", adminID, req.UserID, req.DateOfSend)
+	var newsletterVersion2 *newsletter.NewsletterVersion2
+	var newsletterHTML *string
+	// Creating a newsletter writes the email record along with everything
+	// that references it, so none of this can be committed for a preview
+	if err := database.WithRollbackTx(func(tx *sqlx.Tx) error {
+		var err error
+		newsletterVersion2, err = newsletter.CreateNewsletterVersion2ForUser(r, tx, req.UserID, *languageCode, dateOfSend.UTC())
+		switch {
+		case err != nil:
+			return err
+		case newsletterVersion2 == nil:
+			return nil
+		}
+		userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, req.UserID)
+		if err != nil {
+			return err
+		}
+		renderedNewsletter, err := emailtemplates.MakeNewsletterVersion2(emailtemplates.MakeNewsletterVersion2Input{
+			EmailRecordID:    newsletterVersion2.EmailRecordID,
+			UserAccessor:     userAccessor,
+			Body:             newsletterVersion2.Body,
+			WeeklyDigestBody: newsletterVersion2.WeeklyDigestBody,
+		})
+		if err != nil {
+			return err
+		}
+		newsletterHTML = ptr.String(renderedNewsletter.HTMLBody)
+		return nil
+	}); err != nil {
+		return previewNewsletterResponse{
+			Error: ptr.String(err.Error()),
+		}, nil
+	}
+	return previewNewsletterResponse{
+		NoSendRequested: newsletterVersion2 == nil,
+		Newsletter:      newsletterVersion2,
+		HTML:            newsletterHTML,
+	}, nil
+}
@@ -33,10 +33,16 @@ var Routes = router.RouteGroup{
 			),
 		}, {
 			Path: "add_podcast_1",
-			Handler: middleware.WithPermission(
+			Handler: middleware.WithAuditedPermission(
 				admin.PermissionEditContentSources,
 				addPodcast,
 			),
+		}, {
+			Path: "get_podcast_feed_changes_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionEditContentSources,
+				getPodcastFeedChanges,
+			),
 		},
 	},
 }
@@ -155,3 +161,31 @@ func addPodcast(adminID admin.ID, r *router.Request) (interface{}, error) {
 		Error: nil,
 	}, nil
 }
+
+const maxPodcastFeedChanges = 500
+
+type getPodcastFeedChangesRequest struct {
+	SourceID content.SourceID `json:"source_id"`
+}
+
+type getPodcastFeedChangesResponse struct {
+	FeedChanges []podcasts.FeedChange `json:"feed_changes"`
+}
+
+func getPodcastFeedChanges(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req getPodcastFeedChangesRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var feedChanges []podcasts.FeedChange
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		feedChanges, err = podcasts.GetFeedChangesForSource(tx, req.SourceID, maxPodcastFeedChanges)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getPodcastFeedChangesResponse{
+		FeedChanges: feedChanges,
+	}, nil
+}
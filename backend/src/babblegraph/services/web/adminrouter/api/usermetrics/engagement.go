@@ -0,0 +1,113 @@
+package usermetrics
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/email"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/ptr"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const engagementDateFormat = "2006-01-02"
+
+type getNewsletterEngagementRequest struct {
+	// Both are formatted as YYYY-MM-DD, and the end date is inclusive
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type getNewsletterEngagementResponse struct {
+	Error    *string                                `json:"error,omitempty"`
+	Days     []newsletterEngagementForDay           `json:"days,omitempty"`
+	Sections []email.NewsletterEngagementForSection `json:"sections,omitempty"`
+}
+
+type newsletterEngagementForDay struct {
+	Day          string `json:"day"`
+	Sent         int64  `json:"sent"`
+	Delivered    int64  `json:"delivered"`
+	Opened       int64  `json:"opened"`
+	Clicked      int64  `json:"clicked"`
+	Unsubscribed int64  `json:"unsubscribed"`
+	Bounced      int64  `json:"bounced"`
+
+	DeliveryDelayed int64 `json:"delivery_delayed"`
+	Rejected        int64 `json:"rejected"`
+}
+
+func getNewsletterEngagement(adminID admin.ID, r *router.Request) (interface{}, error) {
+	var req getNewsletterEngagementRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	startDate, err := time.Parse(engagementDateFormat, req.StartDate)
+	if err != nil {
+		return getNewsletterEngagementResponse{
+			Error: ptr.String(err.Error()),
+		}, nil
+	}
+	endDate, err := time.Parse(engagementDateFormat, req.EndDate)
+	if err != nil {
+		return getNewsletterEngagementResponse{
+			Error: ptr.String(err.Error()),
+		}, nil
+	}
+	endDate = endDate.Add(24 * time.Hour)
+	var byDay []email.NewsletterEngagementForDay
+	var bySection []email.NewsletterEngagementForSection
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		byDay, err = email.GetNewsletterEngagementByDay(tx, startDate, endDate)
+		if err != nil {
+			return err
+		}
+		bySection, err = email.GetNewsletterEngagementBySection(tx, startDate, endDate)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	daysByDate := make(map[string]*newsletterEngagementForDay)
+	for _, d := range byDay {
+		date := d.Day.UTC().Format(engagementDateFormat)
+		day, ok := daysByDate[date]
+		if !ok {
+			day = &newsletterEngagementForDay{Day: date}
+			daysByDate[date] = day
+		}
+		switch d.EventType {
+		case email.EngagementEventTypeSent:
+			day.Sent = d.NumberOfEmails
+		case email.EngagementEventTypeDelivered:
+			day.Delivered = d.NumberOfEmails
+		case email.EngagementEventTypeOpened:
+			day.Opened = d.NumberOfEmails
+		case email.EngagementEventTypeClicked:
+			day.Clicked = d.NumberOfEmails
+		case email.EngagementEventTypeUnsubscribed:
+			day.Unsubscribed = d.NumberOfEmails
+		case email.EngagementEventTypeBounced:
+			day.Bounced = d.NumberOfEmails
+		case email.EngagementEventTypeDeliveryDelayed:
+			day.DeliveryDelayed = d.NumberOfEmails
+		case email.EngagementEventTypeRejected:
+			day.Rejected = d.NumberOfEmails
+		default:
+			r.Warnf("Unrecognized engagement event type %s", d.EventType)
+		}
+	}
+	var days []newsletterEngagementForDay
+	for _, day := range daysByDate {
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Day < days[j].Day
+	})
+	return getNewsletterEngagementResponse{
+		Days:     days,
+		Sections: bySection,
+	}, nil
+}
@@ -19,6 +19,12 @@ var Routes = router.RouteGroup{
 				admin.PermissionViewUserMetrics,
 				getUserAggregationByStatus,
 			),
+		}, {
+			Path: "get_newsletter_engagement_1",
+			Handler: middleware.WithPermission(
+				admin.PermissionViewUserMetrics,
+				getNewsletterEngagement,
+			),
 		},
 	},
 }
@@ -28,6 +34,7 @@ type getUserAggregationByStatusResponse struct {
 	UnsubscribedUserCount               int64 `json:"unsubscribed_user_count"`
 	UnverifiedUserCount                 int64 `json:"unverified_user_count"`
 	BlocklistedUserCount                int64 `json:"blocklisted_user_count"`
+	ErasedUserCount                     int64 `json:"erased_user_count"`
 	VerifiedUserCountNetChangeOverWeek  int64 `json:"verified_user_count_net_change_over_week"`
 	VerifiedUserCountNetChangeOverMonth int64 `json:"verified_user_count_net_change_over_month"`
 }
@@ -55,7 +62,9 @@ func getUserAggregationByStatus(adminID admin.ID, r *router.Request) (interface{
 			resp.UnsubscribedUserCount = statusCount.Count
 		case users.UserStatusBlocklistBounced,
 			users.UserStatusBlocklistComplaint:
-			resp.BlocklistedUserCount = statusCount.Count
+			resp.BlocklistedUserCount += statusCount.Count
+		case users.UserStatusErased:
+			resp.ErasedUserCount = statusCount.Count
 		}
 	}
 	return resp, nil
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/ptr"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WithAuditedPermission is WithPermission for routes that change anything.
+// The audit entry is written before the handler runs, so if it can't be
+// recorded, the request is rejected instead of going through unaudited.
+func WithAuditedPermission(requiredPermission admin.Permission, handler AuthenticatedRequestHandler) router.RequestHandler {
+	return WithPermission(requiredPermission, func(adminID admin.ID, r *router.Request) (interface{}, error) {
+		return handleAuditedRequest(adminID, requiredPermission.Ptr(), r, handler)
+	})
+}
+
+// WithAuditedAuthentication is WithAuthentication for routes that
+// change anything, like an admin's own two factor settings
+func WithAuditedAuthentication(handler AuthenticatedRequestHandler) router.RequestHandler {
+	return WithAuthentication(func(adminID admin.ID, r *router.Request) (interface{}, error) {
+		return handleAuditedRequest(adminID, nil, r, handler)
+	})
+}
+
+func handleAuditedRequest(adminID admin.ID, permission *admin.Permission, r *router.Request, handler AuthenticatedRequestHandler) (interface{}, error) {
+	body, err := r.GetBodyAsBytes()
+	if err != nil {
+		return nil, err
+	}
+	var auditLogEntryID *admin.AuditLogEntryID
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		auditLogEntryID, err = admin.CreateAuditLogEntry(tx, admin.CreateAuditLogEntryInput{
+			AdminUserID: adminID,
+			Route:       r.GetPath(),
+			Permission:  permission,
+			RequestBody: body,
+		})
+		return err
+	}); err != nil {
+		r.Errorf("Error creating audit log entry for admin %s on %s: %s", adminID, r.GetPath(), err.Error())
+		return nil, err
+	}
+	resp, handlerErr := handler(adminID, r)
+	var result admin.AuditLogResult
+	var errorMessage *string
+	if handlerErr != nil {
+		result = admin.AuditLogResultError
+		errorMessage = ptr.String(handlerErr.Error())
+	} else {
+		result, errorMessage = getAuditLogResultForResponse(r.GetResponseStatus(), resp)
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return admin.CompleteAuditLogEntry(tx, *auditLogEntryID, result, errorMessage)
+	}); err != nil {
+		// The change has already happened at this point, so the
+		// response still goes out and the entry is left as pending
+		r.Errorf("Error completing audit log entry %s: %s", *auditLogEntryID, err.Error())
+	}
+	return resp, handlerErr
+}
+
+type auditedResponse struct {
+	Success *bool       `json:"success"`
+	Error   interface{} `json:"error"`
+}
+
+// Handlers report most failures in the response instead of as errors,
+// either with "success": false or with an "error" field, so the response
+// has to be checked to know whether the request actually went through
+func getAuditLogResultForResponse(status *int, resp interface{}) (admin.AuditLogResult, *string) {
+	if status != nil && *status >= http.StatusBadRequest {
+		return admin.AuditLogResultFailure, ptr.String(fmt.Sprintf("Responded with status %d", *status))
+	}
+	if resp == nil {
+		return admin.AuditLogResultSuccess, nil
+	}
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		return admin.AuditLogResultSuccess, nil
+	}
+	var r auditedResponse
+	if err := json.Unmarshal(encoded, &r); err != nil {
+		// Responses that aren't JSON objects have no way to report a failure
+		return admin.AuditLogResultSuccess, nil
+	}
+	switch {
+	case r.Error != nil:
+		return admin.AuditLogResultFailure, ptr.String(fmt.Sprintf("Responded with error %v", r.Error))
+	case r.Success != nil && !*r.Success:
+		return admin.AuditLogResultFailure, ptr.String("Responded with success false")
+	default:
+		return admin.AuditLogResultSuccess, nil
+	}
+}
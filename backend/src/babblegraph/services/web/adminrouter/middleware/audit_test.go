@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"babblegraph/model/admin"
+	"net/http"
+	"testing"
+)
+
+func TestGetAuditLogResultForResponse(t *testing.T) {
+	type successResponse struct {
+		Success bool `json:"success"`
+	}
+	type errorResponse struct {
+		Error *string `json:"error,omitempty"`
+	}
+	forbidden := http.StatusForbidden
+	created := http.StatusCreated
+	invalidCode := "invalid-code"
+	type testCase struct {
+		status   *int
+		resp     interface{}
+		expected admin.AuditLogResult
+	}
+	for idx, tc := range []testCase{
+		{resp: successResponse{Success: true}, expected: admin.AuditLogResultSuccess},
+		{resp: successResponse{Success: false}, expected: admin.AuditLogResultFailure},
+		{resp: &successResponse{Success: false}, expected: admin.AuditLogResultFailure},
+		{resp: errorResponse{}, expected: admin.AuditLogResultSuccess},
+		{resp: errorResponse{Error: &invalidCode}, expected: admin.AuditLogResultFailure},
+		{resp: []string{"not", "an", "object"}, expected: admin.AuditLogResultSuccess},
+		{resp: nil, expected: admin.AuditLogResultSuccess},
+		{status: &forbidden, resp: nil, expected: admin.AuditLogResultFailure},
+		{status: &created, resp: successResponse{Success: true}, expected: admin.AuditLogResultSuccess},
+	} {
+		result, errorMessage := getAuditLogResultForResponse(tc.status, tc.resp)
+		if result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+		if result == admin.AuditLogResultFailure && errorMessage == nil {
+			t.Errorf("Error on test case %d: expected an error message for a failure", idx)
+		}
+	}
+}
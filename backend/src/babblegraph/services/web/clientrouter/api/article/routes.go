@@ -51,7 +51,7 @@ func getArticleMetadata(r *router.Request) (interface{}, error) {
 	var hasSeenReaderTutorial bool
 	var articleID string
 	var userID *users.UserID
-	if err := encrypt.WithDecodedToken(req.ArticleToken, func(tokenPair encrypt.TokenPair) error {
+	if err := encrypt.WithDecodedTokenForKeys(req.ArticleToken, []string{routes.ArticleLinkKeyDEPRECATED.Str(), routes.ArticleLinkKeyForUserDocumentID.Str()}, func(tokenPair encrypt.TokenPair) error {
 		switch {
 		case tokenPair.Key == routes.ArticleLinkKeyDEPRECATED.Str():
 			return nil
@@ -35,6 +35,16 @@ var Routes = router.RouteGroup{
 			Handler: routermiddleware.WithRequestBodyLogger(
 				routermiddleware.WithAuthentication(setPremiumNewsletterSubscriptionAutoRenew),
 			),
+		}, {
+			Path: "get_available_plans_1",
+			Handler: routermiddleware.WithRequestBodyLogger(
+				routermiddleware.WithAuthentication(getAvailablePlans),
+			),
+		}, {
+			Path: "switch_premium_newsletter_subscription_plan_1",
+			Handler: routermiddleware.WithRequestBodyLogger(
+				routermiddleware.WithAuthentication(switchPremiumNewsletterSubscriptionPlan),
+			),
 		}, {
 			Path: "prepare_premium_newsletter_subscription_sync_1",
 			Handler: routermiddleware.WithRequestBodyLogger(
@@ -106,7 +116,8 @@ func getOrCreateBillingInformation(userAuth routermiddleware.UserAuthentication,
 }
 
 type getOrCreatePremiumNewsletterSubscriptionRequest struct {
-	PremiumSubscriptionCheckoutToken string `json:"premium_subscription_checkout_token"`
+	PremiumSubscriptionCheckoutToken string  `json:"premium_subscription_checkout_token"`
+	PlanType                         *string `json:"plan_type,omitempty"`
 }
 
 type getOrCreatePremiumNewsletterSubscriptionResponse struct {
@@ -123,6 +134,15 @@ func getOrCreatePremiumNewsletterSubscription(userAuth routermiddleware.UserAuth
 		r.RespondWithStatus(http.StatusForbidden)
 		return nil, nil
 	}
+	planType := billing.DefaultPlanType
+	if req.PlanType != nil {
+		requestedPlanType, err := billing.GetPlanTypeForString(*req.PlanType)
+		if err != nil {
+			r.RespondWithStatus(http.StatusBadRequest)
+			return nil, nil
+		}
+		planType = *requestedPlanType
+	}
 	premiumNewsletterSubscriptionID := billing.NewPremiumNewsletterSubscriptionID()
 	var premiumNewsletterSubscription *billing.PremiumNewsletterSubscription
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
@@ -137,7 +157,7 @@ func getOrCreatePremiumNewsletterSubscription(userAuth routermiddleware.UserAuth
 		if err := billing.InsertPremiumNewsletterSyncRequest(tx, premiumNewsletterSubscriptionID, billing.PremiumNewsletterSubscriptionUpdateTypeTransitionToActive); err != nil {
 			return err
 		}
-		premiumNewsletterSubscription, err = billing.CreatePremiumNewsletterSubscriptionForUserWithID(r, tx, *userID, premiumNewsletterSubscriptionID)
+		premiumNewsletterSubscription, err = billing.CreatePremiumNewsletterSubscriptionForUserWithID(r, tx, *userID, premiumNewsletterSubscriptionID, planType)
 		// THIS IS A HACK
 		premiumNewsletterSubscription.ID = &premiumNewsletterSubscriptionID
 		return err
@@ -209,6 +229,67 @@ func setPremiumNewsletterSubscriptionAutoRenew(userAuth routermiddleware.UserAut
 	}, nil
 }
 
+type getAvailablePlansRequest struct{}
+
+type getAvailablePlansResponse struct {
+	Plans []billing.Plan `json:"plans"`
+}
+
+func getAvailablePlans(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var plans []billing.Plan
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		plans, err = billing.GetAvailablePlansForUser(tx, userAuth.UserID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getAvailablePlansResponse{
+		Plans: plans,
+	}, nil
+}
+
+type switchPremiumNewsletterSubscriptionPlanRequest struct {
+	SubscriptionManagementToken string `json:"subscription_management_token"`
+	PlanType                    string `json:"plan_type"`
+}
+
+type switchPremiumNewsletterSubscriptionPlanResponse struct {
+	Success bool `json:"success"`
+}
+
+func switchPremiumNewsletterSubscriptionPlan(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req switchPremiumNewsletterSubscriptionPlanRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	userID, err := routetoken.ValidateTokenAndGetUserID(req.SubscriptionManagementToken, routes.SubscriptionManagementRouteEncryptionKey)
+	if err != nil || *userID != userAuth.UserID {
+		r.RespondWithStatus(http.StatusForbidden)
+		return nil, nil
+	}
+	planType, err := billing.GetPlanTypeForString(req.PlanType)
+	if err != nil {
+		return switchPremiumNewsletterSubscriptionPlanResponse{
+			Success: false,
+		}, nil
+	}
+	var planSwitch *billing.PremiumNewsletterSubscriptionPlanSwitch
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		planSwitch, err = billing.PreparePremiumNewsletterSubscriptionPlanSwitch(tx, *userID, *planType)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if err := billing.ApplyPremiumNewsletterSubscriptionPlanSwitch(r, *planSwitch); err != nil {
+		return nil, err
+	}
+	return switchPremiumNewsletterSubscriptionPlanResponse{
+		Success: true,
+	}, nil
+}
+
 type preparePremiumNewsletterSubscriptionSyncRequest struct {
 	ID         billing.PremiumNewsletterSubscriptionID `json:"id"`
 	UpdateType string                                  `json:"update_type"`
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -27,6 +28,13 @@ var Routes = router.RouteGroup{
 			Handler: routermiddleware.WithNoBodyRequestLogger(
 				routermiddleware.MaybeWithAuthentication(searchText),
 			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            routermiddleware.RateLimitKeyUserIDOrIPAddress,
+					Capacity:       30,
+					RefillInterval: 2 * time.Second,
+				},
+			},
 		},
 	},
 }
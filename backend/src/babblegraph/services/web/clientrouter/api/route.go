@@ -3,6 +3,7 @@ package api
 import (
 	"babblegraph/model/useraccounts"
 	"babblegraph/model/users"
+	"babblegraph/services/web/router"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -14,6 +15,7 @@ type Route struct {
 	Handler          RouteHandler
 	ShouldLogBody    bool
 	TrackEventWithID *string
+	RateLimits       []router.RateLimit
 }
 
 type RouteHandler func(reqBody []byte) (_resp interface{}, _err error)
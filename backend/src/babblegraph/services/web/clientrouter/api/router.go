@@ -3,6 +3,7 @@ package api
 import (
 	"babblegraph/model/users"
 	"babblegraph/services/web/clientrouter/middleware"
+	"babblegraph/services/web/router"
 	"fmt"
 	"net/http"
 
@@ -52,6 +53,7 @@ func RegisterRouteGroup(rg RouteGroup) error {
 		if err := registerRoute(registerRouteInput{
 			shouldLogBody:    r.ShouldLogBody,
 			trackEventWithID: r.TrackEventWithID,
+			rateLimits:       r.RateLimits,
 			muxRoute:         makeMuxRouter(r.Handler),
 			path:             fmt.Sprintf("/%s/%s/%s", apiPrefix, rg.Prefix, r.Path),
 		}); err != nil {
@@ -95,6 +97,7 @@ func RegisterRouteWithoutWrapper(routeGroupPrefix string, path string, handler f
 type registerRouteInput struct {
 	shouldLogBody    bool
 	trackEventWithID *string
+	rateLimits       []router.RateLimit
 	path             string
 	muxRoute         func(http.ResponseWriter, *http.Request)
 }
@@ -112,6 +115,9 @@ func registerRoute(input registerRouteInput) error {
 	if input.trackEventWithID != nil {
 		muxRoute = middleware.WithTrackingIDCapture(*input.trackEventWithID, muxRoute)
 	}
+	if len(input.rateLimits) != 0 {
+		muxRoute = router.WithHTTPRateLimits(input.path, input.rateLimits, muxRoute)
+	}
 	a.r.HandleFunc(input.path, a.sentryHandler.HandleFunc(muxRoute)).Methods("POST")
 	a.routeNames[input.path] = true
 	return nil
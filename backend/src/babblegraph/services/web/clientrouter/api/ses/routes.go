@@ -2,17 +2,19 @@ package ses
 
 import (
 	"babblegraph/model/billing"
+	"babblegraph/model/email"
 	"babblegraph/model/sesnotifications"
 	"babblegraph/model/users"
 	"babblegraph/services/web/clientrouter/api"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
+	"babblegraph/util/env"
 	"babblegraph/util/ses"
 	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/getsentry/sentry-go"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -26,6 +28,9 @@ func RegisterRouteGroups() error {
 			}, {
 				Path:    "handle_complaint_notification_1",
 				Handler: handleComplaintNotification,
+			}, {
+				Path:    "handle_delivery_notification_1",
+				Handler: handleDeliveryNotification,
 			},
 		},
 	})
@@ -33,121 +38,206 @@ func RegisterRouteGroups() error {
 
 type sesNotificationResponse struct{}
 
-func handleBounceNotification(body []byte) (interface{}, error) {
+// parseNotification returns a nil body for
+// subscription confirmations, which have nothing to process
+func parseNotification(body []byte) (*ses.Notification, *ses.NotificationBody, error) {
 	var req ses.Notification
 	if err := json.Unmarshal(body, &req); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	switch env.MustEnvironmentName() {
+	case env.EnvironmentLocal,
+		env.EnvironmentLocalNoEmail,
+		env.EnvironmentLocalTestEmail:
+		// SNS can't reach local environments, so
+		// notifications there are always hand-crafted
+	default:
+		if err := ses.VerifyNotificationSignature(req); err != nil {
+			return nil, nil, fmt.Errorf("Error verifying SNS signature: %s", err.Error())
+		}
 	}
 	switch {
 	case req.SubscribeURL != nil:
 		log.Println(fmt.Sprintf("Got subscription confirmation with URL: %s", *req.SubscribeURL))
-		return nil, nil
+		return &req, nil, nil
 	case req.Message != nil:
 		var b ses.NotificationBody
 		if err := json.Unmarshal([]byte(*req.Message), &b); err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		return &req, &b, nil
+	default:
+		return nil, nil, fmt.Errorf("Notification does not have a body")
+	}
+}
+
+func handleBounceNotification(body []byte) (interface{}, error) {
+	req, b, err := parseNotification(body)
+	switch {
+	case err != nil:
+		return nil, err
+	case b == nil:
+		return nil, nil
+	case b.Bounce == nil:
+		return nil, fmt.Errorf("Bounce does not have a body")
+	}
+	c := ctx.GetDefaultLogContext()
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		if err := sesnotifications.InsertSESNotification(tx, *req); err != nil {
+			return err
 		}
-		if err := database.WithTx(func(tx *sqlx.Tx) error {
-			return sesnotifications.InsertSESNotification(tx, req)
-		}); err != nil {
-			return nil, err
+		isFirstTime, err := sesnotifications.MarkNotificationAsProcessed(tx, b.Mail.OriginalMessageID, ses.NotificationTypeBounce)
+		switch {
+		case err != nil:
+			return err
+		case !isFirstTime:
+			log.Println(fmt.Sprintf("Already processed bounce for SES message ID %s", b.Mail.OriginalMessageID))
+			return nil
+		}
+		if _, err := email.RecordEngagementEventForSESMessageID(tx, b.Mail.OriginalMessageID, email.EngagementEventTypeBounced); err != nil {
+			return err
 		}
-		c := ctx.GetDefaultLogContext()
 		for _, recipient := range b.Bounce.BouncedRecipients {
-			if err := database.WithTx(func(tx *sqlx.Tx) error {
-				didUpdate, err := users.AddUserToBlocklistByEmailAddress(tx, recipient.EmailAddress, users.UserStatusBlocklistBounced)
-				if err != nil {
-					return fmt.Errorf("Error on adding %s to bounce list: %s", recipient.EmailAddress, err.Error())
-				}
-				if didUpdate {
-					log.Println(fmt.Sprintf("Successfully added %s to bounce list", recipient.EmailAddress))
-					user, err := users.LookupUserByEmailAddress(tx, recipient.EmailAddress)
-					switch {
-					case err != nil:
-						return fmt.Errorf("Error finding user %s: %s", recipient.EmailAddress, err.Error())
-					case user == nil:
-						log.Println(fmt.Sprintf("No user found %s", recipient.EmailAddress))
-					default:
-						subscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
-						switch {
-						case err != nil:
-							return fmt.Errorf("error cancelling subscription for user %s: %s", recipient.EmailAddress, err.Error())
-						case subscription == nil:
-							// no-op
-						default:
-							return billing.CancelPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
-						}
-					}
-				} else {
-					log.Println(fmt.Sprintf("Did not add %s to bounce list", recipient.EmailAddress))
-				}
-				return nil
-			}); err != nil {
-				sentry.CaptureException(err)
+			shouldBlocklist, err := sesnotifications.RecordBounceForRecipient(tx, sesnotifications.RecordBounceForRecipientInput{
+				EmailAddress: recipient.EmailAddress,
+				SESMessageID: b.Mail.OriginalMessageID,
+				Bounce:       *b.Bounce,
+			})
+			switch {
+			case err != nil:
+				return fmt.Errorf("Error recording bounce for %s: %s", recipient.EmailAddress, err.Error())
+			case !shouldBlocklist:
+				log.Println(fmt.Sprintf("Got %s bounce for %s, not adding to bounce list yet", b.Bounce.Type, recipient.EmailAddress))
 				continue
 			}
+			if err := addRecipientToBlocklist(c, tx, recipient.EmailAddress, users.UserStatusBlocklistBounced); err != nil {
+				return err
+			}
 		}
-		return sesNotificationResponse{}, nil
-	default:
-		return nil, fmt.Errorf("Bounce does not have a body")
+		return nil
+	}); err != nil {
+		return nil, err
 	}
+	return sesNotificationResponse{}, nil
 }
 
 func handleComplaintNotification(body []byte) (interface{}, error) {
-	var req ses.Notification
-	if err := json.Unmarshal(body, &req); err != nil {
-		return nil, err
-	}
+	req, b, err := parseNotification(body)
 	switch {
-	case req.SubscribeURL != nil:
-		log.Println(fmt.Sprintf("Got subscription confirmation with URL: %s", *req.SubscribeURL))
+	case err != nil:
+		return nil, err
+	case b == nil:
 		return nil, nil
-	case req.Message != nil:
-		var b ses.NotificationBody
-		if err := json.Unmarshal([]byte(*req.Message), &b); err != nil {
-			return nil, err
+	case b.Complaint == nil:
+		return nil, fmt.Errorf("Complaint does not have a body")
+	}
+	c := ctx.GetDefaultLogContext()
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		if err := sesnotifications.InsertSESNotification(tx, *req); err != nil {
+			return err
 		}
-		if err := database.WithTx(func(tx *sqlx.Tx) error {
-			return sesnotifications.InsertSESNotification(tx, req)
-		}); err != nil {
-			sentry.CaptureException(fmt.Errorf("Error persisting SES notification: %s. Continuing...", err.Error()))
+		isFirstTime, err := sesnotifications.MarkNotificationAsProcessed(tx, b.Mail.OriginalMessageID, ses.NotificationTypeComplaint)
+		switch {
+		case err != nil:
+			return err
+		case !isFirstTime:
+			log.Println(fmt.Sprintf("Already processed complaint for SES message ID %s", b.Mail.OriginalMessageID))
+			return nil
 		}
-		c := ctx.GetDefaultLogContext()
 		for _, recipient := range b.Complaint.ComplainedRecipients {
-			if err := database.WithTx(func(tx *sqlx.Tx) error {
-				didUpdate, err := users.AddUserToBlocklistByEmailAddress(tx, recipient.EmailAddress, users.UserStatusBlocklistComplaint)
-				if err != nil {
-					return fmt.Errorf("Error on adding %s to complaint list: %s", recipient.EmailAddress, err.Error())
-				}
-				if didUpdate {
-					log.Println(fmt.Sprintf("Successfully added %s to complaint list", recipient.EmailAddress))
-					user, err := users.LookupUserByEmailAddress(tx, recipient.EmailAddress)
-					switch {
-					case err != nil:
-						return fmt.Errorf("Error finding user %s: %s", recipient.EmailAddress, err.Error())
-					case user == nil:
-						log.Println(fmt.Sprintf("No user found %s", recipient.EmailAddress))
-					default:
-						subscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
-						switch {
-						case err != nil:
-							return fmt.Errorf("error cancelling subscription for user %s: %s", recipient.EmailAddress, err.Error())
-						case subscription == nil:
-							// no-op
-						default:
-							return billing.CancelPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
-						}
-					}
-				} else {
-					log.Println(fmt.Sprintf("Did not add %s to complaint list", recipient.EmailAddress))
+			if err := addRecipientToBlocklist(c, tx, recipient.EmailAddress, users.UserStatusBlocklistComplaint); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return sesNotificationResponse{}, nil
+}
+
+// handleDeliveryNotification handles every notification about
+// whether an email reached the recipient's server: deliveries,
+// delivery delays and rejects.
+func handleDeliveryNotification(body []byte) (interface{}, error) {
+	_, b, err := parseNotification(body)
+	switch {
+	case err != nil:
+		return nil, err
+	case b == nil:
+		return nil, nil
+	}
+	notificationType := b.GetType()
+	var eventType email.EngagementEventType
+	switch notificationType {
+	case ses.NotificationTypeDelivery:
+		eventType = email.EngagementEventTypeDelivered
+	case ses.NotificationTypeDeliveryDelay:
+		eventType = email.EngagementEventTypeDeliveryDelayed
+	case ses.NotificationTypeReject:
+		eventType = email.EngagementEventTypeRejected
+	default:
+		return nil, fmt.Errorf("Unsupported notification type %s", notificationType)
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		isFirstTime, err := sesnotifications.MarkNotificationAsProcessed(tx, b.Mail.OriginalMessageID, notificationType)
+		switch {
+		case err != nil:
+			return err
+		case !isFirstTime:
+			log.Println(fmt.Sprintf("Already processed %s for SES message ID %s", notificationType, b.Mail.OriginalMessageID))
+			return nil
+		}
+		if notificationType == ses.NotificationTypeDelivery {
+			deliveredAt := time.Now()
+			if b.Delivery != nil {
+				if t, err := time.Parse(time.RFC3339, b.Delivery.TimestampISO8601); err == nil {
+					deliveredAt = t
 				}
-				return nil
-			}); err != nil {
-				return nil, err
+			}
+			if _, err := email.SetEmailRecordDeliveredForSESMessageID(tx, b.Mail.OriginalMessageID, deliveredAt); err != nil {
+				return err
 			}
 		}
-		return sesNotificationResponse{}, nil
+		didRecord, err := email.RecordEngagementEventForSESMessageID(tx, b.Mail.OriginalMessageID, eventType)
+		if err != nil {
+			return err
+		}
+		if !didRecord {
+			log.Println(fmt.Sprintf("No email record found for SES message ID %s", b.Mail.OriginalMessageID))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return sesNotificationResponse{}, nil
+}
+
+func addRecipientToBlocklist(c ctx.LogContext, tx *sqlx.Tx, emailAddress string, status users.UserStatus) error {
+	didUpdate, err := users.AddUserToBlocklistByEmailAddress(tx, emailAddress, status)
+	switch {
+	case err != nil:
+		return fmt.Errorf("Error on adding %s to %s list: %s", emailAddress, status, err.Error())
+	case !didUpdate:
+		log.Println(fmt.Sprintf("Did not add %s to %s list", emailAddress, status))
+		return nil
+	}
+	log.Println(fmt.Sprintf("Successfully added %s to %s list", emailAddress, status))
+	user, err := users.LookupUserByEmailAddress(tx, emailAddress)
+	switch {
+	case err != nil:
+		return fmt.Errorf("Error finding user %s: %s", emailAddress, err.Error())
+	case user == nil:
+		log.Println(fmt.Sprintf("No user found %s", emailAddress))
+		return nil
+	}
+	subscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
+	switch {
+	case err != nil:
+		return fmt.Errorf("error cancelling subscription for user %s: %s", emailAddress, err.Error())
+	case subscription == nil:
+		return nil
 	default:
-		return nil, fmt.Errorf("Complaint does not have a body")
+		return billing.CancelPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
 	}
 }
@@ -0,0 +1,120 @@
+package user
+
+import (
+	"babblegraph/model/newsletterarchive"
+	"babblegraph/model/routes"
+	"babblegraph/model/useraccounts"
+	"babblegraph/services/web/clientrouter/clienterror"
+	"babblegraph/services/web/clientrouter/routermiddleware"
+	"babblegraph/services/web/clientrouter/util/routetoken"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/wordsmith"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	newsletterArchiveDateFormat      = "2006-01-02"
+	defaultNewsletterArchiveLookback = 90 * 24 * time.Hour
+
+	errorInvalidDate clienterror.Error = "invalid-date"
+)
+
+type getNewsletterArchiveRequest struct {
+	SubscriptionManagementToken string `json:"subscription_management_token"`
+	LanguageCode                string `json:"language_code"`
+	// Both are formatted as YYYY-MM-DD and
+	// default to the last 90 days
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+}
+
+type getNewsletterArchiveResponse struct {
+	Issues []newsletterArchiveIssue `json:"issues,omitempty"`
+	Error  *clienterror.Error       `json:"error,omitempty"`
+}
+
+type newsletterArchiveIssue struct {
+	ID         newsletterarchive.ID `json:"id"`
+	DateOfSend time.Time            `json:"date_of_send"`
+	ViewURL    string               `json:"view_url"`
+}
+
+func getNewsletterArchive(userAuth *routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req getNewsletterArchiveRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	userID, err := routetoken.ValidateTokenAndGetUserID(req.SubscriptionManagementToken, routes.SubscriptionManagementRouteEncryptionKey)
+	if err != nil {
+		return getNewsletterArchiveResponse{
+			Error: clienterror.ErrorInvalidToken.Ptr(),
+		}, nil
+	}
+	languageCode, err := wordsmith.GetLanguageCodeFromString(req.LanguageCode)
+	if err != nil {
+		return getNewsletterArchiveResponse{
+			Error: clienterror.ErrorInvalidLanguageCode.Ptr(),
+		}, nil
+	}
+	endDate := time.Now().UTC()
+	if req.EndDate != nil {
+		endDate, err = time.Parse(newsletterArchiveDateFormat, *req.EndDate)
+		if err != nil {
+			return getNewsletterArchiveResponse{
+				Error: errorInvalidDate.Ptr(),
+			}, nil
+		}
+		// The end date is inclusive for users
+		endDate = endDate.Add(24 * time.Hour)
+	}
+	startDate := endDate.Add(-1 * defaultNewsletterArchiveLookback)
+	if req.StartDate != nil {
+		startDate, err = time.Parse(newsletterArchiveDateFormat, *req.StartDate)
+		if err != nil {
+			return getNewsletterArchiveResponse{
+				Error: errorInvalidDate.Ptr(),
+			}, nil
+		}
+	}
+	var entries []newsletterarchive.ArchiveEntry
+	var doesUserHaveAccount bool
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		doesUserHaveAccount, err = useraccounts.DoesUserAlreadyHaveAccount(tx, *userID)
+		if err != nil {
+			return err
+		}
+		entries, err = newsletterarchive.GetArchiveEntriesForUser(tx, *userID, *languageCode, startDate, endDate)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	switch {
+	case userAuth != nil && userAuth.UserID != *userID:
+		return getNewsletterArchiveResponse{
+			Error: clienterror.ErrorIncorrectKey.Ptr(),
+		}, nil
+	case userAuth == nil && doesUserHaveAccount:
+		return getNewsletterArchiveResponse{
+			Error: clienterror.ErrorNoAuth.Ptr(),
+		}, nil
+	}
+	var issues []newsletterArchiveIssue
+	for _, e := range entries {
+		viewURL, err := routes.MakeNewsletterViewInBrowserLink(string(e.NewsletterSendRequestID))
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, newsletterArchiveIssue{
+			ID:         e.ID,
+			DateOfSend: e.DateOfSend,
+			ViewURL:    *viewURL,
+		})
+	}
+	return getNewsletterArchiveResponse{
+		Issues: issues,
+	}, nil
+}
@@ -26,6 +26,13 @@ type userSchedule struct {
 	HourIndex        int    `json:"hour_index"`
 	QuarterHourIndex int    `json:"quarter_hour_index"`
 	IsActiveForDays  []bool `json:"is_active_for_days"`
+	// When this is set, the newsletter is sent shortly before the time
+	// the user usually reads it, falling back to the hour and quarter hour above
+	IsBestTimeEnabled bool `json:"is_best_time_enabled"`
+	// When the cadence is a weekly digest, IsActiveForDays is kept
+	// but ignored, and the digest goes out on WeeklyDigestDayOfWeekIndex
+	NewsletterCadence          *usernewsletterpreferences.NewsletterCadence `json:"newsletter_cadence,omitempty"`
+	WeeklyDigestDayOfWeekIndex int                                          `json:"weekly_digest_day_of_week_index"`
 }
 
 type userNewsletterPreferences struct {
@@ -97,10 +104,13 @@ func getUserNewsletterPreferences(userAuth *routermiddleware.UserAuthentication,
 		IsLemmaReinforcementSpotlightActive: prefs.ShouldIncludeLemmaReinforcementSpotlight,
 		NumberOfArticlesPerEmail:            schedule.NumberOfArticlesPerEmail,
 		Schedule: userSchedule{
-			IANATimezone:     schedule.IANATimezone,
-			HourIndex:        schedule.HourIndex,
-			QuarterHourIndex: schedule.QuarterHourIndex,
-			IsActiveForDays:  schedule.IsActiveForDay,
+			IANATimezone:               schedule.IANATimezone,
+			HourIndex:                  schedule.HourIndex,
+			QuarterHourIndex:           schedule.QuarterHourIndex,
+			IsActiveForDays:            schedule.IsActiveForDay,
+			IsBestTimeEnabled:          schedule.IsBestTimeEnabled,
+			NewsletterCadence:          schedule.Cadence.Ptr(),
+			WeeklyDigestDayOfWeekIndex: schedule.WeeklyDigestDayOfWeekIndex,
 		},
 	}
 	switch {
@@ -158,6 +168,7 @@ const (
 	errorEmptyEmailAddress clienterror.Error = "no-email-address"
 	errorInvalidTimezone   clienterror.Error = "invalid-timezone"
 	errorNoActiveDay       clienterror.Error = "no-active-day"
+	errorInvalidCadence    clienterror.Error = "invalid-cadence"
 )
 
 func updateUserNewsletterPreferences(userAuth *routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
@@ -183,6 +194,21 @@ func updateUserNewsletterPreferences(userAuth *routermiddleware.UserAuthenticati
 			Error: errorInvalidTimezone.Ptr(),
 		}, nil
 	}
+	cadence := usernewsletterpreferences.NewsletterCadenceDaily
+	if req.Preferences.Schedule.NewsletterCadence != nil {
+		parsedCadence, err := usernewsletterpreferences.GetNewsletterCadenceFromString(req.Preferences.Schedule.NewsletterCadence.Str())
+		if err != nil {
+			return getUserNewsletterPreferencesResponse{
+				Error: errorInvalidCadence.Ptr(),
+			}, nil
+		}
+		cadence = *parsedCadence
+	}
+	if weeklyDigestDay := req.Preferences.Schedule.WeeklyDigestDayOfWeekIndex; weeklyDigestDay < 0 || weeklyDigestDay > 6 {
+		return getUserNewsletterPreferencesResponse{
+			Error: errorInvalidCadence.Ptr(),
+		}, nil
+	}
 	var hasAtLeastOneActiveDay bool
 	for _, isActive := range req.Preferences.Schedule.IsActiveForDays {
 		hasAtLeastOneActiveDay = hasAtLeastOneActiveDay || isActive
@@ -221,6 +247,9 @@ func updateUserNewsletterPreferences(userAuth *routermiddleware.UserAuthenticati
 				HourIndex:                           req.Preferences.Schedule.HourIndex,
 				QuarterHourIndex:                    req.Preferences.Schedule.QuarterHourIndex,
 				IsActiveForDays:                     req.Preferences.Schedule.IsActiveForDays,
+				IsBestTimeEnabled:                   req.Preferences.Schedule.IsBestTimeEnabled,
+				Cadence:                             cadence,
+				WeeklyDigestDayOfWeekIndex:          req.Preferences.Schedule.WeeklyDigestDayOfWeekIndex,
 				NumberOfArticlesPerEmail:            req.Preferences.NumberOfArticlesPerEmail,
 			})
 		}); err != nil {
@@ -259,6 +288,9 @@ func updateUserNewsletterPreferences(userAuth *routermiddleware.UserAuthenticati
 				HourIndex:                           req.Preferences.Schedule.HourIndex,
 				QuarterHourIndex:                    req.Preferences.Schedule.QuarterHourIndex,
 				IsActiveForDays:                     req.Preferences.Schedule.IsActiveForDays,
+				IsBestTimeEnabled:                   req.Preferences.Schedule.IsBestTimeEnabled,
+				Cadence:                             cadence,
+				WeeklyDigestDayOfWeekIndex:          req.Preferences.Schedule.WeeklyDigestDayOfWeekIndex,
 				NumberOfArticlesPerEmail:            req.Preferences.NumberOfArticlesPerEmail,
 			}
 			userSubscription, err := useraccounts.LookupSubscriptionLevelForUser(tx, *userID)
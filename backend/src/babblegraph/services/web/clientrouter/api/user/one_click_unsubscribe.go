@@ -0,0 +1,56 @@
+package user
+
+import (
+	"babblegraph/model/email"
+	"babblegraph/model/routes"
+	"babblegraph/model/users"
+	"babblegraph/services/web/clientrouter/util/routetoken"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// This is the target of the List-Unsubscribe header (RFC 8058).
+// Mail clients send a form encoded POST with List-Unsubscribe=One-Click
+// and no cookies, so the token in the URL is the only authentication.
+// That's enough to stop newsletter emails, but anything to do with
+// billing needs the user to be logged in, so it's left to the unsubscribe page.
+
+type oneClickUnsubscribeResponse struct {
+	Success bool `json:"success"`
+}
+
+func handleOneClickUnsubscribe(r *router.Request) (interface{}, error) {
+	token := r.GetQueryParam("token")
+	if token == nil || r.GetFormValue("List-Unsubscribe") != "One-Click" {
+		r.RespondWithStatus(http.StatusBadRequest)
+		return oneClickUnsubscribeResponse{
+			Success: false,
+		}, nil
+	}
+	userID, err := routetoken.ValidateTokenAndGetUserID(*token, routes.UnsubscribeRouteEncryptionKey)
+	if err != nil {
+		r.Warnf("Got invalid one-click unsubscribe token: %s", err.Error())
+		r.RespondWithStatus(http.StatusBadRequest)
+		return oneClickUnsubscribeResponse{
+			Success: false,
+		}, nil
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return unsubscribeUserAndRecordEngagement(tx, *userID)
+	}); err != nil {
+		return nil, err
+	}
+	return oneClickUnsubscribeResponse{
+		Success: true,
+	}, nil
+}
+
+func unsubscribeUserAndRecordEngagement(tx *sqlx.Tx, userID users.UserID) error {
+	if err := users.UnsubscribeUserByID(tx, userID); err != nil {
+		return err
+	}
+	return email.RecordUnsubscribeEngagementEvent(tx, userID)
+}
@@ -4,10 +4,12 @@ import (
 	"babblegraph/model/routes"
 	"babblegraph/model/users"
 	"babblegraph/services/web/clientrouter/api"
+	"babblegraph/services/web/router"
 	"babblegraph/util/database"
 	"babblegraph/util/encrypt"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -25,6 +27,17 @@ func RegisterRouteGroups() error {
 			}, {
 				Path:    "handle_request_password_reset_link_1",
 				Handler: requestPasswordResetLink,
+				RateLimits: []router.RateLimit{
+					{
+						Key:            router.RateLimitKeyIPAddress,
+						Capacity:       10,
+						RefillInterval: time.Minute,
+					}, {
+						Key:            router.RateLimitKeyJSONBodyField("email_address"),
+						Capacity:       3,
+						RefillInterval: 15 * time.Minute,
+					},
+				},
 			},
 		},
 	})
@@ -32,10 +45,7 @@ func RegisterRouteGroups() error {
 
 func parseSubscriptionManagementToken(token string, emailAddress *string) (*users.UserID, error) {
 	var userID users.UserID
-	if err := encrypt.WithDecodedToken(token, func(t encrypt.TokenPair) error {
-		if t.Key != routes.SubscriptionManagementRouteEncryptionKey.Str() {
-			return fmt.Errorf("incorrect key")
-		}
+	if err := encrypt.WithDecodedToken(token, routes.SubscriptionManagementRouteEncryptionKey.Str(), func(t encrypt.TokenPair) error {
 		userIDStr, ok := t.Value.(string)
 		if !ok {
 			return fmt.Errorf("incorrect type")
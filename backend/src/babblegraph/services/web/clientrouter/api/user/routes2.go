@@ -13,6 +13,7 @@ import (
 	"babblegraph/util/email"
 	"babblegraph/wordsmith"
 	"net/http"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -30,12 +31,28 @@ var Routes = router.RouteGroup{
 					routermiddleware.WithMaybePromotion(handleSignupUser),
 				),
 			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       10,
+					RefillInterval: time.Minute,
+				}, {
+					Key:            router.RateLimitKeyJSONBodyField("email_address"),
+					Capacity:       3,
+					RefillInterval: 10 * time.Minute,
+				},
+			},
 		},
 		{
 			Path: "unsubscribe_user_1",
 			Handler: routermiddleware.WithNoBodyRequestLogger(
 				routermiddleware.MaybeWithAuthentication(unsubscribeUser),
 			),
+		}, {
+			Path: "one_click_unsubscribe_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				handleOneClickUnsubscribe,
+			),
 		}, {
 			Path: "get_user_newsletter_preferences_1",
 			Handler: routermiddleware.WithNoBodyRequestLogger(
@@ -56,6 +73,11 @@ var Routes = router.RouteGroup{
 			Handler: routermiddleware.WithNoBodyRequestLogger(
 				routermiddleware.MaybeWithAuthentication(getUserVocabulary),
 			),
+		}, {
+			Path: "get_newsletter_archive_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.MaybeWithAuthentication(getNewsletterArchive),
+			),
 		},
 	},
 }
@@ -103,7 +125,7 @@ func unsubscribeUser(userAuth *routermiddleware.UserAuthentication, r *router.Re
 			}, nil
 		}
 		if err := database.WithTx(func(tx *sqlx.Tx) error {
-			if err := users.UnsubscribeUserByID(tx, *userID); err != nil {
+			if err := unsubscribeUserAndRecordEngagement(tx, *userID); err != nil {
 				return err
 			}
 			if req.UnsubscribeReason != nil && len(*req.UnsubscribeReason) != 0 {
@@ -180,7 +202,7 @@ func unsubscribeUser(userAuth *routermiddleware.UserAuthentication, r *router.Re
 			}
 			return billing.CancelPremiumNewsletterSubscriptionForUser(r, tx, *userID)
 		}
-		return users.UnsubscribeUserByID(tx, *userID)
+		return unsubscribeUserAndRecordEngagement(tx, *userID)
 	})
 	switch {
 	case err != nil:
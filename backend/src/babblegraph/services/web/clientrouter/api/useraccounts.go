@@ -4,8 +4,10 @@ import (
 	"babblegraph/model/routes"
 	"babblegraph/model/useraccounts"
 	"babblegraph/model/users"
+	"babblegraph/model/usersessions"
 	"babblegraph/services/web/clientrouter/middleware"
 	"babblegraph/services/web/clientrouter/util/routetoken"
+	"babblegraph/services/web/router"
 	"babblegraph/util/database"
 	"babblegraph/util/email"
 	"babblegraph/util/encrypt"
@@ -17,6 +19,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -24,10 +27,30 @@ import (
 func registerUserAccountsRoutes() {
 	a.prefixes["useraccounts"] = true
 
-	a.r.HandleFunc("/api/useraccounts/login_user_1", middleware.WithoutBodyLogger(loginUser))
+	a.r.HandleFunc("/api/useraccounts/login_user_1", middleware.WithoutBodyLogger(
+		router.WithHTTPRateLimits("/api/useraccounts/login_user_1", []router.RateLimit{
+			{
+				Key:            router.RateLimitKeyIPAddress,
+				Capacity:       20,
+				RefillInterval: 30 * time.Second,
+			}, {
+				Key:            router.RateLimitKeyJSONBodyField("email_address"),
+				Capacity:       5,
+				RefillInterval: 5 * time.Minute,
+			},
+		}, loginUser),
+	))
 	a.routeNames["/api/useraccounts/login_user_1"] = true
 
-	a.r.HandleFunc("/api/useraccounts/reset_password_1", middleware.WithoutBodyLogger(resetPassword))
+	a.r.HandleFunc("/api/useraccounts/reset_password_1", middleware.WithoutBodyLogger(
+		router.WithHTTPRateLimits("/api/useraccounts/reset_password_1", []router.RateLimit{
+			{
+				Key:            router.RateLimitKeyIPAddress,
+				Capacity:       10,
+				RefillInterval: time.Minute,
+			},
+		}, resetPassword),
+	))
 	a.routeNames["/api/useraccounts/reset_password_1"] = true
 
 	a.r.HandleFunc("/api/useraccounts/get_user_profile_1", middleware.WithoutBodyLogger(getUserProfile))
@@ -110,7 +133,7 @@ func loginUser(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	if err := middleware.AssignAuthToken(w, *userID); err != nil {
+	if err := middleware.AssignAuthToken(w, r, *userID); err != nil {
 		writeErrorJSONResponse(w, errorResponse{
 			Message: "Request is not valid",
 		})
@@ -160,7 +183,7 @@ func getUserProfile(w http.ResponseWriter, r *http.Request) {
 	middleware.WithAuthorizationCheck(w, r, middleware.WithAuthorizationCheckInput{
 		HandleFoundUser: func(userID users.UserID, subscriptionLevel *useraccounts.SubscriptionLevel, w http.ResponseWriter, r *http.Request) {
 			if *expectedUserID != userID {
-				middleware.RemoveAuthToken(w)
+				middleware.RemoveAuthToken(w, r)
 				writeJSONResponse(w, getUserProfileResponse{})
 				return
 			}
@@ -234,11 +257,7 @@ func resetPassword(w http.ResponseWriter, r *http.Request) {
 	formattedEmailAddress := email.FormatEmailAddress(req.EmailAddress)
 	var rErr *resetPasswordError
 	var forgotPasswordID *useraccounts.ForgotPasswordAttemptID
-	if err := encrypt.WithDecodedToken(req.ResetPasswordToken, func(tokenPair encrypt.TokenPair) error {
-		if tokenPair.Key != routes.ForgotPasswordKey.Str() {
-			rErr = resetPasswordErrorInvalidToken.Ptr()
-			return fmt.Errorf("Invalid token")
-		}
+	if err := encrypt.WithDecodedToken(req.ResetPasswordToken, routes.ForgotPasswordKey.Str(), func(tokenPair encrypt.TokenPair) error {
 		forgotPasswordIDStr, ok := tokenPair.Value.(string)
 		if !ok {
 			return fmt.Errorf("Bad value for token pair")
@@ -247,6 +266,9 @@ func resetPassword(w http.ResponseWriter, r *http.Request) {
 		return nil
 	}); err != nil {
 		log.Println(fmt.Sprintf("Error resetting password for user %s: %s", formattedEmailAddress, err.Error()))
+		if err == encrypt.ErrUnexpectedTokenKey {
+			rErr = resetPasswordErrorInvalidToken.Ptr()
+		}
 		if rErr != nil {
 			writeJSONResponse(w, resetPasswordResponse{
 				ResetPasswordError: rErr,
@@ -302,9 +324,16 @@ func resetPassword(w http.ResponseWriter, r *http.Request) {
 		if err := useraccounts.CreateUserPasswordForUser(tx, user.ID, req.Password); err != nil {
 			return err
 		}
+		// Anyone who was logged in with the old password is logged out
+		if err := usersessions.RevokeAllSessionsForUser(tx, user.ID); err != nil {
+			return err
+		}
 		return useraccounts.SetForgotPasswordAttemptAsUsed(tx, forgotPasswordAttempt.ID)
 	}); err != nil {
 		log.Println(fmt.Sprintf("Error resetting password for user %s: %s", formattedEmailAddress, err.Error()))
+		if err == encrypt.ErrUnexpectedTokenKey {
+			rErr = resetPasswordErrorInvalidToken.Ptr()
+		}
 		if rErr != nil {
 			writeJSONResponse(w, resetPasswordResponse{
 				ResetPasswordError: rErr,
@@ -316,7 +345,7 @@ func resetPassword(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	if err := middleware.AssignAuthToken(w, *userID); err != nil {
+	if err := middleware.AssignAuthToken(w, r, *userID); err != nil {
 		writeErrorJSONResponse(w, errorResponse{
 			Message: "Request is not valid",
 		})
@@ -0,0 +1,160 @@
+package useraccounts
+
+import (
+	"babblegraph/model/useraccounts"
+	"babblegraph/services/web/clientrouter/clienterror"
+	"babblegraph/services/web/clientrouter/routermiddleware"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/email"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	errorInvalidGiftCode           clienterror.Error = "invalid-gift-code"
+	errorGroupLicenseExpired       clienterror.Error = "license-expired"
+	errorGroupLicenseNoSeats       clienterror.Error = "no-seats-available"
+	errorGroupLicenseAlreadyMember clienterror.Error = "already-member"
+)
+
+type redeemGiftCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type redeemGiftCodeResponse struct {
+	PremiumExpiresAt *time.Time         `json:"premium_expires_at,omitempty"`
+	Error            *clienterror.Error `json:"error,omitempty"`
+}
+
+func redeemGiftCode(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req redeemGiftCodeRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var giftCode *useraccounts.GiftCode
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		giftCode, err = useraccounts.RedeemGiftCodeForUser(tx, userAuth.UserID, req.Code)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if giftCode == nil {
+		return redeemGiftCodeResponse{
+			Error: errorInvalidGiftCode.Ptr(),
+		}, nil
+	}
+	r.Infof("User %s redeemed gift code %s", userAuth.UserID, giftCode.ID)
+	return redeemGiftCodeResponse{
+		PremiumExpiresAt: giftCode.PremiumExpiresAt,
+	}, nil
+}
+
+type getGroupLicensesResponse struct {
+	GroupLicenses []useraccounts.GroupLicense `json:"group_licenses"`
+}
+
+func getGroupLicenses(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var groupLicenses []useraccounts.GroupLicense
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		groupLicenses, err = useraccounts.GetGroupLicensesForOwner(tx, userAuth.UserID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return getGroupLicensesResponse{
+		GroupLicenses: groupLicenses,
+	}, nil
+}
+
+type addGroupLicenseMemberRequest struct {
+	GroupLicenseID useraccounts.GroupLicenseID `json:"group_license_id"`
+	EmailAddress   string                      `json:"email_address"`
+}
+
+type addGroupLicenseMemberResponse struct {
+	GroupLicense *useraccounts.GroupLicense `json:"group_license,omitempty"`
+	Error        *clienterror.Error         `json:"error,omitempty"`
+}
+
+func addGroupLicenseMember(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req addGroupLicenseMemberRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	formattedEmailAddress := email.FormatEmailAddress(req.EmailAddress)
+	if err := email.ValidateEmailAddress(formattedEmailAddress); err != nil {
+		return addGroupLicenseMemberResponse{
+			Error: clienterror.ErrorInvalidEmailAddress.Ptr(),
+		}, nil
+	}
+	var groupLicense *useraccounts.GroupLicense
+	var memberErr *useraccounts.GroupLicenseMemberError
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		groupLicense, memberErr, err = useraccounts.AddMemberToGroupLicense(tx, userAuth.UserID, req.GroupLicenseID, formattedEmailAddress)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if groupLicense == nil {
+		r.RespondWithStatus(http.StatusForbidden)
+		return nil, nil
+	}
+	if memberErr != nil {
+		var cErr clienterror.Error
+		switch *memberErr {
+		case useraccounts.GroupLicenseMemberErrorLicenseExpired:
+			cErr = errorGroupLicenseExpired
+		case useraccounts.GroupLicenseMemberErrorNoSeatsAvailable:
+			cErr = errorGroupLicenseNoSeats
+		case useraccounts.GroupLicenseMemberErrorAlreadyMember:
+			cErr = errorGroupLicenseAlreadyMember
+		default:
+			return nil, fmt.Errorf("Unrecognized group license member error %s", *memberErr)
+		}
+		return addGroupLicenseMemberResponse{
+			GroupLicense: groupLicense,
+			Error:        cErr.Ptr(),
+		}, nil
+	}
+	return addGroupLicenseMemberResponse{
+		GroupLicense: groupLicense,
+	}, nil
+}
+
+type removeGroupLicenseMemberRequest struct {
+	GroupLicenseID useraccounts.GroupLicenseID `json:"group_license_id"`
+	EmailAddress   string                      `json:"email_address"`
+}
+
+type removeGroupLicenseMemberResponse struct {
+	GroupLicense useraccounts.GroupLicense `json:"group_license"`
+}
+
+func removeGroupLicenseMember(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req removeGroupLicenseMemberRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var groupLicense *useraccounts.GroupLicense
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		groupLicense, err = useraccounts.RemoveMemberFromGroupLicense(tx, userAuth.UserID, req.GroupLicenseID, email.FormatEmailAddress(req.EmailAddress))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if groupLicense == nil {
+		r.RespondWithStatus(http.StatusForbidden)
+		return nil, nil
+	}
+	return removeGroupLicenseMemberResponse{
+		GroupLicense: *groupLicense,
+	}, nil
+}
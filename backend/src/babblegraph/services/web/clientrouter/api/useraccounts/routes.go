@@ -5,6 +5,7 @@ import (
 	"babblegraph/model/routes"
 	"babblegraph/model/useraccounts"
 	"babblegraph/model/users"
+	"babblegraph/model/usersessions"
 	"babblegraph/services/web/clientrouter/routermiddleware"
 	"babblegraph/services/web/clientrouter/util/auth"
 	"babblegraph/services/web/clientrouter/util/routetoken"
@@ -30,6 +31,137 @@ var Routes = router.RouteGroup{
 			Handler: routermiddleware.WithNoBodyRequestLogger(
 				routermiddleware.MaybeWithAuthentication(createUser),
 			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       10,
+					RefillInterval: time.Minute,
+				},
+			},
+		}, {
+			Path: "get_active_sessions_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.WithAuthentication(getActiveSessions),
+			),
+		}, {
+			Path: "revoke_session_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.WithAuthentication(revokeSession),
+			),
+		}, {
+			Path: "revoke_all_sessions_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.WithAuthentication(revokeAllSessions),
+			),
+		}, {
+			Path: "export_user_data_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.WithAuthentication(exportUserData),
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       5,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "erase_user_data_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.WithAuthentication(eraseUserData),
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       5,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "request_user_data_link_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				requestUserDataLink,
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       5,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "export_user_data_for_token_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				exportUserDataForToken,
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       5,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "erase_user_data_for_token_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				eraseUserDataForToken,
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       5,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "redeem_gift_code_1",
+			Handler: routermiddleware.WithRequestBodyLogger(
+				routermiddleware.WithAuthentication(redeemGiftCode),
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       10,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "get_group_licenses_1",
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				routermiddleware.WithAuthentication(getGroupLicenses),
+			),
+		}, {
+			Path: "add_group_license_member_1",
+			Handler: routermiddleware.WithRequestBodyLogger(
+				routermiddleware.WithAuthentication(addGroupLicenseMember),
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       60,
+					RefillInterval: time.Hour,
+				}, {
+					Key:            router.RateLimitKeyJSONBodyField("group_license_id"),
+					Capacity:       60,
+					RefillInterval: time.Hour,
+				},
+			},
+		}, {
+			Path: "remove_group_license_member_1",
+			Handler: routermiddleware.WithRequestBodyLogger(
+				routermiddleware.WithAuthentication(removeGroupLicenseMember),
+			),
+			RateLimits: []router.RateLimit{
+				{
+					Key:            router.RateLimitKeyIPAddress,
+					Capacity:       60,
+					RefillInterval: time.Hour,
+				}, {
+					Key:            router.RateLimitKeyJSONBodyField("group_license_id"),
+					Capacity:       60,
+					RefillInterval: time.Hour,
+				},
+			},
 		},
 	},
 }
@@ -113,7 +245,8 @@ func getUserProfileInformation(userAuth *routermiddleware.UserAuthentication, r
 					if err != nil {
 						return err
 					}
-					hasPaymentMethod = premiumSubscription != nil && premiumSubscription.PaymentState != billing.PaymentStateTrialNoPaymentMethod
+					// Premium without a Stripe subscription is from a gift or group license, which doesn't need a payment method
+					hasPaymentMethod = premiumSubscription == nil || premiumSubscription.PaymentState != billing.PaymentStateTrialNoPaymentMethod
 				default:
 					r.Warnf("Unrecognized subscription level %s", *subscriptionLevel)
 				}
@@ -133,13 +266,7 @@ func getUserProfileInformation(userAuth *routermiddleware.UserAuthentication, r
 			}, nil
 		}
 		r.Infof("User ID does not match user that is logged in, removing cookie...")
-		r.RespondWithCookie(&http.Cookie{
-			Name:     routermiddleware.AuthTokenCookieName,
-			Value:    "",
-			HttpOnly: true,
-			Path:     "/",
-			Expires:  time.Now().Add(-5 * 60 * time.Second),
-		})
+		r.RespondWithCookie(auth.MakeExpiredSessionCookie())
 	}
 	var hasPaymentMethod bool
 	var doesUserHaveAccount bool
@@ -170,7 +297,7 @@ func getUserProfileInformation(userAuth *routermiddleware.UserAuthentication, r
 			*subscriptionLevel == useraccounts.SubscriptionLevelBetaPremium:
 			hasPaymentMethod = true
 		case *subscriptionLevel == useraccounts.SubscriptionLevelPremium:
-			hasPaymentMethod = premiumSubscription != nil && premiumSubscription.PaymentState != billing.PaymentStateTrialNoPaymentMethod
+			hasPaymentMethod = premiumSubscription == nil || premiumSubscription.PaymentState != billing.PaymentStateTrialNoPaymentMethod
 		}
 		return nil
 	}); err != nil {
@@ -243,6 +370,7 @@ func createUser(userAuth *routermiddleware.UserAuthentication, r *router.Request
 		}, nil
 	}
 	var cErr *createUserError
+	var sessionCookie *http.Cookie
 	err = database.WithTx(func(tx *sqlx.Tx) error {
 		user, err := users.GetUser(tx, *userID)
 		if err != nil {
@@ -260,7 +388,14 @@ func createUser(userAuth *routermiddleware.UserAuthentication, r *router.Request
 			cErr = createUserErrorAlreadyExists.Ptr()
 			return nil
 		}
-		return useraccounts.CreateUserPasswordForUser(tx, *userID, req.Password)
+		if err := useraccounts.CreateUserPasswordForUser(tx, *userID, req.Password); err != nil {
+			return err
+		}
+		sessionCookie, err = auth.CreateSessionCookieForUser(tx, *userID, usersessions.SessionMetadata{
+			IPAddress: r.GetClientIPAddress(),
+			UserAgent: r.GetHeader("User-Agent"),
+		})
+		return err
 	})
 	switch {
 	case err != nil:
@@ -270,16 +405,6 @@ func createUser(userAuth *routermiddleware.UserAuthentication, r *router.Request
 			CreateUserError: cErr,
 		}, nil
 	}
-	token, err := auth.CreateJWTForUser(*userID)
-	if err != nil {
-		return nil, err
-	}
-	r.RespondWithCookie(&http.Cookie{
-		Name:     routermiddleware.AuthTokenCookieName,
-		Value:    *token,
-		HttpOnly: true,
-		Path:     "/",
-		Expires:  time.Now().Add(auth.SessionExpirationTime),
-	})
+	r.RespondWithCookie(sessionCookie)
 	return createUserResponse{}, nil
 }
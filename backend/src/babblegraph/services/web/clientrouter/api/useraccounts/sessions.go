@@ -0,0 +1,99 @@
+package useraccounts
+
+import (
+	"babblegraph/model/usersessions"
+	"babblegraph/services/web/clientrouter/routermiddleware"
+	"babblegraph/services/web/clientrouter/util/auth"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type getActiveSessionsResponse struct {
+	Sessions []activeSession `json:"sessions"`
+}
+
+type activeSession struct {
+	ID               usersessions.ID     `json:"id"`
+	Device           usersessions.Device `json:"device"`
+	IPAddress        *string             `json:"ip_address,omitempty"`
+	UserAgent        *string             `json:"user_agent,omitempty"`
+	CreatedAt        time.Time           `json:"created_at"`
+	LastSeenAt       time.Time           `json:"last_seen_at"`
+	IsCurrentSession bool                `json:"is_current_session"`
+}
+
+func getActiveSessions(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var sessions []usersessions.Session
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		sessions, err = usersessions.GetActiveSessionsForUser(tx, userAuth.UserID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	out := []activeSession{}
+	for _, s := range sessions {
+		out = append(out, activeSession{
+			ID:               s.ID,
+			Device:           s.Device,
+			IPAddress:        s.IPAddress,
+			UserAgent:        s.UserAgent,
+			CreatedAt:        s.CreatedAt,
+			LastSeenAt:       s.LastSeenAt,
+			IsCurrentSession: s.ID == userAuth.SessionID,
+		})
+	}
+	return getActiveSessionsResponse{
+		Sessions: out,
+	}, nil
+}
+
+type revokeSessionRequest struct {
+	SessionID usersessions.ID `json:"session_id"`
+}
+
+type revokeSessionResponse struct {
+	Success bool `json:"success"`
+}
+
+func revokeSession(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req revokeSessionRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var didRevoke bool
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		// This is scoped to the user so that nobody can
+		// log someone else out by guessing a session ID
+		didRevoke, err = usersessions.RevokeSessionForUser(tx, userAuth.UserID, req.SessionID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if didRevoke && req.SessionID == userAuth.SessionID {
+		r.RespondWithCookie(auth.MakeExpiredSessionCookie())
+	}
+	return revokeSessionResponse{
+		Success: didRevoke,
+	}, nil
+}
+
+type revokeAllSessionsResponse struct {
+	Success bool `json:"success"`
+}
+
+func revokeAllSessions(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return usersessions.RevokeAllSessionsForUser(tx, userAuth.UserID)
+	}); err != nil {
+		return nil, err
+	}
+	r.RespondWithCookie(auth.MakeExpiredSessionCookie())
+	return revokeAllSessionsResponse{
+		Success: true,
+	}, nil
+}
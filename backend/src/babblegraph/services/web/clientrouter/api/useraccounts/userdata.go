@@ -0,0 +1,279 @@
+package useraccounts
+
+import (
+	"babblegraph/model/routes"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/useraccountsnotifications"
+	"babblegraph/model/userdata"
+	"babblegraph/model/users"
+	"babblegraph/model/utm"
+	"babblegraph/services/web/clientrouter/routermiddleware"
+	"babblegraph/services/web/clientrouter/util/auth"
+	"babblegraph/services/web/clientrouter/util/routetoken"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/email"
+	"babblegraph/util/recaptcha"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type userDataExportFormat string
+
+const (
+	userDataExportFormatJSON userDataExportFormat = "json"
+	userDataExportFormatZIP  userDataExportFormat = "zip"
+)
+
+type exportUserDataRequest struct {
+	Format userDataExportFormat `json:"format"`
+}
+
+type exportUserDataResponse struct {
+	UserData *userdata.Export `json:"user_data,omitempty"`
+	// This is the ZIP archive encoded as base64
+	ZIPArchive *string `json:"zip_archive,omitempty"`
+}
+
+func exportUserData(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req exportUserDataRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	return getExportUserDataResponse(r, userAuth.UserID, req.Format)
+}
+
+func getExportUserDataResponse(r *router.Request, userID users.UserID, format userDataExportFormat) (*exportUserDataResponse, error) {
+	var export *userdata.Export
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		export, err = userdata.ExportUserData(tx, userdata.ExportUserDataInput{
+			UserID:     userID,
+			TrackingID: getUTMTrackingIDFromCookies(r),
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	switch format {
+	case userDataExportFormatJSON:
+		return &exportUserDataResponse{
+			UserData: export,
+		}, nil
+	case userDataExportFormatZIP:
+		archive, err := userdata.MakeZIPArchive(*export)
+		if err != nil {
+			return nil, err
+		}
+		encodedArchive := base64.StdEncoding.EncodeToString(archive)
+		return &exportUserDataResponse{
+			ZIPArchive: &encodedArchive,
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unrecognized export format %s", format)
+	}
+}
+
+type eraseUserDataRequest struct {
+	Password string `json:"password"`
+}
+
+type eraseUserDataResponse struct {
+	Success bool                `json:"success"`
+	Error   *eraseUserDataError `json:"error,omitempty"`
+}
+
+type eraseUserDataError string
+
+const (
+	eraseUserDataErrorInvalidCredentials eraseUserDataError = "invalid-credentials"
+	eraseUserDataErrorInvalidToken       eraseUserDataError = "invalid-token"
+)
+
+func (e eraseUserDataError) Ptr() *eraseUserDataError {
+	return &e
+}
+
+func eraseUserData(userAuth routermiddleware.UserAuthentication, r *router.Request) (interface{}, error) {
+	var req eraseUserDataRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	var eErr *eraseUserDataError
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		// Erasure can't be undone, so it needs more than a session cookie
+		if err := useraccounts.VerifyPasswordForUser(tx, userAuth.UserID, req.Password); err != nil {
+			eErr = eraseUserDataErrorInvalidCredentials.Ptr()
+			return nil
+		}
+		tombstone, err := userdata.EraseUserData(r, tx, userdata.EraseUserDataInput{
+			UserID:     userAuth.UserID,
+			TrackingID: getUTMTrackingIDFromCookies(r),
+		})
+		if err != nil {
+			return err
+		}
+		r.Infof("Erased data for user %s with tombstone %s", userAuth.UserID, tombstone.ID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if eErr != nil {
+		return eraseUserDataResponse{
+			Error: eErr,
+		}, nil
+	}
+	r.RespondWithCookie(auth.MakeExpiredSessionCookie())
+	return eraseUserDataResponse{
+		Success: true,
+	}, nil
+}
+
+type requestUserDataLinkRequest struct {
+	EmailAddress string `json:"email_address"`
+	CaptchaToken string `json:"captcha_token"`
+}
+
+type requestUserDataLinkResponse struct {
+	Success bool `json:"success"`
+}
+
+// requestUserDataLink is for users without an account, who can't log in to
+// export or erase their data. The link is emailed to them so that having it
+// proves that they own the email address. The response doesn't say whether
+// the email address belongs to a user.
+func requestUserDataLink(r *router.Request) (interface{}, error) {
+	var req requestUserDataLinkRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	isValid, err := recaptcha.VerifyRecaptchaToken("userdatarequest", req.CaptchaToken)
+	switch {
+	case err != nil:
+		return nil, err
+	case !isValid:
+		return requestUserDataLinkResponse{
+			Success: false,
+		}, nil
+	}
+	formattedEmailAddress := email.FormatEmailAddress(req.EmailAddress)
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		user, err := users.LookupUserByEmailAddress(tx, formattedEmailAddress)
+		switch {
+		case err != nil:
+			return err
+		case user == nil:
+			r.Infof("No user found for email address, not sending user data link")
+			return nil
+		case user.Status != users.UserStatusVerified:
+			r.Infof("User %s does not have verified status, not sending user data link", user.ID)
+			return nil
+		}
+		alreadyHasAccount, err := useraccounts.DoesUserAlreadyHaveAccount(tx, user.ID)
+		switch {
+		case err != nil:
+			return err
+		case alreadyHasAccount:
+			// Users with an account need to log in and confirm their password
+			r.Infof("User %s has an account, not sending user data link", user.ID)
+			return nil
+		}
+		_, err = useraccountsnotifications.EnqueueNotificationRequest(tx, user.ID, useraccountsnotifications.NotificationTypeUserDataRequest, time.Now())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return requestUserDataLinkResponse{
+		Success: true,
+	}, nil
+}
+
+type exportUserDataForTokenRequest struct {
+	Token  string               `json:"token"`
+	Format userDataExportFormat `json:"format"`
+}
+
+type exportUserDataForTokenResponse struct {
+	UserData   *userdata.Export     `json:"user_data,omitempty"`
+	ZIPArchive *string              `json:"zip_archive,omitempty"`
+	Error      *exportUserDataError `json:"error,omitempty"`
+}
+
+type exportUserDataError string
+
+const (
+	exportUserDataErrorInvalidToken exportUserDataError = "invalid-token"
+)
+
+func (e exportUserDataError) Ptr() *exportUserDataError {
+	return &e
+}
+
+func exportUserDataForToken(r *router.Request) (interface{}, error) {
+	var req exportUserDataForTokenRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	userID, err := routetoken.ValidateTokenAndGetUserID(req.Token, routes.UserDataRequestKey)
+	if err != nil {
+		return exportUserDataForTokenResponse{
+			Error: exportUserDataErrorInvalidToken.Ptr(),
+		}, nil
+	}
+	resp, err := getExportUserDataResponse(r, *userID, req.Format)
+	if err != nil {
+		return nil, err
+	}
+	return exportUserDataForTokenResponse{
+		UserData:   resp.UserData,
+		ZIPArchive: resp.ZIPArchive,
+	}, nil
+}
+
+type eraseUserDataForTokenRequest struct {
+	Token string `json:"token"`
+	// Erasure can't be undone, so the user has to type
+	// their email address to confirm, the same as unsubscribing
+	EmailAddress string `json:"email_address"`
+}
+
+func eraseUserDataForToken(r *router.Request) (interface{}, error) {
+	var req eraseUserDataForTokenRequest
+	if err := r.GetJSONBody(&req); err != nil {
+		return nil, err
+	}
+	userID, err := routetoken.ValidateTokenAndEmailAndGetUserID(req.Token, routes.UserDataRequestKey, req.EmailAddress)
+	if err != nil {
+		return eraseUserDataResponse{
+			Error: eraseUserDataErrorInvalidToken.Ptr(),
+		}, nil
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		tombstone, err := userdata.EraseUserData(r, tx, userdata.EraseUserDataInput{
+			UserID:     *userID,
+			TrackingID: getUTMTrackingIDFromCookies(r),
+		})
+		if err != nil {
+			return err
+		}
+		r.Infof("Erased data for user %s with tombstone %s", *userID, tombstone.ID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return eraseUserDataResponse{
+		Success: true,
+	}, nil
+}
+
+func getUTMTrackingIDFromCookies(r *router.Request) *utm.TrackingID {
+	for _, cookie := range r.GetCookies() {
+		if cookie.Name == utm.UTMTrackingIDCookieName {
+			return utm.TrackingID(cookie.Value).Ptr()
+		}
+	}
+	return nil
+}
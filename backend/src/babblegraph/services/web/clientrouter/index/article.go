@@ -52,7 +52,7 @@ func handleArticleRoute(staticFileDirName string) func(w http.ResponseWriter, r
 			var emailRecordID *email.ID
 			var userID *users.UserID
 			var url *urlparser.ParsedURL
-			if err := encrypt.WithDecodedToken(token, func(tokenPair encrypt.TokenPair) error {
+			if err := encrypt.WithDecodedTokenForKeys(token, []string{routes.ArticleLinkKeyDEPRECATED.Str(), routes.ArticleLinkKeyForUserDocumentID.Str()}, func(tokenPair encrypt.TokenPair) error {
 				switch {
 				case tokenPair.Key == routes.ArticleLinkKeyDEPRECATED.Str():
 					return nil
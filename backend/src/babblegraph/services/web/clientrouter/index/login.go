@@ -46,7 +46,7 @@ func HandleLogout() func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		middleware.WithAuthorizationCheck(w, r, middleware.WithAuthorizationCheckInput{
 			HandleFoundUser: func(userID users.UserID, subscriptionLevel *useraccounts.SubscriptionLevel, w http.ResponseWriter, r *http.Request) {
-				middleware.RemoveAuthToken(w)
+				middleware.RemoveAuthToken(w, r)
 				http.Redirect(w, r, env.GetAbsoluteURLForEnvironment("login"), http.StatusTemporaryRedirect)
 			},
 			HandleNoUserFound: func(w http.ResponseWriter, r *http.Request) {
@@ -30,18 +30,18 @@ func HandleServeLogo(staticFileDirName string) func(w http.ResponseWriter, r *ht
 				return
 			}
 			if err := database.WithTx(func(tx *sqlx.Tx) error {
-				return encrypt.WithDecodedToken(token, func(t encrypt.TokenPair) error {
-					if t.Key != routes.EmailOpenedKey.Str() {
-						return fmt.Errorf("Token has wrong key: %s", t.Key)
-					}
+				return encrypt.WithDecodedToken(token, routes.EmailOpenedKey.Str(), func(t encrypt.TokenPair) error {
 					emailRecordID, ok := t.Value.(string)
 					if !ok {
 						return fmt.Errorf("Token has wrong value type")
 					}
-					return email.SetEmailFirstOpened(tx, email.ID(emailRecordID))
+					if err := email.SetEmailFirstOpened(tx, email.ID(emailRecordID)); err != nil {
+						return err
+					}
+					return email.RecordEngagementEvent(tx, email.ID(emailRecordID), email.EngagementEventTypeOpened, nil)
 				})
 			}); err != nil {
-				log.Println(fmt.Sprintf("Got error handling token %s: %s", token, err.Error()))
+				log.Println(fmt.Sprintf("Got error handling logo token: %s", err.Error()))
 			}
 		}()
 		http.ServeFile(w, r, fmt.Sprintf("%s/logo.png", staticFileDirName))
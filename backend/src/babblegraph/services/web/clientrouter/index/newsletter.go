@@ -0,0 +1,93 @@
+package index
+
+import (
+	"babblegraph/model/emailtemplates"
+	"babblegraph/model/newsletter"
+	"babblegraph/model/newsletterarchive"
+	"babblegraph/model/newslettersendrequests"
+	"babblegraph/model/routes"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/encrypt"
+	"babblegraph/util/ptr"
+	"babblegraph/util/storage"
+	"encoding/json"
+	"fmt"
+	"html/template"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// The newsletter HTML is rendered by the email templates,
+// so the page template just writes it out unescaped
+var viewNewsletterTemplate = template.Must(template.New("view-newsletter").Parse("{{ . }}"))
+
+func handleViewNewsletter(r *router.Request) (interface{}, error) {
+	token, err := r.GetRouteVar("token")
+	if err != nil {
+		return nil, err
+	}
+	var sendRequestID newslettersendrequests.ID
+	if err := encrypt.WithDecodedToken(*token, routes.NewsletterViewKey.Str(), func(t encrypt.TokenPair) error {
+		sendRequestIDStr, ok := t.Value.(string)
+		if !ok {
+			return fmt.Errorf("Token has wrong value type")
+		}
+		sendRequestID = newslettersendrequests.ID(sendRequestIDStr)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var sendRequest *newslettersendrequests.NewsletterSendRequest
+	var archivedNewsletter *newsletter.NewsletterVersion2
+	var userAccessor *emailtemplates.DefaultUserAccessor
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		sendRequest, err = newslettersendrequests.LookupSendRequestByID(tx, sendRequestID)
+		if err != nil || sendRequest == nil {
+			return err
+		}
+		_, archivedNewsletter, err = newsletterarchive.LookupArchivedNewsletterForSendRequest(tx, sendRequestID)
+		if err != nil {
+			return err
+		}
+		userAccessor, err = emailtemplates.GetDefaultUserAccessor(tx, sendRequest.UserID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	var newsletterVersion2 *newsletter.NewsletterVersion2
+	switch {
+	case sendRequest == nil:
+		r.Infof("No send request found for ID %s", sendRequestID)
+		return ptr.String(routes.MustGetHomePageURL()), nil
+	case archivedNewsletter != nil:
+		newsletterVersion2 = archivedNewsletter
+	case sendRequest.PayloadStatus == newslettersendrequests.PayloadStatusSent:
+		// Newsletters sent before the archive existed are only
+		// available until the payload is cleaned up
+		data, err := storage.NewS3StorageForEnvironment().GetData("prod-spaces-1", sendRequest.GetFileKey())
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(*data), &newsletterVersion2); err != nil {
+			return nil, err
+		}
+	default:
+		r.Infof("Send request %s is not available to view", sendRequestID)
+		return ptr.String(routes.MustGetHomePageURL()), nil
+	}
+	renderedNewsletter, err := emailtemplates.MakeNewsletterVersion2(emailtemplates.MakeNewsletterVersion2Input{
+		EmailRecordID:    newsletterVersion2.EmailRecordID,
+		UserAccessor:     userAccessor,
+		Body:             newsletterVersion2.Body,
+		WeeklyDigestBody: newsletterVersion2.WeeklyDigestBody,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &router.IndexResponse{
+		FileTemplate: viewNewsletterTemplate,
+		TemplateData: template.HTML(renderedNewsletter.HTMLBody),
+	}, nil
+}
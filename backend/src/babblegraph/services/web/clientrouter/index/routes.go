@@ -28,6 +28,13 @@ func GetRoutes() []router.IndexRoute {
 			Handler: routermiddleware.WithNoBodyRequestLogger(
 				handleAdClick,
 			),
+		}, {
+			Path: router.IndexPath{
+				Text: "/view-newsletter/{token}",
+			},
+			Handler: routermiddleware.WithNoBodyRequestLogger(
+				handleViewNewsletter,
+			),
 		},
 	}
 	routes = append(routes, getPromotionCodeRoutes()...)
@@ -3,42 +3,46 @@ package middleware
 import (
 	"babblegraph/model/useraccounts"
 	"babblegraph/model/users"
+	"babblegraph/model/usersessions"
 	"babblegraph/services/web/clientrouter/util/auth"
+	"babblegraph/services/web/router"
 	"babblegraph/util/database"
 	"fmt"
 	"log"
 	"net/http"
-	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
-const authTokenCookieName = "session_token"
-
-func AssignAuthToken(w http.ResponseWriter, userID users.UserID) error {
-	token, err := auth.CreateJWTForUser(userID)
-	if err != nil {
-		log.Println(fmt.Sprintf("Error creating token for user ID %s: %s", userID, err.Error()))
+func AssignAuthToken(w http.ResponseWriter, r *http.Request, userID users.UserID) error {
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		sessionCookie, err := auth.CreateSessionCookieForUser(tx, userID, getSessionMetadataForRequest(r))
+		if err != nil {
+			return err
+		}
+		http.SetCookie(w, sessionCookie)
+		return nil
+	}); err != nil {
+		log.Println(fmt.Sprintf("Error creating session for user ID %s: %s", userID, err.Error()))
 		return err
 	}
-	http.SetCookie(w, &http.Cookie{
-		Name:     authTokenCookieName,
-		Value:    *token,
-		HttpOnly: true,
-		Path:     "/",
-		Expires:  time.Now().Add(auth.SessionExpirationTime),
-	})
 	return nil
 }
 
-func RemoveAuthToken(w http.ResponseWriter) error {
-	http.SetCookie(w, &http.Cookie{
-		Name:     authTokenCookieName,
-		Value:    "",
-		HttpOnly: true,
-		Path:     "/",
-		Expires:  time.Now().Add(-5 * 60 * time.Second),
-	})
+// RemoveAuthToken revokes the session for the request
+// in addition to removing the cookie from the browser
+func RemoveAuthToken(w http.ResponseWriter, r *http.Request) error {
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == auth.SessionCookieName {
+			if err := database.WithTx(func(tx *sqlx.Tx) error {
+				return usersessions.RevokeSessionForToken(tx, cookie.Value)
+			}); err != nil {
+				log.Println(fmt.Sprintf("Error revoking session: %s", err.Error()))
+				return err
+			}
+		}
+	}
+	http.SetCookie(w, auth.MakeExpiredSessionCookie())
 	return nil
 }
 
@@ -50,52 +54,52 @@ type WithAuthorizationCheckInput struct {
 }
 
 func WithAuthorizationCheck(w http.ResponseWriter, r *http.Request, input WithAuthorizationCheckInput) {
-	var userSubscriptionLevel *useraccounts.SubscriptionLevel
-	var userID *users.UserID
 	for _, cookie := range r.Cookies() {
-		if cookie.Name == authTokenCookieName {
-			var err error
-			var isValid bool
+		if cookie.Name == auth.SessionCookieName {
 			token := cookie.Value
-			userID, isValid, err = auth.VerifyJWTAndGetUserID(token)
-			switch {
-			case err != nil:
+			var session *usersessions.ActiveSession
+			var userStatus users.UserStatus
+			var userSubscriptionLevel *useraccounts.SubscriptionLevel
+			if err := database.WithTx(func(tx *sqlx.Tx) error {
+				var err error
+				session, err = usersessions.LookupAndRefreshSessionForToken(tx, token, getSessionMetadataForRequest(r))
+				switch {
+				case err != nil:
+					return err
+				case session == nil:
+					return nil
+				}
+				user, err := users.GetUser(tx, session.UserID)
+				if err != nil {
+					return err
+				}
+				userStatus = user.Status
+				userSubscriptionLevel, err = useraccounts.LookupSubscriptionLevelForUser(tx, session.UserID)
+				return err
+			}); err != nil {
 				input.HandleError(err, w, r)
 				return
-			case !isValid:
+			}
+			if session == nil {
 				input.HandleInvalidAuthenticationToken(w, r)
 				return
-			case userID == nil:
+			}
+			if session.WasExtended {
+				http.SetCookie(w, auth.MakeSessionCookie(token, session.ExpiresAt))
+			}
+			switch userStatus {
+			case users.UserStatusVerified:
+				input.HandleFoundUser(session.UserID, userSubscriptionLevel, w, r)
+			case users.UserStatusUnverified,
+				users.UserStatusUnsubscribed,
+				users.UserStatusBlocklistBounced,
+				users.UserStatusBlocklistComplaint,
+				users.UserStatusErased:
 				input.HandleNoUserFound(w, r)
-				return
 			default:
-				var userStatus users.UserStatus
-				if err := database.WithTx(func(tx *sqlx.Tx) error {
-					user, err := users.GetUser(tx, *userID)
-					if err != nil {
-						return err
-					}
-					userStatus = user.Status
-					userSubscriptionLevel, err = useraccounts.LookupSubscriptionLevelForUser(tx, *userID)
-					return err
-				}); err != nil {
-					input.HandleError(err, w, r)
-					return
-				}
-				switch userStatus {
-				case users.UserStatusVerified:
-					input.HandleFoundUser(*userID, userSubscriptionLevel, w, r)
-				case users.UserStatusUnverified,
-					users.UserStatusUnsubscribed,
-					users.UserStatusBlocklistBounced,
-					users.UserStatusBlocklistComplaint:
-					input.HandleNoUserFound(w, r)
-				default:
-					input.HandleError(fmt.Errorf("Invalid state"), w, r)
-					return
-				}
-				return
+				input.HandleError(fmt.Errorf("Invalid state"), w, r)
 			}
+			return
 		}
 	}
 	input.HandleNoUserFound(w, r)
@@ -140,3 +144,10 @@ func HandleAuthorizationError(err error, w http.ResponseWriter, r *http.Request)
 	log.Println(fmt.Sprintf("Error authenticating user: %s", err.Error()))
 	w.WriteHeader(http.StatusBadRequest)
 }
+
+func getSessionMetadataForRequest(r *http.Request) usersessions.SessionMetadata {
+	return usersessions.SessionMetadata{
+		IPAddress: router.GetClientIPAddress(r),
+		UserAgent: r.UserAgent(),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"babblegraph/model/admin"
 	"babblegraph/model/useraccounts"
 	"babblegraph/model/users"
+	"babblegraph/model/usersessions"
 	"babblegraph/services/web/clientrouter/util/auth"
 	"babblegraph/services/web/router"
 	"babblegraph/util/database"
@@ -14,10 +15,11 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
-const AuthTokenCookieName = "session_token"
+const AuthTokenCookieName = auth.SessionCookieName
 
 type UserAuthentication struct {
 	UserID            users.UserID
+	SessionID         usersessions.ID
 	SubscriptionLevel *useraccounts.SubscriptionLevel
 }
 
@@ -29,41 +31,52 @@ func MaybeWithAuthentication(handler MaybeWithAuthenticationHandler) router.Requ
 		for _, cookie := range r.GetCookies() {
 			if cookie.Name == AuthTokenCookieName {
 				token := cookie.Value
-				userID, isValid, err := auth.VerifyJWTAndGetUserID(token)
-				switch {
-				case err != nil:
-					return nil, err
-				case !isValid,
-					userID == nil:
-					// no-op
-				default:
-					var userStatus users.UserStatus
-					var userSubscriptionLevel *useraccounts.SubscriptionLevel
-					if err := database.WithTx(func(tx *sqlx.Tx) error {
-						user, err := users.GetUser(tx, *userID)
-						if err != nil {
-							return err
-						}
-						userStatus = user.Status
-						userSubscriptionLevel, err = useraccounts.LookupSubscriptionLevelForUser(tx, *userID)
+				var session *usersessions.ActiveSession
+				var userStatus users.UserStatus
+				var userSubscriptionLevel *useraccounts.SubscriptionLevel
+				if err := database.WithTx(func(tx *sqlx.Tx) error {
+					var err error
+					session, err = usersessions.LookupAndRefreshSessionForToken(tx, token, usersessions.SessionMetadata{
+						IPAddress: r.GetClientIPAddress(),
+						UserAgent: r.GetHeader("User-Agent"),
+					})
+					switch {
+					case err != nil:
 						return err
-					}); err != nil {
-						return nil, err
+					case session == nil:
+						return nil
 					}
-					switch userStatus {
-					case users.UserStatusVerified:
-						userAuth = &UserAuthentication{
-							UserID:            *userID,
-							SubscriptionLevel: userSubscriptionLevel,
-						}
-					case users.UserStatusUnverified,
-						users.UserStatusUnsubscribed,
-						users.UserStatusBlocklistBounced,
-						users.UserStatusBlocklistComplaint:
-						// no-op
-					default:
-						return nil, fmt.Errorf("Invalid user state: %s", userStatus)
+					user, err := users.GetUser(tx, session.UserID)
+					if err != nil {
+						return err
 					}
+					userStatus = user.Status
+					userSubscriptionLevel, err = useraccounts.LookupSubscriptionLevelForUser(tx, session.UserID)
+					return err
+				}); err != nil {
+					return nil, err
+				}
+				if session == nil {
+					continue
+				}
+				if session.WasExtended {
+					r.RespondWithCookie(auth.MakeSessionCookie(token, session.ExpiresAt))
+				}
+				switch userStatus {
+				case users.UserStatusVerified:
+					userAuth = &UserAuthentication{
+						UserID:            session.UserID,
+						SessionID:         session.ID,
+						SubscriptionLevel: userSubscriptionLevel,
+					}
+				case users.UserStatusUnverified,
+					users.UserStatusUnsubscribed,
+					users.UserStatusBlocklistBounced,
+					users.UserStatusBlocklistComplaint,
+					users.UserStatusErased:
+					// no-op
+				default:
+					return nil, fmt.Errorf("Invalid user state: %s", userStatus)
 				}
 			}
 		}
@@ -89,10 +89,7 @@ func encodePromotionCode(promotionCode billing.PromotionCode) (*string, error) {
 
 func decodePromotionCode(c ctx.LogContext, tokenStr string) *promotionCodeTokenValue {
 	var out *promotionCodeTokenValue
-	if err := encrypt.WithDecodedToken(tokenStr, func(tokenPair encrypt.TokenPair) error {
-		if tokenPair.Key != PromotionCodeCookieName {
-			return fmt.Errorf("Incorrect key for cookie value: %s", tokenPair.Key)
-		}
+	if err := encrypt.WithDecodedToken(tokenStr, PromotionCodeCookieName, func(tokenPair encrypt.TokenPair) error {
 		val, ok := tokenPair.Value.(map[string]interface{})
 		if !ok {
 			return fmt.Errorf("Value did not correctly parse as a map, was %T", tokenPair.Value)
@@ -0,0 +1,42 @@
+package routermiddleware
+
+import (
+	"babblegraph/model/usersessions"
+	"babblegraph/services/web/router"
+	"babblegraph/util/database"
+	"babblegraph/util/ptr"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RateLimitKeyUserIDOrIPAddress limits logged in users by their user ID,
+// so that users behind a shared IP address don't throttle each other,
+// and falls back to the IP address for everyone else
+var RateLimitKeyUserIDOrIPAddress = router.RateLimitKey{
+	Name: "user-id-or-ip",
+	GetKey: func(r *router.Request) (*string, error) {
+		for _, cookie := range r.GetCookies() {
+			if cookie.Name != AuthTokenCookieName {
+				continue
+			}
+			var key *string
+			if err := database.WithTx(func(tx *sqlx.Tx) error {
+				userID, err := usersessions.LookupUserIDForActiveSessionToken(tx, cookie.Value)
+				if err != nil {
+					return err
+				}
+				if userID != nil {
+					key = ptr.String(fmt.Sprintf("user:%s", *userID))
+				}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			if key != nil {
+				return key, nil
+			}
+		}
+		return router.RateLimitKeyIPAddress.GetKey(r)
+	},
+}
@@ -2,39 +2,39 @@ package auth
 
 import (
 	"babblegraph/model/users"
-	"babblegraph/util/env"
-	"babblegraph/util/ptr"
+	"babblegraph/model/usersessions"
+	"net/http"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/jmoiron/sqlx"
 )
 
-const SessionExpirationTime = 3 * 24 * time.Hour
+const SessionCookieName = "session_token"
 
-func CreateJWTForUser(userID users.UserID) (*string, error) {
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(SessionExpirationTime).Unix(),
-		Subject:   string(userID),
-		IssuedAt:  time.Now().Unix(),
-	})
-	signedToken, err := accessToken.SignedString([]byte(env.MustEnvironmentVariable("HMAC_SECRET")))
+func CreateSessionCookieForUser(tx *sqlx.Tx, userID users.UserID, metadata usersessions.SessionMetadata) (*http.Cookie, error) {
+	token, expiresAt, err := usersessions.CreateSession(tx, userID, metadata)
 	if err != nil {
 		return nil, err
 	}
-	return ptr.String(signedToken), nil
+	return MakeSessionCookie(*token, *expiresAt), nil
 }
 
-func VerifyJWTAndGetUserID(tokenString string) (*users.UserID, bool, error) {
-	claims := jwt.StandardClaims{}
-	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(env.MustEnvironmentVariable("HMAC_SECRET")), nil
-	})
-	if err != nil {
-		return nil, false, err
+func MakeSessionCookie(token string, expiresAt time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    token,
+		HttpOnly: true,
+		Path:     "/",
+		Expires:  expiresAt,
 	}
-	if !token.Valid {
-		return nil, false, nil
+}
+
+func MakeExpiredSessionCookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		HttpOnly: true,
+		Path:     "/",
+		Expires:  time.Now().Add(-5 * time.Minute),
 	}
-	userID := users.UserID(claims.Subject)
-	return &userID, true, nil
 }
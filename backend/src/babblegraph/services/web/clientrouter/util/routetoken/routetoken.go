@@ -12,20 +12,14 @@ import (
 )
 
 func ValidateRouteToken(token string, expectedToken routes.RouteEncryptionKey) error {
-	return encrypt.WithDecodedToken(token, func(t encrypt.TokenPair) error {
-		if t.Key != expectedToken.Str() {
-			return fmt.Errorf("incorrect key")
-		}
+	return encrypt.WithDecodedToken(token, expectedToken.Str(), func(t encrypt.TokenPair) error {
 		return nil
 	})
 }
 
 func ValidateTokenAndGetUserID(token string, expectedToken routes.RouteEncryptionKey) (*users.UserID, error) {
 	var userID users.UserID
-	if err := encrypt.WithDecodedToken(token, func(t encrypt.TokenPair) error {
-		if t.Key != expectedToken.Str() {
-			return fmt.Errorf("incorrect key")
-		}
+	if err := encrypt.WithDecodedToken(token, expectedToken.Str(), func(t encrypt.TokenPair) error {
 		userIDStr, ok := t.Value.(string)
 		if !ok {
 			return fmt.Errorf("incorrect type")
@@ -40,10 +34,7 @@ func ValidateTokenAndGetUserID(token string, expectedToken routes.RouteEncryptio
 
 func ValidateTokenAndEmailAndGetUserID(token string, expectedToken routes.RouteEncryptionKey, expectedEmailAddress string) (*users.UserID, error) {
 	var userID users.UserID
-	if err := encrypt.WithDecodedToken(token, func(t encrypt.TokenPair) error {
-		if t.Key != expectedToken.Str() {
-			return fmt.Errorf("incorrect key")
-		}
+	if err := encrypt.WithDecodedToken(token, expectedToken.Str(), func(t encrypt.TokenPair) error {
 		userIDStr, ok := t.Value.(string)
 		if !ok {
 			return fmt.Errorf("incorrect type")
@@ -2,13 +2,18 @@ package router
 
 import (
 	"babblegraph/util/deref"
+	"babblegraph/util/env"
 	"babblegraph/util/ptr"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -32,11 +37,14 @@ func (r *Request) LogRequest(includeBody bool) {
 }
 
 func (r *Request) GetBodyAsBytes() ([]byte, error) {
-	bytes, err := ioutil.ReadAll(r.r.Body)
+	body, err := ioutil.ReadAll(r.r.Body)
 	if err != nil {
 		return nil, err
 	}
-	return bytes, nil
+	// The body is put back so that it can be read more than once,
+	// for instance by a rate limit and then by the handler
+	r.r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
 }
 
 func (r *Request) GetJSONBody(v interface{}) error {
@@ -76,6 +84,14 @@ func (r *Request) GetHeader(headerName string) string {
 	return r.r.Header.Get(headerName)
 }
 
+func (r *Request) GetPath() string {
+	return r.r.URL.Path
+}
+
+func (r *Request) GetClientIPAddress() string {
+	return GetClientIPAddress(r.r)
+}
+
 func (r *Request) GetCookies() []*http.Cookie {
 	return r.r.Cookies()
 }
@@ -98,6 +114,12 @@ func (r *Request) RespondWithStatus(status int) {
 	r.respStatus = &status
 }
 
+// GetResponseStatus returns nil if the handler hasn't
+// set a status, in which case the response is a 200
+func (r *Request) GetResponseStatus() *int {
+	return r.respStatus
+}
+
 func (r *Request) Debugf(format string, args ...interface{}) {
 	r.c.Debugf(format, args...)
 }
@@ -113,3 +135,38 @@ func (r *Request) Warnf(format string, args ...interface{}) {
 func (r *Request) Errorf(format string, args ...interface{}) {
 	r.c.Errorf(format, args...)
 }
+
+// Each proxy in front of the server appends the address it got the request
+// from to X-Forwarded-For, so only the last TRUSTED_PROXY_COUNT entries can
+// be trusted. Anything before those was sent by the client and can be made up.
+var trustedProxyCount = getTrustedProxyCount()
+
+func getTrustedProxyCount() int {
+	count, err := strconv.Atoi(env.GetEnvironmentVariableOrDefault("TRUSTED_PROXY_COUNT", "1"))
+	if err != nil || count < 0 {
+		return 1
+	}
+	return count
+}
+
+func GetClientIPAddress(r *http.Request) string {
+	return getClientIPAddress(r.Header.Values("X-Forwarded-For"), r.RemoteAddr, trustedProxyCount)
+}
+
+func getClientIPAddress(forwardedForHeaders []string, remoteAddr string, trustedProxyCount int) string {
+	var forwardedFor []string
+	for _, header := range forwardedForHeaders {
+		for _, address := range strings.Split(header, ",") {
+			if address = strings.TrimSpace(address); len(address) > 0 {
+				forwardedFor = append(forwardedFor, address)
+			}
+		}
+	}
+	if trustedProxyCount > 0 && len(forwardedFor) >= trustedProxyCount {
+		return forwardedFor[len(forwardedFor)-trustedProxyCount]
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
@@ -0,0 +1,27 @@
+package router
+
+import "testing"
+
+func TestGetClientIPAddress(t *testing.T) {
+	type testCase struct {
+		forwardedForHeaders []string
+		remoteAddr          string
+		trustedProxyCount   int
+		expected            string
+	}
+	for idx, tc := range []testCase{
+		{forwardedForHeaders: nil, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 1, expected: "10.0.0.1"},
+		{forwardedForHeaders: []string{"203.0.113.7"}, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 1, expected: "203.0.113.7"},
+		// The client can send its own X-Forwarded-For, which the load balancer appends to
+		{forwardedForHeaders: []string{"1.2.3.4, 203.0.113.7"}, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 1, expected: "203.0.113.7"},
+		{forwardedForHeaders: []string{"1.2.3.4", "203.0.113.7"}, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 1, expected: "203.0.113.7"},
+		{forwardedForHeaders: []string{"1.2.3.4, 203.0.113.7, 10.0.0.2"}, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 2, expected: "203.0.113.7"},
+		// Fewer entries than proxies means the header didn't come from our proxies
+		{forwardedForHeaders: []string{"1.2.3.4"}, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 2, expected: "10.0.0.1"},
+		{forwardedForHeaders: []string{"1.2.3.4"}, remoteAddr: "10.0.0.1:4321", trustedProxyCount: 0, expected: "10.0.0.1"},
+	} {
+		if result := getClientIPAddress(tc.forwardedForHeaders, tc.remoteAddr, tc.trustedProxyCount); result != tc.expected {
+			t.Errorf("Error on test case %d: expected %s, but got %s", idx, tc.expected, result)
+		}
+	}
+}
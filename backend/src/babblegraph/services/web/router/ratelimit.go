@@ -0,0 +1,224 @@
+package router
+
+import (
+	"babblegraph/util/bglog"
+	"babblegraph/util/env"
+	"babblegraph/util/ptr"
+	"babblegraph/util/random"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate limits are token buckets. Each bucket starts out
+// with Capacity tokens, every request takes one, and one
+// token is added back every RefillInterval.
+type RateLimit struct {
+	Key            RateLimitKey
+	Capacity       int
+	RefillInterval time.Duration
+}
+
+type RateLimitKey struct {
+	Name string
+	// If this returns nil, the limit is skipped for the request
+	GetKey func(r *Request) (*string, error)
+}
+
+var RateLimitKeyIPAddress = RateLimitKey{
+	Name: "ip",
+	GetKey: func(r *Request) (*string, error) {
+		return ptr.String(r.GetClientIPAddress()), nil
+	},
+}
+
+// RateLimitKeyJSONBodyField keys a limit on a string field of the request body,
+// which is how routes like login are limited per email address
+func RateLimitKeyJSONBodyField(fieldName string) RateLimitKey {
+	return RateLimitKey{
+		Name: fmt.Sprintf("body-%s", fieldName),
+		GetKey: func(r *Request) (*string, error) {
+			var body map[string]interface{}
+			if err := r.GetJSONBody(&body); err != nil {
+				return nil, nil
+			}
+			value, ok := body[fieldName].(string)
+			if !ok {
+				return nil, nil
+			}
+			value = strings.ToLower(strings.TrimSpace(value))
+			if len(value) == 0 {
+				return nil, nil
+			}
+			return ptr.String(value), nil
+		},
+	}
+}
+
+type RateLimitBackend interface {
+	TakeToken(bucketKey string, limit RateLimit, now time.Time) (_isAllowed bool, _retryAfter time.Duration, _err error)
+}
+
+type rateLimitBackendType string
+
+const (
+	rateLimitBackendTypeInMemory rateLimitBackendType = "memory"
+	rateLimitBackendTypePostgres rateLimitBackendType = "postgres"
+)
+
+var (
+	rateLimitBackend     RateLimitBackend
+	rateLimitBackendOnce sync.Once
+)
+
+// The in-memory backend is only correct for a single instance,
+// so deploys with more than one should set RATE_LIMIT_BACKEND to postgres
+func getRateLimitBackend() RateLimitBackend {
+	rateLimitBackendOnce.Do(func() {
+		switch rateLimitBackendType(env.GetEnvironmentVariableOrDefault("RATE_LIMIT_BACKEND", string(rateLimitBackendTypeInMemory))) {
+		case rateLimitBackendTypePostgres:
+			rateLimitBackend = NewPostgresRateLimitBackend()
+		default:
+			rateLimitBackend = NewInMemoryRateLimitBackend()
+		}
+	})
+	return rateLimitBackend
+}
+
+type rateLimitBucket struct {
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func takeTokenFromBucket(bucket *rateLimitBucket, limit RateLimit, now time.Time) (_updated rateLimitBucket, _isAllowed bool, _retryAfter time.Duration) {
+	tokens := float64(limit.Capacity)
+	if bucket != nil {
+		refilled := float64(now.Sub(bucket.UpdatedAt)) / float64(limit.RefillInterval)
+		tokens = math.Min(float64(limit.Capacity), bucket.Tokens+math.Max(refilled, 0))
+	}
+	if tokens >= 1 {
+		return rateLimitBucket{
+			Tokens:    tokens - 1,
+			UpdatedAt: now,
+		}, true, 0
+	}
+	return rateLimitBucket{
+		Tokens:    tokens,
+		UpdatedAt: now,
+	}, false, time.Duration((1 - tokens) * float64(limit.RefillInterval))
+}
+
+// A bucket that has had time to refill completely
+// is the same as one that does not exist
+func isBucketFull(bucket rateLimitBucket, limit RateLimit, now time.Time) bool {
+	return now.Sub(bucket.UpdatedAt) >= time.Duration((float64(limit.Capacity)-bucket.Tokens)*float64(limit.RefillInterval))
+}
+
+const maxInMemoryRateLimitBuckets = 100000
+
+type inMemoryRateLimitBackend struct {
+	mu      sync.Mutex
+	buckets map[string]inMemoryRateLimitBucket
+}
+
+type inMemoryRateLimitBucket struct {
+	bucket rateLimitBucket
+	limit  RateLimit
+}
+
+func NewInMemoryRateLimitBackend() RateLimitBackend {
+	return &inMemoryRateLimitBackend{
+		buckets: make(map[string]inMemoryRateLimitBucket),
+	}
+}
+
+func (b *inMemoryRateLimitBackend) TakeToken(bucketKey string, limit RateLimit, now time.Time) (_isAllowed bool, _retryAfter time.Duration, _err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.buckets) >= maxInMemoryRateLimitBuckets {
+		for key, existing := range b.buckets {
+			if isBucketFull(existing.bucket, existing.limit, now) {
+				delete(b.buckets, key)
+			}
+		}
+	}
+	var current *rateLimitBucket
+	if existing, ok := b.buckets[bucketKey]; ok {
+		current = &existing.bucket
+	}
+	updated, isAllowed, retryAfter := takeTokenFromBucket(current, limit, now)
+	b.buckets[bucketKey] = inMemoryRateLimitBucket{
+		bucket: updated,
+		limit:  limit,
+	}
+	return isAllowed, retryAfter, nil
+}
+
+// Errors from the backend are logged and the request is let through,
+// since an outage of the rate limiter should not take the site down with it
+func checkRateLimits(r *Request, backend RateLimitBackend, scope string, limits []RateLimit) (_isAllowed bool, _retryAfter time.Duration) {
+	isAllowed := true
+	var retryAfter time.Duration
+	now := time.Now()
+	for _, limit := range limits {
+		key, err := limit.Key.GetKey(r)
+		switch {
+		case err != nil:
+			r.Errorf("Error getting rate limit key %s for %s: %s", limit.Key.Name, scope, err.Error())
+			continue
+		case key == nil:
+			continue
+		}
+		// Keys are hashed so that things like email addresses
+		// are not stored by the rate limiting backend
+		keyHash := sha256.Sum256([]byte(*key))
+		bucketKey := fmt.Sprintf("%s:%s:%s", scope, limit.Key.Name, hex.EncodeToString(keyHash[:]))
+		isLimitAllowed, limitRetryAfter, err := backend.TakeToken(bucketKey, limit, now)
+		switch {
+		case err != nil:
+			r.Errorf("Error checking rate limit %s for %s: %s", limit.Key.Name, scope, err.Error())
+		case !isLimitAllowed:
+			r.Warnf("Throttled request for %s by rate limit on %s", scope, limit.Key.Name)
+			isAllowed = false
+			if limitRetryAfter > retryAfter {
+				retryAfter = limitRetryAfter
+			}
+		}
+	}
+	return isAllowed, retryAfter
+}
+
+func writeTooManyRequestsResponse(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", int64(math.Ceil(retryAfter.Seconds()))))
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(errorResponse{
+		Message: "Too many requests",
+	})
+}
+
+// WithHTTPRateLimits is for routes that are registered
+// directly on the mux router instead of through a RouteGroup
+func WithHTTPRateLimits(scope string, limits []RateLimit, handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		wrappedRequest := &Request{
+			c: Context{
+				ctx:    context.Background(),
+				logger: bglog.NewLoggerForContext(scope, random.MustMakeRandomString(12), 4),
+			},
+			r: req,
+		}
+		if isAllowed, retryAfter := checkRateLimits(wrappedRequest, getRateLimitBackend(), scope, limits); !isAllowed {
+			writeTooManyRequestsResponse(w, retryAfter)
+			return
+		}
+		handler(w, req)
+	}
+}
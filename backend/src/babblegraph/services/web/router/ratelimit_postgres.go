@@ -0,0 +1,79 @@
+package router
+
+import (
+	"babblegraph/util/database"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	insertRateLimitBucketQuery = `INSERT INTO
+        rate_limit_buckets (bucket_key, tokens, updated_at)
+        VALUES ($1, $2, $3)
+    ON CONFLICT (bucket_key) DO NOTHING`
+	lookupRateLimitBucketForUpdateQuery = "SELECT tokens, updated_at FROM rate_limit_buckets WHERE bucket_key = $1 FOR UPDATE"
+	updateRateLimitBucketQuery          = "UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE bucket_key = $1"
+	deleteStaleRateLimitBucketsQuery    = "DELETE FROM rate_limit_buckets WHERE updated_at < $1"
+
+	// None of the limits take anywhere near this long to refill,
+	// so buckets this old are full and can be removed
+	staleRateLimitBucketAge          = 24 * time.Hour
+	staleRateLimitBucketCleanupEvery = time.Hour
+)
+
+// The Postgres backend shares buckets between every instance
+// of the web server. Each bucket is locked for the duration of
+// the transaction, so concurrent requests can't both take the last token.
+type postgresRateLimitBackend struct {
+	mu            sync.Mutex
+	lastCleanupAt time.Time
+}
+
+func NewPostgresRateLimitBackend() RateLimitBackend {
+	return &postgresRateLimitBackend{}
+}
+
+func (b *postgresRateLimitBackend) TakeToken(bucketKey string, limit RateLimit, now time.Time) (_isAllowed bool, _retryAfter time.Duration, _err error) {
+	var isAllowed bool
+	var retryAfter time.Duration
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		if err := b.maybeRemoveStaleBuckets(tx, now); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(insertRateLimitBucketQuery, bucketKey, limit.Capacity, now); err != nil {
+			return err
+		}
+		var matches []rateLimitBucket
+		err := tx.Select(&matches, lookupRateLimitBucketForUpdateQuery, bucketKey)
+		switch {
+		case err != nil:
+			return err
+		case len(matches) != 1:
+			return fmt.Errorf("Expected exactly one rate limit bucket, but got %d", len(matches))
+		}
+		var updated rateLimitBucket
+		updated, isAllowed, retryAfter = takeTokenFromBucket(&matches[0], limit, now)
+		_, err = tx.Exec(updateRateLimitBucketQuery, bucketKey, updated.Tokens, updated.UpdatedAt)
+		return err
+	}); err != nil {
+		return false, 0, err
+	}
+	return isAllowed, retryAfter, nil
+}
+
+func (b *postgresRateLimitBackend) maybeRemoveStaleBuckets(tx *sqlx.Tx, now time.Time) error {
+	b.mu.Lock()
+	shouldCleanup := now.Sub(b.lastCleanupAt) > staleRateLimitBucketCleanupEvery
+	if shouldCleanup {
+		b.lastCleanupAt = now
+	}
+	b.mu.Unlock()
+	if !shouldCleanup {
+		return nil
+	}
+	_, err := tx.Exec(deleteStaleRateLimitBucketsQuery, now.Add(-staleRateLimitBucketAge))
+	return err
+}
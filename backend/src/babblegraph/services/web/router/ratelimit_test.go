@@ -0,0 +1,110 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTakeTokenFromBucket(t *testing.T) {
+	limit := RateLimit{
+		Capacity:       2,
+		RefillInterval: time.Minute,
+	}
+	now := time.Now()
+	bucket, isAllowed, _ := takeTokenFromBucket(nil, limit, now)
+	if !isAllowed || bucket.Tokens != 1 {
+		t.Fatalf("Expected first request to be allowed with 1 token left, but got %t with %f tokens", isAllowed, bucket.Tokens)
+	}
+	bucket, isAllowed, _ = takeTokenFromBucket(&bucket, limit, now)
+	if !isAllowed || bucket.Tokens != 0 {
+		t.Fatalf("Expected second request to be allowed with no tokens left, but got %t with %f tokens", isAllowed, bucket.Tokens)
+	}
+	bucket, isAllowed, retryAfter := takeTokenFromBucket(&bucket, limit, now.Add(15*time.Second))
+	if isAllowed {
+		t.Fatalf("Expected third request to be throttled")
+	}
+	if retryAfter != 45*time.Second {
+		t.Errorf("Expected retry after of 45s, but got %s", retryAfter)
+	}
+	bucket, isAllowed, _ = takeTokenFromBucket(&bucket, limit, now.Add(time.Minute))
+	if !isAllowed {
+		t.Errorf("Expected request to be allowed once a token was refilled")
+	}
+	// Buckets never refill past their capacity
+	bucket, _, _ = takeTokenFromBucket(&bucket, limit, now.Add(time.Hour))
+	if bucket.Tokens != 1 {
+		t.Errorf("Expected bucket to be capped at capacity, but got %f tokens after taking one", bucket.Tokens)
+	}
+}
+
+func TestInMemoryRateLimitBackend(t *testing.T) {
+	backend := NewInMemoryRateLimitBackend()
+	limit := RateLimit{
+		Capacity:       1,
+		RefillInterval: time.Minute,
+	}
+	now := time.Now()
+	for idx, tc := range []struct {
+		bucketKey string
+		expected  bool
+	}{
+		{bucketKey: "a", expected: true},
+		{bucketKey: "a", expected: false},
+		{bucketKey: "b", expected: true},
+	} {
+		isAllowed, _, err := backend.TakeToken(tc.bucketKey, limit, now)
+		switch {
+		case err != nil:
+			t.Fatalf("Error on test case %d: %s", idx, err.Error())
+		case isAllowed != tc.expected:
+			t.Errorf("Error on test case %d: expected %t, but got %t", idx, tc.expected, isAllowed)
+		}
+	}
+}
+
+func TestRouteGroupRateLimits(t *testing.T) {
+	rg := RouteGroup{
+		Prefix: "test-rate-limits",
+		RateLimits: []RateLimit{
+			{
+				Key:            RateLimitKeyJSONBodyField("email_address"),
+				Capacity:       1,
+				RefillInterval: time.Hour,
+			},
+		},
+	}
+	route := Route{
+		Path: "test",
+		Handler: func(r *Request) (interface{}, error) {
+			var body map[string]string
+			if err := r.GetJSONBody(&body); err != nil {
+				return nil, err
+			}
+			return body, nil
+		},
+	}
+	handler := route.makeMuxRoute(rg)
+	for idx, tc := range []struct {
+		body           string
+		expectedStatus int
+	}{
+		{body: `{"email_address": "test@babblegraph.com"}`, expectedStatus: http.StatusOK},
+		{body: `{"email_address": " TEST@babblegraph.com"}`, expectedStatus: http.StatusTooManyRequests},
+		{body: `{"email_address": "other@babblegraph.com"}`, expectedStatus: http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPost, "/api/test-rate-limits/test", strings.NewReader(tc.body)))
+		if w.Code != tc.expectedStatus {
+			t.Errorf("Error on test case %d: expected status %d, but got %d", idx, tc.expectedStatus, w.Code)
+		}
+		if tc.expectedStatus == http.StatusOK && !strings.Contains(w.Body.String(), "email_address") {
+			t.Errorf("Error on test case %d: expected handler to be able to read the body, but got %s", idx, w.Body.String())
+		}
+		if tc.expectedStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Errorf("Error on test case %d: expected Retry-After header", idx)
+		}
+	}
+}
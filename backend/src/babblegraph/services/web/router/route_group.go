@@ -4,6 +4,7 @@ import (
 	"babblegraph/util/bglog"
 	"babblegraph/util/random"
 	"context"
+	"fmt"
 	"html/template"
 	"net/http"
 )
@@ -11,16 +12,21 @@ import (
 type RouteGroup struct {
 	Prefix string
 	Routes []Route
+	// These are shared by every route in the group,
+	// so a client that spreads requests across routes
+	// still draws from the same buckets
+	RateLimits []RateLimit
 }
 
 type Route struct {
-	Path    string
-	Handler RequestHandler
+	Path       string
+	Handler    RequestHandler
+	RateLimits []RateLimit
 }
 
 type RequestHandler func(r *Request) (interface{}, error)
 
-func (r Route) makeMuxRoute() func(http.ResponseWriter, *http.Request) {
+func (r Route) makeMuxRoute(routeGroup RouteGroup) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Add("Content-Type", "application/json")
 		contextKey := random.MustMakeRandomString(12)
@@ -32,6 +38,18 @@ func (r Route) makeMuxRoute() func(http.ResponseWriter, *http.Request) {
 			c: ctx,
 			r: req,
 		}
+		if len(routeGroup.RateLimits) != 0 || len(r.RateLimits) != 0 {
+			backend := getRateLimitBackend()
+			isGroupAllowed, groupRetryAfter := checkRateLimits(wrappedRequest, backend, routeGroup.Prefix, routeGroup.RateLimits)
+			isRouteAllowed, routeRetryAfter := checkRateLimits(wrappedRequest, backend, fmt.Sprintf("%s/%s", routeGroup.Prefix, r.Path), r.RateLimits)
+			if !isGroupAllowed || !isRouteAllowed {
+				if routeRetryAfter > groupRetryAfter {
+					groupRetryAfter = routeRetryAfter
+				}
+				writeTooManyRequestsResponse(w, groupRetryAfter)
+				return
+			}
+		}
 		status := http.StatusOK
 		resp, err := r.Handler(wrappedRequest)
 		switch {
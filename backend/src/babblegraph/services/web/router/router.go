@@ -38,7 +38,7 @@ func WithAPIRouter(r *mux.Router, apiPrefix string, routeGroups []RouteGroup) er
 				return fmt.Errorf("Duplicate path %s for route group %s", r.Path, rg.Prefix)
 			}
 			routeNames[r.Path] = true
-			apiRouter.HandleFunc(fmt.Sprintf("/%s/%s", rg.Prefix, r.Path), r.makeMuxRoute()).Methods("POST")
+			apiRouter.HandleFunc(fmt.Sprintf("/%s/%s", rg.Prefix, r.Path), r.makeMuxRoute(rg)).Methods("POST")
 		}
 	}
 	return nil
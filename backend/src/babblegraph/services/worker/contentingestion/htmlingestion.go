@@ -4,17 +4,16 @@ import (
 	"babblegraph/model/content"
 	"babblegraph/model/contenttopics"
 	"babblegraph/model/documents"
+	"babblegraph/model/ingesthtml"
 	"babblegraph/model/links2"
+	"babblegraph/model/textprocessing"
 	"babblegraph/model/urltopicmapping"
-	"babblegraph/services/worker/contentingestion/ingesthtml"
 	"babblegraph/services/worker/indexing"
-	"babblegraph/services/worker/textprocessing"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
 	"babblegraph/util/opengraph"
 	"babblegraph/util/ptr"
 	"babblegraph/util/urlparser"
-	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -66,6 +65,11 @@ func processWebsiteHTML1Link(c ctx.LogContext, link links2.Link) error {
 	default:
 		// no-op
 	}
+	ingestionOutcome := content.RecordIngestionOutcomeInput{
+		SourceID: source.ID,
+		Outcome:  content.IngestionOutcomeNotIndexed,
+	}
+	defer recordIngestionOutcome(c, &ingestionOutcome)
 	parsedHTMLPage, err := ingesthtml.ProcessURL(ingesthtml.ProcessURLInput{
 		URL:          link.URL,
 		Source:       *source,
@@ -73,8 +77,10 @@ func processWebsiteHTML1Link(c ctx.LogContext, link links2.Link) error {
 	})
 	if err != nil {
 		c.Infof("Error parsing html for link %s: %s", link.URL, err.Error())
+		ingestionOutcome.Outcome = content.IngestionOutcomeParseFailure
 		return nil
 	}
+	ingestionOutcome.IsPaywalled = parsedHTMLPage.IsPaywalled
 	if err := insertLinks(parsedHTMLPage.Links); err != nil {
 		return err
 	}
@@ -96,35 +102,19 @@ func processWebsiteHTML1Link(c ctx.LogContext, link links2.Link) error {
 	var topicIDs []content.TopicID
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		var err error
-		topicsForURL, topicMappingIDs, err = urltopicmapping.GetTopicsAndMappingIDsForURL(tx, u)
-		if err != nil {
-			return err
-		}
-		var sourceSeedTopicMappings []content.SourceSeedTopicMappingID
-		for _, topicMappingID := range topicMappingIDs {
-			sourceSeedTopicMapping, sourceTopicMapping, err := topicMappingID.GetOriginID()
-			switch {
-			case err != nil:
-				return err
-			case sourceTopicMapping != nil:
-				c.Warnf("Found source topic mapping from ID %s, which is unsupported", topicMappingID)
-			case sourceSeedTopicMapping != nil:
-				sourceSeedTopicMappings = append(sourceSeedTopicMappings, *sourceSeedTopicMapping)
-			default:
-				return fmt.Errorf("unreachable")
-			}
-		}
-		if len(sourceSeedTopicMappings) > 0 {
-			topicIDs, err = content.LookupTopicsForSourceSeedMappingIDs(tx, sourceSeedTopicMappings)
-			if err != nil {
-				return err
-			}
-		}
+		topicsForURL, topicMappingIDs, topicIDs, err = urltopicmapping.LookupTopicsForURL(c, tx, u)
 		return err
 	}); err != nil {
 		c.Warnf("Error getting topics for url %s: %s. Continuing...", u, err.Error())
 		return nil
 	}
+	var topicClassifications []documents.TopicClassification
+	if len(topicIDs) == 0 && textMetadata.LemmatizedDescription != nil {
+		topicClassifications = classifyTopics(c, source.LanguageCode, textMetadata.LemmatizedDescription.LemmatizedText)
+		for _, classification := range topicClassifications {
+			topicIDs = append(topicIDs, classification.TopicID)
+		}
+	}
 	c.Debugf("Indexing text for URL %s", u)
 	err = indexing.IndexDocument(c, indexing.IndexDocumentInput{
 		ParsedHTMLPage:         *parsedHTMLPage,
@@ -136,38 +126,31 @@ func processWebsiteHTML1Link(c ctx.LogContext, link links2.Link) error {
 		TopicsForURL:           topicsForURL,
 		TopicIDs:               topicIDs,
 		TopicMappingIDs:        topicMappingIDs,
+		TopicClassifications:   topicClassifications,
 		SeedJobIngestTimestamp: link.SeedJobIngestTimestamp,
 	})
 	if err != nil {
 		c.Warnf("Got error indexing document for url %s: %s. Continuing...", u, err.Error())
+		return nil
 	}
+	ingestionOutcome.Outcome = content.IngestionOutcomeIndexed
+	ingestionOutcome.ReadabilityScore = ptr.Int64(textMetadata.ReadabilityScore.ToInt64Rounded())
 	return nil
 }
 
-func insertLinks(urls []string) error {
-	var parsedURLs []urlparser.ParsedURL
-	for _, u := range urls {
-		parsedURL := urlparser.ParseURL(u)
-		if parsedURL == nil {
-			continue
-		}
-		parsedURLs = append(parsedURLs, *parsedURL)
+func recordIngestionOutcome(c ctx.LogContext, input *content.RecordIngestionOutcomeInput) {
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return content.RecordIngestionOutcome(tx, *input)
+	}); err != nil {
+		c.Errorf("Error recording ingestion outcome for source %s: %s", input.SourceID, err.Error())
 	}
+}
+
+func insertLinks(urls []string) error {
 	return database.WithTx(func(tx *sqlx.Tx) error {
-		var filteredURLs []links2.URLWithSourceMapping
-		for _, u := range parsedURLs {
-			sourceID, _, err := content.LookupSourceIDForDomain(tx, u.Domain)
-			switch {
-			case err != nil:
-				return err
-			case sourceID == nil:
-				continue
-			default:
-				filteredURLs = append(filteredURLs, links2.URLWithSourceMapping{
-					URL:      u,
-					SourceID: *sourceID,
-				})
-			}
+		filteredURLs, err := links2.FilterLinksForKnownSources(tx, urls)
+		if err != nil {
+			return err
 		}
 		if len(filteredURLs) == 0 {
 			return nil
@@ -2,36 +2,84 @@ package ingestrss
 
 import (
 	"babblegraph/util/ctx"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 )
 
-func GetPodcastDataForRSSFeed(c ctx.LogContext, url string) (*PodcastRSSChannel, error) {
-	feed, err := fetchRSSForURL(url)
+type GetPodcastDataForRSSFeedInput struct {
+	URL string
+
+	// These come from the last successful fetch of the feed
+	// and are used to skip feeds that have not changed
+	ETag               *string
+	LastModifiedHeader *string
+	ContentHash        *string
+}
+
+type PodcastRSSFeedData struct {
+	// Channel is nil if the feed has not changed since the last fetch
+	Channel *PodcastRSSChannel
+
+	ETag               *string
+	LastModifiedHeader *string
+	ContentHash        string
+}
+
+func GetPodcastDataForRSSFeed(c ctx.LogContext, input GetPodcastDataForRSSFeedInput) (*PodcastRSSFeedData, error) {
+	req, err := http.NewRequest(http.MethodGet, input.URL, nil)
 	if err != nil {
 		return nil, err
 	}
-	return &feed.Channel, nil
-}
-
-func fetchRSSForURL(u string) (*podcastRSSFeed, error) {
-	resp, err := http.Get(u)
+	if input.ETag != nil {
+		req.Header.Set("If-None-Match", *input.ETag)
+	}
+	if input.LastModifiedHeader != nil {
+		req.Header.Set("If-Modified-Since", *input.LastModifiedHeader)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
+	out := &PodcastRSSFeedData{
+		ETag:               getOptionalHeader(resp, "ETag"),
+		LastModifiedHeader: getOptionalHeader(resp, "Last-Modified"),
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		c.Infof("Feed %s was not modified", input.URL)
+		return out, nil
+	case resp.StatusCode != http.StatusOK:
 		return nil, fmt.Errorf("Got status code for website: %d", resp.StatusCode)
 	}
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	// Not every feed supports conditional requests, so
+	// the body is hashed to detect changes as well
+	hash := sha256.Sum256(data)
+	out.ContentHash = hex.EncodeToString(hash[:])
+	if input.ContentHash != nil && *input.ContentHash == out.ContentHash {
+		c.Infof("Feed %s has the same content hash as the last fetch", input.URL)
+		return out, nil
+	}
 	var feed podcastRSSFeed
 	if err := xml.Unmarshal(data, &feed); err != nil {
 		return nil, err
 	}
-	return &feed, nil
+	out.Channel = &feed.Channel
+	return out, nil
+}
+
+func getOptionalHeader(resp *http.Response, key string) *string {
+	value := resp.Header.Get(key)
+	if len(value) == 0 {
+		return nil
+	}
+	return &value
 }
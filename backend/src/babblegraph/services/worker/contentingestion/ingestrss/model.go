@@ -25,7 +25,7 @@ type PodcastRSSChannel struct {
 	Subtitle       string                `xml:"itunes:subtitle"`
 	Author         string                `xml:"itunes:author"`
 	Summary        string                `xml:"itunes:summary"`
-	ContentEncoded PodcastEncodedContent `xml:"content:encoded"`
+	ContentEncoded PodcastEncodedContent `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 	Owner          PodcastOwner          `xml:"itunes:owner"`
 	ITunesImage    PodcastITunesImage    `xml:"itunes:image"`
 	Categories     []PodcastCategory     `xml:"itunes:category"`
@@ -73,8 +73,10 @@ const (
 	PodcastTypeEpisodic PodcastType = "episodic"
 )
 
+// encoding/xml matches namespaced elements on the namespace URL
+// rather than the prefix, so these tags need to use the full URL
 type PodcastEncodedContent struct {
-	XMLName xml.Name `xml:"content:encoded"`
+	XMLName xml.Name `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 	Value   string   `xml:",cdata"`
 }
 
@@ -103,11 +105,12 @@ type PodcastEpisode struct {
 	Author          string                `xml:"author"`
 	Subtitle        string                `xml:"subtitle"`
 	Summary         string                `xml:"itunes:summary"`
-	ContentEncoded  PodcastEncodedContent `xml:"content:encoded"`
+	ContentEncoded  PodcastEncodedContent `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 	Duration        string                `xml:"duration"`
 	IsExplicit      PodcastExplicitity    `xml:"itunes:explicit"`
 	ID              string                `xml:"guid"`
 	AudioData       PodcastEnclosure      `xml:"enclosure"`
+	Transcripts     []PodcastTranscript   `xml:"https://podcastindex.org/namespace/1.0 transcript"`
 }
 
 type PodcastEpisodeType string
@@ -126,3 +129,10 @@ type PodcastEnclosure struct {
 	Length  string   `xml:"length,attr"`
 	Type    string   `xml:"type,attr"`
 }
+
+type PodcastTranscript struct {
+	XMLName  xml.Name `xml:"https://podcastindex.org/namespace/1.0 transcript"`
+	URL      string   `xml:"url,attr"`
+	Type     string   `xml:"type,attr"`
+	Language string   `xml:"language,attr"`
+}
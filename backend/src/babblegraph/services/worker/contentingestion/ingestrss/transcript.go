@@ -0,0 +1,180 @@
+package ingestrss
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const maxTranscriptSizeBytes = 5 * 1024 * 1024
+
+type transcriptFormat string
+
+const (
+	transcriptFormatJSON transcriptFormat = "json"
+	transcriptFormatVTT  transcriptFormat = "vtt"
+	transcriptFormatSRT  transcriptFormat = "srt"
+)
+
+// Transcripts are picked in this order since JSON
+// transcripts don't need any cue markup removed
+var transcriptFormatPreference = []transcriptFormat{
+	transcriptFormatJSON,
+	transcriptFormatVTT,
+	transcriptFormatSRT,
+}
+
+func getTranscriptFormatForMimeType(mimeType string) *transcriptFormat {
+	var out transcriptFormat
+	switch strings.ToLower(strings.TrimSpace(mimeType)) {
+	case "application/json":
+		out = transcriptFormatJSON
+	case "text/vtt":
+		out = transcriptFormatVTT
+	case "application/srt",
+		"application/x-subrip",
+		"text/srt":
+		out = transcriptFormatSRT
+	default:
+		return nil
+	}
+	return &out
+}
+
+var cueTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// GetTranscriptForEpisode returns nil if the episode
+// does not link a transcript in a supported format
+func GetTranscriptForEpisode(episode PodcastEpisode) *PodcastTranscript {
+	transcriptsByFormat := make(map[transcriptFormat]PodcastTranscript)
+	for _, t := range episode.Transcripts {
+		if format := getTranscriptFormatForMimeType(t.Type); format != nil {
+			if _, ok := transcriptsByFormat[*format]; !ok {
+				transcriptsByFormat[*format] = t
+			}
+		}
+	}
+	for _, format := range transcriptFormatPreference {
+		if transcript, ok := transcriptsByFormat[format]; ok {
+			return &transcript
+		}
+	}
+	return nil
+}
+
+func GetTextForTranscript(transcript PodcastTranscript) (*string, error) {
+	format := getTranscriptFormatForMimeType(transcript.Type)
+	if format == nil {
+		return nil, fmt.Errorf("Unsupported transcript type %s", transcript.Type)
+	}
+	data, err := fetchTranscript(transcript.URL)
+	if err != nil {
+		return nil, err
+	}
+	var text string
+	switch *format {
+	case transcriptFormatJSON:
+		text, err = parseJSONTranscript(data)
+	case transcriptFormatVTT,
+		transcriptFormatSRT:
+		text, err = parseCueTranscript(data)
+	default:
+		return nil, fmt.Errorf("Unsupported transcript format %s", *format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &text, nil
+}
+
+func fetchTranscript(u string) ([]byte, error) {
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Got status code for transcript: %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxTranscriptSizeBytes))
+}
+
+type jsonTranscript struct {
+	Segments []jsonTranscriptSegment `json:"segments"`
+}
+
+type jsonTranscriptSegment struct {
+	Body string `json:"body"`
+}
+
+func parseJSONTranscript(data []byte) (string, error) {
+	var transcript jsonTranscript
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, s := range transcript.Segments {
+		if body := strings.TrimSpace(s.Body); len(body) > 0 {
+			lines = append(lines, body)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseCueTranscript handles both SRT and WebVTT. Both formats are blocks separated
+// by blank lines, where cues have a timing line containing "-->" followed by the text.
+// Blocks without a timing line (headers, notes, styles) are skipped.
+func parseCueTranscript(data []byte) (string, error) {
+	var lines []string
+	var isInCueText bool
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case len(line) == 0:
+			isInCueText = false
+		case strings.Contains(line, "-->"):
+			isInCueText = true
+		case isInCueText:
+			if cleaned := strings.TrimSpace(cueTagRegex.ReplaceAllString(line, "")); len(cleaned) > 0 {
+				lines = append(lines, html.UnescapeString(cleaned))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// GetShowNotesTextForEpisode returns the plain text of the episode's
+// encoded content if there is any, and its description otherwise
+func GetShowNotesTextForEpisode(episode PodcastEpisode) string {
+	showNotes := episode.ContentEncoded.Value
+	if len(strings.TrimSpace(showNotes)) == 0 {
+		showNotes = episode.Description
+	}
+	return stripHTML(showNotes)
+}
+
+func stripHTML(in string) string {
+	var parts []string
+	tokenizer := html.NewTokenizer(strings.NewReader(in))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(parts, " ")
+		case html.TextToken:
+			if text := strings.TrimSpace(string(tokenizer.Text())); len(text) > 0 {
+				parts = append(parts, text)
+			}
+		}
+	}
+}
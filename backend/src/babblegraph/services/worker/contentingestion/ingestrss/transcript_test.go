@@ -0,0 +1,101 @@
+package ingestrss
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestParseCueTranscript(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    string
+		expected string
+	}
+	for _, tc := range []testCase{
+		{
+			name: "srt",
+			input: `1
+00:00:00,000 --> 00:00:02,500
+Hola a todos.
+
+2
+00:00:02,500 --> 00:00:05,000
+<i>Bienvenidos</i> al programa.
+`,
+			expected: "Hola a todos.\nBienvenidos al programa.",
+		}, {
+			name: "vtt",
+			input: `WEBVTT
+
+NOTE This is a comment
+
+intro
+00:00.000 --> 00:02.500
+<v Ana>Hola a todos.</v>
+
+00:02.500 --> 00:05.000 align:start
+Hoy hablamos de m&uacute;sica.
+`,
+			expected: "Hola a todos.\nHoy hablamos de música.",
+		},
+	} {
+		result, err := parseCueTranscript([]byte(tc.input))
+		switch {
+		case err != nil:
+			t.Errorf("Error on test case %s: %s", tc.name, err.Error())
+		case result != tc.expected:
+			t.Errorf("Error on test case %s: expected %q, but got %q", tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestParseJSONTranscript(t *testing.T) {
+	result, err := parseJSONTranscript([]byte(`{"version": "1.0.0", "segments": [{"speaker": "Ana", "startTime": 0, "endTime": 2.5, "body": "Hola a todos."}, {"startTime": 2.5, "endTime": 5, "body": " Bienvenidos "}]}`))
+	if err != nil {
+		t.Fatalf("Got error: %s", err.Error())
+	}
+	if expected := "Hola a todos.\nBienvenidos"; result != expected {
+		t.Errorf("Expected %q, but got %q", expected, result)
+	}
+}
+
+func TestGetTranscriptForEpisode(t *testing.T) {
+	transcript := GetTranscriptForEpisode(PodcastEpisode{
+		Transcripts: []PodcastTranscript{
+			{URL: "https://example.com/episode.html", Type: "text/html"},
+			{URL: "https://example.com/episode.srt", Type: "application/srt"},
+			{URL: "https://example.com/episode.json", Type: "application/json"},
+		},
+	})
+	switch {
+	case transcript == nil:
+		t.Errorf("Expected a transcript, but got none")
+	case transcript.URL != "https://example.com/episode.json":
+		t.Errorf("Expected the JSON transcript, but got %s", transcript.URL)
+	}
+	if transcript := GetTranscriptForEpisode(PodcastEpisode{
+		Transcripts: []PodcastTranscript{
+			{URL: "https://example.com/episode.html", Type: "text/html"},
+		},
+	}); transcript != nil {
+		t.Errorf("Expected no transcript, but got %s", transcript.URL)
+	}
+}
+
+func TestParseEpisodeNamespacedElements(t *testing.T) {
+	var episode PodcastEpisode
+	if err := xml.Unmarshal([]byte(`<item xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:podcast="https://podcastindex.org/namespace/1.0">
+    <title>Episodio 1</title>
+    <description>Descripción</description>
+    <content:encoded><![CDATA[<p>Notas del <b>episodio</b></p>]]></content:encoded>
+    <podcast:transcript url="https://example.com/1.vtt" type="text/vtt" language="es" />
+</item>`), &episode); err != nil {
+		t.Fatalf("Got error: %s", err.Error())
+	}
+	if len(episode.Transcripts) != 1 || episode.Transcripts[0].URL != "https://example.com/1.vtt" {
+		t.Errorf("Expected one transcript, but got %+v", episode.Transcripts)
+	}
+	if showNotes := GetShowNotesTextForEpisode(episode); showNotes != "Notas del episodio" {
+		t.Errorf("Expected show notes from encoded content, but got %q", showNotes)
+	}
+}
@@ -3,6 +3,7 @@ package contentingestion
 import (
 	"babblegraph/model/content"
 	"babblegraph/model/podcasts"
+	"babblegraph/model/textprocessing"
 	"babblegraph/services/worker/contentingestion/ingestrss"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
@@ -16,12 +17,34 @@ import (
 )
 
 func processPodcastRSS1SourceSeed(c ctx.LogContext, sourceSeed content.SourceSeed) error {
-	channel, err := ingestrss.GetPodcastDataForRSSFeed(c, sourceSeed.URL)
-	if err != nil {
+	var feedState *podcasts.FeedState
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		feedState, err = podcasts.LookupFeedStateForSourceSeed(tx, sourceSeed.ID)
+		return err
+	}); err != nil {
+		return err
+	}
+	getFeedInput := ingestrss.GetPodcastDataForRSSFeedInput{
+		URL: sourceSeed.URL,
+	}
+	if feedState != nil {
+		getFeedInput.ETag = feedState.ETag
+		getFeedInput.LastModifiedHeader = feedState.LastModifiedHeader
+		getFeedInput.ContentHash = ptr.String(feedState.ContentHash)
+	}
+	feedData, err := ingestrss.GetPodcastDataForRSSFeed(c, getFeedInput)
+	switch {
+	case err != nil:
 		return err
+	case feedData.Channel == nil:
+		c.Infof("Feed for source seed %s has not changed, skipping", sourceSeed.ID)
+		return nil
 	}
+	channel := feedData.Channel
 	var topicIDs []content.TopicID
 	var source *content.Source
+	var episodeRecords []podcasts.EpisodeRecord
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		var err error
 		_, topicIDs, err = content.LookupTopicMappingIDForSourceSeedID(tx, sourceSeed.ID)
@@ -32,6 +55,10 @@ func processPodcastRSS1SourceSeed(c ctx.LogContext, sourceSeed content.SourceSee
 		if err != nil {
 			return err
 		}
+		episodeRecords, err = podcasts.GetActiveEpisodeRecordsForSourceSeed(tx, sourceSeed.ID)
+		if err != nil {
+			return err
+		}
 		if err := content.UpdateSource(tx, sourceSeed.RootID, content.UpdateSourceInput{
 			LanguageCode:          source.LanguageCode,
 			Title:                 channel.Title,
@@ -50,29 +77,213 @@ func processPodcastRSS1SourceSeed(c ctx.LogContext, sourceSeed content.SourceSee
 		return err
 	}
 	var errs []string
+	// Conversion errors don't keep the feed state from being saved, since
+	// the episode would fail the same way until the feed changes, and its
+	// GUID is tracked so that it isn't treated as removed in the meantime
+	var conversionErrs []string
+	var feedEpisodes []podcasts.FeedEpisode
+	rssEpisodesByGUID := make(map[string]ingestrss.PodcastEpisode)
+	toIndexByGUID := make(map[string]*podcasts.IndexPodcastEpisodeInput)
+	unconvertedGUIDs := make(map[string]bool)
 	for _, episode := range channel.Episodes {
 		toIndex, err := convertIngestEpisodeToModelEpisode(c, episode)
 		if err != nil {
-			errs = append(errs, fmt.Sprintf("Error converting episode with GUID %s for source id %s: %s", episode.ID, sourceSeed.RootID, err.Error()))
+			conversionErrs = append(conversionErrs, fmt.Sprintf("Error converting episode with GUID %s for source id %s: %s", episode.ID, sourceSeed.RootID, err.Error()))
+			unconvertedGUIDs[episode.ID] = true
 			continue
 		}
+		feedEpisodes = append(feedEpisodes, podcasts.FeedEpisode{
+			GUID:        toIndex.GUID,
+			AudioURL:    toIndex.AudioFile.URL,
+			Fingerprint: podcasts.MakeEpisodeFingerprint(toIndex.Title, toIndex.PublicationDate),
+		})
+		if _, ok := toIndexByGUID[toIndex.GUID]; !ok {
+			rssEpisodesByGUID[toIndex.GUID] = episode
+			toIndexByGUID[toIndex.GUID] = toIndex
+		}
+	}
+	reconciliation := podcasts.ReconcileEpisodes(episodeRecords, feedEpisodes)
+	for _, action := range reconciliation.ToIndex {
+		episode := rssEpisodesByGUID[action.FeedEpisode.GUID]
+		toIndex := toIndexByGUID[action.FeedEpisode.GUID]
+		// Episodes keep the GUID they were first indexed with
+		// so that a rewritten GUID does not create a duplicate
+		toIndex.GUID = action.IndexedGUID
 		toIndex.Version = podcasts.CurrentVersion
 		toIndex.SourceID = source.ID
 		toIndex.LanguageCode = source.LanguageCode
 		toIndex.TopicIDs = topicIDs
+		if err := addTextMetadataToEpisode(c, sourceSeed.ID, episode, toIndex); err != nil {
+			// Episodes are still indexed without text metadata so that
+			// they can be matched on topic and duration
+			c.Warnf("Error processing text for episode with GUID %s for source %s: %s", episode.ID, source.ID, err.Error())
+		}
 		podcastEpisodeID, err := podcasts.AssignIDAndIndexPodcastEpisode(c, *toIndex)
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("Error indexing podcast for source %s with GUID %s: %s", source.ID, toIndex.GUID, err.Error()))
 			continue
 		}
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			if err := podcasts.UpsertEpisodeRecord(tx, podcasts.EpisodeRecord{
+				EpisodeID:    *podcastEpisodeID,
+				SourceID:     source.ID,
+				SourceSeedID: sourceSeed.ID,
+				LanguageCode: source.LanguageCode,
+				IndexedGUID:  action.IndexedGUID,
+				FeedGUID:     action.FeedEpisode.GUID,
+				AudioURL:     action.FeedEpisode.AudioURL,
+				Fingerprint:  action.FeedEpisode.Fingerprint,
+			}); err != nil {
+				return err
+			}
+			if action.ChangeType == nil {
+				return nil
+			}
+			return podcasts.InsertFeedChange(tx, podcasts.InsertFeedChangeInput{
+				SourceID:     source.ID,
+				SourceSeedID: sourceSeed.ID,
+				EpisodeID:    podcastEpisodeID,
+				FeedGUID:     action.FeedEpisode.GUID,
+				ChangeType:   *action.ChangeType,
+				Details:      action.Details,
+			})
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("Error recording podcast episode %s: %s", *podcastEpisodeID, err.Error()))
+			continue
+		}
 		c.Infof("Indexed podcast episode with ID %s", *podcastEpisodeID)
 	}
+	for _, duplicate := range reconciliation.Duplicates {
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			return podcasts.InsertFeedChange(tx, podcasts.InsertFeedChangeInput{
+				SourceID:     source.ID,
+				SourceSeedID: sourceSeed.ID,
+				FeedGUID:     duplicate.FeedEpisode.GUID,
+				ChangeType:   *duplicate.ChangeType,
+				Details:      duplicate.Details,
+			})
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("Error recording duplicate episode with GUID %s: %s", duplicate.FeedEpisode.GUID, err.Error()))
+		}
+	}
+	for _, record := range reconciliation.Removed {
+		if unconvertedGUIDs[record.FeedGUID] {
+			// The episode is still in the feed, it just failed to parse this time
+			continue
+		}
+		if err := podcasts.MarkEpisodeAsRemoved(record.LanguageCode, record.EpisodeID); err != nil {
+			errs = append(errs, fmt.Sprintf("Error removing podcast episode %s: %s", record.EpisodeID, err.Error()))
+			continue
+		}
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			if err := podcasts.DeactivateEpisodeRecord(tx, record.EpisodeID); err != nil {
+				return err
+			}
+			return podcasts.InsertFeedChange(tx, podcasts.InsertFeedChangeInput{
+				SourceID:     source.ID,
+				SourceSeedID: sourceSeed.ID,
+				EpisodeID:    record.EpisodeID.Ptr(),
+				FeedGUID:     record.FeedGUID,
+				ChangeType:   podcasts.FeedChangeTypeRemoved,
+				Details:      fmt.Sprintf("Episode with GUID %s is no longer in the feed", record.FeedGUID),
+			})
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("Error recording removal of podcast episode %s: %s", record.EpisodeID, err.Error()))
+			continue
+		}
+		c.Infof("Removed podcast episode with ID %s", record.EpisodeID)
+	}
+	if len(conversionErrs) > 0 {
+		c.Warnf("Got %d errors converting episodes for source %s: %s", len(conversionErrs), source.ID, strings.Join(conversionErrs, "\n"))
+	}
 	if len(errs) > 0 {
+		// The feed state is not saved so that the whole feed is retried on the next poll
 		c.Warnf("Got %d errors for source %s: %s", len(errs), source.ID, strings.Join(errs, "\n"))
+		return nil
+	}
+	return database.WithTx(func(tx *sqlx.Tx) error {
+		return podcasts.UpsertFeedState(tx, podcasts.UpsertFeedStateInput{
+			SourceSeedID:       sourceSeed.ID,
+			ETag:               feedData.ETag,
+			LastModifiedHeader: feedData.LastModifiedHeader,
+			ContentHash:        feedData.ContentHash,
+		})
+	})
+}
+
+func addTextMetadataToEpisode(c ctx.LogContext, sourceSeedID content.SourceSeedID, episode ingestrss.PodcastEpisode, toIndex *podcasts.IndexPodcastEpisodeInput) error {
+	showNotes := ingestrss.GetShowNotesTextForEpisode(episode)
+	transcript, err := getTranscriptTextForEpisode(c, sourceSeedID, episode)
+	if err != nil {
+		c.Infof("Error getting transcript for episode with GUID %s: %s. Continuing without it...", episode.ID, err.Error())
+	}
+	bodyText := showNotes
+	if transcript != nil {
+		bodyText = *transcript
+	}
+	var description *string
+	if len(showNotes) > 0 {
+		description = ptr.String(showNotes)
+	}
+	textMetadata, err := textprocessing.ProcessText(textprocessing.ProcessTextInput{
+		BodyText:     bodyText,
+		Description:  description,
+		LanguageCode: toIndex.LanguageCode,
+	})
+	if err != nil {
+		return err
+	}
+	toIndex.ReadabilityScore = ptr.Int64(textMetadata.ReadabilityScore.ToInt64Rounded())
+	if textMetadata.LemmatizedDescription != nil {
+		toIndex.LemmatizedDescription = ptr.String(textMetadata.LemmatizedDescription.LemmatizedText)
+		toIndex.LemmatizedDescriptionIndexMappings = textMetadata.LemmatizedDescription.IndexMappings
+	}
+	if transcript != nil {
+		lemmatizedTranscript, err := textprocessing.LemmatizeText(toIndex.LanguageCode, *transcript)
+		if err != nil {
+			return err
+		}
+		toIndex.LemmatizedTranscript = ptr.String(lemmatizedTranscript.LemmatizedText)
 	}
 	return nil
 }
 
+// Episodes are reindexed whenever their title or date changes,
+// so transcripts are cached instead of being fetched every time
+func getTranscriptTextForEpisode(c ctx.LogContext, sourceSeedID content.SourceSeedID, episode ingestrss.PodcastEpisode) (*string, error) {
+	transcript := ingestrss.GetTranscriptForEpisode(episode)
+	if transcript == nil {
+		return nil, nil
+	}
+	cacheKey := podcasts.CachedTranscriptKey{
+		SourceSeedID:  sourceSeedID,
+		FeedGUID:      episode.ID,
+		TranscriptURL: transcript.URL,
+	}
+	var cachedText *string
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		cachedText, err = podcasts.LookupCachedTranscript(tx, cacheKey)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if cachedText != nil {
+		return cachedText, nil
+	}
+	text, err := ingestrss.GetTextForTranscript(*transcript)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return podcasts.InsertCachedTranscript(tx, cacheKey, *text)
+	}); err != nil {
+		// The transcript is still usable, it just gets fetched again next time
+		c.Warnf("Error caching transcript for episode with GUID %s: %s", episode.ID, err.Error())
+	}
+	return text, nil
+}
+
 func convertIngestEpisodeToModelEpisode(c ctx.LogContext, in ingestrss.PodcastEpisode) (*podcasts.IndexPodcastEpisodeInput, error) {
 	publicationDate, err := time.Parse("Mon, 2 Jan 2006 15:04:05 MST", in.PublicationDate)
 	if err != nil {
@@ -0,0 +1,70 @@
+package contentingestion
+
+import (
+	"babblegraph/model/documents"
+	"babblegraph/model/topicclassifier"
+	"babblegraph/util/ctx"
+	"babblegraph/util/database"
+	"babblegraph/wordsmith"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// The classifier is retrained by a task, so it only
+// needs to be reloaded occasionally
+const topicClassifierRefreshInterval = time.Hour
+
+type cachedTopicClassifier struct {
+	classifier *topicclassifier.Classifier
+	loadedAt   time.Time
+}
+
+var (
+	topicClassifierMutex           sync.Mutex
+	topicClassifiersByLanguageCode = make(map[wordsmith.LanguageCode]cachedTopicClassifier)
+)
+
+func getTopicClassifier(languageCode wordsmith.LanguageCode) (*topicclassifier.Classifier, error) {
+	topicClassifierMutex.Lock()
+	defer topicClassifierMutex.Unlock()
+	if cached, ok := topicClassifiersByLanguageCode[languageCode]; ok && time.Since(cached.loadedAt) < topicClassifierRefreshInterval {
+		return cached.classifier, nil
+	}
+	var classifier *topicclassifier.Classifier
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		classifier, err = topicclassifier.LookupActiveClassifierForLanguage(tx, languageCode)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	topicClassifiersByLanguageCode[languageCode] = cachedTopicClassifier{
+		classifier: classifier,
+		loadedAt:   time.Now(),
+	}
+	return classifier, nil
+}
+
+// classifyTopics assigns topics to documents that do not have a topic mapping.
+// Errors are logged instead of returned so that a missing classifier never blocks indexing.
+func classifyTopics(c ctx.LogContext, languageCode wordsmith.LanguageCode, lemmatizedDescription string) []documents.TopicClassification {
+	classifier, err := getTopicClassifier(languageCode)
+	switch {
+	case err != nil:
+		c.Warnf("Error getting topic classifier for language %s: %s", languageCode, err.Error())
+		return nil
+	case classifier == nil:
+		return nil
+	}
+	var out []documents.TopicClassification
+	for _, classification := range classifier.AssignTopics(strings.Fields(lemmatizedDescription)) {
+		out = append(out, documents.TopicClassification{
+			TopicID:    classification.TopicID,
+			Confidence: classification.Confidence,
+		})
+	}
+	return out
+}
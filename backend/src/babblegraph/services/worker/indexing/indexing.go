@@ -4,8 +4,8 @@ import (
 	"babblegraph/model/content"
 	"babblegraph/model/contenttopics"
 	"babblegraph/model/documents"
-	"babblegraph/services/worker/contentingestion/ingesthtml"
-	"babblegraph/services/worker/textprocessing"
+	"babblegraph/model/ingesthtml"
+	"babblegraph/model/textprocessing"
 	"babblegraph/util/ctx"
 	"babblegraph/util/ptr"
 	"babblegraph/util/urlparser"
@@ -22,6 +22,7 @@ type IndexDocumentInput struct {
 	TopicsForURL           []contenttopics.ContentTopic
 	TopicIDs               []content.TopicID
 	TopicMappingIDs        []content.TopicMappingID
+	TopicClassifications   []documents.TopicClassification
 	SeedJobIngestTimestamp *int64
 }
 
@@ -41,6 +42,7 @@ func IndexDocument(c ctx.LogContext, input IndexDocumentInput) error {
 		Topics:                             input.TopicsForURL,
 		TopicIDs:                           input.TopicIDs,
 		TopicMappingIDs:                    input.TopicMappingIDs,
+		TopicClassifications:               input.TopicClassifications,
 		LemmatizedDescription:              lemmatizedDescriptionText,
 		LemmatizedDescriptionIndexMappings: lemmatizedDescriptionIndexMappings,
 		SeedJobIngestTimestamp:             input.SeedJobIngestTimestamp,
@@ -13,6 +13,7 @@ import (
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
 	"babblegraph/util/elastic"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/env"
 	"babblegraph/wordsmith"
 	"fmt"
@@ -43,6 +44,7 @@ func main() {
 	if err := newsletter.InitializeNewsletterExperiments(); err != nil {
 		bglog.Fatalf("Error initializing newsletter experiments: %s", err.Error())
 	}
+	emailsender.StartMailboxViewerForEnvironment()
 	ingestErrs := make(chan error, 1)
 	schedulerErrs := make(chan error, 1)
 	if currentEnvironmentName != env.EnvironmentLocalTestEmail {
@@ -4,15 +4,16 @@ import (
 	"babblegraph/model/email"
 	"babblegraph/model/emailtemplates"
 	"babblegraph/model/newsletter"
+	"babblegraph/model/newsletterarchive"
 	"babblegraph/model/newslettersendrequests"
+	"babblegraph/model/routes"
 	"babblegraph/model/usernewsletterpreferences"
 	"babblegraph/model/users"
 	"babblegraph/services/worker/newsletterprocessing"
 	"babblegraph/util/async"
 	"babblegraph/util/database"
-	"babblegraph/util/env"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/ptr"
-	"babblegraph/util/ses"
 	"babblegraph/util/storage"
 	"babblegraph/util/timeutils"
 	"encoding/json"
@@ -54,34 +55,7 @@ func StartNewsletterPreloadWorkerThread(newsletterProcessor *newsletterprocessin
 				if err := newslettersendrequests.UpdateSendRequestStatus(tx, sendRequest.ID, newslettersendrequests.PayloadStatusPayloadReady); err != nil {
 					return err
 				}
-				wordsmithAccessor := newsletter.GetDefaultWordsmithAccessor()
-				emailAccessor := newsletter.GetDefaultEmailAccessor(tx)
-				docsAccessor := newsletter.GetDefaultDocumentsAccessor()
-				userAccessor, err := newsletter.GetDefaultUserPreferencesAccessor(c, tx, sendRequest.UserID, sendRequest.LanguageCode, dateOfSendUTCMidnight)
-				if err != nil {
-					return err
-				}
-				contentAccessor, err := newsletter.GetDefaultContentAccessor(tx, sendRequest.LanguageCode)
-				if err != nil {
-					return err
-				}
-				podcastAccessor, err := newsletter.GetDefaultPodcastAccessor(c, tx, sendRequest.LanguageCode, sendRequest.UserID)
-				if err != nil {
-					return err
-				}
-				advertisementAccessor, err := newsletter.GetDefaultAdvertisementAccessor(tx, sendRequest.UserID, sendRequest.LanguageCode)
-				if err != nil {
-					return err
-				}
-				newsletter, err := newsletter.CreateNewsletterVersion2(c, dateOfSendUTCMidnight, newsletter.CreateNewsletterVersion2Input{
-					WordsmithAccessor:     wordsmithAccessor,
-					EmailAccessor:         emailAccessor,
-					UserAccessor:          userAccessor,
-					DocsAccessor:          docsAccessor,
-					ContentAccessor:       contentAccessor,
-					PodcastAccessor:       podcastAccessor,
-					AdvertisementAccessor: advertisementAccessor,
-				})
+				newsletter, err := newsletter.CreateNewsletterVersion2ForUser(c, tx, sendRequest.UserID, sendRequest.LanguageCode, dateOfSendUTCMidnight)
 				switch {
 				case err != nil:
 					return err
@@ -114,12 +88,7 @@ func StartNewsletterFulfillmentWorkerThread(newsletterProcessor *newsletterproce
 	return func(c async.Context) {
 		c.Infof("Starting Newsletter Fulfillment Process")
 		s3Storage := storage.NewS3StorageForEnvironment()
-		emailClient := ses.NewClient(ses.NewClientInput{
-			AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
-			AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
-			AWSRegion:          "us-east-1",
-			FromAddress:        env.MustEnvironmentVariable("EMAIL_ADDRESS"),
-		})
+		emailClient := emailsender.NewEmailSenderForEnvironment()
 		for {
 			sendRequest, err := newsletterProcessor.GetNextSendRequestToFulfill(c)
 			switch {
@@ -157,9 +126,10 @@ func StartNewsletterFulfillmentWorkerThread(newsletterProcessor *newsletterproce
 				if err != nil {
 					return err
 				}
-				var newsletterHTML *string
+				var renderedNewsletter *emailtemplates.RenderedEmail
 				var edition newsletter.Newsletter
 				var emailRecordID email.ID
+				var isWeeklyDigest bool
 				// First we try to unmarshal newsletter
 				if err := json.Unmarshal([]byte(*data), &edition); err != nil {
 					return err
@@ -173,18 +143,31 @@ func StartNewsletterFulfillmentWorkerThread(newsletterProcessor *newsletterproce
 					c.Debugf("ID %s was version 2", sendRequest.ID)
 					c.Debugf("Unmarshalled %+v", newsletterVersion2)
 					emailRecordID = newsletterVersion2.EmailRecordID
-					newsletterHTML, err = emailtemplates.MakeNewsletterVersion2HTML(emailtemplates.MakeNewsletterVersion2HTMLInput{
-						EmailRecordID: newsletterVersion2.EmailRecordID,
-						UserAccessor:  userAccessor,
-						Body:          newsletterVersion2.Body,
+					isWeeklyDigest = newsletterVersion2.WeeklyDigestBody != nil
+					viewInBrowserLink, err := routes.MakeNewsletterViewInBrowserLink(string(sendRequest.ID))
+					if err != nil {
+						return err
+					}
+					renderedNewsletter, err = emailtemplates.MakeNewsletterVersion2(emailtemplates.MakeNewsletterVersion2Input{
+						EmailRecordID:     newsletterVersion2.EmailRecordID,
+						UserAccessor:      userAccessor,
+						Body:              newsletterVersion2.Body,
+						WeeklyDigestBody:  newsletterVersion2.WeeklyDigestBody,
+						ViewInBrowserLink: viewInBrowserLink,
 					})
 					if err != nil {
 						return err
 					}
+					if err := newsletterarchive.InsertArchiveEntry(tx, newsletterarchive.InsertArchiveEntryInput{
+						SendRequest: *sendRequest,
+						Newsletter:  newsletterVersion2,
+					}); err != nil {
+						return err
+					}
 				default:
 					c.Debugf("Unmarshalled %+v", edition)
 					emailRecordID = edition.EmailRecordID
-					newsletterHTML, err = emailtemplates.MakeNewsletterHTML(emailtemplates.MakeNewsletterHTMLInput{
+					renderedNewsletter, err = emailtemplates.MakeNewsletter(emailtemplates.MakeNewsletterInput{
 						EmailRecordID: edition.EmailRecordID,
 						UserAccessor:  userAccessor,
 						Body:          edition.Body,
@@ -193,17 +176,28 @@ func StartNewsletterFulfillmentWorkerThread(newsletterProcessor *newsletterproce
 						return err
 					}
 				}
-				c.Debugf("Created HTML %s", *newsletterHTML)
+				c.Debugf("Created HTML %s", renderedNewsletter.HTMLBody)
 				today, err := userNewsletterPreferences.Schedule.ConvertUTCTimeToUserDate(c, dateOfSendUTCMidnight)
 				if err != nil {
 					return err
 				}
-				subject := fmt.Sprintf("Babblegraph Newsletter - %s %d, %d", today.Month().String(), today.Day(), today.Year())
+				subjectPrefix := "Babblegraph Newsletter"
+				if isWeeklyDigest {
+					subjectPrefix = "Babblegraph Weekly Digest"
+				}
+				subject := fmt.Sprintf("%s - %s %d, %d", subjectPrefix, today.Month().String(), today.Day(), today.Year())
+				unsubscribeURL, err := routes.MakeOneClickUnsubscribeURLForUserID(user.ID)
+				if err != nil {
+					return err
+				}
 				return email.SendEmailWithHTMLBody(tx, emailClient, email.SendEmailWithHTMLBodyInput{
-					ID:           emailRecordID,
-					EmailAddress: user.EmailAddress,
-					Body:         *newsletterHTML,
-					Subject:      subject,
+					ID:             emailRecordID,
+					EmailAddress:   user.EmailAddress,
+					Body:           renderedNewsletter.HTMLBody,
+					TextBody:       renderedNewsletter.TextBody,
+					Subject:        subject,
+					ListName:       ptr.String("newsletter"),
+					UnsubscribeURL: unsubscribeURL,
 				})
 			}); err != nil {
 				c.Errorf("Got error fulfilling send request with ID %s: %s", sendRequest.ID, err.Error())
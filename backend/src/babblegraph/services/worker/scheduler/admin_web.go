@@ -6,21 +6,15 @@ import (
 	"babblegraph/model/emailtemplates"
 	"babblegraph/util/async"
 	"babblegraph/util/database"
-	"babblegraph/util/env"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/ptr"
-	"babblegraph/util/ses"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
 
 func handleSendAdminTwoFactorAuthenticationCode(c async.Context) {
-	emailClient := ses.NewClient(ses.NewClientInput{
-		AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
-		AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
-		AWSRegion:          "us-east-1",
-		FromAddress:        env.MustEnvironmentVariable("EMAIL_ADDRESS"),
-	})
+	emailClient := emailsender.NewEmailSenderForEnvironment()
 	var unfulfilledCodes []admin.TwoFactorAuthenticationCode
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		var err error
@@ -39,7 +33,7 @@ func handleSendAdminTwoFactorAuthenticationCode(c async.Context) {
 			if err := admin.FulfillTwoFactorAuthenticationAttempt(tx, adminUser.ID, code.Code); err != nil {
 				return err
 			}
-			twoFactorEmailHTML, err := emailtemplates.MakeGenericEmailHTML(emailtemplates.MakeGenericEmailHTMLInput{
+			twoFactorEmail, err := emailtemplates.MakeGenericEmail(emailtemplates.MakeGenericEmailInput{
 				EmailTitle:    "Requested Two Factor Code",
 				PreheaderText: "This is a requested two factor authentication code",
 				BeforeParagraphs: []string{
@@ -55,7 +49,8 @@ func handleSendAdminTwoFactorAuthenticationCode(c async.Context) {
 				EmailAddress:    adminUser.EmailAddress,
 				Subject:         "Two Factor Authentication Code",
 				EmailSenderName: ptr.String("Babblegraph Admin"),
-				Body:            *twoFactorEmailHTML,
+				Body:            twoFactorEmail.HTMLBody,
+				TextBody:        twoFactorEmail.TextBody,
 			})
 		}); err != nil {
 			c.Errorf("Error fulfilling attempt for admin ID %s: %s", code.AdminUserID, err.Error())
@@ -69,7 +64,10 @@ func handleCleanUpAdminTwoFactorCodesAndAccessTokens(c async.Context) {
 		if err := admin.RemoveExpiredAccessTokens(tx); err != nil {
 			return err
 		}
-		return admin.RemoveExpiredTwoFactorCodes(tx)
+		if err := admin.RemoveExpiredTwoFactorCodes(tx); err != nil {
+			return err
+		}
+		return admin.RemoveExpiredWebAuthnChallenges(tx)
 	}); err != nil {
 		c.Errorf("Error cleaning up admin two factor codes and access tokens: %s", err.Error())
 	}
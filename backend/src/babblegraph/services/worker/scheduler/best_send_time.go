@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"babblegraph/model/usernewsletterpreferences"
+	"babblegraph/util/async"
+	"babblegraph/util/database"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func handleUpdateBestSendTimes(c async.Context) {
+	var usersWithBestTimeEnabled []usernewsletterpreferences.UserWithBestTimeEnabled
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		usersWithBestTimeEnabled, err = usernewsletterpreferences.GetUsersWithBestTimeEnabled(tx)
+		return err
+	}); err != nil {
+		c.Errorf("Error getting users with best send time enabled: %s", err.Error())
+		return
+	}
+	now := time.Now()
+	var numberOfUsersWithBestSendTime int
+	for _, u := range usersWithBestTimeEnabled {
+		var hasBestSendTime bool
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			var err error
+			hasBestSendTime, err = usernewsletterpreferences.UpdateBestSendTimeForUser(tx, u.UserID, u.LanguageCode, now)
+			return err
+		}); err != nil {
+			c.Errorf("Error updating best send time for user %s: %s", u.UserID, err.Error())
+			continue
+		}
+		if hasBestSendTime {
+			numberOfUsersWithBestSendTime++
+		}
+	}
+	c.Infof("Updated best send times: %d of %d users had enough engagement", numberOfUsersWithBestSendTime, len(usersWithBestTimeEnabled))
+}
@@ -42,6 +42,9 @@ func handleSyncBilling(c async.Context) {
 			if err != nil {
 				return err
 			}
+			if err := syncDunningForPremiumNewsletterSubscription(c, tx, *userID, premiumNewsletterSubscription); err != nil {
+				return err
+			}
 			switch updateType {
 			case billing.PremiumNewsletterSubscriptionUpdateTypeTransitionToActive:
 				switch premiumNewsletterSubscription.PaymentState {
@@ -80,7 +83,8 @@ func handleSyncBilling(c async.Context) {
 					return fmt.Errorf("Unrecognized payment state for subscription ID %s: %d", premiumSubscriptionID, premiumNewsletterSubscription.PaymentState)
 				}
 			case billing.PremiumNewsletterSubscriptionUpdateTypeRemoteUpdated,
-				billing.PremiumNewsletterSubscriptionUpdateTypePaymentMethodAdded:
+				billing.PremiumNewsletterSubscriptionUpdateTypePaymentMethodAdded,
+				billing.PremiumNewsletterSubscriptionUpdateTypePlanChanged:
 				if err := billing.SyncUserAccountWithPremiumNewsletterSubscription(tx, *userID, premiumNewsletterSubscription); err != nil {
 					return err
 				}
@@ -111,6 +115,17 @@ func handleSyncBilling(c async.Context) {
 				if err != nil {
 					return err
 				}
+				if premiumNewsletterSubscription != nil {
+					// Users with a failed payment are handled by the dunning job until it's resolved
+					dunningCycle, err := billing.LookupOpenDunningCycleForPremiumNewsletterSubscription(tx, *premiumNewsletterSubscription.ID)
+					switch {
+					case err != nil:
+						return err
+					case dunningCycle != nil:
+						c.Infof("User ID %s has an open dunning cycle, skipping", sub.UserID)
+						return nil
+					}
+				}
 				switch {
 				case !time.Now().Before(sub.ExpiringAt):
 					// We think the subscription should be expired.
@@ -132,8 +147,17 @@ func handleSyncBilling(c async.Context) {
 					if err := useraccounts.ExpireSubscriptionForUser(tx, sub.UserID); err != nil {
 						return err
 					}
-					if _, err := useraccountsnotifications.EnqueueNotificationRequest(tx, sub.UserID, useraccountsnotifications.NotificationTypePremiumSubscriptionCanceled, time.Now()); err != nil {
+					// Users with a gift or a group license are still premium, so they don't get told it was canceled
+					hasPremiumGrant, err := useraccounts.HasPremiumGrantForUser(tx, sub.UserID)
+					switch {
+					case err != nil:
 						return err
+					case hasPremiumGrant:
+						c.Infof("User ID %s still has premium from a gift or group license", sub.UserID)
+					default:
+						if _, err := useraccountsnotifications.EnqueueNotificationRequest(tx, sub.UserID, useraccountsnotifications.NotificationTypePremiumSubscriptionCanceled, time.Now()); err != nil {
+							return err
+						}
 					}
 					return billing.CancelPremiumNewsletterSubscriptionForUser(c, tx, sub.UserID)
 				default:
@@ -208,3 +232,12 @@ func handleSyncBilling(c async.Context) {
 		}
 	}
 }
+
+func handleSyncBillingPlans(c async.Context) {
+	c.Infof("Starting billing plans sync")
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return billing.SyncPlansWithStripe(c, tx)
+	}); err != nil {
+		c.Errorf("Error syncing billing plans: %s", err.Error())
+	}
+}
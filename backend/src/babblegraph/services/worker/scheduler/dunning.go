@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"babblegraph/model/billing"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/useraccountsnotifications"
+	"babblegraph/model/users"
+	"babblegraph/util/async"
+	"babblegraph/util/ctx"
+	"babblegraph/util/database"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func handleDunning(c async.Context) {
+	c.Infof("Starting dunning job")
+	var dunningCycles []billing.DunningCycle
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		dunningCycles, err = billing.GetOpenDunningCycles(tx)
+		return err
+	}); err != nil {
+		c.Errorf("Error getting open dunning cycles: %s", err.Error())
+		return
+	}
+	c.Infof("Got %d open dunning cycles", len(dunningCycles))
+	for _, dunningCycle := range dunningCycles {
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			premiumNewsletterSubscription, err := billing.GetPremiumNewsletterSubscriptionByID(c, tx, dunningCycle.PremiumNewsletterSubscriptionID)
+			if err != nil {
+				return err
+			}
+			userID, err := premiumNewsletterSubscription.GetUserID()
+			if err != nil {
+				return err
+			}
+			if resolution := billing.GetDunningResolutionForPaymentState(premiumNewsletterSubscription.PaymentState); resolution != nil {
+				// The billing sync updates the user's account for these
+				c.Infof("Dunning cycle %s resolved as %s", dunningCycle.ID, *resolution)
+				return billing.ResolveDunningCycle(tx, dunningCycle.ID, *resolution)
+			}
+			now := time.Now()
+			if !now.Before(dunningCycle.GracePeriodEndsAt) {
+				c.Infof("Grace period for dunning cycle %s is over, downgrading user %s", dunningCycle.ID, *userID)
+				return downgradeUserForDunningCycle(c, tx, *userID, dunningCycle)
+			}
+			reminderType := billing.GetDueDunningReminderType(dunningCycle, now)
+			if reminderType == nil {
+				return nil
+			}
+			notificationType, err := getNotificationTypeForDunningReminderType(*reminderType)
+			if err != nil {
+				return err
+			}
+			if _, err := useraccountsnotifications.EnqueueNotificationRequest(tx, *userID, *notificationType, now); err != nil {
+				return err
+			}
+			return billing.MarkDunningReminderSent(tx, dunningCycle.ID, *reminderType)
+		}); err != nil {
+			c.Errorf("Error handling dunning cycle %s: %s", dunningCycle.ID, err.Error())
+		}
+	}
+}
+
+func downgradeUserForDunningCycle(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, dunningCycle billing.DunningCycle) error {
+	if err := useraccounts.ExpireSubscriptionForUser(tx, userID); err != nil {
+		return err
+	}
+	hasPremiumGrant, err := useraccounts.HasPremiumGrantForUser(tx, userID)
+	switch {
+	case err != nil:
+		return err
+	case hasPremiumGrant:
+		c.Infof("User ID %s still has premium from a gift or group license", userID)
+	default:
+		if _, err := useraccountsnotifications.EnqueueNotificationRequest(tx, userID, useraccountsnotifications.NotificationTypePremiumSubscriptionCanceled, time.Now()); err != nil {
+			return err
+		}
+	}
+	if err := billing.ResolveDunningCycle(tx, dunningCycle.ID, billing.DunningResolutionDowngraded); err != nil {
+		return err
+	}
+	return billing.CancelPremiumNewsletterSubscriptionForUser(c, tx, userID)
+}
+
+// syncDunningForPremiumNewsletterSubscription is called by the billing sync so that
+// users keep premium through the grace period, and so that a payment that goes through
+// closes the dunning cycle even if the dunning job hasn't run yet.
+func syncDunningForPremiumNewsletterSubscription(c ctx.LogContext, tx *sqlx.Tx, userID users.UserID, premiumNewsletterSubscription *billing.PremiumNewsletterSubscription) error {
+	dunningCycle, err := billing.LookupOpenDunningCycleForPremiumNewsletterSubscription(tx, *premiumNewsletterSubscription.ID)
+	switch {
+	case err != nil:
+		return err
+	case dunningCycle == nil:
+		return nil
+	}
+	if resolution := billing.GetDunningResolutionForPaymentState(premiumNewsletterSubscription.PaymentState); resolution != nil {
+		c.Infof("Dunning cycle %s resolved as %s", dunningCycle.ID, *resolution)
+		return billing.ResolveDunningCycle(tx, dunningCycle.ID, *resolution)
+	}
+	switch premiumNewsletterSubscription.PaymentState {
+	case billing.PaymentStatePaymentPending,
+		billing.PaymentStateErrored:
+		if !time.Now().Before(dunningCycle.GracePeriodEndsAt) {
+			// The dunning job takes care of the downgrade
+			return nil
+		}
+		subscriptionLevel, err := useraccounts.LookupAccountSubscriptionLevelForUser(tx, userID)
+		switch {
+		case err != nil:
+			return err
+		case subscriptionLevel == nil,
+			*subscriptionLevel != useraccounts.SubscriptionLevelPremium:
+			return nil
+		}
+		return useraccounts.UpdateSubscriptionExpirationTime(tx, userID, dunningCycle.GracePeriodEndsAt)
+	default:
+		return nil
+	}
+}
+
+func getNotificationTypeForDunningReminderType(reminderType billing.DunningReminderType) (*useraccountsnotifications.NotificationType, error) {
+	var notificationType useraccountsnotifications.NotificationType
+	switch reminderType {
+	case billing.DunningReminderTypePaymentFailed:
+		notificationType = useraccountsnotifications.NotificationTypeDunningPaymentFailed
+	case billing.DunningReminderTypeStillFailing:
+		notificationType = useraccountsnotifications.NotificationTypeDunningStillFailing
+	case billing.DunningReminderTypeFinalNotice:
+		notificationType = useraccountsnotifications.NotificationTypeDunningFinalNotice
+	default:
+		return nil, fmt.Errorf("Unrecognized dunning reminder type %s", reminderType)
+	}
+	return &notificationType, nil
+}
@@ -8,20 +8,14 @@ import (
 	"babblegraph/model/users"
 	"babblegraph/util/async"
 	"babblegraph/util/database"
-	"babblegraph/util/env"
-	"babblegraph/util/ses"
+	"babblegraph/util/emailsender"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
 
 func handlePendingForgotPasswordAttempts(c async.Context) {
-	emailClient := ses.NewClient(ses.NewClientInput{
-		AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
-		AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
-		AWSRegion:          "us-east-1",
-		FromAddress:        env.MustEnvironmentVariable("EMAIL_ADDRESS"),
-	})
+	emailClient := emailsender.NewEmailSenderForEnvironment()
 	var forgotPasswordAttempts []useraccounts.ForgotPasswordAttempt
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		var err error
@@ -58,7 +52,7 @@ func handlePendingForgotPasswordAttempts(c async.Context) {
 	}
 }
 
-func sendForgotPasswordEmailForUserAndAttemptID(tx *sqlx.Tx, sesClient *ses.Client, user users.User, forgotPasswordAttemptID useraccounts.ForgotPasswordAttemptID) error {
+func sendForgotPasswordEmailForUserAndAttemptID(tx *sqlx.Tx, sesClient emailsender.EmailSender, user users.User, forgotPasswordAttemptID useraccounts.ForgotPasswordAttemptID) error {
 	passwordResetLink, err := routes.MakeForgotPasswordLink(forgotPasswordAttemptID)
 	if err != nil {
 		return err
@@ -71,7 +65,7 @@ func sendForgotPasswordEmailForUserAndAttemptID(tx *sqlx.Tx, sesClient *ses.Clie
 	if err != nil {
 		return err
 	}
-	emailHTML, err := emailtemplates.MakeGenericUserEmailHTML(emailtemplates.MakeGenericUserEmailHTMLInput{
+	renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
 		EmailRecordID: emailRecordID,
 		UserAccessor:  userAccessor,
 		EmailTitle:    "Password Reset",
@@ -97,7 +91,8 @@ func sendForgotPasswordEmailForUserAndAttemptID(tx *sqlx.Tx, sesClient *ses.Clie
 		ID:           emailRecordID,
 		EmailAddress: user.EmailAddress,
 		Subject:      "Reset your Babblegraph Account Password",
-		Body:         *emailHTML,
+		Body:         renderedEmail.HTMLBody,
+		TextBody:     renderedEmail.TextBody,
 	})
 }
 
@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"babblegraph/model/email"
+	"babblegraph/model/emailtemplates"
+	"babblegraph/model/localization"
+	"babblegraph/model/routes"
+	"babblegraph/model/useraccounts"
+	"babblegraph/model/users"
+	"babblegraph/util/async"
+	"babblegraph/util/database"
+	"babblegraph/util/emailsender"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func handleGroupLicenseMemberNotifications(c async.Context) {
+	emailClient := emailsender.NewEmailSenderForEnvironment()
+	var members []useraccounts.GroupLicenseMemberToNotify
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		members, err = useraccounts.GetGroupLicenseMembersToNotify(tx)
+		return err
+	}); err != nil {
+		c.Errorf("Error getting group license members to notify: %s", err.Error())
+		return
+	}
+	for _, member := range members {
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			if err := useraccounts.MarkGroupLicenseMemberNotified(tx, member); err != nil {
+				return err
+			}
+			return sendGroupLicenseSeatEmail(tx, emailClient, member)
+		}); err != nil {
+			c.Errorf("Error notifying group license member %s: %s", member.ID, err.Error())
+		}
+	}
+}
+
+// Members don't have to be users yet, so the email
+// tells them whichever step they still need to take to get premium
+func sendGroupLicenseSeatEmail(tx *sqlx.Tx, emailClient emailsender.EmailSender, member useraccounts.GroupLicenseMemberToNotify) error {
+	user, err := users.LookupUserByEmailAddress(tx, member.EmailAddress)
+	switch {
+	case err != nil:
+		return err
+	case user == nil:
+		// no-op
+	case user.Status == users.UserStatusUnsubscribed,
+		user.Status == users.UserStatusBlocklistBounced,
+		user.Status == users.UserStatusBlocklistComplaint,
+		user.Status == users.UserStatusErased:
+		// The seat still works if they sign up again, but
+		// they've asked not to get email from Babblegraph
+		return nil
+	}
+	beforeParagraphs := []string{
+		getInterfaceMessage(localization.MessageKeyEmailGreeting),
+		localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyGroupLicenseSeatBody, localization.Params{
+			"name": member.GroupLicenseName,
+			"date": member.ExpiresAt.Format(emailDateFormat),
+		}),
+	}
+	if user == nil || user.Status != users.UserStatusVerified {
+		renderedEmail, err := emailtemplates.MakeGenericEmail(emailtemplates.MakeGenericEmailInput{
+			EmailTitle:       getInterfaceMessage(localization.MessageKeyGroupLicenseSeatTitle),
+			PreheaderText:    getInterfaceMessage(localization.MessageKeyGroupLicenseSeatPreheader),
+			BeforeParagraphs: append(beforeParagraphs, getInterfaceMessage(localization.MessageKeyGroupLicenseSeatClaimSignUp)),
+			GenericEmailAction: &emailtemplates.GenericEmailAction{
+				Link:       routes.MustGetHomePageURL(),
+				ButtonText: getInterfaceMessage(localization.MessageKeyGroupLicenseSeatButtonSignUp),
+			},
+			AfterParagraphs: []string{
+				getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return email.SendEmailWithHTMLBody(tx, emailClient, email.SendEmailWithHTMLBodyInput{
+			// There is no email record since there may not be a user to attach it to
+			ID:           email.NewEmailRecordID(),
+			EmailAddress: member.EmailAddress,
+			Subject:      getInterfaceMessage(localization.MessageKeyGroupLicenseSeatSubject),
+			Body:         renderedEmail.HTMLBody,
+			TextBody:     renderedEmail.TextBody,
+		})
+	}
+	alreadyHasAccount, err := useraccounts.DoesUserAlreadyHaveAccount(tx, user.ID)
+	if err != nil {
+		return err
+	}
+	var action emailtemplates.GenericEmailAction
+	if alreadyHasAccount {
+		beforeParagraphs = append(beforeParagraphs, getInterfaceMessage(localization.MessageKeyGroupLicenseSeatClaimLogIn))
+		action = emailtemplates.GenericEmailAction{
+			Link:       routes.GetLoginRoute(),
+			ButtonText: getInterfaceMessage(localization.MessageKeyGroupLicenseSeatButtonLogIn),
+		}
+	} else {
+		userCreationLink, err := routes.MakeUserCreationLink(user.ID)
+		if err != nil {
+			return err
+		}
+		beforeParagraphs = append(beforeParagraphs, getInterfaceMessage(localization.MessageKeyGroupLicenseSeatClaimAccount))
+		action = emailtemplates.GenericEmailAction{
+			Link:       *userCreationLink,
+			ButtonText: getInterfaceMessage(localization.MessageKeyGroupLicenseSeatButtonAccount),
+		}
+	}
+	emailRecordID := email.NewEmailRecordID()
+	if err := email.InsertEmailRecord(tx, emailRecordID, user.ID, email.EmailTypeGroupLicenseSeat); err != nil {
+		return err
+	}
+	userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, user.ID)
+	if err != nil {
+		return err
+	}
+	renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
+		EmailRecordID:      emailRecordID,
+		UserAccessor:       userAccessor,
+		EmailTitle:         getInterfaceMessage(localization.MessageKeyGroupLicenseSeatTitle),
+		PreheaderText:      getInterfaceMessage(localization.MessageKeyGroupLicenseSeatPreheader),
+		BeforeParagraphs:   beforeParagraphs,
+		GenericEmailAction: &action,
+		AfterParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return email.SendEmailWithHTMLBody(tx, emailClient, email.SendEmailWithHTMLBodyInput{
+		ID:           emailRecordID,
+		EmailAddress: user.EmailAddress,
+		Subject:      getInterfaceMessage(localization.MessageKeyGroupLicenseSeatSubject),
+		Body:         renderedEmail.HTMLBody,
+		TextBody:     renderedEmail.TextBody,
+	})
+}
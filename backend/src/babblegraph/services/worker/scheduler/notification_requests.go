@@ -4,27 +4,32 @@ import (
 	"babblegraph/model/billing"
 	"babblegraph/model/email"
 	"babblegraph/model/emailtemplates"
+	"babblegraph/model/localization"
 	"babblegraph/model/routes"
 	"babblegraph/model/useraccountsnotifications"
+	"babblegraph/model/userreengagement"
 	"babblegraph/model/users"
 	"babblegraph/util/async"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
-	"babblegraph/util/env"
+	"babblegraph/util/emailsender"
 	"babblegraph/util/ptr"
-	"babblegraph/util/ses"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
 
+const (
+	emailDateFormat = "January 2, 2006"
+
+	// These are used in the List-ID header, so that
+	// mail clients can tell these apart from the newsletter
+	notificationsEmailListName = "notifications"
+	reengagementEmailListName  = "reengagement"
+)
+
 func handlePendingUserAccountNotificationRequests(c async.Context) {
-	emailClient := ses.NewClient(ses.NewClientInput{
-		AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
-		AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
-		AWSRegion:          "us-east-1",
-		FromAddress:        env.MustEnvironmentVariable("EMAIL_ADDRESS"),
-	})
+	emailClient := emailsender.NewEmailSenderForEnvironment()
 	c.Infof("Starting user accounts notification job")
 	var notificationRequests []useraccountsnotifications.NotificationRequest
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
@@ -49,23 +54,45 @@ func handlePendingUserAccountNotificationRequests(c async.Context) {
 				c.Infof("User is not verified, not sending")
 				return nil
 			}
-			var subject, emailHTML *string
+			var subject *string
+			var renderedEmail *emailtemplates.RenderedEmail
 			var emailType *email.EmailType
+			listName := notificationsEmailListName
 			emailRecordID := email.NewEmailRecordID()
 			switch req.Type {
 			case useraccountsnotifications.NotificationTypeTrialEndingSoon:
-				subject = ptr.String("Attention! Your Babblegraph trial is ending soon!")
-				emailHTML, emailType, err = handleTrialEndingSoonNotification(c, tx, emailRecordID, user)
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyTrialEndingSoonSubject))
+				renderedEmail, emailType, err = handleTrialEndingSoonNotification(c, tx, emailRecordID, user)
 			case useraccountsnotifications.NotificationTypePremiumSubscriptionCanceled:
-				subject = ptr.String("Your Babblegraph subscription has ended")
-				emailHTML, emailType, err = handleSubscriptionCanceledNotification(c, tx, emailRecordID, user)
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeySubscriptionCanceledSubject))
+				renderedEmail, emailType, err = handleSubscriptionCanceledNotification(c, tx, emailRecordID, user)
 			case useraccountsnotifications.NotificationTypeNeedPaymentMethodWarning,
 				useraccountsnotifications.NotificationTypeNeedPaymentMethodWarningVeryUrgent:
-				subject = ptr.String("Attention! Add a payment method to keep using Babblegraph")
-				emailHTML, emailType, err = handleNeedPaymentMethodWarningNotification(c, tx, emailRecordID, user)
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyNeedPaymentMethodSubject))
+				renderedEmail, emailType, err = handleNeedPaymentMethodWarningNotification(c, tx, emailRecordID, user)
 			case useraccountsnotifications.NotificationTypePaymentError:
-				subject = ptr.String("Attention! There was an error processing your payment")
-				emailHTML, emailType, err = handlePaymentErrorNotification(c, tx, emailRecordID, user)
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyPaymentErrorSubject))
+				renderedEmail, emailType, err = handlePaymentErrorNotification(c, tx, emailRecordID, user)
+			case useraccountsnotifications.NotificationTypeDunningPaymentFailed:
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyDunningPaymentFailedSubject))
+				renderedEmail, emailType, err = handleDunningNotification(c, tx, emailRecordID, user, billing.DunningReminderTypePaymentFailed)
+			case useraccountsnotifications.NotificationTypeDunningStillFailing:
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyDunningStillFailingSubject))
+				renderedEmail, emailType, err = handleDunningNotification(c, tx, emailRecordID, user, billing.DunningReminderTypeStillFailing)
+			case useraccountsnotifications.NotificationTypeDunningFinalNotice:
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyDunningFinalNoticeSubject))
+				renderedEmail, emailType, err = handleDunningNotification(c, tx, emailRecordID, user, billing.DunningReminderTypeFinalNotice)
+			case useraccountsnotifications.NotificationTypeReengagement:
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyReengagementSubject))
+				listName = reengagementEmailListName
+				renderedEmail, emailType, err = handleReengagementNotification(c, tx, emailRecordID, user)
+			case useraccountsnotifications.NotificationTypeReengagementSunset:
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyReengagementSunsetSubject))
+				listName = reengagementEmailListName
+				renderedEmail, emailType, err = handleReengagementSunsetNotification(c, tx, emailRecordID, user)
+			case useraccountsnotifications.NotificationTypeUserDataRequest:
+				subject = ptr.String(getInterfaceMessage(localization.MessageKeyUserDataRequestSubject))
+				renderedEmail, emailType, err = handleUserDataRequestNotification(c, tx, emailRecordID, user)
 			case useraccountsnotifications.NotificationTypeNeedPaymentMethodWarningUrgent,
 				useraccountsnotifications.NotificationTypeAccountCreatedDEPRECATED,
 				useraccountsnotifications.NotificationTypeInitialPremiumInformationDEPRECATED:
@@ -76,17 +103,24 @@ func handlePendingUserAccountNotificationRequests(c async.Context) {
 			if err != nil {
 				return err
 			}
-			if emailHTML == nil {
+			if renderedEmail == nil {
 				return nil
 			}
 			if err := email.InsertEmailRecord(tx, emailRecordID, user.ID, *emailType); err != nil {
 				return err
 			}
+			unsubscribeURL, err := routes.MakeOneClickUnsubscribeURLForUserID(user.ID)
+			if err != nil {
+				return err
+			}
 			return email.SendEmailWithHTMLBody(tx, emailClient, email.SendEmailWithHTMLBodyInput{
-				ID:           emailRecordID,
-				EmailAddress: user.EmailAddress,
-				Subject:      *subject,
-				Body:         *emailHTML,
+				ID:             emailRecordID,
+				EmailAddress:   user.EmailAddress,
+				Subject:        *subject,
+				Body:           renderedEmail.HTMLBody,
+				TextBody:       renderedEmail.TextBody,
+				ListName:       ptr.String(listName),
+				UnsubscribeURL: unsubscribeURL,
 			})
 		}); err != nil {
 			c.Errorf("Error fulfilling request %s: %s", req.ID, err.Error())
@@ -96,7 +130,12 @@ func handlePendingUserAccountNotificationRequests(c async.Context) {
 	}
 }
 
-func handleTrialEndingSoonNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*string, *email.EmailType, error) {
+// TODO(multiple-languages): use the user's interface language once it can be set
+func getInterfaceMessage(key localization.MessageKey) string {
+	return localization.GetMessage(localization.DefaultInterfaceLocale, key, nil)
+}
+
+func handleTrialEndingSoonNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
 	premiumSubscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
 	switch {
 	case err != nil:
@@ -110,22 +149,22 @@ func handleTrialEndingSoonNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecor
 			return nil, nil, err
 		}
 		beforeParagraphs := []string{
-			"Hello!",
-			"We hope you’ve been enjoying Babblegraph. If not, your feedback is always appreciated!",
-			"Just respond to this email with any ideas or comments you have about what Babblegraph could be doing better.",
-			"This email is to let you know that your trial is almost over.",
+			getInterfaceMessage(localization.MessageKeyEmailGreeting),
+			getInterfaceMessage(localization.MessageKeyTrialEndingSoonHopeYouEnjoyed),
+			getInterfaceMessage(localization.MessageKeyTrialEndingSoonAskForFeedback),
+			getInterfaceMessage(localization.MessageKeyTrialEndingSoonTrialAlmostOver),
 		}
 		var action *emailtemplates.GenericEmailAction
 		switch premiumSubscription.PaymentState {
 		case billing.PaymentStateTrialNoPaymentMethod:
-			beforeParagraphs = append(beforeParagraphs, "It looks like you haven’t added a payment method yet. If you’d like to continue using Babblegraph, then you’ll need to add a payment method. You can do that at this link")
+			beforeParagraphs = append(beforeParagraphs, getInterfaceMessage(localization.MessageKeyTrialEndingSoonNoPaymentMethod))
 			checkoutLink, err := routes.MakePremiumSubscriptionCheckoutLink(user.ID)
 			if err != nil {
 				return nil, nil, err
 			}
 			action = &emailtemplates.GenericEmailAction{
 				Link:       *checkoutLink,
-				ButtonText: "Add a payment method to your account",
+				ButtonText: getInterfaceMessage(localization.MessageKeyEmailAddPaymentMethodButton),
 			}
 		case billing.PaymentStateTrialPaymentMethodAdded:
 			paymentSettingsRoute, err := routes.MakePaymentSettingsRouteForUserID(user.ID)
@@ -133,13 +172,19 @@ func handleTrialEndingSoonNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecor
 				return nil, nil, err
 			}
 			if !premiumSubscription.IsAutoRenewEnabled {
-				beforeParagraphs = append(beforeParagraphs, "You have a payment method added to your account, but your subscription will is not set to automatically renew. If you would like to continue to use Babblegraph, you’ll need to turn auto-renew on.")
+				beforeParagraphs = append(beforeParagraphs, getInterfaceMessage(localization.MessageKeyTrialEndingSoonAutoRenewDisabled))
 				action = &emailtemplates.GenericEmailAction{
 					Link:       *paymentSettingsRoute,
-					ButtonText: "Manage your subscription settings here",
+					ButtonText: getInterfaceMessage(localization.MessageKeyTrialEndingSoonAutoRenewDisabledButton),
 				}
 			} else {
-				beforeParagraphs = append(beforeParagraphs, "You have a payment method added to your account, so your subscription will automatically renew in a few days, and you’ll be charged $29 then.")
+				priceText, err := getPriceTextForPremiumNewsletterSubscription(*premiumSubscription)
+				if err != nil {
+					return nil, nil, err
+				}
+				beforeParagraphs = append(beforeParagraphs, localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyTrialEndingSoonAutoRenewEnabled, localization.Params{
+					"price": *priceText,
+				}))
 			}
 		case billing.PaymentStateCreatedUnpaid,
 			billing.PaymentStateTerminated,
@@ -149,26 +194,26 @@ func handleTrialEndingSoonNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecor
 			return nil, nil, fmt.Errorf("Invalid payment state for premium subscription %s: %d", *premiumSubscription.ID, premiumSubscription.PaymentState)
 		}
 
-		emailHTML, err := emailtemplates.MakeGenericUserEmailHTML(emailtemplates.MakeGenericUserEmailHTMLInput{
+		renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
 			EmailRecordID:      emailRecordID,
 			UserAccessor:       userAccessor,
-			EmailTitle:         "Your Babblegraph trial is ending soon",
-			PreheaderText:      "Your trial of Babblegraph is set to expire in the next few days",
+			EmailTitle:         getInterfaceMessage(localization.MessageKeyTrialEndingSoonTitle),
+			PreheaderText:      getInterfaceMessage(localization.MessageKeyTrialEndingSoonPreheader),
 			BeforeParagraphs:   beforeParagraphs,
 			GenericEmailAction: action,
 			AfterParagraphs: []string{
-				"If you have any questions or believe there is an error in this email, just respond to this email.",
-				"Thank you so much for trying out Babblegraph!",
+				getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+				getInterfaceMessage(localization.MessageKeyTrialEndingSoonThanks),
 			},
 		})
 		if err != nil {
 			return nil, nil, err
 		}
-		return emailHTML, email.EmailTypeTrialEndingSoon.Ptr(), nil
+		return renderedEmail, email.EmailTypeTrialEndingSoon.Ptr(), nil
 	}
 }
 
-func handleSubscriptionCanceledNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*string, *email.EmailType, error) {
+func handleSubscriptionCanceledNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
 	premiumSubscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
 	switch {
 	case err != nil:
@@ -185,36 +230,36 @@ func handleSubscriptionCanceledNotification(c ctx.LogContext, tx *sqlx.Tx, email
 		if err != nil {
 			return nil, nil, err
 		}
-		emailHTML, err := emailtemplates.MakeGenericUserEmailHTML(emailtemplates.MakeGenericUserEmailHTMLInput{
+		renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
 			EmailRecordID: emailRecordID,
 			UserAccessor:  userAccessor,
-			EmailTitle:    "Your Babblegraph subscription has ended",
-			PreheaderText: "Your Babblegraph subscription has expired",
+			EmailTitle:    getInterfaceMessage(localization.MessageKeySubscriptionCanceledTitle),
+			PreheaderText: getInterfaceMessage(localization.MessageKeySubscriptionCanceledPreheader),
 			BeforeParagraphs: []string{
-				"Hello!",
-				"Thanks so much for trying Babblegraph!",
-				"This email is to let you know that your subscription to Babblegraph has ended. You will no longer be charged for the subscription.",
-				"If you wanted to continue to use Babblegraph, you can restart your subscription at the link below.",
+				getInterfaceMessage(localization.MessageKeyEmailGreeting),
+				getInterfaceMessage(localization.MessageKeySubscriptionCanceledThanks),
+				getInterfaceMessage(localization.MessageKeySubscriptionCanceledHasEnded),
+				getInterfaceMessage(localization.MessageKeySubscriptionCanceledCanRestart),
 			},
 			GenericEmailAction: &emailtemplates.GenericEmailAction{
 				Link:       *checkoutLink,
-				ButtonText: "Click here to restart your subscription",
+				ButtonText: getInterfaceMessage(localization.MessageKeySubscriptionCanceledRestartButton),
 			},
 			AfterParagraphs: []string{
-				"If you are not restarting your subscription and you see any new charges on your credit card statement from Babblegraph, just respond to this email and we’ll get it sorted out.",
-				"Lastly, before you go, we’d love to know what we could do better! You can respond directly to this email to give feedback.",
-				"If you have any questions or believe there is an error in this email, just respond to this email.",
-				"Thanks again so much for trying out Babblegraph!",
+				getInterfaceMessage(localization.MessageKeySubscriptionCanceledNewCharges),
+				getInterfaceMessage(localization.MessageKeySubscriptionCanceledAskForFeedback),
+				getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+				getInterfaceMessage(localization.MessageKeyEmailThanksForTryingSignOff),
 			},
 		})
 		if err != nil {
 			return nil, nil, err
 		}
-		return emailHTML, email.EmailTypePremiumSubscriptionCanceled.Ptr(), nil
+		return renderedEmail, email.EmailTypePremiumSubscriptionCanceled.Ptr(), nil
 	}
 }
 
-func handleNeedPaymentMethodWarningNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*string, *email.EmailType, error) {
+func handleNeedPaymentMethodWarningNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
 	premiumSubscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
 	switch {
 	case err != nil:
@@ -233,29 +278,29 @@ func handleNeedPaymentMethodWarningNotification(c ctx.LogContext, tx *sqlx.Tx, e
 			if err != nil {
 				return nil, nil, err
 			}
-			emailHTML, err := emailtemplates.MakeGenericUserEmailHTML(emailtemplates.MakeGenericUserEmailHTMLInput{
+			renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
 				EmailRecordID: emailRecordID,
 				UserAccessor:  userAccessor,
-				EmailTitle:    "Your trial is about to expire with no payment method",
-				PreheaderText: "Your trial is ending, but you don’t have a pyament method added.",
+				EmailTitle:    getInterfaceMessage(localization.MessageKeyNeedPaymentMethodTitle),
+				PreheaderText: getInterfaceMessage(localization.MessageKeyNeedPaymentMethodPreheader),
 				BeforeParagraphs: []string{
-					"Hello!",
-					"This email is to let you know that your subscription is ending soon, but you have no payment method added to your account.",
-					"If you would like to continue to use Babblegraph, you’ll need to add a payment method. You can do that at the link below.",
+					getInterfaceMessage(localization.MessageKeyEmailGreeting),
+					getInterfaceMessage(localization.MessageKeyNeedPaymentMethodEndingSoon),
+					getInterfaceMessage(localization.MessageKeyNeedPaymentMethodAddPaymentMethod),
 				},
 				GenericEmailAction: &emailtemplates.GenericEmailAction{
 					Link:       *checkoutLink,
-					ButtonText: "Add a payment method to your account",
+					ButtonText: getInterfaceMessage(localization.MessageKeyEmailAddPaymentMethodButton),
 				},
 				AfterParagraphs: []string{
-					"If you have any questions or believe there is an error in this email, just respond to this email.",
-					"Thanks again so much for trying out Babblegraph!",
+					getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+					getInterfaceMessage(localization.MessageKeyEmailThanksForTryingSignOff),
 				},
 			})
 			if err != nil {
 				return nil, nil, err
 			}
-			return emailHTML, email.EmailTypeTrialEndingSoonActionRequired.Ptr(), nil
+			return renderedEmail, email.EmailTypeTrialEndingSoonActionRequired.Ptr(), nil
 		case billing.PaymentStateTerminated,
 			billing.PaymentStateErrored,
 			billing.PaymentStateActive,
@@ -267,7 +312,7 @@ func handleNeedPaymentMethodWarningNotification(c ctx.LogContext, tx *sqlx.Tx, e
 	return nil, nil, nil
 }
 
-func handlePaymentErrorNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*string, *email.EmailType, error) {
+func handlePaymentErrorNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
 	premiumSubscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
 	switch {
 	case err != nil:
@@ -292,30 +337,246 @@ func handlePaymentErrorNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID
 			if err != nil {
 				return nil, nil, err
 			}
-			emailHTML, err := emailtemplates.MakeGenericUserEmailHTML(emailtemplates.MakeGenericUserEmailHTMLInput{
+			renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
 				EmailRecordID: emailRecordID,
 				UserAccessor:  userAccessor,
-				EmailTitle:    "There was an error processing your payment",
-				PreheaderText: "Your subscription is about to end and there was an error processing your payment.",
+				EmailTitle:    getInterfaceMessage(localization.MessageKeyPaymentErrorTitle),
+				PreheaderText: getInterfaceMessage(localization.MessageKeyPaymentErrorPreheader),
 				BeforeParagraphs: []string{
-					"Hello!",
-					"This email is to let you know that there has been an error processing the payment method that we have on file.",
-					"If you would like to continue to use Babblegraph, you’ll need to add a valid payment method. You can do that at the link below.",
+					getInterfaceMessage(localization.MessageKeyEmailGreeting),
+					getInterfaceMessage(localization.MessageKeyPaymentErrorErrorProcessing),
+					getInterfaceMessage(localization.MessageKeyPaymentErrorAddPaymentMethod),
 				},
 				GenericEmailAction: &emailtemplates.GenericEmailAction{
 					Link:       *paymentSettingsRoute,
-					ButtonText: "Edit the payment method on your account",
+					ButtonText: getInterfaceMessage(localization.MessageKeyPaymentErrorButton),
 				},
 				AfterParagraphs: []string{
-					"If you have any questions or believe there is an error in this email, just respond to this email.",
-					"Thanks again so much for trying out Babblegraph!",
+					getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+					getInterfaceMessage(localization.MessageKeyEmailThanksForTryingSignOff),
 				},
 			})
 			if err != nil {
 				return nil, nil, err
 			}
-			return emailHTML, email.EmailTypePaymentFailureNotification.Ptr(), nil
+			return renderedEmail, email.EmailTypePaymentFailureNotification.Ptr(), nil
 		}
 	}
 	return nil, nil, nil
 }
+
+func handleDunningNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User, reminderType billing.DunningReminderType) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
+	premiumSubscription, err := billing.LookupPremiumNewsletterSubscriptionForUser(c, tx, user.ID)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case premiumSubscription == nil:
+		c.Warnf("Need to send dunning email, but there is no subscription for user %s", user.ID)
+		return nil, nil, nil
+	}
+	dunningCycle, err := billing.LookupOpenDunningCycleForPremiumNewsletterSubscription(tx, *premiumSubscription.ID)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case dunningCycle == nil:
+		c.Infof("Payment for user %s was resolved before the dunning email was sent, skipping", user.ID)
+		return nil, nil, nil
+	}
+	userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	paymentSettingsRoute, err := routes.MakePaymentSettingsRouteForUserID(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	params := localization.Params{
+		"amount": billing.FormatPriceCents(dunningCycle.AmountDueCents, dunningCycle.Currency),
+		"date":   dunningCycle.GracePeriodEndsAt.Format(emailDateFormat),
+	}
+	keepPremiumParagraph := localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyDunningKeepPremiumUntil, params)
+	var titleKey, preheaderKey localization.MessageKey
+	var bodyParagraphs []string
+	var emailType email.EmailType
+	switch reminderType {
+	case billing.DunningReminderTypePaymentFailed:
+		titleKey, preheaderKey = localization.MessageKeyDunningPaymentFailedTitle, localization.MessageKeyDunningPaymentFailedPreheader
+		bodyParagraphs = []string{
+			localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyDunningPaymentFailedBody, params),
+			keepPremiumParagraph,
+		}
+		emailType = email.EmailTypeDunningPaymentFailed
+	case billing.DunningReminderTypeStillFailing:
+		titleKey, preheaderKey = localization.MessageKeyDunningStillFailingTitle, localization.MessageKeyDunningStillFailingPreheader
+		bodyParagraphs = []string{
+			localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyDunningStillFailingBody, params),
+			keepPremiumParagraph,
+		}
+		emailType = email.EmailTypeDunningStillFailing
+	case billing.DunningReminderTypeFinalNotice:
+		titleKey, preheaderKey = localization.MessageKeyDunningFinalNoticeTitle, localization.MessageKeyDunningFinalNoticePreheader
+		bodyParagraphs = []string{
+			localization.GetMessage(localization.DefaultInterfaceLocale, localization.MessageKeyDunningFinalNoticeBody, params),
+		}
+		emailType = email.EmailTypeDunningFinalNotice
+	default:
+		return nil, nil, fmt.Errorf("Unrecognized dunning reminder type %s", reminderType)
+	}
+	beforeParagraphs := append([]string{getInterfaceMessage(localization.MessageKeyEmailGreeting)}, bodyParagraphs...)
+	beforeParagraphs = append(beforeParagraphs, getInterfaceMessage(localization.MessageKeyDunningUpdatePaymentMethod))
+	renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
+		EmailRecordID:    emailRecordID,
+		UserAccessor:     userAccessor,
+		EmailTitle:       getInterfaceMessage(titleKey),
+		PreheaderText:    getInterfaceMessage(preheaderKey),
+		BeforeParagraphs: beforeParagraphs,
+		GenericEmailAction: &emailtemplates.GenericEmailAction{
+			Link:       *paymentSettingsRoute,
+			ButtonText: getInterfaceMessage(localization.MessageKeyDunningButton),
+		},
+		AfterParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return renderedEmail, emailType.Ptr(), nil
+}
+
+func handleReengagementNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
+	stage, err := userreengagement.LookupStageForUser(tx, user.ID)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case stage == userreengagement.StageActive:
+		c.Infof("User %s engaged again before reengagement email was sent, skipping", user.ID)
+		return nil, nil, nil
+	}
+	userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	newsletterPreferencesLink, err := routes.MakeNewsletterPreferencesLink(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
+		EmailRecordID: emailRecordID,
+		UserAccessor:  userAccessor,
+		EmailTitle:    getInterfaceMessage(localization.MessageKeyReengagementTitle),
+		PreheaderText: getInterfaceMessage(localization.MessageKeyReengagementPreheader),
+		BeforeParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyEmailGreeting),
+			getInterfaceMessage(localization.MessageKeyReengagementHaventSeenYou),
+			getInterfaceMessage(localization.MessageKeyReengagementAdjustNewsletter),
+		},
+		GenericEmailAction: &emailtemplates.GenericEmailAction{
+			Link:       *newsletterPreferencesLink,
+			ButtonText: getInterfaceMessage(localization.MessageKeyReengagementButton),
+		},
+		AfterParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyReengagementWillSendLess),
+			getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return renderedEmail, email.EmailTypeReengagement.Ptr(), nil
+}
+
+func handleReengagementSunsetNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
+	stage, err := userreengagement.LookupStageForUser(tx, user.ID)
+	switch {
+	case err != nil:
+		return nil, nil, err
+	case stage != userreengagement.StageSunset:
+		c.Infof("User %s is no longer sunset, skipping", user.ID)
+		return nil, nil, nil
+	}
+	userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	newsletterPreferencesLink, err := routes.MakeNewsletterPreferencesLink(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
+		EmailRecordID: emailRecordID,
+		UserAccessor:  userAccessor,
+		EmailTitle:    getInterfaceMessage(localization.MessageKeyReengagementSunsetTitle),
+		PreheaderText: getInterfaceMessage(localization.MessageKeyReengagementSunsetPreheader),
+		BeforeParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyEmailGreeting),
+			getInterfaceMessage(localization.MessageKeyReengagementSunsetPaused),
+			getInterfaceMessage(localization.MessageKeyReengagementSunsetCanResume),
+		},
+		GenericEmailAction: &emailtemplates.GenericEmailAction{
+			Link:       *newsletterPreferencesLink,
+			ButtonText: getInterfaceMessage(localization.MessageKeyReengagementSunsetButton),
+		},
+		AfterParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyEmailQuestionsSignOff),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return renderedEmail, email.EmailTypeReengagementSunset.Ptr(), nil
+}
+
+func handleUserDataRequestNotification(c ctx.LogContext, tx *sqlx.Tx, emailRecordID email.ID, user *users.User) (*emailtemplates.RenderedEmail, *email.EmailType, error) {
+	userAccessor, err := emailtemplates.GetDefaultUserAccessor(tx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	userDataRequestLink, err := routes.MakeUserDataRequestLink(user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailtemplates.MakeGenericUserEmailInput{
+		EmailRecordID: emailRecordID,
+		UserAccessor:  userAccessor,
+		EmailTitle:    getInterfaceMessage(localization.MessageKeyUserDataRequestTitle),
+		PreheaderText: getInterfaceMessage(localization.MessageKeyUserDataRequestPreheader),
+		BeforeParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyEmailGreeting),
+			getInterfaceMessage(localization.MessageKeyUserDataRequestBody),
+			getInterfaceMessage(localization.MessageKeyUserDataRequestDidNotAsk),
+		},
+		GenericEmailAction: &emailtemplates.GenericEmailAction{
+			Link:       *userDataRequestLink,
+			ButtonText: getInterfaceMessage(localization.MessageKeyUserDataRequestButton),
+		},
+		AfterParagraphs: []string{
+			getInterfaceMessage(localization.MessageKeyUserDataRequestLinkLifetime),
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return renderedEmail, email.EmailTypeUserDataRequest.Ptr(), nil
+}
+
+// getPriceTextForPremiumNewsletterSubscription uses the discounted price on the
+// subscription, and only uses the plan for the currency and billing interval
+func getPriceTextForPremiumNewsletterSubscription(premiumSubscription billing.PremiumNewsletterSubscription) (*string, error) {
+	plan, err := billing.GetPlanForPremiumNewsletterSubscription(premiumSubscription)
+	if err != nil {
+		return nil, err
+	}
+	priceCents := plan.PriceCents
+	if premiumSubscription.PriceCents != nil {
+		priceCents = *premiumSubscription.PriceCents
+	}
+	messageKey := localization.MessageKeyBillingPricePerYear
+	if plan.BillingInterval == billing.BillingIntervalMonth {
+		messageKey = localization.MessageKeyBillingPricePerMonth
+	}
+	priceText := localization.GetMessage(localization.DefaultInterfaceLocale, messageKey, localization.Params{
+		"amount": billing.FormatPriceCents(priceCents, plan.Currency),
+	})
+	return &priceText, nil
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"babblegraph/model/useraccountsnotifications"
+	"babblegraph/model/userreengagement"
+	"babblegraph/util/async"
+	"babblegraph/util/database"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func handleUserReengagement(c async.Context) {
+	var summaries []userreengagement.EngagementSummary
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		var err error
+		summaries, err = userreengagement.GetEngagementSummariesForVerifiedUsers(tx)
+		return err
+	}); err != nil {
+		c.Errorf("Error getting engagement summaries: %s", err.Error())
+		return
+	}
+	now := time.Now()
+	numberOfUsersByStage := make(map[userreengagement.Stage]int)
+	for _, summary := range summaries {
+		currentStage := userreengagement.StageActive
+		if summary.CurrentStage != nil {
+			currentStage = *summary.CurrentStage
+		}
+		nextStage := userreengagement.GetNextStage(summary, now)
+		numberOfUsersByStage[nextStage]++
+		if nextStage == currentStage {
+			continue
+		}
+		if err := database.WithTx(func(tx *sqlx.Tx) error {
+			if err := userreengagement.UpdateStageForUser(tx, summary.UserID, nextStage); err != nil {
+				return err
+			}
+			var notificationType useraccountsnotifications.NotificationType
+			switch nextStage {
+			case userreengagement.StageDormant:
+				notificationType = useraccountsnotifications.NotificationTypeReengagement
+			case userreengagement.StageSunset:
+				notificationType = useraccountsnotifications.NotificationTypeReengagementSunset
+			default:
+				// Reducing frequency happens quietly, since the
+				// reengagement email already warned the user about it
+				return nil
+			}
+			_, err := useraccountsnotifications.EnqueueNotificationRequest(tx, summary.UserID, notificationType, now)
+			return err
+		}); err != nil {
+			c.Errorf("Error moving user %s from stage %s to %s: %s", summary.UserID, currentStage, nextStage, err.Error())
+			continue
+		}
+		c.Infof("Moved user %s from stage %s to %s", summary.UserID, currentStage, nextStage)
+	}
+	c.Infof("Finished reengagement job: %+v", numberOfUsersByStage)
+}
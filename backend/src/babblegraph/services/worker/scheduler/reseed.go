@@ -2,9 +2,9 @@ package scheduler
 
 import (
 	"babblegraph/model/content"
+	"babblegraph/model/ingesthtml"
 	"babblegraph/model/links2"
 	"babblegraph/model/urltopicmapping"
-	"babblegraph/services/worker/contentingestion/ingesthtml"
 	"babblegraph/util/async"
 	"babblegraph/util/ctx"
 	"babblegraph/util/database"
@@ -21,23 +21,37 @@ func StartScheduler(errs chan error) error {
 		c.AddFunc("30 0 * * *", async.WithContext(errs, "archive-forgot-passwords", handleArchiveForgotPasswordAttempts).Func())
 		c.AddFunc("30 2 * * *", async.WithContext(errs, "refetch", fetchNewLinksForSeedURLs).Func())
 		c.AddFunc("30 3 * * *", async.WithContext(errs, "admin-2fa-cleanup", handleCleanUpAdminTwoFactorCodesAndAccessTokens).Func())
+		c.AddFunc("45 3 * * *", async.WithContext(errs, "user-sessions-cleanup", handleCleanUpUserSessions).Func())
 		c.AddFunc("30 4 * * *", async.WithContext(errs, "cleanup-newsletters", handleCleanupOldNewsletter).Func())
+		c.AddFunc("30 5 * * *", async.WithContext(errs, "quarantine-sources", handleQuarantineFailingSources).Func())
+		c.AddFunc("0 1 * * *", async.WithContext(errs, "best-send-times", handleUpdateBestSendTimes).Func())
+		c.AddFunc("0 6 * * *", async.WithContext(errs, "user-reengagement", handleUserReengagement).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "pending-verifications", handlePendingVerifications).Func())
 		c.AddFunc("*/3 * * * *", async.WithContext(errs, "forgot-passwords", handlePendingForgotPasswordAttempts).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "send-2fa-codes", handleSendAdminTwoFactorAuthenticationCode).Func())
 		c.AddFunc("*/7 * * * *", async.WithContext(errs, "sync-billing", handleSyncBilling).Func())
+		c.AddFunc("20 * * * *", async.WithContext(errs, "dunning", handleDunning).Func())
+		c.AddFunc("15 4 * * *", async.WithContext(errs, "sync-billing-plans", handleSyncBillingPlans).Func())
 		c.AddFunc("*/10 * * * *", async.WithContext(errs, "user-account-notifications", handlePendingUserAccountNotificationRequests).Func())
+		c.AddFunc("*/10 * * * *", async.WithContext(errs, "group-license-notifications", handleGroupLicenseMemberNotifications).Func())
 	case env.EnvironmentLocal,
 		env.EnvironmentLocalTestEmail:
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "cleanup-newsletters", handleCleanupOldNewsletter).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "admin-2fa-cleanup", handleCleanUpAdminTwoFactorCodesAndAccessTokens).Func())
+		c.AddFunc("*/30 * * * *", async.WithContext(errs, "user-sessions-cleanup", handleCleanUpUserSessions).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "pending-verifications", handlePendingVerifications).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "forgot-passwords", handlePendingForgotPasswordAttempts).Func())
 		c.AddFunc("*/5 * * * *", async.WithContext(errs, "archive-forgot-passwords", handleArchiveForgotPasswordAttempts).Func())
 		c.AddFunc("*/30 * * * *", async.WithContext(errs, "refetch", fetchNewLinksForSeedURLs).Func())
+		c.AddFunc("*/30 * * * *", async.WithContext(errs, "quarantine-sources", handleQuarantineFailingSources).Func())
+		c.AddFunc("*/30 * * * *", async.WithContext(errs, "best-send-times", handleUpdateBestSendTimes).Func())
+		c.AddFunc("*/30 * * * *", async.WithContext(errs, "user-reengagement", handleUserReengagement).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "send-2fa-codes", handleSendAdminTwoFactorAuthenticationCode).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "sync-billing", handleSyncBilling).Func())
+		c.AddFunc("*/1 * * * *", async.WithContext(errs, "dunning", handleDunning).Func())
+		c.AddFunc("*/30 * * * *", async.WithContext(errs, "sync-billing-plans", handleSyncBillingPlans).Func())
 		c.AddFunc("*/1 * * * *", async.WithContext(errs, "user-account-notifications", handlePendingUserAccountNotificationRequests).Func())
+		c.AddFunc("*/1 * * * *", async.WithContext(errs, "group-license-notifications", handleGroupLicenseMemberNotifications).Func())
 	case env.EnvironmentLocalNoEmail:
 		async.WithContext(errs, "sync-billing", handleSyncBilling).Func()()
 		async.WithContext(errs, "refetch", fetchNewLinksForSeedURLs).Func()()
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"babblegraph/model/admin"
+	"babblegraph/model/content"
+	"babblegraph/model/email"
+	"babblegraph/model/emailtemplates"
+	"babblegraph/util/async"
+	"babblegraph/util/database"
+	"babblegraph/util/emailsender"
+	"babblegraph/util/ptr"
+	"babblegraph/util/timeutils"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const sourceQuarantineWindowDays = 3
+
+func handleQuarantineFailingSources(c async.Context) {
+	windowStart := timeutils.ConvertToMidnight(time.Now().UTC().Add(-1 * sourceQuarantineWindowDays * 24 * time.Hour))
+	var quarantinedSources []content.Source
+	var quarantinedMetrics []content.SourceIngestionMetrics
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		metrics, err := content.GetIngestionMetricsForQuarantine(tx, windowStart)
+		if err != nil {
+			return err
+		}
+		for _, m := range metrics {
+			if !m.ShouldQuarantine() {
+				continue
+			}
+			source, err := content.GetSource(tx, m.SourceID)
+			if err != nil {
+				return err
+			}
+			if !source.IsActive {
+				continue
+			}
+			c.Infof("Quarantining source %s after %d parse failures out of %d links", source.ID, m.ParseFailures, m.LinksFetched)
+			if err := content.QuarantineSource(tx, m); err != nil {
+				return err
+			}
+			quarantinedSources = append(quarantinedSources, *source)
+			quarantinedMetrics = append(quarantinedMetrics, m)
+		}
+		return nil
+	}); err != nil {
+		c.Errorf("Error quarantining failing sources: %s", err.Error())
+		return
+	}
+	if len(quarantinedSources) == 0 {
+		return
+	}
+	var paragraphs []string
+	for idx, source := range quarantinedSources {
+		m := quarantinedMetrics[idx]
+		paragraphs = append(paragraphs, fmt.Sprintf("%s (%s): %d of %d links failed to parse since %s", source.Title, source.URL, m.ParseFailures, m.LinksFetched, m.WindowStart.Format("2006-01-02")))
+	}
+	emailClient := emailsender.NewEmailSenderForEnvironment()
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		permissions, err := admin.GetAllActiveUserPermissions(tx)
+		if err != nil {
+			return err
+		}
+		quarantineEmail, err := emailtemplates.MakeGenericEmail(emailtemplates.MakeGenericEmailInput{
+			EmailTitle:       "Sources Quarantined",
+			PreheaderText:    "Some content sources were deactivated because of parse failures",
+			BeforeParagraphs: []string{"The following sources were deactivated because most of their links failed to parse. Check their source filters and reactivate them once they are fixed."},
+			AfterParagraphs:  paragraphs,
+		})
+		if err != nil {
+			return err
+		}
+		for _, p := range permissions {
+			if p.Permission != admin.PermissionEditContentSources {
+				continue
+			}
+			adminUser, err := admin.GetAdminUser(tx, p.AdminUserID)
+			if err != nil {
+				return err
+			}
+			if err := email.SendEmailWithHTMLBody(tx, emailClient, email.SendEmailWithHTMLBodyInput{
+				// Same as the two factor codes, there is no email record for admin emails
+				ID:              email.NewEmailRecordID(),
+				EmailAddress:    adminUser.EmailAddress,
+				Subject:         fmt.Sprintf("%d content sources were quarantined", len(quarantinedSources)),
+				EmailSenderName: ptr.String("Babblegraph Admin"),
+				Body:            quarantineEmail.HTMLBody,
+				TextBody:        quarantineEmail.TextBody,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		c.Errorf("Error notifying admins of quarantined sources: %s", err.Error())
+	}
+}
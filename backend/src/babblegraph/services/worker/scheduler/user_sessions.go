@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"babblegraph/model/usersessions"
+	"babblegraph/util/async"
+	"babblegraph/util/database"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Expired and revoked sessions are kept around for a week
+// in case they are needed to look into suspicious activity
+const inactiveUserSessionRetentionTime = 7 * 24 * time.Hour
+
+func handleCleanUpUserSessions(c async.Context) {
+	if err := database.WithTx(func(tx *sqlx.Tx) error {
+		return usersessions.RemoveSessionsInactiveSince(tx, time.Now().Add(-inactiveUserSessionRetentionTime))
+	}); err != nil {
+		c.Errorf("Error cleaning up user sessions: %s", err.Error())
+	}
+}
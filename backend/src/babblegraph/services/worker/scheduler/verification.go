@@ -9,19 +9,13 @@ import (
 	"babblegraph/model/userverificationattempt"
 	"babblegraph/util/async"
 	"babblegraph/util/database"
-	"babblegraph/util/env"
-	"babblegraph/util/ses"
+	"babblegraph/util/emailsender"
 
 	"github.com/jmoiron/sqlx"
 )
 
 func handlePendingVerifications(c async.Context) {
-	emailClient := ses.NewClient(ses.NewClientInput{
-		AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
-		AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
-		AWSRegion:          "us-east-1",
-		FromAddress:        env.MustEnvironmentVariable("EMAIL_ADDRESS"),
-	})
+	emailClient := emailsender.NewEmailSenderForEnvironment()
 	var userIDs []users.UserID
 	if err := database.WithTx(func(tx *sqlx.Tx) error {
 		var err error
@@ -52,7 +46,7 @@ func handlePendingVerifications(c async.Context) {
 			if err != nil {
 				return err
 			}
-			emailInput := emailtemplates.MakeGenericUserEmailHTMLInput{
+			emailInput := emailtemplates.MakeGenericUserEmailInput{
 				EmailRecordID: emailRecordID,
 				UserAccessor:  emailUserAccessor,
 				EmailTitle:    "Verify your Babblegraph Subscription",
@@ -94,7 +88,7 @@ func handlePendingVerifications(c async.Context) {
 					"Thanks again for signing up for Babblegraph!",
 				}
 			}
-			emailHTML, err := emailtemplates.MakeGenericUserEmailHTML(emailInput)
+			renderedEmail, err := emailtemplates.MakeGenericUserEmail(emailInput)
 			if err != nil {
 				return err
 			}
@@ -102,7 +96,8 @@ func handlePendingVerifications(c async.Context) {
 				ID:           emailRecordID,
 				EmailAddress: user.EmailAddress,
 				Subject:      "Verify your Babblegraph Subscription",
-				Body:         *emailHTML,
+				Body:         renderedEmail.HTMLBody,
+				TextBody:     renderedEmail.TextBody,
 			})
 		}); err != nil {
 			c.Errorf("Error fulfilling verification attempt for user %s: %s. Continuing...", userID, err.Error())
@@ -49,3 +49,15 @@ func WithTx(f func(tx *sqlx.Tx) error) error {
 	}
 	return tx.Commit()
 }
+
+// WithRollbackTx runs f in a transaction that is always rolled back.
+// This is useful for running code that writes records as a side effect
+// when only the result is needed, like previewing a newsletter.
+func WithRollbackTx(f func(tx *sqlx.Tx) error) error {
+	tx := db.MustBegin()
+	err := f(tx)
+	if rbErr := tx.Rollback(); rbErr != nil {
+		log.Println(fmt.Sprintf("Unable to rollback transaction: %s", rbErr.Error()))
+	}
+	return err
+}
@@ -63,6 +63,7 @@ const (
 	mappingTypeBoolean mappingType = "boolean"
 	mappingTypeKeyword mappingType = "keyword"
 	mappingTypeLong    mappingType = "long"
+	mappingTypeFloat   mappingType = "float"
 	mappingTypeDate    mappingType = "date"
 )
 
@@ -16,6 +16,10 @@ func MakeLongMapping(fieldName string, options MappingOptions) Mapping {
 	return makeMapping(fieldName, mappingTypeLong, options)
 }
 
+func MakeFloatMapping(fieldName string, options MappingOptions) Mapping {
+	return makeMapping(fieldName, mappingTypeFloat, options)
+}
+
 func MakeDateMapping(fieldName string, options MappingOptions) Mapping {
 	return makeMapping(fieldName, mappingTypeDate, options)
 }
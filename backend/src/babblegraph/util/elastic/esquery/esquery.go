@@ -39,9 +39,20 @@ func makeQuery(key string, value interface{}) query {
 type searchBody struct {
 	Query query  `json:"query"`
 	Sort  []sort `json:"sort,omitempty"`
+	Size  *int64 `json:"size,omitempty"`
 }
 
 func ExecuteSearch(index elastic.Index, query query, orderedSort *orderedSort, fn func(source []byte, relevance decimal.Number) error) error {
+	return executeSearch(index, query, orderedSort, nil, fn)
+}
+
+// ExecuteSearchWithSize is the same as ExecuteSearch, but returns up to size
+// results instead of the Elasticsearch default of 10.
+func ExecuteSearchWithSize(index elastic.Index, query query, orderedSort *orderedSort, size int64, fn func(source []byte, relevance decimal.Number) error) error {
+	return executeSearch(index, query, orderedSort, &size, fn)
+}
+
+func executeSearch(index elastic.Index, query query, orderedSort *orderedSort, size *int64, fn func(source []byte, relevance decimal.Number) error) error {
 	var sorts []sort
 	if orderedSort != nil {
 		sorts = orderedSort.sorts
@@ -49,6 +60,7 @@ func ExecuteSearch(index elastic.Index, query query, orderedSort *orderedSort, f
 	bodyBytes, err := json.Marshal(searchBody{
 		Query: query,
 		Sort:  sorts,
+		Size:  size,
 	})
 	if err != nil {
 		return err
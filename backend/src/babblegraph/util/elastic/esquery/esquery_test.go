@@ -29,6 +29,18 @@ func TestTerm(t *testing.T) {
 	}
 }
 
+func TestExists(t *testing.T) {
+	testQuery := Exists("text")
+	expected := `{"exists":{"field":"text"}}`
+	out, err := json.Marshal(testQuery)
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+	if string(out) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(out))
+	}
+}
+
 func TestBool(t *testing.T) {
 	builder := NewBoolQueryBuilder()
 	builder.AddMust(Match("text", "abc 123"))
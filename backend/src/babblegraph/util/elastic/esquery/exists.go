@@ -0,0 +1,7 @@
+package esquery
+
+func Exists(fieldName string) query {
+	return makeQuery("exists", map[string]string{
+		"field": fieldName,
+	})
+}
@@ -0,0 +1,133 @@
+package emailsender
+
+import (
+	"babblegraph/util/env"
+	"babblegraph/util/ses"
+	"fmt"
+)
+
+const defaultEmailSenderName = "Babblegraph"
+
+type SendEmailInput struct {
+	Recipient       string
+	HTMLBody        string
+	TextBody        string
+	Subject         string
+	EmailSenderName *string
+
+	// MessageID is used as the local part of the Message-ID header
+	// so that retries of the same email keep the same Message-ID.
+	// A random one is generated if it is not set.
+	MessageID *string
+
+	// ListName and UnsubscribeURL should be set for any email
+	// that is sent regularly, like the newsletter. UnsubscribeURL must
+	// accept a one-click POST request as described in RFC 8058.
+	ListName       *string
+	UnsubscribeURL *string
+}
+
+// EmailSender sends a single email and returns the
+// message ID assigned to it by the transport
+type EmailSender interface {
+	SendEmail(input SendEmailInput) (*string, error)
+}
+
+type Transport string
+
+const (
+	TransportSES  Transport = "ses"
+	TransportSMTP Transport = "smtp"
+	TransportFile Transport = "file"
+)
+
+func (t Transport) Str() string {
+	return string(t)
+}
+
+func getTransportFromString(s string) (*Transport, error) {
+	var out Transport
+	switch s {
+	case TransportSES.Str():
+		out = TransportSES
+	case TransportSMTP.Str():
+		out = TransportSMTP
+	case TransportFile.Str():
+		out = TransportFile
+	default:
+		return nil, fmt.Errorf("Unrecognized email transport: %s", s)
+	}
+	return &out, nil
+}
+
+// GetTransportForEnvironment uses EMAIL_TRANSPORT if it is set. Otherwise,
+// only environments that are meant to send real email use SES and everything
+// else writes emails to the local mailbox.
+func GetTransportForEnvironment() Transport {
+	if transportStr := env.GetEnvironmentVariableOrDefault("EMAIL_TRANSPORT", ""); len(transportStr) > 0 {
+		transport, err := getTransportFromString(transportStr)
+		if err != nil {
+			panic(err.Error())
+		}
+		return *transport
+	}
+	switch env.MustEnvironmentName() {
+	case env.EnvironmentProd,
+		env.EnvironmentStage,
+		env.EnvironmentLocalTestEmail:
+		return TransportSES
+	case env.EnvironmentLocal,
+		env.EnvironmentLocalNoEmail,
+		env.EnvironmentTest:
+		return TransportFile
+	default:
+		panic(fmt.Sprintf("Unsupported environment name: %s", env.MustEnvironmentName()))
+	}
+}
+
+func NewEmailSenderForEnvironment() EmailSender {
+	fromAddress := env.MustEnvironmentVariable("EMAIL_ADDRESS")
+	switch GetTransportForEnvironment() {
+	case TransportSES:
+		return &sesSender{
+			fromAddress: fromAddress,
+			client: ses.NewClient(ses.NewClientInput{
+				AWSAccessKey:       env.MustEnvironmentVariable("AWS_SES_ACCESS_KEY"),
+				AWSSecretAccessKey: env.MustEnvironmentVariable("AWS_SES_SECRET_KEY"),
+				AWSRegion:          "us-east-1",
+				FromAddress:        fromAddress,
+			}),
+		}
+	case TransportSMTP:
+		return NewSMTPSender(NewSMTPSenderInput{
+			Host:        env.MustEnvironmentVariable("SMTP_HOST"),
+			Port:        env.GetEnvironmentVariableOrDefault("SMTP_PORT", "587"),
+			Username:    env.GetEnvironmentVariableOrDefault("SMTP_USERNAME", ""),
+			Password:    env.GetEnvironmentVariableOrDefault("SMTP_PASSWORD", ""),
+			FromAddress: fromAddress,
+		})
+	case TransportFile:
+		return NewFileSink(getMailboxDirectoryForEnvironment(), fromAddress)
+	default:
+		panic("unreachable")
+	}
+}
+
+type sesSender struct {
+	fromAddress string
+	client      *ses.Client
+}
+
+// SendEmail returns the message ID assigned by SES, which is
+// what bounce and complaint notifications refer to.
+func (s *sesSender) SendEmail(input SendEmailInput) (*string, error) {
+	m := makeMessage(s.fromAddress, input)
+	rawMessage, err := m.toBytes()
+	if err != nil {
+		return nil, err
+	}
+	return s.client.SendRawEmail(ses.SendRawEmailInput{
+		Recipient:  input.Recipient,
+		RawMessage: rawMessage,
+	})
+}
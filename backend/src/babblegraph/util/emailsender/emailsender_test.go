@@ -0,0 +1,148 @@
+package emailsender
+
+import (
+	"babblegraph/util/ptr"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFileSink(t *testing.T) {
+	directory, err := ioutil.TempDir("", "mailbox")
+	if err != nil {
+		t.Fatalf("Error creating mailbox directory: %s", err.Error())
+	}
+	defer os.RemoveAll(directory)
+	sender := NewFileSink(directory, "hello@babblegraph.com")
+	htmlBody := `<html><body><p>¡Hola! Aquí está tu boletín de hoy con una línea bastante larga para forzar el ajuste de quoted-printable.</p></body></html>`
+	messageID, err := sender.SendEmail(SendEmailInput{
+		Recipient:       "test@example.com",
+		HTMLBody:        htmlBody,
+		TextBody:        "¡Hola! Aquí está tu boletín de hoy.",
+		Subject:         "Tu boletín de español",
+		EmailSenderName: ptr.String("Babblegraph Test"),
+	})
+	if err != nil {
+		t.Fatalf("Error sending email: %s", err.Error())
+	}
+	if messageID == nil || !strings.HasSuffix(*messageID, "@babblegraph.com") {
+		t.Errorf("Expected message ID for domain babblegraph.com, but got %v", messageID)
+	}
+	messages, err := ListMailbox(directory)
+	if err != nil {
+		t.Fatalf("Error listing mailbox: %s", err.Error())
+	}
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, but got %d", len(messages))
+	}
+	m := messages[0]
+	if m.Subject != "Tu boletín de español" {
+		t.Errorf("Expected subject to be decoded, but got %s", m.Subject)
+	}
+	if m.To != "test@example.com" {
+		t.Errorf("Expected recipient test@example.com, but got %s", m.To)
+	}
+	if !strings.Contains(m.From, "Babblegraph Test") {
+		t.Errorf("Expected sender name in from header, but got %s", m.From)
+	}
+	if m.HTMLBody != htmlBody {
+		t.Errorf("Expected HTML body %s, but got %s", htmlBody, m.HTMLBody)
+	}
+	if _, err := ReadMailboxMessage(directory, "../"+m.FileName); err == nil {
+		t.Errorf("Expected error reading file outside of mailbox")
+	}
+}
+
+func TestMessageHasPlainTextAlternativeAndListHeaders(t *testing.T) {
+	m := makeMessage("hello@babblegraph.com", SendEmailInput{
+		Recipient:      "test@example.com",
+		HTMLBody:       `<html><body><p>Hola &amp; bienvenido</p><p>Lee <a href="https://www.babblegraph.com/article/1">este artículo</a></p></body></html>`,
+		TextBody:       "Hola & bienvenido\n\nLee este artículo: https://www.babblegraph.com/article/1\n",
+		Subject:        "Tu boletín de español",
+		MessageID:      ptr.String("email-record-1"),
+		ListName:       ptr.String("newsletter"),
+		UnsubscribeURL: ptr.String("https://www.babblegraph.com/api/user/one_click_unsubscribe_1?token=abc"),
+	})
+	rawMessage, err := m.toBytes()
+	if err != nil {
+		t.Fatalf("Error rendering message: %s", err.Error())
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(rawMessage))
+	if err != nil {
+		t.Fatalf("Error parsing message: %s", err.Error())
+	}
+	expectedHeaders := map[string]string{
+		"Message-ID":            "<email-record-1@babblegraph.com>",
+		"List-ID":               "Babblegraph <newsletter.babblegraph.com>",
+		"List-Unsubscribe":      "<https://www.babblegraph.com/api/user/one_click_unsubscribe_1?token=abc>",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	for key, expected := range expectedHeaders {
+		if value := parsed.Header.Get(key); value != expected {
+			t.Errorf("Expected header %s to be %s, but got %s", key, expected, value)
+		}
+	}
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Error parsing content type: %s", err.Error())
+	}
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative, but got %s", mediaType)
+	}
+	var contentTypes, bodies []string
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Error reading part: %s", err.Error())
+		}
+		// NextPart decodes quoted-printable and removes the header
+		body, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatalf("Error reading part: %s", err.Error())
+		}
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+	if len(contentTypes) != 2 || !strings.HasPrefix(contentTypes[0], "text/plain") || !strings.HasPrefix(contentTypes[1], "text/html") {
+		t.Fatalf("Expected text/plain part followed by text/html part, but got %v", contentTypes)
+	}
+	// The quoted-printable writer uses CRLF line endings
+	expectedText := "Hola & bienvenido\r\n\r\nLee este artículo: https://www.babblegraph.com/article/1\r\n"
+	if bodies[0] != expectedText {
+		t.Errorf("Expected plain text body %q, but got %q", expectedText, bodies[0])
+	}
+	if bodies[1] != m.HTMLBody {
+		t.Errorf("Expected HTML body %s, but got %s", m.HTMLBody, bodies[1])
+	}
+}
+
+func TestMessageWithoutListHeaders(t *testing.T) {
+	m := makeMessage("hello@babblegraph.com", SendEmailInput{
+		Recipient: "test@example.com",
+		HTMLBody:  "<p>Verifica tu correo</p>",
+		TextBody:  "Verifica tu correo",
+		Subject:   "Verificación",
+	})
+	rawMessage, err := m.toBytes()
+	if err != nil {
+		t.Fatalf("Error rendering message: %s", err.Error())
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(rawMessage))
+	if err != nil {
+		t.Fatalf("Error parsing message: %s", err.Error())
+	}
+	for _, key := range []string{"List-ID", "List-Unsubscribe", "List-Unsubscribe-Post"} {
+		if value := parsed.Header.Get(key); len(value) > 0 {
+			t.Errorf("Expected no %s header, but got %s", key, value)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package emailsender
+
+import (
+	"babblegraph/util/env"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultMailboxDirectory = "/tmp/babblegraph-mailbox"
+	mailboxFileExtension    = ".eml"
+)
+
+func getMailboxDirectoryForEnvironment() string {
+	return env.GetEnvironmentVariableOrDefault("EMAIL_MAILBOX_DIRECTORY", defaultMailboxDirectory)
+}
+
+type fileSink struct {
+	directory   string
+	fromAddress string
+}
+
+// NewFileSink creates a sender that writes every email to the
+// directory as an .eml file instead of sending it
+func NewFileSink(directory, fromAddress string) EmailSender {
+	return &fileSink{
+		directory:   directory,
+		fromAddress: fromAddress,
+	}
+}
+
+func (f *fileSink) SendEmail(input SendEmailInput) (*string, error) {
+	m := makeMessage(f.fromAddress, input)
+	messageBytes, err := m.toBytes()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(f.directory, 0755); err != nil {
+		return nil, err
+	}
+	// Prefixing with the timestamp keeps the files in the order they were sent
+	fileName := fmt.Sprintf("%s-%s%s", m.Date.UTC().Format("20060102T150405.000000000"), sanitizeFileName(input.Recipient), mailboxFileExtension)
+	if err := ioutil.WriteFile(filepath.Join(f.directory, fileName), messageBytes, 0644); err != nil {
+		return nil, err
+	}
+	log.Println(fmt.Sprintf("Wrote email with id %s to %s in mailbox %s", m.ID, input.Recipient, f.directory))
+	return &m.ID, nil
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			r >= '0' && r <= '9',
+			r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
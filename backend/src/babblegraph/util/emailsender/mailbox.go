@@ -0,0 +1,180 @@
+package emailsender
+
+import (
+	"babblegraph/util/env"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const defaultMailboxViewerAddress = "127.0.0.1:8025"
+
+type MailboxMessage struct {
+	FileName string
+	From     string
+	To       string
+	Subject  string
+	Date     string
+	HTMLBody string
+}
+
+// ListMailbox returns the messages in the mailbox, newest first
+func ListMailbox(directory string) ([]MailboxMessage, error) {
+	files, err := ioutil.ReadDir(directory)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	var fileNames []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), mailboxFileExtension) {
+			fileNames = append(fileNames, f.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(fileNames)))
+	var out []MailboxMessage
+	for _, fileName := range fileNames {
+		m, err := ReadMailboxMessage(directory, fileName)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	return out, nil
+}
+
+func ReadMailboxMessage(directory, fileName string) (*MailboxMessage, error) {
+	// Only plain file names are allowed so that the
+	// viewer can't be used to read outside of the mailbox
+	if fileName != filepath.Base(fileName) || !strings.HasSuffix(fileName, mailboxFileExtension) {
+		return nil, fmt.Errorf("Invalid mailbox file name %s", fileName)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(directory, fileName))
+	if err != nil {
+		return nil, err
+	}
+	m, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		return nil, err
+	}
+	htmlBody, err := getHTMLBody(m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &MailboxMessage{
+		FileName: fileName,
+		From:     m.Header.Get("From"),
+		To:       m.Header.Get("To"),
+		Subject:  subject,
+		Date:     m.Header.Get("Date"),
+		HTMLBody: htmlBody,
+	}, nil
+}
+
+func getHTMLBody(contentType, transferEncoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			switch {
+			case err == io.EOF:
+				return "", nil
+			case err != nil:
+				return "", err
+			}
+			htmlBody, err := getHTMLBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			if len(htmlBody) > 0 {
+				return htmlBody, nil
+			}
+		}
+	}
+	if mediaType != "text/html" {
+		return "", nil
+	}
+	switch strings.ToLower(transferEncoding) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	decoded, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+var mailboxIndexTemplate = template.Must(template.New("mailbox").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Mailbox</title></head>
+<body>
+<h1>Mailbox</h1>
+<table>
+<tr><th>Date</th><th>To</th><th>Subject</th></tr>
+{{range .}}<tr><td>{{.Date}}</td><td>{{.To}}</td><td><a href="/message?name={{.FileName}}">{{.Subject}}</a></td></tr>
+{{end}}</table>
+</body>
+</html>`))
+
+// StartMailboxViewerForEnvironment serves a page listing the emails written by
+// the file sink. It does nothing unless the environment uses the file transport.
+func StartMailboxViewerForEnvironment() {
+	if GetTransportForEnvironment() != TransportFile {
+		return
+	}
+	directory := getMailboxDirectoryForEnvironment()
+	address := env.GetEnvironmentVariableOrDefault("EMAIL_MAILBOX_VIEWER_ADDRESS", defaultMailboxViewerAddress)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		messages, err := ListMailbox(directory)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := mailboxIndexTemplate.Execute(w, messages); err != nil {
+			log.Println(fmt.Sprintf("Error rendering mailbox: %s", err.Error()))
+		}
+	})
+	mux.HandleFunc("/message", func(w http.ResponseWriter, r *http.Request) {
+		m, err := ReadMailboxMessage(directory, r.URL.Query().Get("name"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write([]byte(m.HTMLBody))
+	})
+	go func() {
+		log.Println(fmt.Sprintf("Serving mailbox %s on %s", directory, address))
+		if err := http.ListenAndServe(address, mux); err != nil {
+			log.Println(fmt.Sprintf("Mailbox viewer stopped: %s", err.Error()))
+		}
+	}()
+}
@@ -0,0 +1,125 @@
+package emailsender
+
+import (
+	"babblegraph/util/deref"
+	"babblegraph/util/ptr"
+	"babblegraph/util/random"
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+type message struct {
+	ID             string
+	FromAddress    string
+	SenderName     string
+	Recipient      string
+	Subject        string
+	HTMLBody       string
+	TextBody       string
+	ListID         *string
+	UnsubscribeURL *string
+	Date           time.Time
+}
+
+func makeMessage(fromAddress string, input SendEmailInput) message {
+	domain := "localhost"
+	if parts := strings.Split(fromAddress, "@"); len(parts) == 2 {
+		domain = parts[1]
+	}
+	localPart := fmt.Sprintf("%d.%s", time.Now().UnixNano(), random.MustMakeRandomString(16))
+	if input.MessageID != nil {
+		localPart = *input.MessageID
+	}
+	var listID *string
+	if input.ListName != nil {
+		listID = ptr.String(fmt.Sprintf("%s.%s", *input.ListName, domain))
+	}
+	return message{
+		ID:             fmt.Sprintf("%s@%s", localPart, domain),
+		FromAddress:    fromAddress,
+		SenderName:     deref.String(input.EmailSenderName, defaultEmailSenderName),
+		Recipient:      input.Recipient,
+		Subject:        input.Subject,
+		HTMLBody:       input.HTMLBody,
+		TextBody:       input.TextBody,
+		ListID:         listID,
+		UnsubscribeURL: input.UnsubscribeURL,
+		Date:           time.Now(),
+	}
+}
+
+// toBytes renders the message in RFC 5322 format as a multipart/alternative
+// message with a plain text part followed by the HTML part
+func (m message) toBytes() ([]byte, error) {
+	from := mail.Address{
+		Name:    m.SenderName,
+		Address: m.FromAddress,
+	}
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", m.TextBody},
+		{"text/html; charset=UTF-8", m.HTMLBody},
+	} {
+		if err := writeQuotedPrintablePart(w, part.contentType, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	headers := [][]string{
+		{"From", from.String()},
+		{"To", m.Recipient},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", m.Date.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s>", m.ID)},
+	}
+	if m.ListID != nil {
+		headers = append(headers, []string{"List-ID", fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", m.SenderName), *m.ListID)})
+	}
+	if m.UnsubscribeURL != nil {
+		// RFC 8058: mail clients send a POST request with the body
+		// List-Unsubscribe=One-Click to the URL in List-Unsubscribe
+		headers = append(headers,
+			[]string{"List-Unsubscribe", fmt.Sprintf("<%s>", *m.UnsubscribeURL)},
+			[]string{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"},
+		)
+	}
+	headers = append(headers,
+		[]string{"MIME-Version", "1.0"},
+		[]string{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%s", w.Boundary())},
+	)
+	var buf bytes.Buffer
+	for _, h := range headers {
+		buf.WriteString(fmt.Sprintf("%s: %s\r\n", h[0], h[1]))
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(w *multipart.Writer, contentType, content string) error {
+	partWriter, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(partWriter)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
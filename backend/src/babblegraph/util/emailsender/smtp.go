@@ -0,0 +1,48 @@
+package emailsender
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+)
+
+type smtpSender struct {
+	address     string
+	auth        smtp.Auth
+	fromAddress string
+}
+
+type NewSMTPSenderInput struct {
+	Host        string
+	Port        string
+	Username    string
+	Password    string
+	FromAddress string
+}
+
+// NewSMTPSender creates a sender for a generic SMTP server. Authentication
+// is skipped if there is no username, which is useful for local mail catchers.
+func NewSMTPSender(input NewSMTPSenderInput) EmailSender {
+	var auth smtp.Auth
+	if len(input.Username) > 0 {
+		auth = smtp.PlainAuth("", input.Username, input.Password, input.Host)
+	}
+	return &smtpSender{
+		address:     fmt.Sprintf("%s:%s", input.Host, input.Port),
+		auth:        auth,
+		fromAddress: input.FromAddress,
+	}
+}
+
+func (s *smtpSender) SendEmail(input SendEmailInput) (*string, error) {
+	m := makeMessage(s.fromAddress, input)
+	messageBytes, err := m.toBytes()
+	if err != nil {
+		return nil, err
+	}
+	if err := smtp.SendMail(s.address, s.auth, s.fromAddress, []string{input.Recipient}, messageBytes); err != nil {
+		return nil, err
+	}
+	log.Println(fmt.Sprintf("Sent email with id %s to %s over SMTP", m.ID, input.Recipient))
+	return &m.ID, nil
+}
@@ -0,0 +1,19 @@
+package encrypt
+
+import (
+	"babblegraph/util/env"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GetKeyedHash is for values that need to be matched later without being
+// stored. Unlike a plain hash, guesses can't be checked against it without
+// the key. The purpose keeps hashes of the same value for different uses apart.
+func GetKeyedHash(purpose, value string) string {
+	mac := hmac.New(sha256.New, []byte(env.MustEnvironmentVariable("AES_KEY")))
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
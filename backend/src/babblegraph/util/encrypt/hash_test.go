@@ -0,0 +1,21 @@
+package encrypt
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGetKeyedHash(t *testing.T) {
+	withTestAESKey(t, testAESKey)
+	hash := GetKeyedHash("test-purpose", "someone@babblegraph.com")
+	if result := GetKeyedHash("test-purpose", "someone@babblegraph.com"); result != hash {
+		t.Errorf("Expected the same value to have the same hash, but got %s and %s", hash, result)
+	}
+	if GetKeyedHash("other-purpose", "someone@babblegraph.com") == hash {
+		t.Errorf("Expected hashes for different purposes to be different")
+	}
+	os.Setenv("AES_KEY", "Zk2hXtT8pQ3vN7wLrC5yB1mA9sD4fG6j")
+	if GetKeyedHash("test-purpose", "someone@babblegraph.com") == hash {
+		t.Errorf("Expected hashes for different keys to be different")
+	}
+}
@@ -0,0 +1,94 @@
+package encrypt
+
+import (
+	"babblegraph/util/ptr"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// Encrypted secrets look like s1.<key id>.<base64(nonce + ciphertext)>
+	// and use the same keyring as tokens, so rotating keys works the same way.
+	secretVersion1Prefix = "s1"
+)
+
+// EncryptSecret is for values that need to be stored, but can't be hashed
+// because they have to be read back, like TOTP secrets. The purpose is bound
+// to the ciphertext, so a secret can only be decrypted for the same purpose.
+func EncryptSecret(purpose, plaintext string) (*string, error) {
+	keyring, err := getTokenKeyring()
+	if err != nil {
+		return nil, err
+	}
+	activeKey := keyring[0]
+	aead, err := getAEADForKey(activeKey.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	header := makeSecretHeader(activeKey.id)
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), makeSecretAdditionalData(header, purpose))
+	return ptr.String(strings.Join([]string{header, base64.RawURLEncoding.EncodeToString(sealed)}, tokenPartSeparator)), nil
+}
+
+func DecryptSecret(purpose, ciphertext string) (*string, error) {
+	if !IsEncryptedSecret(ciphertext) {
+		return nil, errors.New("value is not an encrypted secret")
+	}
+	parts := strings.Split(ciphertext, tokenPartSeparator)
+	if len(parts) != 3 {
+		return nil, errors.New("malformed secret")
+	}
+	keyring, err := getTokenKeyring()
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	for _, k := range keyring {
+		if k.id == parts[1] {
+			key = k.key
+			break
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("unknown secret key id %s", parts[1])
+	}
+	aead, err := getAEADForKey(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealedPlaintext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	decoded, err := aead.Open(nil, nonce, sealedPlaintext, makeSecretAdditionalData(makeSecretHeader(parts[1]), purpose))
+	if err != nil {
+		return nil, errors.New("secret failed authentication")
+	}
+	return ptr.String(string(decoded)), nil
+}
+
+// IsEncryptedSecret is for values that were stored
+// before they were encrypted, so they can be migrated
+func IsEncryptedSecret(value string) bool {
+	return strings.HasPrefix(value, secretVersion1Prefix+tokenPartSeparator)
+}
+
+func makeSecretHeader(keyID string) string {
+	return strings.Join([]string{secretVersion1Prefix, keyID}, tokenPartSeparator)
+}
+
+func makeSecretAdditionalData(header, purpose string) []byte {
+	return []byte(strings.Join([]string{header, purpose}, "\x00"))
+}
@@ -0,0 +1,42 @@
+package encrypt
+
+import (
+	"testing"
+)
+
+const (
+	testSecretPurpose = "test-purpose"
+	testSecretValue   = "JBSWY3DPEHPK3PXP"
+)
+
+func TestSecretRoundTrip(t *testing.T) {
+	withTestKeyring(t, "1:ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE")
+	encrypted, err := EncryptSecret(testSecretPurpose, testSecretValue)
+	if err != nil {
+		t.Fatalf("Error encrypting secret: %s", err.Error())
+	}
+	if !IsEncryptedSecret(*encrypted) {
+		t.Errorf("Expected %s to be an encrypted secret", *encrypted)
+	}
+	if IsEncryptedSecret(testSecretValue) {
+		t.Errorf("Expected %s not to be an encrypted secret", testSecretValue)
+	}
+	decrypted, err := DecryptSecret(testSecretPurpose, *encrypted)
+	switch {
+	case err != nil:
+		t.Errorf("Error decrypting secret: %s", err.Error())
+	case *decrypted != testSecretValue:
+		t.Errorf("Expected %s, but got %s", testSecretValue, *decrypted)
+	}
+	if _, err := DecryptSecret("other-purpose", *encrypted); err == nil {
+		t.Errorf("Expected secret to be rejected for a different purpose")
+	}
+	withTestKeyring(t, "2:Zk2hXtT8pQ3vN7wLrC5yB1mA9sD4fG6j,1:ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE")
+	if _, err := DecryptSecret(testSecretPurpose, *encrypted); err != nil {
+		t.Errorf("Expected secret for rotated key to decrypt, but got error: %s", err.Error())
+	}
+	withTestKeyring(t, "2:Zk2hXtT8pQ3vN7wLrC5yB1mA9sD4fG6j")
+	if _, err := DecryptSecret(testSecretPurpose, *encrypted); err == nil {
+		t.Errorf("Expected secret for retired key to be rejected")
+	}
+}
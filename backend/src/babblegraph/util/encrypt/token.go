@@ -11,7 +11,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"strings"
+	"time"
 )
 
 // A token pair is used so different
@@ -26,40 +27,198 @@ type TokenPair struct {
 	Value interface{} `json:"value"`
 }
 
+const (
+	// Version 2 tokens look like v2.<key id>.<base64(nonce + ciphertext)>
+	// Legacy tokens are plain URL base64, which never contains a period,
+	// so the prefix is enough to tell the two apart.
+	tokenVersion2Prefix = "v2"
+	tokenPartSeparator  = "."
+
+	DefaultTokenLifetime = 180 * 24 * time.Hour
+)
+
+var (
+	ErrTokenExpired       = errors.New("token is expired")
+	ErrUnexpectedTokenKey = errors.New("token was issued for a different key")
+
+	// Tokens in the original AES-CFB format are unauthenticated
+	// and never expire. They are accepted until this date so that
+	// links in emails sent before version 2 keep working for a while.
+	legacyTokensAcceptedUntil = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+type tokenClaims struct {
+	Key       string      `json:"k"`
+	Value     interface{} `json:"v"`
+	IssuedAt  int64       `json:"iat"`
+	ExpiresAt int64       `json:"exp"`
+}
+
 func GetToken(t TokenPair) (*string, error) {
-	encryptionKey := env.MustEnvironmentVariable("AES_KEY")
-	block, err := aes.NewCipher([]byte(encryptionKey))
+	return GetTokenWithLifetime(t, DefaultTokenLifetime)
+}
+
+func GetTokenWithLifetime(t TokenPair, lifetime time.Duration) (*string, error) {
+	keyring, err := getTokenKeyring()
+	if err != nil {
+		return nil, err
+	}
+	activeKey := keyring[0]
+	aead, err := getAEADForKey(activeKey.key)
 	if err != nil {
 		return nil, err
 	}
-	jsonToken, err := json.Marshal(t)
+	now := time.Now()
+	claims, err := json.Marshal(tokenClaims{
+		Key:       t.Key,
+		Value:     t.Value,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(lifetime).Unix(),
+	})
 	if err != nil {
 		return nil, err
 	}
-	ciphertext := make([]byte, aes.BlockSize+len(jsonToken))
-	iv := ciphertext[:aes.BlockSize]
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
-	cfb := cipher.NewCFBEncrypter(block, iv)
-	cfb.XORKeyStream(ciphertext[aes.BlockSize:], jsonToken)
-	return ptr.String(base64.URLEncoding.EncodeToString(ciphertext)), nil
+	header := makeTokenHeader(activeKey.id)
+	sealed := aead.Seal(nonce, nonce, claims, []byte(header))
+	return ptr.String(strings.Join([]string{header, base64.RawURLEncoding.EncodeToString(sealed)}, tokenPartSeparator)), nil
 }
 
-func WithDecodedToken(token string, fn func(TokenPair) error) error {
-	log.Println(fmt.Sprintf("Decoding token %s", token))
-	encryptionKey := env.MustEnvironmentVariable("AES_KEY")
-	block, err := aes.NewCipher([]byte(encryptionKey))
+// WithDecodedToken only calls fn for tokens issued for the expected key,
+// so that a token for one route can't be used on another
+func WithDecodedToken(token string, expectedKey string, fn func(TokenPair) error) error {
+	return WithDecodedTokenForKeys(token, []string{expectedKey}, fn)
+}
+
+// WithDecodedTokenForKeys is for routes that accept tokens for more than one key,
+// like article links, which were issued under a different key before
+func WithDecodedTokenForKeys(token string, expectedKeys []string, fn func(TokenPair) error) error {
+	return withDecodedToken(token, func(t TokenPair) error {
+		for _, expectedKey := range expectedKeys {
+			if t.Key == expectedKey {
+				return fn(t)
+			}
+		}
+		return ErrUnexpectedTokenKey
+	})
+}
+
+func withDecodedToken(token string, fn func(TokenPair) error) error {
+	if !strings.HasPrefix(token, tokenVersion2Prefix+tokenPartSeparator) {
+		if time.Now().After(legacyTokensAcceptedUntil) {
+			return errors.New("legacy tokens are no longer accepted")
+		}
+		return withDecodedLegacyToken(token, fn)
+	}
+	parts := strings.Split(token, tokenPartSeparator)
+	if len(parts) != 3 {
+		return errors.New("malformed token")
+	}
+	keyring, err := getTokenKeyring()
+	if err != nil {
+		return err
+	}
+	var key []byte
+	for _, k := range keyring {
+		if k.id == parts[1] {
+			key = k.key
+			break
+		}
+	}
+	if key == nil {
+		return fmt.Errorf("unknown token key id %s", parts[1])
+	}
+	aead, err := getAEADForKey(key)
+	if err != nil {
+		return err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
 	if err != nil {
 		return err
 	}
-	if len(token) < aes.BlockSize {
+	if len(sealed) < aead.NonceSize() {
 		return errors.New("ciphertext too short")
 	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	decoded, err := aead.Open(nil, nonce, ciphertext, []byte(makeTokenHeader(parts[1])))
+	if err != nil {
+		return errors.New("token failed authentication")
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(decoded, &claims); err != nil {
+		return err
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return ErrTokenExpired
+	}
+	return fn(TokenPair{
+		Key:   claims.Key,
+		Value: claims.Value,
+	})
+}
+
+func makeTokenHeader(keyID string) string {
+	return strings.Join([]string{tokenVersion2Prefix, keyID}, tokenPartSeparator)
+}
+
+func getAEADForKey(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type tokenKey struct {
+	id  string
+	key []byte
+}
+
+// TOKEN_KEYRING holds comma separated <key id>:<key> pairs.
+// The first key is used to issue new tokens and the rest
+// are only used to decode tokens, which allows keys to be
+// rotated without invalidating links that are already sent.
+// Without a keyring, AES_KEY is used under the key ID "0".
+func getTokenKeyring() ([]tokenKey, error) {
+	keyringStr := env.GetEnvironmentVariableOrDefault("TOKEN_KEYRING", "")
+	if len(keyringStr) == 0 {
+		return []tokenKey{
+			{
+				id:  "0",
+				key: []byte(env.MustEnvironmentVariable("AES_KEY")),
+			},
+		}, nil
+	}
+	var out []tokenKey
+	for _, entry := range strings.Split(keyringStr, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || strings.Contains(parts[0], tokenPartSeparator) {
+			return nil, fmt.Errorf("malformed token keyring entry")
+		}
+		out = append(out, tokenKey{
+			id:  parts[0],
+			key: []byte(parts[1]),
+		})
+	}
+	return out, nil
+}
+
+func withDecodedLegacyToken(token string, fn func(TokenPair) error) error {
+	encryptionKey := env.MustEnvironmentVariable("AES_KEY")
+	block, err := aes.NewCipher([]byte(encryptionKey))
+	if err != nil {
+		return err
+	}
 	decodedToken, err := base64.URLEncoding.DecodeString(string(token))
 	if err != nil {
 		return err
 	}
+	if len(decodedToken) < aes.BlockSize {
+		return errors.New("ciphertext too short")
+	}
 	iv := decodedToken[:aes.BlockSize]
 	decodedToken = decodedToken[aes.BlockSize:]
 	cfb := cipher.NewCFBDecrypter(block, iv)
@@ -0,0 +1,197 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAESKey     = "ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE"
+	testTokenKey   = "test-key"
+	testTokenValue = "test-value"
+)
+
+// Tokens without a keyring use AES_KEY, so tests
+// set it rather than relying on the environment
+func withTestAESKey(t *testing.T, aesKey string) {
+	original, hadOriginal := os.LookupEnv("AES_KEY")
+	if err := os.Setenv("AES_KEY", aesKey); err != nil {
+		t.Fatalf("Error setting AES key: %s", err.Error())
+	}
+	t.Cleanup(func() {
+		if hadOriginal {
+			os.Setenv("AES_KEY", original)
+		} else {
+			os.Unsetenv("AES_KEY")
+		}
+	})
+}
+
+func withTestKeyring(t *testing.T, keyring string) {
+	original, hadOriginal := os.LookupEnv("TOKEN_KEYRING")
+	if err := os.Setenv("TOKEN_KEYRING", keyring); err != nil {
+		t.Fatalf("Error setting keyring: %s", err.Error())
+	}
+	t.Cleanup(func() {
+		if hadOriginal {
+			os.Setenv("TOKEN_KEYRING", original)
+		} else {
+			os.Unsetenv("TOKEN_KEYRING")
+		}
+	})
+}
+
+func decodeTestToken(token string) (*TokenPair, error) {
+	var out TokenPair
+	if err := WithDecodedToken(token, testTokenKey, func(t TokenPair) error {
+		out = t
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func makeLegacyTestToken(t TokenPair) (*string, error) {
+	block, err := aes.NewCipher([]byte(os.Getenv("AES_KEY")))
+	if err != nil {
+		return nil, err
+	}
+	jsonToken, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, aes.BlockSize+len(jsonToken))
+	iv := ciphertext[:aes.BlockSize]
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(ciphertext[aes.BlockSize:], jsonToken)
+	out := base64.URLEncoding.EncodeToString(ciphertext)
+	return &out, nil
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	withTestAESKey(t, testAESKey)
+	withTestKeyring(t, "")
+	token, err := GetToken(TokenPair{Key: testTokenKey, Value: testTokenValue})
+	if err != nil {
+		t.Fatalf("Error getting token: %s", err.Error())
+	}
+	if !strings.HasPrefix(*token, "v2.") {
+		t.Errorf("Expected version 2 token, but got %s", *token)
+	}
+	decoded, err := decodeTestToken(*token)
+	if err != nil {
+		t.Fatalf("Error decoding token: %s", err.Error())
+	}
+	if decoded.Key != testTokenKey || decoded.Value != testTokenValue {
+		t.Errorf("Expected %s:%s, but got %+v", testTokenKey, testTokenValue, decoded)
+	}
+}
+
+func TestTamperedTokenIsRejected(t *testing.T) {
+	withTestAESKey(t, testAESKey)
+	withTestKeyring(t, "")
+	token, err := GetToken(TokenPair{Key: testTokenKey, Value: testTokenValue})
+	if err != nil {
+		t.Fatalf("Error getting token: %s", err.Error())
+	}
+	parts := strings.Split(*token, ".")
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("Error decoding token: %s", err.Error())
+	}
+	sealed[len(sealed)-1] ^= 0x01
+	tampered := strings.Join([]string{parts[0], parts[1], base64.RawURLEncoding.EncodeToString(sealed)}, ".")
+	if _, err := decodeTestToken(tampered); err == nil {
+		t.Errorf("Expected tampered token to be rejected")
+	}
+}
+
+func TestExpiredTokenIsRejected(t *testing.T) {
+	withTestAESKey(t, testAESKey)
+	withTestKeyring(t, "")
+	token, err := GetTokenWithLifetime(TokenPair{Key: testTokenKey, Value: testTokenValue}, -1*time.Hour)
+	if err != nil {
+		t.Fatalf("Error getting token: %s", err.Error())
+	}
+	if _, err := decodeTestToken(*token); err != ErrTokenExpired {
+		t.Errorf("Expected token expired error, but got %v", err)
+	}
+}
+
+func TestUnexpectedTokenKeyIsRejected(t *testing.T) {
+	withTestKeyring(t, "1:ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE")
+	token, err := GetToken(TokenPair{Key: testTokenKey, Value: testTokenValue})
+	if err != nil {
+		t.Fatalf("Error getting token: %s", err.Error())
+	}
+	if err := WithDecodedToken(*token, "other-key", func(t TokenPair) error {
+		return nil
+	}); err != ErrUnexpectedTokenKey {
+		t.Errorf("Expected unexpected key error, but got %v", err)
+	}
+	if err := WithDecodedTokenForKeys(*token, []string{"other-key", testTokenKey}, func(t TokenPair) error {
+		return nil
+	}); err != nil {
+		t.Errorf("Expected token to decode for one of several keys, but got error: %s", err.Error())
+	}
+}
+
+func TestTokenKeyRotation(t *testing.T) {
+	withTestKeyring(t, "1:ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE")
+	oldToken, err := GetToken(TokenPair{Key: testTokenKey, Value: testTokenValue})
+	if err != nil {
+		t.Fatalf("Error getting token: %s", err.Error())
+	}
+	withTestKeyring(t, "2:Zk2hXtT8pQ3vN7wLrC5yB1mA9sD4fG6j,1:ryDMLNhwEqPBHMgu6YOsCZ0ihx4WgvqE")
+	newToken, err := GetToken(TokenPair{Key: testTokenKey, Value: testTokenValue})
+	if err != nil {
+		t.Fatalf("Error getting token: %s", err.Error())
+	}
+	if !strings.HasPrefix(*newToken, "v2.2.") {
+		t.Errorf("Expected new token to use key 2, but got %s", *newToken)
+	}
+	for _, token := range []string{*oldToken, *newToken} {
+		if _, err := decodeTestToken(token); err != nil {
+			t.Errorf("Expected token %s to decode, but got error: %s", token, err.Error())
+		}
+	}
+	withTestKeyring(t, "2:Zk2hXtT8pQ3vN7wLrC5yB1mA9sD4fG6j")
+	if _, err := decodeTestToken(*oldToken); err == nil {
+		t.Errorf("Expected token for retired key to be rejected")
+	}
+}
+
+func TestLegacyTokenGracePeriod(t *testing.T) {
+	withTestAESKey(t, testAESKey)
+	originalCutoff := legacyTokensAcceptedUntil
+	defer func() {
+		legacyTokensAcceptedUntil = originalCutoff
+	}()
+	token, err := makeLegacyTestToken(TokenPair{Key: testTokenKey, Value: testTokenValue})
+	if err != nil {
+		t.Fatalf("Error getting legacy token: %s", err.Error())
+	}
+	legacyTokensAcceptedUntil = time.Now().Add(time.Hour)
+	decoded, err := decodeTestToken(*token)
+	switch {
+	case err != nil:
+		t.Errorf("Expected legacy token to be accepted, but got error: %s", err.Error())
+	case decoded.Key != testTokenKey || decoded.Value != testTokenValue:
+		t.Errorf("Expected %s:%s, but got %+v", testTokenKey, testTokenValue, decoded)
+	}
+	legacyTokensAcceptedUntil = time.Now().Add(-time.Hour)
+	if _, err := decodeTestToken(*token); err == nil {
+		t.Errorf("Expected legacy token to be rejected after grace period")
+	}
+}
@@ -20,7 +20,7 @@ type BouncedRecipient struct {
 type BounceType string
 
 const (
-	BounceTypeUndetermined BounceType = "Undeteremined"
+	BounceTypeUndetermined BounceType = "Undetermined"
 	BounceTypePermanent    BounceType = "Permanent"
 	BounceTypeTransient    BounceType = "Transient"
 )
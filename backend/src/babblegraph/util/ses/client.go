@@ -1,9 +1,9 @@
 package ses
 
 import (
-	"babblegraph/util/deref"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -11,13 +11,15 @@ import (
 	"github.com/aws/aws-sdk-go/service/ses"
 )
 
-const defaultEmailSenderName = "Babblegraph"
-
 type Client struct {
 	awsAccessKey       string
 	awsSecretAccessKey string
 	awsRegion          string
 	fromAddress        string
+
+	// The session is created on the first send and reused after that
+	mu  sync.Mutex
+	svc *ses.SES
 }
 
 type NewClientInput struct {
@@ -36,14 +38,20 @@ func NewClient(input NewClientInput) *Client {
 	}
 }
 
-type SendEmailInput struct {
-	Recipient       string
-	HTMLBody        string
-	Subject         string
-	EmailSenderName *string
+type SendRawEmailInput struct {
+	Recipient string
+
+	// RawMessage is a complete RFC 5322 message,
+	// including all headers and MIME parts
+	RawMessage []byte
 }
 
-func (cl *Client) SendEmail(input SendEmailInput) (*string, error) {
+func (cl *Client) getService() (*ses.SES, error) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.svc != nil {
+		return cl.svc, nil
+	}
 	sess, err := session.NewSession(&aws.Config{
 		Region:      aws.String(cl.awsRegion),
 		Credentials: credentials.NewStaticCredentials(cl.awsAccessKey, cl.awsSecretAccessKey, ""),
@@ -51,30 +59,25 @@ func (cl *Client) SendEmail(input SendEmailInput) (*string, error) {
 	if err != nil {
 		return nil, err
 	}
-	senderName := deref.String(input.EmailSenderName, defaultEmailSenderName)
-	svc := ses.New(sess)
-	sesInput := &ses.SendEmailInput{
-		Destination: &ses.Destination{
-			ToAddresses: []*string{
-				aws.String(input.Recipient),
-			},
-			CcAddresses: []*string{},
+	cl.svc = ses.New(sess)
+	return cl.svc, nil
+}
+
+func (cl *Client) SendRawEmail(input SendRawEmailInput) (*string, error) {
+	svc, err := cl.getService()
+	if err != nil {
+		return nil, err
+	}
+	sesInput := &ses.SendRawEmailInput{
+		Destinations: []*string{
+			aws.String(input.Recipient),
 		},
-		Message: &ses.Message{
-			Body: &ses.Body{
-				Html: &ses.Content{
-					Charset: aws.String("UTF-8"),
-					Data:    aws.String(input.HTMLBody),
-				},
-			},
-			Subject: &ses.Content{
-				Charset: aws.String("UTF-8"),
-				Data:    aws.String(input.Subject),
-			},
+		RawMessage: &ses.RawMessage{
+			Data: input.RawMessage,
 		},
-		Source: aws.String(fmt.Sprintf("\"%s\" <%s>", senderName, cl.fromAddress)),
+		Source: aws.String(cl.fromAddress),
 	}
-	output, err := svc.SendEmail(sesInput)
+	output, err := svc.SendRawEmail(sesInput)
 	if err != nil {
 		return nil, err
 	}
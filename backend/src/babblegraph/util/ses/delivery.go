@@ -8,3 +8,35 @@ type Delivery struct {
 	ReportingMTA               string   `json:"reportingMTA"`
 	ReportingMTAIP             string   `json:"remoteMtaIp"`
 }
+
+type DeliveryDelay struct {
+	TimestampISO8601      string             `json:"timestamp"`
+	Type                  DeliveryDelayType  `json:"delayType"`
+	ExpirationTimeISO8601 string             `json:"expirationTime"`
+	DelayedRecipients     []DelayedRecipient `json:"delayedRecipients"`
+	ReportingMTA          *string            `json:"reportingMTA,omitempty"`
+}
+
+type DelayedRecipient struct {
+	EmailAddress   string  `json:"emailAddress"`
+	Status         *string `json:"status,omitempty"`
+	DiagnosticCode *string `json:"diagnosticCode,omitempty"`
+}
+
+type DeliveryDelayType string
+
+const (
+	DeliveryDelayTypeInternalFailure               DeliveryDelayType = "InternalFailure"
+	DeliveryDelayTypeGeneral                       DeliveryDelayType = "General"
+	DeliveryDelayTypeMailboxFull                   DeliveryDelayType = "MailboxFull"
+	DeliveryDelayTypeSpamDetected                  DeliveryDelayType = "SpamDetected"
+	DeliveryDelayTypeRecipientServerError          DeliveryDelayType = "RecipientServerError"
+	DeliveryDelayTypeIPFailure                     DeliveryDelayType = "IPFailure"
+	DeliveryDelayTypeTransientCommunicationFailure DeliveryDelayType = "TransientCommunicationFailure"
+	DeliveryDelayTypeBYOIPHostNameLookup           DeliveryDelayType = "BYOIPHostNameLookupUnavailable"
+	DeliveryDelayTypeUndetermined                  DeliveryDelayType = "Undetermined"
+)
+
+type Reject struct {
+	Reason string `json:"reason"`
+}
@@ -2,14 +2,16 @@ package ses
 
 // defined in https://docs.aws.amazon.com/ses/latest/DeveloperGuide/notification-contents.html
 type Notification struct {
-	MessageID        SESMessageID `json:"MessageId,omitempty"`
-	Token            *string      `json:"Token,omitempty"`
-	TopicARN         string       `json:"TopicArn,omitempty"`
-	Message          *string      `json:"Message,omitempty"`
-	TimestampISO8601 string       `json:"Timestamp,omitempty"`
-	SignatureVersion string       `json:"SignatureVersion,omitempty"`
-	Signature        string       `json:"Signature,omitempty"`
-	SigningCertURL   string       `json:"SigningCertURL,omitempty"`
+	Type             SNSMessageType `json:"Type,omitempty"`
+	MessageID        SESMessageID   `json:"MessageId,omitempty"`
+	Subject          *string        `json:"Subject,omitempty"`
+	Token            *string        `json:"Token,omitempty"`
+	TopicARN         string         `json:"TopicArn,omitempty"`
+	Message          *string        `json:"Message,omitempty"`
+	TimestampISO8601 string         `json:"Timestamp,omitempty"`
+	SignatureVersion string         `json:"SignatureVersion,omitempty"`
+	Signature        string         `json:"Signature,omitempty"`
+	SigningCertURL   string         `json:"SigningCertURL,omitempty"`
 
 	SubscribeURL   *string `json:"SubscribeURL,omitempty"`
 	UnsubscribeURL *string `json:"UnsubscribeURL,omitempty"`
@@ -17,21 +19,45 @@ type Notification struct {
 
 type SESMessageID string
 
+// SNSMessageType is the type of the SNS envelope,
+// not of the SES notification inside of it
+type SNSMessageType string
+
+const (
+	SNSMessageTypeNotification             SNSMessageType = "Notification"
+	SNSMessageTypeSubscriptionConfirmation SNSMessageType = "SubscriptionConfirmation"
+	SNSMessageTypeUnsubscribeConfirmation  SNSMessageType = "UnsubscribeConfirmation"
+)
+
 type NotificationType string
 
 const (
 	NotificationTypeBounce                   NotificationType = "Bounce"
 	NotificationTypeComplaint                NotificationType = "Complaint"
 	NotificationTypeDelivery                 NotificationType = "Delivery"
+	NotificationTypeDeliveryDelay            NotificationType = "DeliveryDelay"
+	NotificationTypeReject                   NotificationType = "Reject"
 	NotificationTypeSubscriptionConfirmation NotificationType = "SubscriptionConfirmation"
 )
 
 type NotificationBody struct {
-	Type      NotificationType `json:"notificationType"`
-	Mail      Mail             `json:"mail"`
-	Bounce    *Bounce          `json:"bounce,omitempty"`
-	Complaint *Complaint       `json:"complaint,omitempty"`
-	Delivery  *Delivery        `json:"delivery,omitempty"`
+	Type NotificationType `json:"notificationType"`
+	// Notifications sent through a configuration set's
+	// event destination use eventType instead of notificationType
+	EventType     NotificationType `json:"eventType,omitempty"`
+	Mail          Mail             `json:"mail"`
+	Bounce        *Bounce          `json:"bounce,omitempty"`
+	Complaint     *Complaint       `json:"complaint,omitempty"`
+	Delivery      *Delivery        `json:"delivery,omitempty"`
+	DeliveryDelay *DeliveryDelay   `json:"deliveryDelay,omitempty"`
+	Reject        *Reject          `json:"reject,omitempty"`
+}
+
+func (n NotificationBody) GetType() NotificationType {
+	if n.Type != "" {
+		return n.Type
+	}
+	return n.EventType
 }
 
 type Mail struct {
@@ -0,0 +1,161 @@
+package ses
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNS signs every message it sends, so anything posted to the
+// notification routes is checked against the signing certificate
+// before being trusted. The algorithm is described in
+// https://docs.aws.amazon.com/sns/latest/dg/sns-verify-signature-of-message.html
+
+var signingCertHostRegex = regexp.MustCompile(`^sns\.[a-z0-9\-]+\.amazonaws\.com(\.cn)?$`)
+
+var signingCertificateCache = struct {
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}{
+	certs: make(map[string]*x509.Certificate),
+}
+
+var signingCertHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+func VerifyNotificationSignature(n Notification) error {
+	cert, err := getSigningCertificate(n.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	return verifyNotificationSignatureWithCertificate(n, cert)
+}
+
+func verifyNotificationSignatureWithCertificate(n Notification, cert *x509.Certificate) error {
+	var algorithm x509.SignatureAlgorithm
+	switch n.SignatureVersion {
+	case "1":
+		algorithm = x509.SHA1WithRSA
+	case "2":
+		algorithm = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("Unsupported signature version %s", n.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(n.Signature)
+	if err != nil {
+		return fmt.Errorf("Error decoding signature: %s", err.Error())
+	}
+	stringToSign, err := getStringToSign(n)
+	if err != nil {
+		return err
+	}
+	if err := cert.CheckSignature(algorithm, []byte(stringToSign), signature); err != nil {
+		return fmt.Errorf("Signature does not match: %s", err.Error())
+	}
+	return nil
+}
+
+type signedField struct {
+	name  string
+	value *string
+}
+
+func getStringToSign(n Notification) (string, error) {
+	var fields []signedField
+	messageID := string(n.MessageID)
+	snsMessageType := string(n.Type)
+	switch n.Type {
+	case SNSMessageTypeNotification:
+		fields = []signedField{
+			{name: "Message", value: n.Message},
+			{name: "MessageId", value: &messageID},
+			{name: "Subject", value: n.Subject},
+			{name: "Timestamp", value: &n.TimestampISO8601},
+			{name: "TopicArn", value: &n.TopicARN},
+			{name: "Type", value: &snsMessageType},
+		}
+	case SNSMessageTypeSubscriptionConfirmation,
+		SNSMessageTypeUnsubscribeConfirmation:
+		fields = []signedField{
+			{name: "Message", value: n.Message},
+			{name: "MessageId", value: &messageID},
+			{name: "SubscribeURL", value: n.SubscribeURL},
+			{name: "Timestamp", value: &n.TimestampISO8601},
+			{name: "Token", value: n.Token},
+			{name: "TopicArn", value: &n.TopicARN},
+			{name: "Type", value: &snsMessageType},
+		}
+	default:
+		return "", fmt.Errorf("Unrecognized SNS message type %s", n.Type)
+	}
+	var sb strings.Builder
+	for _, f := range fields {
+		// Only Subject is optional, everything else must be present
+		if f.value == nil {
+			if f.name == "Subject" {
+				continue
+			}
+			return "", fmt.Errorf("SNS message is missing %s", f.name)
+		}
+		sb.WriteString(fmt.Sprintf("%s\n%s\n", f.name, *f.value))
+	}
+	return sb.String(), nil
+}
+
+func validateSigningCertURL(signingCertURL string) error {
+	u, err := url.Parse(signingCertURL)
+	if err != nil {
+		return fmt.Errorf("Invalid signing certificate URL: %s", err.Error())
+	}
+	switch {
+	case u.Scheme != "https":
+		return fmt.Errorf("Signing certificate URL must use https, got %s", u.Scheme)
+	case !signingCertHostRegex.MatchString(u.Hostname()):
+		return fmt.Errorf("Signing certificate URL has unexpected host %s", u.Hostname())
+	case !strings.HasSuffix(u.Path, ".pem"):
+		return fmt.Errorf("Signing certificate URL is not a certificate")
+	}
+	return nil
+}
+
+func getSigningCertificate(signingCertURL string) (*x509.Certificate, error) {
+	if err := validateSigningCertURL(signingCertURL); err != nil {
+		return nil, err
+	}
+	signingCertificateCache.mu.Lock()
+	defer signingCertificateCache.mu.Unlock()
+	if cert, ok := signingCertificateCache.certs[signingCertURL]; ok {
+		return cert, nil
+	}
+	resp, err := signingCertHTTPClient.Get(signingCertURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Got status %d fetching signing certificate", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, fmt.Errorf("Signing certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signingCertificateCache.certs[signingCertURL] = cert
+	return cert, nil
+}
@@ -0,0 +1,148 @@
+package ses
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func makeTestSigningCertificate(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err.Error())
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %s", err.Error())
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Error parsing certificate: %s", err.Error())
+	}
+	return key, cert
+}
+
+func signTestNotification(t *testing.T, key *rsa.PrivateKey, n *Notification) {
+	stringToSign, err := getStringToSign(*n)
+	if err != nil {
+		t.Fatalf("Error getting string to sign: %s", err.Error())
+	}
+	var signature []byte
+	switch n.SignatureVersion {
+	case "1":
+		hashed := sha1.Sum([]byte(stringToSign))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, hashed[:])
+	case "2":
+		hashed := sha256.Sum256([]byte(stringToSign))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	}
+	if err != nil {
+		t.Fatalf("Error signing: %s", err.Error())
+	}
+	n.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func makeTestNotification(signatureVersion string) Notification {
+	message := `{"notificationType":"Delivery"}`
+	return Notification{
+		Type:             SNSMessageTypeNotification,
+		MessageID:        SESMessageID("22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324"),
+		Message:          &message,
+		TimestampISO8601: "2021-11-01T12:00:00.000Z",
+		TopicARN:         "arn:aws:sns:us-east-1:123456789012:ses-delivery",
+		SignatureVersion: signatureVersion,
+		SigningCertURL:   "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-abc.pem",
+	}
+}
+
+func TestGetStringToSign(t *testing.T) {
+	n := makeTestNotification("1")
+	stringToSign, err := getStringToSign(n)
+	if err != nil {
+		t.Fatalf("Did not expect error, but got %s", err.Error())
+	}
+	expected := "Message\n{\"notificationType\":\"Delivery\"}\n" +
+		"MessageId\n22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324\n" +
+		"Timestamp\n2021-11-01T12:00:00.000Z\n" +
+		"TopicArn\narn:aws:sns:us-east-1:123456789012:ses-delivery\n" +
+		"Type\nNotification\n"
+	if stringToSign != expected {
+		t.Errorf("Expected %q, but got %q", expected, stringToSign)
+	}
+	subject := "Amazon SES Email Event Notification"
+	n.Subject = &subject
+	stringToSign, err = getStringToSign(n)
+	if err != nil {
+		t.Fatalf("Did not expect error, but got %s", err.Error())
+	}
+	expected = "Message\n{\"notificationType\":\"Delivery\"}\n" +
+		"MessageId\n22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324\n" +
+		"Subject\nAmazon SES Email Event Notification\n" +
+		"Timestamp\n2021-11-01T12:00:00.000Z\n" +
+		"TopicArn\narn:aws:sns:us-east-1:123456789012:ses-delivery\n" +
+		"Type\nNotification\n"
+	if stringToSign != expected {
+		t.Errorf("Expected %q, but got %q", expected, stringToSign)
+	}
+	n.Type = SNSMessageTypeSubscriptionConfirmation
+	if _, err := getStringToSign(n); err == nil {
+		t.Errorf("Expected error for subscription confirmation without token, but got none")
+	}
+}
+
+func TestVerifyNotificationSignature(t *testing.T) {
+	key, cert := makeTestSigningCertificate(t)
+	for _, signatureVersion := range []string{"1", "2"} {
+		n := makeTestNotification(signatureVersion)
+		signTestNotification(t, key, &n)
+		if err := verifyNotificationSignatureWithCertificate(n, cert); err != nil {
+			t.Errorf("Expected signature version %s to verify, but got %s", signatureVersion, err.Error())
+		}
+		tamperedMessage := `{"notificationType":"Bounce"}`
+		n.Message = &tamperedMessage
+		if err := verifyNotificationSignatureWithCertificate(n, cert); err == nil {
+			t.Errorf("Expected tampered message with signature version %s to fail verification", signatureVersion)
+		}
+	}
+	n := makeTestNotification("3")
+	if err := verifyNotificationSignatureWithCertificate(n, cert); err == nil {
+		t.Errorf("Expected unsupported signature version to fail verification")
+	}
+}
+
+func TestValidateSigningCertURL(t *testing.T) {
+	testCases := []struct {
+		url     string
+		isValid bool
+	}{
+		{url: "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-abc.pem", isValid: true},
+		{url: "https://sns.cn-north-1.amazonaws.com.cn/SimpleNotificationService-abc.pem", isValid: true},
+		{url: "http://sns.us-east-1.amazonaws.com/SimpleNotificationService-abc.pem", isValid: false},
+		{url: "https://sns.us-east-1.amazonaws.com.evil.com/SimpleNotificationService-abc.pem", isValid: false},
+		{url: "https://evil.com/sns.us-east-1.amazonaws.com.pem", isValid: false},
+		{url: "https://sns.us-east-1.amazonaws.com/index.html", isValid: false},
+	}
+	for _, tc := range testCases {
+		err := validateSigningCertURL(tc.url)
+		switch {
+		case tc.isValid && err != nil:
+			t.Errorf("Expected %s to be valid, but got %s", tc.url, err.Error())
+		case !tc.isValid && err == nil:
+			t.Errorf("Expected %s to be invalid, but it was not", tc.url)
+		}
+	}
+}
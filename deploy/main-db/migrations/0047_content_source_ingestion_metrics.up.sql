@@ -0,0 +1,30 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS content_source_ingestion_metrics(
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    source_id uuid NOT NULL REFERENCES content_source(_id),
+    metric_date DATE NOT NULL,
+    links_fetched BIGINT NOT NULL DEFAULT 0,
+    parse_failures BIGINT NOT NULL DEFAULT 0,
+    paywalled BIGINT NOT NULL DEFAULT 0,
+    indexed BIGINT NOT NULL DEFAULT 0,
+    readability_score_total BIGINT NOT NULL DEFAULT 0,
+    last_success_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (source_id, metric_date)
+);
+
+CREATE TABLE IF NOT EXISTS content_source_quarantine(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    source_id uuid NOT NULL REFERENCES content_source(_id),
+    links_fetched BIGINT NOT NULL,
+    parse_failures BIGINT NOT NULL,
+    window_start_date DATE NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS content_source_quarantine_source_id_idx ON content_source_quarantine(source_id);
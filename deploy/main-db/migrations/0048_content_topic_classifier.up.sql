@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS content_topic_classifier(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    language_code TEXT NOT NULL,
+    classifier JSONB NOT NULL,
+    evaluation JSONB NOT NULL,
+    is_active BOOLEAN NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS content_topic_classifier_active_language_idx ON content_topic_classifier(language_code) WHERE is_active = TRUE;
@@ -0,0 +1,48 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS content_podcast_feed_state(
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    source_seed_id uuid NOT NULL REFERENCES content_source_seed(_id),
+    etag TEXT,
+    last_modified_header TEXT,
+    content_hash TEXT NOT NULL,
+    last_changed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    PRIMARY KEY (source_seed_id)
+);
+
+CREATE TABLE IF NOT EXISTS content_podcast_episode_record(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    source_id uuid NOT NULL REFERENCES content_source(_id),
+    source_seed_id uuid NOT NULL REFERENCES content_source_seed(_id),
+    episode_id TEXT NOT NULL,
+    language_code TEXT NOT NULL,
+    indexed_guid TEXT NOT NULL,
+    feed_guid TEXT NOT NULL,
+    audio_url TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS content_podcast_episode_record_episode_id_idx ON content_podcast_episode_record(episode_id);
+CREATE INDEX IF NOT EXISTS content_podcast_episode_record_source_seed_id_idx ON content_podcast_episode_record(source_seed_id);
+
+CREATE TABLE IF NOT EXISTS content_podcast_feed_change(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    source_id uuid NOT NULL REFERENCES content_source(_id),
+    source_seed_id uuid NOT NULL REFERENCES content_source_seed(_id),
+    episode_id TEXT,
+    change_type TEXT NOT NULL,
+    details TEXT NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS content_podcast_feed_change_source_id_idx ON content_podcast_feed_change(source_id, created_at);
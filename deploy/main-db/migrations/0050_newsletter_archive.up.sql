@@ -0,0 +1,18 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS newsletter_archive_entries(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    newsletter_send_request_id TEXT NOT NULL REFERENCES newsletter_send_requests(_id),
+    user_id uuid NOT NULL REFERENCES users(_id),
+    email_record_id TEXT NOT NULL REFERENCES email_records(_id),
+    language_code TEXT NOT NULL,
+    date_of_send TIMESTAMP WITH TIME ZONE NOT NULL,
+    compressed_newsletter BYTEA NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS newsletter_archive_entries_send_request_idx ON newsletter_archive_entries(newsletter_send_request_id);
+CREATE INDEX IF NOT EXISTS newsletter_archive_entries_user_idx ON newsletter_archive_entries(user_id, language_code, date_of_send);
@@ -0,0 +1,19 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+ALTER TABLE user_newsletter_schedule ADD COLUMN IF NOT EXISTS is_best_time_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS user_newsletter_best_send_time(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    user_id uuid NOT NULL REFERENCES users(_id),
+    language_code TEXT NOT NULL,
+    hour_of_day_index INTEGER NOT NULL CHECK (hour_of_day_index >= 0 AND hour_of_day_index <= 23),
+    quarter_hour_index INTEGER NOT NULL CHECK (quarter_hour_index >= 0 AND quarter_hour_index <= 3),
+    number_of_engagements INTEGER NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_newsletter_best_send_time_language ON user_newsletter_best_send_time(user_id, language_code);
+CREATE INDEX IF NOT EXISTS email_records_user_first_opened_at_idx ON email_records(user_id, first_opened_at);
@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS email_engagement_events(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    email_record_id TEXT NOT NULL REFERENCES email_records(_id),
+    user_id uuid NOT NULL REFERENCES users(_id),
+    event_type TEXT NOT NULL,
+    section TEXT,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS email_engagement_events_email_record_idx ON email_engagement_events(email_record_id, event_type);
+CREATE INDEX IF NOT EXISTS email_engagement_events_created_at_idx ON email_engagement_events(created_at);
+CREATE INDEX IF NOT EXISTS email_records_ses_message_id_idx ON email_records(ses_message_id);
@@ -0,0 +1,28 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+ALTER TABLE email_records ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP WITH TIME ZONE DEFAULT NULL;
+
+-- SNS delivers at least once, so notifications are only processed
+-- the first time they're seen for a given SES message
+CREATE TABLE IF NOT EXISTS ses_processed_notifications(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    ses_message_id TEXT NOT NULL,
+    notification_type TEXT NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ses_processed_notifications_message_type_idx ON ses_processed_notifications(ses_message_id, notification_type);
+
+CREATE TABLE IF NOT EXISTS ses_transient_bounces(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    email_address TEXT NOT NULL,
+    ses_message_id TEXT NOT NULL,
+    bounce_sub_type TEXT NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS ses_transient_bounces_email_address_idx ON ses_transient_bounces(email_address, created_at);
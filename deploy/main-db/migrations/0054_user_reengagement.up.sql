@@ -0,0 +1,18 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS user_reengagement_statuses(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    user_id uuid NOT NULL REFERENCES users(_id),
+    stage TEXT NOT NULL,
+    stage_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('utc', now()),
+    -- Newsletters sent before this time are ignored when looking for dormancy,
+    -- so that a user who resumes their newsletter starts from a clean slate
+    reactivated_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_reengagement_statuses_user_id_idx ON user_reengagement_statuses(user_id);
+CREATE INDEX IF NOT EXISTS user_link_clicks_user_first_accessed_at_idx ON user_link_clicks(user_id, first_accessed_at);
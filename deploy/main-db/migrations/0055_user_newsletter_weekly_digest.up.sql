@@ -0,0 +1,3 @@
+ALTER TABLE user_newsletter_schedule ADD COLUMN IF NOT EXISTS newsletter_cadence TEXT NOT NULL DEFAULT 'daily';
+-- This is the day of the week in the user's timezone, not UTC
+ALTER TABLE user_newsletter_schedule ADD COLUMN IF NOT EXISTS weekly_digest_day_of_week_index INTEGER NOT NULL DEFAULT 0 CHECK (weekly_digest_day_of_week_index >= 0 AND weekly_digest_day_of_week_index <= 6);
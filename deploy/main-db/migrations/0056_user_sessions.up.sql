@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS user_sessions(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    user_id uuid NOT NULL REFERENCES users(_id),
+    -- Only a hash of the session token is stored, so a leaked
+    -- copy of this table cannot be used to log in as anyone
+    token_hash TEXT NOT NULL,
+    device TEXT NOT NULL,
+    ip_address TEXT,
+    user_agent TEXT,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT timezone('utc', now()),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_sessions_token_hash_idx ON user_sessions(token_hash);
+CREATE INDEX IF NOT EXISTS user_sessions_user_id_idx ON user_sessions(user_id);
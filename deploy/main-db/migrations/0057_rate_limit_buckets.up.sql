@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS rate_limit_buckets(
+    bucket_key TEXT NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    PRIMARY KEY (bucket_key)
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_updated_at_idx ON rate_limit_buckets(updated_at);
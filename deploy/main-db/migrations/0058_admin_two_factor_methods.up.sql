@@ -0,0 +1,62 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+ALTER TABLE admin_user ADD COLUMN IF NOT EXISTS two_factor_method TEXT NOT NULL DEFAULT 'email';
+
+CREATE TABLE IF NOT EXISTS admin_totp_secret(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    admin_user_id uuid NOT NULL REFERENCES admin_user(_id),
+    secret TEXT NOT NULL,
+    is_confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Each time step can only be used once, so a code
+    -- that is seen by someone else can't be replayed
+    last_used_time_step BIGINT,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS admin_totp_secret_admin_user_id ON admin_totp_secret(admin_user_id);
+
+CREATE TABLE IF NOT EXISTS admin_recovery_code(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    admin_user_id uuid NOT NULL REFERENCES admin_user(_id),
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS admin_recovery_code_admin_user_id ON admin_recovery_code(admin_user_id);
+
+CREATE TABLE IF NOT EXISTS admin_webauthn_credential(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    admin_user_id uuid NOT NULL REFERENCES admin_user(_id),
+    name TEXT NOT NULL,
+    credential_id TEXT NOT NULL,
+    -- DER encoded SubjectPublicKeyInfo
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS admin_webauthn_credential_admin_user_id ON admin_webauthn_credential(admin_user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS admin_webauthn_credential_credential_id ON admin_webauthn_credential(credential_id);
+
+CREATE TABLE IF NOT EXISTS admin_webauthn_challenge(
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    admin_user_id uuid NOT NULL REFERENCES admin_user(_id),
+    challenge TEXT NOT NULL,
+    ceremony TEXT NOT NULL,
+
+    PRIMARY KEY (challenge)
+);
+
+CREATE INDEX IF NOT EXISTS admin_webauthn_challenge_admin_user_id ON admin_webauthn_challenge(admin_user_id);
@@ -0,0 +1,24 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS admin_audit_log(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    admin_user_id uuid NOT NULL REFERENCES admin_user(_id),
+    route TEXT NOT NULL,
+    permission TEXT NOT NULL,
+    -- Secrets are redacted before this is stored,
+    -- and it is NULL for bodies that are not JSON
+    request_body JSONB,
+    -- Entries are created as pending before the handler runs
+    -- so that nothing can change without being recorded
+    result TEXT NOT NULL,
+    error_message TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_created_at ON admin_audit_log(created_at);
+CREATE INDEX IF NOT EXISTS admin_audit_log_admin_user_id_created_at ON admin_audit_log(admin_user_id, created_at);
+CREATE INDEX IF NOT EXISTS admin_audit_log_route_created_at ON admin_audit_log(route, created_at);
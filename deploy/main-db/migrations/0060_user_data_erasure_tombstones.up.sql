@@ -0,0 +1,19 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- The users row is kept (anonymized) when a user's data is erased,
+-- so that records kept for accounting still have something to point to
+CREATE TABLE IF NOT EXISTS user_data_erasure_tombstones(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    user_id uuid NOT NULL REFERENCES users(_id),
+    -- Only a hash is stored so that an erasure can be confirmed
+    -- for an email address without keeping the address itself
+    email_address_hash TEXT NOT NULL,
+    -- Number of rows removed from each table
+    erased_row_counts JSONB NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_data_erasure_tombstones_user_id_idx ON user_data_erasure_tombstones(user_id);
+CREATE INDEX IF NOT EXISTS user_data_erasure_tombstones_email_address_hash_idx ON user_data_erasure_tombstones(email_address_hash);
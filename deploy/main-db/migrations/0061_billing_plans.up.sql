@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- Each row is a Stripe price. Stripe prices can't be changed, so a new
+-- price for a plan type is a new row, and the old row is kept inactive
+-- so that subscriptions still on the old price can be matched to a plan
+CREATE TABLE IF NOT EXISTS billing_plans(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    plan_type TEXT NOT NULL,
+    external_id_mapping_id uuid NOT NULL REFERENCES billing_external_id_mapping(_id),
+    -- These are copied from Stripe when the plan is added and on every sync
+    price_cents BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    billing_interval TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS billing_plans_external_id_mapping_idx ON billing_plans(external_id_mapping_id);
+CREATE UNIQUE INDEX IF NOT EXISTS billing_plans_active_plan_type_idx ON billing_plans(plan_type) WHERE is_active = TRUE;
@@ -0,0 +1,49 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- Gift codes grant premium for a fixed number of days without a
+-- Stripe subscription. The grant starts when the code is redeemed.
+CREATE TABLE IF NOT EXISTS user_account_gift_codes(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    code TEXT NOT NULL,
+    duration_days INTEGER NOT NULL,
+    purchaser_email_address TEXT,
+    note TEXT,
+    redeemed_by_user_id uuid REFERENCES users(_id),
+    redeemed_at TIMESTAMP WITH TIME ZONE,
+    premium_expires_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_account_gift_codes_code_idx ON user_account_gift_codes(code);
+CREATE INDEX IF NOT EXISTS user_account_gift_codes_redeemed_by_user_idx ON user_account_gift_codes(redeemed_by_user_id);
+
+CREATE TABLE IF NOT EXISTS user_account_group_licenses(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    owner_user_id uuid NOT NULL REFERENCES users(_id),
+    name TEXT NOT NULL,
+    seat_count INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_account_group_licenses_owner_user_idx ON user_account_group_licenses(owner_user_id);
+
+-- Members are email addresses so that an owner can add someone
+-- before they sign up. Removed members are kept for the owner's history.
+CREATE TABLE IF NOT EXISTS user_account_group_license_members(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    group_license_id uuid NOT NULL REFERENCES user_account_group_licenses(_id),
+    email_address TEXT NOT NULL,
+    removed_at TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_account_group_license_members_active_email_idx ON user_account_group_license_members(group_license_id, email_address) WHERE removed_at IS NULL;
+CREATE INDEX IF NOT EXISTS user_account_group_license_members_email_idx ON user_account_group_license_members(email_address);
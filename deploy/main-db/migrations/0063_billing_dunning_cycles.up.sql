@@ -0,0 +1,26 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- A dunning cycle starts when Stripe fails to charge a subscription's invoice
+-- and ends when the invoice is paid, the subscription ends, or the grace period
+-- runs out and the user is downgraded
+CREATE TABLE IF NOT EXISTS billing_dunning_cycles(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    last_modified_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    premium_newsletter_subscription_id uuid NOT NULL REFERENCES billing_premium_newsletter_subscription(_id),
+    invoice_external_id_mapping_id uuid NOT NULL REFERENCES billing_external_id_mapping(_id),
+    -- These are copied from the most recent failed invoice
+    amount_due_cents BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    payment_attempt_count INTEGER NOT NULL,
+    next_payment_attempt_at TIMESTAMP WITH TIME ZONE,
+    grace_period_ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_reminder_type TEXT,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution TEXT,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS billing_dunning_cycles_open_subscription_idx ON billing_dunning_cycles(premium_newsletter_subscription_id) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS billing_dunning_cycles_created_at_idx ON billing_dunning_cycles(created_at);
@@ -0,0 +1,5 @@
+-- Members are told about their seat by the scheduler, which
+-- picks up any that haven't been notified yet
+ALTER TABLE user_account_group_license_members ADD COLUMN IF NOT EXISTS notified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS user_account_group_license_members_unnotified_idx ON user_account_group_license_members(created_at) WHERE notified_at IS NULL AND removed_at IS NULL;
@@ -0,0 +1,3 @@
+-- Routes that only need an admin to be logged in, like
+-- changing their own two factor settings, have no permission
+ALTER TABLE admin_audit_log ALTER COLUMN permission DROP NOT NULL;
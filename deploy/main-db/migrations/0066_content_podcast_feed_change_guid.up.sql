@@ -0,0 +1,5 @@
+ALTER TABLE content_podcast_feed_change ADD COLUMN IF NOT EXISTS feed_guid TEXT;
+
+-- A duplicate stays in the feed until the publisher fixes it, so it
+-- is only recorded the first time it is seen instead of on every poll
+CREATE UNIQUE INDEX IF NOT EXISTS content_podcast_feed_change_duplicate_guid_idx ON content_podcast_feed_change(source_seed_id, feed_guid) WHERE change_type = 'duplicate-in-feed';
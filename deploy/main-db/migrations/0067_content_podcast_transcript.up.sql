@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+-- Transcripts are cached so that reindexing an episode, which happens
+-- whenever its title or date changes, doesn't fetch the transcript again.
+-- A new transcript URL for the same episode is fetched and cached separately.
+CREATE TABLE IF NOT EXISTS content_podcast_transcript(
+    _id uuid DEFAULT uuid_generate_v4 (),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT timezone('utc', now()),
+    source_seed_id uuid NOT NULL REFERENCES content_source_seed(_id),
+    feed_guid TEXT NOT NULL,
+    transcript_url TEXT NOT NULL,
+    transcript_text TEXT NOT NULL,
+
+    PRIMARY KEY (_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS content_podcast_transcript_episode_url_idx ON content_podcast_transcript(source_seed_id, feed_guid, transcript_url);
@@ -0,0 +1,48 @@
+import { makePostRequestWithStandardEncoding } from 'util/bgfetch/bgfetch';
+
+import { Permission } from 'AdminWeb/api/auth/permissions';
+
+export enum AuditLogResult {
+    Pending = 'pending',
+    Success = 'success',
+    Error = 'error',
+    Failure = 'failure',
+}
+
+export type AuditLogEntry = {
+    id: string;
+    createdAt: string;
+    adminId: string;
+    emailAddress: string | undefined;
+    route: string;
+    permission: Permission | undefined;
+    requestBody: object | undefined;
+    result: AuditLogResult;
+    errorMessage: string | undefined;
+    completedAt: string | undefined;
+}
+
+export type GetAuditLogEntriesRequest = {
+    adminId?: string;
+    route?: string;
+    startTime?: string;
+    endTime?: string;
+    limit?: number;
+}
+
+export type GetAuditLogEntriesResponse = {
+    entries: Array<AuditLogEntry>;
+}
+
+export function getAuditLogEntries(
+    req: GetAuditLogEntriesRequest,
+    onSuccess: (resp: GetAuditLogEntriesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetAuditLogEntriesRequest, GetAuditLogEntriesResponse>(
+        '/ops/api/auth/get_audit_log_entries_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
@@ -5,8 +5,23 @@ export type ValidateLoginCredentialsRequest = {
 	password: string;
 }
 
+export enum TwoFactorMethod {
+    Email = 'email',
+    TOTP = 'totp',
+    WebAuthn = 'webauthn',
+}
+
+export type WebAuthnAuthenticationOptions = {
+    challenge: string;
+    relyingPartyId: string;
+    allowCredentialIds: Array<string>;
+}
+
 export type ValidateLoginCredentialsResponse = {
     success: boolean;
+    loginToken: string | undefined;
+    twoFactorMethod: TwoFactorMethod | undefined;
+    webauthnOptions: WebAuthnAuthenticationOptions | undefined;
 }
 
 export function validateLoginCredentials(
@@ -22,9 +37,47 @@ export function validateLoginCredentials(
     );
 }
 
+export type RequestEmailTwoFactorCodeRequest = {
+    loginToken: string;
+}
+
+export type RequestEmailTwoFactorCodeResponse = {
+    success: boolean;
+}
+
+export function requestEmailTwoFactorCode(
+    req: RequestEmailTwoFactorCodeRequest,
+    onSuccess: (resp: RequestEmailTwoFactorCodeResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RequestEmailTwoFactorCodeRequest, RequestEmailTwoFactorCodeResponse>(
+        '/ops/api/auth/request_email_two_factor_code_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export enum TwoFactorCodeType {
+    Email = 'email',
+    TOTP = 'totp',
+    RecoveryCode = 'recovery-code',
+    WebAuthn = 'webauthn',
+}
+
+// Binary values are unpadded base64url strings
+export type WebAuthnAssertion = {
+    credentialId: string;
+    clientDataJson: string;
+    authenticatorData: string;
+    signature: string;
+}
+
 export type ValidateTwoFactorAuthenticationCodeRequest = {
-    emailAddress: string;
-    twoFactorAuthenticationCode: string;
+    loginToken: string;
+    codeType: TwoFactorCodeType;
+    twoFactorAuthenticationCode?: string;
+    webauthnAssertion?: WebAuthnAssertion;
 }
 
 export type ValidateTwoFactorAuthenticationCodeResponse = {
@@ -108,3 +161,184 @@ export function validateTwoFactorAuthenticationCodeForCreate(
     );
 }
 
+
+export type GetTwoFactorSettingsRequest = {}
+
+export type SecurityKey = {
+    credentialId: string;
+    name: string;
+    createdAt: string;
+    lastUsedAt: string | undefined;
+}
+
+export type GetTwoFactorSettingsResponse = {
+    twoFactorMethod: TwoFactorMethod;
+    isTotpEnrolled: boolean;
+    numberOfUnusedRecoveryCodes: number;
+    securityKeys: Array<SecurityKey>;
+}
+
+export function getTwoFactorSettings(
+    req: GetTwoFactorSettingsRequest,
+    onSuccess: (resp: GetTwoFactorSettingsResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetTwoFactorSettingsRequest, GetTwoFactorSettingsResponse>(
+        '/ops/api/auth/get_two_factor_settings_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type SetTwoFactorMethodRequest = {
+    twoFactorMethod: TwoFactorMethod;
+}
+
+export type SetTwoFactorMethodResponse = {
+    success: boolean;
+}
+
+export function setTwoFactorMethod(
+    req: SetTwoFactorMethodRequest,
+    onSuccess: (resp: SetTwoFactorMethodResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<SetTwoFactorMethodRequest, SetTwoFactorMethodResponse>(
+        '/ops/api/auth/set_two_factor_method_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type BeginTOTPEnrollmentRequest = {}
+
+export type BeginTOTPEnrollmentResponse = {
+    secret: string;
+    provisioningUri: string;
+}
+
+export function beginTOTPEnrollment(
+    req: BeginTOTPEnrollmentRequest,
+    onSuccess: (resp: BeginTOTPEnrollmentResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<BeginTOTPEnrollmentRequest, BeginTOTPEnrollmentResponse>(
+        '/ops/api/auth/begin_totp_enrollment_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type ConfirmTOTPEnrollmentRequest = {
+    code: string;
+}
+
+export type ConfirmTOTPEnrollmentResponse = {
+    success: boolean;
+    recoveryCodes: Array<string> | undefined;
+}
+
+export function confirmTOTPEnrollment(
+    req: ConfirmTOTPEnrollmentRequest,
+    onSuccess: (resp: ConfirmTOTPEnrollmentResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<ConfirmTOTPEnrollmentRequest, ConfirmTOTPEnrollmentResponse>(
+        '/ops/api/auth/confirm_totp_enrollment_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type RegenerateRecoveryCodesRequest = {}
+
+export type RegenerateRecoveryCodesResponse = {
+    recoveryCodes: Array<string>;
+}
+
+export function regenerateRecoveryCodes(
+    req: RegenerateRecoveryCodesRequest,
+    onSuccess: (resp: RegenerateRecoveryCodesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RegenerateRecoveryCodesRequest, RegenerateRecoveryCodesResponse>(
+        '/ops/api/auth/regenerate_recovery_codes_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type BeginWebAuthnRegistrationRequest = {}
+
+export type BeginWebAuthnRegistrationResponse = {
+    challenge: string;
+    relyingPartyId: string;
+    relyingPartyName: string;
+    userHandle: string;
+    userName: string;
+    excludeCredentialIds: Array<string>;
+}
+
+export function beginWebAuthnRegistration(
+    req: BeginWebAuthnRegistrationRequest,
+    onSuccess: (resp: BeginWebAuthnRegistrationResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<BeginWebAuthnRegistrationRequest, BeginWebAuthnRegistrationResponse>(
+        '/ops/api/auth/begin_webauthn_registration_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type FinishWebAuthnRegistrationRequest = {
+    name: string;
+    credentialId: string;
+    clientDataJson: string;
+    authenticatorData: string;
+    publicKey: string;
+}
+
+export type FinishWebAuthnRegistrationResponse = {
+    success: boolean;
+}
+
+export function finishWebAuthnRegistration(
+    req: FinishWebAuthnRegistrationRequest,
+    onSuccess: (resp: FinishWebAuthnRegistrationResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<FinishWebAuthnRegistrationRequest, FinishWebAuthnRegistrationResponse>(
+        '/ops/api/auth/finish_webauthn_registration_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type RemoveSecurityKeyRequest = {
+    credentialId: string;
+}
+
+export type RemoveSecurityKeyResponse = {
+    success: boolean;
+}
+
+export function removeSecurityKey(
+    req: RemoveSecurityKeyRequest,
+    onSuccess: (resp: RemoveSecurityKeyResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RemoveSecurityKeyRequest, RemoveSecurityKeyResponse>(
+        '/ops/api/auth/remove_security_key_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
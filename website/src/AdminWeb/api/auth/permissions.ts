@@ -7,6 +7,8 @@ export enum Permission {
 	ManageBilling = 'manage-billing',
 	BillingAddCouponCodes = 'billing-add-coupon-codes',
 	PodcastSearch = 'podcast-search',
+	PreviewNewsletters = 'preview-newsletters',
+	ViewAuditLog = 'view-audit-log',
 
     // TODO: delete these
     ViewUserMetrics = 'view-user-metrics',
@@ -0,0 +1,117 @@
+import {
+    WebAuthnAuthenticationOptions,
+    WebAuthnAssertion,
+    BeginWebAuthnRegistrationResponse,
+} from 'AdminWeb/api/auth/auth';
+
+// The API passes binary values as unpadded base64url strings,
+// but the browser's credential APIs work with ArrayBuffers.
+
+function encodeBase64URL(buffer: ArrayBuffer) {
+    const bytes = new Uint8Array(buffer);
+    let binary = '';
+    for (let i = 0; i < bytes.length; i++) {
+        binary += String.fromCharCode(bytes[i]);
+    }
+    return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+function decodeBase64URL(encoded: string) {
+    const base64 = encoded.replace(/-/g, '+').replace(/_/g, '/');
+    const binary = atob(base64 + '='.repeat((4 - base64.length % 4) % 4));
+    const bytes = new Uint8Array(binary.length);
+    for (let i = 0; i < binary.length; i++) {
+        bytes[i] = binary.charCodeAt(i);
+    }
+    return bytes.buffer;
+}
+
+export function isWebAuthnSupported() {
+    return !!window.PublicKeyCredential && !!navigator.credentials;
+}
+
+export type SecurityKeyRegistration = {
+    credentialId: string;
+    clientDataJson: string;
+    authenticatorData: string;
+    publicKey: string;
+}
+
+export function registerSecurityKey(
+    options: BeginWebAuthnRegistrationResponse,
+    onSuccess: (registration: SecurityKeyRegistration) => void,
+    onError: (e: Error) => void,
+) {
+    navigator.credentials.create({
+        publicKey: {
+            challenge: decodeBase64URL(options.challenge),
+            rp: {
+                id: options.relyingPartyId,
+                name: options.relyingPartyName,
+            },
+            user: {
+                id: decodeBase64URL(options.userHandle),
+                name: options.userName,
+                displayName: options.userName,
+            },
+            // ES256, EdDSA, RS256
+            pubKeyCredParams: [
+                { type: 'public-key', alg: -7 },
+                { type: 'public-key', alg: -8 },
+                { type: 'public-key', alg: -257 },
+            ],
+            excludeCredentials: options.excludeCredentialIds.map((id: string) => ({
+                type: 'public-key' as PublicKeyCredentialType,
+                id: decodeBase64URL(id),
+            })),
+            attestation: 'none',
+            timeout: 60000,
+        },
+    })
+    .then((credential: Credential | null) => {
+        const publicKeyCredential = credential as PublicKeyCredential;
+        // getPublicKey() and getAuthenticatorData() save the server from having to parse CBOR
+        const response = publicKeyCredential.response as any;
+        const publicKey = response.getPublicKey ? response.getPublicKey() : null;
+        if (!publicKey) {
+            onError(new Error('This browser or security key is not supported'));
+            return;
+        }
+        onSuccess({
+            credentialId: encodeBase64URL(publicKeyCredential.rawId),
+            clientDataJson: encodeBase64URL(response.clientDataJSON),
+            authenticatorData: encodeBase64URL(response.getAuthenticatorData()),
+            publicKey: encodeBase64URL(publicKey),
+        });
+    })
+    .catch(onError);
+}
+
+export function getSecurityKeyAssertion(
+    options: WebAuthnAuthenticationOptions,
+    onSuccess: (assertion: WebAuthnAssertion) => void,
+    onError: (e: Error) => void,
+) {
+    navigator.credentials.get({
+        publicKey: {
+            challenge: decodeBase64URL(options.challenge),
+            rpId: options.relyingPartyId,
+            allowCredentials: options.allowCredentialIds.map((id: string) => ({
+                type: 'public-key' as PublicKeyCredentialType,
+                id: decodeBase64URL(id),
+            })),
+            timeout: 60000,
+        },
+    })
+    .then((credential: Credential | null) => {
+        const publicKeyCredential = credential as PublicKeyCredential;
+        const response = publicKeyCredential.response as AuthenticatorAssertionResponse;
+        onSuccess({
+            credentialId: encodeBase64URL(publicKeyCredential.rawId),
+            clientDataJson: encodeBase64URL(response.clientDataJSON),
+            authenticatorData: encodeBase64URL(response.authenticatorData),
+            signature: encodeBase64URL(response.signature),
+        });
+    })
+    .catch(onError);
+}
@@ -3,10 +3,16 @@ import { makePostRequestWithStandardEncoding } from 'util/bgfetch/bgfetch';
 import {
     PremiumNewsletterSubscription,
     Discount,
+    Plan,
+    PlanType,
     PromotionCode,
     PromotionType,
 } from 'common/api/billing/billing';
-import { SubscriptionLevel } from 'common/api/useraccounts/useraccounts';
+import {
+    GiftCode,
+    GroupLicense,
+    SubscriptionLevel,
+} from 'common/api/useraccounts/useraccounts';
 
 export type UserBillingInformation = {
     userId: string;
@@ -80,3 +86,187 @@ export function createPromotionCode(
         onError,
     );
 }
+
+export type GetBillingPlansRequest = {}
+
+export type GetBillingPlansResponse = {
+    plans: Array<Plan>;
+}
+
+export function getBillingPlans(
+    req: GetBillingPlansRequest,
+    onSuccess: (resp: GetBillingPlansResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetBillingPlansRequest, GetBillingPlansResponse>(
+        '/ops/api/billing/get_billing_plans_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type SetStripePriceForPlanTypeRequest = {
+    planType: PlanType;
+    stripePriceId: string;
+}
+
+export type SetStripePriceForPlanTypeResponse = {
+    plan: Plan;
+}
+
+export function setStripePriceForPlanType(
+    req: SetStripePriceForPlanTypeRequest,
+    onSuccess: (resp: SetStripePriceForPlanTypeResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<SetStripePriceForPlanTypeRequest, SetStripePriceForPlanTypeResponse>(
+        '/ops/api/billing/set_stripe_price_for_plan_type_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type GetGiftCodesRequest = {}
+
+export type GetGiftCodesResponse = {
+    giftCodes: Array<GiftCode> | undefined;
+}
+
+export function getGiftCodes(
+    req: GetGiftCodesRequest,
+    onSuccess: (resp: GetGiftCodesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetGiftCodesRequest, GetGiftCodesResponse>(
+        '/ops/api/billing/get_gift_codes_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type CreateGiftCodeRequest = {
+    durationDays: number;
+    purchaserEmailAddress: string | undefined;
+    note: string | undefined;
+}
+
+export type CreateGiftCodeResponse = {
+    giftCode: GiftCode;
+}
+
+export function createGiftCode(
+    req: CreateGiftCodeRequest,
+    onSuccess: (resp: CreateGiftCodeResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<CreateGiftCodeRequest, CreateGiftCodeResponse>(
+        '/ops/api/billing/create_gift_code_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type GetGroupLicensesRequest = {}
+
+export type GetGroupLicensesResponse = {
+    groupLicenses: Array<GroupLicense> | undefined;
+}
+
+export function getGroupLicenses(
+    req: GetGroupLicensesRequest,
+    onSuccess: (resp: GetGroupLicensesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetGroupLicensesRequest, GetGroupLicensesResponse>(
+        '/ops/api/billing/get_group_licenses_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type CreateGroupLicenseRequest = {
+    ownerEmailAddress: string;
+    name: string;
+    seatCount: number;
+    expiresAt: Date;
+}
+
+export type CreateGroupLicenseResponse = {
+    groupLicense: GroupLicense;
+}
+
+export function createGroupLicense(
+    req: CreateGroupLicenseRequest,
+    onSuccess: (resp: CreateGroupLicenseResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<CreateGroupLicenseRequest, CreateGroupLicenseResponse>(
+        '/ops/api/billing/create_group_license_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type UpdateGroupLicenseRequest = {
+    groupLicenseId: string;
+    seatCount: number;
+    expiresAt: Date;
+}
+
+export type UpdateGroupLicenseResponse = {
+    groupLicense: GroupLicense;
+}
+
+export function updateGroupLicense(
+    req: UpdateGroupLicenseRequest,
+    onSuccess: (resp: UpdateGroupLicenseResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<UpdateGroupLicenseRequest, UpdateGroupLicenseResponse>(
+        '/ops/api/billing/update_group_license_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type DunningMetrics = {
+    startedCount: number;
+    recoveredCount: number;
+    downgradedCount: number;
+    canceledCount: number;
+    openCount: number;
+    // These are keyed by currency
+    recoveredAmountCents: { [currency: string]: number };
+    lostAmountCents: { [currency: string]: number };
+}
+
+export type GetDunningMetricsRequest = {
+    // Both are formatted as YYYY-MM-DD, and the end date is inclusive
+    startDate: string;
+    endDate: string;
+}
+
+export type GetDunningMetricsResponse = {
+    error: string | undefined;
+    dunningMetrics: DunningMetrics | undefined;
+}
+
+export function getDunningMetrics(
+    req: GetDunningMetricsRequest,
+    onSuccess: (resp: GetDunningMetricsResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetDunningMetricsRequest, GetDunningMetricsResponse>(
+        '/ops/api/billing/get_dunning_metrics_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
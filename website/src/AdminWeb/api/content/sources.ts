@@ -306,3 +306,117 @@ export function upsertSourceFilterForSource(
         onError,
     );
 }
+
+export type SourceIngestionMetrics = {
+    sourceId: string;
+    windowStart: string;
+    linksFetched: number;
+    parseFailures: number;
+    paywalled: number;
+    indexed: number;
+    averageReadabilityScore: number | undefined;
+    lastSuccessAt: string | undefined;
+}
+
+export type GetSourceIngestionMetricsRequest = {
+    windowDays?: number;
+}
+
+export type GetSourceIngestionMetricsResponse = {
+    metrics: Array<SourceIngestionMetrics> | undefined;
+}
+
+export function getSourceIngestionMetrics(
+    req: GetSourceIngestionMetricsRequest,
+    onSuccess: (resp: GetSourceIngestionMetricsResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetSourceIngestionMetricsRequest, GetSourceIngestionMetricsResponse>(
+        '/ops/api/content/get_source_ingestion_metrics_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type SourceQuarantine = {
+    id: string;
+    sourceId: string;
+    quarantinedAt: string;
+    linksFetched: number;
+    parseFailures: number;
+    windowStartDate: string;
+}
+
+export type GetSourceQuarantinesRequest = {
+    sourceId?: string;
+}
+
+export type GetSourceQuarantinesResponse = {
+    quarantines: Array<SourceQuarantine> | undefined;
+}
+
+export function getSourceQuarantines(
+    req: GetSourceQuarantinesRequest,
+    onSuccess: (resp: GetSourceQuarantinesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetSourceQuarantinesRequest, GetSourceQuarantinesResponse>(
+        '/ops/api/content/get_source_quarantines_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type SourceConfigurationTestSourceFilter = {
+    isActive: boolean;
+    useLdJsonValidation: boolean | undefined;
+    paywallClasses: string[];
+    paywallIds: string[];
+}
+
+export type SourceConfigurationTestResult = {
+    url: string;
+    isSeedUrl: boolean;
+    bodyText: string;
+    metadata: { [key: string]: string };
+    language: string | undefined;
+    pageType: string | undefined;
+    isPaywalled: boolean;
+    links: Array<string> | undefined;
+    linksForKnownSources: Array<string> | undefined;
+    readabilityScore: number | undefined;
+    lemmatizedDescription: string | undefined;
+    topicsForUrl: Array<string> | undefined;
+    topicIds: Array<string> | undefined;
+    textProcessingError: string | undefined;
+}
+
+export type TestSourceConfigurationRequest = {
+    sampleUrl: string;
+    sourceId?: string;
+    url?: string;
+    languageCode?: WordsmithLanguageCode;
+    country?: CountryCode;
+    ingestStrategy?: IngestStrategy;
+    type?: SourceType;
+    sourceFilter?: SourceConfigurationTestSourceFilter;
+}
+
+export type TestSourceConfigurationResponse = {
+    result: SourceConfigurationTestResult;
+}
+
+export function testSourceConfiguration(
+    req: TestSourceConfigurationRequest,
+    onSuccess: (resp: TestSourceConfigurationResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<TestSourceConfigurationRequest, TestSourceConfigurationResponse>(
+        '/ops/api/content/test_source_configuration_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
@@ -0,0 +1,30 @@
+import { makePostRequestWithStandardEncoding } from 'util/bgfetch/bgfetch';
+import { WordsmithLanguageCode } from 'common/model/language/language';
+
+export type PreviewNewsletterRequest = {
+    userId: string;
+    // Formatted as YYYY-MM-DD
+    dateOfSend: string;
+    languageCode?: WordsmithLanguageCode;
+}
+
+export type PreviewNewsletterResponse = {
+    error: string | undefined;
+    noSendRequested: boolean;
+    // This is the JSON body that the preload worker would upload
+    newsletter: any | undefined;
+    html: string | undefined;
+}
+
+export function previewNewsletter(
+    req: PreviewNewsletterRequest,
+    onSuccess: (resp: PreviewNewsletterResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<PreviewNewsletterRequest, PreviewNewsletterResponse>(
+        '/ops/api/newsletter/preview_newsletter_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
@@ -108,3 +108,42 @@ export function addPodcast(
         onError,
     );
 }
+
+export enum PodcastFeedChangeType {
+    Added = 'added',
+    GUIDRewritten = 'guid-rewritten',
+    EnclosureMoved = 'enclosure-moved',
+    Duplicate = 'duplicate-in-feed',
+    Removed = 'removed',
+}
+
+export type PodcastFeedChange = {
+    id: string;
+    createdAt: string;
+    sourceId: string;
+    sourceSeedId: string;
+    episodeId: string | undefined;
+    changeType: PodcastFeedChangeType;
+    details: string;
+}
+
+export type GetPodcastFeedChangesRequest = {
+    sourceId: string;
+}
+
+export type GetPodcastFeedChangesResponse = {
+    feedChanges: Array<PodcastFeedChange> | undefined;
+}
+
+export function getPodcastFeedChanges(
+    req: GetPodcastFeedChangesRequest,
+    onSuccess: (resp: GetPodcastFeedChangesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetPodcastFeedChangesRequest, GetPodcastFeedChangesResponse>(
+        '/ops/api/podcasts/get_podcast_feed_changes_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
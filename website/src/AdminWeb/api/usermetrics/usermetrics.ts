@@ -7,6 +7,7 @@ export type GetUserStatusDataResponse = {
     unsubscribedUserCount: number;
     unverifiedUserCount: number;
     blocklistedUserCount: number;
+    erasedUserCount: number;
     verifiedUserCountNetChangeOverWeek: number;
     verifiedUserCountNetChangeOverMonth: number;
 }
@@ -23,3 +24,64 @@ export function getUserStatusData(
         onError,
     );
 }
+
+export enum EngagementEventType {
+    Sent = 'sent',
+    Delivered = 'delivered',
+    Opened = 'opened',
+    Clicked = 'clicked',
+    Unsubscribed = 'unsubscribed',
+    Bounced = 'bounced',
+    DeliveryDelayed = 'delivery-delayed',
+    Rejected = 'rejected',
+}
+
+export enum EngagementSection {
+    Document = 'document',
+    Podcast = 'podcast',
+    Advertisement = 'advertisement',
+}
+
+export type NewsletterEngagementForDay = {
+    day: string;
+    sent: number;
+    delivered: number;
+    opened: number;
+    clicked: number;
+    unsubscribed: number;
+    bounced: number;
+    deliveryDelayed: number;
+    rejected: number;
+}
+
+export type NewsletterEngagementForSection = {
+    section: EngagementSection;
+    eventType: EngagementEventType;
+    numberOfEvents: number;
+    numberOfEmails: number;
+}
+
+export type GetNewsletterEngagementRequest = {
+    // Both are formatted as YYYY-MM-DD, and the end date is inclusive
+    startDate: string;
+    endDate: string;
+}
+
+export type GetNewsletterEngagementResponse = {
+    error: string | undefined;
+    days: Array<NewsletterEngagementForDay> | undefined;
+    sections: Array<NewsletterEngagementForSection> | undefined;
+}
+
+export function getNewsletterEngagement(
+    req: GetNewsletterEngagementRequest,
+    onSuccess: (resp: GetNewsletterEngagementResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetNewsletterEngagementRequest, GetNewsletterEngagementResponse>(
+        '/ops/api/usermetrics/get_newsletter_engagement_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
@@ -0,0 +1,240 @@
+import React, { useState, useEffect } from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+
+import Page from 'common/components/Page/Page';
+import DisplayCard from 'common/components/DisplayCard/DisplayCard';
+import Form from 'common/components/Form/Form';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+import { Alignment, TypographyColor } from 'common/typography/common';
+import { Heading1, Heading3 } from 'common/typography/Heading';
+import Paragraph, { Size } from 'common/typography/Paragraph';
+import { PrimaryButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+
+import {
+    AuditLogEntry,
+    AuditLogResult,
+    GetAuditLogEntriesResponse,
+    getAuditLogEntries,
+} from 'AdminWeb/api/auth/audit';
+
+const styleClasses = makeStyles({
+    formComponent: {
+        padding: '10px',
+    },
+    textField: {
+        width: '100%',
+    },
+    requestBody: {
+        whiteSpace: 'pre-wrap',
+        wordBreak: 'break-all',
+        fontSize: '12px',
+    },
+});
+
+const colorsByResult = {
+    [AuditLogResult.Pending]: TypographyColor.Gray,
+    [AuditLogResult.Success]: TypographyColor.Confirmation,
+    [AuditLogResult.Error]: TypographyColor.Warning,
+    [AuditLogResult.Failure]: TypographyColor.Warning,
+}
+
+type AuditLogFilters = {
+    adminId?: string;
+    adminEmailAddress?: string;
+    route?: string;
+    startDate?: string;
+    endDate?: string;
+}
+
+const AuditLogPage = () => {
+    const classes = styleClasses();
+
+    const [ isLoading, setIsLoading ] = useState<boolean>(true);
+    const [ entries, setEntries ] = useState<Array<AuditLogEntry>>([]);
+    const [ error, setError ] = useState<Error>(null);
+
+    const [ filters, setFilters ] = useState<AuditLogFilters>({});
+    const [ route, setRoute ] = useState<string>(null);
+    const [ startDate, setStartDate ] = useState<string>(null);
+    const [ endDate, setEndDate ] = useState<string>(null);
+
+    useEffect(() => {
+        setIsLoading(true);
+        getAuditLogEntries({
+            adminId: filters.adminId,
+            route: filters.route,
+            // Dates are entered in the admin's time zone, and the end date is inclusive
+            startTime: !!filters.startDate ? new Date(`${filters.startDate}T00:00:00`).toISOString() : undefined,
+            endTime: !!filters.endDate ? new Date(new Date(`${filters.endDate}T00:00:00`).getTime() + 24 * 60 * 60 * 1000).toISOString() : undefined,
+        },
+        (resp: GetAuditLogEntriesResponse) => {
+            setIsLoading(false);
+            setError(null);
+            setEntries(resp.entries);
+        },
+        (err: Error) => {
+            setIsLoading(false);
+            setError(err);
+        });
+    }, [filters]);
+
+    const handleSubmit = () => {
+        setFilters({
+            ...filters,
+            route: route || undefined,
+            startDate: startDate || undefined,
+            endDate: endDate || undefined,
+        });
+    }
+
+    const handleSelectAdmin = (adminId: string, adminEmailAddress: string | undefined) => {
+        setFilters({
+            ...filters,
+            adminId: adminId,
+            adminEmailAddress: adminEmailAddress,
+        });
+    }
+
+    const handleClearAdmin = () => {
+        setFilters({
+            ...filters,
+            adminId: undefined,
+            adminEmailAddress: undefined,
+        });
+    }
+
+    let body = <LoadingSpinner />;
+    if (!!error) {
+        body = <Paragraph color={TypographyColor.Warning}>An error occurred. Make sure you have permission to view this.</Paragraph>;
+    } else if (!isLoading && !entries.length) {
+        body = <Paragraph>No audit log entries match these filters.</Paragraph>;
+    } else if (!isLoading) {
+        body = (
+            <div>
+                {
+                    entries.map((e: AuditLogEntry) => (
+                        <AuditLogEntryDisplay
+                            key={e.id}
+                            entry={e}
+                            handleSelectAdmin={handleSelectAdmin} />
+                    ))
+                }
+            </div>
+        );
+    }
+    return (
+        <Page>
+            <Heading1 color={TypographyColor.Primary}>
+                Audit Log
+            </Heading1>
+            <DisplayCard>
+                <Form handleSubmit={handleSubmit}>
+                    <Grid container>
+                        <Grid className={classes.formComponent} item xs={12} md={6}>
+                            <PrimaryTextField
+                                className={classes.textField}
+                                id="route"
+                                label="Route"
+                                variant="outlined"
+                                defaultValue={route}
+                                onChange={(e: React.ChangeEvent<HTMLInputElement>) => setRoute(e.target.value)} />
+                        </Grid>
+                        <Grid className={classes.formComponent} item xs={6} md={3}>
+                            <PrimaryTextField
+                                className={classes.textField}
+                                id="start-date"
+                                label="Start Date"
+                                type="date"
+                                variant="outlined"
+                                InputLabelProps={{ shrink: true }}
+                                defaultValue={startDate}
+                                onChange={(e: React.ChangeEvent<HTMLInputElement>) => setStartDate(e.target.value)} />
+                        </Grid>
+                        <Grid className={classes.formComponent} item xs={6} md={3}>
+                            <PrimaryTextField
+                                className={classes.textField}
+                                id="end-date"
+                                label="End Date"
+                                type="date"
+                                variant="outlined"
+                                InputLabelProps={{ shrink: true }}
+                                defaultValue={endDate}
+                                onChange={(e: React.ChangeEvent<HTMLInputElement>) => setEndDate(e.target.value)} />
+                        </Grid>
+                        <Grid className={classes.formComponent} item xs={12} md={3}>
+                            <PrimaryButton type="submit">
+                                Filter
+                            </PrimaryButton>
+                        </Grid>
+                        {
+                            !!filters.adminId && (
+                                <Grid className={classes.formComponent} item xs={12} md={9}>
+                                    <Paragraph align={Alignment.Left}>
+                                        Showing entries for {filters.adminEmailAddress || filters.adminId}.
+                                    </Paragraph>
+                                    <PrimaryButton onClick={handleClearAdmin}>
+                                        Show all admins
+                                    </PrimaryButton>
+                                </Grid>
+                            )
+                        }
+                    </Grid>
+                </Form>
+            </DisplayCard>
+            { body }
+        </Page>
+    );
+}
+
+type AuditLogEntryDisplayProps = {
+    entry: AuditLogEntry;
+
+    handleSelectAdmin: (adminId: string, adminEmailAddress: string | undefined) => void;
+}
+
+const AuditLogEntryDisplay = (props: AuditLogEntryDisplayProps) => {
+    const classes = styleClasses();
+    const { entry } = props;
+    return (
+        <DisplayCard>
+            <Grid container>
+                <Grid item xs={12} md={8}>
+                    <Heading3 align={Alignment.Left} color={TypographyColor.Primary}>
+                        {entry.route}
+                    </Heading3>
+                    <Paragraph align={Alignment.Left} size={Size.Small}>
+                        {new Date(entry.createdAt).toLocaleString()} by {entry.emailAddress || entry.adminId}
+                        {!!entry.permission ? ` using ${entry.permission}` : ''}
+                    </Paragraph>
+                </Grid>
+                <Grid item xs={12} md={4}>
+                    <Paragraph align={Alignment.Right} color={colorsByResult[entry.result]}>
+                        {entry.result}
+                    </Paragraph>
+                    <PrimaryButton onClick={() => props.handleSelectAdmin(entry.adminId, entry.emailAddress)}>
+                        Only this admin
+                    </PrimaryButton>
+                </Grid>
+            </Grid>
+            {
+                !!entry.errorMessage && (
+                    <Paragraph align={Alignment.Left} color={TypographyColor.Warning} size={Size.Small}>
+                        {entry.errorMessage}
+                    </Paragraph>
+                )
+            }
+            {
+                !!entry.requestBody && (
+                    <pre className={classes.requestBody}>
+                        {JSON.stringify(entry.requestBody, null, 2)}
+                    </pre>
+                )
+            }
+        </DisplayCard>
+    );
+}
+
+export default AuditLogPage;
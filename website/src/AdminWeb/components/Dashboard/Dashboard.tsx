@@ -52,6 +52,14 @@ const Dashboard = () => {
                     location="/ops/blog-manager"
                     title="Blog Manager"
                     description="Write, edit, delete, or promote blog posts." />
+                <NavigationCard
+                    location="/ops/two-factor-settings"
+                    title="Two Factor Authentication"
+                    description="Set up an authenticator app or security key for logging in" />
+                <NavigationCard
+                    location="/ops/audit-log"
+                    title="Audit Log"
+                    description="See every change made by admins, and whether it went through" />
             </Grid>
         </Page>
     );
@@ -7,16 +7,24 @@ import Card from '@material-ui/core/Card';
 import Page from 'common/components/Page/Page';
 import { TypographyColor } from 'common/typography/common';
 import { Heading1 } from 'common/typography/Heading';
+import Paragraph from 'common/typography/Paragraph';
 import { PrimaryButton } from 'common/components/Button/Button';
 import { PrimaryTextField } from 'common/components/TextField/TextField';
 import { setLocation } from 'util/window/Location';
 
 import {
+    TwoFactorMethod,
+    TwoFactorCodeType,
+    WebAuthnAuthenticationOptions,
+    WebAuthnAssertion,
     validateLoginCredentials,
     ValidateLoginCredentialsResponse,
+    requestEmailTwoFactorCode,
+    RequestEmailTwoFactorCodeResponse,
     validateTwoFactorAuthenticationCode,
     ValidateTwoFactorAuthenticationCodeResponse,
 } from 'AdminWeb/api/auth/auth';
+import { getSecurityKeyAssertion } from 'AdminWeb/api/auth/webauthn';
 
 const styleClasses = makeStyles({
     displayCard: {
@@ -49,11 +57,14 @@ const LoginPage = (props: LoginPageProps) => {
     const [ password, setPassword ] = useState<string | null>(null);
 
     const [ twoFactorAuthenticationCode, setTwoFactorAuthenticationCode ] = useState<string>("");
-
-    const [ shouldShowLoginForm, setShouldShowLoginForm ] = useState<boolean>(true);
+    const [ loginToken, setLoginToken ] = useState<string | null>(null);
+    const [ codeType, setCodeType ] = useState<TwoFactorCodeType | null>(null);
+    const [ webAuthnOptions, setWebAuthnOptions ] = useState<WebAuthnAuthenticationOptions | null>(null);
+    const [ hasError, setHasError ] = useState<boolean>(false);
 
     const handleSubmit = () => {
         setIsLoading(true);
+        setHasError(false);
         validateLoginCredentials({
             emailAddress: emailAddress,
             password: password,
@@ -61,29 +72,76 @@ const LoginPage = (props: LoginPageProps) => {
         (resp: ValidateLoginCredentialsResponse) => {
             setIsLoading(false);
             if (resp.success) {
-                setShouldShowLoginForm(false);
+                setLoginToken(resp.loginToken);
+                setWebAuthnOptions(resp.webauthnOptions || null);
+                setCodeType(getCodeTypeForTwoFactorMethod(resp.twoFactorMethod));
+            } else {
+                setHasError(true);
             }
         },
         (err: Error) => {
             setIsLoading(false);
+            setHasError(true);
         });
     }
-    const handleSubmitTwoFactorAuthenticationCode = () => {
+    const submitTwoFactorAuthentication = (twoFactorCode: string | null, webauthnAssertion: WebAuthnAssertion | null) => {
         setIsLoading(true);
+        setHasError(false);
         validateTwoFactorAuthenticationCode({
-            emailAddress: emailAddress,
-            twoFactorAuthenticationCode: twoFactorAuthenticationCode,
+            loginToken: loginToken,
+            codeType: codeType,
+            twoFactorAuthenticationCode: twoFactorCode || undefined,
+            webauthnAssertion: webauthnAssertion || undefined,
         },
         (resp: ValidateTwoFactorAuthenticationCodeResponse) => {
             setIsLoading(false);
             if (resp.success) {
                 setLocation("/ops/dashboard");
+            } else {
+                setHasError(true);
             }
         },
         (err: Error) => {
             setIsLoading(false);
+            setHasError(true);
+        });
+    }
+    const handleSubmitTwoFactorAuthenticationCode = () => {
+        submitTwoFactorAuthentication(twoFactorAuthenticationCode, null);
+    }
+    const handleUseSecurityKey = () => {
+        setIsLoading(true);
+        setHasError(false);
+        getSecurityKeyAssertion(
+            webAuthnOptions,
+            (assertion: WebAuthnAssertion) => {
+                submitTwoFactorAuthentication(null, assertion);
+            },
+            (err: Error) => {
+                setIsLoading(false);
+                setHasError(true);
+            });
+    }
+    const handleRequestEmailCode = () => {
+        setIsLoading(true);
+        setHasError(false);
+        requestEmailTwoFactorCode({
+            loginToken: loginToken,
+        },
+        (resp: RequestEmailTwoFactorCodeResponse) => {
+            setIsLoading(false);
+            setTwoFactorAuthenticationCode("");
+            setCodeType(TwoFactorCodeType.Email);
+        },
+        (err: Error) => {
+            setIsLoading(false);
+            setHasError(true);
         });
     }
+    const handleUseRecoveryCode = () => {
+        setTwoFactorAuthenticationCode("");
+        setCodeType(TwoFactorCodeType.RecoveryCode);
+    }
 
     return (
         <Page>
@@ -97,7 +155,14 @@ const LoginPage = (props: LoginPageProps) => {
                             babblegraph
                         </Heading1>
                         {
-                            shouldShowLoginForm ? (
+                            hasError && (
+                                <Paragraph color={TypographyColor.Warning}>
+                                    Something went wrong. Check what you entered and try again.
+                                </Paragraph>
+                            )
+                        }
+                        {
+                            !codeType ? (
                                 <LoginForm
                                     emailAddress={emailAddress}
                                     setEmailAddress={setEmailAddress}
@@ -107,9 +172,13 @@ const LoginPage = (props: LoginPageProps) => {
                                     isLoading={isLoading} />
                             ) : (
                                 <TwoFactorAuthenticationForm
+                                    codeType={codeType}
                                     twoFactorAuthenticationCode={twoFactorAuthenticationCode}
                                     setTwoFactorAuthenticationCode={setTwoFactorAuthenticationCode}
                                     handleSubmit={handleSubmitTwoFactorAuthenticationCode}
+                                    handleUseSecurityKey={handleUseSecurityKey}
+                                    handleRequestEmailCode={handleRequestEmailCode}
+                                    handleUseRecoveryCode={handleUseRecoveryCode}
                                     isLoading={isLoading} />
                             )
                         }
@@ -120,6 +189,17 @@ const LoginPage = (props: LoginPageProps) => {
     )
 }
 
+function getCodeTypeForTwoFactorMethod(method: TwoFactorMethod) {
+    switch (method) {
+        case TwoFactorMethod.TOTP:
+            return TwoFactorCodeType.TOTP;
+        case TwoFactorMethod.WebAuthn:
+            return TwoFactorCodeType.WebAuthn;
+        default:
+            return TwoFactorCodeType.Email;
+    }
+}
+
 type LoginFormProps = {
     emailAddress: string;
     setEmailAddress: (v: string) => void;
@@ -177,13 +257,24 @@ const LoginForm = (props: LoginFormProps) => {
 }
 
 type TwoFactorAuthenticationFormProps = {
+    codeType: TwoFactorCodeType;
+
     twoFactorAuthenticationCode: string;
     setTwoFactorAuthenticationCode: (v: string) => void;
 
     handleSubmit: () => void;
+    handleUseSecurityKey: () => void;
+    handleRequestEmailCode: () => void;
+    handleUseRecoveryCode: () => void;
     isLoading: boolean;
 }
 
+const codeFieldLabels = {
+    [TwoFactorCodeType.Email]: "Code from your email",
+    [TwoFactorCodeType.TOTP]: "Code from your authenticator app",
+    [TwoFactorCodeType.RecoveryCode]: "Recovery code",
+};
+
 const TwoFactorAuthenticationForm = (props: TwoFactorAuthenticationFormProps) => {
     const classes = styleClasses();
 
@@ -195,33 +286,68 @@ const TwoFactorAuthenticationForm = (props: TwoFactorAuthenticationFormProps) =>
         props.handleSubmit();
     }
     return (
-        <form onSubmit={handleSubmit} noValidate autoComplete="off">
+        <div>
+            {
+                props.codeType === TwoFactorCodeType.WebAuthn ? (
+                    <Grid container className={classes.formGridContainer}>
+                        <Grid item xs={12} className={classes.formGridItem}>
+                            <PrimaryButton onClick={props.handleUseSecurityKey} disabled={props.isLoading}>
+                                Use Security Key
+                            </PrimaryButton>
+                        </Grid>
+                    </Grid>
+                ) : (
+                    <form onSubmit={handleSubmit} noValidate autoComplete="off">
+                        <Grid container className={classes.formGridContainer}>
+                            <Grid item xs={false} md={3}>
+                                &nbsp;
+                            </Grid>
+                            <Grid item xs={12} md={6} className={classes.formGridItem}>
+                                <PrimaryTextField
+                                    key={props.codeType}
+                                    className={classes.textField}
+                                    id="two-factor-code"
+                                    label={codeFieldLabels[props.codeType]}
+                                    variant="outlined"
+                                    defaultValue={props.twoFactorAuthenticationCode}
+                                    onChange={handleTwoFactorAuthenticationCodeChange} />
+                            </Grid>
+                            <Grid item xs={false} md={3}>
+                                &nbsp;
+                            </Grid>
+                            <Grid item xs={false} md={3}>
+                                &nbsp;
+                            </Grid>
+                            <Grid item xs={3} md={2} className={classes.formGridItem}>
+                                <PrimaryButton type="submit" disabled={!props.twoFactorAuthenticationCode || props.isLoading}>
+                                    Validate
+                                </PrimaryButton>
+                            </Grid>
+                        </Grid>
+                    </form>
+                )
+            }
             <Grid container className={classes.formGridContainer}>
-                <Grid item xs={false} md={3}>
-                    &nbsp;
-                </Grid>
-                <Grid item xs={12} md={6} className={classes.formGridItem}>
-                    <PrimaryTextField
-                        className={classes.textField}
-                        id="two-factor-code"
-                        label="Two Factor Authentication Code"
-                        variant="outlined"
-                        defaultValue={props.twoFactorAuthenticationCode}
-                        onChange={handleTwoFactorAuthenticationCodeChange} />
-                </Grid>
-                <Grid item xs={false} md={3}>
-                    &nbsp;
-                </Grid>
-                <Grid item xs={false} md={3}>
-                    &nbsp;
-                </Grid>
-                <Grid item xs={3} md={2} className={classes.formGridItem}>
-                    <PrimaryButton type="submit" disabled={!props.twoFactorAuthenticationCode || props.isLoading}>
-                        Validate
-                    </PrimaryButton>
-                </Grid>
+                {
+                    props.codeType !== TwoFactorCodeType.Email && (
+                        <Grid item xs={12} md={6} className={classes.formGridItem}>
+                            <PrimaryButton onClick={props.handleRequestEmailCode} disabled={props.isLoading}>
+                                Email me a code instead
+                            </PrimaryButton>
+                        </Grid>
+                    )
+                }
+                {
+                    props.codeType !== TwoFactorCodeType.RecoveryCode && (
+                        <Grid item xs={12} md={6} className={classes.formGridItem}>
+                            <PrimaryButton onClick={props.handleUseRecoveryCode} disabled={props.isLoading}>
+                                Use a recovery code
+                            </PrimaryButton>
+                        </Grid>
+                    )
+                }
             </Grid>
-        </form>
+        </div>
     );
 }
 
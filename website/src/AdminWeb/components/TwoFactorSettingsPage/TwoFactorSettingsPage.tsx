@@ -0,0 +1,455 @@
+import React, { useState, useEffect } from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+
+import Page from 'common/components/Page/Page';
+import { Alignment, TypographyColor } from 'common/typography/common';
+import { Heading1, Heading3 } from 'common/typography/Heading';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+import Paragraph from 'common/typography/Paragraph';
+import DisplayCard from 'common/components/DisplayCard/DisplayCard';
+import { PrimaryButton, WarningButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+
+import {
+    TwoFactorMethod,
+    SecurityKey,
+    getTwoFactorSettings,
+    GetTwoFactorSettingsResponse,
+    setTwoFactorMethod,
+    SetTwoFactorMethodResponse,
+    beginTOTPEnrollment,
+    BeginTOTPEnrollmentResponse,
+    confirmTOTPEnrollment,
+    ConfirmTOTPEnrollmentResponse,
+    regenerateRecoveryCodes,
+    RegenerateRecoveryCodesResponse,
+    beginWebAuthnRegistration,
+    BeginWebAuthnRegistrationResponse,
+    finishWebAuthnRegistration,
+    FinishWebAuthnRegistrationResponse,
+    removeSecurityKey,
+    RemoveSecurityKeyResponse,
+} from 'AdminWeb/api/auth/auth';
+import {
+    isWebAuthnSupported,
+    registerSecurityKey,
+    SecurityKeyRegistration,
+} from 'AdminWeb/api/auth/webauthn';
+
+const styleClasses = makeStyles({
+    textField: {
+        width: '100%',
+    },
+    formGridItem: {
+       padding: '5px',
+    },
+    recoveryCode: {
+        fontFamily: 'monospace',
+    },
+});
+
+const twoFactorMethodNames = {
+    [TwoFactorMethod.Email]: "Email",
+    [TwoFactorMethod.TOTP]: "Authenticator App",
+    [TwoFactorMethod.WebAuthn]: "Security Key",
+};
+
+const TwoFactorSettingsPage = () => {
+    const [ isLoading, setIsLoading ] = useState<boolean>(true);
+    const [ settings, setSettings ] = useState<GetTwoFactorSettingsResponse | null>(null);
+    const [ recoveryCodes, setRecoveryCodes ] = useState<Array<string> | null>(null);
+    const [ error, setError ] = useState<Error>(null);
+
+    const loadSettings = () => {
+        setIsLoading(true);
+        getTwoFactorSettings({},
+            (resp: GetTwoFactorSettingsResponse) => {
+                setIsLoading(false);
+                setSettings(resp);
+            },
+            (err: Error) => {
+                setIsLoading(false);
+                setError(err);
+            });
+    }
+    useEffect(loadSettings, []);
+
+    const handleError = (err: Error) => {
+        setIsLoading(false);
+        setError(err);
+    }
+    const handleSetTwoFactorMethod = (method: TwoFactorMethod) => {
+        setIsLoading(true);
+        setTwoFactorMethod({
+            twoFactorMethod: method,
+        },
+        (resp: SetTwoFactorMethodResponse) => {
+            loadSettings();
+        },
+        handleError);
+    }
+    const handleRegenerateRecoveryCodes = () => {
+        setIsLoading(true);
+        regenerateRecoveryCodes({},
+            (resp: RegenerateRecoveryCodesResponse) => {
+                setRecoveryCodes(resp.recoveryCodes);
+                loadSettings();
+            },
+            handleError);
+    }
+
+    let body = <LoadingSpinner />;
+    if (!!error) {
+        body = <Paragraph color={TypographyColor.Warning}>An error occurred. Try reloading the page.</Paragraph>;
+    } else if (!isLoading && !!settings) {
+        body = (
+            <div>
+                <DisplayCard>
+                    <Heading3 color={TypographyColor.Primary}>
+                        Two Factor Method
+                    </Heading3>
+                    <Paragraph>
+                        You currently use {twoFactorMethodNames[settings.twoFactorMethod]} to log in. Email codes are always available as a fallback.
+                    </Paragraph>
+                    <Grid container>
+                        <TwoFactorMethodButton
+                            method={TwoFactorMethod.Email}
+                            currentMethod={settings.twoFactorMethod}
+                            isAvailable
+                            handleSetTwoFactorMethod={handleSetTwoFactorMethod} />
+                        <TwoFactorMethodButton
+                            method={TwoFactorMethod.TOTP}
+                            currentMethod={settings.twoFactorMethod}
+                            isAvailable={settings.isTotpEnrolled}
+                            handleSetTwoFactorMethod={handleSetTwoFactorMethod} />
+                        <TwoFactorMethodButton
+                            method={TwoFactorMethod.WebAuthn}
+                            currentMethod={settings.twoFactorMethod}
+                            isAvailable={!!settings.securityKeys.length}
+                            handleSetTwoFactorMethod={handleSetTwoFactorMethod} />
+                    </Grid>
+                </DisplayCard>
+                <RecoveryCodesDisplay
+                    numberOfUnusedRecoveryCodes={settings.numberOfUnusedRecoveryCodes}
+                    recoveryCodes={recoveryCodes}
+                    handleRegenerateRecoveryCodes={handleRegenerateRecoveryCodes} />
+                <TOTPEnrollmentDisplay
+                    isTotpEnrolled={settings.isTotpEnrolled}
+                    isCurrentMethod={settings.twoFactorMethod === TwoFactorMethod.TOTP}
+                    handleRecoveryCodes={setRecoveryCodes}
+                    handleEnrolled={loadSettings} />
+                <SecurityKeysDisplay
+                    securityKeys={settings.securityKeys}
+                    handleUpdated={loadSettings} />
+            </div>
+        );
+    }
+    return (
+        <Page>
+            <Heading1 color={TypographyColor.Primary}>
+                Two Factor Authentication
+            </Heading1>
+            { body }
+        </Page>
+    );
+}
+
+type TwoFactorMethodButtonProps = {
+    method: TwoFactorMethod;
+    currentMethod: TwoFactorMethod;
+    isAvailable: boolean;
+    handleSetTwoFactorMethod: (method: TwoFactorMethod) => void;
+}
+
+const TwoFactorMethodButton = (props: TwoFactorMethodButtonProps) => {
+    const classes = styleClasses();
+    return (
+        <Grid item xs={12} md={4} className={classes.formGridItem}>
+            <PrimaryButton
+                onClick={() => props.handleSetTwoFactorMethod(props.method)}
+                disabled={!props.isAvailable || props.method === props.currentMethod}>
+                Use {twoFactorMethodNames[props.method]}
+            </PrimaryButton>
+        </Grid>
+    );
+}
+
+type RecoveryCodesDisplayProps = {
+    numberOfUnusedRecoveryCodes: number;
+    recoveryCodes: Array<string> | null;
+    handleRegenerateRecoveryCodes: () => void;
+}
+
+const RecoveryCodesDisplay = (props: RecoveryCodesDisplayProps) => {
+    const classes = styleClasses();
+    return (
+        <DisplayCard>
+            <Heading3 color={TypographyColor.Primary}>
+                Recovery Codes
+            </Heading3>
+            {
+                !!props.recoveryCodes ? (
+                    <div>
+                        <Paragraph color={TypographyColor.Warning}>
+                            Save these somewhere safe. Each one can be used once, and they will not be shown again.
+                        </Paragraph>
+                        {
+                            props.recoveryCodes.map((code: string) => (
+                                <Paragraph key={code} className={classes.recoveryCode}>{code}</Paragraph>
+                            ))
+                        }
+                    </div>
+                ) : (
+                    <Paragraph>
+                        You have {props.numberOfUnusedRecoveryCodes} unused recovery codes.
+                    </Paragraph>
+                )
+            }
+            <WarningButton onClick={props.handleRegenerateRecoveryCodes}>
+                Generate new recovery codes
+            </WarningButton>
+        </DisplayCard>
+    );
+}
+
+type TOTPEnrollmentDisplayProps = {
+    isTotpEnrolled: boolean;
+    isCurrentMethod: boolean;
+    handleRecoveryCodes: (codes: Array<string>) => void;
+    handleEnrolled: () => void;
+}
+
+const TOTPEnrollmentDisplay = (props: TOTPEnrollmentDisplayProps) => {
+    const classes = styleClasses();
+
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ enrollment, setEnrollment ] = useState<BeginTOTPEnrollmentResponse | null>(null);
+    const [ code, setCode ] = useState<string>("");
+    const [ hasError, setHasError ] = useState<boolean>(false);
+
+    const handleBeginEnrollment = () => {
+        setIsLoading(true);
+        setHasError(false);
+        beginTOTPEnrollment({},
+            (resp: BeginTOTPEnrollmentResponse) => {
+                setIsLoading(false);
+                setEnrollment(resp);
+            },
+            (err: Error) => {
+                setIsLoading(false);
+                setHasError(true);
+            });
+    }
+    const handleConfirmEnrollment = (e: React.FormEvent<HTMLFormElement>) => {
+        e.preventDefault();
+        setIsLoading(true);
+        setHasError(false);
+        confirmTOTPEnrollment({
+            code: code,
+        },
+        (resp: ConfirmTOTPEnrollmentResponse) => {
+            setIsLoading(false);
+            if (resp.success) {
+                setEnrollment(null);
+                props.handleRecoveryCodes(resp.recoveryCodes);
+                props.handleEnrolled();
+            } else {
+                setHasError(true);
+            }
+        },
+        (err: Error) => {
+            setIsLoading(false);
+            setHasError(true);
+        });
+    }
+    const handleCodeChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        setCode((event.target as HTMLInputElement).value);
+    };
+
+    let body;
+    if (!!enrollment) {
+        body = (
+            <form onSubmit={handleConfirmEnrollment} noValidate autoComplete="off">
+                <Paragraph>
+                    Add this key to your authenticator app, then enter the code it shows.
+                </Paragraph>
+                <Paragraph className={classes.recoveryCode}>{enrollment.secret}</Paragraph>
+                <Paragraph align={Alignment.Left}>
+                    <a href={enrollment.provisioningUri}>Open in authenticator app</a>
+                </Paragraph>
+                <Grid container>
+                    <Grid item xs={12} md={6} className={classes.formGridItem}>
+                        <PrimaryTextField
+                            className={classes.textField}
+                            id="totp-code"
+                            label="Code from your authenticator app"
+                            variant="outlined"
+                            defaultValue={code}
+                            onChange={handleCodeChange} />
+                    </Grid>
+                    <Grid item xs={12} md={3} className={classes.formGridItem}>
+                        <PrimaryButton type="submit" disabled={!code || isLoading}>
+                            Confirm
+                        </PrimaryButton>
+                    </Grid>
+                </Grid>
+            </form>
+        );
+    } else if (props.isCurrentMethod) {
+        body = (
+            <Paragraph>
+                Your authenticator app is set up. To set up a different one, switch to a different two factor method first.
+            </Paragraph>
+        );
+    } else {
+        body = (
+            <div>
+                <Paragraph>
+                    {
+                        props.isTotpEnrolled ? (
+                            "Your authenticator app is set up. Setting up a new one will replace it."
+                        ) : (
+                            "Use an app like Google Authenticator or 1Password to generate login codes."
+                        )
+                    }
+                </Paragraph>
+                <PrimaryButton onClick={handleBeginEnrollment} disabled={isLoading}>
+                    Set up authenticator app
+                </PrimaryButton>
+            </div>
+        );
+    }
+    return (
+        <DisplayCard>
+            <Heading3 color={TypographyColor.Primary}>
+                Authenticator App
+            </Heading3>
+            {
+                hasError && (
+                    <Paragraph color={TypographyColor.Warning}>
+                        Something went wrong. Try again.
+                    </Paragraph>
+                )
+            }
+            { body }
+        </DisplayCard>
+    );
+}
+
+type SecurityKeysDisplayProps = {
+    securityKeys: Array<SecurityKey>;
+    handleUpdated: () => void;
+}
+
+const SecurityKeysDisplay = (props: SecurityKeysDisplayProps) => {
+    const classes = styleClasses();
+
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ name, setName ] = useState<string>("");
+    const [ hasError, setHasError ] = useState<boolean>(false);
+
+    const handleFailure = (err: Error) => {
+        setIsLoading(false);
+        setHasError(true);
+    }
+    const handleRegister = (e: React.FormEvent<HTMLFormElement>) => {
+        e.preventDefault();
+        setIsLoading(true);
+        setHasError(false);
+        beginWebAuthnRegistration({},
+            (options: BeginWebAuthnRegistrationResponse) => {
+                registerSecurityKey(
+                    options,
+                    (registration: SecurityKeyRegistration) => {
+                        finishWebAuthnRegistration({
+                            name: name,
+                            ...registration,
+                        },
+                        (resp: FinishWebAuthnRegistrationResponse) => {
+                            setIsLoading(false);
+                            props.handleUpdated();
+                        },
+                        handleFailure);
+                    },
+                    handleFailure);
+            },
+            handleFailure);
+    }
+    const handleRemove = (credentialId: string) => {
+        setIsLoading(true);
+        setHasError(false);
+        removeSecurityKey({
+            credentialId: credentialId,
+        },
+        (resp: RemoveSecurityKeyResponse) => {
+            setIsLoading(false);
+            props.handleUpdated();
+        },
+        handleFailure);
+    }
+    const handleNameChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        setName((event.target as HTMLInputElement).value);
+    };
+    return (
+        <DisplayCard>
+            <Heading3 color={TypographyColor.Primary}>
+                Security Keys
+            </Heading3>
+            {
+                hasError && (
+                    <Paragraph color={TypographyColor.Warning}>
+                        Something went wrong. You can't remove your last security key while it is your two factor method.
+                    </Paragraph>
+                )
+            }
+            <Grid container>
+                {
+                    props.securityKeys.map((k: SecurityKey) => (
+                        <Grid item xs={12} key={k.credentialId} container>
+                            <Grid item xs={8} className={classes.formGridItem}>
+                                <Paragraph align={Alignment.Left}>
+                                    {k.name} (added {new Date(k.createdAt).toLocaleDateString()})
+                                </Paragraph>
+                            </Grid>
+                            <Grid item xs={4} className={classes.formGridItem}>
+                                <WarningButton onClick={() => handleRemove(k.credentialId)} disabled={isLoading}>
+                                    Remove
+                                </WarningButton>
+                            </Grid>
+                        </Grid>
+                    ))
+                }
+            </Grid>
+            {
+                isWebAuthnSupported() ? (
+                    <form onSubmit={handleRegister} noValidate autoComplete="off">
+                        <Grid container>
+                            <Grid item xs={12} md={6} className={classes.formGridItem}>
+                                <PrimaryTextField
+                                    className={classes.textField}
+                                    id="security-key-name"
+                                    label="Security key name"
+                                    variant="outlined"
+                                    defaultValue={name}
+                                    onChange={handleNameChange} />
+                            </Grid>
+                            <Grid item xs={12} md={3} className={classes.formGridItem}>
+                                <PrimaryButton type="submit" disabled={isLoading}>
+                                    Add security key
+                                </PrimaryButton>
+                            </Grid>
+                        </Grid>
+                    </form>
+                ) : (
+                    <Paragraph>
+                        This browser does not support security keys.
+                    </Paragraph>
+                )
+            }
+        </DisplayCard>
+    );
+}
+
+export default TwoFactorSettingsPage;
@@ -56,9 +56,9 @@ const UserCountWidget = () => {
                 <NumberDisplay
                     value={metrics.blocklistedUserCount}
                     label="Blocklisted Users" />
-                <Grid item xs={false} md={3}>
-                    &nbsp;
-                </Grid>
+                <NumberDisplay
+                    value={metrics.erasedUserCount}
+                    label="Erased Users" />
                 <NumberDisplay
                     value={metrics.verifiedUserCountNetChangeOverWeek}
                     label="Verified Users Net Change (week)"
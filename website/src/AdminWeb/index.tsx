@@ -13,6 +13,8 @@ import Dashboard from 'AdminWeb/components/Dashboard/Dashboard';
 import UserMetricsPage from 'AdminWeb/components/UserMetricsPage/UserMetricsPage';
 import BillingManagementPage from 'AdminWeb/components/BillingManagementPage/BillingManagementPage';
 import PermissionManagerPage from 'AdminWeb/components/PermissionManagerPage/PermissionManagerPage';
+import TwoFactorSettingsPage from 'AdminWeb/components/TwoFactorSettingsPage/TwoFactorSettingsPage';
+import AuditLogPage from 'AdminWeb/components/AuditLogPage/AuditLogPage';
 
 import BlogListPage from 'AdminWeb/components/Blog/BlogListPage/BlogListPage';
 import BlogEditPage from 'AdminWeb/components/Blog/BlogEditPage/BlogEditPage';
@@ -38,6 +40,8 @@ class App extends React.Component{
                     <Route path="/dashboard" component={Dashboard} />
 
                     <Route path="/permission-manager" component={PermissionManagerPage} />
+                    <Route path="/two-factor-settings" component={TwoFactorSettingsPage} />
+                    <Route path="/audit-log" component={AuditLogPage} />
 
                     <Route path="/user-metrics" component={UserMetricsPage} />
                     <Route path="/billing-manager" component={BillingManagementPage} />
@@ -2,6 +2,8 @@ import { makePostRequestWithStandardEncoding } from 'util/bgfetch/bgfetch';
 
 import {
     PaymentState,
+    Plan,
+    PlanType,
     PremiumNewsletterSubscription,
     PromotionCode,
 } from 'common/api/billing/billing';
@@ -29,6 +31,7 @@ export function getOrCreateBillingInformation(
 
 export type GetOrCreatePremiumNewsletterSubscriptionRequest = {
     premiumSubscriptionCheckoutToken: string;
+    planType?: PlanType;
 }
 
 export type GetOrCreatePremiumNewsletterSubscriptionResponse = {
@@ -91,6 +94,47 @@ export function setPremiumNewsletterSubscriptionAutoRenew(
     );
 }
 
+export type GetAvailablePlansRequest = {}
+
+export type GetAvailablePlansResponse = {
+    plans: Array<Plan>;
+}
+
+export function getAvailablePlans(
+    req: GetAvailablePlansRequest,
+    onSuccess: (resp: GetAvailablePlansResponse) => void,
+    onError: (err: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetAvailablePlansRequest, GetAvailablePlansResponse>(
+        '/api/billing/get_available_plans_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type SwitchPremiumNewsletterSubscriptionPlanRequest = {
+    subscriptionManagementToken: string;
+    planType: PlanType;
+}
+
+export type SwitchPremiumNewsletterSubscriptionPlanResponse = {
+    success: boolean;
+}
+
+export function switchPremiumNewsletterSubscriptionPlan(
+    req: SwitchPremiumNewsletterSubscriptionPlanRequest,
+    onSuccess: (resp: SwitchPremiumNewsletterSubscriptionPlanResponse) => void,
+    onError: (err: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<SwitchPremiumNewsletterSubscriptionPlanRequest, SwitchPremiumNewsletterSubscriptionPlanResponse>(
+        '/api/billing/switch_premium_newsletter_subscription_plan_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
 export enum PremiumNewsletterSubscriptionUpdateType {
     TransitionToActive = 'transition-to-active',
     PaymentMethodAdded = 'payment-method-added',
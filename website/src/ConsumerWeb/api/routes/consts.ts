@@ -5,6 +5,9 @@ export enum LoginRedirectKey {
 	NewsletterPreferences = 'npf',
 	CheckoutPage = 'premco',
 	PaymentSettings = 'pymtst',
+	GiftCode = 'gftcd',
+	GroupLicenses = 'grplc',
+	NewsletterArchive = 'nwsarc',
 }
 
 export enum RouteEncryptionKey {
@@ -0,0 +1,36 @@
+import { makePostRequestWithStandardEncoding } from 'util/bgfetch/bgfetch';
+import { ClientError } from 'ConsumerWeb/api/clienterror';
+import { WordsmithLanguageCode } from 'common/model/language/language';
+
+export type GetNewsletterArchiveRequest = {
+    subscriptionManagementToken: string;
+    languageCode: WordsmithLanguageCode;
+    // Both are formatted as YYYY-MM-DD and
+    // default to the last 90 days
+    startDate?: string;
+    endDate?: string;
+}
+
+export type NewsletterArchiveIssue = {
+    id: string;
+    dateOfSend: Date;
+    viewUrl: string;
+}
+
+export type GetNewsletterArchiveResponse = {
+    issues: Array<NewsletterArchiveIssue> | undefined;
+    error: ClientError | undefined;
+}
+
+export function getNewsletterArchive(
+    req: GetNewsletterArchiveRequest,
+    onSuccess: (resp: GetNewsletterArchiveResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetNewsletterArchiveRequest, GetNewsletterArchiveResponse>(
+        '/api/user/get_newsletter_archive_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
@@ -2,11 +2,19 @@ import { makePostRequestWithStandardEncoding } from 'util/bgfetch/bgfetch';
 import { ClientError } from 'ConsumerWeb/api/clienterror';
 import { WordsmithLanguageCode } from 'common/model/language/language';
 
+export enum NewsletterCadence {
+    Daily = 'daily',
+    WeeklyDigest = 'weekly-digest',
+}
+
 export type Schedule = {
     ianaTimezone: string;
     hourIndex: number;
     quarterHourIndex: number;
     isActiveForDays: Array<boolean>;
+    isBestTimeEnabled: boolean;
+    newsletterCadence: NewsletterCadence;
+    weeklyDigestDayOfWeekIndex: number;
 }
 
 export type UserNewsletterPreferences = {
@@ -47,6 +55,7 @@ enum UserPreferencesClientError {
     EmptyEmailAddress = 'no-email-address',
     InvalidTimezone = 'invalid-timezone',
     NoActiveDay = 'no-active-day',
+    InvalidCadence = 'invalid-cadence',
 };
 
 export const UserPreferencesError = { ...UserPreferencesClientError, ...ClientError };
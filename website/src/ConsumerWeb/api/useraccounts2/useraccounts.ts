@@ -3,7 +3,10 @@ import {
     RouteEncryptionKey,
     LoginRedirectKey,
 } from 'ConsumerWeb/api/routes/consts';
-import { SubscriptionLevel } from 'common/api/useraccounts/useraccounts';
+import {
+    GroupLicense,
+    SubscriptionLevel,
+} from 'common/api/useraccounts/useraccounts';
 
 export enum UserProfileInformationError {
     InvalidKey = 'invalid-key',
@@ -74,3 +77,300 @@ export function createUser(
     );
 }
 
+
+export enum SessionDevice {
+    Desktop = 'desktop',
+    Mobile = 'mobile',
+    Tablet = 'tablet',
+    Unknown = 'unknown',
+}
+
+export type ActiveSession = {
+    id: string;
+    device: SessionDevice;
+    ipAddress: string | undefined;
+    userAgent: string | undefined;
+    createdAt: Date;
+    lastSeenAt: Date;
+    isCurrentSession: boolean;
+}
+
+export type GetActiveSessionsRequest = {}
+
+export type GetActiveSessionsResponse = {
+    sessions: Array<ActiveSession>;
+}
+
+export function getActiveSessions(
+    req: GetActiveSessionsRequest,
+    onSuccess: (resp: GetActiveSessionsResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetActiveSessionsRequest, GetActiveSessionsResponse>(
+        '/api/useraccounts/get_active_sessions_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type RevokeSessionRequest = {
+    sessionId: string;
+}
+
+export type RevokeSessionResponse = {
+    success: boolean;
+}
+
+export function revokeSession(
+    req: RevokeSessionRequest,
+    onSuccess: (resp: RevokeSessionResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RevokeSessionRequest, RevokeSessionResponse>(
+        '/api/useraccounts/revoke_session_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type RevokeAllSessionsRequest = {}
+
+export type RevokeAllSessionsResponse = {
+    success: boolean;
+}
+
+export function revokeAllSessions(
+    req: RevokeAllSessionsRequest,
+    onSuccess: (resp: RevokeAllSessionsResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RevokeAllSessionsRequest, RevokeAllSessionsResponse>(
+        '/api/useraccounts/revoke_all_sessions_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export enum UserDataExportFormat {
+    JSON = 'json',
+    ZIP = 'zip',
+}
+
+export type ExportUserDataRequest = {
+    format: UserDataExportFormat;
+}
+
+export type ExportUserDataResponse = {
+    userData: any | undefined;
+    // Base64 encoded
+    zipArchive: string | undefined;
+}
+
+export function exportUserData(
+    req: ExportUserDataRequest,
+    onSuccess: (resp: ExportUserDataResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<ExportUserDataRequest, ExportUserDataResponse>(
+        '/api/useraccounts/export_user_data_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export enum EraseUserDataError {
+    InvalidCredentials = 'invalid-credentials',
+    InvalidToken = 'invalid-token',
+}
+
+export type EraseUserDataRequest = {
+    password: string;
+}
+
+export type EraseUserDataResponse = {
+    success: boolean;
+    error: EraseUserDataError | undefined;
+}
+
+export function eraseUserData(
+    req: EraseUserDataRequest,
+    onSuccess: (resp: EraseUserDataResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<EraseUserDataRequest, EraseUserDataResponse>(
+        '/api/useraccounts/erase_user_data_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export enum RedeemGiftCodeError {
+    InvalidGiftCode = 'invalid-gift-code',
+}
+
+export type RedeemGiftCodeRequest = {
+    code: string;
+}
+
+export type RedeemGiftCodeResponse = {
+    premiumExpiresAt: Date | undefined;
+    error: RedeemGiftCodeError | undefined;
+}
+
+export function redeemGiftCode(
+    req: RedeemGiftCodeRequest,
+    onSuccess: (resp: RedeemGiftCodeResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RedeemGiftCodeRequest, RedeemGiftCodeResponse>(
+        '/api/useraccounts/redeem_gift_code_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type GetGroupLicensesRequest = {}
+
+export type GetGroupLicensesResponse = {
+    groupLicenses: Array<GroupLicense> | undefined;
+}
+
+export function getGroupLicenses(
+    req: GetGroupLicensesRequest,
+    onSuccess: (resp: GetGroupLicensesResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<GetGroupLicensesRequest, GetGroupLicensesResponse>(
+        '/api/useraccounts/get_group_licenses_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export enum AddGroupLicenseMemberError {
+    InvalidEmailAddress = 'invalid-email-address',
+    LicenseExpired = 'license-expired',
+    NoSeatsAvailable = 'no-seats-available',
+    AlreadyMember = 'already-member',
+}
+
+export type AddGroupLicenseMemberRequest = {
+    groupLicenseId: string;
+    emailAddress: string;
+}
+
+export type AddGroupLicenseMemberResponse = {
+    groupLicense: GroupLicense | undefined;
+    error: AddGroupLicenseMemberError | undefined;
+}
+
+export function addGroupLicenseMember(
+    req: AddGroupLicenseMemberRequest,
+    onSuccess: (resp: AddGroupLicenseMemberResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<AddGroupLicenseMemberRequest, AddGroupLicenseMemberResponse>(
+        '/api/useraccounts/add_group_license_member_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type RemoveGroupLicenseMemberRequest = {
+    groupLicenseId: string;
+    emailAddress: string;
+}
+
+export type RemoveGroupLicenseMemberResponse = {
+    groupLicense: GroupLicense;
+}
+
+export function removeGroupLicenseMember(
+    req: RemoveGroupLicenseMemberRequest,
+    onSuccess: (resp: RemoveGroupLicenseMemberResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RemoveGroupLicenseMemberRequest, RemoveGroupLicenseMemberResponse>(
+        '/api/useraccounts/remove_group_license_member_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type RequestUserDataLinkRequest = {
+    emailAddress: string;
+    captchaToken: string;
+}
+
+export type RequestUserDataLinkResponse = {
+    success: boolean;
+}
+
+export function requestUserDataLink(
+    req: RequestUserDataLinkRequest,
+    onSuccess: (resp: RequestUserDataLinkResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<RequestUserDataLinkRequest, RequestUserDataLinkResponse>(
+        '/api/useraccounts/request_user_data_link_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export enum ExportUserDataForTokenError {
+    InvalidToken = 'invalid-token',
+}
+
+export type ExportUserDataForTokenRequest = {
+    token: string;
+    format: UserDataExportFormat;
+}
+
+export type ExportUserDataForTokenResponse = {
+    userData: any | undefined;
+    // Base64 encoded
+    zipArchive: string | undefined;
+    error: ExportUserDataForTokenError | undefined;
+}
+
+export function exportUserDataForToken(
+    req: ExportUserDataForTokenRequest,
+    onSuccess: (resp: ExportUserDataForTokenResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<ExportUserDataForTokenRequest, ExportUserDataForTokenResponse>(
+        '/api/useraccounts/export_user_data_for_token_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
+
+export type EraseUserDataForTokenRequest = {
+    token: string;
+    emailAddress: string;
+}
+
+export function eraseUserDataForToken(
+    req: EraseUserDataForTokenRequest,
+    onSuccess: (resp: EraseUserDataResponse) => void,
+    onError: (e: Error) => void,
+) {
+    makePostRequestWithStandardEncoding<EraseUserDataForTokenRequest, EraseUserDataResponse>(
+        '/api/useraccounts/erase_user_data_for_token_1',
+        req,
+        onSuccess,
+        onError,
+    );
+}
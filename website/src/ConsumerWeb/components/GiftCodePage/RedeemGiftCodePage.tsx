@@ -0,0 +1,171 @@
+import React, { useState } from 'react';
+import { RouteComponentProps } from 'react-router-dom';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+
+import CenteredComponent from 'common/components/CenteredComponent/CenteredComponent';
+import DisplayCard from 'common/components/DisplayCard/DisplayCard';
+import DisplayCardHeader from 'common/components/DisplayCard/DisplayCardHeader';
+import { Heading3 } from 'common/typography/Heading';
+import Paragraph from 'common/typography/Paragraph';
+import { TypographyColor } from 'common/typography/common';
+import { PrimaryButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+import Link, { LinkTarget } from 'common/components/Link/Link';
+
+import {
+    withUserProfileInformation,
+    UserProfileComponentProps
+} from 'ConsumerWeb/base/UserProfile/withUserProfile';
+import {
+    RouteEncryptionKey,
+    LoginRedirectKey,
+} from 'ConsumerWeb/api/routes/consts';
+import {
+    RedeemGiftCodeResponse,
+    redeemGiftCode,
+    RedeemGiftCodeError,
+} from 'ConsumerWeb/api/useraccounts2/useraccounts';
+
+const styleClasses = makeStyles({
+    formGridItem: {
+       padding: '5px',
+    },
+    textField: {
+        width: '100%',
+    },
+});
+
+const errorMessagesByType = {
+    [RedeemGiftCodeError.InvalidGiftCode]: "That gift code isn’t valid or has already been used. Check that it was entered exactly as it appears in your email.",
+    "default": "Something went wrong redeeming your gift code. Try again, or email hello@babblegraph.com for help.",
+}
+
+type Params = {
+    token: string;
+}
+
+type RedeemGiftCodePageProps = RouteComponentProps<Params>;
+
+const RedeemGiftCodePage = withUserProfileInformation<RedeemGiftCodePageProps>(
+    RouteEncryptionKey.SubscriptionManagement,
+    [RouteEncryptionKey.CreateUser],
+    (ownProps: RedeemGiftCodePageProps) => {
+        return ownProps.match.params.token;
+    },
+    LoginRedirectKey.GiftCode,
+    (props: RedeemGiftCodePageProps & UserProfileComponentProps) => {
+        const { token } = props.match.params;
+        const [ createUserToken ] = props.userProfile.nextTokens;
+
+        return (
+            <CenteredComponent>
+                <DisplayCard>
+                    <DisplayCardHeader
+                        title="Redeem a Gift Code"
+                        backArrowDestination={`/manage/${token}`} />
+                    {
+                        props.userProfile.hasAccount ? (
+                            <RedeemGiftCodeForm />
+                        ) : (
+                            <div>
+                                <Paragraph>
+                                    You need a Babblegraph account to redeem a gift code. It only takes a minute to create one.
+                                </Paragraph>
+                                <Link href={`/signup/${createUserToken}`} target={LinkTarget.Self}>
+                                    Create your account
+                                </Link>
+                            </div>
+                        )
+                    }
+                </DisplayCard>
+            </CenteredComponent>
+        );
+    }
+);
+
+const RedeemGiftCodeForm = () => {
+    const classes = styleClasses();
+
+    const [ code, setCode ] = useState<string | null>(null);
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ errorMessage, setErrorMessage ] = useState<string | null>(null);
+    const [ premiumExpiresAt, setPremiumExpiresAt ] = useState<Date | null>(null);
+
+    const handleCodeChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        setCode((event.target as HTMLInputElement).value);
+    };
+
+    const handleSubmit = () => {
+        setIsLoading(true);
+        redeemGiftCode({
+            code: code.trim(),
+        },
+        (resp: RedeemGiftCodeResponse) => {
+            setIsLoading(false);
+            if (!!resp.error) {
+                setErrorMessage(errorMessagesByType[resp.error] || errorMessagesByType["default"]);
+                return
+            }
+            setErrorMessage(null);
+            setPremiumExpiresAt(resp.premiumExpiresAt);
+        },
+        (e: Error) => {
+            setIsLoading(false);
+            setErrorMessage(errorMessagesByType["default"]);
+        });
+    }
+
+    if (!!premiumExpiresAt) {
+        return (
+            <div>
+                <Heading3 color={TypographyColor.Confirmation}>
+                    Your gift code was redeemed!
+                </Heading3>
+                <Paragraph>
+                    You have Babblegraph Premium until {new Date(premiumExpiresAt).toLocaleDateString()}.
+                </Paragraph>
+            </div>
+        );
+    }
+    return (
+        <div>
+            <Paragraph>
+                If someone gave you Babblegraph Premium as a gift, enter the code from your gift email below.
+            </Paragraph>
+            {
+                !!errorMessage && (
+                    <Paragraph color={TypographyColor.Warning}>
+                        {errorMessage}
+                    </Paragraph>
+                )
+            }
+            {
+                isLoading ? (
+                    <LoadingSpinner />
+                ) : (
+                    <Grid container>
+                        <Grid item xs={12} md={8} className={classes.formGridItem}>
+                            <PrimaryTextField
+                                className={classes.textField}
+                                id="gift-code"
+                                label="Gift Code"
+                                variant="outlined"
+                                defaultValue={code}
+                                onChange={handleCodeChange} />
+                        </Grid>
+                        <Grid item xs={12} md={4} className={classes.formGridItem}>
+                            <PrimaryButton onClick={handleSubmit} disabled={!code}>
+                                Redeem
+                            </PrimaryButton>
+                        </Grid>
+                    </Grid>
+                )
+            }
+        </div>
+    );
+}
+
+export default RedeemGiftCodePage;
@@ -0,0 +1,255 @@
+import React, { useState } from 'react';
+import { RouteComponentProps } from 'react-router-dom';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+import Divider from '@material-ui/core/Divider';
+
+import CenteredComponent from 'common/components/CenteredComponent/CenteredComponent';
+import DisplayCard from 'common/components/DisplayCard/DisplayCard';
+import DisplayCardHeader from 'common/components/DisplayCard/DisplayCardHeader';
+import { Heading3 } from 'common/typography/Heading';
+import Paragraph, { Size } from 'common/typography/Paragraph';
+import { Alignment, TypographyColor } from 'common/typography/common';
+import { PrimaryButton, WarningButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+import { GroupLicense } from 'common/api/useraccounts/useraccounts';
+
+import {
+    asBaseComponent,
+    BaseComponentProps,
+} from 'common/base/BaseComponent';
+import {
+    withUserProfileInformation,
+    UserProfileComponentProps
+} from 'ConsumerWeb/base/UserProfile/withUserProfile';
+import {
+    RouteEncryptionKey,
+    LoginRedirectKey,
+} from 'ConsumerWeb/api/routes/consts';
+import {
+    GetGroupLicensesResponse,
+    getGroupLicenses,
+    AddGroupLicenseMemberResponse,
+    addGroupLicenseMember,
+    AddGroupLicenseMemberError,
+    RemoveGroupLicenseMemberResponse,
+    removeGroupLicenseMember,
+} from 'ConsumerWeb/api/useraccounts2/useraccounts';
+
+const styleClasses = makeStyles({
+    formGridItem: {
+       padding: '5px',
+    },
+    textField: {
+        width: '100%',
+    },
+    licenseDivider: {
+        margin: '20px 0',
+    },
+    memberRow: {
+        alignItems: 'center',
+    },
+});
+
+const errorMessagesByType = {
+    [AddGroupLicenseMemberError.InvalidEmailAddress]: "That doesn’t look like a valid email address.",
+    [AddGroupLicenseMemberError.LicenseExpired]: "This license has expired, so members can’t be added to it.",
+    [AddGroupLicenseMemberError.NoSeatsAvailable]: "Every seat on this license is taken. Remove a member to free one up.",
+    [AddGroupLicenseMemberError.AlreadyMember]: "That email address is already a member of this license.",
+    "default": "Something went wrong updating this license. Try again, or email hello@babblegraph.com for help.",
+}
+
+type Params = {
+    token: string;
+}
+
+type GroupLicensesPageProps = RouteComponentProps<Params>;
+
+const GroupLicensesPage = withUserProfileInformation<GroupLicensesPageProps>(
+    RouteEncryptionKey.SubscriptionManagement,
+    [],
+    (ownProps: GroupLicensesPageProps) => {
+        return ownProps.match.params.token;
+    },
+    LoginRedirectKey.GroupLicenses,
+    (props: GroupLicensesPageProps & UserProfileComponentProps) => {
+        const { token } = props.match.params;
+
+        return (
+            <CenteredComponent>
+                <DisplayCard>
+                    <DisplayCardHeader
+                        title="Group Licenses"
+                        backArrowDestination={`/manage/${token}`} />
+                    {
+                        props.userProfile.hasAccount ? (
+                            <GroupLicenseList />
+                        ) : (
+                            <Paragraph>
+                                You don’t have any group licenses.
+                            </Paragraph>
+                        )
+                    }
+                    <Paragraph color={TypographyColor.Primary}>
+                        Need help? Just reach out to hello@babblegraph.com
+                    </Paragraph>
+                </DisplayCard>
+            </CenteredComponent>
+        );
+    }
+);
+
+const GroupLicenseList = asBaseComponent<GetGroupLicensesResponse, {}>(
+    (props: GetGroupLicensesResponse & BaseComponentProps) => {
+        if (!props.groupLicenses || !props.groupLicenses.length) {
+            return (
+                <Paragraph>
+                    You don’t have any group licenses. If you’d like to buy Babblegraph Premium for a class or a group, email hello@babblegraph.com.
+                </Paragraph>
+            );
+        }
+        return (
+            <div>
+                {
+                    props.groupLicenses.map((groupLicense: GroupLicense) => (
+                        <GroupLicenseDisplay key={groupLicense.id} groupLicense={groupLicense} />
+                    ))
+                }
+            </div>
+        );
+    },
+    (
+        ownProps: {},
+        onSuccess: (resp: GetGroupLicensesResponse) => void,
+        onError: (err: Error) => void,
+    ) => getGroupLicenses({}, onSuccess, onError),
+    false,
+);
+
+type GroupLicenseDisplayProps = {
+    groupLicense: GroupLicense;
+}
+
+const GroupLicenseDisplay = (props: GroupLicenseDisplayProps) => {
+    const classes = styleClasses();
+
+    const [ groupLicense, setGroupLicense ] = useState<GroupLicense>(props.groupLicense);
+    const [ emailAddress, setEmailAddress ] = useState<string | null>(null);
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ errorMessage, setErrorMessage ] = useState<string | null>(null);
+
+    const memberEmailAddresses = groupLicense.memberEmailAddresses || [];
+    const isExpired = new Date(groupLicense.expiresAt) < new Date();
+
+    const handleEmailAddressChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        setEmailAddress((event.target as HTMLInputElement).value);
+    };
+
+    const handleAddMember = () => {
+        setIsLoading(true);
+        addGroupLicenseMember({
+            groupLicenseId: groupLicense.id,
+            emailAddress: emailAddress,
+        },
+        (resp: AddGroupLicenseMemberResponse) => {
+            setIsLoading(false);
+            if (!!resp.groupLicense) {
+                setGroupLicense(resp.groupLicense);
+            }
+            if (!!resp.error) {
+                setErrorMessage(errorMessagesByType[resp.error] || errorMessagesByType["default"]);
+                return
+            }
+            setErrorMessage(null);
+            setEmailAddress(null);
+        },
+        (e: Error) => {
+            setIsLoading(false);
+            setErrorMessage(errorMessagesByType["default"]);
+        });
+    }
+
+    const handleRemoveMember = (memberEmailAddress: string) => () => {
+        setIsLoading(true);
+        removeGroupLicenseMember({
+            groupLicenseId: groupLicense.id,
+            emailAddress: memberEmailAddress,
+        },
+        (resp: RemoveGroupLicenseMemberResponse) => {
+            setIsLoading(false);
+            setErrorMessage(null);
+            setGroupLicense(resp.groupLicense);
+        },
+        (e: Error) => {
+            setIsLoading(false);
+            setErrorMessage(errorMessagesByType["default"]);
+        });
+    }
+
+    return (
+        <div>
+            <Heading3 color={TypographyColor.Primary}>
+                {groupLicense.name}
+            </Heading3>
+            <Paragraph color={isExpired ? TypographyColor.Warning : TypographyColor.Gray}>
+                {memberEmailAddresses.length} of {groupLicense.seatCount} seats used. {isExpired ? "Expired" : "Expires"} on {new Date(groupLicense.expiresAt).toLocaleDateString()}.
+            </Paragraph>
+            {
+                !!errorMessage && (
+                    <Paragraph color={TypographyColor.Warning}>
+                        {errorMessage}
+                    </Paragraph>
+                )
+            }
+            {
+                isLoading ? (
+                    <LoadingSpinner />
+                ) : (
+                    <div>
+                        {
+                            memberEmailAddresses.map((memberEmailAddress: string) => (
+                                <Grid container key={memberEmailAddress} className={classes.memberRow}>
+                                    <Grid item xs={8} className={classes.formGridItem}>
+                                        <Paragraph align={Alignment.Left} size={Size.Small}>
+                                            {memberEmailAddress}
+                                        </Paragraph>
+                                    </Grid>
+                                    <Grid item xs={4} className={classes.formGridItem}>
+                                        <WarningButton onClick={handleRemoveMember(memberEmailAddress)}>
+                                            Remove
+                                        </WarningButton>
+                                    </Grid>
+                                </Grid>
+                            ))
+                        }
+                        {
+                            !isExpired && memberEmailAddresses.length < groupLicense.seatCount && (
+                                <Grid container>
+                                    <Grid item xs={12} md={8} className={classes.formGridItem}>
+                                        <PrimaryTextField
+                                            className={classes.textField}
+                                            id={`add-member-${groupLicense.id}`}
+                                            label="Email Address"
+                                            variant="outlined"
+                                            defaultValue={emailAddress}
+                                            onChange={handleEmailAddressChange} />
+                                    </Grid>
+                                    <Grid item xs={12} md={4} className={classes.formGridItem}>
+                                        <PrimaryButton onClick={handleAddMember} disabled={!emailAddress}>
+                                            Add member
+                                        </PrimaryButton>
+                                    </Grid>
+                                </Grid>
+                            )
+                        }
+                    </div>
+                )
+            }
+            <Divider className={classes.licenseDivider} />
+        </div>
+    );
+}
+
+export default GroupLicensesPage;
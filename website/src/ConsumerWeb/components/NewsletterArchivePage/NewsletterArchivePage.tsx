@@ -0,0 +1,185 @@
+import React, { useState, useEffect } from 'react';
+import { RouteComponentProps } from 'react-router-dom';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+
+import CenteredComponent from 'common/components/CenteredComponent/CenteredComponent';
+import DisplayCard from 'common/components/DisplayCard/DisplayCard';
+import DisplayCardHeader from 'common/components/DisplayCard/DisplayCardHeader';
+import Paragraph from 'common/typography/Paragraph';
+import { Alignment, TypographyColor } from 'common/typography/common';
+import { PrimaryButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+import Link, { LinkTarget } from 'common/components/Link/Link';
+import { WordsmithLanguageCode } from 'common/model/language/language';
+
+import {
+    withUserProfileInformation,
+    UserProfileComponentProps
+} from 'ConsumerWeb/base/UserProfile/withUserProfile';
+import {
+    RouteEncryptionKey,
+    LoginRedirectKey,
+} from 'ConsumerWeb/api/routes/consts';
+import {
+    NewsletterArchiveIssue,
+    GetNewsletterArchiveResponse,
+    getNewsletterArchive,
+} from 'ConsumerWeb/api/user/newsletterArchive';
+
+const styleClasses = makeStyles({
+    formGridItem: {
+       padding: '5px',
+    },
+    textField: {
+        width: '100%',
+    },
+});
+
+type Params = {
+    token: string;
+}
+
+type NewsletterArchivePageProps = RouteComponentProps<Params>;
+
+const NewsletterArchivePage = withUserProfileInformation<NewsletterArchivePageProps>(
+    RouteEncryptionKey.SubscriptionManagement,
+    [],
+    (ownProps: NewsletterArchivePageProps) => {
+        return ownProps.match.params.token;
+    },
+    LoginRedirectKey.NewsletterArchive,
+    (props: NewsletterArchivePageProps & UserProfileComponentProps) => {
+        const { token } = props.match.params;
+
+        return (
+            <CenteredComponent>
+                <DisplayCard>
+                    <DisplayCardHeader
+                        title="Past Newsletters"
+                        backArrowDestination={`/manage/${token}`} />
+                    <NewsletterArchiveList subscriptionManagementToken={token} />
+                </DisplayCard>
+            </CenteredComponent>
+        );
+    }
+);
+
+type NewsletterArchiveDateRange = {
+    startDate?: string;
+    endDate?: string;
+}
+
+type NewsletterArchiveListProps = {
+    subscriptionManagementToken: string;
+}
+
+const NewsletterArchiveList = (props: NewsletterArchiveListProps) => {
+    const classes = styleClasses();
+
+    const [ isLoading, setIsLoading ] = useState<boolean>(true);
+    const [ hasError, setHasError ] = useState<boolean>(false);
+    const [ issues, setIssues ] = useState<Array<NewsletterArchiveIssue>>([]);
+
+    const [ dateRange, setDateRange ] = useState<NewsletterArchiveDateRange>({});
+    const [ startDate, setStartDate ] = useState<string | null>(null);
+    const [ endDate, setEndDate ] = useState<string | null>(null);
+
+    useEffect(() => {
+        setIsLoading(true);
+        getNewsletterArchive({
+            subscriptionManagementToken: props.subscriptionManagementToken,
+            languageCode: WordsmithLanguageCode.Spanish,
+            startDate: dateRange.startDate,
+            endDate: dateRange.endDate,
+        },
+        (resp: GetNewsletterArchiveResponse) => {
+            setIsLoading(false);
+            setHasError(!!resp.error);
+            setIssues(resp.issues || []);
+        },
+        (err: Error) => {
+            setIsLoading(false);
+            setHasError(true);
+        });
+    }, [dateRange]);
+
+    const handleSubmit = () => {
+        setDateRange({
+            startDate: startDate || undefined,
+            endDate: endDate || undefined,
+        });
+    }
+
+    let body;
+    if (isLoading) {
+        body = <LoadingSpinner />;
+    } else if (hasError) {
+        body = (
+            <Paragraph color={TypographyColor.Warning}>
+                Something went wrong loading your past newsletters. Try again, or email hello@babblegraph.com for help.
+            </Paragraph>
+        );
+    } else if (!issues.length) {
+        body = (
+            <Paragraph>
+                We didn’t send you any newsletters in these dates.
+            </Paragraph>
+        );
+    } else {
+        body = (
+            <div>
+                {
+                    issues.map((issue: NewsletterArchiveIssue) => (
+                        <Paragraph key={issue.id} align={Alignment.Left}>
+                            <Link href={issue.viewUrl} target={LinkTarget.Blank}>
+                                {new Date(issue.dateOfSend).toLocaleDateString(undefined, { weekday: 'long', year: 'numeric', month: 'long', day: 'numeric' })}
+                            </Link>
+                        </Paragraph>
+                    ))
+                }
+            </div>
+        );
+    }
+    return (
+        <div>
+            <Paragraph>
+                Looking for an article from an old newsletter? Every newsletter we’ve sent you is here. By default, you’ll see the last 90 days.
+            </Paragraph>
+            <Grid container>
+                <Grid item xs={12} md={4} className={classes.formGridItem}>
+                    <PrimaryTextField
+                        className={classes.textField}
+                        id="start-date"
+                        label="From"
+                        type="date"
+                        variant="outlined"
+                        InputLabelProps={{ shrink: true }}
+                        defaultValue={startDate}
+                        onChange={(e: React.ChangeEvent<HTMLInputElement>) => setStartDate(e.target.value)} />
+                </Grid>
+                <Grid item xs={12} md={4} className={classes.formGridItem}>
+                    <PrimaryTextField
+                        className={classes.textField}
+                        id="end-date"
+                        label="To"
+                        type="date"
+                        variant="outlined"
+                        InputLabelProps={{ shrink: true }}
+                        defaultValue={endDate}
+                        onChange={(e: React.ChangeEvent<HTMLInputElement>) => setEndDate(e.target.value)} />
+                </Grid>
+                <Grid item xs={12} md={4} className={classes.formGridItem}>
+                    <PrimaryButton onClick={handleSubmit} disabled={isLoading}>
+                        Search
+                    </PrimaryButton>
+                </Grid>
+            </Grid>
+            { body }
+        </div>
+    );
+}
+
+export default NewsletterArchivePage;
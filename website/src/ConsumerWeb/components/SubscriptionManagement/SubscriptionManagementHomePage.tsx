@@ -82,6 +82,22 @@ const SubscriptionManagementHomePage = withUserProfileInformation<SubscriptionMa
                                 description="Need to update your preferred payment method or pause your subscription? Click here!" />
                         )
                     }
+                    <NavigationCard
+                        location={`/manage/${token}/archive`}
+                        title="Past newsletters"
+                        description="Looking for an article you saw in an old newsletter? Find every newsletter we’ve sent you by date." />
+                    <NavigationCard
+                        location={`/manage/${token}/gift-code`}
+                        title="Redeem a gift code"
+                        description="Did someone give you Babblegraph Premium as a gift? Enter your gift code here to start using it." />
+                    {
+                        props.userProfile.hasAccount && (
+                            <NavigationCard
+                                location={`/manage/${token}/group-licenses`}
+                                title="Group licenses"
+                                description="Bought Babblegraph Premium for a class or a group? Add or remove the people who can use it here." />
+                        )
+                    }
                     <NavigationCard
                         location={`/manage/${token}/unsubscribe`}
                         title="Unsubscribe"
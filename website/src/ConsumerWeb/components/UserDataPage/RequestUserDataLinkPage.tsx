@@ -0,0 +1,131 @@
+import React, { useState, useEffect } from 'react';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+import Card from '@material-ui/core/Card';
+
+import Page from 'common/components/Page/Page';
+import { Heading1 } from 'common/typography/Heading';
+import Paragraph from 'common/typography/Paragraph';
+import { TypographyColor } from 'common/typography/common';
+import { PrimaryButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+import { withCaptchaToken, loadCaptchaScript } from 'common/util/grecaptcha/grecaptcha';
+
+import {
+    RequestUserDataLinkResponse,
+    requestUserDataLink,
+} from 'ConsumerWeb/api/useraccounts2/useraccounts';
+
+const styleClasses = makeStyles({
+    displayCard: {
+        padding: '20px',
+        marginTop: '20px',
+    },
+    textField: {
+        width: '100%',
+    },
+    formGridItem: {
+       padding: '5px',
+    },
+});
+
+type RequestUserDataLinkPageProps = {};
+
+const RequestUserDataLinkPage = (props: RequestUserDataLinkPageProps) => {
+    const classes = styleClasses();
+
+    const [ emailAddress, setEmailAddress ] = useState<string | null>(null);
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ wasRequestSuccessful, setWasRequestSuccessful ] = useState<boolean | null>(null);
+    const [ error, setError ] = useState<Error>(null);
+    const [ hasLoadedCaptcha, setHasLoadedCaptcha ] = useState<boolean>(false);
+
+    const handleEmailAddressChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        setEmailAddress((event.target as HTMLInputElement).value);
+    };
+
+    const handleSubmit = () => {
+        setIsLoading(true);
+        withCaptchaToken("userdatarequest", (token: string) => {
+            requestUserDataLink({
+                emailAddress: emailAddress,
+                captchaToken: token,
+            },
+            (resp: RequestUserDataLinkResponse) => {
+                setIsLoading(false);
+                setWasRequestSuccessful(resp.success);
+            },
+            (e: Error) => {
+                setIsLoading(false);
+                setError(e);
+            });
+        });
+    };
+
+    useEffect(() => {
+        loadCaptchaScript();
+        setHasLoadedCaptcha(true);
+    }, []);
+
+    let resultBody = null;
+    if (wasRequestSuccessful) {
+        resultBody = (
+            <Paragraph color={TypographyColor.Confirmation}>
+                If Babblegraph has data for the email address entered, you’ll receive an email with a link to download or erase it in the next five minutes. The link is valid for 24 hours.
+            </Paragraph>
+        );
+    } else if (wasRequestSuccessful != null && !wasRequestSuccessful || !!error) {
+        resultBody = (
+            <Paragraph color={TypographyColor.Warning}>
+                There was a problem making this request. Try again later or email hello@babblegraph.com for assistance.
+            </Paragraph>
+        );
+    }
+
+    return (
+        <Page>
+            <Grid container>
+                <Grid item xs={false} md={3}>
+                    &nbsp;
+                </Grid>
+                <Grid item xs={12} md={6}>
+                    <Card className={classes.displayCard}>
+                        <Heading1 color={TypographyColor.Primary}>
+                            Download or erase your data
+                        </Heading1>
+                        <Paragraph>
+                            If you have a Babblegraph account, you can do this from your account settings. Otherwise, enter the email address that you receive Babblegraph at and we’ll email you a link.
+                        </Paragraph>
+                        { resultBody }
+                        {
+                            isLoading ? (
+                                <LoadingSpinner />
+                            ) : (
+                                <Grid container>
+                                    <Grid item xs={12} className={classes.formGridItem}>
+                                        <PrimaryTextField
+                                            className={classes.textField}
+                                            id="email"
+                                            label="Email Address"
+                                            variant="outlined"
+                                            defaultValue={emailAddress}
+                                            onChange={handleEmailAddressChange} />
+                                    </Grid>
+                                    <Grid item xs={12} md={4} className={classes.formGridItem}>
+                                        <PrimaryButton onClick={handleSubmit} disabled={!hasLoadedCaptcha || !emailAddress}>
+                                            Email me a link
+                                        </PrimaryButton>
+                                    </Grid>
+                                </Grid>
+                            )
+                        }
+                    </Card>
+                </Grid>
+            </Grid>
+        </Page>
+    );
+}
+
+export default RequestUserDataLinkPage;
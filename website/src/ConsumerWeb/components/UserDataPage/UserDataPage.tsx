@@ -0,0 +1,273 @@
+import React, { useState } from 'react';
+import { RouteComponentProps } from 'react-router-dom';
+
+import { makeStyles } from '@material-ui/core/styles';
+import Grid from '@material-ui/core/Grid';
+import Card from '@material-ui/core/Card';
+import Divider from '@material-ui/core/Divider';
+
+import Page from 'common/components/Page/Page';
+import { Heading1, Heading3 } from 'common/typography/Heading';
+import Paragraph from 'common/typography/Paragraph';
+import { TypographyColor } from 'common/typography/common';
+import { PrimaryButton, WarningButton } from 'common/components/Button/Button';
+import { PrimaryTextField } from 'common/components/TextField/TextField';
+import LoadingSpinner from 'common/components/LoadingSpinner/LoadingSpinner';
+
+import {
+    UserDataExportFormat,
+    ExportUserDataForTokenResponse,
+    exportUserDataForToken,
+    ExportUserDataForTokenError,
+    EraseUserDataResponse,
+    eraseUserDataForToken,
+    EraseUserDataError,
+} from 'ConsumerWeb/api/useraccounts2/useraccounts';
+
+const styleClasses = makeStyles({
+    displayCard: {
+        padding: '20px',
+        marginTop: '20px',
+    },
+    sectionDivider: {
+        margin: '20px 0',
+    },
+    formGridItem: {
+       padding: '5px',
+    },
+    textField: {
+        width: '100%',
+    },
+});
+
+const exportErrorMessagesByType = {
+    [ExportUserDataForTokenError.InvalidToken]: "The link you used is invalid or expired. Request a new link to download your data.",
+    "default": "Something went wrong downloading your data. Try again, or email hello@babblegraph.com for help.",
+}
+
+const eraseErrorMessagesByType = {
+    [EraseUserDataError.InvalidToken]: "The link you used is invalid or expired, or the email address entered doesn’t match it.",
+    "default": "Something went wrong erasing your data. Try again, or email hello@babblegraph.com for help.",
+}
+
+type Params = {
+    token: string
+}
+
+type UserDataPageProps = RouteComponentProps<Params>
+
+const UserDataPage = (props: UserDataPageProps) => {
+    const classes = styleClasses();
+    const { token } = props.match.params;
+
+    return (
+        <Page>
+            <Grid container>
+                <Grid item xs={false} md={3}>
+                    &nbsp;
+                </Grid>
+                <Grid item xs={12} md={6}>
+                    <Card className={classes.displayCard}>
+                        <Heading1 color={TypographyColor.Primary}>
+                            Your Babblegraph data
+                        </Heading1>
+                        <Paragraph>
+                            You can download a copy of everything Babblegraph has stored about you, or permanently erase it.
+                        </Paragraph>
+                        <ExportUserDataSection token={token} />
+                        <Divider className={classes.sectionDivider} />
+                        <EraseUserDataSection token={token} />
+                    </Card>
+                </Grid>
+            </Grid>
+        </Page>
+    );
+}
+
+type ExportUserDataSectionProps = {
+    token: string;
+}
+
+const ExportUserDataSection = (props: ExportUserDataSectionProps) => {
+    const classes = styleClasses();
+
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ errorMessage, setErrorMessage ] = useState<string | null>(null);
+    const [ wasDownloaded, setWasDownloaded ] = useState<boolean>(false);
+
+    const handleExport = (format: UserDataExportFormat) => () => {
+        setIsLoading(true);
+        exportUserDataForToken({
+            token: props.token,
+            format: format,
+        },
+        (resp: ExportUserDataForTokenResponse) => {
+            setIsLoading(false);
+            if (!!resp.error) {
+                setErrorMessage(exportErrorMessagesByType[resp.error] || exportErrorMessagesByType["default"]);
+                return
+            }
+            switch (format) {
+                case UserDataExportFormat.JSON:
+                    downloadBlob(new Blob([JSON.stringify(resp.userData, null, 2)], { type: 'application/json' }), 'babblegraph-data.json');
+                    break;
+                case UserDataExportFormat.ZIP:
+                    downloadBlob(base64ToBlob(resp.zipArchive, 'application/zip'), 'babblegraph-data.zip');
+                    break;
+            }
+            setErrorMessage(null);
+            setWasDownloaded(true);
+        },
+        (e: Error) => {
+            setIsLoading(false);
+            setErrorMessage(exportErrorMessagesByType["default"]);
+        });
+    }
+
+    return (
+        <div>
+            <Heading3 color={TypographyColor.Primary}>
+                Download your data
+            </Heading3>
+            {
+                !!errorMessage && (
+                    <Paragraph color={TypographyColor.Warning}>
+                        {errorMessage}
+                    </Paragraph>
+                )
+            }
+            {
+                wasDownloaded && (
+                    <Paragraph color={TypographyColor.Confirmation}>
+                        Your download has started.
+                    </Paragraph>
+                )
+            }
+            {
+                isLoading ? (
+                    <LoadingSpinner />
+                ) : (
+                    <Grid container>
+                        <Grid item xs={12} md={6} className={classes.formGridItem}>
+                            <PrimaryButton onClick={handleExport(UserDataExportFormat.JSON)}>
+                                Download as JSON
+                            </PrimaryButton>
+                        </Grid>
+                        <Grid item xs={12} md={6} className={classes.formGridItem}>
+                            <PrimaryButton onClick={handleExport(UserDataExportFormat.ZIP)}>
+                                Download as ZIP
+                            </PrimaryButton>
+                        </Grid>
+                    </Grid>
+                )
+            }
+        </div>
+    );
+}
+
+type EraseUserDataSectionProps = {
+    token: string;
+}
+
+const EraseUserDataSection = (props: EraseUserDataSectionProps) => {
+    const classes = styleClasses();
+
+    const [ emailAddress, setEmailAddress ] = useState<string | null>(null);
+    const [ isLoading, setIsLoading ] = useState<boolean>(false);
+    const [ errorMessage, setErrorMessage ] = useState<string | null>(null);
+    const [ wasErased, setWasErased ] = useState<boolean>(false);
+
+    const handleEmailAddressChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        setEmailAddress((event.target as HTMLInputElement).value);
+    };
+
+    const handleErase = () => {
+        setIsLoading(true);
+        eraseUserDataForToken({
+            token: props.token,
+            emailAddress: emailAddress,
+        },
+        (resp: EraseUserDataResponse) => {
+            setIsLoading(false);
+            if (!resp.success) {
+                setErrorMessage(eraseErrorMessagesByType[resp.error] || eraseErrorMessagesByType["default"]);
+                return
+            }
+            setErrorMessage(null);
+            setWasErased(true);
+        },
+        (e: Error) => {
+            setIsLoading(false);
+            setErrorMessage(eraseErrorMessagesByType["default"]);
+        });
+    }
+
+    if (wasErased) {
+        return (
+            <Paragraph color={TypographyColor.Confirmation}>
+                Your data has been erased. You won’t receive any more emails from Babblegraph.
+            </Paragraph>
+        );
+    }
+    return (
+        <div>
+            <Heading3 color={TypographyColor.Primary}>
+                Erase your data
+            </Heading3>
+            <Paragraph>
+                This permanently deletes your subscription, vocabulary, and any account you have with Babblegraph. It can’t be undone. To confirm, enter the email address that you receive Babblegraph at.
+            </Paragraph>
+            {
+                !!errorMessage && (
+                    <Paragraph color={TypographyColor.Warning}>
+                        {errorMessage}
+                    </Paragraph>
+                )
+            }
+            {
+                isLoading ? (
+                    <LoadingSpinner />
+                ) : (
+                    <Grid container>
+                        <Grid item xs={12} className={classes.formGridItem}>
+                            <PrimaryTextField
+                                className={classes.textField}
+                                id="email"
+                                label="Email Address"
+                                variant="outlined"
+                                defaultValue={emailAddress}
+                                onChange={handleEmailAddressChange} />
+                        </Grid>
+                        <Grid item xs={12} md={6} className={classes.formGridItem}>
+                            <WarningButton onClick={handleErase} disabled={!emailAddress}>
+                                Erase my data
+                            </WarningButton>
+                        </Grid>
+                    </Grid>
+                )
+            }
+        </div>
+    );
+}
+
+const base64ToBlob = (encoded: string, contentType: string) => {
+    const decoded = atob(encoded);
+    const bytes = new Uint8Array(decoded.length);
+    for (let i = 0; i < decoded.length; i++) {
+        bytes[i] = decoded.charCodeAt(i);
+    }
+    return new Blob([bytes], { type: contentType });
+}
+
+const downloadBlob = (blob: Blob, fileName: string) => {
+    const url = URL.createObjectURL(blob);
+    const link = document.createElement('a');
+    link.href = url;
+    link.download = fileName;
+    document.body.appendChild(link);
+    link.click();
+    document.body.removeChild(link);
+    URL.revokeObjectURL(url);
+}
+
+export default UserDataPage;
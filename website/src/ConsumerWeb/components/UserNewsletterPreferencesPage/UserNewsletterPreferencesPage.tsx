@@ -53,6 +53,7 @@ import {
 
     UserNewsletterPreferences,
     UserPreferencesError,
+    NewsletterCadence,
 } from 'ConsumerWeb/api/user/userNewsletterPreferences';
 import {
     toTitleCase
@@ -73,6 +74,7 @@ const errorMessages = {
     [UserPreferencesError["InvalidLanguageCode"]]: "That language is not yet supported by Babblegraph, try again later",
     [UserPreferencesError["InvalidTimezone"]]: "We didn't understand that timezone",
     [UserPreferencesError["NoActiveDay"]]: "You must have at least one active day. Click the link at the bottom of your newsletter if you want to unsubscribe instead",
+    [UserPreferencesError["InvalidCadence"]]: "We didn't understand which day to send your weekly digest",
     [UserPreferencesError["EmptyEmailAddress"]]: "You need to provide an email address",
     "default": "Something went wrong processing that request. Try again later or email hello@babblegraph.com for support.",
 }
@@ -200,6 +202,12 @@ const UserNewsletterPreferencesDisplay = asBaseComponent<GetUserNewsletterPrefer
         const [ hourIndex, setHourIndex ] = useState<number>(props.preferences.schedule.hourIndex);
         const [ quarterHourIndex, setQuarterHourIndex ] = useState<number>(props.preferences.schedule.quarterHourIndex * 15);
         const [ isActiveForDays, setIsActiveForDays ] = useState<Array<boolean>>(props.preferences.schedule.isActiveForDays);
+        const [ isBestTimeEnabled, setIsBestTimeEnabled ] = useState<boolean>(props.preferences.schedule.isBestTimeEnabled);
+        const [ isWeeklyDigest, setIsWeeklyDigest ] = useState<boolean>(props.preferences.schedule.newsletterCadence === NewsletterCadence.WeeklyDigest);
+        const [ weeklyDigestDayOfWeekIndex, setWeeklyDigestDayOfWeekIndex ] = useState<number>(props.preferences.schedule.weeklyDigestDayOfWeekIndex);
+        const handleWeeklyDigestDayChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+            setWeeklyDigestDayOfWeekIndex(parseInt((event.target as HTMLInputElement).value, 10));
+        };
         const [ numberOfArticlesPerEmail, setNumberOfArticlesPerEmail ] = useState<number>(props.preferences.numberOfArticlesPerEmail);
         const handleUpdateNumberOfArticles = (event: React.ChangeEvent<HTMLInputElement>) => {
             const numberOfArticles = parseInt((event.target as HTMLInputElement).value, 10);
@@ -247,6 +255,9 @@ const UserNewsletterPreferencesDisplay = asBaseComponent<GetUserNewsletterPrefer
                         hourIndex: hourIndex,
                         quarterHourIndex: quarterHourIndex / 15,
                         isActiveForDays: isActiveForDays,
+                        isBestTimeEnabled: isBestTimeEnabled,
+                        newsletterCadence: isWeeklyDigest ? NewsletterCadence.WeeklyDigest : NewsletterCadence.Daily,
+                        weeklyDigestDayOfWeekIndex: weeklyDigestDayOfWeekIndex,
                     },
                 },
             },
@@ -302,36 +313,95 @@ const UserNewsletterPreferencesDisplay = asBaseComponent<GetUserNewsletterPrefer
                                 handleUpdateHourIndex={setHourIndex}
                                 handleUpdateQuarterHourIndex={setQuarterHourIndex} />
                         </Grid>
-                        <Grid item xs={12}>
+                        <Grid item xs={10} xl={11}>
                             <Heading4 align={Alignment.Left} color={TypographyColor.Primary}>
-                                Which days would you like to receive your newsletter?
+                                Send your newsletter when you usually read it?
                             </Heading4>
+                            <Paragraph align={Alignment.Left}>
+                                If this is enabled, Babblegraph learns when you usually open your newsletter and click on articles, and sends your newsletter shortly before then. Until there’s enough to learn from, your newsletter will keep arriving at the time you selected above.
+                            </Paragraph>
+                        </Grid>
+                        <Grid item
+                            className={classes.toggleContainer}
+                            xs={2}
+                            xl={1}>
+                            <PrimarySwitch
+                                checked={isBestTimeEnabled}
+                                onClick={() => {setIsBestTimeEnabled(!isBestTimeEnabled)}}
+                                disabled={isLoading} />
+                        </Grid>
+                        <Grid item xs={10} xl={11}>
+                            <Heading4 align={Alignment.Left} color={TypographyColor.Primary}>
+                                Receive a weekly digest instead?
+                            </Heading4>
+                            <Paragraph align={Alignment.Left}>
+                                If this is enabled, you’ll get one email a week instead of your regular newsletter. It has the week’s best articles for each of your topics, the words you’re tracking that showed up most in the news, and podcast picks.
+                            </Paragraph>
+                        </Grid>
+                        <Grid item
+                            className={classes.toggleContainer}
+                            xs={2}
+                            xl={1}>
+                            <PrimarySwitch
+                                checked={isWeeklyDigest}
+                                onClick={() => {setIsWeeklyDigest(!isWeeklyDigest)}}
+                                disabled={isLoading} />
                         </Grid>
                         {
-                            (daysOfTheWeekByLanguageCode[DisplayLanguage.English] || []).map((day: string, idx: number) => (
+                            isWeeklyDigest ? (
                                 <Grid item xs={12}>
-                                    <Grid container>
-                                        <Grid item xs={10} xl={11}>
-                                            <Paragraph align={Alignment.Left}>
-                                                {toTitleCase(day)}
-                                            </Paragraph>
-                                        </Grid>
-                                        <Grid item
-                                            className={classes.toggleContainer}
-                                            xs={2}
-                                            xl={1}>
-                                            <PrimarySwitch
-                                                checked={isActiveForDays[idx]}
-                                                onClick={() => {
-                                                    setIsActiveForDays(
-                                                        isActiveForDays.map((val: boolean, i: number) => i === idx ? !val : val)
-                                                    )
-                                                }}
-                                                disabled={isLoading} />
-                                        </Grid>
-                                    </Grid>
+                                    <Heading4 align={Alignment.Left} color={TypographyColor.Primary}>
+                                        Which day would you like to receive your weekly digest?
+                                    </Heading4>
+                                    <FormControl component="fieldset">
+                                        <RadioGroup aria-label="weekly-digest-day" name="weekly-digest-day" value={`${weeklyDigestDayOfWeekIndex}`} onChange={handleWeeklyDigestDayChange}>
+                                            <Grid container>
+                                                {
+                                                    (daysOfTheWeekByLanguageCode[DisplayLanguage.English] || []).map((day: string, idx: number) => (
+                                                        <Grid key={`weekly-digest-day-${idx}`} item xs={12}>
+                                                            <FormControlLabel value={`${idx}`} control={<PrimaryRadio disabled={isLoading} />} label={toTitleCase(day)} />
+                                                        </Grid>
+                                                    ))
+                                                }
+                                            </Grid>
+                                        </RadioGroup>
+                                    </FormControl>
                                 </Grid>
-                            ))
+                            ) : (
+                                <React.Fragment>
+                                    <Grid item xs={12}>
+                                        <Heading4 align={Alignment.Left} color={TypographyColor.Primary}>
+                                            Which days would you like to receive your newsletter?
+                                        </Heading4>
+                                    </Grid>
+                                    {
+                                        (daysOfTheWeekByLanguageCode[DisplayLanguage.English] || []).map((day: string, idx: number) => (
+                                            <Grid item xs={12}>
+                                                <Grid container>
+                                                    <Grid item xs={10} xl={11}>
+                                                        <Paragraph align={Alignment.Left}>
+                                                            {toTitleCase(day)}
+                                                        </Paragraph>
+                                                    </Grid>
+                                                    <Grid item
+                                                        className={classes.toggleContainer}
+                                                        xs={2}
+                                                        xl={1}>
+                                                        <PrimarySwitch
+                                                            checked={isActiveForDays[idx]}
+                                                            onClick={() => {
+                                                                setIsActiveForDays(
+                                                                    isActiveForDays.map((val: boolean, i: number) => i === idx ? !val : val)
+                                                                )
+                                                            }}
+                                                            disabled={isLoading} />
+                                                    </Grid>
+                                                </Grid>
+                                            </Grid>
+                                        ))
+                                    }
+                                </React.Fragment>
+                            )
                         }
                         <Grid item xs={10} xl={11}>
                             <Heading4 align={Alignment.Left} color={TypographyColor.Primary}>
@@ -29,10 +29,15 @@ import LoginPage from 'ConsumerWeb/components/UserAccounts/LoginPage';
 import CreateUserAccountPage from 'ConsumerWeb/components/CreateUserAccountPage/CreateUserAccountPage';
 import ForgotPasswordPage from 'ConsumerWeb/components/UserAccounts/ForgotPasswordPage';
 import ResetPasswordPage from 'ConsumerWeb/components/UserAccounts/ResetPasswordPage';
+import UserDataPage from 'ConsumerWeb/components/UserDataPage/UserDataPage';
+import RequestUserDataLinkPage from 'ConsumerWeb/components/UserDataPage/RequestUserDataLinkPage';
 
 import PremiumNewsletterSubscriptionCheckoutPage from 'ConsumerWeb/components/PremiumNewsletterSubscriptionCheckoutPage/PremiumNewsletterSubscriptionCheckoutPage';
 import PremiumInformationPage from 'ConsumerWeb/components/PremiumInformationPage/PremiumInformationPage';
 import PremiumNewsletterSubscriptionManagementPage from 'ConsumerWeb/components/PremiumNewsletterSubscriptionManagementPage/PremiumNewsletterSubscriptionManagementPage';
+import RedeemGiftCodePage from 'ConsumerWeb/components/GiftCodePage/RedeemGiftCodePage';
+import GroupLicensesPage from 'ConsumerWeb/components/GroupLicensesPage/GroupLicensesPage';
+import NewsletterArchivePage from 'ConsumerWeb/components/NewsletterArchivePage/NewsletterArchivePage';
 
 import PodcastPlayerPage from 'ConsumerWeb/components/PodcastPlayerPage/PodcastPlayerPage';
 
@@ -50,6 +55,9 @@ class App extends React.Component{
                     <Route path="/manage/:token/preferences" component={UserNewsletterPreferencesPage} />
                     <Route exact path="/manage/:token/premium" component={PremiumInformationPage} />
                     <Route path="/manage/:token/payment-settings" component={PremiumNewsletterSubscriptionManagementPage} />
+                    <Route path="/manage/:token/gift-code" component={RedeemGiftCodePage} />
+                    <Route path="/manage/:token/group-licenses" component={GroupLicensesPage} />
+                    <Route path="/manage/:token/archive" component={NewsletterArchivePage} />
                     <Route exact path="/manage/:token" component={SubscriptionManagementHomePage} />
 
                     { /* Content */ }
@@ -62,6 +70,8 @@ class App extends React.Component{
                     <Route path="/checkout/:token" component={PremiumNewsletterSubscriptionCheckoutPage} />
                     <Route path="/forgot-password" component={ForgotPasswordPage} />
                     <Route path="/password-reset/:token" component={ResetPasswordPage} />
+                    <Route path="/user-data/:token" component={UserDataPage} />
+                    <Route exact path="/user-data" component={RequestUserDataLinkPage} />
 
                     { /* Blog */ }
                     <Route path="/blog/:blogPath" component={BlogPostPage} />
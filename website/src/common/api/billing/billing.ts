@@ -16,6 +16,27 @@ export type PremiumNewsletterSubscription = {
     isAutoRenewEnabled: boolean;
     priceCents: number | undefined;
     hasValidDiscount: boolean;
+    plan: Plan | undefined;
+}
+
+export enum PlanType {
+    Monthly = 'monthly',
+    Annual = 'annual',
+    Student = 'student',
+}
+
+export enum BillingInterval {
+    Month = 'month',
+    Year = 'year',
+}
+
+export type Plan = {
+    id: string;
+    planType: PlanType;
+    priceCents: number;
+    currency: string;
+    billingInterval: BillingInterval;
+    isActive: boolean;
 }
 
 export type Discount = {
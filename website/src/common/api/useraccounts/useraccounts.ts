@@ -4,3 +4,24 @@ export enum SubscriptionLevel {
     Premium = 'Premium',
     BetaPremium = 'Beta-Premium',
 }
+
+export type GiftCode = {
+    id: string;
+    createdAt: Date;
+    code: string;
+    durationDays: number;
+    purchaserEmailAddress: string | undefined;
+    note: string | undefined;
+    redeemedByUserId: string | undefined;
+    redeemedAt: Date | undefined;
+    premiumExpiresAt: Date | undefined;
+}
+
+export type GroupLicense = {
+    id: string;
+    ownerUserId: string;
+    name: string;
+    seatCount: number;
+    expiresAt: Date;
+    memberEmailAddresses: Array<string> | undefined;
+}